@@ -31,8 +31,7 @@ type tokenCredential interface {
 // (DefaultAzureCredential-equivalent) with in-memory token reuse.
 type AzureTokenProvider struct {
 	credential tokenCredential
-	cache      map[string]azcore.AccessToken
-	mu         sync.RWMutex
+	cache      TokenCache
 	now        func() time.Time
 	timeout    time.Duration
 }
@@ -40,6 +39,7 @@ type AzureTokenProvider struct {
 var (
 	defaultProvider   TokenProvider
 	providerOnce      sync.Once
+	providerMu        sync.RWMutex
 	providerErr       error
 	credentialFactory = func() (tokenCredential, error) {
 		cred, err := azidentity.NewDefaultAzureCredential(nil)
@@ -52,8 +52,19 @@ var (
 )
 
 // NewAzureTokenProvider creates a provider backed by DefaultAzureCredential.
-// The provider caches tokens per scope until close to expiration.
+// The provider caches tokens per scope in memory until close to expiration.
 func NewAzureTokenProvider() (*AzureTokenProvider, error) {
+	return NewAzureTokenProviderWithCache(NewMemoryTokenCache())
+}
+
+// NewAzureTokenProviderWithCache creates a provider backed by
+// DefaultAzureCredential that stores tokens in cache. Use a FileTokenCache
+// to reuse tokens across process restarts.
+func NewAzureTokenProviderWithCache(cache TokenCache) (*AzureTokenProvider, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("token cache cannot be nil")
+	}
+
 	cred, err := credentialFactory()
 	if err != nil {
 		return nil, err
@@ -61,7 +72,7 @@ func NewAzureTokenProvider() (*AzureTokenProvider, error) {
 
 	return &AzureTokenProvider{
 		credential: cred,
-		cache:      make(map[string]azcore.AccessToken),
+		cache:      cache,
 		now:        timeNow,
 		timeout:    defaultAuthTimeout,
 	}, nil
@@ -84,9 +95,28 @@ func GetAzureToken(ctx context.Context, scope string) (string, error) {
 	return provider.GetToken(ctx, scope)
 }
 
+// ClearCache removes all tokens cached by the shared provider instance.
+// It is a no-op if the shared provider has not been created.
+func ClearCache() error {
+	providerMu.RLock()
+	provider := defaultProvider
+	providerMu.RUnlock()
+
+	if p, ok := provider.(*AzureTokenProvider); ok && p != nil {
+		return p.ClearCache()
+	}
+	return nil
+}
+
 func getDefaultProvider() (TokenProvider, error) {
 	providerOnce.Do(func() {
-		defaultProvider, providerErr = NewAzureTokenProvider()
+		provider, err := NewAzureTokenProvider()
+		providerMu.Lock()
+		if err == nil {
+			defaultProvider = provider
+		}
+		providerErr = err
+		providerMu.Unlock()
 	})
 
 	return defaultProvider, providerErr
@@ -108,7 +138,13 @@ func (p *AzureTokenProvider) GetToken(ctx context.Context, scope string) (string
 		defer cancel()
 	}
 
+	start := time.Now()
+	id := auditIdentityFromContext(ctx)
+	audit := tokenAudit{client: id.client, tenant: id.tenant, scope: scope, scopeLabel: id.scopeLabel}
+
 	if token, ok := p.getCached(scope); ok {
+		audit.result, audit.duration = tokenResultCacheHit, time.Since(start)
+		recordTokenRequest(audit)
 		return token, nil
 	}
 
@@ -116,18 +152,30 @@ func (p *AzureTokenProvider) GetToken(ctx context.Context, scope string) (string
 		Scopes: []string{scope},
 	})
 	if err != nil {
-		return "", classifyAuthError(scope, err)
+		err = classifyAuthError(scope, err)
+		audit.result, audit.err, audit.duration = tokenResultFailure, err, time.Since(start)
+		recordTokenRequest(audit)
+		return "", err
 	}
 
 	p.setCached(scope, accessToken)
+	audit.result, audit.duration = tokenResultSuccess, time.Since(start)
+	recordTokenRequest(audit)
 	return accessToken.Token, nil
 }
 
-func (p *AzureTokenProvider) getCached(scope string) (string, bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+// recordsTokenRequests marks AzureTokenProvider as recording its own
+// metrics and audit entries.
+func (p *AzureTokenProvider) recordsTokenRequests() {}
 
-	token, ok := p.cache[scope]
+// ClearCache removes all tokens from the provider's cache, forcing the next
+// GetToken call for each scope to acquire a fresh token.
+func (p *AzureTokenProvider) ClearCache() error {
+	return p.cache.Clear()
+}
+
+func (p *AzureTokenProvider) getCached(scope string) (string, bool) {
+	token, ok := p.cache.Get(scope)
 	if !ok || token.Token == "" || token.ExpiresOn.IsZero() {
 		return "", false
 	}
@@ -144,9 +192,8 @@ func (p *AzureTokenProvider) setCached(scope string, token azcore.AccessToken) {
 		return
 	}
 
-	p.mu.Lock()
-	p.cache[scope] = token
-	p.mu.Unlock()
+	// A failed write only loses persistence; the token is still returned.
+	_ = p.cache.Set(scope, token)
 }
 
 func classifyAuthError(scope string, err error) error {
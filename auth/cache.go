@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// TokenCache stores access tokens keyed by scope.
+// Implementations must be safe for concurrent use. Expiry is enforced by
+// the caller; a cache may return tokens that are already expired.
+type TokenCache interface {
+	// Get returns the cached token for scope, if any.
+	Get(scope string) (azcore.AccessToken, bool)
+	// Set stores token for scope.
+	Set(scope string, token azcore.AccessToken) error
+	// Clear removes all cached tokens.
+	Clear() error
+}
+
+// MemoryTokenCache is an in-process TokenCache. It is the default cache
+// used by AzureTokenProvider.
+type MemoryTokenCache struct {
+	tokens map[string]azcore.AccessToken
+	mu     sync.RWMutex
+}
+
+// NewMemoryTokenCache creates an empty in-memory token cache.
+func NewMemoryTokenCache() *MemoryTokenCache {
+	return &MemoryTokenCache{tokens: make(map[string]azcore.AccessToken)}
+}
+
+// Get returns the cached token for scope.
+func (c *MemoryTokenCache) Get(scope string) (azcore.AccessToken, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	token, ok := c.tokens[scope]
+	return token, ok
+}
+
+// Set stores token for scope.
+func (c *MemoryTokenCache) Set(scope string, token azcore.AccessToken) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[scope] = token
+	return nil
+}
+
+// Clear removes all cached tokens.
+func (c *MemoryTokenCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.tokens)
+	return nil
+}
+
+// snapshot returns a copy of all cached tokens.
+func (c *MemoryTokenCache) snapshot() map[string]azcore.AccessToken {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]azcore.AccessToken, len(c.tokens))
+	for k, v := range c.tokens {
+		out[k] = v
+	}
+	return out
+}
+
+// FileTokenCache is a TokenCache that persists tokens to a file so
+// short-lived processes (for example, frequently restarting containers) can
+// reuse tokens instead of re-authenticating on every start.
+//
+// The file is written with 0600 permissions in a 0700 directory. On Windows
+// the contents are also encrypted with DPAPI for the current user. On other
+// platforms tokens are NOT encrypted at rest: they are stored as plain JSON
+// and protected by the file permissions only, so anyone who can read the
+// file as the current user (or root) can read the tokens.
+type FileTokenCache struct {
+	path   string
+	memory *MemoryTokenCache
+	mu     sync.Mutex
+}
+
+// fileTokenCacheEntry is the persisted form of a cached token.
+type fileTokenCacheEntry struct {
+	Token     string `json:"token"`
+	ExpiresOn int64  `json:"expiresOn"`
+}
+
+// NewFileTokenCache creates a file-backed cache at path, loading any
+// previously persisted tokens. An unreadable or corrupt cache file is
+// discarded rather than returned as an error.
+func NewFileTokenCache(path string) (*FileTokenCache, error) {
+	if path == "" {
+		return nil, fmt.Errorf("cache path cannot be empty")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cache path: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(absPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &FileTokenCache{
+		path:   absPath,
+		memory: NewMemoryTokenCache(),
+	}
+	c.load()
+	return c, nil
+}
+
+// DefaultTokenCachePath returns the default location for a persisted token
+// cache under the user's cache directory.
+func DefaultTokenCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "azd-core", "tokens.cache"), nil
+}
+
+// Path returns the cache file path.
+func (c *FileTokenCache) Path() string {
+	return c.path
+}
+
+// Get returns the cached token for scope.
+func (c *FileTokenCache) Get(scope string) (azcore.AccessToken, bool) {
+	return c.memory.Get(scope)
+}
+
+// Set stores token for scope and persists the cache to disk.
+func (c *FileTokenCache) Set(scope string, token azcore.AccessToken) error {
+	if err := c.memory.Set(scope, token); err != nil {
+		return err
+	}
+	return c.save()
+}
+
+// Clear removes all cached tokens from memory and deletes the cache file.
+func (c *FileTokenCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_ = c.memory.Clear()
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove token cache: %w", err)
+	}
+	return nil
+}
+
+func (c *FileTokenCache) load() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// #nosec G304 -- path is supplied by the caller constructing the cache
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	plaintext, err := unprotectData(data)
+	if err != nil {
+		_ = os.Remove(c.path)
+		return
+	}
+
+	var entries map[string]fileTokenCacheEntry
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		_ = os.Remove(c.path)
+		return
+	}
+
+	for scope, entry := range entries {
+		_ = c.memory.Set(scope, azcore.AccessToken{
+			Token:     entry.Token,
+			ExpiresOn: time.Unix(entry.ExpiresOn, 0),
+		})
+	}
+}
+
+func (c *FileTokenCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens := c.memory.snapshot()
+	entries := make(map[string]fileTokenCacheEntry, len(tokens))
+	for scope, token := range tokens {
+		entries[scope] = fileTokenCacheEntry{
+			Token:     token.Token,
+			ExpiresOn: token.ExpiresOn.Unix(),
+		}
+	}
+
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode token cache: %w", err)
+	}
+
+	ciphertext, err := protectData(plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to protect token cache: %w", err)
+	}
+
+	if err := fileutil.AtomicWriteFile(c.path, ciphertext, 0600); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingCredential struct {
+	calls atomic.Int32
+	token string
+}
+
+func (c *countingCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.calls.Add(1)
+	return azcore.AccessToken{Token: c.token, ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func newTestProvider(cred tokenCredential, cache TokenCache) *AzureTokenProvider {
+	return &AzureTokenProvider{
+		credential: cred,
+		cache:      cache,
+		now:        time.Now,
+		timeout:    defaultAuthTimeout,
+	}
+}
+
+func TestMemoryTokenCache(t *testing.T) {
+	cache := NewMemoryTokenCache()
+	_, ok := cache.Get("scope")
+	assert.False(t, ok)
+
+	token := azcore.AccessToken{Token: "abc", ExpiresOn: time.Now().Add(time.Hour)}
+	require.NoError(t, cache.Set("scope", token))
+
+	got, ok := cache.Get("scope")
+	require.True(t, ok)
+	assert.Equal(t, "abc", got.Token)
+
+	require.NoError(t, cache.Clear())
+	_, ok = cache.Get("scope")
+	assert.False(t, ok)
+}
+
+func TestAzureTokenProvider_UsesCache(t *testing.T) {
+	cred := &countingCredential{token: "token-1"}
+	provider := newTestProvider(cred, NewMemoryTokenCache())
+	scope := "https://management.azure.com/.default"
+
+	for range 3 {
+		token, err := provider.GetToken(context.Background(), scope)
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+	}
+	assert.Equal(t, int32(1), cred.calls.Load())
+
+	require.NoError(t, provider.ClearCache())
+	_, err := provider.GetToken(context.Background(), scope)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), cred.calls.Load())
+}
+
+func TestAzureTokenProvider_ExpiredCacheEntry(t *testing.T) {
+	cred := &countingCredential{token: "fresh"}
+	cache := NewMemoryTokenCache()
+	scope := "https://vault.azure.net/.default"
+	require.NoError(t, cache.Set(scope, azcore.AccessToken{Token: "stale", ExpiresOn: time.Now().Add(time.Minute)}))
+
+	token, err := newTestProvider(cred, cache).GetToken(context.Background(), scope)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", token)
+}
+
+func TestNewAzureTokenProviderWithCache_NilCache(t *testing.T) {
+	_, err := NewAzureTokenProviderWithCache(nil)
+	assert.Error(t, err)
+}
+
+func TestFileTokenCache_PersistsAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens", "cache.bin")
+	scope := "https://management.azure.com/.default"
+
+	cache, err := NewFileTokenCache(path)
+	require.NoError(t, err)
+	assert.Equal(t, path, cache.Path())
+
+	cred := &countingCredential{token: "persisted-token-value"}
+	_, err = newTestProvider(cred, cache).GetToken(context.Background(), scope)
+	require.NoError(t, err)
+
+	if runtime.GOOS == "windows" {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.False(t, strings.Contains(string(data), "persisted-token-value"), "token must be protected with DPAPI")
+	} else {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		_, err = os.Stat(path + ".key")
+		assert.True(t, os.IsNotExist(err), "no key file should be written next to the cache")
+	}
+
+	reloaded, err := NewFileTokenCache(path)
+	require.NoError(t, err)
+	cred2 := &countingCredential{token: "other"}
+	token, err := newTestProvider(cred2, reloaded).GetToken(context.Background(), scope)
+	require.NoError(t, err)
+	assert.Equal(t, "persisted-token-value", token)
+	assert.Equal(t, int32(0), cred2.calls.Load())
+}
+
+func TestFileTokenCache_Clear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.bin")
+	cache, err := NewFileTokenCache(path)
+	require.NoError(t, err)
+	require.NoError(t, cache.Set("scope", azcore.AccessToken{Token: "abc", ExpiresOn: time.Now().Add(time.Hour)}))
+
+	require.NoError(t, cache.Clear())
+	_, ok := cache.Get("scope")
+	assert.False(t, ok)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// Clearing an already empty cache succeeds.
+	require.NoError(t, cache.Clear())
+}
+
+func TestFileTokenCache_CorruptFileDiscarded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.bin")
+	require.NoError(t, os.WriteFile(path, []byte("not encrypted"), 0600))
+
+	cache, err := NewFileTokenCache(path)
+	require.NoError(t, err)
+	_, ok := cache.Get("scope")
+	assert.False(t, ok)
+}
+
+func TestNewFileTokenCache_EmptyPath(t *testing.T) {
+	_, err := NewFileTokenCache("")
+	assert.Error(t, err)
+}
+
+func TestClearCache_NoDefaultProvider(t *testing.T) {
+	assert.NoError(t, ClearCache())
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Transport selects how a local token server accepts connections.
+type Transport string
+
+const (
+	// TransportTCP listens on a loopback TCP port.
+	TransportTCP Transport = "tcp"
+	// TransportLocal listens on a Unix domain socket that only the current
+	// user can open: the socket file is created with mode 0600 on Unix, and
+	// on Windows its DACL grants access to the current user alone. Windows
+	// also uses an AF_UNIX socket (Windows 10 1803 or later); named pipes
+	// are not supported. It avoids port conflicts and the cost of TLS for
+	// same-host clients.
+	TransportLocal Transport = "local"
+)
+
+// localSocketName is the socket file name used when ServerOptions.Address
+// is empty.
+const localSocketName = "token.sock"
+
+// ServerOptions configures the listener of a token server. There is no
+// named-pipe transport; TransportLocal is a Unix domain socket on every
+// platform.
+type ServerOptions struct {
+	// Transport defaults to TransportTCP.
+	Transport Transport
+	// Address is the TCP address for TransportTCP (default "127.0.0.1:0")
+	// or the socket path for TransportLocal. When the socket path is empty,
+	// the socket is created in a new private directory that is removed when
+	// the listener closes. A stale socket at Address is replaced; any other
+	// existing file is an error.
+	Address string
+}
+
+// Listen opens the listener described by opts. Use the listener's Addr to
+// tell clients where to connect, and Dial to connect to it.
+//
+// Example:
+//
+//	ln, err := auth.Listen(auth.ServerOptions{Transport: auth.TransportLocal})
+//	if err != nil {
+//	    return err
+//	}
+//	defer ln.Close()
+//	return http.Serve(ln, handler) // clients: auth.Dial(ctx, auth.TransportLocal, ln.Addr().String())
+func Listen(opts ServerOptions) (net.Listener, error) {
+	switch opts.Transport {
+	case "", TransportTCP:
+		addr := opts.Address
+		if addr == "" {
+			addr = "127.0.0.1:0"
+		}
+		return net.Listen("tcp", addr)
+	case TransportLocal:
+		return listenLocal(opts.Address)
+	default:
+		return nil, fmt.Errorf("unsupported transport %q", opts.Transport)
+	}
+}
+
+// Dial connects to a listener opened by Listen with the same transport.
+func Dial(ctx context.Context, transport Transport, address string) (net.Conn, error) {
+	var d net.Dialer
+	switch transport {
+	case "", TransportTCP:
+		return d.DialContext(ctx, "tcp", address)
+	case TransportLocal:
+		return d.DialContext(ctx, "unix", address)
+	default:
+		return nil, fmt.Errorf("unsupported transport %q", transport)
+	}
+}
+
+// listenLocal listens on a Unix domain socket at path, or in a new private
+// directory when path is empty.
+func listenLocal(path string) (net.Listener, error) {
+	var dir string
+	if path == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "azd-auth-"); err != nil {
+			return nil, fmt.Errorf("failed to create socket directory: %w", err)
+		}
+		if err := restrictToOwner(dir); err != nil {
+			_ = os.RemoveAll(dir)
+			return nil, err
+		}
+		path = filepath.Join(dir, localSocketName)
+	} else if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	ln, err := listenUnix(path)
+	if err != nil {
+		if dir != "" {
+			_ = os.RemoveAll(dir)
+		}
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	if err := restrictToOwner(path); err != nil {
+		_ = ln.Close()
+		if dir != "" {
+			_ = os.RemoveAll(dir)
+		}
+		return nil, err
+	}
+	return &localListener{Listener: ln, dir: dir}, nil
+}
+
+// removeStaleSocket removes a socket left behind at path by a previous
+// process. Other files are never removed.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("cannot listen on %s: file exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
+
+// localListener removes its private socket directory on Close.
+type localListener struct {
+	net.Listener
+	dir  string
+	once sync.Once
+}
+
+func (l *localListener) Close() error {
+	err := l.Listener.Close()
+	l.once.Do(func() {
+		if l.dir != "" {
+			_ = os.RemoveAll(l.dir)
+		}
+	})
+	return err
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+
+package auth
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// umaskMu serializes listenUnix calls that change the process umask.
+var umaskMu sync.Mutex
+
+// listenUnix listens on a Unix domain socket at path. The umask is narrowed
+// while binding so the socket is created with mode 0600 instead of being
+// chmodded afterwards, leaving no window in which other users can connect.
+// The umask is process-wide, so files created by other goroutines during
+// the bind may briefly get owner-only permissions.
+func listenUnix(path string) (net.Listener, error) {
+	umaskMu.Lock()
+	defer umaskMu.Unlock()
+	old := syscall.Umask(0o177)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
+
+// restrictToOwner limits path to the current user: 0600 for the socket and
+// 0700 for its directory.
+func restrictToOwner(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0o600)
+	if info.IsDir() {
+		mode = 0o700
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		return fmt.Errorf("failed to restrict %s to the current user: %w", path, err)
+	}
+	return nil
+}
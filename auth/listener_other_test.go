@@ -0,0 +1,33 @@
+//go:build !windows
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestListenUnix_CreatesOwnerOnlySocket(t *testing.T) {
+	old := syscall.Umask(0)
+	defer syscall.Umask(old)
+
+	path := filepath.Join(t.TempDir(), "s.sock")
+	ln, err := listenUnix(path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0o600 {
+		t.Errorf("socket mode = %o, want 600 before any chmod", got)
+	}
+	if got := syscall.Umask(0); got != 0 {
+		t.Errorf("umask after listenUnix = %o, want restored 0", got)
+	}
+}
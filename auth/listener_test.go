@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// echoOnce accepts one connection and writes back what it reads.
+func echoOnce(t *testing.T, ln net.Listener) {
+	t.Helper()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+}
+
+func roundTrip(t *testing.T, transport Transport, addr string) {
+	t.Helper()
+	conn, err := Dial(context.Background(), transport, addr)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("read %q, %v", buf, err)
+	}
+}
+
+func TestListen_TCP(t *testing.T) {
+	ln, err := Listen(ServerOptions{})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	if !strings.HasPrefix(ln.Addr().String(), "127.0.0.1:") {
+		t.Errorf("Addr() = %s, want loopback", ln.Addr())
+	}
+	echoOnce(t, ln)
+	roundTrip(t, TransportTCP, ln.Addr().String())
+}
+
+func TestListen_LocalPrivateDirectory(t *testing.T) {
+	ln, err := Listen(ServerOptions{Transport: TransportLocal})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	path := ln.Addr().String()
+	dir := filepath.Dir(path)
+
+	if runtime.GOOS != "windows" {
+		for p, want := range map[string]os.FileMode{path: 0o600, dir: 0o700} {
+			info, err := os.Stat(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := info.Mode().Perm(); got != want {
+				t.Errorf("%s mode = %o, want %o", p, got, want)
+			}
+		}
+	}
+	echoOnce(t, ln)
+	roundTrip(t, TransportLocal, path)
+
+	if err := ln.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", dir, err)
+	}
+}
+
+func TestListen_LocalAddress(t *testing.T) {
+	dir, err := os.MkdirTemp("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, "s.sock")
+
+	// A stale socket from an earlier process is replaced.
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	ln, err := Listen(ServerOptions{Transport: TransportLocal, Address: path})
+	if err != nil {
+		t.Fatalf("Listen over stale socket: %v", err)
+	}
+	echoOnce(t, ln)
+	roundTrip(t, TransportLocal, path)
+	_ = ln.Close()
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("caller's directory should be kept: %v", err)
+	}
+
+	regular := filepath.Join(dir, "regular")
+	if err := os.WriteFile(regular, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(ServerOptions{Transport: TransportLocal, Address: regular}); err == nil {
+		t.Error("expected error for an existing regular file")
+	}
+	if data, _ := os.ReadFile(regular); string(data) != "keep" {
+		t.Error("regular file was modified")
+	}
+}
+
+func TestListen_UnsupportedTransport(t *testing.T) {
+	if _, err := Listen(ServerOptions{Transport: "pipe"}); err == nil {
+		t.Error("expected error for unsupported transport")
+	}
+	if _, err := Dial(context.Background(), "pipe", "x"); err == nil {
+		t.Error("expected error for unsupported transport")
+	}
+}
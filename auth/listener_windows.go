@@ -0,0 +1,42 @@
+//go:build windows
+
+package auth
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/windows"
+)
+
+// listenUnix listens on an AF_UNIX socket at path. Windows has no umask;
+// the socket inherits its directory's DACL until restrictToOwner replaces
+// it, so callers passing an explicit path should choose a directory only
+// the current user can access.
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
+
+// restrictToOwner replaces the DACL of path with one granting full access to
+// the current user only, without inheriting from the parent directory.
+func restrictToOwner(path string) error {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return fmt.Errorf("failed to look up the current user: %w", err)
+	}
+	sd, err := windows.SecurityDescriptorFromString(fmt.Sprintf("D:P(A;OICI;GA;;;%s)", user.User.Sid.String()))
+	if err != nil {
+		return fmt.Errorf("failed to build security descriptor: %w", err)
+	}
+	dacl, _, err := sd.DACL()
+	if err != nil {
+		return fmt.Errorf("failed to read DACL: %w", err)
+	}
+	err = windows.SetNamedSecurityInfo(path, windows.SE_FILE_OBJECT,
+		windows.DACL_SECURITY_INFORMATION|windows.PROTECTED_DACL_SECURITY_INFORMATION,
+		nil, nil, dacl, nil)
+	if err != nil {
+		return fmt.Errorf("failed to restrict %s to the current user: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/jongio/azd-core/logutil"
+)
+
+// Token request results used for metrics labels and audit entries.
+const (
+	tokenResultSuccess  = "success"
+	tokenResultCacheHit = "cache_hit"
+	tokenResultFailure  = "failure"
+	tokenResultDenied   = "denied"
+)
+
+// Scope label values for requests not attributed to a TokenPolicy pattern.
+// Requested scopes are never used as labels directly, so clients cannot
+// create unbounded metric series by asking for arbitrary scopes.
+const (
+	scopeLabelOther  = "other"
+	scopeLabelDenied = "denied"
+)
+
+var (
+	tokenRequestTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "azd_auth_token_requests_total",
+			Help: "Total number of token requests by scope pattern and result",
+		},
+		[]string{"scope", "result"},
+	)
+
+	tokenRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "azd_auth_token_request_duration_seconds",
+			Help:    "Duration of token requests in seconds by scope pattern and result",
+			Buckets: []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"scope", "result"},
+	)
+)
+
+// tokenAudit describes a single token request for metrics and audit logging.
+// It must never carry token material.
+type tokenAudit struct {
+	client string
+	scope  string
+	// scopeLabel is the metrics label for scope: the TokenPolicy pattern
+	// that allowed it, scopeLabelDenied, or scopeLabelOther when empty.
+	scopeLabel string
+	tenant     string
+	result     string
+	duration   time.Duration
+	err        error
+}
+
+// tokenRequestRecorder is implemented by providers that record metrics and
+// audit entries for their own requests, so wrappers such as
+// PolicyTokenProvider do not record them a second time.
+type tokenRequestRecorder interface {
+	recordsTokenRequests()
+}
+
+type auditContextKey struct{}
+
+// auditIdentity is the requester and scope label recorded with a token
+// request.
+type auditIdentity struct {
+	client     string
+	tenant     string
+	scopeLabel string
+}
+
+// withAuditIdentity returns a copy of ctx carrying the identity a wrapped
+// provider records with the request.
+func withAuditIdentity(ctx context.Context, id auditIdentity) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, id)
+}
+
+// auditIdentityFromContext returns the identity stored by withAuditIdentity.
+func auditIdentityFromContext(ctx context.Context) auditIdentity {
+	id, _ := ctx.Value(auditContextKey{}).(auditIdentity)
+	return id
+}
+
+// recordTokenRequest updates token metrics and writes a structured audit
+// entry through logutil. Cache hits are counted but not logged. Metrics are
+// labeled with a.scopeLabel; the requested scope appears only in the log.
+func recordTokenRequest(a tokenAudit) {
+	scopeLabel := a.scopeLabel
+	if scopeLabel == "" {
+		scopeLabel = scopeLabelOther
+	}
+	labels := prometheus.Labels{"scope": scopeLabel, "result": a.result}
+	tokenRequestTotal.With(labels).Inc()
+	tokenRequestDuration.With(labels).Observe(a.duration.Seconds())
+
+	if a.result == tokenResultCacheHit {
+		return
+	}
+
+	attrs := []any{
+		"client", a.client,
+		"scope", a.scope,
+		"result", a.result,
+		"duration_ms", a.duration.Milliseconds(),
+	}
+	if a.tenant != "" {
+		attrs = append(attrs, "tenant", a.tenant)
+	}
+
+	logger := logutil.NewLogger("auth").WithOperation("token_request")
+	switch a.result {
+	case tokenResultSuccess:
+		logger.Debug("token issued", attrs...)
+	case tokenResultDenied:
+		logger.Warn("token request denied", append(attrs, "reason", a.err)...)
+	default:
+		logger.Warn("token request failed", append(attrs, "error", a.err)...)
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jongio/azd-core/logutil"
+)
+
+func TestRecordTokenRequest_Metrics(t *testing.T) {
+	pattern := "https://*.metrics-test.example/.default"
+	before := testutil.ToFloat64(tokenRequestTotal.WithLabelValues(pattern, tokenResultSuccess))
+
+	recordTokenRequest(tokenAudit{scope: "https://a.metrics-test.example/.default", scopeLabel: pattern, result: tokenResultSuccess, duration: 10 * time.Millisecond})
+
+	after := testutil.ToFloat64(tokenRequestTotal.WithLabelValues(pattern, tokenResultSuccess))
+	assert.Equal(t, before+1, after)
+}
+
+func TestRecordTokenRequest_ScopeNotALabel(t *testing.T) {
+	scope := "https://random-scope-label.example/.default"
+	before := testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scopeLabelOther, tokenResultFailure))
+
+	recordTokenRequest(tokenAudit{scope: scope, result: tokenResultFailure, err: errors.New("boom")})
+
+	assert.Equal(t, before+1, testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scopeLabelOther, tokenResultFailure)))
+	assert.Zero(t, testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scope, tokenResultFailure)))
+}
+
+func TestRecordTokenRequest_AuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	logutil.SetupLoggerWithWriter(&buf, true, true)
+	t.Cleanup(func() { logutil.SetupLogger(false, false) })
+
+	recordTokenRequest(tokenAudit{client: "api", scope: "s1", result: tokenResultSuccess, duration: 1500 * time.Millisecond})
+	recordTokenRequest(tokenAudit{client: "api", scope: "s2", result: tokenResultFailure, err: errors.New("boom")})
+	recordTokenRequest(tokenAudit{client: "api", scope: "s3", result: tokenResultCacheHit})
+
+	output := buf.String()
+	assert.Contains(t, output, `"level":"DEBUG","msg":"token issued"`)
+	assert.Contains(t, output, `"duration_ms":1500`)
+	assert.Contains(t, output, `"msg":"token request failed"`)
+	assert.Contains(t, output, `"operation":"token_request"`)
+	assert.NotContains(t, output, `"scope":"s3"`, "cache hits should not be audited")
+}
+
+func TestRecordTokenRequest_IssuedIsDebugOnly(t *testing.T) {
+	var buf bytes.Buffer
+	logutil.SetupLoggerWithWriter(&buf, false, false)
+	t.Cleanup(func() { logutil.SetupLogger(false, false) })
+
+	recordTokenRequest(tokenAudit{client: "api", scope: "s1", result: tokenResultSuccess})
+	assert.Empty(t, buf.String(), "successful token requests should not log at info level")
+}
+
+func TestAzureTokenProvider_RecordsMetrics(t *testing.T) {
+	scope := "https://provider-metrics.example/.default"
+	provider := newTestProvider(&countingCredential{token: "t"}, NewMemoryTokenCache())
+	success := testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scopeLabelOther, tokenResultSuccess))
+	cacheHit := testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scopeLabelOther, tokenResultCacheHit))
+
+	_, err := provider.GetToken(context.Background(), scope)
+	require.NoError(t, err)
+	_, err = provider.GetToken(context.Background(), scope)
+	require.NoError(t, err)
+
+	assert.Equal(t, success+1, testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scopeLabelOther, tokenResultSuccess)))
+	assert.Equal(t, cacheHit+1, testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scopeLabelOther, tokenResultCacheHit)))
+}
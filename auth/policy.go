@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrScopeNotAllowed indicates a token was requested for a scope outside the policy.
+	ErrScopeNotAllowed = errors.New("scope not allowed by token policy")
+	// ErrTenantNotAllowed indicates a token was requested for a tenant outside the policy.
+	ErrTenantNotAllowed = errors.New("tenant not allowed by token policy")
+	// ErrClientNotAllowed indicates the requesting client is not bound to the requested scope.
+	ErrClientNotAllowed = errors.New("client not allowed by token policy")
+)
+
+// TokenRequest describes a token issuance request evaluated by a TokenPolicy.
+type TokenRequest struct {
+	// ClientID identifies the requesting client (for example, a container name).
+	ClientID string
+	// Scope is the OAuth scope requested.
+	Scope string
+	// TenantID is the Microsoft Entra tenant the token is issued for. Optional.
+	TenantID string
+}
+
+// TokenPolicy restricts which tokens a host may issue on behalf of clients.
+// Empty allowlists impose no restriction. Scope patterns are matched
+// case-insensitively and may use '*' wildcards within a path segment, e.g.
+// "https://*.vault.azure.net/.default".
+type TokenPolicy struct {
+	// AllowedScopes lists scope patterns any client may request.
+	AllowedScopes []string
+	// AllowedTenants lists tenant IDs tokens may be issued for.
+	AllowedTenants []string
+	// ClientScopes binds client identities to the scope patterns they may
+	// request. When non-empty, only listed clients may obtain tokens, and
+	// only for their bound scopes (which must also satisfy AllowedScopes).
+	ClientScopes map[string][]string
+}
+
+// Authorize reports whether req is permitted by the policy.
+// It returns an error wrapping ErrScopeNotAllowed, ErrTenantNotAllowed, or
+// ErrClientNotAllowed when the request is denied.
+func (p TokenPolicy) Authorize(req TokenRequest) error {
+	scope := strings.TrimSpace(req.Scope)
+	if scope == "" {
+		return fmt.Errorf("%w: scope cannot be empty", ErrScopeNotAllowed)
+	}
+
+	if len(p.AllowedScopes) > 0 && !matchesAnyScope(scope, p.AllowedScopes) {
+		return fmt.Errorf("%w: %s", ErrScopeNotAllowed, scope)
+	}
+
+	if len(p.AllowedTenants) > 0 && !containsFold(p.AllowedTenants, req.TenantID) {
+		return fmt.Errorf("%w: %q", ErrTenantNotAllowed, req.TenantID)
+	}
+
+	if len(p.ClientScopes) > 0 {
+		bound, ok := p.ClientScopes[req.ClientID]
+		if !ok {
+			return fmt.Errorf("%w: unknown client %q", ErrClientNotAllowed, req.ClientID)
+		}
+		if !matchesAnyScope(scope, bound) {
+			return fmt.Errorf("%w: client %q is not bound to scope %s", ErrClientNotAllowed, req.ClientID, scope)
+		}
+	}
+
+	return nil
+}
+
+// PolicyTokenProvider enforces a TokenPolicy in front of another TokenProvider
+// and records metrics and an audit log entry for every issuance or denial.
+// Metrics are labeled with the AllowedScopes pattern that matched the
+// request, "denied" for denials, or "other" when no pattern applies.
+// When the wrapped provider is an AzureTokenProvider, which records its own
+// requests, only denials are recorded here and the client and tenant are
+// passed down to the provider's entries, so each request is counted once.
+type PolicyTokenProvider struct {
+	inner    TokenProvider
+	policy   TokenPolicy
+	clientID string
+	tenantID string
+}
+
+// NewPolicyTokenProvider wraps inner so that every GetToken call is checked
+// against policy on behalf of clientID. tenantID is the tenant inner issues
+// tokens for and may be empty when AllowedTenants is not used.
+func NewPolicyTokenProvider(inner TokenProvider, policy TokenPolicy, clientID, tenantID string) (*PolicyTokenProvider, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("token provider cannot be nil")
+	}
+
+	return &PolicyTokenProvider{
+		inner:    inner,
+		policy:   policy,
+		clientID: clientID,
+		tenantID: tenantID,
+	}, nil
+}
+
+// GetToken authorizes the request and delegates to the wrapped provider.
+func (p *PolicyTokenProvider) GetToken(ctx context.Context, scope string) (string, error) {
+	start := time.Now()
+	req := TokenRequest{ClientID: p.clientID, Scope: strings.TrimSpace(scope), TenantID: p.tenantID}
+	audit := tokenAudit{client: req.ClientID, scope: req.Scope, tenant: req.TenantID}
+
+	if err := p.policy.Authorize(req); err != nil {
+		audit.scopeLabel = scopeLabelDenied
+		audit.result, audit.err, audit.duration = tokenResultDenied, err, time.Since(start)
+		recordTokenRequest(audit)
+		return "", err
+	}
+	audit.scopeLabel = p.policy.scopeLabel(req.Scope)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = withAuditIdentity(ctx, auditIdentity{client: req.ClientID, tenant: req.TenantID, scopeLabel: audit.scopeLabel})
+	if _, ok := p.inner.(tokenRequestRecorder); ok {
+		return p.inner.GetToken(ctx, req.Scope)
+	}
+
+	token, err := p.inner.GetToken(ctx, req.Scope)
+	if err != nil {
+		audit.result, audit.err, audit.duration = tokenResultFailure, err, time.Since(start)
+		recordTokenRequest(audit)
+		return "", err
+	}
+
+	audit.result, audit.duration = tokenResultSuccess, time.Since(start)
+	recordTokenRequest(audit)
+	return token, nil
+}
+
+// scopeLabel returns the AllowedScopes pattern matching scope, for use as a
+// bounded metrics label, or scopeLabelOther.
+func (p TokenPolicy) scopeLabel(scope string) string {
+	if pattern, ok := matchScope(scope, p.AllowedScopes); ok {
+		return pattern
+	}
+	return scopeLabelOther
+}
+
+func matchesAnyScope(scope string, patterns []string) bool {
+	_, ok := matchScope(scope, patterns)
+	return ok
+}
+
+// matchScope returns the first pattern, as configured, that matches scope.
+func matchScope(scope string, patterns []string) (string, bool) {
+	scope = strings.ToLower(scope)
+	for _, configured := range patterns {
+		pattern := strings.ToLower(strings.TrimSpace(configured))
+		if pattern == scope {
+			return strings.TrimSpace(configured), true
+		}
+		if strings.Contains(pattern, "*") {
+			if ok, err := path.Match(pattern, scope); err == nil && ok {
+				return strings.TrimSpace(configured), true
+			}
+		}
+	}
+	return "", false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
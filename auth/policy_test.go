@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jongio/azd-core/logutil"
+)
+
+func TestTokenPolicy_Authorize(t *testing.T) {
+	policy := TokenPolicy{
+		AllowedScopes:  []string{"https://management.azure.com/.default", "https://*.vault.azure.net/.default"},
+		AllowedTenants: []string{"tenant-a"},
+		ClientScopes: map[string][]string{
+			"api": {"https://management.azure.com/.default"},
+			"web": {"https://*.vault.azure.net/.default"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		req     TokenRequest
+		wantErr error
+	}{
+		{"allowed", TokenRequest{ClientID: "api", Scope: "https://management.azure.com/.default", TenantID: "tenant-a"}, nil},
+		{"wildcard", TokenRequest{ClientID: "web", Scope: "https://myvault.vault.azure.net/.default", TenantID: "TENANT-A"}, nil},
+		{"scope denied", TokenRequest{ClientID: "api", Scope: "https://graph.microsoft.com/.default", TenantID: "tenant-a"}, ErrScopeNotAllowed},
+		{"empty scope", TokenRequest{ClientID: "api", TenantID: "tenant-a"}, ErrScopeNotAllowed},
+		{"tenant denied", TokenRequest{ClientID: "api", Scope: "https://management.azure.com/.default", TenantID: "tenant-b"}, ErrTenantNotAllowed},
+		{"unknown client", TokenRequest{ClientID: "worker", Scope: "https://management.azure.com/.default", TenantID: "tenant-a"}, ErrClientNotAllowed},
+		{"client not bound", TokenRequest{ClientID: "web", Scope: "https://management.azure.com/.default", TenantID: "tenant-a"}, ErrClientNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Authorize(tt.req)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenPolicy_EmptyAllowsAll(t *testing.T) {
+	err := TokenPolicy{}.Authorize(TokenRequest{Scope: "https://graph.microsoft.com/.default"})
+	assert.NoError(t, err)
+}
+
+func TestPolicyTokenProvider(t *testing.T) {
+	var buf bytes.Buffer
+	logutil.SetupLoggerWithWriter(&buf, true, false)
+	t.Cleanup(func() { logutil.SetupLogger(false, false) })
+
+	inner := &MockTokenProvider{Token: "secret-token-value"}
+	provider, err := NewPolicyTokenProvider(inner, TokenPolicy{
+		AllowedScopes: []string{"https://management.azure.com/.default"},
+	}, "api", "")
+	require.NoError(t, err)
+
+	token, err := provider.GetToken(context.Background(), "https://management.azure.com/.default")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token-value", token)
+
+	_, err = provider.GetToken(context.Background(), "https://graph.microsoft.com/.default")
+	assert.ErrorIs(t, err, ErrScopeNotAllowed)
+
+	output := buf.String()
+	assert.Contains(t, output, "token issued")
+	assert.Contains(t, output, "token request denied")
+	assert.Contains(t, output, "client=api")
+	assert.NotContains(t, output, "secret-token-value")
+}
+
+func TestPolicyTokenProvider_RecordsOnce(t *testing.T) {
+	var buf bytes.Buffer
+	logutil.SetupLoggerWithWriter(&buf, true, false)
+	t.Cleanup(func() { logutil.SetupLogger(false, false) })
+
+	pattern := "https://*.policy-once.example/.default"
+	inner := newTestProvider(&countingCredential{token: "t"}, NewMemoryTokenCache())
+	provider, err := NewPolicyTokenProvider(inner, TokenPolicy{AllowedScopes: []string{pattern}}, "api", "tenant-a")
+	require.NoError(t, err)
+
+	_, err = provider.GetToken(context.Background(), "https://a.policy-once.example/.default")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(tokenRequestTotal.WithLabelValues(pattern, tokenResultSuccess)))
+	output := buf.String()
+	assert.Equal(t, 1, strings.Count(output, "token issued"), output)
+	assert.Contains(t, output, "client=api")
+	assert.Contains(t, output, "tenant=tenant-a")
+}
+
+func TestPolicyTokenProvider_DeniedScopeLabel(t *testing.T) {
+	scope := "https://denied-label.example/.default"
+	provider, err := NewPolicyTokenProvider(&MockTokenProvider{Token: "t"}, TokenPolicy{
+		AllowedScopes: []string{"https://management.azure.com/.default"},
+	}, "api", "")
+	require.NoError(t, err)
+	before := testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scopeLabelDenied, tokenResultDenied))
+
+	_, err = provider.GetToken(context.Background(), scope)
+	assert.ErrorIs(t, err, ErrScopeNotAllowed)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scopeLabelDenied, tokenResultDenied)))
+	assert.Zero(t, testutil.ToFloat64(tokenRequestTotal.WithLabelValues(scope, tokenResultDenied)))
+}
+
+func TestPolicyTokenProvider_InnerError(t *testing.T) {
+	inner := &MockTokenProvider{Error: errors.New("credential unavailable")}
+	provider, err := NewPolicyTokenProvider(inner, TokenPolicy{}, "api", "")
+	require.NoError(t, err)
+
+	_, err = provider.GetToken(context.Background(), "https://management.azure.com/.default")
+	assert.Error(t, err)
+}
+
+func TestNewPolicyTokenProvider_NilInner(t *testing.T) {
+	_, err := NewPolicyTokenProvider(nil, TokenPolicy{}, "api", "")
+	assert.Error(t, err)
+}
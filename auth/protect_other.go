@@ -0,0 +1,15 @@
+//go:build !windows
+
+package auth
+
+// protectData returns data unchanged. Without an OS-managed secret store,
+// any key written next to the cache would be readable by whoever can read
+// the cache, so the cache relies on its 0600 permissions instead.
+func protectData(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// unprotectData returns data unchanged.
+func unprotectData(data []byte) ([]byte, error) {
+	return data, nil
+}
@@ -0,0 +1,185 @@
+package azdextutil
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/logutil"
+	"github.com/jongio/azd-core/version"
+)
+
+// Names of the global flags registered by CommandBuilder.
+const (
+	FlagOutput  = "output"
+	FlagDebug   = "debug"
+	FlagNoColor = "no-color"
+)
+
+// EnvOrchestrated marks a process as running under an orchestrating command
+// (for example, an extension invoking another extension's command). When set
+// to "true", commands built by CommandBuilder enable cliout orchestrated mode
+// so nested commands skip their headers.
+const EnvOrchestrated = "AZD_EXTENSION_ORCHESTRATED"
+
+// GlobalFlags holds the values of the standard flags registered by CommandBuilder.
+type GlobalFlags struct {
+	// Output is the output format ("default" or "json").
+	Output string
+	// Debug enables debug logging.
+	Debug bool
+	// NoColor disables ANSI colors in cliout output.
+	NoColor bool
+	// DryRun records intended actions instead of performing them. Only
+	// registered by CommandBuilder.WithDryRunFlag.
+	DryRun bool
+}
+
+// CommandBuilder assembles an extension's root cobra command with the
+// standard azd extension conventions: a persistent --output flag wired to
+// cliout.SetFormat, --debug wired to logutil, --no-color wired to cliout,
+// and orchestration detection via EnvOrchestrated.
+type CommandBuilder struct {
+	root     *cobra.Command
+	flags    *GlobalFlags
+	preRun   []func(cmd *cobra.Command, args []string) error
+	commands []*cobra.Command
+	dryRun   bool
+}
+
+// NewCommandBuilder creates a builder for a root command named use.
+//
+// Example:
+//
+//	info := version.New("jongio.azd.exec", "azd exec")
+//	root := azdextutil.NewCommandBuilder("exec", "Execute scripts with azd environment").
+//	    WithVersion(info).
+//	    AddCommand(newRunCommand()).
+//	    Build()
+//	if err := root.Execute(); err != nil {
+//	    os.Exit(1)
+//	}
+func NewCommandBuilder(use, short string) *CommandBuilder {
+	return &CommandBuilder{
+		root: &cobra.Command{
+			Use:           use,
+			Short:         short,
+			SilenceUsage:  true,
+			SilenceErrors: true,
+		},
+		flags: &GlobalFlags{Output: string(cliout.FormatDefault)},
+	}
+}
+
+// Flags returns the global flag values. Values are populated once the
+// command has parsed its arguments (from a command's RunE onward).
+func (b *CommandBuilder) Flags() *GlobalFlags {
+	return b.flags
+}
+
+// WithLong sets the root command's long description.
+func (b *CommandBuilder) WithLong(long string) *CommandBuilder {
+	b.root.Long = long
+	return b
+}
+
+// WithVersion adds a "version" subcommand that honors --output.
+func (b *CommandBuilder) WithVersion(info *version.Info) *CommandBuilder {
+	if info != nil {
+		b.root.Version = info.Version
+		b.commands = append(b.commands, version.NewCommand(info, &b.flags.Output))
+	}
+	return b
+}
+
+// WithPreRun registers a hook that runs after the standard flags have been
+// applied and before any command executes. Hooks run in registration order.
+func (b *CommandBuilder) WithPreRun(fn func(cmd *cobra.Command, args []string) error) *CommandBuilder {
+	if fn != nil {
+		b.preRun = append(b.preRun, fn)
+	}
+	return b
+}
+
+// WithDryRunFlag adds a persistent --dry-run flag. Every command receives
+// a context carrying a Recorder (see RecorderFromContext) before pre-run
+// hooks run, and with --dry-run the recorded plan is rendered after the
+// command succeeds.
+func (b *CommandBuilder) WithDryRunFlag() *CommandBuilder {
+	b.dryRun = true
+	return b
+}
+
+// AddCommand registers subcommands on the root command.
+func (b *CommandBuilder) AddCommand(cmds ...*cobra.Command) *CommandBuilder {
+	b.commands = append(b.commands, cmds...)
+	return b
+}
+
+// Build returns the configured root command.
+func (b *CommandBuilder) Build() *cobra.Command {
+	pf := b.root.PersistentFlags()
+	pf.StringVarP(&b.flags.Output, FlagOutput, "o", string(cliout.FormatDefault), "Output format (default, json)")
+	pf.BoolVar(&b.flags.Debug, FlagDebug, false, "Enable debug logging")
+	pf.BoolVar(&b.flags.NoColor, FlagNoColor, false, "Disable colored output")
+	if b.dryRun {
+		pf.BoolVar(&b.flags.DryRun, FlagDryRun, false, "Show planned changes without applying them")
+	}
+
+	b.root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := ApplyGlobalFlags(b.flags); err != nil {
+			return err
+		}
+		if b.dryRun {
+			cmd.SetContext(WithDryRun(cmd.Context(), NewRecorder(b.flags.DryRun)))
+		}
+		for _, fn := range b.preRun {
+			if err := fn(cmd, args); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if b.dryRun {
+		b.root.PersistentPostRunE = func(cmd *cobra.Command, _ []string) error {
+			if !b.flags.DryRun {
+				return nil
+			}
+			return RecorderFromContext(cmd.Context()).RenderPlan()
+		}
+	}
+
+	b.root.AddCommand(b.commands...)
+	return b.root
+}
+
+// ApplyGlobalFlags applies flag values to cliout and logutil. It is called
+// automatically by commands built with CommandBuilder and is exported for
+// extensions that construct their own root command.
+func ApplyGlobalFlags(flags *GlobalFlags) error {
+	if flags == nil {
+		return nil
+	}
+
+	if err := cliout.SetFormat(strings.ToLower(flags.Output)); err != nil {
+		return fmt.Errorf("invalid --%s value: %w", FlagOutput, err)
+	}
+
+	if flags.NoColor || os.Getenv("NO_COLOR") != "" {
+		cliout.NoColor()
+	}
+
+	debug := flags.Debug || os.Getenv(logutil.EnvDebug) == "true"
+	logutil.SetupLogger(debug, cliout.IsJSON())
+
+	cliout.SetOrchestrated(IsOrchestrated())
+	return nil
+}
+
+// IsOrchestrated reports whether EnvOrchestrated is set to "true".
+func IsOrchestrated() bool {
+	return strings.EqualFold(os.Getenv(EnvOrchestrated), "true")
+}
@@ -0,0 +1,113 @@
+package azdextutil
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/logutil"
+	"github.com/jongio/azd-core/version"
+)
+
+func resetGlobals(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		_ = cliout.SetFormat("default")
+		cliout.SetOrchestrated(false)
+		cliout.ForceColor()
+		logutil.SetupLogger(false, false)
+	})
+}
+
+func TestCommandBuilder_AppliesGlobalFlags(t *testing.T) {
+	resetGlobals(t)
+
+	var ran bool
+	builder := NewCommandBuilder("ext", "Test extension")
+	root := builder.AddCommand(&cobra.Command{
+		Use: "status",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ran = true
+			if !cliout.IsJSON() {
+				t.Error("expected JSON output format")
+			}
+			if logutil.GetLevel() != logutil.LevelDebug {
+				t.Error("expected debug logging")
+			}
+			return nil
+		},
+	}).Build()
+
+	root.SetArgs([]string{"status", "--output", "json", "--debug"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !ran {
+		t.Fatal("expected subcommand to run")
+	}
+	if builder.Flags().Output != "json" || !builder.Flags().Debug {
+		t.Errorf("unexpected flags: %+v", builder.Flags())
+	}
+}
+
+func TestCommandBuilder_InvalidOutput(t *testing.T) {
+	resetGlobals(t)
+
+	root := NewCommandBuilder("ext", "Test extension").
+		AddCommand(&cobra.Command{Use: "status", RunE: func(*cobra.Command, []string) error { return nil }}).
+		Build()
+	root.SetArgs([]string{"status", "-o", "yaml"})
+
+	if err := root.Execute(); err == nil {
+		t.Error("expected error for invalid output format")
+	}
+}
+
+func TestCommandBuilder_PreRunAndVersion(t *testing.T) {
+	resetGlobals(t)
+
+	var order []string
+	info := version.New("test.ext", "Test Ext")
+	root := NewCommandBuilder("ext", "Test extension").
+		WithVersion(info).
+		WithPreRun(func(*cobra.Command, []string) error {
+			order = append(order, "first")
+			return nil
+		}).
+		WithPreRun(func(*cobra.Command, []string) error {
+			order = append(order, "second")
+			return nil
+		}).
+		Build()
+
+	if _, _, err := root.Find([]string{"version"}); err != nil {
+		t.Fatalf("expected version subcommand: %v", err)
+	}
+
+	root.SetArgs([]string{"version", "--quiet"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("unexpected pre-run order: %v", order)
+	}
+}
+
+func TestApplyGlobalFlags_Orchestrated(t *testing.T) {
+	resetGlobals(t)
+	t.Setenv(EnvOrchestrated, "true")
+
+	if err := ApplyGlobalFlags(&GlobalFlags{}); err != nil {
+		t.Fatalf("ApplyGlobalFlags: %v", err)
+	}
+	if !cliout.IsOrchestrated() {
+		t.Error("expected orchestrated mode")
+	}
+}
+
+func TestApplyGlobalFlags_Nil(t *testing.T) {
+	if err := ApplyGlobalFlags(nil); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package azdextutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/jongio/azd-core/security"
+)
+
+// EnvAzdConfigDir overrides the azd configuration directory, matching azd's behavior.
+const EnvAzdConfigDir = "AZD_CONFIG_DIR"
+
+// ErrConfigVersionTooNew indicates a stored document was written by a newer
+// schema version than the running extension understands.
+var ErrConfigVersionTooNew = errors.New("config schema version is newer than supported")
+
+// MigrationFunc upgrades a stored document by exactly one schema version.
+// It receives the decoded data of version N and returns data for version N+1.
+type MigrationFunc func(data map[string]any) (map[string]any, error)
+
+// configEnvelope is the on-disk format of a ConfigStore document.
+type configEnvelope struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// ConfigStore persists per-extension configuration and state as versioned
+// JSON documents under <azd config dir>/extensions/<extension id>/.
+// Writes are atomic. ConfigStore is safe for concurrent use within a process.
+type ConfigStore struct {
+	dir        string
+	version    int
+	migrations map[int]MigrationFunc
+	mu         sync.Mutex
+}
+
+// AzdConfigDir returns the azd configuration directory: $AZD_CONFIG_DIR if
+// set, otherwise ~/.azd.
+func AzdConfigDir() (string, error) {
+	if dir := os.Getenv(EnvAzdConfigDir); dir != "" {
+		return filepath.Clean(dir), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".azd"), nil
+}
+
+// NewConfigStore creates a store namespaced by extensionID whose documents
+// use schemaVersion (1 or greater). The directory is created on first write.
+//
+// Example:
+//
+//	store, err := azdextutil.NewConfigStore("jongio.azd.app", 2)
+//	store.RegisterMigration(1, func(d map[string]any) (map[string]any, error) {
+//	    d["port"] = d["legacyPort"]
+//	    delete(d, "legacyPort")
+//	    return d, nil
+//	})
+//	var cfg Settings
+//	found, err := store.Load("settings", &cfg)
+func NewConfigStore(extensionID string, schemaVersion int) (*ConfigStore, error) {
+	if err := security.ValidateServiceName(extensionID, false); err != nil {
+		return nil, fmt.Errorf("invalid extension ID: %w", err)
+	}
+	if schemaVersion < 1 {
+		return nil, fmt.Errorf("schema version must be at least 1, got %d", schemaVersion)
+	}
+
+	base, err := AzdConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfigStore{
+		dir:        filepath.Join(base, "extensions", extensionID),
+		version:    schemaVersion,
+		migrations: make(map[int]MigrationFunc),
+	}, nil
+}
+
+// Dir returns the extension's config directory.
+func (s *ConfigStore) Dir() string {
+	return s.dir
+}
+
+// SchemaVersion returns the schema version documents are written with.
+func (s *ConfigStore) SchemaVersion() int {
+	return s.version
+}
+
+// RegisterMigration registers fn to upgrade documents from fromVersion to
+// fromVersion+1. Migrations run in sequence when Load encounters an older
+// document, and the upgraded document is written back.
+func (s *ConfigStore) RegisterMigration(fromVersion int, fn MigrationFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.migrations[fromVersion] = fn
+}
+
+// Save writes v as the named document at the current schema version.
+func (s *ConfigStore) Save(name string, v any) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(path, data)
+}
+
+// Load reads the named document into target, applying registered
+// migrations if it was written with an older schema version. It reports
+// false (with a nil error) when the document does not exist.
+func (s *ConfigStore) Load(name string, target any) (bool, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// #nosec G304 -- path is confined to the extension's config directory
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	var env configEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	switch {
+	case env.SchemaVersion > s.version:
+		return false, fmt.Errorf("%w: %s has version %d, supported up to %d", ErrConfigVersionTooNew, name, env.SchemaVersion, s.version)
+	case env.SchemaVersion < s.version:
+		migrated, err := s.migrate(name, env)
+		if err != nil {
+			return false, err
+		}
+		if err := s.write(path, migrated); err != nil {
+			return false, err
+		}
+		env.Data = migrated
+	}
+
+	if err := json.Unmarshal(env.Data, target); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// Delete removes the named document. Deleting a missing document is not an error.
+func (s *ConfigStore) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", name, err)
+	}
+	return nil
+}
+
+// List returns the names of stored documents, sorted.
+func (s *ConfigStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list config directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// migrate upgrades env.Data to the current schema version. Caller must hold s.mu.
+func (s *ConfigStore) migrate(name string, env configEnvelope) (json.RawMessage, error) {
+	var data map[string]any
+	if err := json.Unmarshal(env.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode %s for migration: %w", name, err)
+	}
+
+	for v := env.SchemaVersion; v < s.version; v++ {
+		fn, ok := s.migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration registered for %s from version %d to %d", name, v, v+1)
+		}
+		var err error
+		if data, err = fn(data); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s from version %d: %w", name, v, err)
+		}
+	}
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal migrated %s: %w", name, err)
+	}
+	return out, nil
+}
+
+// write stores data in an envelope at the current version. Caller must hold s.mu.
+func (s *ConfigStore) write(path string, data json.RawMessage) error {
+	if err := fileutil.EnsureDir(s.dir); err != nil {
+		return err
+	}
+	env := configEnvelope{SchemaVersion: s.version, Data: data}
+	if err := fileutil.AtomicWriteJSON(path, env); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// path validates name and returns its file path.
+func (s *ConfigStore) path(name string) (string, error) {
+	if err := security.ValidateServiceName(name, false); err != nil {
+		return "", fmt.Errorf("invalid config name: %w", err)
+	}
+	if strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid config name %q", name)
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
@@ -0,0 +1,151 @@
+package azdextutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testSettings struct {
+	Port int    `json:"port"`
+	Name string `json:"name"`
+}
+
+func newTestStore(t *testing.T, version int) *ConfigStore {
+	t.Helper()
+	t.Setenv(EnvAzdConfigDir, t.TempDir())
+	store, err := NewConfigStore("test.ext", version)
+	if err != nil {
+		t.Fatalf("NewConfigStore: %v", err)
+	}
+	return store
+}
+
+func TestAzdConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvAzdConfigDir, dir)
+	got, err := AzdConfigDir()
+	if err != nil || got != dir {
+		t.Errorf("AzdConfigDir() = %q, %v; want %q", got, err, dir)
+	}
+
+	t.Setenv(EnvAzdConfigDir, "")
+	got, err = AzdConfigDir()
+	if err != nil {
+		t.Fatalf("AzdConfigDir: %v", err)
+	}
+	if filepath.Base(got) != ".azd" {
+		t.Errorf("expected default ~/.azd, got %q", got)
+	}
+}
+
+func TestConfigStore_SaveLoad(t *testing.T) {
+	store := newTestStore(t, 1)
+
+	var missing testSettings
+	found, err := store.Load("settings", &missing)
+	if err != nil || found {
+		t.Fatalf("expected missing document, got found=%v err=%v", found, err)
+	}
+
+	if err := store.Save("settings", testSettings{Port: 8080, Name: "api"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	var got testSettings
+	found, err = store.Load("settings", &got)
+	if err != nil || !found {
+		t.Fatalf("Load: found=%v err=%v", found, err)
+	}
+	if got.Port != 8080 || got.Name != "api" {
+		t.Errorf("unexpected settings: %+v", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(store.Dir(), "settings.json")); err != nil {
+		t.Errorf("expected file under extension dir: %v", err)
+	}
+
+	names, err := store.List()
+	if err != nil || len(names) != 1 || names[0] != "settings" {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+
+	if err := store.Delete("settings"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete("settings"); err != nil {
+		t.Errorf("expected deleting missing document to succeed: %v", err)
+	}
+}
+
+func TestConfigStore_Migration(t *testing.T) {
+	v1 := newTestStore(t, 1)
+	if err := v1.Save("settings", map[string]any{"legacyPort": 3000, "name": "web"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	v3, err := NewConfigStore("test.ext", 3)
+	if err != nil {
+		t.Fatalf("NewConfigStore: %v", err)
+	}
+	v3.RegisterMigration(1, func(d map[string]any) (map[string]any, error) {
+		d["port"] = d["legacyPort"]
+		delete(d, "legacyPort")
+		return d, nil
+	})
+	v3.RegisterMigration(2, func(d map[string]any) (map[string]any, error) {
+		d["name"] = d["name"].(string) + "-v3"
+		return d, nil
+	})
+
+	var got testSettings
+	if _, err := v3.Load("settings", &got); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.Port != 3000 || got.Name != "web-v3" {
+		t.Errorf("unexpected migrated settings: %+v", got)
+	}
+
+	// The migrated document is persisted, so older stores now see a newer version.
+	if _, err := v1.Load("settings", &got); !errors.Is(err, ErrConfigVersionTooNew) {
+		t.Errorf("expected ErrConfigVersionTooNew, got %v", err)
+	}
+}
+
+func TestConfigStore_MissingMigration(t *testing.T) {
+	v1 := newTestStore(t, 1)
+	if err := v1.Save("state", map[string]any{"a": 1}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	v2, err := NewConfigStore("test.ext", 2)
+	if err != nil {
+		t.Fatalf("NewConfigStore: %v", err)
+	}
+	var got map[string]any
+	if _, err := v2.Load("state", &got); err == nil {
+		t.Error("expected error for missing migration")
+	}
+}
+
+func TestConfigStore_InvalidInputs(t *testing.T) {
+	t.Setenv(EnvAzdConfigDir, t.TempDir())
+
+	if _, err := NewConfigStore("../evil", 1); err == nil {
+		t.Error("expected error for invalid extension ID")
+	}
+	if _, err := NewConfigStore("test.ext", 0); err == nil {
+		t.Error("expected error for schema version 0")
+	}
+
+	store, err := NewConfigStore("test.ext", 1)
+	if err != nil {
+		t.Fatalf("NewConfigStore: %v", err)
+	}
+	for _, name := range []string{"", "../x", "a/b", "a..b"} {
+		if err := store.Save(name, 1); err == nil {
+			t.Errorf("expected error for config name %q", name)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package azdextutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/jongio/azd-core/logutil"
+	"github.com/jongio/azd-core/security"
+)
+
+// CodeCrash is the RichError code presented when Main recovers a panic.
+const CodeCrash = "CRASH"
+
+// Exit codes returned by RunMain.
+const (
+	ExitOK          = 0
+	ExitError       = 1
+	ExitPanic       = 2
+	ExitInterrupted = 130
+)
+
+// maxCrashStackSize bounds the goroutine dump written to a crash report.
+const maxCrashStackSize = 4 << 20
+
+// MainOptions configures Main and RunMain.
+type MainOptions struct {
+	// Name identifies the extension in crash reports and their file names.
+	// Defaults to the executable name.
+	Name string
+	// Version is recorded in crash reports.
+	Version string
+	// CrashDir is where crash reports are written. Defaults to
+	// <user cache dir>/azd/crash, or the temp directory if that is unavailable.
+	CrashDir string
+	// RingBuffer supplies the recent log records included in crash reports.
+	// Defaults to the buffer installed with logutil.SetRingBuffer; if none is
+	// installed, one of logutil.DefaultRingBufferSize records is installed.
+	RingBuffer *logutil.RingBuffer
+	// Telemetry, if set, is closed before RunMain returns, including after
+	// a panic, so pending events are flushed even though Main exits with
+	// os.Exit. Close is bounded by TelemetryCloseTimeout.
+	Telemetry *Telemetry
+}
+
+// crashNow is replaced in tests to get stable report names.
+var crashNow = time.Now
+
+// Main runs an extension's entry point with consistent signal and crash
+// handling, then exits the process. See RunMain.
+//
+// Example:
+//
+//	func main() {
+//	    azdextutil.Main(func(ctx context.Context) error {
+//	        return newRootCmd().ExecuteContext(ctx)
+//	    })
+//	}
+func Main(run func(ctx context.Context) error) {
+	os.Exit(RunMain(MainOptions{}, run))
+}
+
+// RunMain calls run with a context canceled on SIGINT or SIGTERM and returns
+// the process exit code:
+//
+//   - ExitOK when run returns nil
+//   - ExitInterrupted when run fails after a signal canceled the context
+//   - ExitError for any other error, which is shown with cliout.PresentError
+//   - ExitPanic when run panics
+//
+// A panic is recovered and written, with the stack of every goroutine and the
+// recent log records from the ring buffer, to a crash report file. The user
+// sees a short error pointing at the report instead of a raw stack trace.
+// Panics in goroutines started by run cannot be recovered and still crash
+// the process.
+func RunMain(opts MainOptions, run func(ctx context.Context) error) (code int) {
+	opts = opts.withDefaults()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if opts.Telemetry != nil {
+		defer closeTelemetry(opts.Telemetry)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			stack := goroutineStacks()
+			reportPath, err := writeCrashReport(opts, r, stack)
+			presentCrash(opts.Name, r, reportPath, err)
+			code = ExitPanic
+		}
+	}()
+
+	err := run(ctx)
+	switch {
+	case err == nil:
+		return ExitOK
+	case ctx.Err() != nil && errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	default:
+		cliout.PresentError(err)
+		if ctx.Err() != nil {
+			return ExitInterrupted
+		}
+		return ExitError
+	}
+}
+
+// closeTelemetry flushes tel within TelemetryCloseTimeout. Failures are
+// logged at debug level and never change the exit code.
+func closeTelemetry(tel *Telemetry) {
+	ctx, cancel := context.WithTimeout(context.Background(), TelemetryCloseTimeout)
+	defer cancel()
+	if err := tel.Close(ctx); err != nil {
+		logutil.Debug("telemetry flush failed", "error", err)
+	}
+}
+
+func (o MainOptions) withDefaults() MainOptions {
+	if o.Name == "" {
+		o.Name = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	}
+	if o.CrashDir == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			o.CrashDir = filepath.Join(dir, "azd", "crash")
+		} else {
+			o.CrashDir = filepath.Join(os.TempDir(), "azd-crash")
+		}
+	}
+	if o.RingBuffer == nil {
+		o.RingBuffer = logutil.GetRingBuffer()
+	}
+	if o.RingBuffer == nil {
+		o.RingBuffer = logutil.NewRingBuffer(0)
+		logutil.SetRingBuffer(o.RingBuffer)
+	}
+	return o
+}
+
+// goroutineStacks returns the stack traces of all goroutines, growing the
+// buffer until the dump fits or maxCrashStackSize is reached.
+func goroutineStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxCrashStackSize {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// writeCrashReport writes a crash report for the recovered value r and
+// returns its path. Secrets in the panic value and log records are redacted.
+func writeCrashReport(opts MainOptions, r interface{}, stack []byte) (string, error) {
+	now := crashNow()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s crash report\n\n", opts.Name)
+	fmt.Fprintf(&b, "Time:    %s\n", now.Format(time.RFC3339))
+	if opts.Version != "" {
+		fmt.Fprintf(&b, "Version: %s\n", opts.Version)
+	}
+	fmt.Fprintf(&b, "Go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Args:    %s\n", security.RedactSecrets(strings.Join(os.Args, " ")))
+	fmt.Fprintf(&b, "\nPanic: %s\n", security.RedactSecrets(fmt.Sprint(r)))
+	fmt.Fprintf(&b, "\nStack:\n%s\n", stack)
+	b.WriteString("\nRecent log:\n")
+	if err := opts.RingBuffer.Dump(&b); err != nil {
+		fmt.Fprintf(&b, "(failed to dump log: %v)\n", err)
+	}
+
+	if err := fileutil.EnsureDir(opts.CrashDir); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-crash-%s-%d.log", opts.Name, now.Format("20060102-150405"), os.Getpid())
+	path := filepath.Join(opts.CrashDir, name)
+	if err := fileutil.AtomicWriteFile(path, b.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// presentCrash tells the user the extension crashed and where the report is.
+func presentCrash(name string, r interface{}, reportPath string, writeErr error) {
+	rich := &cliout.RichError{
+		Code:    CodeCrash,
+		Summary: fmt.Sprintf("%s crashed unexpectedly", name),
+		Details: security.RedactSecrets(fmt.Sprint(r)),
+	}
+	if writeErr != nil {
+		rich.Suggestions = []string{
+			fmt.Sprintf("The crash report could not be written: %v", writeErr),
+			"Re-run with --debug and include the output when reporting the issue",
+		}
+	} else {
+		rich.Suggestions = []string{
+			fmt.Sprintf("A crash report was written to %s", reportPath),
+			"Include the crash report when reporting the issue",
+		}
+	}
+	cliout.PresentError(rich)
+}
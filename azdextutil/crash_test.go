@@ -0,0 +1,211 @@
+package azdextutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/logutil"
+)
+
+// captureStdout returns everything fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+	fn()
+	_ = w.Close()
+	return <-done
+}
+
+func testMainOptions(t *testing.T) MainOptions {
+	t.Helper()
+	return MainOptions{
+		Name:       "azd-test",
+		Version:    "1.2.3",
+		CrashDir:   t.TempDir(),
+		RingBuffer: logutil.NewRingBuffer(10),
+	}
+}
+
+func TestRunMainSuccess(t *testing.T) {
+	var gotCtx context.Context
+	code := RunMain(testMainOptions(t), func(ctx context.Context) error {
+		gotCtx = ctx
+		return nil
+	})
+	if code != ExitOK {
+		t.Errorf("code = %d, want %d", code, ExitOK)
+	}
+	if gotCtx == nil {
+		t.Error("run was not called with a context")
+	}
+}
+
+func TestRunMainError(t *testing.T) {
+	var code int
+	output := captureStdout(t, func() {
+		code = RunMain(testMainOptions(t), func(ctx context.Context) error {
+			return errors.New("deploy failed")
+		})
+	})
+	if code != ExitError {
+		t.Errorf("code = %d, want %d", code, ExitError)
+	}
+	if !strings.Contains(output, "deploy failed") {
+		t.Errorf("error not presented: %q", output)
+	}
+}
+
+func TestRunMainFlushesTelemetry(t *testing.T) {
+	t.Setenv(EnvCollectTelemetry, "")
+	for _, tt := range []struct {
+		name string
+		run  func(ctx context.Context) error
+		code int
+	}{
+		{"success", func(context.Context) error { return nil }, ExitOK},
+		{"error", func(context.Context) error { return errors.New("failed") }, ExitError},
+		{"panic", func(context.Context) error { panic("boom") }, ExitPanic},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			opts := testMainOptions(t)
+			opts.Telemetry = NewTelemetry("test.ext", "1.2.3", sink)
+
+			var code int
+			captureStdout(t, func() {
+				code = RunMain(opts, func(ctx context.Context) error {
+					opts.Telemetry.Record(ctx, "run", time.Millisecond, nil)
+					return tt.run(ctx)
+				})
+			})
+			if code != tt.code {
+				t.Errorf("code = %d, want %d", code, tt.code)
+			}
+			if got := len(sink.events()); got != 1 {
+				t.Errorf("flushed %d events, want 1", got)
+			}
+		})
+	}
+}
+
+func TestRunMainPanicWritesCrashReport(t *testing.T) {
+	oldNow := crashNow
+	crashNow = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { crashNow = oldNow }()
+
+	opts := testMainOptions(t)
+	var code int
+	output := captureStdout(t, func() {
+		code = RunMain(opts, func(ctx context.Context) error {
+			logutil.SetRingBuffer(opts.RingBuffer)
+			defer logutil.SetRingBuffer(nil)
+			logutil.Debug("loading project", "path", "/src/app")
+			panic("nil map access password=hunter2")
+		})
+	})
+	if code != ExitPanic {
+		t.Errorf("code = %d, want %d", code, ExitPanic)
+	}
+
+	entries, err := os.ReadDir(opts.CrashDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("crash dir entries = %v, %v; want one report", entries, err)
+	}
+	name := entries[0].Name()
+	if !strings.HasPrefix(name, "azd-test-crash-20260102-030405-") {
+		t.Errorf("report name = %q", name)
+	}
+	reportPath := filepath.Join(opts.CrashDir, name)
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{"azd-test crash report", "Version: 1.2.3", "Panic: nil map access", "TestRunMainPanicWritesCrashReport", "loading project"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "hunter2") || strings.Contains(output, "hunter2") {
+		t.Error("secret in panic value was not redacted")
+	}
+
+	for _, want := range []string{"azd-test crashed unexpectedly", reportPath} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestRunMainPanicReportFailure(t *testing.T) {
+	opts := testMainOptions(t)
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts.CrashDir = filepath.Join(blocker, "crash")
+
+	var code int
+	output := captureStdout(t, func() {
+		code = RunMain(opts, func(ctx context.Context) error { panic("boom") })
+	})
+	if code != ExitPanic {
+		t.Errorf("code = %d, want %d", code, ExitPanic)
+	}
+	if !strings.Contains(output, "could not be written") {
+		t.Errorf("output should explain the report failure:\n%s", output)
+	}
+}
+
+func TestRunMainInterrupted(t *testing.T) {
+	code := RunMain(testMainOptions(t), func(ctx context.Context) error {
+		self, _ := os.FindProcess(os.Getpid())
+		if err := self.Signal(syscall.SIGTERM); err != nil {
+			t.Skipf("cannot signal self: %v", err)
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if code != ExitInterrupted {
+		t.Errorf("code = %d, want %d", code, ExitInterrupted)
+	}
+}
+
+func TestMainOptionsDefaults(t *testing.T) {
+	defer logutil.SetRingBuffer(nil)
+
+	opts := MainOptions{}.withDefaults()
+	if opts.Name == "" || opts.CrashDir == "" {
+		t.Errorf("defaults not applied: %+v", opts)
+	}
+	if opts.RingBuffer == nil || logutil.GetRingBuffer() != opts.RingBuffer {
+		t.Error("a ring buffer should be installed when none is set")
+	}
+
+	installed := logutil.NewRingBuffer(5)
+	logutil.SetRingBuffer(installed)
+	if got := (MainOptions{}).withDefaults().RingBuffer; got != installed {
+		t.Error("the installed ring buffer should be reused")
+	}
+}
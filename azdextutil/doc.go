@@ -2,5 +2,30 @@
 // that integrate with the Azure Developer CLI extension framework.
 //
 // It reduces boilerplate for common patterns like metadata generation,
-// listen command creation, MCP server setup, and distributed tracing.
+// listen command creation, MCP server setup, distributed tracing, project
+// root and azure.yaml discovery, and consistent signal and crash handling
+// via Main.
+//
+// Listen commands exchange messages with the azd host through a Transport,
+// an adapter over the host's extension channel (or NewInMemoryTransport in
+// tests). A Session dispatches incoming messages to a Handler, sends
+// heartbeats, and reconnects with backoff when the connection drops:
+//
+//	session := azdextutil.NewSession(func(ctx context.Context) (azdextutil.Transport, error) {
+//	    return newHostTransport(ctx) // adapts azd's extension channel
+//	}, handleMessage, azdextutil.SessionOptions{})
+//	return session.Run(cmd.Context())
+//
+// Commands that change files or resources can share one plan/apply flow:
+// CommandBuilder.WithDryRunFlag adds --dry-run, and each change is recorded
+// before it is made, then rendered as a table or JSON plan in dry-run mode:
+//
+//	if azdextutil.RecorderFromContext(cmd.Context()).Record("create", path, nil) {
+//	    err = os.WriteFile(path, data, 0o644)
+//	}
+//
+// MCPRegistry collects the resources (azure.yaml, environment snapshots,
+// health status) and prompts an extension offers to AI clients over MCP,
+// with per-resource caching and change notifications, independent of the
+// MCP server library in use.
 package azdextutil
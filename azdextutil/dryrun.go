@@ -0,0 +1,153 @@
+package azdextutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+// FlagDryRun is the name of the flag registered by
+// CommandBuilder.WithDryRunFlag.
+const FlagDryRun = "dry-run"
+
+type dryRunContextKey struct{}
+
+// PlannedAction is an operation recorded by a Recorder.
+type PlannedAction struct {
+	// Action is a short verb such as "create", "update", or "delete".
+	Action string `json:"action"`
+	// Target identifies what the action applies to, such as a file path or
+	// resource name.
+	Target string `json:"target"`
+	// Details holds optional key/value context for the action.
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// Plan is the JSON form of the actions rendered by Recorder.RenderPlan.
+type Plan struct {
+	DryRun  bool            `json:"dryRun"`
+	Actions []PlannedAction `json:"actions"`
+}
+
+// Recorder collects the operations a command intends to perform. In dry-run
+// mode, Record tells the caller to skip the operation and RenderPlan shows
+// what would have been done, so every extension offers the same plan/apply
+// experience. Recorder is safe for concurrent use.
+type Recorder struct {
+	dryRun bool
+
+	mu      sync.Mutex
+	actions []PlannedAction
+}
+
+// NewRecorder creates a Recorder. When dryRun is false, Record still
+// collects actions but always tells the caller to execute them.
+func NewRecorder(dryRun bool) *Recorder {
+	return &Recorder{dryRun: dryRun}
+}
+
+// WithDryRun returns a copy of ctx carrying r. Commands built with
+// CommandBuilder.WithDryRunFlag receive a context that already carries one.
+func WithDryRun(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, r)
+}
+
+// RecorderFromContext returns the Recorder stored in ctx by WithDryRun. If
+// there is none, it returns a new Recorder that is not in dry-run mode, so
+// callers can record unconditionally.
+func RecorderFromContext(ctx context.Context) *Recorder {
+	if ctx != nil {
+		if r, ok := ctx.Value(dryRunContextKey{}).(*Recorder); ok && r != nil {
+			return r
+		}
+	}
+	return NewRecorder(false)
+}
+
+// IsDryRun reports whether ctx carries a Recorder in dry-run mode.
+func IsDryRun(ctx context.Context) bool {
+	return RecorderFromContext(ctx).DryRun()
+}
+
+// DryRun reports whether r is in dry-run mode.
+func (r *Recorder) DryRun() bool {
+	return r.dryRun
+}
+
+// Record notes an intended action and reports whether the caller should
+// perform it: false in dry-run mode, true otherwise. details may be nil.
+//
+// Example:
+//
+//	rec := azdextutil.RecorderFromContext(cmd.Context())
+//	if rec.Record("delete", path, nil) {
+//	    if err := os.Remove(path); err != nil {
+//	        return err
+//	    }
+//	}
+func (r *Recorder) Record(action, target string, details map[string]string) bool {
+	var copied map[string]string
+	if len(details) > 0 {
+		copied = make(map[string]string, len(details))
+		for k, v := range details {
+			copied[k] = v
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.actions = append(r.actions, PlannedAction{Action: action, Target: target, Details: copied})
+	return !r.dryRun
+}
+
+// Actions returns the recorded actions in the order they were recorded.
+func (r *Recorder) Actions() []PlannedAction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]PlannedAction(nil), r.actions...)
+}
+
+// RenderPlan prints the recorded actions through cliout: a table in the
+// default format, or a Plan object in JSON and NDJSON formats.
+func (r *Recorder) RenderPlan() error {
+	plan := Plan{DryRun: r.dryRun, Actions: r.Actions()}
+	if plan.Actions == nil {
+		plan.Actions = []PlannedAction{}
+	}
+
+	return cliout.Print(plan, func() {
+		if len(plan.Actions) == 0 {
+			cliout.Info("Dry run: no changes would be made")
+			return
+		}
+		noun := "actions"
+		if len(plan.Actions) == 1 {
+			noun = "action"
+		}
+		cliout.Section(cliout.IconCheck, fmt.Sprintf("Dry run: %d planned %s", len(plan.Actions), noun))
+		rows := make([]cliout.TableRow, len(plan.Actions))
+		for i, a := range plan.Actions {
+			rows[i] = cliout.TableRow{"Action": a.Action, "Target": a.Target, "Details": formatDetails(a.Details)}
+		}
+		cliout.Table([]string{"Action", "Target", "Details"}, rows)
+	})
+}
+
+// formatDetails renders details as "key=value" pairs sorted by key.
+func formatDetails(details map[string]string) string {
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + details[k]
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,140 @@
+package azdextutil
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+func TestRecorder_Record(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		wantExecute bool
+	}{
+		{name: "dry run", dryRun: true, wantExecute: false},
+		{name: "apply", dryRun: false, wantExecute: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRecorder(tt.dryRun)
+			details := map[string]string{"size": "10"}
+			if got := r.Record("create", "a.txt", details); got != tt.wantExecute {
+				t.Errorf("Record() = %v, want %v", got, tt.wantExecute)
+			}
+			details["size"] = "20"
+			r.Record("delete", "b.txt", nil)
+
+			actions := r.Actions()
+			if len(actions) != 2 || actions[0].Target != "a.txt" || actions[1].Action != "delete" {
+				t.Fatalf("Actions() = %+v", actions)
+			}
+			if actions[0].Details["size"] != "10" {
+				t.Errorf("details were not copied: %v", actions[0].Details)
+			}
+		})
+	}
+}
+
+func TestRecorderFromContext(t *testing.T) {
+	if IsDryRun(context.Background()) {
+		t.Error("expected no dry run without a recorder")
+	}
+	if r := RecorderFromContext(context.Background()); r == nil || !r.Record("create", "x", nil) {
+		t.Error("expected a recorder that executes actions")
+	}
+
+	rec := NewRecorder(true)
+	ctx := WithDryRun(context.Background(), rec)
+	if !IsDryRun(ctx) || RecorderFromContext(ctx) != rec {
+		t.Error("expected the stored dry-run recorder")
+	}
+}
+
+func TestRecorder_RenderPlan(t *testing.T) {
+	resetGlobals(t)
+
+	r := NewRecorder(true)
+	r.Record("update", "azure.yaml", map[string]string{"service": "api", "key": "host"})
+
+	output := captureStdout(t, func() {
+		if err := r.RenderPlan(); err != nil {
+			t.Errorf("RenderPlan: %v", err)
+		}
+	})
+	for _, want := range []string{"1 planned action", "update", "azure.yaml", "key=host, service=api"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	_ = cliout.SetFormat("json")
+	output = captureStdout(t, func() {
+		if err := NewRecorder(true).RenderPlan(); err != nil {
+			t.Errorf("RenderPlan: %v", err)
+		}
+	})
+	var plan Plan
+	if err := json.Unmarshal([]byte(output), &plan); err != nil {
+		t.Fatalf("invalid JSON %q: %v", output, err)
+	}
+	if !plan.DryRun || plan.Actions == nil || len(plan.Actions) != 0 {
+		t.Errorf("plan = %+v", plan)
+	}
+}
+
+func TestCommandBuilder_DryRunFlag(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantExecute bool
+		wantPlan    bool
+	}{
+		{name: "dry run", args: []string{"apply", "--dry-run", "-o", "json"}, wantExecute: false, wantPlan: true},
+		{name: "apply", args: []string{"apply", "-o", "json"}, wantExecute: true, wantPlan: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetGlobals(t)
+
+			var executed bool
+			root := NewCommandBuilder("ext", "Test extension").
+				WithDryRunFlag().
+				AddCommand(&cobra.Command{
+					Use: "apply",
+					RunE: func(cmd *cobra.Command, args []string) error {
+						if RecorderFromContext(cmd.Context()).Record("create", "main.bicep", nil) {
+							executed = true
+						}
+						return nil
+					},
+				}).
+				Build()
+			root.SetArgs(tt.args)
+
+			output := captureStdout(t, func() {
+				if err := root.Execute(); err != nil {
+					t.Errorf("Execute: %v", err)
+				}
+			})
+			if executed != tt.wantExecute {
+				t.Errorf("executed = %v, want %v", executed, tt.wantExecute)
+			}
+			if got := strings.Contains(output, `"target": "main.bicep"`); got != tt.wantPlan {
+				t.Errorf("plan rendered = %v, want %v:\n%s", got, tt.wantPlan, output)
+			}
+		})
+	}
+}
+
+func TestCommandBuilder_NoDryRunFlagByDefault(t *testing.T) {
+	root := NewCommandBuilder("ext", "Test extension").Build()
+	if root.PersistentFlags().Lookup(FlagDryRun) != nil {
+		t.Error("expected --dry-run to be registered only by WithDryRunFlag")
+	}
+}
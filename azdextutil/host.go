@@ -0,0 +1,173 @@
+package azdextutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jongio/azd-core/env"
+	"github.com/jongio/azd-core/pathutil"
+	"github.com/jongio/azd-core/semverutil"
+)
+
+// ErrAzdVersionUnsupported is returned (wrapped in a *VersionError) when the
+// host azd does not satisfy a version constraint or capability requirement.
+var ErrAzdVersionUnsupported = errors.New("azd version not supported")
+
+// hostRunner runs the azd CLI to query host information. Tests replace it.
+var hostRunner env.CommandRunner = &env.DefaultCommandRunner{}
+
+// semverPattern extracts the first version from "azd version" text output.
+var semverPattern = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.\-]+))?`)
+
+// HostInfo describes the azd installation invoking the extension.
+type HostInfo struct {
+	// Version is the azd version, e.g. "1.11.0".
+	Version string `json:"version"`
+	// Commit is the azd build commit, when reported.
+	Commit string `json:"commit,omitempty"`
+}
+
+// Capability names a host feature and the first azd version that supports it.
+// Extensions declare the capabilities they depend on and check them with
+// HostInfo.Supports or RequireCapabilities.
+type Capability struct {
+	Name       string
+	MinVersion string
+}
+
+// VersionError describes an unsupported host azd version. It wraps
+// ErrAzdVersionUnsupported and its message includes upgrade instructions.
+type VersionError struct {
+	// Actual is the detected azd version.
+	Actual string
+	// Constraint is the version constraint that was not satisfied.
+	Constraint string
+	// Capability is the capability that required the constraint, if any.
+	Capability string
+}
+
+// Error implements the error interface.
+func (e *VersionError) Error() string {
+	var b strings.Builder
+	if e.Capability != "" {
+		fmt.Fprintf(&b, "%s requires azd %s", e.Capability, e.Constraint)
+	} else {
+		fmt.Fprintf(&b, "this extension requires azd %s", e.Constraint)
+	}
+	fmt.Fprintf(&b, " (found %s). Upgrade azd: %s", e.Actual, pathutil.GetInstallSuggestion("azd"))
+	return b.String()
+}
+
+// Unwrap returns ErrAzdVersionUnsupported.
+func (e *VersionError) Unwrap() error {
+	return ErrAzdVersionUnsupported
+}
+
+// DetectHost queries the installed azd for its version using
+// "azd version --output json", falling back to parsing the plain text output
+// of older releases.
+func DetectHost(ctx context.Context) (*HostInfo, error) {
+	output, err := hostRunner.Run(ctx, "azd", "version", "--output", "json")
+	if err == nil {
+		var result struct {
+			Azd HostInfo `json:"azd"`
+		}
+		if jsonErr := json.Unmarshal(output, &result); jsonErr == nil && result.Azd.Version != "" {
+			return &result.Azd, nil
+		}
+	} else {
+		output, err = hostRunner.Run(ctx, "azd", "version")
+		if err != nil {
+			return nil, fmt.Errorf("failed to query azd version: %w", err)
+		}
+	}
+
+	match := semverPattern.FindString(string(output))
+	if match == "" {
+		return nil, fmt.Errorf("failed to parse azd version from %q", strings.TrimSpace(string(output)))
+	}
+	return &HostInfo{Version: match}, nil
+}
+
+// Satisfies reports whether the host version satisfies constraint.
+//
+// The constraint uses the npm-style syntax of semverutil.ParseConstraint,
+// for example ">=1.10.0", ">=1.10 <2", or "^1.10.0". A full version on its
+// own is treated as ">=" that version. Pre-release builds are ordered like
+// any other version, so beta and daily builds such as 1.11.0-beta.1 satisfy
+// ">=1.10.0". Development builds (0.0.0 with a pre-release suffix) satisfy
+// every constraint.
+func (h *HostInfo) Satisfies(constraint string) (bool, error) {
+	actual, err := semverutil.Parse(semverPattern.FindString(h.Version))
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := semverutil.Parse(strings.TrimSpace(constraint)); err == nil {
+		constraint = ">=" + strings.TrimSpace(constraint)
+	}
+	c, err := semverutil.ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	c.IncludePrerelease = true
+	return c.Check(actual) || isDevBuild(actual), nil
+}
+
+// Supports reports whether the host provides capability c.
+func (h *HostInfo) Supports(c Capability) bool {
+	ok, err := h.Satisfies(">=" + c.MinVersion)
+	return err == nil && ok
+}
+
+// RequireAzdVersion returns a *VersionError if the installed azd does not
+// satisfy constraint. See HostInfo.Satisfies for the constraint syntax.
+//
+// Example:
+//
+//	if err := azdextutil.RequireAzdVersion(ctx, ">=1.10.0"); err != nil {
+//	    return err // "this extension requires azd >=1.10.0 (found 1.9.3). Upgrade azd: ..."
+//	}
+func RequireAzdVersion(ctx context.Context, constraint string) error {
+	host, err := DetectHost(ctx)
+	if err != nil {
+		return err
+	}
+	return host.require(constraint, "")
+}
+
+// RequireCapabilities returns a *VersionError for the first capability the
+// installed azd does not support.
+func RequireCapabilities(ctx context.Context, caps ...Capability) error {
+	host, err := DetectHost(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range caps {
+		if err := host.require(">="+c.MinVersion, c.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// require checks constraint and builds a VersionError on failure.
+func (h *HostInfo) require(constraint, capability string) error {
+	ok, err := h.Satisfies(constraint)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &VersionError{Actual: h.Version, Constraint: constraint, Capability: capability}
+	}
+	return nil
+}
+
+// isDevBuild reports whether v is an unversioned development build.
+func isDevBuild(v semverutil.Version) bool {
+	return v.Major == 0 && v.Minor == 0 && v.Patch == 0 && v.IsPrerelease()
+}
@@ -0,0 +1,163 @@
+package azdextutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeHostRunner struct {
+	outputs map[string]string
+}
+
+func (f *fakeHostRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	key := name + " " + strings.Join(args, " ")
+	out, ok := f.outputs[key]
+	if !ok {
+		return nil, errors.New("unknown command: " + key)
+	}
+	return []byte(out), nil
+}
+
+func setHostRunner(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	prev := hostRunner
+	hostRunner = &fakeHostRunner{outputs: outputs}
+	t.Cleanup(func() { hostRunner = prev })
+}
+
+func TestDetectHost(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		setHostRunner(t, map[string]string{
+			"azd version --output json": `{"azd":{"version":"1.11.0","commit":"abc123"}}`,
+		})
+		host, err := DetectHost(context.Background())
+		if err != nil {
+			t.Fatalf("DetectHost: %v", err)
+		}
+		if host.Version != "1.11.0" || host.Commit != "abc123" {
+			t.Errorf("unexpected host: %+v", host)
+		}
+	})
+
+	t.Run("text fallback", func(t *testing.T) {
+		setHostRunner(t, map[string]string{
+			"azd version": "azd version 1.5.1 (commit 0123456)\n",
+		})
+		host, err := DetectHost(context.Background())
+		if err != nil {
+			t.Fatalf("DetectHost: %v", err)
+		}
+		if host.Version != "1.5.1" {
+			t.Errorf("Version = %q, want 1.5.1", host.Version)
+		}
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		setHostRunner(t, map[string]string{})
+		if _, err := DetectHost(context.Background()); err == nil {
+			t.Error("expected error when azd is unavailable")
+		}
+	})
+}
+
+func TestHostInfo_Satisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.11.0", ">=1.10.0", true},
+		{"1.11.0", "1.11.0", true},
+		{"1.9.9", ">=1.10.0", false},
+		{"1.10.0", ">1.10.0", false},
+		{"1.10.0", "<2.0.0", true},
+		{"2.0.0", ">=1.10.0, <2.0.0", false},
+		{"1.10.0", "=1.10.0", true},
+		{"1.10.0-beta.1", ">=1.10.0", false},
+		{"1.11.0-beta.1", ">=1.10.0", true},
+		{"1.12.0-daily.5012345", ">=1.10.0", true},
+		{"1.9.0", "1.10.0", false},
+		{"1.12.0", "1.10.0", true},
+		{"1.10.0-beta.2", ">1.10.0-beta.1", true},
+		{"0.0.0-dev.0", ">=99.0.0", true},
+		{"1.12.0", ">=1.10 <2", true},
+		{"2.0.0", ">=1.10 <2", false},
+		{"1.0.5", "~1.0.0", true},
+		{"1.1.0", "~1.0.0", false},
+		{"1.12.0", "^1.10.0", true},
+		{"1.11.0-beta.1", "^1.10.0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			host := &HostInfo{Version: tt.version}
+			got, err := host.Satisfies(tt.constraint)
+			if err != nil {
+				t.Fatalf("Satisfies: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Satisfies(%q) = %v, want %v", tt.constraint, got, tt.want)
+			}
+		})
+	}
+
+	host := &HostInfo{Version: "1.0.0"}
+	for _, bad := range []string{"", ">=", "~>1.0", ">=one"} {
+		if _, err := host.Satisfies(bad); err == nil {
+			t.Errorf("expected error for constraint %q", bad)
+		}
+	}
+}
+
+func TestRequireAzdVersion(t *testing.T) {
+	setHostRunner(t, map[string]string{
+		"azd version --output json": `{"azd":{"version":"1.9.3"}}`,
+	})
+
+	if err := RequireAzdVersion(context.Background(), ">=1.9.0"); err != nil {
+		t.Errorf("expected 1.9.3 to satisfy >=1.9.0: %v", err)
+	}
+
+	err := RequireAzdVersion(context.Background(), ">=1.10.0")
+	if !errors.Is(err, ErrAzdVersionUnsupported) {
+		t.Fatalf("expected ErrAzdVersionUnsupported, got %v", err)
+	}
+	for _, want := range []string{">=1.10.0", "1.9.3", "https://aka.ms/install-azd"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestRequireCapabilities(t *testing.T) {
+	setHostRunner(t, map[string]string{
+		"azd version --output json": `{"azd":{"version":"1.12.0"}}`,
+	})
+
+	listen := Capability{Name: "listen", MinVersion: "1.10.0"}
+	future := Capability{Name: "future feature", MinVersion: "2.0.0"}
+
+	if err := RequireCapabilities(context.Background(), listen); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := RequireCapabilities(context.Background(), listen, future)
+	var verr *VersionError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *VersionError, got %v", err)
+	}
+	if verr.Capability != "future feature" || !strings.Contains(err.Error(), "future feature requires azd") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	host := &HostInfo{Version: "1.12.0"}
+	if !host.Supports(listen) || host.Supports(future) {
+		t.Error("unexpected Supports result")
+	}
+	for _, v := range []string{"1.11.0-beta.1", "1.12.0-daily.5012345"} {
+		if !(&HostInfo{Version: v}).Supports(listen) {
+			t.Errorf("expected %s to support %s", v, listen.Name)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package azdextutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jongio/azd-core/healthcheck"
+	"github.com/jongio/azd-core/logutil"
+	"github.com/jongio/azd-core/security"
+)
+
+// DefaultShutdownTimeout bounds how long Lifecycle waits for components to stop.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// HealthzPath is the path served by the optional Lifecycle health endpoint.
+const HealthzPath = "/healthz"
+
+// ErrLifecycleAlreadyRun is returned when Run is called more than once on
+// the same Lifecycle.
+var ErrLifecycleAlreadyRun = errors.New("lifecycle already run")
+
+// Component is a long-running part of a listen command (an event handler,
+// gRPC client, file watcher, etc.) managed by a Lifecycle.
+type Component struct {
+	// Name identifies the component in logs and health reports.
+	Name string
+	// Start launches the component. It must not block; background work
+	// should be started in a goroutine.
+	Start func(ctx context.Context) error
+	// Stop shuts the component down, returning before ctx expires. Optional.
+	Stop func(ctx context.Context) error
+	// Health reports nil when the component is healthy. Optional; components
+	// without a Health func are reported healthy once started.
+	Health func(ctx context.Context) error
+}
+
+// LifecycleOptions configures a Lifecycle.
+type LifecycleOptions struct {
+	// ShutdownTimeout bounds graceful shutdown. Defaults to DefaultShutdownTimeout.
+	ShutdownTimeout time.Duration
+	// HealthAddr, if set, serves HealthzPath on this address (for example
+	// "127.0.0.1:0"). Leave empty to disable the endpoint.
+	HealthAddr string
+	// OnReady is called once all components have started, to signal
+	// readiness to the azd host.
+	OnReady func()
+}
+
+// Lifecycle runs the components of an extension's listen command: it starts
+// them in registration order, waits for SIGINT/SIGTERM or context
+// cancellation, then stops them in reverse order within a shutdown deadline.
+type Lifecycle struct {
+	opts       LifecycleOptions
+	components []Component
+	log        *logutil.ComponentLogger
+
+	mu         sync.RWMutex
+	ran        bool
+	ready      bool
+	healthAddr string
+	readyCh    chan struct{}
+}
+
+// NewLifecycle creates a Lifecycle with opts.
+//
+// Example:
+//
+//	lc := azdextutil.NewLifecycle(azdextutil.LifecycleOptions{HealthAddr: "127.0.0.1:0"})
+//	lc.Register(azdextutil.Component{Name: "events", Start: handler.Start, Stop: handler.Stop})
+//	return lc.Run(cmd.Context())
+func NewLifecycle(opts LifecycleOptions) *Lifecycle {
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	return &Lifecycle{
+		opts:    opts,
+		log:     logutil.NewLogger("lifecycle"),
+		readyCh: make(chan struct{}),
+	}
+}
+
+// Register adds a component. Components must be registered before Run.
+func (l *Lifecycle) Register(c Component) {
+	l.components = append(l.components, c)
+}
+
+// Ready returns a channel that is closed once all components have started.
+func (l *Lifecycle) Ready() <-chan struct{} {
+	return l.readyCh
+}
+
+// HealthAddr returns the address the health endpoint is bound to, or "" if
+// the endpoint is disabled or not yet listening.
+func (l *Lifecycle) HealthAddr() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.healthAddr
+}
+
+// Run starts all components and blocks until ctx is canceled or the process
+// receives SIGINT or SIGTERM, then shuts down gracefully. It returns the
+// joined errors from startup or shutdown. A Lifecycle runs only once; later
+// calls return ErrLifecycleAlreadyRun.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	l.mu.Lock()
+	if l.ran {
+		l.mu.Unlock()
+		return ErrLifecycleAlreadyRun
+	}
+	l.ran = true
+	l.mu.Unlock()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var server *http.Server
+	if l.opts.HealthAddr != "" {
+		var err error
+		server, err = l.serveHealth()
+		if err != nil {
+			return err
+		}
+	}
+
+	started, startErr := l.startComponents(ctx)
+	if startErr == nil {
+		l.markReady()
+		l.log.Info("all components started", "count", len(started))
+		<-ctx.Done()
+		l.log.Info("shutting down", "timeout", l.opts.ShutdownTimeout)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), l.opts.ShutdownTimeout)
+	defer cancel()
+
+	errs := []error{startErr}
+	for i := len(started) - 1; i >= 0; i-- {
+		c := started[i]
+		if c.Stop == nil {
+			continue
+		}
+		if err := c.Stop(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", c.Name, err))
+		}
+	}
+	if server != nil {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop health endpoint: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// startComponents starts components in order, returning those that started.
+func (l *Lifecycle) startComponents(ctx context.Context) ([]Component, error) {
+	started := make([]Component, 0, len(l.components))
+	for _, c := range l.components {
+		if c.Start != nil {
+			if err := c.Start(ctx); err != nil {
+				return started, fmt.Errorf("failed to start %s: %w", c.Name, err)
+			}
+		}
+		l.log.Debug("component started", "component", c.Name)
+		started = append(started, c)
+	}
+	return started, nil
+}
+
+// markReady records readiness and notifies the host.
+func (l *Lifecycle) markReady() {
+	l.mu.Lock()
+	l.ready = true
+	l.mu.Unlock()
+	close(l.readyCh)
+	if l.opts.OnReady != nil {
+		l.opts.OnReady()
+	}
+}
+
+// serveHealth starts the health endpoint in the background.
+func (l *Lifecycle) serveHealth() (*http.Server, error) {
+	listener, err := net.Listen("tcp", l.opts.HealthAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", l.opts.HealthAddr, err)
+	}
+
+	l.mu.Lock()
+	l.healthAddr = listener.Addr().String()
+	l.mu.Unlock()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthzPath, l.handleHealthz)
+	server := &http.Server{
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			l.log.Error("health endpoint failed", "error", err)
+		}
+	}()
+	return server, nil
+}
+
+// Report checks every component and returns an aggregated health report.
+// Before all components have started, each is reported as starting.
+// Component errors are redacted with security.RedactSecrets, since the
+// report is served over HTTP.
+func (l *Lifecycle) Report(ctx context.Context) healthcheck.HealthReport {
+	l.mu.RLock()
+	ready := l.ready
+	l.mu.RUnlock()
+
+	results := make([]healthcheck.HealthCheckResult, 0, len(l.components))
+	for _, c := range l.components {
+		start := time.Now()
+		result := healthcheck.HealthCheckResult{
+			ServiceName: c.Name,
+			Status:      healthcheck.HealthStatusHealthy,
+			CheckType:   healthcheck.HealthCheckTypeProcess,
+			Timestamp:   start,
+		}
+		switch {
+		case !ready:
+			result.Status = healthcheck.HealthStatusStarting
+		case c.Health != nil:
+			if err := c.Health(ctx); err != nil {
+				result.Status = healthcheck.HealthStatusUnhealthy
+				result.Error = security.RedactSecrets(err.Error())
+			}
+		}
+		result.ResponseTime = time.Since(start)
+		results = append(results, result)
+	}
+
+	return healthcheck.HealthReport{
+		Timestamp: time.Now(),
+		Services:  results,
+		Summary:   healthcheck.Summarize(results),
+	}
+}
+
+// handleHealthz serves the health report as JSON, responding 503 while
+// starting or when any component is unhealthy.
+func (l *Lifecycle) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	report := l.Report(r.Context())
+
+	l.mu.RLock()
+	ready := l.ready
+	l.mu.RUnlock()
+
+	status := http.StatusOK
+	if !ready || report.Summary.Overall == healthcheck.HealthStatusUnhealthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
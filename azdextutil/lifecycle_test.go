@@ -0,0 +1,201 @@
+package azdextutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/healthcheck"
+)
+
+type eventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *eventLog) add(s string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, s)
+}
+
+func (e *eventLog) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return strings.Join(e.events, ",")
+}
+
+func trackedComponent(name string, log *eventLog) Component {
+	return Component{
+		Name:  name,
+		Start: func(context.Context) error { log.add("start:" + name); return nil },
+		Stop:  func(context.Context) error { log.add("stop:" + name); return nil },
+	}
+}
+
+func TestLifecycle_StartStopOrder(t *testing.T) {
+	log := &eventLog{}
+	readyCalled := make(chan struct{})
+	lc := NewLifecycle(LifecycleOptions{OnReady: func() { close(readyCalled) }})
+	lc.Register(trackedComponent("a", log))
+	lc.Register(trackedComponent("b", log))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- lc.Run(ctx) }()
+
+	select {
+	case <-lc.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("lifecycle did not become ready")
+	}
+	<-readyCalled
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := log.String(); got != "start:a,start:b,stop:b,stop:a" {
+		t.Errorf("unexpected order: %s", got)
+	}
+}
+
+func TestLifecycle_RunTwice(t *testing.T) {
+	log := &eventLog{}
+	lc := NewLifecycle(LifecycleOptions{})
+	lc.Register(trackedComponent("a", log))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := lc.Run(ctx); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	if err := lc.Run(context.Background()); !errors.Is(err, ErrLifecycleAlreadyRun) {
+		t.Errorf("second Run error = %v, want ErrLifecycleAlreadyRun", err)
+	}
+	if got := log.String(); got != "start:a,stop:a" {
+		t.Errorf("events = %q, want components started once", got)
+	}
+}
+
+func TestLifecycle_StartFailureStopsStarted(t *testing.T) {
+	log := &eventLog{}
+	lc := NewLifecycle(LifecycleOptions{})
+	lc.Register(trackedComponent("a", log))
+	lc.Register(Component{Name: "broken", Start: func(context.Context) error { return errors.New("boom") }})
+	lc.Register(trackedComponent("c", log))
+
+	err := lc.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to start broken") {
+		t.Fatalf("expected start error, got %v", err)
+	}
+	if got := log.String(); got != "start:a,stop:a" {
+		t.Errorf("unexpected events: %s", got)
+	}
+}
+
+func TestLifecycle_ShutdownDeadline(t *testing.T) {
+	lc := NewLifecycle(LifecycleOptions{ShutdownTimeout: 50 * time.Millisecond})
+	lc.Register(Component{
+		Name: "slow",
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := lc.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("shutdown took too long: %v", elapsed)
+	}
+}
+
+func TestLifecycle_HealthEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	var healthErr error
+	lc := NewLifecycle(LifecycleOptions{HealthAddr: "127.0.0.1:0"})
+	lc.Register(Component{
+		Name: "worker",
+		Health: func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return healthErr
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- lc.Run(ctx) }()
+	<-lc.Ready()
+
+	get := func() (int, healthcheck.HealthReport) {
+		t.Helper()
+		resp, err := http.Get("http://" + lc.HealthAddr() + HealthzPath)
+		if err != nil {
+			t.Fatalf("GET healthz: %v", err)
+		}
+		defer resp.Body.Close()
+		var report healthcheck.HealthReport
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp.StatusCode, report
+	}
+
+	status, report := get()
+	if status != http.StatusOK || report.Summary.Overall != healthcheck.HealthStatusHealthy {
+		t.Errorf("expected healthy 200, got %d %s", status, report.Summary.Overall)
+	}
+
+	mu.Lock()
+	healthErr = errors.New("queue disconnected")
+	mu.Unlock()
+
+	status, report = get()
+	if status != http.StatusServiceUnavailable || report.Services[0].Error != "queue disconnected" {
+		t.Errorf("expected unhealthy 503, got %d %+v", status, report.Services)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func TestLifecycle_ReportRedactsErrors(t *testing.T) {
+	lc := NewLifecycle(LifecycleOptions{})
+	lc.Register(Component{
+		Name: "storage",
+		Health: func(context.Context) error {
+			return errors.New("dial failed: AccountName=dev;AccountKey=c2VjcmV0a2V5;EndpointSuffix=core.windows.net")
+		},
+	})
+	lc.markReady()
+
+	got := lc.Report(context.Background()).Services[0].Error
+	if strings.Contains(got, "c2VjcmV0a2V5") || !strings.Contains(got, "dial failed") {
+		t.Errorf("Error = %q, want the account key redacted", got)
+	}
+}
+
+func TestLifecycle_ReportBeforeReady(t *testing.T) {
+	lc := NewLifecycle(LifecycleOptions{})
+	lc.Register(Component{Name: "worker"})
+
+	report := lc.Report(context.Background())
+	if report.Summary.Overall != healthcheck.HealthStatusStarting {
+		t.Errorf("expected starting, got %s", report.Summary.Overall)
+	}
+}
@@ -1 +1,351 @@
 package azdextutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrMCPResourceNotFound is returned when a resource URI is not registered.
+	ErrMCPResourceNotFound = errors.New("MCP resource not found")
+	// ErrMCPPromptNotFound is returned when a prompt name is not registered.
+	ErrMCPPromptNotFound = errors.New("MCP prompt not found")
+)
+
+// AzureYamlResourceURI is the URI of the resource built by AzureYamlResource.
+const AzureYamlResourceURI = "azd://project/azure.yaml"
+
+// MCPResource is a readable resource exposed to MCP clients, such as the
+// project's azure.yaml, an environment snapshot, or service health.
+type MCPResource struct {
+	// URI identifies the resource, for example "azd://project/azure.yaml".
+	// It must have a scheme.
+	URI         string
+	Name        string
+	Description string
+	// MIMEType describes the content. Empty means "text/plain".
+	MIMEType string
+	// Read returns the current content.
+	Read func(ctx context.Context) ([]byte, error)
+	// CacheTTL keeps content returned by Read for this long, so clients
+	// polling a resource do not recompute it. 0 disables caching.
+	// NotifyResourceChanged discards cached content early.
+	CacheTTL time.Duration
+}
+
+// MCPResourceContent is the content of a resource read through an MCPRegistry.
+type MCPResourceContent struct {
+	URI      string `json:"uri"`
+	MIMEType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// MCPPromptArgument describes an argument of an MCPPrompt.
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MCPPromptMessage is one message of a rendered prompt. Role is "user" or
+// "assistant".
+type MCPPromptMessage struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+}
+
+// MCPPrompt is a reusable prompt template offered to MCP clients.
+type MCPPrompt struct {
+	Name        string
+	Description string
+	Arguments   []MCPPromptArgument
+	// Render builds the prompt messages from the client's arguments.
+	// Required arguments are checked before it is called.
+	Render func(ctx context.Context, args map[string]string) ([]MCPPromptMessage, error)
+}
+
+// MCPRegistry holds the resources and prompts an extension exposes over
+// MCP. It does not depend on a particular MCP server library: list and
+// read requests are answered from Resources, ReadResource, Prompts, and
+// GetPrompt, and OnResourceChanged is wired to the server's
+// resource-updated notification. It is safe for concurrent use.
+//
+// Example:
+//
+//	reg := azdextutil.NewMCPRegistry()
+//	_ = reg.AddResource(azdextutil.AzureYamlResource(projectDir))
+//	_ = reg.AddResource(azdextutil.JSONResource("azd://health", "Service health",
+//	    func(ctx context.Context) (any, error) { return checker.CheckAll(ctx, services, healthcheck.CheckAllOptions{}), nil }))
+//	reg.OnResourceChanged(func(uri string) { server.NotifyResourceUpdated(uri) })
+type MCPRegistry struct {
+	mu        sync.Mutex
+	resources map[string]MCPResource
+	resOrder  []string
+	cache     map[string]mcpCacheEntry
+	// versions counts NotifyResourceChanged calls per URI, so a read that
+	// started before a change does not cache stale content.
+	versions  map[string]uint64
+	prompts   map[string]MCPPrompt
+	prmOrder  []string
+	listeners []func(uri string)
+}
+
+// mcpCacheEntry is cached resource content.
+type mcpCacheEntry struct {
+	content MCPResourceContent
+	expires time.Time
+}
+
+// mcpNow is replaced in tests.
+var mcpNow = time.Now
+
+// NewMCPRegistry creates an empty registry.
+func NewMCPRegistry() *MCPRegistry {
+	return &MCPRegistry{
+		resources: make(map[string]MCPResource),
+		cache:     make(map[string]mcpCacheEntry),
+		versions:  make(map[string]uint64),
+		prompts:   make(map[string]MCPPrompt),
+	}
+}
+
+// AddResource registers res. The URI must be unique and have a scheme, and
+// Read must be set.
+func (r *MCPRegistry) AddResource(res MCPResource) error {
+	u, err := url.Parse(res.URI)
+	if err != nil || u.Scheme == "" {
+		return fmt.Errorf("invalid MCP resource URI %q: must be an absolute URI", res.URI)
+	}
+	if res.Read == nil {
+		return fmt.Errorf("MCP resource %q has no Read function", res.URI)
+	}
+	if res.MIMEType == "" {
+		res.MIMEType = "text/plain"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.resources[res.URI]; exists {
+		return fmt.Errorf("MCP resource %q is already registered", res.URI)
+	}
+	r.resources[res.URI] = res
+	r.resOrder = append(r.resOrder, res.URI)
+	return nil
+}
+
+// Resources returns the registered resources in registration order.
+func (r *MCPRegistry) Resources() []MCPResource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := make([]MCPResource, 0, len(r.resOrder))
+	for _, uri := range r.resOrder {
+		list = append(list, r.resources[uri])
+	}
+	return list
+}
+
+// ReadResource returns the content of the resource at uri, from the cache
+// when it is still fresh.
+func (r *MCPRegistry) ReadResource(ctx context.Context, uri string) (MCPResourceContent, error) {
+	r.mu.Lock()
+	res, ok := r.resources[uri]
+	entry, cached := r.cache[uri]
+	version := r.versions[uri]
+	r.mu.Unlock()
+	if !ok {
+		return MCPResourceContent{}, fmt.Errorf("%w: %s", ErrMCPResourceNotFound, uri)
+	}
+	if cached && mcpNow().Before(entry.expires) {
+		return entry.content, nil
+	}
+
+	data, err := res.Read(ctx)
+	if err != nil {
+		return MCPResourceContent{}, fmt.Errorf("failed to read MCP resource %s: %w", uri, err)
+	}
+	content := MCPResourceContent{URI: uri, MIMEType: res.MIMEType, Text: string(data)}
+
+	if res.CacheTTL > 0 {
+		r.mu.Lock()
+		if r.versions[uri] == version {
+			r.cache[uri] = mcpCacheEntry{content: content, expires: mcpNow().Add(res.CacheTTL)}
+		}
+		r.mu.Unlock()
+	}
+	return content, nil
+}
+
+// OnResourceChanged registers fn to be called with the URI of every
+// resource passed to NotifyResourceChanged. Use it to send the MCP
+// notifications/resources/updated message to subscribed clients.
+func (r *MCPRegistry) OnResourceChanged(fn func(uri string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// NotifyResourceChanged discards cached content for uri and calls the
+// OnResourceChanged listeners. Call it when the underlying data changes,
+// for example after a health check or when azure.yaml is saved. Unknown
+// URIs are ignored.
+func (r *MCPRegistry) NotifyResourceChanged(uri string) {
+	r.mu.Lock()
+	if _, ok := r.resources[uri]; !ok {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.cache, uri)
+	r.versions[uri]++
+	listeners := append([]func(string){}, r.listeners...)
+	r.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(uri)
+	}
+}
+
+// AddPrompt registers p. The name must be unique and Render must be set.
+func (r *MCPRegistry) AddPrompt(p MCPPrompt) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("MCP prompt name cannot be empty")
+	}
+	if p.Render == nil {
+		return fmt.Errorf("MCP prompt %q has no Render function", p.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.prompts[p.Name]; exists {
+		return fmt.Errorf("MCP prompt %q is already registered", p.Name)
+	}
+	r.prompts[p.Name] = p
+	r.prmOrder = append(r.prmOrder, p.Name)
+	return nil
+}
+
+// Prompts returns the registered prompts in registration order.
+func (r *MCPRegistry) Prompts() []MCPPrompt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := make([]MCPPrompt, 0, len(r.prmOrder))
+	for _, name := range r.prmOrder {
+		list = append(list, r.prompts[name])
+	}
+	return list
+}
+
+// GetPrompt renders the prompt called name with args, after checking that
+// every required argument is present and not empty.
+func (r *MCPRegistry) GetPrompt(ctx context.Context, name string, args map[string]string) ([]MCPPromptMessage, error) {
+	r.mu.Lock()
+	p, ok := r.prompts[name]
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMCPPromptNotFound, name)
+	}
+
+	for _, arg := range p.Arguments {
+		if arg.Required && strings.TrimSpace(args[arg.Name]) == "" {
+			return nil, fmt.Errorf("MCP prompt %q requires argument %q", name, arg.Name)
+		}
+	}
+	return p.Render(ctx, args)
+}
+
+// TextPrompt builds a prompt with a single user message from template, in
+// which each {{name}} is replaced by the argument of that name (empty if
+// it was not given).
+//
+// Example:
+//
+//	_ = reg.AddPrompt(azdextutil.TextPrompt("diagnose-service",
+//	    "Diagnose a failing service",
+//	    "Service {{service}} is unhealthy. Read azd://health and azure.yaml and suggest a fix.",
+//	    azdextutil.MCPPromptArgument{Name: "service", Required: true}))
+func TextPrompt(name, description, template string, args ...MCPPromptArgument) MCPPrompt {
+	return MCPPrompt{
+		Name:        name,
+		Description: description,
+		Arguments:   args,
+		Render: func(_ context.Context, values map[string]string) ([]MCPPromptMessage, error) {
+			pairs := make([]string, 0, 2*len(args))
+			for _, arg := range args {
+				pairs = append(pairs, "{{"+arg.Name+"}}", values[arg.Name])
+			}
+			return []MCPPromptMessage{{Role: "user", Text: strings.NewReplacer(pairs...).Replace(template)}}, nil
+		},
+	}
+}
+
+// FileResource exposes the file at path. The MIME type is derived from the
+// extension (YAML, JSON, or Markdown; plain text otherwise), and the file
+// is read again on every request.
+func FileResource(uri, name, path string) MCPResource {
+	return MCPResource{
+		URI:      uri,
+		Name:     name,
+		MIMEType: mimeTypeForFile(path),
+		Read: func(context.Context) ([]byte, error) {
+			return os.ReadFile(path) // #nosec G304 -- path is chosen by the extension, not the client
+		},
+	}
+}
+
+// AzureYamlResource exposes the azure.yaml (or azure.yml) in projectDir at
+// AzureYamlResourceURI. The file is located on every read, so it may be
+// created after the resource is registered.
+func AzureYamlResource(projectDir string) MCPResource {
+	return MCPResource{
+		URI:         AzureYamlResourceURI,
+		Name:        "azure.yaml",
+		Description: "The azd project file: services, infrastructure, and hooks",
+		MIMEType:    "application/yaml",
+		Read: func(context.Context) ([]byte, error) {
+			path := findAzureYaml(projectDir)
+			if path == "" {
+				return nil, ErrProjectNotFound
+			}
+			return os.ReadFile(path) // #nosec G304 -- path is the project file
+		},
+	}
+}
+
+// JSONResource exposes the value returned by fn as indented JSON, for
+// example an environment snapshot or health check results. Redact secrets
+// before returning them; clients may show resources to a model verbatim.
+func JSONResource(uri, name string, fn func(ctx context.Context) (any, error)) MCPResource {
+	return MCPResource{
+		URI:      uri,
+		Name:     name,
+		MIMEType: "application/json",
+		Read: func(ctx context.Context) ([]byte, error) {
+			v, err := fn(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return json.MarshalIndent(v, "", "  ")
+		},
+	}
+}
+
+// mimeTypeForFile returns the MIME type for a file resource.
+func mimeTypeForFile(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "application/yaml"
+	case ".json":
+		return "application/json"
+	case ".md":
+		return "text/markdown"
+	default:
+		return "text/plain"
+	}
+}
@@ -0,0 +1,230 @@
+package azdextutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMCPRegistry_AddResource(t *testing.T) {
+	read := func(context.Context) ([]byte, error) { return nil, nil }
+	reg := NewMCPRegistry()
+
+	if err := reg.AddResource(MCPResource{URI: "azd://env", Name: "env", Read: read}); err != nil {
+		t.Fatalf("AddResource() error = %v", err)
+	}
+	if err := reg.AddResource(MCPResource{URI: "azd://health", Name: "health", MIMEType: "application/json", Read: read}); err != nil {
+		t.Fatalf("AddResource() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		res  MCPResource
+	}{
+		{"duplicate", MCPResource{URI: "azd://env", Read: read}},
+		{"relative URI", MCPResource{URI: "env", Read: read}},
+		{"empty URI", MCPResource{Read: read}},
+		{"no Read", MCPResource{URI: "azd://other"}},
+	}
+	for _, tt := range tests {
+		if err := reg.AddResource(tt.res); err == nil {
+			t.Errorf("AddResource(%s) error = nil, want error", tt.name)
+		}
+	}
+
+	list := reg.Resources()
+	if len(list) != 2 || list[0].URI != "azd://env" || list[1].URI != "azd://health" {
+		t.Fatalf("Resources() = %+v", list)
+	}
+	if list[0].MIMEType != "text/plain" || list[1].MIMEType != "application/json" {
+		t.Errorf("MIME types = %q, %q", list[0].MIMEType, list[1].MIMEType)
+	}
+}
+
+func TestMCPRegistry_ReadResourceCaching(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	oldNow := mcpNow
+	mcpNow = func() time.Time { return now }
+	t.Cleanup(func() { mcpNow = oldNow })
+
+	calls := 0
+	reg := NewMCPRegistry()
+	_ = reg.AddResource(MCPResource{
+		URI:      "azd://health",
+		CacheTTL: time.Minute,
+		Read: func(context.Context) ([]byte, error) {
+			calls++
+			return []byte(strings.Repeat("x", calls)), nil
+		},
+	})
+	var notified []string
+	reg.OnResourceChanged(func(uri string) { notified = append(notified, uri) })
+
+	read := func() string {
+		t.Helper()
+		content, err := reg.ReadResource(context.Background(), "azd://health")
+		if err != nil {
+			t.Fatalf("ReadResource() error = %v", err)
+		}
+		return content.Text
+	}
+
+	if got := read(); got != "x" {
+		t.Errorf("first read = %q", got)
+	}
+	if got := read(); got != "x" || calls != 1 {
+		t.Errorf("cached read = %q after %d calls, want cached x", got, calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if got := read(); got != "xx" {
+		t.Errorf("read after TTL = %q, want xx", got)
+	}
+
+	reg.NotifyResourceChanged("azd://health")
+	reg.NotifyResourceChanged("azd://unknown")
+	if got := read(); got != "xxx" {
+		t.Errorf("read after change = %q, want xxx", got)
+	}
+	if len(notified) != 1 || notified[0] != "azd://health" {
+		t.Errorf("notified = %v, want [azd://health]", notified)
+	}
+}
+
+func TestMCPRegistry_ReadResourceErrors(t *testing.T) {
+	reg := NewMCPRegistry()
+	if _, err := reg.ReadResource(context.Background(), "azd://missing"); !errors.Is(err, ErrMCPResourceNotFound) {
+		t.Errorf("ReadResource(missing) error = %v, want ErrMCPResourceNotFound", err)
+	}
+
+	readErr := errors.New("boom")
+	calls := 0
+	_ = reg.AddResource(MCPResource{URI: "azd://broken", CacheTTL: time.Hour, Read: func(context.Context) ([]byte, error) {
+		calls++
+		return nil, readErr
+	}})
+	for i := 0; i < 2; i++ {
+		if _, err := reg.ReadResource(context.Background(), "azd://broken"); !errors.Is(err, readErr) {
+			t.Errorf("ReadResource(broken) error = %v, want %v", err, readErr)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Read calls = %d, want 2 (errors are not cached)", calls)
+	}
+}
+
+func TestMCPRegistry_Prompts(t *testing.T) {
+	reg := NewMCPRegistry()
+	prompt := TextPrompt("diagnose", "Diagnose a service", "Why is {{service}} failing in {{env}}?",
+		MCPPromptArgument{Name: "service", Required: true},
+		MCPPromptArgument{Name: "env"},
+	)
+	if err := reg.AddPrompt(prompt); err != nil {
+		t.Fatalf("AddPrompt() error = %v", err)
+	}
+	if err := reg.AddPrompt(prompt); err == nil {
+		t.Error("AddPrompt() duplicate error = nil")
+	}
+	if err := reg.AddPrompt(MCPPrompt{Name: " ", Render: prompt.Render}); err == nil {
+		t.Error("AddPrompt() with empty name error = nil")
+	}
+	if err := reg.AddPrompt(MCPPrompt{Name: "x"}); err == nil {
+		t.Error("AddPrompt() without Render error = nil")
+	}
+
+	if list := reg.Prompts(); len(list) != 1 || list[0].Name != "diagnose" || len(list[0].Arguments) != 2 {
+		t.Errorf("Prompts() = %+v", list)
+	}
+
+	msgs, err := reg.GetPrompt(context.Background(), "diagnose", map[string]string{"service": "api", "env": "dev"})
+	if err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].Role != "user" || msgs[0].Text != "Why is api failing in dev?" {
+		t.Errorf("GetPrompt() = %+v", msgs)
+	}
+
+	msgs, _ = reg.GetPrompt(context.Background(), "diagnose", map[string]string{"service": "api"})
+	if msgs[0].Text != "Why is api failing in ?" {
+		t.Errorf("GetPrompt() without optional arg = %q", msgs[0].Text)
+	}
+
+	if _, err := reg.GetPrompt(context.Background(), "diagnose", map[string]string{"service": " "}); err == nil || !strings.Contains(err.Error(), "service") {
+		t.Errorf("GetPrompt() without required arg error = %v", err)
+	}
+	if _, err := reg.GetPrompt(context.Background(), "missing", nil); !errors.Is(err, ErrMCPPromptNotFound) {
+		t.Errorf("GetPrompt(missing) error = %v, want ErrMCPPromptNotFound", err)
+	}
+}
+
+func TestMCPResourceHelpers(t *testing.T) {
+	dir := t.TempDir()
+	reg := NewMCPRegistry()
+	ctx := context.Background()
+
+	_ = reg.AddResource(AzureYamlResource(dir))
+	if _, err := reg.ReadResource(ctx, AzureYamlResourceURI); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("ReadResource(azure.yaml) before it exists error = %v, want ErrProjectNotFound", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "azure.yml"), []byte("name: demo\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	content, err := reg.ReadResource(ctx, AzureYamlResourceURI)
+	if err != nil || content.Text != "name: demo\n" || content.MIMEType != "application/yaml" {
+		t.Errorf("ReadResource(azure.yaml) = %+v, %v", content, err)
+	}
+
+	notes := filepath.Join(dir, "NOTES.md")
+	_ = os.WriteFile(notes, []byte("# Notes"), 0600)
+	_ = reg.AddResource(FileResource("file:///notes", "notes", notes))
+	if content, err := reg.ReadResource(ctx, "file:///notes"); err != nil || content.MIMEType != "text/markdown" || content.Text != "# Notes" {
+		t.Errorf("ReadResource(notes) = %+v, %v", content, err)
+	}
+
+	_ = reg.AddResource(JSONResource("azd://env", "env", func(context.Context) (any, error) {
+		return map[string]string{"AZURE_LOCATION": "eastus"}, nil
+	}))
+	content, err = reg.ReadResource(ctx, "azd://env")
+	if err != nil || content.MIMEType != "application/json" || !strings.Contains(content.Text, `"AZURE_LOCATION": "eastus"`) {
+		t.Errorf("ReadResource(env) = %+v, %v", content, err)
+	}
+}
+
+func TestMimeTypeForFile(t *testing.T) {
+	tests := map[string]string{
+		"azure.yaml": "application/yaml",
+		"a.YML":      "application/yaml",
+		"x.json":     "application/json",
+		"README.md":  "text/markdown",
+		"log.txt":    "text/plain",
+		"Makefile":   "text/plain",
+	}
+	for path, want := range tests {
+		if got := mimeTypeForFile(path); got != want {
+			t.Errorf("mimeTypeForFile(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestMCPRegistry_ChangeDuringRead(t *testing.T) {
+	reg := NewMCPRegistry()
+	calls := 0
+	_ = reg.AddResource(MCPResource{URI: "azd://env", CacheTTL: time.Hour, Read: func(context.Context) ([]byte, error) {
+		calls++
+		if calls == 1 {
+			// The data changes while the first read is in flight.
+			reg.NotifyResourceChanged("azd://env")
+		}
+		return []byte("v"), nil
+	}})
+
+	_, _ = reg.ReadResource(context.Background(), "azd://env")
+	_, _ = reg.ReadResource(context.Background(), "azd://env")
+	if calls != 2 {
+		t.Errorf("Read calls = %d, want 2 (content read before a change must not be cached)", calls)
+	}
+}
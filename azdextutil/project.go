@@ -0,0 +1,222 @@
+package azdextutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrProjectNotFound is returned by FindProjectRoot when no azd project is
+// found between the start directory and the filesystem boundary.
+var ErrProjectNotFound = errors.New("no azd project found (azure.yaml or .azure)")
+
+// azureYamlNames are the project file names azd accepts, in order of preference.
+var azureYamlNames = []string{"azure.yaml", "azure.yml"}
+
+// projectUserHomeDir is replaced in tests.
+var projectUserHomeDir = os.UserHomeDir
+
+// AzureYaml is the subset of an azd project file that extensions commonly
+// need. Unknown fields are ignored.
+type AzureYaml struct {
+	// Name is the project name.
+	Name string `yaml:"name"`
+	// Metadata holds the template the project was created from.
+	Metadata AzureYamlMetadata `yaml:"metadata"`
+	// Infra configures the infrastructure provider.
+	Infra AzureYamlInfra `yaml:"infra"`
+	// Services are keyed by service name.
+	Services map[string]AzureYamlService `yaml:"services"`
+
+	// Path is the absolute path of the file that was loaded.
+	Path string `yaml:"-"`
+}
+
+// AzureYamlMetadata is the metadata section of azure.yaml.
+type AzureYamlMetadata struct {
+	Template string `yaml:"template"`
+}
+
+// AzureYamlInfra is the infra section of azure.yaml.
+type AzureYamlInfra struct {
+	Provider string `yaml:"provider"`
+	Path     string `yaml:"path"`
+	Module   string `yaml:"module"`
+}
+
+// AzureYamlService is one entry of the services section of azure.yaml.
+type AzureYamlService struct {
+	// Name is the service's key in the services section.
+	Name string `yaml:"-"`
+	// Project is the service directory, relative to the project root.
+	Project string `yaml:"project"`
+	// Language is the service language, e.g. "python" or "ts".
+	Language string `yaml:"language"`
+	// Host is the Azure host, e.g. "containerapp" or "appservice".
+	Host string `yaml:"host"`
+	// Image is a prebuilt container image, used instead of Project.
+	Image string `yaml:"image"`
+}
+
+// FindProjectRoot walks up from startDir to the directory containing
+// azure.yaml (or azure.yml) or a .azure directory and returns it.
+// startDir is made absolute and its symlinks are resolved first, so the
+// result is the same whether the project was reached through a link or not.
+//
+// The walk stops at the filesystem root and at mount points, where the
+// parent is on a different device, and returns ErrProjectNotFound. The
+// .azure directory in the user's home directory belongs to the Azure CLI and
+// does not mark a project.
+//
+// Example:
+//
+//	root, err := azdextutil.FindProjectRoot(".")
+//	if errors.Is(err, azdextutil.ErrProjectNotFound) {
+//	    return fmt.Errorf("run this command from an azd project: %w", err)
+//	}
+func FindProjectRoot(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", startDir, err)
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", startDir, err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", startDir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", startDir)
+	}
+
+	home, _ := projectUserHomeDir()
+	if home != "" {
+		if resolved, err := filepath.EvalSymlinks(home); err == nil {
+			home = resolved
+		}
+	}
+
+	for {
+		if findAzureYaml(dir) != "" {
+			return dir, nil
+		}
+		if dir != home && isDir(filepath.Join(dir, ".azure")) {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrProjectNotFound
+		}
+		parentInfo, err := os.Stat(parent)
+		if err != nil || !sameDevice(info, parentInfo) {
+			return "", ErrProjectNotFound
+		}
+		dir, info = parent, parentInfo
+	}
+}
+
+// LoadAzureYaml reads an azd project file. path may be the file itself or
+// a project directory containing azure.yaml or azure.yml.
+//
+// Example:
+//
+//	root, err := azdextutil.FindProjectRoot(".")
+//	if err != nil {
+//	    return err
+//	}
+//	project, err := azdextutil.LoadAzureYaml(root)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, svc := range project.ServiceList() {
+//	    fmt.Println(svc.Name, project.ServiceDir(svc.Name))
+//	}
+func LoadAzureYaml(path string) (*AzureYaml, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	if isDir(abs) {
+		file := findAzureYaml(abs)
+		if file == "" {
+			return nil, fmt.Errorf("no azure.yaml in %s: %w", abs, os.ErrNotExist)
+		}
+		abs = file
+	}
+
+	// #nosec G304 -- path is the caller's project file
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read azure.yaml: %w", err)
+	}
+
+	var project AzureYaml
+	if err := yaml.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", abs, err)
+	}
+	project.Path = abs
+	for name, svc := range project.Services {
+		svc.Name = name
+		project.Services[name] = svc
+	}
+	return &project, nil
+}
+
+// Dir returns the project root, the directory containing the loaded file.
+func (p *AzureYaml) Dir() string {
+	return filepath.Dir(p.Path)
+}
+
+// ServiceNames returns the service names, sorted.
+func (p *AzureYaml) ServiceNames() []string {
+	names := make([]string, 0, len(p.Services))
+	for name := range p.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServiceList returns the services sorted by name.
+func (p *AzureYaml) ServiceList() []AzureYamlService {
+	services := make([]AzureYamlService, 0, len(p.Services))
+	for _, name := range p.ServiceNames() {
+		services = append(services, p.Services[name])
+	}
+	return services
+}
+
+// ServiceDir returns the absolute directory of the named service's project,
+// or "" if the service does not exist or has no project directory.
+func (p *AzureYaml) ServiceDir(name string) string {
+	svc, ok := p.Services[name]
+	if !ok || svc.Project == "" {
+		return ""
+	}
+	if filepath.IsAbs(svc.Project) {
+		return filepath.Clean(svc.Project)
+	}
+	return filepath.Join(p.Dir(), filepath.FromSlash(svc.Project))
+}
+
+// findAzureYaml returns the path of the project file in dir, or "".
+func findAzureYaml(dir string) string {
+	for _, name := range azureYamlNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,210 @@
+package azdextutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func writeProjectFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// resolvedTempDir returns a temp dir with symlinks resolved, since
+// FindProjectRoot returns resolved paths (e.g. /private/var on macOS).
+func resolvedTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectUserHomeDir = func() (string, error) { return "", errors.New("no home") }
+	t.Cleanup(func() { projectUserHomeDir = os.UserHomeDir })
+	return dir
+}
+
+func TestFindProjectRoot(t *testing.T) {
+	root := resolvedTempDir(t)
+	writeProjectFile(t, filepath.Join(root, "azure.yaml"), "name: demo\n")
+	nested := filepath.Join(root, "src", "api", "handlers")
+	if err := os.MkdirAll(nested, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, start := range []string{root, nested} {
+		got, err := FindProjectRoot(start)
+		if err != nil || got != root {
+			t.Errorf("FindProjectRoot(%q) = %q, %v; want %q", start, got, err, root)
+		}
+	}
+}
+
+func TestFindProjectRoot_Markers(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(root string)
+	}{
+		{"azure.yml", func(root string) { writeProjectFile(t, filepath.Join(root, "azure.yml"), "name: demo\n") }},
+		{".azure directory", func(root string) { writeProjectFile(t, filepath.Join(root, ".azure", "config.json"), "{}") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := resolvedTempDir(t)
+			tt.setup(root)
+			start := filepath.Join(root, "sub")
+			if err := os.Mkdir(start, 0o750); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := FindProjectRoot(start); err != nil || got != root {
+				t.Errorf("FindProjectRoot() = %q, %v; want %q", got, err, root)
+			}
+		})
+	}
+}
+
+func TestFindProjectRoot_NearestWins(t *testing.T) {
+	root := resolvedTempDir(t)
+	writeProjectFile(t, filepath.Join(root, "azure.yaml"), "name: outer\n")
+	inner := filepath.Join(root, "samples", "inner")
+	writeProjectFile(t, filepath.Join(inner, "azure.yaml"), "name: inner\n")
+
+	if got, err := FindProjectRoot(inner); err != nil || got != inner {
+		t.Errorf("FindProjectRoot() = %q, %v; want %q", got, err, inner)
+	}
+}
+
+func TestFindProjectRoot_SkipsHomeAzureDir(t *testing.T) {
+	home := resolvedTempDir(t)
+	projectUserHomeDir = func() (string, error) { return home, nil }
+	writeProjectFile(t, filepath.Join(home, ".azure", "azureProfile.json"), "{}")
+	start := filepath.Join(home, "code")
+	if err := os.Mkdir(start, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := FindProjectRoot(start); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("expected ErrProjectNotFound, got %v", err)
+	}
+}
+
+func TestFindProjectRoot_Symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	root := resolvedTempDir(t)
+	writeProjectFile(t, filepath.Join(root, "project", "azure.yaml"), "name: demo\n")
+	service := filepath.Join(root, "project", "src", "web")
+	if err := os.MkdirAll(service, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(service, link); err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(root, "project")
+	if got, err := FindProjectRoot(link); err != nil || got != want {
+		t.Errorf("FindProjectRoot(%q) = %q, %v; want %q", link, got, err, want)
+	}
+}
+
+func TestFindProjectRoot_Errors(t *testing.T) {
+	root := resolvedTempDir(t)
+	file := filepath.Join(root, "file.txt")
+	writeProjectFile(t, file, "x")
+
+	if _, err := FindProjectRoot(filepath.Join(root, "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+	if _, err := FindProjectRoot(file); err == nil {
+		t.Error("expected error for a file")
+	}
+}
+
+const testAzureYaml = `name: todo
+metadata:
+  template: todo-python-mongo@0.0.1
+infra:
+  provider: bicep
+  path: infra
+services:
+  web:
+    project: ./src/web
+    language: ts
+    host: containerapp
+  api:
+    project: src/api
+    language: python
+    host: appservice
+  cache:
+    image: redis:7
+    host: containerapp
+`
+
+func TestLoadAzureYaml(t *testing.T) {
+	root := resolvedTempDir(t)
+	writeProjectFile(t, filepath.Join(root, "azure.yaml"), testAzureYaml)
+
+	for _, path := range []string{root, filepath.Join(root, "azure.yaml")} {
+		project, err := LoadAzureYaml(path)
+		if err != nil {
+			t.Fatalf("LoadAzureYaml(%q): %v", path, err)
+		}
+		if project.Name != "todo" || project.Metadata.Template != "todo-python-mongo@0.0.1" || project.Infra.Provider != "bicep" {
+			t.Errorf("unexpected project: %+v", project)
+		}
+		if project.Dir() != root {
+			t.Errorf("Dir() = %q, want %q", project.Dir(), root)
+		}
+		if got, want := project.ServiceNames(), []string{"api", "cache", "web"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ServiceNames() = %v, want %v", got, want)
+		}
+		list := project.ServiceList()
+		if len(list) != 3 || list[0].Name != "api" || list[0].Language != "python" || list[1].Image != "redis:7" {
+			t.Errorf("ServiceList() = %+v", list)
+		}
+		if got, want := project.ServiceDir("web"), filepath.Join(root, "src", "web"); got != want {
+			t.Errorf("ServiceDir(web) = %q, want %q", got, want)
+		}
+		if got := project.ServiceDir("cache"); got != "" {
+			t.Errorf("ServiceDir(cache) = %q, want empty", got)
+		}
+		if got := project.ServiceDir("missing"); got != "" {
+			t.Errorf("ServiceDir(missing) = %q, want empty", got)
+		}
+	}
+}
+
+func TestLoadAzureYaml_Errors(t *testing.T) {
+	root := resolvedTempDir(t)
+	if _, err := LoadAzureYaml(root); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist for directory without azure.yaml, got %v", err)
+	}
+
+	writeProjectFile(t, filepath.Join(root, "azure.yaml"), "services: [unclosed\n")
+	if _, err := LoadAzureYaml(root); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestLoadAzureYaml_NoServices(t *testing.T) {
+	root := resolvedTempDir(t)
+	writeProjectFile(t, filepath.Join(root, "azure.yml"), "name: empty\n")
+
+	project, err := LoadAzureYaml(root)
+	if err != nil {
+		t.Fatalf("LoadAzureYaml: %v", err)
+	}
+	if names := project.ServiceNames(); len(names) != 0 {
+		t.Errorf("ServiceNames() = %v, want none", names)
+	}
+}
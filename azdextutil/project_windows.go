@@ -0,0 +1,11 @@
+//go:build windows
+
+package azdextutil
+
+import "os"
+
+// sameDevice reports whether a and b are on the same filesystem. Windows
+// volumes have their own roots, so reaching a root is the only boundary.
+func sameDevice(_, _ os.FileInfo) bool {
+	return true
+}
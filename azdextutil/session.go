@@ -0,0 +1,244 @@
+package azdextutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jongio/azd-core/logutil"
+)
+
+// Session defaults.
+const (
+	DefaultHeartbeatInterval    = 10 * time.Second
+	DefaultMaxReconnectAttempts = 5
+	DefaultReconnectDelay       = 250 * time.Millisecond
+	maxReconnectDelay           = 10 * time.Second
+)
+
+var (
+	// ErrHeartbeatTimeout is reported when nothing is received from the peer
+	// within SessionOptions.HeartbeatTimeout.
+	ErrHeartbeatTimeout = errors.New("heartbeat timeout")
+	// ErrNotConnected is returned by Session.Send while no transport is
+	// connected, for example during reconnection.
+	ErrNotConnected = errors.New("not connected")
+)
+
+// Dialer opens a new Transport. Sessions call it to connect and again for
+// every reconnection attempt.
+type Dialer func(ctx context.Context) (Transport, error)
+
+// Handler processes a message received from the host. A non-nil reply is
+// sent back with the request ID unless it sets its own; a non-nil error is
+// sent back as a MessageTypeError reply.
+type Handler func(ctx context.Context, msg Message) (*Message, error)
+
+// SessionOptions configures a Session.
+type SessionOptions struct {
+	// HeartbeatInterval is how often a heartbeat is sent. Defaults to
+	// DefaultHeartbeatInterval; a negative value disables heartbeats and the
+	// heartbeat timeout.
+	HeartbeatInterval time.Duration
+	// HeartbeatTimeout is how long the session waits without receiving any
+	// message before treating the connection as dead. Defaults to three
+	// heartbeat intervals.
+	HeartbeatTimeout time.Duration
+	// MaxReconnectAttempts bounds consecutive failed reconnections before Run
+	// gives up. Defaults to DefaultMaxReconnectAttempts; a negative value
+	// disables reconnection.
+	MaxReconnectAttempts int
+	// ReconnectDelay is the delay before the first reconnection attempt. It
+	// doubles on each consecutive failure, up to 10 seconds. Defaults to
+	// DefaultReconnectDelay.
+	ReconnectDelay time.Duration
+}
+
+// Session runs the message loop of a listen command over a Transport: it
+// dispatches incoming messages to a Handler, sends heartbeats, detects dead
+// connections, and reconnects with exponential backoff.
+type Session struct {
+	dial    Dialer
+	handler Handler
+	opts    SessionOptions
+	log     *logutil.ComponentLogger
+
+	mu      sync.RWMutex
+	current Transport
+}
+
+// NewSession creates a Session that connects with dial and dispatches
+// messages to handler.
+//
+// Example:
+//
+//	session := azdextutil.NewSession(func(ctx context.Context) (azdextutil.Transport, error) {
+//	    return newHostTransport(ctx) // adapts azd's extension channel
+//	}, handleMessage, azdextutil.SessionOptions{})
+//	return session.Run(cmd.Context())
+func NewSession(dial Dialer, handler Handler, opts SessionOptions) *Session {
+	if opts.HeartbeatInterval == 0 {
+		opts.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if opts.HeartbeatTimeout <= 0 && opts.HeartbeatInterval > 0 {
+		opts.HeartbeatTimeout = 3 * opts.HeartbeatInterval
+	}
+	if opts.MaxReconnectAttempts == 0 {
+		opts.MaxReconnectAttempts = DefaultMaxReconnectAttempts
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = DefaultReconnectDelay
+	}
+	return &Session{
+		dial:    dial,
+		handler: handler,
+		opts:    opts,
+		log:     logutil.NewLogger("transport"),
+	}
+}
+
+// Run connects and serves messages until ctx is canceled, returning nil, or
+// until reconnection is disabled or exhausted, returning the last error.
+func (s *Session) Run(ctx context.Context) error {
+	attempt := 0
+	for {
+		t, err := s.dial(ctx)
+		if err == nil {
+			attempt = 0
+			err = s.serve(ctx, t)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if s.opts.MaxReconnectAttempts < 0 || attempt >= s.opts.MaxReconnectAttempts {
+			return fmt.Errorf("transport disconnected: %w", err)
+		}
+
+		delay := s.reconnectDelay(attempt)
+		attempt++
+		s.log.Warn("transport disconnected, reconnecting", "attempt", attempt, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Send sends msg over the current connection.
+func (s *Session) Send(ctx context.Context, msg Message) error {
+	s.mu.RLock()
+	t := s.current
+	s.mu.RUnlock()
+	if t == nil {
+		return ErrNotConnected
+	}
+	return t.Send(ctx, msg)
+}
+
+// reconnectDelay returns the backoff before reconnection attempt n (0-based).
+func (s *Session) reconnectDelay(n int) time.Duration {
+	delay := s.opts.ReconnectDelay
+	for i := 0; i < n && delay < maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxReconnectDelay)
+}
+
+func (s *Session) setCurrent(t Transport) {
+	s.mu.Lock()
+	s.current = t
+	s.mu.Unlock()
+}
+
+// serve runs the receive and heartbeat loops on t until either fails or ctx
+// is done, then closes t.
+func (s *Session) serve(ctx context.Context, t Transport) error {
+	s.setCurrent(t)
+	defer s.setCurrent(nil)
+
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(ctx)
+	defer wg.Wait()
+	defer func() { _ = t.Close() }()
+	defer cancel()
+
+	var lastSeen atomic.Int64
+	lastSeen.Store(time.Now().UnixNano())
+	errCh := make(chan error, 2)
+
+	if s.opts.HeartbeatInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errCh <- s.heartbeat(ctx, t, &lastSeen)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errCh <- s.receive(ctx, t, &lastSeen)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errCh:
+		return err
+	}
+}
+
+// heartbeat sends heartbeats and fails once the peer has been silent for
+// longer than the heartbeat timeout.
+func (s *Session) heartbeat(ctx context.Context, t Transport, lastSeen *atomic.Int64) error {
+	ticker := time.NewTicker(s.opts.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if time.Since(time.Unix(0, lastSeen.Load())) > s.opts.HeartbeatTimeout {
+			return ErrHeartbeatTimeout
+		}
+		if err := t.Send(ctx, Message{Type: MessageTypeHeartbeat}); err != nil {
+			return fmt.Errorf("failed to send heartbeat: %w", err)
+		}
+	}
+}
+
+// receive dispatches incoming messages to the handler and sends replies.
+func (s *Session) receive(ctx context.Context, t Transport, lastSeen *atomic.Int64) error {
+	for {
+		msg, err := t.Receive(ctx)
+		if err != nil {
+			return err
+		}
+		lastSeen.Store(time.Now().UnixNano())
+		if msg.Type == MessageTypeHeartbeat {
+			continue
+		}
+
+		reply, err := s.handler(ctx, msg)
+		if err != nil {
+			reply = &Message{Type: MessageTypeError, Error: err.Error()}
+		}
+		if reply == nil {
+			continue
+		}
+		if reply.ID == "" {
+			reply.ID = msg.ID
+		}
+		if err := t.Send(ctx, *reply); err != nil {
+			return fmt.Errorf("failed to send reply: %w", err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package azdextutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pairDialer returns a Dialer that hands out the extension side of a new
+// in-memory transport on each call and publishes the host side on hosts.
+func pairDialer(hosts chan<- Transport, dials *atomic.Int32) Dialer {
+	return func(context.Context) (Transport, error) {
+		dials.Add(1)
+		ext, host := NewInMemoryTransport()
+		hosts <- host
+		return ext, nil
+	}
+}
+
+func nextHost(t *testing.T, hosts <-chan Transport) Transport {
+	t.Helper()
+	select {
+	case h := <-hosts:
+		return h
+	case <-time.After(5 * time.Second):
+		t.Fatal("session did not connect")
+		return nil
+	}
+}
+
+func TestSession_HandlerReplies(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hosts := make(chan Transport, 1)
+	var dials atomic.Int32
+	handler := func(_ context.Context, msg Message) (*Message, error) {
+		switch msg.Type {
+		case "ping":
+			return &Message{Type: "pong"}, nil
+		case "fail":
+			return nil, errors.New("handler failed")
+		default:
+			return nil, nil
+		}
+	}
+	session := NewSession(pairDialer(hosts, &dials), handler, SessionOptions{HeartbeatInterval: -1})
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(ctx) }()
+	host := nextHost(t, hosts)
+
+	_ = host.Send(ctx, Message{Type: "ignored", ID: "0"})
+	_ = host.Send(ctx, Message{Type: "ping", ID: "1"})
+	reply, err := host.Receive(ctx)
+	if err != nil || reply.Type != "pong" || reply.ID != "1" {
+		t.Errorf("reply = %+v, %v, want pong with ID 1", reply, err)
+	}
+
+	_ = host.Send(ctx, Message{Type: "fail", ID: "2"})
+	reply, err = host.Receive(ctx)
+	if err != nil || reply.Type != MessageTypeError || reply.ID != "2" || reply.Error != "handler failed" {
+		t.Errorf("reply = %+v, %v, want error reply", reply, err)
+	}
+
+	if err := session.Send(ctx, Message{Type: "notify"}); err != nil {
+		t.Errorf("Send() error = %v", err)
+	}
+	if msg, err := host.Receive(ctx); err != nil || msg.Type != "notify" {
+		t.Errorf("host received %+v, %v, want notify", msg, err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() after cancel = %v, want nil", err)
+	}
+	if err := session.Send(context.Background(), Message{Type: "late"}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Send() after Run = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestSession_SendsHeartbeats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hosts := make(chan Transport, 1)
+	var dials atomic.Int32
+	session := NewSession(pairDialer(hosts, &dials), func(context.Context, Message) (*Message, error) { return nil, nil },
+		SessionOptions{HeartbeatInterval: 10 * time.Millisecond, HeartbeatTimeout: time.Second})
+	go func() { _ = session.Run(ctx) }()
+
+	host := nextHost(t, hosts)
+	msg, err := host.Receive(ctx)
+	if err != nil || msg.Type != MessageTypeHeartbeat {
+		t.Errorf("host received %+v, %v, want heartbeat", msg, err)
+	}
+}
+
+func TestSession_ReconnectsAfterDisconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hosts := make(chan Transport, 1)
+	var dials atomic.Int32
+	echo := func(_ context.Context, msg Message) (*Message, error) { return &msg, nil }
+	session := NewSession(pairDialer(hosts, &dials), echo,
+		SessionOptions{HeartbeatInterval: -1, ReconnectDelay: time.Millisecond})
+	go func() { _ = session.Run(ctx) }()
+
+	first := nextHost(t, hosts)
+	_ = first.Close()
+
+	second := nextHost(t, hosts)
+	defer second.Close()
+	_ = second.Send(ctx, Message{Type: "echo", ID: "x"})
+	if msg, err := second.Receive(ctx); err != nil || msg.ID != "x" {
+		t.Errorf("reply after reconnect = %+v, %v", msg, err)
+	}
+	if got := dials.Load(); got != 2 {
+		t.Errorf("dials = %d, want 2", got)
+	}
+}
+
+func TestSession_HeartbeatTimeoutReconnects(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hosts := make(chan Transport, 1)
+	var dials atomic.Int32
+	session := NewSession(pairDialer(hosts, &dials), func(context.Context, Message) (*Message, error) { return nil, nil },
+		SessionOptions{HeartbeatInterval: 5 * time.Millisecond, HeartbeatTimeout: 30 * time.Millisecond, ReconnectDelay: time.Millisecond})
+	go func() { _ = session.Run(ctx) }()
+
+	// The first host stays silent, so the session must give up on it.
+	first := nextHost(t, hosts)
+	defer first.Close()
+	go func() {
+		for {
+			if _, err := first.Receive(ctx); err != nil {
+				return
+			}
+		}
+	}()
+
+	second := nextHost(t, hosts)
+	defer second.Close()
+	if got := dials.Load(); got < 2 {
+		t.Errorf("dials = %d, want at least 2", got)
+	}
+}
+
+func TestSession_GivesUpAfterMaxAttempts(t *testing.T) {
+	var dials atomic.Int32
+	dialErr := errors.New("host unavailable")
+	dial := func(context.Context) (Transport, error) {
+		dials.Add(1)
+		return nil, dialErr
+	}
+	session := NewSession(dial, nil, SessionOptions{MaxReconnectAttempts: 2, ReconnectDelay: time.Millisecond})
+
+	err := session.Run(context.Background())
+	if !errors.Is(err, dialErr) {
+		t.Errorf("Run() error = %v, want %v", err, dialErr)
+	}
+	if got := dials.Load(); got != 3 {
+		t.Errorf("dials = %d, want 3 (initial + 2 reconnects)", got)
+	}
+}
+
+func TestSession_ReconnectDisabled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hosts := make(chan Transport, 1)
+	var dials atomic.Int32
+	session := NewSession(pairDialer(hosts, &dials), nil, SessionOptions{HeartbeatInterval: -1, MaxReconnectAttempts: -1})
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(ctx) }()
+	_ = nextHost(t, hosts).Close()
+
+	if err := <-done; !errors.Is(err, ErrTransportClosed) {
+		t.Errorf("Run() error = %v, want ErrTransportClosed", err)
+	}
+	if got := dials.Load(); got != 1 {
+		t.Errorf("dials = %d, want 1", got)
+	}
+}
+
+func TestSessionReconnectDelay(t *testing.T) {
+	s := NewSession(nil, nil, SessionOptions{ReconnectDelay: time.Second})
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{4, maxReconnectDelay},
+		{50, maxReconnectDelay},
+	}
+	for _, tt := range tests {
+		if got := s.reconnectDelay(tt.attempt); got != tt.want {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestNewSessionDefaults(t *testing.T) {
+	s := NewSession(nil, nil, SessionOptions{})
+	if s.opts.HeartbeatInterval != DefaultHeartbeatInterval {
+		t.Errorf("HeartbeatInterval = %v", s.opts.HeartbeatInterval)
+	}
+	if s.opts.HeartbeatTimeout != 3*DefaultHeartbeatInterval {
+		t.Errorf("HeartbeatTimeout = %v", s.opts.HeartbeatTimeout)
+	}
+	if s.opts.MaxReconnectAttempts != DefaultMaxReconnectAttempts {
+		t.Errorf("MaxReconnectAttempts = %d", s.opts.MaxReconnectAttempts)
+	}
+	if s.opts.ReconnectDelay != DefaultReconnectDelay {
+		t.Errorf("ReconnectDelay = %v", s.opts.ReconnectDelay)
+	}
+}
@@ -0,0 +1,219 @@
+package azdextutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EnvCollectTelemetry is the azd telemetry opt-out variable. Setting it to
+// "no" (or "false", "0", "off") disables telemetry collection.
+const EnvCollectTelemetry = "AZURE_DEV_COLLECT_TELEMETRY"
+
+// DefaultTelemetryBatchSize is the number of events buffered before a flush.
+const DefaultTelemetryBatchSize = 20
+
+// TelemetryCloseTimeout bounds how long RunMain waits for pending telemetry
+// to be sent on exit.
+const TelemetryCloseTimeout = 2 * time.Second
+
+// TelemetryEvent is an anonymous usage record. It intentionally carries no
+// arguments, paths, or error messages.
+type TelemetryEvent struct {
+	ExtensionID string        `json:"extensionId"`
+	Version     string        `json:"version,omitempty"`
+	Command     string        `json:"command"`
+	Duration    time.Duration `json:"-"`
+	Success     bool          `json:"success"`
+	ErrorClass  string        `json:"errorClass,omitempty"`
+	TraceID     string        `json:"traceId,omitempty"`
+	Timestamp   time.Time     `json:"timestamp"`
+}
+
+// MarshalJSON encodes Duration in milliseconds.
+func (e TelemetryEvent) MarshalJSON() ([]byte, error) {
+	type alias TelemetryEvent
+	return json.Marshal(struct {
+		alias
+		Duration int64 `json:"durationMs"`
+	}{alias: alias(e), Duration: e.Duration.Milliseconds()})
+}
+
+// TelemetrySink receives batches of telemetry events.
+type TelemetrySink interface {
+	Send(ctx context.Context, events []TelemetryEvent) error
+}
+
+// WriterSink is a TelemetrySink that writes events as JSON lines to W.
+type WriterSink struct {
+	W io.Writer
+}
+
+// Send writes each event as a single JSON line.
+func (s WriterSink) Send(_ context.Context, events []TelemetryEvent) error {
+	enc := json.NewEncoder(s.W)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Telemetry batches anonymous usage events for an extension and delivers
+// them to a TelemetrySink. It is a no-op when the user has opted out via
+// EnvCollectTelemetry. Pass it in MainOptions.Telemetry so RunMain flushes
+// pending events on exit, or call Close before exiting. Telemetry is safe
+// for concurrent use.
+type Telemetry struct {
+	extensionID string
+	version     string
+	sink        TelemetrySink
+	batchSize   int
+	enabled     bool
+
+	mu      sync.Mutex
+	pending []TelemetryEvent
+	closed  bool
+}
+
+// NewTelemetry creates a Telemetry for extensionID that sends to sink.
+// A nil sink disables collection.
+//
+// Example:
+//
+//	tel := azdextutil.NewTelemetry("jongio.azd.exec", info.Version, sink)
+//	os.Exit(azdextutil.RunMain(azdextutil.MainOptions{Telemetry: tel}, func(ctx context.Context) error {
+//	    return tel.Track(ctx, "run", func(ctx context.Context) error { return run(ctx) })
+//	}))
+func NewTelemetry(extensionID, version string, sink TelemetrySink) *Telemetry {
+	return &Telemetry{
+		extensionID: extensionID,
+		version:     version,
+		sink:        sink,
+		batchSize:   DefaultTelemetryBatchSize,
+		enabled:     sink != nil && TelemetryEnabled(),
+	}
+}
+
+// TelemetryEnabled reports whether the user allows telemetry collection.
+func TelemetryEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvCollectTelemetry))) {
+	case "no", "false", "0", "off":
+		return false
+	default:
+		return true
+	}
+}
+
+// Enabled reports whether events are being collected.
+func (t *Telemetry) Enabled() bool {
+	return t.enabled
+}
+
+// SetBatchSize sets how many events are buffered before an automatic flush.
+// Values below 1 are ignored.
+func (t *Telemetry) SetBatchSize(n int) {
+	if n < 1 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.batchSize = n
+}
+
+// Record queues an event for command. The trace ID of the active span in ctx,
+// if any, is attached so events correlate with distributed traces.
+func (t *Telemetry) Record(ctx context.Context, command string, duration time.Duration, err error) {
+	if !t.enabled {
+		return
+	}
+
+	event := TelemetryEvent{
+		ExtensionID: t.extensionID,
+		Version:     t.version,
+		Command:     command,
+		Duration:    duration,
+		Success:     err == nil,
+		ErrorClass:  ErrorClass(err),
+		Timestamp:   time.Now().UTC(),
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		event.TraceID = sc.TraceID().String()
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.pending = append(t.pending, event)
+	full := len(t.pending) >= t.batchSize
+	t.mu.Unlock()
+
+	if full {
+		_ = t.Flush(ctx)
+	}
+}
+
+// Track runs fn and records an event for command with its duration and outcome.
+// It returns fn's error unchanged.
+func (t *Telemetry) Track(ctx context.Context, command string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	t.Record(ctx, command, time.Since(start), err)
+	return err
+}
+
+// Flush sends all pending events to the sink.
+func (t *Telemetry) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := t.sink.Send(ctx, batch); err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	return nil
+}
+
+// Close flushes pending events and stops further collection.
+func (t *Telemetry) Close(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+	return t.Flush(ctx)
+}
+
+// ErrorClass returns a coarse, non-identifying classification of err suitable
+// for telemetry: "" for nil, "canceled", "timeout", or the Go type of the
+// innermost wrapped error.
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = next
+	}
+}
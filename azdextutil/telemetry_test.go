@@ -0,0 +1,170 @@
+package azdextutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]TelemetryEvent
+}
+
+func (s *recordingSink) Send(_ context.Context, events []TelemetryEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *recordingSink) events() []TelemetryEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var all []TelemetryEvent
+	for _, b := range s.batches {
+		all = append(all, b...)
+	}
+	return all
+}
+
+func TestTelemetryEnabled(t *testing.T) {
+	for value, want := range map[string]bool{"": true, "yes": true, "no": false, "FALSE": false, "0": false, "off": false} {
+		t.Setenv(EnvCollectTelemetry, value)
+		if got := TelemetryEnabled(); got != want {
+			t.Errorf("TelemetryEnabled() with %q = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestTelemetry_TrackAndClose(t *testing.T) {
+	t.Setenv(EnvCollectTelemetry, "")
+	sink := &recordingSink{}
+	tel := NewTelemetry("test.ext", "1.2.3", sink)
+
+	wantErr := fmt.Errorf("wrapped: %w", fs.ErrNotExist)
+	if err := tel.Track(context.Background(), "run", func(context.Context) error { return wantErr }); err != wantErr {
+		t.Errorf("Track returned %v, want %v", err, wantErr)
+	}
+	_ = tel.Track(context.Background(), "list", func(context.Context) error { return nil })
+
+	if got := len(sink.events()); got != 0 {
+		t.Fatalf("expected events to be batched, got %d sent", got)
+	}
+	if err := tel.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	events := sink.events()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Command != "run" || events[0].Success || events[0].ErrorClass != "*errors.errorString" {
+		t.Errorf("unexpected failure event: %+v", events[0])
+	}
+	if events[1].Command != "list" || !events[1].Success || events[1].ErrorClass != "" {
+		t.Errorf("unexpected success event: %+v", events[1])
+	}
+	if events[0].ExtensionID != "test.ext" || events[0].Version != "1.2.3" {
+		t.Errorf("missing extension metadata: %+v", events[0])
+	}
+
+	tel.Record(context.Background(), "after-close", 0, nil)
+	if err := tel.Flush(context.Background()); err != nil || len(sink.events()) != 2 {
+		t.Error("expected events after Close to be dropped")
+	}
+}
+
+func TestTelemetry_BatchFlush(t *testing.T) {
+	t.Setenv(EnvCollectTelemetry, "")
+	sink := &recordingSink{}
+	tel := NewTelemetry("test.ext", "", sink)
+	tel.SetBatchSize(2)
+
+	for i := 0; i < 3; i++ {
+		tel.Record(context.Background(), "cmd", time.Millisecond, nil)
+	}
+	if len(sink.batches) != 1 || len(sink.batches[0]) != 2 {
+		t.Errorf("expected one automatic batch of 2, got %v", sink.batches)
+	}
+}
+
+func TestTelemetry_OptOut(t *testing.T) {
+	t.Setenv(EnvCollectTelemetry, "no")
+	sink := &recordingSink{}
+	tel := NewTelemetry("test.ext", "", sink)
+	if tel.Enabled() {
+		t.Error("expected telemetry to be disabled")
+	}
+	tel.Record(context.Background(), "cmd", 0, nil)
+	_ = tel.Close(context.Background())
+	if len(sink.events()) != 0 {
+		t.Error("expected no events when opted out")
+	}
+}
+
+func TestTelemetry_TraceID(t *testing.T) {
+	t.Setenv(EnvCollectTelemetry, "")
+	sink := &recordingSink{}
+	tel := NewTelemetry("test.ext", "", sink)
+
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	tel.Record(ctx, "cmd", 0, nil)
+	_ = tel.Close(context.Background())
+	if got := sink.events()[0].TraceID; got != traceID.String() {
+		t.Errorf("TraceID = %q, want %q", got, traceID.String())
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	event := TelemetryEvent{ExtensionID: "test.ext", Command: "run", Duration: 1500 * time.Millisecond, Success: true}
+	if err := (WriterSink{W: &buf}).Send(context.Background(), []TelemetryEvent{event, event}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %d", len(lines))
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded["durationMs"] != float64(1500) || decoded["command"] != "run" {
+		t.Errorf("unexpected encoding: %s", lines[0])
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{context.Canceled, "canceled"},
+		{fmt.Errorf("op: %w", context.DeadlineExceeded), "timeout"},
+		{fmt.Errorf("decode: %w", &json.SyntaxError{}), "*json.SyntaxError"},
+		{errors.New("boom"), "*errors.errorString"},
+	}
+	for _, tt := range tests {
+		if got := ErrorClass(tt.err); got != tt.want {
+			t.Errorf("ErrorClass(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
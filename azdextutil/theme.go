@@ -0,0 +1,47 @@
+package azdextutil
+
+import (
+	"github.com/jongio/azd-core/cliout"
+)
+
+// ThemeConfigName is the ConfigStore document holding the user's output theme.
+const ThemeConfigName = "theme"
+
+// SaveTheme persists theme as the user's output theme for the extension.
+// Built-in themes are stored by name so later improvements to them apply;
+// custom themes are stored in full.
+//
+// Example:
+//
+//	theme, _ := cliout.BuiltinTheme(cliout.ThemeHighContrast)
+//	if err := azdextutil.SaveTheme(store, theme); err != nil {
+//	    return err
+//	}
+func SaveTheme(store *ConfigStore, theme cliout.Theme) error {
+	if builtin, ok := cliout.BuiltinTheme(theme.Name); ok && builtin == theme {
+		theme = cliout.Theme{Name: theme.Name}
+	}
+	return store.Save(ThemeConfigName, theme)
+}
+
+// LoadTheme reads the user's saved theme and activates it with
+// cliout.SetTheme. It reports false, leaving the current theme unchanged,
+// when no theme has been saved. Call it during startup, before printing.
+func LoadTheme(store *ConfigStore) (bool, error) {
+	var theme cliout.Theme
+	found, err := store.Load(ThemeConfigName, &theme)
+	if err != nil || !found {
+		return false, err
+	}
+	if builtin, ok := cliout.BuiltinTheme(theme.Name); ok && isNameOnly(theme) {
+		theme = builtin
+	}
+	cliout.SetTheme(theme)
+	return true, nil
+}
+
+// isNameOnly reports whether theme sets nothing but its name, as written by
+// SaveTheme for built-in themes.
+func isNameOnly(theme cliout.Theme) bool {
+	return theme == cliout.Theme{Name: theme.Name}
+}
@@ -0,0 +1,57 @@
+package azdextutil
+
+import (
+	"testing"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+func TestSaveLoadTheme(t *testing.T) {
+	defer cliout.SetTheme(cliout.DefaultTheme())
+	store := newTestStore(t, 1)
+
+	found, err := LoadTheme(store)
+	if err != nil || found {
+		t.Fatalf("LoadTheme() on empty store = %v, %v; want false, nil", found, err)
+	}
+
+	highContrast, _ := cliout.BuiltinTheme(cliout.ThemeHighContrast)
+	if err := SaveTheme(store, highContrast); err != nil {
+		t.Fatalf("SaveTheme() error = %v", err)
+	}
+	var stored cliout.Theme
+	if _, err := store.Load(ThemeConfigName, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != (cliout.Theme{Name: cliout.ThemeHighContrast}) {
+		t.Errorf("built-in theme stored as %+v, want name only", stored)
+	}
+
+	cliout.SetTheme(cliout.DefaultTheme())
+	found, err = LoadTheme(store)
+	if err != nil || !found {
+		t.Fatalf("LoadTheme() = %v, %v", found, err)
+	}
+	if got := cliout.CurrentTheme(); got != highContrast {
+		t.Errorf("CurrentTheme() = %+v, want %+v", got, highContrast)
+	}
+}
+
+func TestSaveLoadCustomTheme(t *testing.T) {
+	defer cliout.SetTheme(cliout.DefaultTheme())
+	store := newTestStore(t, 1)
+
+	custom := cliout.DefaultTheme()
+	custom.Name = "contoso"
+	custom.Accent = cliout.Magenta
+	if err := SaveTheme(store, custom); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadTheme(store); err != nil {
+		t.Fatal(err)
+	}
+	if got := cliout.CurrentTheme(); got != custom {
+		t.Errorf("CurrentTheme() = %+v, want %+v", got, custom)
+	}
+}
@@ -0,0 +1,125 @@
+package azdextutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Message types with built-in meaning. Other types are passed to the Handler.
+const (
+	// MessageTypeHeartbeat keeps a connection alive. Sessions send them
+	// periodically and never pass them to the Handler.
+	MessageTypeHeartbeat = "heartbeat"
+	// MessageTypeError is the reply sent when a Handler returns an error.
+	MessageTypeError = "error"
+)
+
+// ErrTransportClosed is returned by Send and Receive once the transport or
+// its peer has closed the connection.
+var ErrTransportClosed = errors.New("transport closed")
+
+// inMemoryBuffer is how many messages an in-memory transport holds before
+// Send blocks.
+const inMemoryBuffer = 16
+
+// Message is one unit of communication between an extension and the azd host.
+type Message struct {
+	// Type identifies how the message is handled, e.g. "event" or "heartbeat".
+	Type string `json:"type"`
+	// ID correlates a reply with its request. Replies inherit the request ID.
+	ID string `json:"id,omitempty"`
+	// Payload is the type-specific body.
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Error is set on MessageTypeError replies.
+	Error string `json:"error,omitempty"`
+}
+
+// Transport carries messages between an extension and its host.
+// Implementations must be safe for one concurrent Send and Receive.
+//
+// This package defines no wire format of its own: a Transport connected to
+// azd adapts the host's extension channel, and handlers written against
+// Transport can be unit tested with NewInMemoryTransport instead.
+type Transport interface {
+	// Send writes msg to the peer.
+	Send(ctx context.Context, msg Message) error
+	// Receive blocks until a message arrives, ctx is done, or the
+	// connection closes (ErrTransportClosed).
+	Receive(ctx context.Context) (Message, error)
+	// Close releases the connection and unblocks pending Receive calls.
+	Close() error
+}
+
+// NewInMemoryTransport returns two connected transports, one for each side
+// of a conversation, so handlers can be tested without a host:
+//
+//	ext, host := azdextutil.NewInMemoryTransport()
+//	go session.Run(ctx) // session dials ext
+//	_ = host.Send(ctx, azdextutil.Message{Type: "event", ID: "1"})
+//	reply, _ := host.Receive(ctx)
+func NewInMemoryTransport() (Transport, Transport) {
+	ab := make(chan Message, inMemoryBuffer)
+	ba := make(chan Message, inMemoryBuffer)
+	a := &memoryTransport{in: ba, out: ab, closed: make(chan struct{})}
+	b := &memoryTransport{in: ab, out: ba, closed: make(chan struct{})}
+	a.peerClosed, b.peerClosed = b.closed, a.closed
+	return a, b
+}
+
+// memoryTransport is one side of NewInMemoryTransport.
+type memoryTransport struct {
+	in         <-chan Message
+	out        chan<- Message
+	closed     chan struct{}
+	peerClosed <-chan struct{}
+	closeOnce  sync.Once
+}
+
+// Send implements Transport.
+func (t *memoryTransport) Send(ctx context.Context, msg Message) error {
+	select {
+	case <-t.closed:
+		return ErrTransportClosed
+	case <-t.peerClosed:
+		return ErrTransportClosed
+	default:
+	}
+	select {
+	case t.out <- msg:
+		return nil
+	case <-t.closed:
+		return ErrTransportClosed
+	case <-t.peerClosed:
+		return ErrTransportClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Receive implements Transport. Messages sent before the peer closed are
+// still delivered.
+func (t *memoryTransport) Receive(ctx context.Context) (Message, error) {
+	select {
+	case msg := <-t.in:
+		return msg, nil
+	case <-t.closed:
+		return Message{}, ErrTransportClosed
+	case <-t.peerClosed:
+		select {
+		case msg := <-t.in:
+			return msg, nil
+		default:
+		}
+		return Message{}, ErrTransportClosed
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+}
+
+// Close implements Transport.
+func (t *memoryTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return nil
+}
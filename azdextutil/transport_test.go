@@ -0,0 +1,73 @@
+package azdextutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInMemoryTransport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ext, host := NewInMemoryTransport()
+	defer ext.Close()
+
+	if err := host.Send(ctx, Message{Type: "event", ID: "1"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	msg, err := ext.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if msg.Type != "event" || msg.ID != "1" {
+		t.Errorf("Receive() = %+v", msg)
+	}
+
+	if err := host.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := ext.Receive(ctx); !errors.Is(err, ErrTransportClosed) {
+		t.Errorf("Receive() after peer close error = %v, want ErrTransportClosed", err)
+	}
+	if err := host.Send(ctx, Message{Type: "event"}); !errors.Is(err, ErrTransportClosed) {
+		t.Errorf("Send() after Close error = %v, want ErrTransportClosed", err)
+	}
+	if err := host.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestTransportReceiveContext(t *testing.T) {
+	ext, host := NewInMemoryTransport()
+	defer ext.Close()
+	defer host.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := ext.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Receive() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestInMemoryTransportDeliversBeforeClose(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ext, host := NewInMemoryTransport()
+	defer ext.Close()
+	_ = host.Send(ctx, Message{Type: "a"})
+	_ = host.Send(ctx, Message{Type: "b"})
+	_ = host.Close()
+
+	for _, want := range []string{"a", "b"} {
+		msg, err := ext.Receive(ctx)
+		if err != nil || msg.Type != want {
+			t.Fatalf("Receive() = %+v, %v, want type %q", msg, err, want)
+		}
+	}
+	if _, err := ext.Receive(ctx); !errors.Is(err, ErrTransportClosed) {
+		t.Errorf("Receive() after drained error = %v, want ErrTransportClosed", err)
+	}
+}
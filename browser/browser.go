@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jongio/azd-core/urlutil"
 	pkgbrowser "github.com/pkg/browser"
 )
 
@@ -69,6 +70,11 @@ type LaunchOptions struct {
 // Launch opens the specified URL in the browser determined by the target.
 // Returns an error if the launch fails, but this is not critical.
 // The function is non-blocking and launches the browser in a separate goroutine.
+//
+// The URL is checked with urlutil.Sanitize before launching: URLs longer than
+// urlutil.MaxURLLength or with embedded credentials (user:pass@host) are
+// rejected, and unsafe characters in the query and fragment are
+// percent-encoded, so nothing unexpected reaches the system launch command.
 func Launch(opts LaunchOptions) error {
 	if opts.Timeout == 0 {
 		opts.Timeout = 5 * time.Second
@@ -79,6 +85,12 @@ func Launch(opts LaunchOptions) error {
 		return fmt.Errorf("invalid URL scheme: URL must start with http:// or https://")
 	}
 
+	sanitized, err := urlutil.Sanitize(opts.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	opts.URL = sanitized
+
 	// Resolve the actual target
 	target := ResolveTarget(opts.Target)
 
@@ -97,7 +109,10 @@ func Launch(opts LaunchOptions) error {
 		select {
 		case err := <-done:
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "⚠️  Could not open browser automatically: %v\n", err)
+				// The launcher's error may echo the URL; the line below
+				// shows it in full for the user to open, so redact it here.
+				msg := strings.ReplaceAll(err.Error(), opts.URL, urlutil.RedactForLogging(opts.URL))
+				fmt.Fprintf(os.Stderr, "⚠️  Could not open browser automatically: %s\n", msg)
 				fmt.Fprintf(os.Stderr, "   Please open this URL manually: %s\n", opts.URL)
 			}
 		case <-time.After(opts.Timeout):
@@ -10,12 +10,16 @@
 //   - Non-blocking launch
 //   - Target options (default browser, system browser, none)
 //   - URL validation (http/https only, prevents file:// and javascript:)
+//   - Loopback preview server for local files and generated reports (ServeAndLaunch)
 //
 // # Security Considerations
 //
 // This package enforces strict URL validation to prevent security issues:
 //   - Only http:// and https:// schemes are allowed
 //   - URLs are validated before passing to shell commands
+//   - URLs longer than urlutil.MaxURLLength or with embedded credentials
+//     (user:pass@host) are rejected so they are never logged by the launcher
+//   - Unsafe characters in the query and fragment are percent-encoded
 //   - No user-controlled data is directly interpolated into commands
 //
 // # Cross-Platform Behavior
@@ -75,6 +79,22 @@
 //	fmt.Printf("Opening in %s...\n", browser.GetTargetDisplayName(target))
 //	// Output: Opening in default browser...
 //
+// # Previewing Local Content
+//
+// Because file:// URLs are rejected, local files such as generated reports are
+// opened through ServeAndLaunch, which serves a directory or in-memory document
+// on 127.0.0.1 and launches the browser to it. The server only answers GET and
+// HEAD requests addressed to the loopback host, does not follow symbolic links
+// out of the served directory, and shuts down after the TTL or when ctx is
+// canceled:
+//
+//	srv, err := browser.ServeAndLaunch(ctx, browser.Source{Content: html, Name: "report.html"},
+//	    browser.ServeOptions{TTL: 5 * time.Minute})
+//	if err != nil {
+//	    return err
+//	}
+//	<-srv.Done()
+//
 // # Error Handling
 //
 // The Launch function is non-blocking and returns immediately. Any errors during
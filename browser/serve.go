@@ -0,0 +1,233 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package browser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultServeTTL is how long ServeAndLaunch keeps serving when
+// ServeOptions.TTL is zero.
+const DefaultServeTTL = 10 * time.Minute
+
+// defaultContentName is the file name used for in-memory content when
+// Source.Name is empty.
+const defaultContentName = "index.html"
+
+// Source is the content served by ServeAndLaunch: either a directory or an
+// in-memory document. Exactly one of Dir and Content must be set.
+type Source struct {
+	// Dir is a directory to serve. Symbolic links that resolve outside Dir
+	// are not followed.
+	Dir string
+	// Content is a single document served from memory.
+	Content []byte
+	// Name is the file name for Content (default "index.html"). Its
+	// extension determines the Content-Type.
+	Name string
+}
+
+// ServeOptions contains options for ServeAndLaunch.
+type ServeOptions struct {
+	// Port to listen on (default 0, an ephemeral port). The server always
+	// binds to 127.0.0.1.
+	Port int
+	// Path is the URL path opened in the browser, e.g. "/report.html".
+	// Defaults to "/" for directories and "/<Name>" for content.
+	Path string
+	// TTL is how long the server runs before shutting down (default DefaultServeTTL).
+	TTL time.Duration
+	// Target browser to use. TargetNone starts the server without launching.
+	Target Target
+}
+
+// PreviewServer is a running loopback server started by ServeAndLaunch.
+type PreviewServer struct {
+	// URL is the address opened in the browser.
+	URL string
+
+	server    *http.Server
+	done      chan struct{}
+	cleanup   func()
+	closeOnce sync.Once
+}
+
+// ServeAndLaunch starts a loopback-only HTTP server for src and opens it in
+// the browser. It is the safe way to "open the generated report": the
+// browser receives an http://127.0.0.1 URL instead of a file:// URL, which
+// Launch rejects.
+//
+// Requests whose Host header is not the loopback address are rejected to
+// prevent DNS rebinding, and only GET and HEAD are served. The server shuts
+// down when TTL elapses, ctx is canceled, or Close is called.
+//
+// Example:
+//
+//	srv, err := browser.ServeAndLaunch(ctx, browser.Source{Dir: "./coverage"}, browser.ServeOptions{
+//	    Path: "/index.html",
+//	    TTL:  5 * time.Minute,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("Report available at %s\n", srv.URL)
+//	<-srv.Done()
+func ServeAndLaunch(ctx context.Context, src Source, opts ServeOptions) (*PreviewServer, error) {
+	handler, defaultPath, cleanup, err := src.handler()
+	if err != nil {
+		return nil, err
+	}
+
+	urlPath := opts.Path
+	if urlPath == "" {
+		urlPath = defaultPath
+	}
+	if !strings.HasPrefix(urlPath, "/") {
+		urlPath = "/" + urlPath
+	}
+	if strings.Contains(urlPath, "..") {
+		cleanup()
+		return nil, fmt.Errorf("invalid path %q: must not contain '..'", opts.Path)
+	}
+
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = DefaultServeTTL
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(opts.Port)))
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("failed to listen on loopback port %d: %w", opts.Port, err)
+	}
+	addr := listener.Addr().String()
+
+	s := &PreviewServer{
+		URL: "http://" + addr + urlPath,
+		server: &http.Server{
+			Handler:           loopbackOnly(addr, handler),
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		done:    make(chan struct{}),
+		cleanup: cleanup,
+	}
+
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	go func() {
+		timer := time.NewTimer(ttl)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		case <-s.done:
+			return
+		}
+		_ = s.Close()
+	}()
+
+	if err := Launch(LaunchOptions{URL: s.URL, Target: opts.Target}); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Done returns a channel that is closed once the server has shut down.
+func (s *PreviewServer) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close shuts the server down. It is safe to call more than once.
+func (s *PreviewServer) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		err = s.server.Shutdown(ctx)
+		s.cleanup()
+		close(s.done)
+	})
+	return err
+}
+
+// handler returns the HTTP handler for the source, the default URL path, and
+// a function releasing resources held by the handler.
+func (src Source) handler() (http.Handler, string, func(), error) {
+	switch {
+	case src.Dir != "" && src.Content != nil:
+		return nil, "", nil, errors.New("source must set either Dir or Content, not both")
+	case src.Dir != "":
+		root, err := os.OpenRoot(src.Dir)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("failed to open directory %s: %w", src.Dir, err)
+		}
+		// os.Root refuses paths and symbolic links that escape the directory.
+		return http.FileServerFS(root.FS()), "/", func() { _ = root.Close() }, nil
+	case src.Content != nil:
+		name := src.Name
+		if name == "" {
+			name = defaultContentName
+		}
+		if name != filepath.Base(name) || name == "." || name == ".." {
+			return nil, "", nil, fmt.Errorf("invalid content name %q: must be a plain file name", src.Name)
+		}
+		contentPath := "/" + name
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		modTime := time.Now()
+		content := src.Content
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != contentPath && r.URL.Path != "/" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", contentType)
+			http.ServeContent(w, r, name, modTime, bytes.NewReader(content))
+		})
+		return h, contentPath, func() {}, nil
+	default:
+		return nil, "", nil, errors.New("source must set Dir or Content")
+	}
+}
+
+// loopbackOnly wraps next, rejecting requests for any host other than addr
+// and any method other than GET or HEAD.
+func loopbackOnly(addr string, next http.Handler) http.Handler {
+	_, port, _ := net.SplitHostPort(addr)
+	allowed := map[string]bool{
+		addr:                true,
+		"localhost:" + port: true,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowed[r.Host] {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "no-store")
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package browser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, url, host string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "" {
+		req.Host = host
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestServeAndLaunch_Content(t *testing.T) {
+	srv, err := ServeAndLaunch(context.Background(), Source{Content: []byte("<h1>report</h1>"), Name: "report.html"}, ServeOptions{Target: TargetNone})
+	if err != nil {
+		t.Fatalf("ServeAndLaunch() error = %v", err)
+	}
+	defer srv.Close()
+
+	if !strings.HasPrefix(srv.URL, "http://127.0.0.1:") || !strings.HasSuffix(srv.URL, "/report.html") {
+		t.Errorf("URL = %q, want loopback URL ending in /report.html", srv.URL)
+	}
+	code, body := get(t, srv.URL, "")
+	if code != http.StatusOK || body != "<h1>report</h1>" {
+		t.Errorf("GET = %d %q, want 200 with content", code, body)
+	}
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html", ct)
+	}
+	if resp.Header.Get("X-Content-Type-Options") != "nosniff" {
+		t.Error("missing X-Content-Type-Options: nosniff")
+	}
+
+	if code, _ := get(t, strings.TrimSuffix(srv.URL, "report.html")+"other.html", ""); code != http.StatusNotFound {
+		t.Errorf("GET other path = %d, want 404", code)
+	}
+}
+
+func TestServeAndLaunch_Dir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("hello"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	symlinkErr := os.Symlink(outside, filepath.Join(dir, "escape.txt"))
+
+	srv, err := ServeAndLaunch(context.Background(), Source{Dir: dir}, ServeOptions{Path: "index.html", Target: TargetNone})
+	if err != nil {
+		t.Fatalf("ServeAndLaunch() error = %v", err)
+	}
+	defer srv.Close()
+
+	if !strings.HasSuffix(srv.URL, "/index.html") {
+		t.Errorf("URL = %q, want suffix /index.html", srv.URL)
+	}
+	if code, body := get(t, srv.URL, ""); code != http.StatusOK || body != "hello" {
+		t.Errorf("GET = %d %q, want 200 hello", code, body)
+	}
+	if symlinkErr == nil {
+		base := strings.TrimSuffix(srv.URL, "index.html")
+		if code, body := get(t, base+"escape.txt", ""); code == http.StatusOK || strings.Contains(body, "secret") {
+			t.Errorf("symlink escaping the directory was served: %d %q", code, body)
+		}
+	}
+}
+
+func TestServeAndLaunch_RejectsForeignHost(t *testing.T) {
+	srv, err := ServeAndLaunch(context.Background(), Source{Content: []byte("x")}, ServeOptions{Target: TargetNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	if code, _ := get(t, srv.URL, "attacker.example:80"); code != http.StatusForbidden {
+		t.Errorf("foreign Host status = %d, want 403", code)
+	}
+
+	resp, err := http.Post(srv.URL, "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestServeAndLaunch_Shutdown(t *testing.T) {
+	t.Run("ttl", func(t *testing.T) {
+		srv, err := ServeAndLaunch(context.Background(), Source{Content: []byte("x")}, ServeOptions{TTL: 50 * time.Millisecond, Target: TargetNone})
+		if err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-srv.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not shut down after TTL")
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		srv, err := ServeAndLaunch(ctx, Source{Content: []byte("x")}, ServeOptions{Target: TargetNone})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+		select {
+		case <-srv.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not shut down after cancel")
+		}
+		if _, err := http.Get(srv.URL); err == nil {
+			t.Error("server still accepting requests after shutdown")
+		}
+	})
+
+	t.Run("close twice", func(t *testing.T) {
+		srv, err := ServeAndLaunch(context.Background(), Source{Content: []byte("x")}, ServeOptions{Target: TargetNone})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+		if err := srv.Close(); err != nil {
+			t.Errorf("second Close() error = %v", err)
+		}
+	})
+}
+
+func TestServeAndLaunch_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		src  Source
+		opts ServeOptions
+	}{
+		{"empty source", Source{}, ServeOptions{}},
+		{"both dir and content", Source{Dir: t.TempDir(), Content: []byte("x")}, ServeOptions{}},
+		{"missing dir", Source{Dir: filepath.Join(t.TempDir(), "missing")}, ServeOptions{}},
+		{"name with separator", Source{Content: []byte("x"), Name: "../x.html"}, ServeOptions{}},
+		{"path traversal", Source{Content: []byte("x")}, ServeOptions{Path: "/../etc/passwd"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Target = TargetNone
+			srv, err := ServeAndLaunch(context.Background(), tt.src, tt.opts)
+			if err == nil {
+				srv.Close()
+				t.Error("ServeAndLaunch() error = nil, want error")
+			}
+		})
+	}
+}
@@ -6,10 +6,12 @@ package cliout
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strings"
 	"sync"
+	"unicode/utf8"
 )
 
 // Format represents the output format.
@@ -20,6 +22,8 @@ const (
 	FormatDefault Format = "default"
 	// FormatJSON is JSON format.
 	FormatJSON Format = "json"
+	// FormatNDJSON emits one JSON event per line as output happens.
+	FormatNDJSON Format = "ndjson"
 )
 
 // ANSI color codes for consistent styling
@@ -113,6 +117,12 @@ func NoColor() {
 	mu.Unlock()
 }
 
+// ColorEnabled reports whether color output is enabled, that is, NoColor
+// has not been called since the last ForceColor.
+func ColorEnabled() bool {
+	return !getNoColor()
+}
+
 // SetOrchestrated sets the orchestration mode flag.
 // When true, subcommands skip their headers.
 func SetOrchestrated(value bool) {
@@ -180,6 +190,13 @@ func detectUnicodeSupport() bool {
 	return true
 }
 
+// SupportsUnicode reports whether the terminal can display Unicode symbols.
+// When false, output should use ASCII equivalents (for example on the legacy
+// Windows console).
+func SupportsUnicode() bool {
+	return supportsUnicode
+}
+
 // getIcon returns the appropriate icon based on Unicode support
 func getIcon(unicode, ascii string) string {
 	if supportsUnicode {
@@ -195,8 +212,10 @@ func SetFormat(format string) error {
 		globalFormat = FormatDefault
 	case "json":
 		globalFormat = FormatJSON
+	case "ndjson":
+		globalFormat = FormatNDJSON
 	default:
-		return fmt.Errorf("invalid output format: %s (valid options: default, json)", format)
+		return fmt.Errorf("invalid output format: %s (valid options: default, json, ndjson)", format)
 	}
 	return nil
 }
@@ -228,9 +247,14 @@ func PrintDefault(formatter func()) {
 // Print outputs data in the configured format.
 // For default format, uses the formatter function.
 // For JSON format, marshals the data object.
+// For NDJSON format, emits the data object as a "result" event.
 func Print(data interface{}, formatter func()) error {
-	if globalFormat == FormatJSON {
+	switch globalFormat {
+	case FormatJSON:
 		return PrintJSON(data)
+	case FormatNDJSON:
+		emitEvent(Event{Type: EventResult, Data: data})
+		return nil
 	}
 	formatter()
 	return nil
@@ -240,6 +264,10 @@ func Print(data interface{}, formatter func()) error {
 
 // Header prints a bold header with a divider
 func Header(text string) {
+	if IsNDJSON() {
+		setSection(text)
+		return
+	}
 	fmt.Printf("\n%s%s%s\n", Bold, text, Reset)
 	fmt.Println(strings.Repeat("=", len(text)))
 }
@@ -248,7 +276,7 @@ func Header(text string) {
 // Shows just the command name with a short divider.
 // Skipped when in orchestrated mode (subcommands don't print headers).
 func CommandHeader(command, _ string) {
-	if globalFormat == FormatJSON || orchestratedMode {
+	if isMachineReadable() || orchestratedMode {
 		return
 	}
 	fmt.Println()
@@ -259,53 +287,87 @@ func CommandHeader(command, _ string) {
 
 // Section prints a section header
 func Section(icon, text string) {
+	if IsNDJSON() {
+		setSection(text)
+		return
+	}
 	displayIcon := getIcon(icon, "[>]")
-	fmt.Printf("\n%s%s %s%s\n", Cyan, displayIcon, text, Reset)
+	fmt.Printf("\n%s%s %s%s\n", CurrentTheme().Accent, displayIcon, text, Reset)
 }
 
 // Success prints a success message with green checkmark
 func Success(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	check := getIcon(SymbolCheck, ASCIICheck)
-	fmt.Printf("%s%s%s %s\n", BrightGreen, check, Reset, msg)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventSuccess, Message: msg})
+		return
+	}
+	t := CurrentTheme()
+	check := getIcon(t.SuccessSymbol, ASCIICheck)
+	fmt.Printf("%s%s%s %s\n", t.Success, check, Reset, msg)
 }
 
 // Error prints an error message with red X
 func Error(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	cross := getIcon(SymbolCross, ASCIICross)
-	fmt.Printf("%s%s%s %s\n", BrightRed, cross, Reset, msg)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventError, Message: msg})
+		return
+	}
+	t := CurrentTheme()
+	cross := getIcon(t.ErrorSymbol, ASCIICross)
+	fmt.Printf("%s%s%s %s\n", t.Error, cross, Reset, msg)
 }
 
 // Warning prints a warning message with yellow triangle
 func Warning(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	warning := getIcon(SymbolWarning, ASCIIWarning)
-	fmt.Printf("%s%s%s  %s\n", BrightYellow, warning, Reset, msg)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventWarning, Message: msg})
+		return
+	}
+	t := CurrentTheme()
+	warning := getIcon(t.WarningSymbol, ASCIIWarning)
+	fmt.Printf("%s%s%s  %s\n", t.Warning, warning, Reset, msg)
 }
 
 // Info prints an info message with blue info icon
 func Info(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	info := getIcon(SymbolInfo, ASCIIInfo)
-	fmt.Printf("%s%s%s  %s\n", BrightBlue, info, Reset, msg)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventInfo, Message: msg})
+		return
+	}
+	t := CurrentTheme()
+	info := getIcon(t.InfoSymbol, ASCIIInfo)
+	fmt.Printf("%s%s%s  %s\n", t.Info, info, Reset, msg)
 }
 
 // Step prints a step message with an icon
 func Step(icon, format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventStep, Message: msg})
+		return
+	}
 	displayIcon := getIcon(icon, "[*]")
-	fmt.Printf("%s%s%s %s\n", Cyan, displayIcon, Reset, msg)
+	fmt.Printf("%s%s%s %s\n", CurrentTheme().Accent, displayIcon, Reset, msg)
 }
 
 // Item prints an indented item
 func Item(format string, args ...interface{}) {
+	if IsNDJSON() {
+		return
+	}
 	msg := fmt.Sprintf(format, args...)
 	fmt.Printf("   %s\n", msg)
 }
 
 // Bullet prints a bulleted list item
 func Bullet(format string, args ...interface{}) {
+	if IsNDJSON() {
+		return
+	}
 	msg := fmt.Sprintf(format, args...)
 	bullet := getIcon(SymbolDot, "*")
 	fmt.Printf("  %s %s\n", bullet, msg)
@@ -314,45 +376,71 @@ func Bullet(format string, args ...interface{}) {
 // ItemSuccess prints an indented success item
 func ItemSuccess(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	check := getIcon(SymbolCheck, ASCIICheck)
-	fmt.Printf("   %s%s%s %s\n", Green, check, Reset, msg)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventSuccess, Message: msg})
+		return
+	}
+	t := CurrentTheme()
+	check := getIcon(t.SuccessSymbol, ASCIICheck)
+	fmt.Printf("   %s%s%s %s\n", t.Success, check, Reset, msg)
 }
 
 // ItemError prints an indented error item
 func ItemError(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	cross := getIcon(SymbolCross, ASCIICross)
-	fmt.Printf("   %s%s%s %s\n", Red, cross, Reset, msg)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventError, Message: msg})
+		return
+	}
+	t := CurrentTheme()
+	cross := getIcon(t.ErrorSymbol, ASCIICross)
+	fmt.Printf("   %s%s%s %s\n", t.Error, cross, Reset, msg)
 }
 
 // ItemWarning prints an indented warning item
 func ItemWarning(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	warning := getIcon(SymbolWarning, ASCIIWarning)
-	fmt.Printf("   %s%s%s  %s\n", Yellow, warning, Reset, msg)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventWarning, Message: msg})
+		return
+	}
+	t := CurrentTheme()
+	warning := getIcon(t.WarningSymbol, ASCIIWarning)
+	fmt.Printf("   %s%s%s  %s\n", t.Warning, warning, Reset, msg)
 }
 
 // ItemInfo prints an indented info item
 func ItemInfo(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	info := getIcon(SymbolInfo, ASCIIInfo)
-	fmt.Printf("   %s%s%s  %s\n", Cyan, info, Reset, msg)
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventInfo, Message: msg})
+		return
+	}
+	t := CurrentTheme()
+	info := getIcon(t.InfoSymbol, ASCIIInfo)
+	fmt.Printf("   %s%s%s  %s\n", t.Info, info, Reset, msg)
 }
 
 // Divider prints a horizontal divider
 func Divider() {
+	if IsNDJSON() {
+		return
+	}
 	fmt.Printf("\n%s%s%s\n", Dim, strings.Repeat("─", 50), Reset)
 }
 
 // Newline prints a blank line
 func Newline() {
+	if IsNDJSON() {
+		return
+	}
 	fmt.Println()
 }
 
 // Hint prints compact hints on a single line with bullet separators.
 // Example: Hint("Press Ctrl+C to stop", "Use --web to open browser")
 func Hint(hints ...string) {
-	if len(hints) == 0 {
+	if len(hints) == 0 || IsNDJSON() {
 		return
 	}
 	fmt.Printf("%s%s%s\n", Dim, strings.Join(hints, " • "), Reset)
@@ -360,44 +448,41 @@ func Hint(hints ...string) {
 
 // Phase prints a phase label like "Installing dependencies..." or "Starting services..."
 func Phase(label string) {
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventStep, Message: label})
+		return
+	}
 	fmt.Printf("%s%s%s\n", Dim, label, Reset)
 }
 
 // Plain prints plain text without any formatting.
 func Plain(format string, args ...interface{}) {
-	fmt.Printf(format+"\n", args...)
-}
-
-// Confirm prompts the user for confirmation and returns true if they confirm.
-// Returns true immediately if in JSON mode (non-interactive).
-// The prompt displays the message and waits for y/n input.
-func Confirm(message string) bool {
-	if globalFormat == FormatJSON {
-		return true // Non-interactive mode, assume yes
-	}
-	fmt.Printf("%s%s%s [y/N]: ", BrightYellow, message, Reset)
-	var response string
-	if _, err := fmt.Scanln(&response); err != nil {
-		return false // On read error, default to no
+	if IsNDJSON() {
+		return
 	}
-	response = strings.ToLower(strings.TrimSpace(response))
-	return response == "y" || response == "yes"
+	fmt.Printf(format+"\n", args...)
 }
 
 // Label prints a label and value pair
 func Label(label, value string) {
+	if IsNDJSON() {
+		return
+	}
 	fmt.Printf("   %s%-12s%s %s\n", Dim, label+":", Reset, value)
 }
 
 // LabelColored prints a label and colored value pair
 func LabelColored(label, value, color string) {
+	if IsNDJSON() {
+		return
+	}
 	fmt.Printf("   %s%-12s%s %s%s%s\n", Dim, label+":", Reset, color, value, Reset)
 }
 
 // Highlight prints highlighted text
 func Highlight(format string, args ...interface{}) string {
 	msg := fmt.Sprintf(format, args...)
-	return Bold + Cyan + msg + Reset
+	return Bold + CurrentTheme().Accent + msg + Reset
 }
 
 // Emphasize prints emphasized text
@@ -412,9 +497,9 @@ func Muted(format string, args ...interface{}) string {
 	return Dim + msg + Reset
 }
 
-// URL prints a URL in bright blue
+// URL prints a URL in the theme's info color
 func URL(url string) string {
-	return BrightBlue + url + Reset
+	return CurrentTheme().Info + url + Reset
 }
 
 // Count prints a count badge
@@ -424,15 +509,16 @@ func Count(n int) string {
 
 // Status prints a status badge with appropriate color
 func Status(status string) string {
+	t := CurrentTheme()
 	switch strings.ToLower(status) {
 	case "success", "ok", "running", "healthy":
-		return BrightGreen + status + Reset
+		return t.Success + status + Reset
 	case "warning", "pending", "starting":
-		return BrightYellow + status + Reset
+		return t.Warning + status + Reset
 	case "error", "failed", "unhealthy":
-		return BrightRed + status + Reset
+		return t.Error + status + Reset
 	case "info", "unknown":
-		return BrightBlue + status + Reset
+		return t.Info + status + Reset
 	default:
 		return status
 	}
@@ -454,43 +540,61 @@ type TableRow map[string]string
 
 // Table prints a simple table with the given headers and rows.
 func Table(headers []string, rows []TableRow) {
-	if len(rows) == 0 {
+	if len(rows) == 0 || IsNDJSON() {
 		return
 	}
+	writeTable(os.Stdout, headers, rows)
+}
 
+// writeTable renders a table to w.
+func writeTable(w io.Writer, headers []string, rows []TableRow) {
 	// Calculate column widths
 	widths := make(map[string]int)
 	for _, header := range headers {
-		widths[header] = len(header)
+		widths[header] = displayWidth(header)
 	}
 	for _, row := range rows {
 		for _, header := range headers {
-			if len(row[header]) > widths[header] {
-				widths[header] = len(row[header])
+			if n := displayWidth(row[header]); n > widths[header] {
+				widths[header] = n
 			}
 		}
 	}
 
 	// Print header
-	fmt.Print("   ")
+	fmt.Fprint(w, "   ")
 	for _, header := range headers {
-		fmt.Printf("%s%-*s%s  ", Bold, widths[header], header, Reset)
+		fmt.Fprintf(w, "%s%s%s  ", Bold, padRight(header, widths[header]), Reset)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// Print separator
-	fmt.Print("   ")
+	fmt.Fprint(w, "   ")
 	for _, header := range headers {
-		fmt.Print(strings.Repeat("─", widths[header]) + "  ")
+		fmt.Fprint(w, strings.Repeat("─", widths[header])+"  ")
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// Print rows
 	for _, row := range rows {
-		fmt.Print("   ")
+		fmt.Fprint(w, "   ")
 		for _, header := range headers {
-			fmt.Printf("%-*s  ", widths[header], row[header])
+			fmt.Fprintf(w, "%s  ", padRight(row[header], widths[header]))
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
+	}
+}
+
+// displayWidth returns the number of terminal columns s occupies, ignoring
+// ANSI escape sequences, so colored cells and symbols align in tables.
+func displayWidth(s string) int {
+	return utf8.RuneCountInString(stripANSI(s))
+}
+
+// padRight pads s with spaces to width display columns.
+func padRight(s string, width int) string {
+	if pad := width - displayWidth(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
 	}
+	return s
 }
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 // captureOutput captures stdout during function execution
@@ -283,6 +284,20 @@ func TestGetIcon(t *testing.T) {
 	}
 }
 
+func TestSupportsUnicode(t *testing.T) {
+	origSupportsUnicode := supportsUnicode
+	defer func() { supportsUnicode = origSupportsUnicode }()
+
+	supportsUnicode = true
+	if !SupportsUnicode() {
+		t.Error("SupportsUnicode() = false, want true")
+	}
+	supportsUnicode = false
+	if SupportsUnicode() {
+		t.Error("SupportsUnicode() = true, want false")
+	}
+}
+
 // Test Output Functions
 
 func TestSuccess(t *testing.T) {
@@ -725,6 +740,30 @@ func TestTable(t *testing.T) {
 	}
 }
 
+func TestTableAlignsColoredAndUnicodeCells(t *testing.T) {
+	rows := []TableRow{
+		{"Task": "web", "Status": Green + "✓ success" + Reset},
+		{"Task": "api", "Status": "- skipped"},
+	}
+	output := captureOutput(t, func() {
+		Table([]string{"Status", "Task"}, rows)
+	})
+
+	// The Task column must start at the same display column on every line.
+	var columns []int
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n")[2:] {
+		plain := stripANSI(line)
+		idx := strings.Index(plain, "web")
+		if idx < 0 {
+			idx = strings.Index(plain, "api")
+		}
+		columns = append(columns, utf8.RuneCountInString(plain[:idx]))
+	}
+	if len(columns) != 2 || columns[0] != columns[1] {
+		t.Errorf("Task column misaligned at %v:\n%s", columns, output)
+	}
+}
+
 // Test JSON Output
 
 func TestPrintJSON(t *testing.T) {
@@ -856,12 +895,18 @@ func TestForceColorAndNoColor(t *testing.T) {
 	if !getNoColor() {
 		t.Error("Expected noColor to be true after NoColor()")
 	}
+	if ColorEnabled() {
+		t.Error("Expected ColorEnabled() to be false after NoColor()")
+	}
 
 	// Test ForceColor
 	ForceColor()
 	if getNoColor() {
 		t.Error("Expected noColor to be false after ForceColor()")
 	}
+	if !ColorEnabled() {
+		t.Error("Expected ColorEnabled() to be true after ForceColor()")
+	}
 }
 
 func TestGetNoColor(t *testing.T) {
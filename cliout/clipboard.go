@@ -0,0 +1,93 @@
+package cliout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ErrClipboardUnavailable is returned by CopyToClipboard when no clipboard
+// tool is installed or the one found fails, for example over SSH or in a
+// container without a display.
+var ErrClipboardUnavailable = errors.New("clipboard unavailable")
+
+// clipboardTimeout bounds how long a clipboard tool may run.
+const clipboardTimeout = 5 * time.Second
+
+// clipboardCommands returns the clipboard tools to try, in order. It is
+// replaced in tests.
+var clipboardCommands = func() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	var cmds [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		cmds = append(cmds,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"})
+	}
+	// Under WSL the Windows clipboard is reachable through clip.exe.
+	return append(cmds, []string{"clip.exe"})
+}
+
+// CopyToClipboard copies text to the system clipboard using pbcopy on
+// macOS, clip.exe on Windows and WSL, and wl-copy, xclip, or xsel on Linux.
+// The returned error wraps ErrClipboardUnavailable when no tool is
+// available or the tool fails.
+func CopyToClipboard(text string) error {
+	var lastErr error
+	for _, args := range clipboardCommands() {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), clipboardTimeout)
+		// #nosec G204 -- the command is one of the fixed clipboard tools above
+		cmd := exec.CommandContext(ctx, path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		err = cmd.Run()
+		cancel()
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("%w: %s failed: %v", ErrClipboardUnavailable, args[0], err)
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return fmt.Errorf("%w: no clipboard tool found", ErrClipboardUnavailable)
+}
+
+// CopyOrPrint copies text to the clipboard and prints "<label> copied to
+// clipboard". When the clipboard is unavailable it prints a warning and
+// the text itself, so the user can copy it by hand. In JSON and NDJSON
+// modes it only copies; include the value in the command's result instead.
+// It reports whether the text was copied.
+//
+// Example:
+//
+//	cliout.CopyOrPrint("Connection string", connStr)
+func CopyOrPrint(label, text string) bool {
+	err := CopyToClipboard(text)
+	if isMachineReadable() {
+		return err == nil
+	}
+	if err == nil {
+		Success("%s copied to clipboard", label)
+		return true
+	}
+	Warning("%s (clipboard unavailable, copy it manually):", label)
+	fmt.Println(text)
+	return false
+}
@@ -0,0 +1,138 @@
+package cliout
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestClipboardHelperProcess is not a real test. CopyToClipboard runs it as
+// the clipboard tool when CLIOUT_CLIPBOARD_FILE is set; it writes stdin to
+// that file, or fails when CLIOUT_CLIPBOARD_FAIL=1.
+func TestClipboardHelperProcess(t *testing.T) {
+	file := os.Getenv("CLIOUT_CLIPBOARD_FILE")
+	if file == "" {
+		return
+	}
+	if os.Getenv("CLIOUT_CLIPBOARD_FAIL") == "1" {
+		os.Exit(1)
+	}
+	data, _ := io.ReadAll(os.Stdin)
+	_ = os.WriteFile(file, data, 0o600)
+	os.Exit(0)
+}
+
+// withClipboard routes CopyToClipboard to the given commands and returns
+// the file TestClipboardHelperProcess writes to.
+func withClipboard(t *testing.T, cmds ...[]string) string {
+	t.Helper()
+	old := clipboardCommands
+	clipboardCommands = func() [][]string { return cmds }
+	t.Cleanup(func() {
+		clipboardCommands = old
+		globalFormat = FormatDefault
+	})
+	file := filepath.Join(t.TempDir(), "clipboard")
+	t.Setenv("CLIOUT_CLIPBOARD_FILE", file)
+	return file
+}
+
+func helperClipboard() []string {
+	return []string{os.Args[0], "-test.run=^TestClipboardHelperProcess$"}
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	file := withClipboard(t, []string{"no-such-clipboard-tool"}, helperClipboard())
+
+	if err := CopyToClipboard("Server=db;Password=x"); err != nil {
+		t.Fatalf("CopyToClipboard() error = %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "Server=db;Password=x" {
+		t.Errorf("clipboard = %q", data)
+	}
+}
+
+func TestCopyToClipboard_Unavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		cmds [][]string
+		fail bool
+		want string
+	}{
+		{name: "no tool", cmds: [][]string{{"no-such-clipboard-tool"}}, want: "no clipboard tool found"},
+		{name: "tool fails", cmds: [][]string{helperClipboard()}, fail: true, want: "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withClipboard(t, tt.cmds...)
+			if tt.fail {
+				t.Setenv("CLIOUT_CLIPBOARD_FAIL", "1")
+			}
+			err := CopyToClipboard("text")
+			if !errors.Is(err, ErrClipboardUnavailable) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CopyToClipboard() error = %v, want ErrClipboardUnavailable containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyOrPrint(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmds       [][]string
+		format     Format
+		wantCopied bool
+		wantOutput []string
+		wantAbsent string
+	}{
+		{
+			name:       "copied",
+			cmds:       [][]string{helperClipboard()},
+			format:     FormatDefault,
+			wantCopied: true,
+			wantOutput: []string{"URL copied to clipboard"},
+			wantAbsent: "https://example.com",
+		},
+		{
+			name:       "fallback prints text",
+			cmds:       [][]string{{"no-such-clipboard-tool"}},
+			format:     FormatDefault,
+			wantOutput: []string{"clipboard unavailable", "https://example.com"},
+		},
+		{
+			name:       "json prints nothing",
+			cmds:       [][]string{{"no-such-clipboard-tool"}},
+			format:     FormatJSON,
+			wantAbsent: "https://example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withClipboard(t, tt.cmds...)
+			globalFormat = tt.format
+
+			var copied bool
+			output := captureOutput(t, func() {
+				copied = CopyOrPrint("URL", "https://example.com")
+			})
+			if copied != tt.wantCopied {
+				t.Errorf("CopyOrPrint() = %v, want %v", copied, tt.wantCopied)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+			if tt.wantAbsent != "" && strings.Contains(output, tt.wantAbsent) {
+				t.Errorf("output should not contain %q:\n%s", tt.wantAbsent, output)
+			}
+		})
+	}
+}
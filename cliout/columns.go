@@ -0,0 +1,110 @@
+package cliout
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	// columnIndent matches the indent of Item and Table.
+	columnIndent = "   "
+	// columnGap separates columns.
+	columnGap = 2
+)
+
+// terminalWidth is replaced in tests.
+var terminalWidth = func() int {
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		return w
+	}
+	return 0
+}
+
+// Columns prints short items, such as service, environment, or extension
+// names, in as many columns as fit the terminal width (the COLUMNS
+// environment variable, if set, overrides the detected width), filling each
+// column top to bottom like ls. Items are printed one per line when stdout
+// is not a terminal or a single column is all that fits. In JSON mode the
+// items are printed as a JSON array, and in NDJSON mode they are emitted as
+// a "result" event.
+//
+// Example:
+//
+//	cliout.Columns([]string{"api", "web", "worker", "frontend", "jobs"})
+//	//    api  web  worker  frontend  jobs
+func Columns(items []string) {
+	if items == nil {
+		items = []string{}
+	}
+	_ = Print(items, func() {
+		width := 0
+		if stdoutIsTerminal() {
+			width = terminalWidth()
+		}
+		writeColumns(os.Stdout, items, width)
+	})
+}
+
+// writeColumns lays out items in column-major order within width display
+// columns. A width of 0 or less prints one item per line.
+func writeColumns(w io.Writer, items []string, width int) {
+	if len(items) == 0 {
+		return
+	}
+
+	rows, widths := columnLayout(items, width)
+	for r := 0; r < rows; r++ {
+		var line strings.Builder
+		line.WriteString(columnIndent)
+		for c, colWidth := range widths {
+			i := c*rows + r
+			if i >= len(items) {
+				break
+			}
+			// Pad every column but the last one on the line.
+			if c < len(widths)-1 && i+rows < len(items) {
+				line.WriteString(padRight(items[i], colWidth+columnGap))
+			} else {
+				line.WriteString(items[i])
+			}
+		}
+		fmt.Fprintln(w, line.String())
+	}
+}
+
+// columnLayout returns the fewest rows, and the width of each column, that
+// fit items within width.
+func columnLayout(items []string, width int) (int, []int) {
+	itemWidths := make([]int, len(items))
+	widest := 0
+	for i, item := range items {
+		itemWidths[i] = displayWidth(item)
+		widest = max(widest, itemWidths[i])
+	}
+
+	available := width - len(columnIndent)
+	for rows := 1; rows < len(items); rows++ {
+		cols := (len(items) + rows - 1) / rows
+		widths := make([]int, cols)
+		total := (cols - 1) * columnGap
+		for i, n := range itemWidths {
+			c := i / rows
+			if n > widths[c] {
+				total += n - widths[c]
+				widths[c] = n
+			}
+		}
+		if total <= available {
+			return rows, widths
+		}
+	}
+	return len(items), []int{widest}
+}
@@ -0,0 +1,104 @@
+package cliout
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteColumns(t *testing.T) {
+	items := []string{"api", "web", "worker", "frontend", "jobs"}
+	tests := []struct {
+		name  string
+		items []string
+		width int
+		want  string
+	}{
+		{"empty", nil, 80, ""},
+		{"no width", items, 0, "   api\n   web\n   worker\n   frontend\n   jobs\n"},
+		{"one row", items, 80, "   api  web  worker  frontend  jobs\n"},
+		// Column-major, like ls: each column is filled top to bottom.
+		{"two rows", items, 30, "   api  worker    jobs\n   web  frontend\n"},
+		{"too narrow", items, 10, "   api\n   web\n   worker\n   frontend\n   jobs\n"},
+		{"single item", []string{"api"}, 80, "   api\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeColumns(&buf, tt.items, tt.width)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writeColumns() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteColumns_FitsWidth(t *testing.T) {
+	items := []string{"alpha", "b", "charlie-service", "d", "echo", "foxtrot", "g", "hotel-extension", "i", "juliet"}
+	for width := 10; width <= 100; width++ {
+		var buf bytes.Buffer
+		writeColumns(&buf, items, width)
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		seen := 0
+		for _, line := range lines {
+			if n := displayWidth(line); n > width && len(lines) < len(items) {
+				t.Errorf("width %d: line %q is %d columns wide", width, line, n)
+			}
+			seen += len(strings.Fields(line))
+		}
+		if seen != len(items) {
+			t.Errorf("width %d: printed %d items, want %d", width, seen, len(items))
+		}
+	}
+}
+
+func TestWriteColumns_UnicodeAndColor(t *testing.T) {
+	items := []string{Green + "✓ api" + Reset, "web", "worker", "jobs"}
+	var buf bytes.Buffer
+	writeColumns(&buf, items, 18)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	// The second column starts at the same display column on both lines.
+	first := strings.Index(stripANSI(lines[0]), "worker")
+	second := strings.Index(stripANSI(lines[1]), "jobs")
+	if displayWidth(stripANSI(lines[0])[:first]) != displayWidth(stripANSI(lines[1])[:second]) {
+		t.Errorf("columns are not aligned:\n%s", buf.String())
+	}
+}
+
+func TestColumns(t *testing.T) {
+	oldTTY, oldWidth := stdoutIsTerminal, terminalWidth
+	stdoutIsTerminal = func() bool { return true }
+	terminalWidth = func() int { return 80 }
+	t.Cleanup(func() {
+		stdoutIsTerminal, terminalWidth = oldTTY, oldWidth
+		globalFormat = FormatDefault
+	})
+
+	output := captureOutput(t, func() { Columns([]string{"dev", "staging", "prod"}) })
+	if output != "   dev  staging  prod\n" {
+		t.Errorf("Columns() on a terminal = %q", output)
+	}
+
+	stdoutIsTerminal = func() bool { return false }
+	output = captureOutput(t, func() { Columns([]string{"dev", "staging", "prod"}) })
+	if output != "   dev\n   staging\n   prod\n" {
+		t.Errorf("Columns() without a terminal = %q", output)
+	}
+
+	globalFormat = FormatJSON
+	for _, items := range [][]string{{"dev", "prod"}, nil} {
+		output = captureOutput(t, func() { Columns(items) })
+		var got []string
+		if err := json.Unmarshal([]byte(output), &got); err != nil {
+			t.Fatalf("Columns() in JSON mode = %q: %v", output, err)
+		}
+		if got == nil || len(got) != len(items) {
+			t.Errorf("Columns(%q) in JSON mode = %q", items, output)
+		}
+	}
+}
@@ -3,11 +3,12 @@
 //
 // # Features
 //
-//   - Multiple output formats (default human-readable and JSON)
+//   - Multiple output formats (default human-readable, JSON, and NDJSON events)
 //   - ANSI color support with consistent color scheme
 //   - Unicode/emoji detection with ASCII fallbacks for legacy terminals
 //   - Orchestration mode for composing subcommands
-//   - Progress bars, tables, and interactive prompts
+//   - Progress bars, timed steps, tables, and interactive prompts
+//   - Clipboard copy (CopyToClipboard, CopyOrPrint) with a printed fallback
 //   - Cross-platform terminal detection (Windows Terminal, VS Code, PowerShell, ConEmu)
 //
 // # Basic Usage
@@ -28,9 +29,10 @@
 //
 // # Output Formats
 //
-// The package supports two output formats:
+// The package supports three output formats:
 //   - default: Human-readable text with colors and Unicode symbols
 //   - json: Structured JSON output for automation and scripting
+//   - ndjson: One JSON event per line, emitted as output happens
 //
 // Set the output format using SetFormat:
 //
@@ -44,6 +46,18 @@
 //	    // Skip interactive prompts
 //	}
 //
+// # NDJSON Events
+//
+// In ndjson mode, Success, Error, Warning, Info, Step, and their Item variants
+// each write one Event line with type, message, timestamp, and the current
+// section (set by Section or Header). Print emits a "result" event and
+// PresentError an "error" event carrying the RichError. Decorative output
+// such as tables, dividers, and hints is suppressed, and no ANSI escapes are
+// written:
+//
+//	{"type":"section","message":"Build","timestamp":"2026-01-02T03:04:05Z","section":"Build"}
+//	{"type":"step","message":"compiling 3 files","timestamp":"2026-01-02T03:04:05Z","section":"Build"}
+//
 // # Unicode Detection
 //
 // The package automatically detects terminal Unicode support and falls back to
@@ -55,7 +69,8 @@
 //   - Unix-like systems (assumed to support Unicode)
 //
 // Old Windows Command Prompt (cmd.exe) without these environment variables will
-// use ASCII fallback symbols.
+// use ASCII fallback symbols. SupportsUnicode exposes the result so other
+// packages (such as progress) can pick matching glyphs.
 //
 // # Orchestration Mode
 //
@@ -79,6 +94,16 @@
 //
 // In JSON mode, the data is marshaled to JSON. In default mode, the formatter is called.
 //
+// # Message Templates
+//
+// Sprintf and Printf accept {style:text} placeholders instead of
+// concatenating Highlight, URL, and Reset by hand. Each placeholder is reset
+// on its own, and all styling is dropped in JSON, NDJSON, and no-color mode:
+//
+//	cliout.Printf("Deployed {highlight:%s} to {url:%s}", name, endpoint)
+//
+// Styles are highlight, bold, muted, url, success, error, warning, and info.
+//
 // # Tables
 //
 // Create simple tables with automatic column width calculation:
@@ -90,6 +115,35 @@
 //	}
 //	cliout.Table(headers, rows)
 //
+// Columns lays out short items such as service or environment names in as
+// many columns as fit the terminal, like ls, one per line when stdout is not
+// a terminal, or as a JSON array in JSON mode:
+//
+//	cliout.Columns([]string{"api", "web", "worker"})
+//
+// # Paging
+//
+// PageTable and Page send output taller than the terminal through a pager
+// (AZD_PAGER, then PAGER, then "less -FRX" on Unix or "more" on Windows).
+// Output is written directly when stdout is not a terminal, in JSON mode, or
+// after SetPaging(false):
+//
+//	if err := cliout.PageTable(headers, rows); err != nil {
+//	    return err
+//	}
+//
+// # Error Presentation
+//
+// PresentError renders an error with a summary, details, suggested actions, and a
+// docs link, or as {"error": {...}} in JSON mode. Return a *RichError to control
+// the output; security and context sentinel errors get codes and suggestions
+// automatically, and RegisterError adds your own:
+//
+//	cliout.RegisterError(ErrNoProject, "NO_PROJECT", "", "Run 'azd init' first")
+//	if err := run(); err != nil {
+//	    cliout.PresentError(err)
+//	}
+//
 // # Interactive Prompts
 //
 // The Confirm function prompts for user confirmation:
@@ -98,7 +152,29 @@
 //	    // User confirmed
 //	}
 //
-// In JSON mode, Confirm always returns true (non-interactive).
+// ConfirmWithOptions adds a default answer, a timeout for unattended runs,
+// and, for destructive operations, a phrase the user must type:
+//
+//	ok, err := cliout.ConfirmWithOptions("Delete resource group rg-dev?", cliout.ConfirmOptions{
+//	    RequireTypedPhrase: "rg-dev",
+//	    Timeout:            time.Minute,
+//	})
+//
+// Select and Input prompt for a choice or a line of text:
+//
+//	region, err := cliout.Select("Select a region", []string{"eastus", "westus2"})
+//	name, err := cliout.Input("App name", "myapp")
+//
+// In JSON mode, Confirm returns true, ConfirmWithOptions and Input return
+// their defaults, and Select and typed-phrase confirmations return
+// ErrNoAnswer unless a pre-seeded answer exists.
+//
+// For unattended runs, answers can be pre-seeded from a JSON object keyed by
+// prompt message, either via the AZD_PROMPT_ANSWERS environment variable (a
+// file path or inline JSON) or LoadPromptAnswers. RecordPromptAnswers writes
+// the answers given during a run to a file for later replay:
+//
+//	AZD_PROMPT_ANSWERS='{"Do you want to continue?": "yes"}' myext deploy
 //
 // # Progress Indicators
 //
@@ -107,6 +183,18 @@
 //	bar := cliout.ProgressBar(45, 100, 30)
 //	fmt.Println(bar)  // [█████████████░░░░░░░░░░░░░░░░░] 45%
 //
+// StartStep times a step, showing a spinner with the elapsed time on a
+// terminal and the final duration when it finishes. In JSON mode the
+// finished steps are collected by StepRecords; in NDJSON mode each step
+// emits events carrying a StepRecord:
+//
+//	step := cliout.StartStep("Installing dependencies")
+//	if err := install(); err != nil {
+//	    step.Fail(err)
+//	    return err
+//	}
+//	step.Succeed()  // ✓ Installing dependencies (4.2s)
+//
 // # Color Constants
 //
 // The package exports ANSI color constants for custom formatting:
@@ -114,6 +202,18 @@
 //   - Foreground colors: Black, Red, Green, Yellow, Blue, Magenta, Cyan, White, Gray
 //   - Bright colors: BrightRed, BrightGreen, BrightYellow, BrightBlue, BrightMagenta, BrightCyan
 //
+// # Themes
+//
+// Success, Error, Warning, Info, and the other message functions take their
+// colors and symbols from the active Theme. Built-in themes are "default",
+// "high-contrast", and "monochrome"; custom themes start from a built-in one:
+//
+//	if err := cliout.SetThemeByName(cliout.ThemeHighContrast); err != nil {
+//	    return err
+//	}
+//
+// azdextutil.SaveTheme and azdextutil.LoadTheme persist the user's choice.
+//
 // # Unicode Symbols
 //
 // Unicode symbols with ASCII fallbacks:
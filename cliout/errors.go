@@ -0,0 +1,175 @@
+package cliout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// Error codes used by PresentError for well-known errors.
+const (
+	CodeUnknown                 = "UNKNOWN"
+	CodeCanceled                = "CANCELED"
+	CodeTimeout                 = "TIMEOUT"
+	CodeInvalidPath             = "INVALID_PATH"
+	CodePathTraversal           = "PATH_TRAVERSAL"
+	CodeInvalidServiceName      = "INVALID_SERVICE_NAME"
+	CodeInsecureFilePermissions = "INSECURE_FILE_PERMISSIONS"
+)
+
+// RichError is a user-facing error with a stable code, a short summary,
+// optional details, and suggested actions. Return one from a command to
+// control how PresentError renders it.
+type RichError struct {
+	// Code is a stable, machine-readable identifier such as "PATH_TRAVERSAL".
+	Code string `json:"code"`
+	// Summary is a one-line description of what went wrong.
+	Summary string `json:"summary"`
+	// Details holds additional context, such as the underlying cause.
+	Details string `json:"details,omitempty"`
+	// Suggestions lists actions the user can take to resolve the error.
+	Suggestions []string `json:"suggestions,omitempty"`
+	// DocsURL links to documentation about the error.
+	DocsURL string `json:"docsUrl,omitempty"`
+	// Err is the underlying error, if any. It is not rendered directly.
+	Err error `json:"-"`
+}
+
+// Error implements the error interface.
+func (e *RichError) Error() string {
+	if e.Details != "" {
+		return e.Summary + ": " + e.Details
+	}
+	return e.Summary
+}
+
+// Unwrap returns the underlying error.
+func (e *RichError) Unwrap() error {
+	return e.Err
+}
+
+// knownError associates a sentinel error with its presentation.
+type knownError struct {
+	target      error
+	code        string
+	suggestions []string
+	docsURL     string
+}
+
+var (
+	knownErrorsMu sync.RWMutex
+	knownErrors   = []knownError{
+		{target: context.Canceled, code: CodeCanceled},
+		{target: context.DeadlineExceeded, code: CodeTimeout, suggestions: []string{
+			"Retry the operation; increase the timeout if it keeps failing",
+		}},
+		{target: security.ErrPathTraversal, code: CodePathTraversal, suggestions: []string{
+			"Use a path inside the project directory without '..' segments",
+		}},
+		{target: security.ErrInvalidPath, code: CodeInvalidPath, suggestions: []string{
+			"Check the path for typos and unsupported characters",
+		}},
+		{target: security.ErrInvalidServiceName, code: CodeInvalidServiceName, suggestions: []string{
+			"Service names must start with a letter or digit and contain only letters, digits, '.', '_' or '-' (max 63 characters)",
+		}},
+		{target: security.ErrInsecureFilePermissions, code: CodeInsecureFilePermissions, suggestions: []string{
+			"Remove world-write permission from the file (for example: chmod o-w <file>)",
+		}},
+	}
+)
+
+// RegisterError teaches PresentError how to present errors matching target
+// (using errors.Is). Registrations take precedence over the built-in entries
+// for context and security errors.
+//
+// Example:
+//
+//	cliout.RegisterError(ErrNotLoggedIn, "NOT_LOGGED_IN", "https://aka.ms/azd-auth", "Run 'azd auth login'")
+func RegisterError(target error, code, docsURL string, suggestions ...string) {
+	knownErrorsMu.Lock()
+	defer knownErrorsMu.Unlock()
+	entry := knownError{target: target, code: code, suggestions: suggestions, docsURL: docsURL}
+	knownErrors = append([]knownError{entry}, knownErrors...)
+}
+
+// AsRichError converts err to a *RichError. A *RichError anywhere in the
+// chain is returned as-is; registered sentinel errors get their code and
+// suggestions; anything else gets CodeUnknown. Secrets in messages are
+// redacted. Returns nil if err is nil.
+func AsRichError(err error) *RichError {
+	if err == nil {
+		return nil
+	}
+
+	var rich *RichError
+	if errors.As(err, &rich) {
+		return rich
+	}
+
+	rich = &RichError{
+		Code:    CodeUnknown,
+		Summary: security.RedactSecrets(err.Error()),
+		Err:     err,
+	}
+
+	knownErrorsMu.RLock()
+	defer knownErrorsMu.RUnlock()
+	for _, k := range knownErrors {
+		if errors.Is(err, k.target) {
+			rich.Code = k.code
+			rich.Suggestions = append([]string(nil), k.suggestions...)
+			rich.DocsURL = k.docsURL
+			break
+		}
+	}
+	return rich
+}
+
+// PresentError prints err for the user. In default format it renders the
+// summary, details, suggestions, and docs link as a formatted block; in JSON
+// format it prints {"error": {...}} with the RichError fields. Nil errors
+// print nothing.
+//
+// Example:
+//
+//	if err := run(); err != nil {
+//	    cliout.PresentError(err)
+//	    os.Exit(1)
+//	}
+func PresentError(err error) {
+	rich := AsRichError(err)
+	if rich == nil {
+		return
+	}
+	if IsNDJSON() {
+		emitEvent(Event{Type: EventError, Message: rich.Summary, Data: rich})
+		return
+	}
+
+	_ = Print(map[string]*RichError{"error": rich}, func() {
+		t := CurrentTheme()
+		cross := getIcon(t.ErrorSymbol, ASCIICross)
+		fmt.Printf("%s%s Error:%s %s\n", t.Error, cross, Reset, rich.Summary)
+		if rich.Details != "" {
+			fmt.Printf("   %s\n", rich.Details)
+		}
+		if len(rich.Suggestions) > 0 {
+			fmt.Println()
+			bulb := getIcon(IconBulb, "[?]")
+			fmt.Printf("%s %sSuggested actions:%s\n", bulb, Bold, Reset)
+			arrow := getIcon(SymbolArrow, ASCIIArrow)
+			for _, s := range rich.Suggestions {
+				fmt.Printf("   %s%s%s %s\n", t.Accent, arrow, Reset, s)
+			}
+		}
+		if rich.DocsURL != "" {
+			fmt.Printf("\n   %sDocs:%s %s\n", Dim, Reset, URL(rich.DocsURL))
+		}
+		if rich.Code != CodeUnknown && rich.Code != "" {
+			fmt.Printf("   %s(%s)%s\n", Dim, rich.Code, Reset)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package cliout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-core/security"
+)
+
+func TestAsRichError(t *testing.T) {
+	custom := &RichError{Code: "CUSTOM", Summary: "custom failure"}
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantSugg   bool
+		wantSumSub string
+	}{
+		{"rich error", fmt.Errorf("wrapped: %w", custom), "CUSTOM", false, "custom failure"},
+		{"path traversal", fmt.Errorf("load config: %w", security.ErrPathTraversal), CodePathTraversal, true, "path traversal"},
+		{"service name", security.ErrInvalidServiceName, CodeInvalidServiceName, true, "invalid service name"},
+		{"timeout", fmt.Errorf("deploy: %w", context.DeadlineExceeded), CodeTimeout, true, "deadline exceeded"},
+		{"canceled", context.Canceled, CodeCanceled, false, "canceled"},
+		{"unknown", errors.New("boom"), CodeUnknown, false, "boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rich := AsRichError(tt.err)
+			if rich.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", rich.Code, tt.wantCode)
+			}
+			if got := len(rich.Suggestions) > 0; got != tt.wantSugg {
+				t.Errorf("has suggestions = %v, want %v", got, tt.wantSugg)
+			}
+			if !strings.Contains(rich.Summary, tt.wantSumSub) {
+				t.Errorf("Summary = %q, want it to contain %q", rich.Summary, tt.wantSumSub)
+			}
+			if !errors.Is(rich, tt.err) && rich != custom {
+				t.Error("expected RichError to wrap the original error")
+			}
+		})
+	}
+
+	if AsRichError(nil) != nil {
+		t.Error("expected nil for nil error")
+	}
+}
+
+func TestAsRichError_RedactsSecrets(t *testing.T) {
+	rich := AsRichError(errors.New("request failed: password=hunter2"))
+	if strings.Contains(rich.Summary, "hunter2") {
+		t.Errorf("expected secret redacted, got %q", rich.Summary)
+	}
+}
+
+func TestRegisterError(t *testing.T) {
+	errNotLoggedIn := errors.New("not logged in")
+	RegisterError(errNotLoggedIn, "NOT_LOGGED_IN", "https://example.com/auth", "Run 'azd auth login'")
+
+	rich := AsRichError(fmt.Errorf("list: %w", errNotLoggedIn))
+	if rich.Code != "NOT_LOGGED_IN" || rich.DocsURL != "https://example.com/auth" {
+		t.Errorf("registered error not applied: %+v", rich)
+	}
+	if len(rich.Suggestions) != 1 || rich.Suggestions[0] != "Run 'azd auth login'" {
+		t.Errorf("Suggestions = %v", rich.Suggestions)
+	}
+}
+
+func TestRichErrorError(t *testing.T) {
+	err := &RichError{Summary: "deploy failed", Details: "quota exceeded"}
+	if got := err.Error(); got != "deploy failed: quota exceeded" {
+		t.Errorf("Error() = %q", got)
+	}
+	err.Details = ""
+	if got := err.Error(); got != "deploy failed" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestPresentError_Default(t *testing.T) {
+	_ = SetFormat("default")
+	err := &RichError{
+		Code:        "QUOTA",
+		Summary:     "deploy failed",
+		Details:     "quota exceeded in eastus",
+		Suggestions: []string{"Request a quota increase", "Try another region"},
+		DocsURL:     "https://example.com/quota",
+	}
+
+	output := captureOutput(t, func() { PresentError(err) })
+	for _, want := range []string{"Error:", "deploy failed", "quota exceeded in eastus", "Suggested actions:", "Request a quota increase", "Try another region", "https://example.com/quota", "(QUOTA)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	if output := captureOutput(t, func() { PresentError(errors.New("plain")) }); strings.Contains(output, CodeUnknown) {
+		t.Errorf("unknown code should not be shown:\n%s", output)
+	}
+	if output := captureOutput(t, func() { PresentError(nil) }); output != "" {
+		t.Errorf("expected no output for nil error, got %q", output)
+	}
+}
+
+func TestPresentError_JSON(t *testing.T) {
+	if err := SetFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetFormat("default") }()
+
+	output := captureOutput(t, func() { PresentError(security.ErrPathTraversal) })
+	var got struct {
+		Error RichError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", output, err)
+	}
+	if got.Error.Code != CodePathTraversal || got.Error.Summary != "path traversal detected" || len(got.Error.Suggestions) == 0 {
+		t.Errorf("unexpected JSON error: %+v", got.Error)
+	}
+}
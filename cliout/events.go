@@ -0,0 +1,78 @@
+package cliout
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"time"
+)
+
+// Event types emitted in NDJSON mode.
+const (
+	EventSuccess = "success"
+	EventError   = "error"
+	EventWarning = "warning"
+	EventInfo    = "info"
+	EventStep    = "step"
+	EventSection = "section"
+	EventResult  = "result"
+)
+
+// Event is a single line of NDJSON output. Section is the text of the most
+// recent Section or Header call, so consumers can group events without
+// tracking state themselves.
+type Event struct {
+	Type      string      `json:"type"`
+	Message   string      `json:"message,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Section   string      `json:"section,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+var (
+	// currentSection is the section context attached to events. Guarded by mu.
+	currentSection string
+
+	// eventNow returns the event timestamp; replaced in tests.
+	eventNow = time.Now
+
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+)
+
+// IsNDJSON returns true if the output format is NDJSON (one JSON event per
+// line).
+func IsNDJSON() bool {
+	return globalFormat == FormatNDJSON
+}
+
+// isMachineReadable reports whether output is consumed by a program rather
+// than a person, so prompts must not block and decorations are skipped.
+func isMachineReadable() bool {
+	return globalFormat == FormatJSON || globalFormat == FormatNDJSON
+}
+
+// emitEvent writes e as one line of JSON to stdout. ANSI escape sequences
+// from helpers such as Highlight are stripped from the message.
+func emitEvent(e Event) {
+	mu.RLock()
+	e.Section = currentSection
+	mu.RUnlock()
+	e.Message = stripANSI(e.Message)
+	e.Timestamp = eventNow().UTC()
+	_ = json.NewEncoder(os.Stdout).Encode(e)
+}
+
+// setSection records the section context for subsequent events and emits a
+// section event.
+func setSection(text string) {
+	text = stripANSI(text)
+	mu.Lock()
+	currentSection = text
+	mu.Unlock()
+	emitEvent(Event{Type: EventSection, Message: text})
+}
+
+// stripANSI removes ANSI escape sequences from s.
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
@@ -0,0 +1,181 @@
+package cliout
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withNDJSON switches to NDJSON mode with a fixed clock for the test.
+func withNDJSON(t *testing.T) time.Time {
+	t.Helper()
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	oldNow := eventNow
+	eventNow = func() time.Time { return fixed }
+	if err := SetFormat("ndjson"); err != nil {
+		t.Fatalf("SetFormat(ndjson) failed: %v", err)
+	}
+	t.Cleanup(func() {
+		eventNow = oldNow
+		globalFormat = FormatDefault
+		mu.Lock()
+		currentSection = ""
+		mu.Unlock()
+	})
+	return fixed
+}
+
+func decodeEvents(t *testing.T, output string) []Event {
+	t.Helper()
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line %q is not JSON: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestSetFormatNDJSON(t *testing.T) {
+	withNDJSON(t)
+	if GetFormat() != FormatNDJSON || !IsNDJSON() {
+		t.Errorf("GetFormat() = %v, want ndjson", GetFormat())
+	}
+	if IsJSON() {
+		t.Error("IsJSON() should be false in NDJSON mode")
+	}
+}
+
+func TestNDJSONEvents(t *testing.T) {
+	fixed := withNDJSON(t)
+
+	output := captureOutput(t, func() {
+		Success("before %s", "section")
+		Section("🔧", "Build")
+		Step("📦", "compiling %d files", 3)
+		Warning("slow disk")
+		Info("using %s", Highlight("cache"))
+		Header("Deploy")
+		Error("deploy failed")
+		ItemSuccess("web")
+	})
+
+	want := []Event{
+		{Type: EventSuccess, Message: "before section"},
+		{Type: EventSection, Message: "Build", Section: "Build"},
+		{Type: EventStep, Message: "compiling 3 files", Section: "Build"},
+		{Type: EventWarning, Message: "slow disk", Section: "Build"},
+		{Type: EventInfo, Message: "using cache", Section: "Build"},
+		{Type: EventSection, Message: "Deploy", Section: "Deploy"},
+		{Type: EventError, Message: "deploy failed", Section: "Deploy"},
+		{Type: EventSuccess, Message: "web", Section: "Deploy"},
+	}
+	events := decodeEvents(t, output)
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d:\n%s", len(events), len(want), output)
+	}
+	for i, w := range want {
+		got := events[i]
+		if got.Type != w.Type || got.Message != w.Message || got.Section != w.Section {
+			t.Errorf("event %d = %+v, want %+v", i, got, w)
+		}
+		if !got.Timestamp.Equal(fixed) {
+			t.Errorf("event %d timestamp = %v, want %v", i, got.Timestamp, fixed)
+		}
+	}
+}
+
+func TestNDJSONSuppressesDecorations(t *testing.T) {
+	withNDJSON(t)
+
+	output := captureOutput(t, func() {
+		CommandHeader("up", "")
+		Item("item")
+		Bullet("bullet")
+		Divider()
+		Newline()
+		Hint("press Ctrl+C")
+		Plain("plain")
+		Label("Port", "8080")
+		LabelColored("Status", "ok", Green)
+		Table([]string{"Name"}, []TableRow{{"Name": "web"}})
+	})
+	if output != "" {
+		t.Errorf("expected no output, got %q", output)
+	}
+}
+
+func TestNDJSONNoANSI(t *testing.T) {
+	withNDJSON(t)
+
+	output := captureOutput(t, func() {
+		Success("%s ready at %s", Emphasize("web"), URL("http://localhost:8080"))
+		Phase("Starting services...")
+	})
+	if strings.Contains(output, "\033[") {
+		t.Errorf("NDJSON output contains ANSI escapes: %q", output)
+	}
+	events := decodeEvents(t, output)
+	if len(events) != 2 || events[0].Message != "web ready at http://localhost:8080" || events[1].Type != EventStep {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestNDJSONPrintAndPresentError(t *testing.T) {
+	withNDJSON(t)
+
+	output := captureOutput(t, func() {
+		_ = Print(map[string]int{"count": 2}, func() { Plain("should not print") })
+		PresentError(errors.New("boom"))
+	})
+	events := decodeEvents(t, output)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2:\n%s", len(events), output)
+	}
+	if events[0].Type != EventResult {
+		t.Errorf("events[0].Type = %q, want result", events[0].Type)
+	}
+	if data, ok := events[0].Data.(map[string]interface{}); !ok || data["count"] != float64(2) {
+		t.Errorf("events[0].Data = %#v", events[0].Data)
+	}
+	if events[1].Type != EventError || events[1].Message != "boom" || events[1].Data == nil {
+		t.Errorf("events[1] = %+v", events[1])
+	}
+}
+
+func TestNDJSONPromptsNonInteractive(t *testing.T) {
+	withNDJSON(t)
+	withPromptInput(t, "")
+	t.Setenv(EnvPromptAnswers, "")
+	resetPromptEnv()
+
+	if !Confirm("Continue?") {
+		t.Error("Confirm should return true in NDJSON mode")
+	}
+	if got, err := Input("Name", "app"); err != nil || got != "app" {
+		t.Errorf("Input = %q, %v; want default", got, err)
+	}
+	if _, err := Select("Region", []string{"eastus"}); !errors.Is(err, ErrNoAnswer) {
+		t.Errorf("Select error = %v, want ErrNoAnswer", err)
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	tests := map[string]string{
+		"plain":                        "plain",
+		Bold + Green + "ok" + Reset:    "ok",
+		"\033[38;5;208morange\033[0m!": "orange!",
+	}
+	for in, want := range tests {
+		if got := stripANSI(in); got != want {
+			t.Errorf("stripANSI(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
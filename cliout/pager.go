@@ -0,0 +1,129 @@
+package cliout
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// EnvPager overrides the pager command used by Page, for example
+// AZD_PAGER="less -S". When unset, PAGER is used, then "less -FRX" on Unix
+// or "more" on Windows.
+const EnvPager = "AZD_PAGER"
+
+var (
+	// pagingDisabled turns Page into a plain write; see SetPaging.
+	pagingDisabled bool
+
+	// stdoutIsTerminal and terminalHeight are replaced in tests.
+	stdoutIsTerminal = func() bool { return term.IsTerminal(int(os.Stdout.Fd())) }
+	terminalHeight   = func() int {
+		if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+			return lines
+		}
+		if _, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			return h
+		}
+		return 0
+	}
+)
+
+// SetPaging enables or disables Page. Paging is enabled by default; tools
+// typically disable it for a --no-pager flag.
+func SetPaging(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	pagingDisabled = !enabled
+}
+
+// Page writes content to stdout through a pager when it is taller than the
+// terminal (the LINES environment variable, if set, overrides the detected
+// height), so long results don't scroll away. Content is written directly
+// when stdout is not a terminal, paging is disabled, the output format is
+// JSON, or it fits on one screen. If the pager cannot be started, content is
+// written directly as well. In NDJSON mode nothing is written.
+//
+// Example:
+//
+//	var b strings.Builder
+//	for _, line := range logLines {
+//	    fmt.Fprintln(&b, line)
+//	}
+//	if err := cliout.Page(b.String()); err != nil {
+//	    return err
+//	}
+func Page(content string) error {
+	if IsNDJSON() {
+		return nil
+	}
+	if !shouldPage(content) {
+		return writeStdout(content)
+	}
+
+	args := pagerCommand()
+	if len(args) == 0 {
+		return writeStdout(content)
+	}
+	// #nosec G204 -- the pager command comes from the user's own environment
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return writeStdout(content)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("pager %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// PageTable renders a table like Table and shows it through Page.
+func PageTable(headers []string, rows []TableRow) error {
+	if len(rows) == 0 || IsNDJSON() {
+		return nil
+	}
+	var buf bytes.Buffer
+	writeTable(&buf, headers, rows)
+	return Page(buf.String())
+}
+
+// shouldPage reports whether content needs a pager.
+func shouldPage(content string) bool {
+	mu.RLock()
+	disabled := pagingDisabled
+	mu.RUnlock()
+	if disabled || IsJSON() || !stdoutIsTerminal() {
+		return false
+	}
+	height := terminalHeight()
+	return height <= 0 || strings.Count(content, "\n") >= height
+}
+
+// pagerCommand returns the pager command and its arguments.
+func pagerCommand() []string {
+	for _, env := range []string{EnvPager, "PAGER"} {
+		if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+			return strings.Fields(value)
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"more"}
+	}
+	if _, err := exec.LookPath("less"); err == nil {
+		return []string{"less", "-FRX"}
+	}
+	return []string{"more"}
+}
+
+func writeStdout(content string) error {
+	_, err := io.WriteString(os.Stdout, content)
+	return err
+}
@@ -0,0 +1,138 @@
+package cliout
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+// TestPagerHelperProcess is not a real test. Page runs it as the pager via
+// AZD_PAGER when CLIOUT_PAGER_HELPER=1; it copies stdin to stdout with a
+// marker so tests can tell the pager was used.
+func TestPagerHelperProcess(t *testing.T) {
+	if os.Getenv("CLIOUT_PAGER_HELPER") != "1" {
+		return
+	}
+	data, _ := io.ReadAll(os.Stdin)
+	_, _ = os.Stdout.WriteString("PAGED:" + string(data))
+	os.Exit(0)
+}
+
+// withTerminal pretends stdout is a terminal of the given height and routes
+// the pager to TestPagerHelperProcess.
+func withTerminal(t *testing.T, height int) {
+	t.Helper()
+	oldTTY, oldHeight := stdoutIsTerminal, terminalHeight
+	stdoutIsTerminal = func() bool { return true }
+	terminalHeight = func() int { return height }
+	t.Cleanup(func() {
+		stdoutIsTerminal, terminalHeight = oldTTY, oldHeight
+		SetPaging(true)
+		globalFormat = FormatDefault
+	})
+	t.Setenv("CLIOUT_PAGER_HELPER", "1")
+	t.Setenv(EnvPager, os.Args[0]+" -test.run=^TestPagerHelperProcess$")
+}
+
+func longContent(lines int) string {
+	return strings.Repeat("row\n", lines)
+}
+
+func TestPageUsesPager(t *testing.T) {
+	withTerminal(t, 10)
+
+	output := captureOutput(t, func() {
+		if err := Page(longContent(50)); err != nil {
+			t.Errorf("Page() error = %v", err)
+		}
+	})
+	if output != "PAGED:"+longContent(50) {
+		t.Errorf("Page() output = %q, want paged content", output)
+	}
+}
+
+func TestPageWritesDirectly(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T)
+		lines int
+	}{
+		{"fits on screen", func(t *testing.T) {}, 5},
+		{"not a terminal", func(t *testing.T) { stdoutIsTerminal = func() bool { return false } }, 50},
+		{"paging disabled", func(t *testing.T) { SetPaging(false) }, 50},
+		{"json format", func(t *testing.T) { globalFormat = FormatJSON }, 50},
+		{"pager missing", func(t *testing.T) { t.Setenv(EnvPager, "azd-no-such-pager") }, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTerminal(t, 10)
+			tt.setup(t)
+
+			content := longContent(tt.lines)
+			output := captureOutput(t, func() {
+				if err := Page(content); err != nil {
+					t.Errorf("Page() error = %v", err)
+				}
+			})
+			if output != content {
+				t.Errorf("Page() output = %q, want %q", output, content)
+			}
+		})
+	}
+}
+
+func TestPageNDJSON(t *testing.T) {
+	withNDJSON(t)
+	output := captureOutput(t, func() {
+		if err := Page("hello\n"); err != nil {
+			t.Errorf("Page() error = %v", err)
+		}
+	})
+	if output != "" {
+		t.Errorf("Page() in NDJSON mode wrote %q", output)
+	}
+}
+
+func TestPageTable(t *testing.T) {
+	withTerminal(t, 3)
+
+	rows := []TableRow{{"Name": "web"}, {"Name": "api"}, {"Name": "db"}}
+	direct := captureOutput(t, func() { Table([]string{"Name"}, rows) })
+	paged := captureOutput(t, func() {
+		if err := PageTable([]string{"Name"}, rows); err != nil {
+			t.Errorf("PageTable() error = %v", err)
+		}
+	})
+	if paged != "PAGED:"+direct {
+		t.Errorf("PageTable() output = %q, want %q", paged, "PAGED:"+direct)
+	}
+}
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv(EnvPager, "")
+	t.Setenv("PAGER", "most -s")
+	if got := pagerCommand(); strings.Join(got, " ") != "most -s" {
+		t.Errorf("pagerCommand() = %v, want PAGER value", got)
+	}
+
+	t.Setenv(EnvPager, "less -S")
+	if got := pagerCommand(); strings.Join(got, " ") != "less -S" {
+		t.Errorf("pagerCommand() = %v, want %s value", got, EnvPager)
+	}
+
+	t.Setenv(EnvPager, "")
+	t.Setenv("PAGER", "")
+	if got := pagerCommand(); len(got) == 0 {
+		t.Error("pagerCommand() returned no default pager")
+	}
+}
+
+func TestTerminalHeightFromLINES(t *testing.T) {
+	testutil.WithTermSize(t, 80, 42)
+	if got := terminalHeight(); got != 42 {
+		t.Errorf("terminalHeight() = %d, want 42 from LINES", got)
+	}
+}
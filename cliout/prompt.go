@@ -0,0 +1,469 @@
+package cliout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/jongio/azd-core/security"
+)
+
+// EnvPromptAnswers pre-seeds prompt answers for unattended runs. Its value is
+// either the path to a JSON answers file or an inline JSON object, mapping
+// prompt messages to answers:
+//
+//	AZD_PROMPT_ANSWERS='{"Deploy to production?": "yes", "Select a region": "eastus"}'
+const EnvPromptAnswers = "AZD_PROMPT_ANSWERS"
+
+// ErrNoAnswer is returned by Select, Input, and typed-phrase
+// ConfirmWithOptions prompts in JSON and NDJSON modes when no answer is
+// available and the prompt has no default.
+var ErrNoAnswer = errors.New("no answer available for non-interactive prompt")
+
+// promptState holds pre-seeded answers and the recording target.
+var (
+	promptMu      sync.Mutex
+	promptAnswers map[string]string
+	// promptEnvLoaded records whether EnvPromptAnswers has been read.
+	promptEnvLoaded bool
+	promptRecordTo  string
+	promptRecorded  map[string]string
+	// promptInput replaces os.Stdin as the source of prompt input in tests.
+	promptInput io.Reader
+	// promptPending is a read abandoned by a timed-out prompt. The next
+	// prompt takes its line instead of starting a second concurrent read.
+	promptPending chan promptLine
+)
+
+// promptLine is the result of reading one line of prompt input.
+type promptLine struct {
+	text string
+	err  error
+}
+
+// errPromptTimeout is returned by readLineWithin when the timeout passes.
+var errPromptTimeout = errors.New("prompt timed out")
+
+// ConfirmOptions configures ConfirmWithOptions.
+type ConfirmOptions struct {
+	// Default is the answer used when the user just presses Enter, when
+	// input ends, when Timeout passes, and in JSON and NDJSON modes.
+	Default bool
+	// Timeout, if positive, answers Default after this long without input,
+	// so unattended runs do not hang.
+	Timeout time.Duration
+	// RequireTypedPhrase, if set, requires the user to type this exact text
+	// (for example the name of the resource being deleted) to confirm. Any
+	// other input, a timeout, or the end of input declines, and Default is
+	// ignored. In JSON and NDJSON modes ErrNoAnswer is returned unless a
+	// pre-seeded answer equal to the phrase exists.
+	RequireTypedPhrase string
+}
+
+// LoadPromptAnswers reads pre-seeded prompt answers from a JSON file mapping
+// prompt messages to answers. Values may be strings, booleans, or numbers.
+// Loaded answers replace any previously set, including those from
+// EnvPromptAnswers.
+func LoadPromptAnswers(path string) error {
+	if err := security.ValidatePath(path); err != nil {
+		return fmt.Errorf("invalid answers file path: %w", err)
+	}
+	// #nosec G304 -- path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read answers file: %w", err)
+	}
+	answers, err := parsePromptAnswers(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse answers file %s: %w", path, err)
+	}
+	SetPromptAnswers(answers)
+	return nil
+}
+
+// SetPromptAnswers replaces the pre-seeded prompt answers. Passing nil clears
+// them, so prompts read from the terminal again.
+func SetPromptAnswers(answers map[string]string) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	promptAnswers = make(map[string]string, len(answers))
+	for k, v := range answers {
+		promptAnswers[k] = v
+	}
+	promptEnvLoaded = true
+}
+
+// RecordPromptAnswers writes every answered prompt to path as a JSON answers
+// file that can be replayed with LoadPromptAnswers or EnvPromptAnswers. The
+// file is rewritten after each prompt with owner-only permissions, since
+// answers may be sensitive. An empty path stops recording.
+func RecordPromptAnswers(path string) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	promptRecordTo = path
+	promptRecorded = make(map[string]string)
+}
+
+// Confirm prompts the user for confirmation and returns true if they confirm.
+// A pre-seeded answer is used if available; otherwise returns true
+// immediately in JSON and NDJSON modes (non-interactive). The prompt displays
+// the message and waits for y/n input.
+func Confirm(message string) bool {
+	if answer, ok := lookupAnswer(message); ok {
+		confirmed := isYes(answer)
+		recordAnswer(message, strconv.FormatBool(confirmed))
+		return confirmed
+	}
+	if isMachineReadable() {
+		return true // Non-interactive mode, assume yes
+	}
+	fmt.Printf("%s%s%s [y/N]: ", BrightYellow, message, Reset)
+	response, err := readLine()
+	if err != nil {
+		return false // On read error, default to no
+	}
+	confirmed := isYes(response)
+	recordAnswer(message, strconv.FormatBool(confirmed))
+	return confirmed
+}
+
+// ConfirmWithOptions is Confirm with a configurable default, a timeout, and
+// an optional phrase the user must type for destructive operations. A
+// pre-seeded answer is used if available. The prompt shows [Y/n] or [y/N]
+// according to opts.Default.
+//
+// Example:
+//
+//	ok, err := cliout.ConfirmWithOptions("Delete resource group rg-dev?", cliout.ConfirmOptions{
+//	    RequireTypedPhrase: "rg-dev",
+//	})
+//	if err != nil || !ok {
+//	    return fmt.Errorf("deletion cancelled")
+//	}
+func ConfirmWithOptions(message string, opts ConfirmOptions) (bool, error) {
+	phrase := strings.TrimSpace(opts.RequireTypedPhrase)
+	if answer, ok := lookupAnswer(message); ok {
+		if phrase != "" {
+			confirmed := strings.TrimSpace(answer) == phrase
+			recordAnswer(message, answer)
+			return confirmed, nil
+		}
+		confirmed := isYes(answer)
+		recordAnswer(message, strconv.FormatBool(confirmed))
+		return confirmed, nil
+	}
+	if isMachineReadable() {
+		if phrase != "" {
+			return false, fmt.Errorf("confirm %q: %w", message, ErrNoAnswer)
+		}
+		return opts.Default, nil
+	}
+
+	if phrase != "" {
+		fmt.Printf("%s%s%s\nType %s%s%s to confirm: ", BrightYellow, message, Reset, Bold, phrase, Reset)
+	} else if opts.Default {
+		fmt.Printf("%s%s%s [Y/n]: ", BrightYellow, message, Reset)
+	} else {
+		fmt.Printf("%s%s%s [y/N]: ", BrightYellow, message, Reset)
+	}
+
+	response, err := readLineWithin(opts.Timeout)
+	switch {
+	case errors.Is(err, errPromptTimeout):
+		fmt.Println()
+		if phrase != "" {
+			Info("No answer after %s, cancelled", opts.Timeout)
+			return false, nil
+		}
+		Info("No answer after %s, using default (%s)", opts.Timeout, yesNo(opts.Default))
+		return opts.Default, nil
+	case errors.Is(err, io.EOF):
+		return opts.Default && phrase == "", nil
+	case err != nil:
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	var confirmed bool
+	switch {
+	case phrase != "":
+		confirmed = response == phrase
+		if !confirmed && response != "" {
+			Warning("Input did not match %q, cancelled", phrase)
+		}
+		recordAnswer(message, response)
+		return confirmed, nil
+	case response == "":
+		confirmed = opts.Default
+	default:
+		confirmed = isYes(response)
+	}
+	recordAnswer(message, strconv.FormatBool(confirmed))
+	return confirmed, nil
+}
+
+// Select prompts the user to choose one of options and returns it. The
+// answer may be given as the option text (case-insensitive) or its 1-based
+// number. A pre-seeded answer is used if available; in JSON and NDJSON modes
+// without one, ErrNoAnswer is returned.
+//
+// Example:
+//
+//	region, err := cliout.Select("Select a region", []string{"eastus", "westus2"})
+func Select(message string, options []string) (string, error) {
+	if len(options) == 0 {
+		return "", fmt.Errorf("select %q: no options", message)
+	}
+
+	if answer, ok := lookupAnswer(message); ok {
+		choice, err := matchOption(answer, options)
+		if err != nil {
+			return "", fmt.Errorf("pre-seeded answer for %q: %w", message, err)
+		}
+		recordAnswer(message, choice)
+		return choice, nil
+	}
+	if isMachineReadable() {
+		return "", fmt.Errorf("select %q: %w", message, ErrNoAnswer)
+	}
+
+	fmt.Printf("%s%s%s\n", BrightYellow, message, Reset)
+	for i, option := range options {
+		fmt.Printf("  %s%d)%s %s\n", Dim, i+1, Reset, option)
+	}
+	fmt.Printf("Enter choice [1-%d]: ", len(options))
+	response, err := readLine()
+	if err != nil {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	choice, err := matchOption(response, options)
+	if err != nil {
+		return "", err
+	}
+	recordAnswer(message, choice)
+	return choice, nil
+}
+
+// Input prompts the user for a line of text and returns it, or defaultValue
+// if the user enters nothing. A pre-seeded answer is used if available; in
+// JSON and NDJSON modes without one, defaultValue is returned, or ErrNoAnswer
+// if it is empty.
+func Input(message, defaultValue string) (string, error) {
+	if answer, ok := lookupAnswer(message); ok {
+		recordAnswer(message, answer)
+		return answer, nil
+	}
+	if isMachineReadable() {
+		if defaultValue == "" {
+			return "", fmt.Errorf("input %q: %w", message, ErrNoAnswer)
+		}
+		return defaultValue, nil
+	}
+
+	if defaultValue != "" {
+		fmt.Printf("%s%s%s %s[%s]%s: ", BrightYellow, message, Reset, Dim, defaultValue, Reset)
+	} else {
+		fmt.Printf("%s%s%s: ", BrightYellow, message, Reset)
+	}
+	response, err := readLine()
+	if err != nil && !(errors.Is(err, io.EOF) && defaultValue != "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	if response == "" {
+		response = defaultValue
+	}
+	recordAnswer(message, response)
+	return response, nil
+}
+
+// lookupAnswer returns the pre-seeded answer for message, loading
+// EnvPromptAnswers on first use.
+func lookupAnswer(message string) (string, bool) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	if !promptEnvLoaded {
+		promptEnvLoaded = true
+		promptAnswers = loadEnvPromptAnswers()
+	}
+	answer, ok := promptAnswers[message]
+	return answer, ok
+}
+
+// loadEnvPromptAnswers reads answers from EnvPromptAnswers, warning on stderr
+// if they cannot be loaded.
+func loadEnvPromptAnswers() map[string]string {
+	value := strings.TrimSpace(os.Getenv(EnvPromptAnswers))
+	if value == "" {
+		return nil
+	}
+
+	data := []byte(value)
+	if !strings.HasPrefix(value, "{") {
+		if err := security.ValidatePath(value); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", EnvPromptAnswers, err)
+			return nil
+		}
+		// #nosec G304 -- path validated by security.ValidatePath
+		fileData, err := os.ReadFile(value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", EnvPromptAnswers, err)
+			return nil
+		}
+		data = fileData
+	}
+
+	answers, err := parsePromptAnswers(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", EnvPromptAnswers, err)
+		return nil
+	}
+	return answers
+}
+
+// parsePromptAnswers decodes a JSON object of answers, converting scalar
+// values to strings.
+func parsePromptAnswers(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	answers := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			answers[k] = v
+		case bool, float64:
+			answers[k] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("answer for %q must be a string, boolean, or number", k)
+		}
+	}
+	return answers, nil
+}
+
+// recordAnswer saves an answer when recording is enabled, warning on stderr
+// if the file cannot be written.
+func recordAnswer(message, answer string) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	if promptRecordTo == "" {
+		return
+	}
+	promptRecorded[message] = answer
+	data, err := json.MarshalIndent(promptRecorded, "", "  ")
+	if err == nil {
+		err = fileutil.AtomicWriteFile(promptRecordTo, data, 0600)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record prompt answers: %v\n", err)
+	}
+}
+
+// readLine reads one line from the prompt input, without the line ending.
+func readLine() (string, error) {
+	return readLineWithin(0)
+}
+
+// readLineWithin is readLine with a timeout; timeout <= 0 waits forever.
+// On timeout it returns errPromptTimeout and leaves the read running for the
+// next prompt, since a blocked read cannot be canceled.
+func readLineWithin(timeout time.Duration) (string, error) {
+	promptMu.Lock()
+	pending := promptPending
+	promptPending = nil
+	promptMu.Unlock()
+
+	if pending == nil {
+		if timeout <= 0 {
+			return readPromptLine()
+		}
+		pending = make(chan promptLine, 1)
+		go func() {
+			text, err := readPromptLine()
+			pending <- promptLine{text: text, err: err}
+		}()
+	}
+
+	if timeout <= 0 {
+		line := <-pending
+		return line.text, line.err
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case line := <-pending:
+		return line.text, line.err
+	case <-timer.C:
+		promptMu.Lock()
+		promptPending = pending
+		promptMu.Unlock()
+		return "", errPromptTimeout
+	}
+}
+
+// readPromptLine reads one line from the current os.Stdin. It reads one byte
+// at a time rather than through a buffer, so input after the newline is left
+// for whatever reads stdin next, such as a child process or a later prompt.
+func readPromptLine() (string, error) {
+	in := promptInput
+	if in == nil {
+		in = os.Stdin
+	}
+
+	var line strings.Builder
+	b := make([]byte, 1)
+	for {
+		n, err := in.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line.WriteByte(b[0])
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) && line.Len() > 0 {
+				break
+			}
+			return "", err
+		}
+	}
+	return strings.TrimSpace(line.String()), nil
+}
+
+// yesNo formats a confirmation default for display.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// isYes reports whether answer is an affirmative response.
+func isYes(answer string) bool {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "true":
+		return true
+	}
+	return false
+}
+
+// matchOption resolves answer to one of options by 1-based number or
+// case-insensitive text.
+func matchOption(answer string, options []string) (string, error) {
+	answer = strings.TrimSpace(answer)
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+		return options[n-1], nil
+	}
+	for _, option := range options {
+		if strings.EqualFold(option, answer) {
+			return option, nil
+		}
+	}
+	return "", fmt.Errorf("invalid choice %q: must be one of %s", answer, strings.Join(options, ", "))
+}
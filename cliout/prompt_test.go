@@ -0,0 +1,425 @@
+package cliout
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withPromptInput resets prompt state and feeds input to prompts for the
+// duration of the test.
+func withPromptInput(t *testing.T, input string) {
+	t.Helper()
+	oldInput := promptInput
+	promptInput = strings.NewReader(input)
+	promptPending = nil
+	SetPromptAnswers(nil)
+	RecordPromptAnswers("")
+	t.Cleanup(func() {
+		promptInput = oldInput
+		promptPending = nil
+		SetPromptAnswers(nil)
+		RecordPromptAnswers("")
+		globalFormat = FormatDefault
+	})
+}
+
+func TestConfirmInteractive(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		withPromptInput(t, tt.input)
+		var got bool
+		captureOutput(t, func() { got = Confirm("Continue?") })
+		if got != tt.want {
+			t.Errorf("Confirm with input %q = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmPreseeded(t *testing.T) {
+	withPromptInput(t, "y\n")
+	SetPromptAnswers(map[string]string{"Delete everything?": "no"})
+	globalFormat = FormatJSON
+
+	// The answer wins over the JSON-mode default of true.
+	if Confirm("Delete everything?") {
+		t.Error("Confirm should use pre-seeded answer")
+	}
+}
+
+func TestConfirmWithOptionsDefault(t *testing.T) {
+	tests := []struct {
+		input      string
+		defaultYes bool
+		want       bool
+	}{
+		{"\n", true, true},
+		{"", true, true},
+		{"n\n", true, false},
+		{"\n", false, false},
+		{"y\n", false, true},
+	}
+	for _, tt := range tests {
+		withPromptInput(t, tt.input)
+		var got bool
+		var err error
+		out := captureOutput(t, func() {
+			got, err = ConfirmWithOptions("Continue?", ConfirmOptions{Default: tt.defaultYes})
+		})
+		if err != nil || got != tt.want {
+			t.Errorf("input %q, default %v: got %v, %v; want %v", tt.input, tt.defaultYes, got, err, tt.want)
+		}
+		hint := "[y/N]"
+		if tt.defaultYes {
+			hint = "[Y/n]"
+		}
+		if !strings.Contains(out, hint) {
+			t.Errorf("prompt %q should show %s", out, hint)
+		}
+	}
+}
+
+func TestConfirmWithOptionsTypedPhrase(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"rg-dev\n", true},
+		{"  rg-dev  \n", true},
+		{"y\n", false},
+		{"RG-DEV\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		withPromptInput(t, tt.input)
+		var got bool
+		var err error
+		captureOutput(t, func() {
+			got, err = ConfirmWithOptions("Delete rg-dev?", ConfirmOptions{Default: true, RequireTypedPhrase: "rg-dev"})
+		})
+		if err != nil || got != tt.want {
+			t.Errorf("input %q: got %v, %v; want %v", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestConfirmWithOptionsTimeout(t *testing.T) {
+	withPromptInput(t, "")
+	r, w := io.Pipe()
+	t.Cleanup(func() { _ = w.Close() })
+	promptInput = r
+
+	for _, opts := range []ConfirmOptions{
+		{Default: true, Timeout: 20 * time.Millisecond},
+		{Default: true, Timeout: 20 * time.Millisecond, RequireTypedPhrase: "prod"},
+	} {
+		var got bool
+		var err error
+		out := captureOutput(t, func() { got, err = ConfirmWithOptions("Deploy?", opts) })
+		if err != nil || got != (opts.RequireTypedPhrase == "") {
+			t.Errorf("%+v: got %v, %v after timeout", opts, got, err)
+		}
+		if !strings.Contains(out, "No answer after") {
+			t.Errorf("expected timeout notice, got %q", out)
+		}
+	}
+
+	// A line typed after the timeout answers the next prompt instead of
+	// being lost to the abandoned read.
+	go func() { _, _ = io.WriteString(w, "n\n") }()
+	var got bool
+	captureOutput(t, func() { got = Confirm("Deploy?") })
+	if got {
+		t.Error("expected the late answer to be used by the next prompt")
+	}
+}
+
+func TestConfirmWithOptionsNonInteractive(t *testing.T) {
+	withPromptInput(t, "y\n")
+	globalFormat = FormatJSON
+
+	if got, err := ConfirmWithOptions("Continue?", ConfirmOptions{}); err != nil || got {
+		t.Errorf("JSON mode should return the default false, got %v, %v", got, err)
+	}
+	if got, err := ConfirmWithOptions("Continue?", ConfirmOptions{Default: true}); err != nil || !got {
+		t.Errorf("JSON mode should return the default true, got %v, %v", got, err)
+	}
+	if _, err := ConfirmWithOptions("Delete rg-dev?", ConfirmOptions{Default: true, RequireTypedPhrase: "rg-dev"}); !errors.Is(err, ErrNoAnswer) {
+		t.Errorf("expected ErrNoAnswer for a typed phrase without an answer, got %v", err)
+	}
+
+	SetPromptAnswers(map[string]string{"Delete rg-dev?": "rg-dev", "Delete rg-prod?": "yes"})
+	if got, err := ConfirmWithOptions("Delete rg-dev?", ConfirmOptions{RequireTypedPhrase: "rg-dev"}); err != nil || !got {
+		t.Errorf("pre-seeded phrase should confirm, got %v, %v", got, err)
+	}
+	if got, _ := ConfirmWithOptions("Delete rg-prod?", ConfirmOptions{RequireTypedPhrase: "rg-prod"}); got {
+		t.Error("a pre-seeded yes must not satisfy a typed phrase")
+	}
+}
+
+func TestConfirmWithOptionsRecordsPhrase(t *testing.T) {
+	withPromptInput(t, "rg-dev\n")
+	path := filepath.Join(t.TempDir(), "recorded.json")
+	RecordPromptAnswers(path)
+
+	captureOutput(t, func() {
+		_, _ = ConfirmWithOptions("Delete rg-dev?", ConfirmOptions{RequireTypedPhrase: "rg-dev"})
+	})
+	if err := LoadPromptAnswers(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ConfirmWithOptions("Delete rg-dev?", ConfirmOptions{RequireTypedPhrase: "rg-dev"}); !got {
+		t.Error("replayed typed phrase should confirm")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	options := []string{"eastus", "westus2", "centralus"}
+
+	t.Run("by number", func(t *testing.T) {
+		withPromptInput(t, "2\n")
+		var got string
+		var err error
+		out := captureOutput(t, func() { got, err = Select("Select a region", options) })
+		if err != nil || got != "westus2" {
+			t.Errorf("Select() = %q, %v; want westus2", got, err)
+		}
+		if !strings.Contains(out, "3)") || !strings.Contains(out, "centralus") {
+			t.Errorf("options not listed in output: %q", out)
+		}
+	})
+
+	t.Run("by name", func(t *testing.T) {
+		withPromptInput(t, "CentralUS\n")
+		var got string
+		captureOutput(t, func() { got, _ = Select("Select a region", options) })
+		if got != "centralus" {
+			t.Errorf("Select() = %q, want centralus", got)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		withPromptInput(t, "9\n")
+		var err error
+		captureOutput(t, func() { _, err = Select("Select a region", options) })
+		if err == nil {
+			t.Error("Select() with invalid choice should error")
+		}
+	})
+
+	t.Run("preseeded", func(t *testing.T) {
+		withPromptInput(t, "")
+		SetPromptAnswers(map[string]string{"Select a region": "eastus"})
+		if got, err := Select("Select a region", options); err != nil || got != "eastus" {
+			t.Errorf("Select() = %q, %v; want eastus", got, err)
+		}
+	})
+
+	t.Run("preseeded invalid", func(t *testing.T) {
+		withPromptInput(t, "")
+		SetPromptAnswers(map[string]string{"Select a region": "mars"})
+		if _, err := Select("Select a region", options); err == nil {
+			t.Error("Select() with invalid pre-seeded answer should error")
+		}
+	})
+
+	t.Run("json mode without answer", func(t *testing.T) {
+		withPromptInput(t, "")
+		globalFormat = FormatJSON
+		if _, err := Select("Select a region", options); !errors.Is(err, ErrNoAnswer) {
+			t.Errorf("Select() error = %v, want ErrNoAnswer", err)
+		}
+	})
+
+	t.Run("no options", func(t *testing.T) {
+		withPromptInput(t, "")
+		if _, err := Select("Select a region", nil); err == nil {
+			t.Error("Select() with no options should error")
+		}
+	})
+}
+
+func TestInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		def     string
+		json    bool
+		answers map[string]string
+		want    string
+		wantErr error
+	}{
+		{name: "typed", input: "myapp\n", want: "myapp"},
+		{name: "empty uses default", input: "\n", def: "app", want: "app"},
+		{name: "eof uses default", input: "", def: "app", want: "app"},
+		{name: "preseeded", answers: map[string]string{"Name": "seeded"}, def: "app", want: "seeded"},
+		{name: "json mode default", json: true, def: "app", want: "app"},
+		{name: "json mode no default", json: true, wantErr: ErrNoAnswer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withPromptInput(t, tt.input)
+			if tt.answers != nil {
+				SetPromptAnswers(tt.answers)
+			}
+			if tt.json {
+				globalFormat = FormatJSON
+			}
+			var got string
+			var err error
+			captureOutput(t, func() { got, err = Input("Name", tt.def) })
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Input() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Input() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptAnswersFromEnv(t *testing.T) {
+	t.Run("inline json", func(t *testing.T) {
+		withPromptInput(t, "")
+		t.Setenv(EnvPromptAnswers, `{"Continue?": true, "Name": "ci"}`)
+		resetPromptEnv()
+		if !Confirm("Continue?") {
+			t.Error("Confirm should use inline answer")
+		}
+		if got, _ := Input("Name", ""); got != "ci" {
+			t.Errorf("Input() = %q, want ci", got)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		withPromptInput(t, "")
+		path := filepath.Join(t.TempDir(), "answers.json")
+		if err := os.WriteFile(path, []byte(`{"Select a region": 2}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(EnvPromptAnswers, path)
+		resetPromptEnv()
+		if got, err := Select("Select a region", []string{"eastus", "westus2"}); err != nil || got != "westus2" {
+			t.Errorf("Select() = %q, %v; want westus2", got, err)
+		}
+	})
+}
+
+func TestLoadPromptAnswers(t *testing.T) {
+	withPromptInput(t, "")
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "answers.json")
+	if err := os.WriteFile(valid, []byte(`{"Continue?": "y"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadPromptAnswers(valid); err != nil {
+		t.Fatalf("LoadPromptAnswers() error = %v", err)
+	}
+	if !Confirm("Continue?") {
+		t.Error("Confirm should use loaded answer")
+	}
+
+	invalid := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(invalid, []byte(`{"Continue?": ["y"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadPromptAnswers(invalid); err == nil {
+		t.Error("LoadPromptAnswers() with non-scalar answer should error")
+	}
+	if err := LoadPromptAnswers(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadPromptAnswers() with missing file should error")
+	}
+}
+
+func TestRecordPromptAnswers(t *testing.T) {
+	withPromptInput(t, "y\n2\nmyapp\n")
+	path := filepath.Join(t.TempDir(), "recorded.json")
+	RecordPromptAnswers(path)
+
+	captureOutput(t, func() {
+		Confirm("Continue?")
+		_, _ = Select("Select a region", []string{"eastus", "westus2"})
+		_, _ = Input("Name", "")
+	})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("recorded file not written: %v", err)
+	}
+	var recorded map[string]string
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Continue?": "true", "Select a region": "westus2", "Name": "myapp"}
+	for k, v := range want {
+		if recorded[k] != v {
+			t.Errorf("recorded[%q] = %q, want %q", k, recorded[k], v)
+		}
+	}
+
+	// The recording replays to the same answers.
+	if err := LoadPromptAnswers(path); err != nil {
+		t.Fatal(err)
+	}
+	if !Confirm("Continue?") {
+		t.Error("replayed Confirm should be true")
+	}
+	if got, _ := Select("Select a region", []string{"eastus", "westus2"}); got != "westus2" {
+		t.Errorf("replayed Select() = %q, want westus2", got)
+	}
+}
+
+// resetPromptEnv makes the next prompt re-read EnvPromptAnswers.
+func resetPromptEnv() {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	promptAnswers = nil
+	promptEnvLoaded = false
+}
+
+func TestReadPromptLineLeavesRemainingInput(t *testing.T) {
+	withPromptInput(t, "")
+	promptInput = nil
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = oldStdin
+		_ = r.Close()
+	})
+	if _, err := w.WriteString("yes\nfor the next reader\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	if line, err := readPromptLine(); err != nil || line != "yes" {
+		t.Fatalf("readPromptLine() = %q, %v", line, err)
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil || string(rest) != "for the next reader\n" {
+		t.Errorf("remaining stdin = %q, %v; want the unread line", rest, err)
+	}
+}
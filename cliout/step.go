@@ -0,0 +1,208 @@
+package cliout
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Step statuses recorded in StepRecord.
+const (
+	StepRunning   = "running"
+	StepSucceeded = "succeeded"
+	StepFailed    = "failed"
+)
+
+// StepRecord describes a step started with StartStep. Finished steps are
+// collected for StepRecords and attached as Data to NDJSON step events.
+type StepRecord struct {
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// StepTimer tracks a running step. Create one with StartStep and finish it
+// with Succeed or Fail; only the first call has any effect.
+type StepTimer struct {
+	name  string
+	start time.Time
+
+	once sync.Once
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+var (
+	// stepRecords holds finished steps. Guarded by mu.
+	stepRecords []StepRecord
+
+	// stepNow and stepTick are replaced in tests.
+	stepNow  = time.Now
+	stepTick = 100 * time.Millisecond
+)
+
+// asciiSpinner is used instead of SymbolSpinner on legacy terminals.
+const asciiSpinner = `|/-\`
+
+// StartStep prints name as a running step and starts timing it. On a
+// terminal a spinner with the elapsed time is redrawn until the step
+// finishes; otherwise the step is printed once. Succeed or Fail replaces it
+// with the outcome and the final duration.
+//
+// In JSON mode nothing is printed; finished steps are available from
+// StepRecords for inclusion in the result. In NDJSON mode a "step" event is
+// emitted at the start and a "success" or "error" event at the end, each
+// carrying a StepRecord as data.
+//
+// Example:
+//
+//	step := cliout.StartStep("Building web")
+//	if err := build(ctx); err != nil {
+//	    step.Fail(err)
+//	    return err
+//	}
+//	step.Succeed()
+func StartStep(name string) *StepTimer {
+	s := &StepTimer{name: name, start: stepNow(), stop: make(chan struct{})}
+
+	switch {
+	case IsNDJSON():
+		emitEvent(Event{Type: EventStep, Message: name, Data: s.record(StepRunning, 0, nil)})
+	case IsJSON():
+	case stdoutIsTerminal():
+		s.wg.Add(1)
+		go s.spin()
+	default:
+		fmt.Printf("%s%s%s %s\n", CurrentTheme().Accent, getIcon(SymbolArrow, ASCIIArrow), Reset, name)
+	}
+	return s
+}
+
+// Succeed marks the step as succeeded.
+func (s *StepTimer) Succeed() {
+	s.finish(nil)
+}
+
+// Fail marks the step as failed with err. A nil err still marks the step
+// as failed.
+func (s *StepTimer) Fail(err error) {
+	if err == nil {
+		err = fmt.Errorf("step failed")
+	}
+	s.finish(err)
+}
+
+// Elapsed returns the time since the step started.
+func (s *StepTimer) Elapsed() time.Duration {
+	return stepNow().Sub(s.start)
+}
+
+// StepRecords returns the steps finished since the program started or
+// ResetStepRecords was last called, in the order they finished.
+//
+// Example:
+//
+//	return cliout.Print(map[string]interface{}{
+//	    "services": results,
+//	    "steps":    cliout.StepRecords(),
+//	}, printResults)
+func StepRecords() []StepRecord {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]StepRecord(nil), stepRecords...)
+}
+
+// ResetStepRecords discards the recorded steps.
+func ResetStepRecords() {
+	mu.Lock()
+	stepRecords = nil
+	mu.Unlock()
+}
+
+func (s *StepTimer) finish(err error) {
+	s.once.Do(func() {
+		close(s.stop)
+		s.wg.Wait()
+
+		elapsed := s.Elapsed()
+		status := StepSucceeded
+		if err != nil {
+			status = StepFailed
+		}
+		rec := s.record(status, elapsed, err)
+
+		mu.Lock()
+		stepRecords = append(stepRecords, rec)
+		mu.Unlock()
+
+		switch {
+		case IsNDJSON():
+			eventType := EventSuccess
+			if err != nil {
+				eventType = EventError
+			}
+			emitEvent(Event{Type: eventType, Message: s.name, Data: rec})
+		case IsJSON():
+		default:
+			fmt.Println(s.resultLine(elapsed, err))
+		}
+	})
+}
+
+func (s *StepTimer) record(status string, elapsed time.Duration, err error) StepRecord {
+	rec := StepRecord{
+		Name:       stripANSI(s.name),
+		Status:     status,
+		StartedAt:  s.start.UTC(),
+		DurationMs: elapsed.Milliseconds(),
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	return rec
+}
+
+// spin redraws the step line with a spinner and the elapsed time until the
+// step finishes, then clears the line.
+func (s *StepTimer) spin() {
+	defer s.wg.Done()
+
+	frames := []rune(getIcon(SymbolSpinner, asciiSpinner))
+	ticker := time.NewTicker(stepTick)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		fmt.Fprintf(os.Stdout, "\r\033[K%s%c%s %s %s",
+			CurrentTheme().Accent, frames[frame%len(frames)], Reset, s.name, Muted("(%s)", formatStepDuration(s.Elapsed())))
+		select {
+		case <-s.stop:
+			fmt.Fprint(os.Stdout, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *StepTimer) resultLine(elapsed time.Duration, err error) string {
+	t := CurrentTheme()
+	duration := Muted("(%s)", formatStepDuration(elapsed))
+	if err != nil {
+		cross := getIcon(t.ErrorSymbol, ASCIICross)
+		return fmt.Sprintf("%s%s%s %s %s: %v", t.Error, cross, Reset, s.name, duration, err)
+	}
+	check := getIcon(t.SuccessSymbol, ASCIICheck)
+	return fmt.Sprintf("%s%s%s %s %s", t.Success, check, Reset, s.name, duration)
+}
+
+// formatStepDuration formats d as tenths of a second below a minute and as
+// minutes and seconds above.
+func formatStepDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,151 @@
+package cliout
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withStepClock makes StartStep use a clock that advances by step on every
+// read, and clears the recorded steps afterwards.
+func withStepClock(t *testing.T, step time.Duration) {
+	t.Helper()
+	current := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	oldNow, oldTTY := stepNow, stdoutIsTerminal
+	stepNow = func() time.Time {
+		now := current
+		current = current.Add(step)
+		return now
+	}
+	stdoutIsTerminal = func() bool { return false }
+	t.Cleanup(func() {
+		stepNow, stdoutIsTerminal = oldNow, oldTTY
+		globalFormat = FormatDefault
+		ResetStepRecords()
+	})
+}
+
+func TestStartStepDefault(t *testing.T) {
+	withStepClock(t, 1500*time.Millisecond)
+
+	output := captureOutput(t, func() {
+		StartStep("Building web").Succeed()
+		step := StartStep("Deploying api")
+		step.Fail(errors.New("quota exceeded"))
+		step.Succeed() // ignored after Fail
+	})
+
+	lines := strings.Split(strings.TrimSpace(stripANSI(output)), "\n")
+	want := []string{
+		"Building web",
+		"Building web (1.5s)",
+		"Deploying api",
+		"Deploying api (1.5s): quota exceeded",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), output)
+	}
+	for i, suffix := range want {
+		if !strings.HasSuffix(lines[i], suffix) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], suffix)
+		}
+	}
+
+	records := StepRecords()
+	if len(records) != 2 {
+		t.Fatalf("StepRecords() = %d records, want 2", len(records))
+	}
+	if r := records[0]; r.Name != "Building web" || r.Status != StepSucceeded || r.DurationMs != 1500 || r.Error != "" {
+		t.Errorf("records[0] = %+v", r)
+	}
+	if r := records[1]; r.Status != StepFailed || r.Error != "quota exceeded" {
+		t.Errorf("records[1] = %+v", r)
+	}
+}
+
+func TestStartStepJSON(t *testing.T) {
+	withStepClock(t, time.Second)
+	globalFormat = FormatJSON
+
+	output := captureOutput(t, func() {
+		StartStep("Provisioning").Fail(nil)
+	})
+	if output != "" {
+		t.Errorf("JSON mode printed %q, want nothing", output)
+	}
+	records := StepRecords()
+	if len(records) != 1 || records[0].Status != StepFailed || records[0].Error == "" {
+		t.Errorf("StepRecords() = %+v", records)
+	}
+
+	ResetStepRecords()
+	if len(StepRecords()) != 0 {
+		t.Error("ResetStepRecords() did not clear records")
+	}
+}
+
+func TestStartStepNDJSON(t *testing.T) {
+	withNDJSON(t)
+	withStepClock(t, 250*time.Millisecond)
+	globalFormat = FormatNDJSON
+
+	output := captureOutput(t, func() {
+		StartStep("Building " + Highlight("web")).Succeed()
+	})
+
+	events := decodeEvents(t, output)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %s", len(events), output)
+	}
+	if events[0].Type != EventStep || events[0].Message != "Building web" {
+		t.Errorf("start event = %+v", events[0])
+	}
+	data, ok := events[1].Data.(map[string]interface{})
+	if events[1].Type != EventSuccess || !ok {
+		t.Fatalf("end event = %+v", events[1])
+	}
+	if data["name"] != "Building web" || data["status"] != StepSucceeded || data["durationMs"] != float64(250) {
+		t.Errorf("end event data = %v", data)
+	}
+}
+
+func TestStartStepSpinner(t *testing.T) {
+	withStepClock(t, 100*time.Millisecond)
+	stdoutIsTerminal = func() bool { return true }
+	oldTick := stepTick
+	stepTick = time.Millisecond
+	t.Cleanup(func() { stepTick = oldTick })
+
+	output := captureOutput(t, func() {
+		step := StartStep("Waiting")
+		time.Sleep(20 * time.Millisecond)
+		step.Succeed()
+	})
+
+	if !strings.Contains(output, "\r\033[K") {
+		t.Errorf("spinner output missing line redraws: %q", output)
+	}
+	final := output[strings.LastIndex(output, "\r\033[K")+len("\r\033[K"):]
+	if !strings.Contains(final, "Waiting") || strings.Count(final, "\n") != 1 {
+		t.Errorf("final line = %q, want one completed step line", final)
+	}
+}
+
+func TestFormatStepDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0.0s"},
+		{1234 * time.Millisecond, "1.2s"},
+		{59 * time.Second, "59.0s"},
+		{90 * time.Second, "1m30s"},
+		{61*time.Minute + 5*time.Second, "61m05s"},
+	}
+	for _, tt := range tests {
+		if got := formatStepDuration(tt.d); got != tt.want {
+			t.Errorf("formatStepDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
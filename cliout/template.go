@@ -0,0 +1,137 @@
+package cliout
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markers standing in for placeholder boundaries while the template is
+// formatted. Placeholders are found before arguments are substituted, so
+// braces in argument values are never treated as placeholders. The markers
+// are Unicode private-use characters that do not occur in ordinary text.
+const (
+	styleOpen  = '\uE000'
+	styleName  = '\uE001'
+	styleClose = '\uE002'
+)
+
+// templateStyles maps placeholder names to the ANSI sequence they apply.
+var templateStyles = map[string]func(Theme) string{
+	"highlight": func(t Theme) string { return Bold + t.Accent },
+	"bold":      func(Theme) string { return Bold },
+	"muted":     func(Theme) string { return Dim },
+	"url":       func(t Theme) string { return t.Info },
+	"success":   func(t Theme) string { return t.Success },
+	"error":     func(t Theme) string { return t.Error },
+	"warning":   func(t Theme) string { return t.Warning },
+	"info":      func(t Theme) string { return t.Info },
+}
+
+// Sprintf formats a message template. Besides fmt verbs, the template may
+// contain styled placeholders of the form {style:text}, where style is one of
+// highlight, bold, muted, url, success, error, warning, or info, and text may
+// itself contain fmt verbs:
+//
+//	msg := cliout.Sprintf("Deployed {highlight:%s} to {url:%s}", name, endpoint)
+//
+// Each placeholder is closed with its own Reset, so styles never leak into
+// the rest of the line. In JSON, NDJSON, or no-color mode the placeholders
+// render as plain text. Braces that do not start a known style are printed
+// as is, and placeholders inside argument values are never interpreted.
+func Sprintf(template string, args ...interface{}) string {
+	formatted := fmt.Sprintf(markPlaceholders(template), args...)
+
+	plain := isMachineReadable() || !ColorEnabled()
+	theme := CurrentTheme()
+
+	var b strings.Builder
+	b.Grow(len(formatted))
+	for len(formatted) > 0 {
+		i := strings.IndexRune(formatted, styleOpen)
+		if i < 0 {
+			b.WriteString(formatted)
+			break
+		}
+		b.WriteString(formatted[:i])
+		formatted = formatted[i+len(string(styleOpen)):]
+
+		j := strings.IndexRune(formatted, styleName)
+		end := strings.IndexRune(formatted, styleClose)
+		if j < 0 || end < j {
+			// A stray marker from an argument value; stripMarkers drops it.
+			continue
+		}
+		style := templateStyles[formatted[:j]]
+		text := formatted[j+len(string(styleName)) : end]
+		formatted = formatted[end+len(string(styleClose)):]
+
+		if plain || style == nil || text == "" {
+			b.WriteString(text)
+			continue
+		}
+		b.WriteString(style(theme))
+		b.WriteString(text)
+		b.WriteString(Reset)
+	}
+	return stripMarkers(b.String())
+}
+
+// Printf prints a message template formatted by Sprintf, followed by a
+// newline. Like Plain, it prints nothing in NDJSON mode.
+//
+// Example:
+//
+//	cliout.Printf("Run {highlight:azd up} to deploy, or see {url:%s}", docsURL)
+func Printf(template string, args ...interface{}) {
+	if IsNDJSON() {
+		return
+	}
+	fmt.Println(Sprintf(template, args...))
+}
+
+// markPlaceholders replaces the delimiters of known {style:text}
+// placeholders with marker runes.
+func markPlaceholders(template string) string {
+	template = stripMarkers(template)
+
+	var b strings.Builder
+	b.Grow(len(template))
+	for len(template) > 0 {
+		i := strings.IndexByte(template, '{')
+		if i < 0 {
+			b.WriteString(template)
+			break
+		}
+		b.WriteString(template[:i])
+		rest := template[i+1:]
+
+		colon := strings.IndexByte(rest, ':')
+		end := strings.IndexByte(rest, '}')
+		if colon < 0 || end < colon || templateStyles[rest[:colon]] == nil {
+			b.WriteByte('{')
+			template = rest
+			continue
+		}
+
+		b.WriteRune(styleOpen)
+		b.WriteString(rest[:colon])
+		b.WriteRune(styleName)
+		b.WriteString(rest[colon+1 : end])
+		b.WriteRune(styleClose)
+		template = rest[end+1:]
+	}
+	return b.String()
+}
+
+// stripMarkers removes placeholder marker runes from s.
+func stripMarkers(s string) string {
+	if !strings.ContainsAny(s, string([]rune{styleOpen, styleName, styleClose})) {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if r == styleOpen || r == styleName || r == styleClose {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,94 @@
+package cliout
+
+import (
+	"strings"
+	"testing"
+)
+
+// withColor forces color output and the default format and theme for the
+// duration of a test.
+func withColor(t *testing.T) {
+	t.Helper()
+	wasNoColor := getNoColor()
+	oldFormat := GetFormat()
+	ForceColor()
+	SetTheme(DefaultTheme())
+	_ = SetFormat(string(FormatDefault))
+	t.Cleanup(func() {
+		if wasNoColor {
+			NoColor()
+		}
+		_ = SetFormat(string(oldFormat))
+	})
+}
+
+func TestSprintf(t *testing.T) {
+	withColor(t)
+	theme := DefaultTheme()
+
+	tests := []struct {
+		name     string
+		template string
+		args     []interface{}
+		want     string
+	}{
+		{"no placeholders", "hello %s", []interface{}{"world"}, "hello world"},
+		{"highlight", "Run {highlight:azd up}", nil, "Run " + Bold + theme.Accent + "azd up" + Reset},
+		{"url with verb", "See {url:%s}.", []interface{}{"https://aka.ms/azd"}, "See " + theme.Info + "https://aka.ms/azd" + Reset + "."},
+		{"multiple", "{success:%d} ok, {error:%d} failed", []interface{}{3, 1},
+			theme.Success + "3" + Reset + " ok, " + theme.Error + "1" + Reset + " failed"},
+		{"bold and muted", "{bold:a} {muted:b}", nil, Bold + "a" + Reset + " " + Dim + "b" + Reset},
+		{"warning and info", "{warning:w}{info:i}", nil, theme.Warning + "w" + Reset + theme.Info + "i" + Reset},
+		{"unknown style kept", "{foo:bar} {baz}", nil, "{foo:bar} {baz}"},
+		{"unterminated kept", "{highlight:oops", nil, "{highlight:oops"},
+		{"empty text", "a{highlight:}b", nil, "ab"},
+		{"braces in args not interpreted", "name: %s", []interface{}{"{error:x}"}, "name: {error:x}"},
+		{"marker runes in args dropped", "%s", []interface{}{"a\uE000b\uE002"}, "ab"},
+		{"json literal", `{"key": %d}`, []interface{}{1}, `{"key": 1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sprintf(tt.template, tt.args...); got != tt.want {
+				t.Errorf("Sprintf(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSprintfPlain(t *testing.T) {
+	withColor(t)
+	template := "Deployed {highlight:%s} to {url:%s}"
+	want := "Deployed api to https://example.com"
+
+	NoColor()
+	if got := Sprintf(template, "api", "https://example.com"); got != want {
+		t.Errorf("Sprintf() with NoColor = %q, want %q", got, want)
+	}
+
+	ForceColor()
+	for _, format := range []Format{FormatJSON, FormatNDJSON} {
+		_ = SetFormat(string(format))
+		if got := Sprintf(template, "api", "https://example.com"); got != want {
+			t.Errorf("Sprintf() in %s mode = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestPrintf(t *testing.T) {
+	withColor(t)
+
+	output := captureOutput(t, func() {
+		Printf("Run {highlight:%s}", "azd up")
+	})
+	if !strings.Contains(output, "azd up"+Reset) || !strings.HasSuffix(output, "\n") {
+		t.Errorf("Printf() output = %q", output)
+	}
+
+	_ = SetFormat(string(FormatNDJSON))
+	output = captureOutput(t, func() {
+		Printf("Run {highlight:%s}", "azd up")
+	})
+	if output != "" {
+		t.Errorf("Printf() in NDJSON mode output = %q, want empty", output)
+	}
+}
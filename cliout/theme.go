@@ -0,0 +1,156 @@
+package cliout
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Built-in theme names.
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
+// Theme controls the colors and symbols used by the output functions.
+// Colors are ANSI escape sequences (such as BrightGreen or Bold+Cyan); an
+// empty color prints uncolored text. Symbols are used when the terminal
+// supports Unicode; otherwise the ASCII fallbacks are used.
+//
+// To customize, start from a built-in theme and override fields:
+//
+//	theme := cliout.DefaultTheme()
+//	theme.Name = "contoso"
+//	theme.Accent = cliout.Magenta
+//	cliout.SetTheme(theme)
+type Theme struct {
+	Name string `json:"name"`
+
+	Success string `json:"success"`
+	Error   string `json:"error"`
+	Warning string `json:"warning"`
+	Info    string `json:"info"`
+	// Accent is used for section headers, steps, and highlighted text.
+	Accent string `json:"accent"`
+
+	SuccessSymbol string `json:"successSymbol,omitempty"`
+	ErrorSymbol   string `json:"errorSymbol,omitempty"`
+	WarningSymbol string `json:"warningSymbol,omitempty"`
+	InfoSymbol    string `json:"infoSymbol,omitempty"`
+}
+
+// DefaultTheme returns the standard color scheme.
+func DefaultTheme() Theme {
+	return Theme{
+		Name:          ThemeDefault,
+		Success:       BrightGreen,
+		Error:         BrightRed,
+		Warning:       BrightYellow,
+		Info:          BrightBlue,
+		Accent:        Cyan,
+		SuccessSymbol: SymbolCheck,
+		ErrorSymbol:   SymbolCross,
+		WarningSymbol: SymbolWarning,
+		InfoSymbol:    SymbolInfo,
+	}
+}
+
+// HighContrastTheme returns a bold, bright color scheme for low-vision users
+// and low-contrast terminals.
+func HighContrastTheme() Theme {
+	return Theme{
+		Name:          ThemeHighContrast,
+		Success:       Bold + BrightGreen,
+		Error:         Bold + BrightRed,
+		Warning:       Bold + BrightYellow,
+		Info:          Bold + BrightCyan,
+		Accent:        Bold + White,
+		SuccessSymbol: SymbolCheck,
+		ErrorSymbol:   SymbolCross,
+		WarningSymbol: SymbolWarning,
+		InfoSymbol:    SymbolInfo,
+	}
+}
+
+// MonochromeTheme returns a scheme without colors, relying on symbols and
+// bold text, for color-blind users and terminals without color.
+func MonochromeTheme() Theme {
+	return Theme{
+		Name:          ThemeMonochrome,
+		Error:         Bold,
+		Warning:       Bold,
+		Accent:        Bold,
+		SuccessSymbol: SymbolCheck,
+		ErrorSymbol:   SymbolCross,
+		WarningSymbol: SymbolWarning,
+		InfoSymbol:    SymbolInfo,
+	}
+}
+
+// builtinThemes maps theme names to constructors.
+var builtinThemes = map[string]func() Theme{
+	ThemeDefault:      DefaultTheme,
+	ThemeHighContrast: HighContrastTheme,
+	ThemeMonochrome:   MonochromeTheme,
+}
+
+// theme is the active theme, protected by mu.
+var theme = DefaultTheme()
+
+// ThemeNames returns the names of the built-in themes, sorted.
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinTheme returns the built-in theme with the given name.
+func BuiltinTheme(name string) (Theme, bool) {
+	fn, ok := builtinThemes[name]
+	if !ok {
+		return Theme{}, false
+	}
+	return fn(), true
+}
+
+// SetTheme makes t the active theme. Empty symbols fall back to the default
+// symbols; colors are used as given.
+func SetTheme(t Theme) {
+	def := DefaultTheme()
+	if t.SuccessSymbol == "" {
+		t.SuccessSymbol = def.SuccessSymbol
+	}
+	if t.ErrorSymbol == "" {
+		t.ErrorSymbol = def.ErrorSymbol
+	}
+	if t.WarningSymbol == "" {
+		t.WarningSymbol = def.WarningSymbol
+	}
+	if t.InfoSymbol == "" {
+		t.InfoSymbol = def.InfoSymbol
+	}
+
+	mu.Lock()
+	theme = t
+	mu.Unlock()
+}
+
+// SetThemeByName activates a built-in theme.
+func SetThemeByName(name string) error {
+	t, ok := BuiltinTheme(name)
+	if !ok {
+		return fmt.Errorf("unknown theme: %s (valid options: %v)", name, ThemeNames())
+	}
+	SetTheme(t)
+	return nil
+}
+
+// CurrentTheme returns the active theme.
+func CurrentTheme() Theme {
+	mu.RLock()
+	defer mu.RUnlock()
+	return theme
+}
@@ -0,0 +1,80 @@
+package cliout
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinThemes(t *testing.T) {
+	names := ThemeNames()
+	want := []string{ThemeDefault, ThemeHighContrast, ThemeMonochrome}
+	if strings.Join(names, ",") != strings.Join([]string{ThemeDefault, ThemeHighContrast, ThemeMonochrome}, ",") {
+		t.Errorf("ThemeNames() = %v, want %v", names, want)
+	}
+	for _, name := range names {
+		th, ok := BuiltinTheme(name)
+		if !ok || th.Name != name {
+			t.Errorf("BuiltinTheme(%q) = %+v, %v", name, th, ok)
+		}
+	}
+	if _, ok := BuiltinTheme("neon"); ok {
+		t.Error("BuiltinTheme(neon) should not exist")
+	}
+
+	mono := MonochromeTheme()
+	for _, c := range []string{mono.Success, mono.Error, mono.Warning, mono.Info, mono.Accent} {
+		if strings.Contains(c, "[3") || strings.Contains(c, "[9") {
+			t.Errorf("monochrome theme contains a color code: %q", c)
+		}
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	defer SetTheme(DefaultTheme())
+
+	custom := DefaultTheme()
+	custom.Name = "contoso"
+	custom.Success = Magenta
+	custom.SuccessSymbol = ""
+	SetTheme(custom)
+
+	got := CurrentTheme()
+	if got.Name != "contoso" || got.Success != Magenta {
+		t.Errorf("CurrentTheme() = %+v, want custom theme", got)
+	}
+	if got.SuccessSymbol != SymbolCheck {
+		t.Errorf("empty SuccessSymbol = %q, want default %q", got.SuccessSymbol, SymbolCheck)
+	}
+
+	output := captureOutput(t, func() { Success("done") })
+	if !strings.Contains(output, Magenta) {
+		t.Errorf("Success output %q does not use theme color", output)
+	}
+	if s := Status("healthy"); !strings.HasPrefix(s, Magenta) {
+		t.Errorf("Status(healthy) = %q, want theme success color", s)
+	}
+}
+
+func TestSetThemeByName(t *testing.T) {
+	defer SetTheme(DefaultTheme())
+
+	if err := SetThemeByName(ThemeMonochrome); err != nil {
+		t.Fatalf("SetThemeByName() error = %v", err)
+	}
+	output := captureOutput(t, func() {
+		Success("ok")
+		Info("note")
+	})
+	for _, color := range []string{BrightGreen, BrightBlue} {
+		if strings.Contains(output, color) {
+			t.Errorf("monochrome output contains color %q: %q", color, output)
+		}
+	}
+
+	if err := SetThemeByName("neon"); err == nil {
+		t.Error("SetThemeByName(neon) should error")
+	}
+	if CurrentTheme().Name != ThemeMonochrome {
+		t.Error("failed SetThemeByName should not change the theme")
+	}
+}
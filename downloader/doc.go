@@ -0,0 +1,22 @@
+// Package downloader provides resumable HTTP downloads with checksum
+// verification for Azure Developer CLI extensions that install tools.
+//
+// Download streams a URL into "<dest>.partial" and, once the transfer is
+// complete and the optional SHA-256 checksum matches, renames it into place,
+// so dest never holds a truncated or unverified file. With Options.Resume, an
+// interrupted download continues from the partial file using an HTTP Range
+// request guarded by If-Range, and starts over if the remote file changed.
+// Progress can be reported through any io.Writer, such as a
+// progress.SpinnerWriter, which also shows the downloaded size and rate next to
+// the bar.
+//
+// Example:
+//
+//	bar := mp.AddBar("bicep", "Downloading bicep")
+//	res, err := downloader.Download(ctx, bicepURL, dest, downloader.Options{
+//	    SHA256:         expectedSHA,
+//	    Resume:         true,
+//	    Timeout:        5 * time.Minute,
+//	    ProgressWriter: progress.NewSpinnerWriter(bar),
+//	})
+package downloader
@@ -0,0 +1,309 @@
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/jongio/azd-core/urlutil"
+)
+
+// PartialSuffix is appended to dest while a download is in progress.
+const PartialSuffix = ".partial"
+
+// validatorSuffix is appended to the partial file name for the sidecar that
+// holds the ETag or Last-Modified value of the partial content.
+const validatorSuffix = ".validator"
+
+// ErrChecksumMismatch is returned when the downloaded content does not match
+// Options.SHA256. The partial file is removed.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Options configures Download.
+type Options struct {
+	// SHA256 is the expected hex-encoded SHA-256 digest. Empty skips verification.
+	SHA256 string
+	// Resume continues from an existing partial file using a Range request.
+	// The request carries If-Range with the ETag or Last-Modified value saved
+	// with the partial file, so a changed remote file is downloaded again
+	// from the start. A partial file without a saved validator is only
+	// resumed when SHA256 is set.
+	Resume bool
+	// Timeout bounds the whole download. Zero means no timeout.
+	Timeout time.Duration
+	// ProgressWriter, if set, receives a copy of every downloaded byte.
+	ProgressWriter io.Writer
+	// Mode is the permission of the final file. Defaults to fileutil.FilePermission.
+	Mode os.FileMode
+	// Client is the HTTP client to use. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Result describes a completed download.
+type Result struct {
+	// Path is the final file path.
+	Path string
+	// Size is the total file size in bytes.
+	Size int64
+	// SHA256 is the hex-encoded digest of the file.
+	SHA256 string
+	// Resumed reports whether an earlier partial download was continued.
+	Resumed bool
+}
+
+// Download fetches rawURL into dest. Only HTTPS URLs are accepted, except for
+// localhost, and redirects to other schemes are refused. The destination
+// directory is created if needed.
+func Download(ctx context.Context, rawURL, dest string, opts Options) (*Result, error) {
+	if err := urlutil.ValidateHTTPSOnly(rawURL); err != nil {
+		return nil, fmt.Errorf("invalid download URL: %w", err)
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if opts.Mode == 0 {
+		opts.Mode = fileutil.FilePermission
+	}
+	client := httpsOnlyClient(opts.Client)
+	// Download URLs often carry SAS signatures or tokens; errors use a
+	// redacted copy.
+	logURL := urlutil.RedactForLogging(rawURL)
+
+	if err := fileutil.EnsureDir(filepath.Dir(dest)); err != nil {
+		return nil, err
+	}
+	partial := dest + PartialSuffix
+	validatorPath := partial + validatorSuffix
+
+	var offset int64
+	var validator string
+	if opts.Resume {
+		offset, validator = resumeState(partial, opts.SHA256 != "")
+	}
+
+	for {
+		resp, err := get(ctx, client, rawURL, offset, validator, logURL)
+		if err != nil {
+			return nil, err
+		}
+
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		complete, restart := false, false
+		rangeStart, rangeTotal := parseContentRange(resp.Header.Get("Content-Range"))
+		switch {
+		case resp.StatusCode == http.StatusPartialContent && offset > 0:
+			if rangeStart == offset {
+				flags = os.O_WRONLY | os.O_APPEND
+			} else {
+				restart = true
+			}
+		case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+			// The partial file already holds the whole body only if its size
+			// is the total the server reports.
+			complete = rangeTotal == offset
+			restart = !complete
+		case resp.StatusCode == http.StatusOK:
+			// The server ignored the Range header or the file changed (If-Range
+			// did not match); start over.
+			offset = 0
+			saveValidator(validatorPath, resp.Header)
+		default:
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("failed to download %s: unexpected status %s", logURL, resp.Status)
+		}
+
+		if restart {
+			_ = resp.Body.Close()
+			offset, validator = 0, ""
+			continue
+		}
+		if !complete {
+			err = writeBody(partial, flags, resp.Body, opts.ProgressWriter)
+		}
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	sum, size, err := hashFile(partial)
+	if err != nil {
+		return nil, err
+	}
+	if opts.SHA256 != "" && !strings.EqualFold(sum, opts.SHA256) {
+		_ = os.Remove(partial)
+		_ = os.Remove(validatorPath)
+		return nil, fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, logURL, opts.SHA256, sum)
+	}
+
+	if err := os.Chmod(partial, opts.Mode); err != nil {
+		return nil, fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := os.Rename(partial, dest); err != nil {
+		return nil, fmt.Errorf("failed to move download into place: %w", err)
+	}
+	_ = os.Remove(validatorPath)
+
+	return &Result{Path: dest, Size: size, SHA256: sum, Resumed: offset > 0}, nil
+}
+
+// get sends the download request, asking for the bytes from offset on when
+// offset is positive.
+func get(ctx context.Context, client *http.Client, rawURL string, offset int64, validator, logURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator != "" {
+			req.Header.Set("If-Range", validator)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = logURL
+		}
+		return nil, fmt.Errorf("failed to download %s: %w", logURL, err)
+	}
+	return resp, nil
+}
+
+// httpsOnlyClient returns a copy of client (or http.DefaultClient) that
+// refuses redirects to URLs ValidateHTTPSOnly rejects.
+func httpsOnlyClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := *client
+	next := client.CheckRedirect
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := urlutil.ValidateHTTPSOnly(req.URL.String()); err != nil {
+			return fmt.Errorf("refusing redirect to %s: %w", urlutil.RedactForLogging(req.URL.String()), err)
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &c
+}
+
+// resumeState returns the size of the partial file and its saved validator.
+// Without a validator the partial file is only resumed when verified is
+// true (a checksum will catch a changed remote file); otherwise it returns
+// a zero offset so the download starts over.
+func resumeState(partial string, verified bool) (int64, string) {
+	info, err := os.Stat(partial)
+	if err != nil || info.Size() == 0 {
+		return 0, ""
+	}
+	// #nosec G304 -- path is the caller's destination plus fixed suffixes
+	data, err := os.ReadFile(partial + validatorSuffix)
+	validator := strings.TrimSpace(string(data))
+	if (err != nil || validator == "") && !verified {
+		return 0, ""
+	}
+	return info.Size(), validator
+}
+
+// saveValidator records the strong ETag, or else the Last-Modified value,
+// of a response whose body starts a new partial file. Weak ETags cannot be
+// used with If-Range.
+func saveValidator(path string, header http.Header) {
+	validator := header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = header.Get("Last-Modified")
+	}
+	if validator == "" {
+		_ = os.Remove(path)
+		return
+	}
+	_ = os.WriteFile(path, []byte(validator), fileutil.FilePermission)
+}
+
+// parseContentRange returns the first byte position and the total size from
+// a "bytes start-end/total" or "bytes */total" header. Unknown values are -1.
+func parseContentRange(header string) (start, total int64) {
+	start, total = -1, -1
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	if !ok {
+		return start, total
+	}
+	rng, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return start, total
+	}
+	if n, err := strconv.ParseInt(size, 10, 64); err == nil {
+		total = n
+	}
+	if first, _, ok := strings.Cut(rng, "-"); ok {
+		if n, err := strconv.ParseInt(first, 10, 64); err == nil {
+			start = n
+		}
+	}
+	return start, total
+}
+
+// writeBody copies body into path opened with flags, mirroring bytes to progress.
+func writeBody(path string, flags int, body io.Reader, progress io.Writer) error {
+	// #nosec G304 -- path is the caller's destination plus PartialSuffix
+	f, err := os.OpenFile(path, flags, fileutil.FilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	var w io.Writer = f
+	if progress != nil {
+		w = io.MultiWriter(f, progress)
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("download interrupted: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+	return nil
+}
+
+// hashFile returns the hex SHA-256 digest and size of path.
+func hashFile(path string) (string, int64, error) {
+	// #nosec G304 -- path is the caller's destination plus PartialSuffix
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
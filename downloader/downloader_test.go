@@ -0,0 +1,297 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var payload = []byte(strings.Repeat("azd-core downloader payload\n", 512))
+
+func payloadSHA() string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// newServer serves payload with Range support and records Range headers.
+func newServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "payload", time.Time{}, bytes.NewReader(payload))
+	}))
+	t.Cleanup(server.Close)
+	return server, &ranges
+}
+
+func TestDownload(t *testing.T) {
+	server, _ := newServer(t)
+	dest := filepath.Join(t.TempDir(), "bin", "tool")
+
+	var progress bytes.Buffer
+	res, err := Download(context.Background(), server.URL, dest, Options{
+		SHA256:         strings.ToUpper(payloadSHA()),
+		ProgressWriter: &progress,
+	})
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Error("downloaded content mismatch")
+	}
+	if res.Size != int64(len(payload)) || res.SHA256 != payloadSHA() || res.Resumed {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if progress.Len() != len(payload) {
+		t.Errorf("progress saw %d bytes, want %d", progress.Len(), len(payload))
+	}
+	if _, err := os.Stat(dest + PartialSuffix); !os.IsNotExist(err) {
+		t.Error("partial file should be removed after success")
+	}
+}
+
+func TestDownload_Resume(t *testing.T) {
+	server, ranges := newServer(t)
+	dest := filepath.Join(t.TempDir(), "tool")
+
+	half := len(payload) / 2
+	if err := os.WriteFile(dest+PartialSuffix, payload[:half], 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress bytes.Buffer
+	res, err := Download(context.Background(), server.URL, dest, Options{
+		SHA256:         payloadSHA(),
+		Resume:         true,
+		ProgressWriter: &progress,
+	})
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if !res.Resumed {
+		t.Error("expected resumed download")
+	}
+	if progress.Len() != len(payload)-half {
+		t.Errorf("expected only remaining bytes to be transferred, got %d", progress.Len())
+	}
+	if (*ranges)[0] == "" {
+		t.Error("expected Range header on resume")
+	}
+}
+
+func TestDownload_ResumeAlreadyComplete(t *testing.T) {
+	server, _ := newServer(t)
+	dest := filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(dest+PartialSuffix, payload, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Download(context.Background(), server.URL, dest, Options{SHA256: payloadSHA(), Resume: true})
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if res.Size != int64(len(payload)) {
+		t.Errorf("Size = %d", res.Size)
+	}
+}
+
+func TestDownload_ChecksumMismatch(t *testing.T) {
+	server, _ := newServer(t)
+	dest := filepath.Join(t.TempDir(), "tool")
+
+	_, err := Download(context.Background(), server.URL, dest, Options{SHA256: strings.Repeat("0", 64)})
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	for _, p := range []string{dest, dest + PartialSuffix} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist after checksum failure", p)
+		}
+	}
+}
+
+func TestDownload_Errors(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "tool")
+
+	if _, err := Download(context.Background(), "http://example.com/tool", dest, Options{}); err == nil {
+		t.Error("expected non-localhost HTTP URL to be rejected")
+	}
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	if _, err := Download(context.Background(), notFound.URL, dest, Options{}); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+}
+
+func TestDownload_ErrorsRedactURL(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "tool")
+	const secret = "sig=s3cr3tSignature"
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	_, err := Download(context.Background(), notFound.URL+"/tool.zip?sv=1&"+secret, dest, Options{})
+	if err == nil || strings.Contains(err.Error(), "s3cr3t") || !strings.Contains(err.Error(), "sv=1") {
+		t.Errorf("status error = %v, want redacted URL", err)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	_, err = Download(context.Background(), closed.URL+"/tool.zip?"+secret, dest, Options{})
+	if err == nil || strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("connection error = %v, want redacted URL", err)
+	}
+}
+
+// newETagServer serves content with an ETag, honoring Range and If-Range,
+// and records the If-Range headers it receives.
+func newETagServer(t *testing.T, etag string, content []byte) (*httptest.Server, *[]string) {
+	t.Helper()
+	var ifRange []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifRange = append(ifRange, r.Header.Get("If-Range"))
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "payload", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	return server, &ifRange
+}
+
+func writePartial(t *testing.T, dest string, data []byte, validator string) {
+	t.Helper()
+	if err := os.WriteFile(dest+PartialSuffix, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if validator != "" {
+		if err := os.WriteFile(dest+PartialSuffix+validatorSuffix, []byte(validator), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDownload_ResumeSendsIfRange(t *testing.T) {
+	server, ifRange := newETagServer(t, `"v1"`, payload)
+	dest := filepath.Join(t.TempDir(), "tool")
+	writePartial(t, dest, payload[:100], `"v1"`)
+
+	res, err := Download(context.Background(), server.URL, dest, Options{Resume: true})
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if !res.Resumed || (*ifRange)[0] != `"v1"` {
+		t.Errorf("Resumed = %v, If-Range = %q", res.Resumed, *ifRange)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Error("downloaded content mismatch")
+	}
+	if _, err := os.Stat(dest + PartialSuffix + validatorSuffix); !os.IsNotExist(err) {
+		t.Error("validator file should be removed after success")
+	}
+}
+
+func TestDownload_ResumeRestartsWhenRemoteChanged(t *testing.T) {
+	changed := bytes.ToUpper(payload)
+	server, _ := newETagServer(t, `"v2"`, changed)
+	dest := filepath.Join(t.TempDir(), "tool")
+	writePartial(t, dest, payload[:100], `"v1"`)
+
+	res, err := Download(context.Background(), server.URL, dest, Options{Resume: true})
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if res.Resumed {
+		t.Error("a changed remote file must not be resumed")
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, changed) {
+		t.Error("partial content was joined to the changed file")
+	}
+}
+
+func TestDownload_ResumeWithoutValidatorOrChecksum(t *testing.T) {
+	server, ranges := newServer(t)
+	dest := filepath.Join(t.TempDir(), "tool")
+	writePartial(t, dest, []byte("stale bytes"), "")
+
+	if _, err := Download(context.Background(), server.URL, dest, Options{Resume: true}); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if (*ranges)[0] != "" {
+		t.Errorf("Range = %q, want a full download", (*ranges)[0])
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Error("downloaded content mismatch")
+	}
+}
+
+func TestDownload_ResumeRangeMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(w http.ResponseWriter)
+	}{
+		{
+			name: "206 from wrong offset",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(payload)-1, len(payload)))
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write(payload)
+			},
+		},
+		{
+			name: "416 with different total",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(payload)))
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") != "" {
+					tt.respond(w)
+					return
+				}
+				_, _ = w.Write(payload)
+			}))
+			defer server.Close()
+			dest := filepath.Join(t.TempDir(), "tool")
+			writePartial(t, dest, payload[:100], `"v1"`)
+
+			res, err := Download(context.Background(), server.URL, dest, Options{Resume: true})
+			if err != nil {
+				t.Fatalf("Download: %v", err)
+			}
+			if got, _ := os.ReadFile(dest); res.Resumed || !bytes.Equal(got, payload) {
+				t.Errorf("Resumed = %v, content matches = %v; want a restart from zero", res.Resumed, bytes.Equal(got, payload))
+			}
+		})
+	}
+}
+
+func TestDownload_RejectsInsecureRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.com/tool", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := Download(context.Background(), server.URL, filepath.Join(t.TempDir(), "tool"), Options{})
+	if err == nil || !strings.Contains(err.Error(), "refusing redirect") {
+		t.Errorf("expected redirect to http:// to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package env
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// canonicalEscaper escapes the bytes that delimit entries in CanonicalBytes.
+var canonicalEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"=", `\=`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x00", `\0`,
+)
+
+// CanonicalBytes returns a deterministic serialization of envVars: one
+// KEY=VALUE line per entry, sorted by key bytes, with backslash, '=',
+// newline, carriage return, and NUL escaped in both keys and values. Two
+// maps produce the same bytes exactly when they have the same entries, so
+// the result is safe to hash or compare. A nil or empty map produces no
+// bytes.
+//
+// The format is stable across releases; stored hashes stay valid.
+func CanonicalBytes(envVars map[string]string) []byte {
+	keys := make([]string, 0, len(envVars))
+	size := 0
+	for k, v := range envVars {
+		keys = append(keys, k)
+		size += len(k) + len(v) + 2
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.Grow(size)
+	for _, k := range keys {
+		_, _ = canonicalEscaper.WriteString(&b, k)
+		b.WriteByte('=')
+		_, _ = canonicalEscaper.WriteString(&b, envVars[k])
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// Hash returns the hex-encoded SHA-256 digest of CanonicalBytes(envVars).
+// Use it to decide whether an environment changed, for example to skip
+// restarting a service whose environment is the same as last time:
+//
+//	if env.Hash(newEnv) == state.EnvHash {
+//	    return nil // environment unchanged, skip restart
+//	}
+func Hash(envVars map[string]string) string {
+	sum := sha256.Sum256(CanonicalBytes(envVars))
+	return hex.EncodeToString(sum[:])
+}
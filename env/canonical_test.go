@@ -0,0 +1,76 @@
+package env
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestCanonicalBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"nil", nil, ""},
+		{"empty", map[string]string{}, ""},
+		{"sorted", map[string]string{"B": "2", "A": "1", "a": "3"}, "A=1\nB=2\na=3\n"},
+		{"empty value", map[string]string{"EMPTY": ""}, "EMPTY=\n"},
+		{"equals in value", map[string]string{"URL": "a=b"}, `URL=a\=b` + "\n"},
+		{"newlines", map[string]string{"CERT": "line1\nline2\r\n"}, `CERT=line1\nline2\r\n` + "\n"},
+		{"backslash and nul", map[string]string{`K\`: "v\x00"}, `K\\=v\0` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(CanonicalBytes(tt.env)); got != tt.want {
+				t.Errorf("CanonicalBytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalBytes_Unambiguous(t *testing.T) {
+	// Each pair differs but would collide under naive KEY=VALUE joining.
+	pairs := [][2]map[string]string{
+		{{"A": "1\nB=2"}, {"A": "1", "B": "2"}},
+		{{"A=B": "C"}, {"A": "B=C"}},
+		{{"A": `x\`}, {"A": `x\\`}},
+		{{"A": ""}, {}},
+	}
+	for _, p := range pairs {
+		if string(CanonicalBytes(p[0])) == string(CanonicalBytes(p[1])) {
+			t.Errorf("CanonicalBytes(%q) == CanonicalBytes(%q)", p[0], p[1])
+		}
+		if Hash(p[0]) == Hash(p[1]) {
+			t.Errorf("Hash(%q) == Hash(%q)", p[0], p[1])
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	env := map[string]string{"AZURE_LOCATION": "eastus", "PORT": "8080", "SERVICE_API_URL": "https://api"}
+
+	first := Hash(env)
+	for i := 0; i < 20; i++ {
+		copied := make(map[string]string, len(env))
+		for k, v := range env {
+			copied[k] = v
+		}
+		if got := Hash(copied); got != first {
+			t.Fatalf("Hash() = %s, want stable %s", got, first)
+		}
+	}
+
+	sum := sha256.Sum256([]byte("AZURE_LOCATION=eastus\nPORT=8080\nSERVICE_API_URL=https://api\n"))
+	if want := hex.EncodeToString(sum[:]); first != want {
+		t.Errorf("Hash() = %s, want %s", first, want)
+	}
+
+	env["PORT"] = "8081"
+	if Hash(env) == first {
+		t.Error("Hash() did not change after a value changed")
+	}
+	if Hash(nil) != Hash(map[string]string{}) {
+		t.Error("Hash(nil) should equal Hash of an empty map")
+	}
+}
@@ -5,6 +5,8 @@
 //   - Format conversion (MapToSlice, SliceToMap)
 //   - Pattern-based extraction (FilterByPrefix, ExtractPattern)
 //   - Service name normalization (NormalizeServiceName)
+//   - Filling command-line flags from the environment (FromFlags, BindFlag)
+//   - Stable serialization for change detection (CanonicalBytes, Hash)
 //
 // # Key Vault Resolution
 //
@@ -49,6 +51,25 @@
 //	})
 //	// Returns: {"my-api": "https://...", "web-app": "https://..."}
 //
+// Filter, FilterSlice, and the matching PatternOptions fields add several
+// prefixes in one pass, case-sensitive matching, and exclusion patterns.
+// FilterSlice and SortedKeys return results in sorted order for stable
+// snapshots:
+//
+//	snapshot := env.FilterSlice(os.Environ(), env.FilterOptions{
+//		Prefixes: []string{"AZURE_", "SERVICE_"},
+//		Exclude:  []*regexp.Regexp{regexp.MustCompile(`(?i)(SECRET|PASSWORD)`)},
+//	})
+//
+// Use ExtractGroups with named capture groups to rebuild per-service settings:
+//
+//	services := env.ExtractGroups(envVars, env.GroupOptions{
+//		Patterns:      []*regexp.Regexp{regexp.MustCompile(`^SERVICE_(?P<name>.+)_(?P<attr>URL|PORT)$`)},
+//		Transform:     env.NormalizeServiceName,
+//		AttrTransform: strings.ToLower,
+//	})
+//	// Returns: {"my-api": {"url": "https://...", "port": "8080"}}
+//
 // # Service Name Normalization
 //
 // Convert environment variable naming to service naming conventions:
@@ -59,6 +80,33 @@
 // This is useful for converting uppercase underscore-separated names
 // (common in environment variables) to lowercase hyphen-separated names
 // (common in service identifiers, DNS labels, and container names).
+// DenormalizeServiceName goes the other way, and ConvertKeys converts every key
+// in a map between SCREAMING_SNAKE_CASE, kebab-case, and camelCase, failing
+// with ErrKeyCollision if two keys would map to the same name.
+//
+// # Flags and Environment Variables
+//
+// FromFlags fills flags the user did not pass from AZD_<EXT>_<FLAG>
+// variables, so every setting can come from the command line, the
+// environment, or the flag default, in that order:
+//
+//	cmd.Flags().Int("port", 8080, "listen port (env AZD_APP_PORT)")
+//	_ = env.BindFlag(cmd.Flags(), "subscription", "AZURE_SUBSCRIPTION_ID")
+//	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+//		return env.FromFlags("app", cmd.Flags())
+//	}
+//
+// # Change Detection
+//
+// Hash returns a SHA-256 digest of an environment map that does not depend
+// on map iteration order, so extensions can skip work when nothing changed:
+//
+//	if env.Hash(resolved) == lastHash {
+//		return nil // environment unchanged, skip restart
+//	}
+//
+// CanonicalBytes returns the sorted, escaped serialization the hash is
+// computed over.
 //
 // # Supported Key Vault Reference Formats
 //
@@ -85,4 +133,12 @@
 //	if err != nil {
 //		// First error encountered
 //	}
+//
+// Each warning names the vault and secret and classifies the failure
+// (keyvault.WarningNotFound, WarningForbidden, WarningThrottled, ...). Its
+// String method gives a message that is safe to show and says what to do:
+//
+//	for _, w := range warnings {
+//		cliout.Warning("%s", w)
+//	}
 package env
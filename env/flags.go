@@ -0,0 +1,85 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// flagEnvAnnotation is the pflag annotation BindFlag stores the bound
+// environment variable name under.
+const flagEnvAnnotation = "azd_env_var"
+
+// FlagEnvName returns the environment variable FromFlags reads for a flag:
+// AZD_<PREFIX>_<FLAG> in SCREAMING_SNAKE_CASE. A prefix that already starts
+// with "AZD_" is not prefixed again, and an empty prefix gives AZD_<FLAG>.
+//
+// Example:
+//
+//	env.FlagEnvName("app", "log-level")     // "AZD_APP_LOG_LEVEL"
+//	env.FlagEnvName("AZD_EXEC", "timeout")  // "AZD_EXEC_TIMEOUT"
+func FlagEnvName(prefix, flagName string) string {
+	name := ConvertName(flagName, ScreamingSnakeCase)
+	prefix = ConvertName(prefix, ScreamingSnakeCase)
+	prefix = strings.TrimPrefix(prefix, "AZD_")
+	if prefix == "AZD" {
+		prefix = ""
+	}
+	if prefix == "" {
+		return "AZD_" + name
+	}
+	return "AZD_" + prefix + "_" + name
+}
+
+// BindFlag makes FromFlags read the named flag from envName instead of the
+// FlagEnvName default, for flags that mirror an existing variable such as
+// AZURE_SUBSCRIPTION_ID. It returns an error if the flag does not exist.
+func BindFlag(flags *pflag.FlagSet, flagName, envName string) error {
+	if envName == "" {
+		return fmt.Errorf("environment variable name for flag --%s cannot be empty", flagName)
+	}
+	if err := flags.SetAnnotation(flagName, flagEnvAnnotation, []string{envName}); err != nil {
+		return fmt.Errorf("failed to bind flag --%s to %s: %w", flagName, envName, err)
+	}
+	return nil
+}
+
+// FromFlags fills flags that were not set on the command line from the
+// environment, giving the precedence flag, then environment variable, then
+// the flag's default. Each flag is read from the variable given to BindFlag,
+// or else from FlagEnvName(prefix, flag). Empty variables are ignored.
+//
+// Values are parsed by the flag itself, so slices, durations, and booleans
+// use their command-line syntax. A flag filled from the environment is
+// marked as changed, which satisfies cobra's required-flag checks. The
+// first value that fails to parse is reported with the variable name.
+//
+// Call FromFlags after the command line is parsed, e.g. from PreRunE:
+//
+//	cmd.Flags().IntVar(&port, "port", 8080, "listen port (env AZD_APP_PORT)")
+//	_ = env.BindFlag(cmd.Flags(), "subscription", "AZURE_SUBSCRIPTION_ID")
+//	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+//	    return env.FromFlags("app", cmd.Flags())
+//	}
+func FromFlags(prefix string, flags *pflag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed {
+			return
+		}
+		envName := FlagEnvName(prefix, f.Name)
+		if bound := f.Annotations[flagEnvAnnotation]; len(bound) > 0 {
+			envName = bound[0]
+		}
+		value := os.Getenv(envName)
+		if value == "" {
+			return
+		}
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("environment variable %s: %w", envName, setErr)
+		}
+	})
+	return err
+}
@@ -0,0 +1,111 @@
+package env
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestFlagEnvName(t *testing.T) {
+	tests := []struct {
+		prefix, flag, want string
+	}{
+		{"app", "port", "AZD_APP_PORT"},
+		{"app", "log-level", "AZD_APP_LOG_LEVEL"},
+		{"my-ext", "dryRun", "AZD_MY_EXT_DRY_RUN"},
+		{"AZD_EXEC", "timeout", "AZD_EXEC_TIMEOUT"},
+		{"azd", "verbose", "AZD_VERBOSE"},
+		{"", "verbose", "AZD_VERBOSE"},
+	}
+	for _, tt := range tests {
+		if got := FlagEnvName(tt.prefix, tt.flag); got != tt.want {
+			t.Errorf("FlagEnvName(%q, %q) = %q, want %q", tt.prefix, tt.flag, got, tt.want)
+		}
+	}
+}
+
+func newTestFlags(t *testing.T, args ...string) (*pflag.FlagSet, *int, *string, *time.Duration, *[]string) {
+	t.Helper()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	port := flags.Int("port", 8080, "")
+	level := flags.String("log-level", "info", "")
+	timeout := flags.Duration("timeout", time.Minute, "")
+	services := flags.StringSlice("services", nil, "")
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return flags, port, level, timeout, services
+}
+
+func TestFromFlags_Precedence(t *testing.T) {
+	t.Setenv("AZD_APP_PORT", "9000")
+	t.Setenv("AZD_APP_LOG_LEVEL", "debug")
+	t.Setenv("AZD_APP_SERVICES", "api,web")
+
+	flags, port, level, timeout, services := newTestFlags(t, "--log-level", "warn")
+	if err := FromFlags("app", flags); err != nil {
+		t.Fatalf("FromFlags: %v", err)
+	}
+
+	if *port != 9000 {
+		t.Errorf("port = %d, want 9000 from the environment", *port)
+	}
+	if *level != "warn" {
+		t.Errorf("log-level = %q, want the command-line value", *level)
+	}
+	if *timeout != time.Minute {
+		t.Errorf("timeout = %v, want the default", *timeout)
+	}
+	if want := []string{"api", "web"}; !reflect.DeepEqual(*services, want) {
+		t.Errorf("services = %v, want %v", *services, want)
+	}
+	if !flags.Changed("port") || flags.Changed("timeout") {
+		t.Error("only flags filled from the environment should be marked changed")
+	}
+}
+
+func TestFromFlags_EmptyVariableIgnored(t *testing.T) {
+	t.Setenv("AZD_APP_PORT", "")
+	flags, port, _, _, _ := newTestFlags(t)
+	if err := FromFlags("app", flags); err != nil {
+		t.Fatalf("FromFlags: %v", err)
+	}
+	if *port != 8080 || flags.Changed("port") {
+		t.Errorf("port = %d, want the default", *port)
+	}
+}
+
+func TestFromFlags_InvalidValue(t *testing.T) {
+	t.Setenv("AZD_APP_TIMEOUT", "soon")
+	flags, _, _, _, _ := newTestFlags(t)
+	err := FromFlags("app", flags)
+	if err == nil || !strings.Contains(err.Error(), "AZD_APP_TIMEOUT") {
+		t.Errorf("expected error naming AZD_APP_TIMEOUT, got %v", err)
+	}
+}
+
+func TestBindFlag(t *testing.T) {
+	t.Setenv("AZD_APP_PORT", "9000")
+	t.Setenv("PORT", "3000")
+
+	flags, port, _, _, _ := newTestFlags(t)
+	if err := BindFlag(flags, "port", "PORT"); err != nil {
+		t.Fatalf("BindFlag: %v", err)
+	}
+	if err := FromFlags("app", flags); err != nil {
+		t.Fatalf("FromFlags: %v", err)
+	}
+	if *port != 3000 {
+		t.Errorf("port = %d, want 3000 from the bound variable", *port)
+	}
+
+	if err := BindFlag(flags, "missing", "X"); err == nil {
+		t.Error("expected error for unknown flag")
+	}
+	if err := BindFlag(flags, "port", ""); err == nil {
+		t.Error("expected error for empty variable name")
+	}
+}
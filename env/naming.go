@@ -0,0 +1,133 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NamingConvention identifies a key naming style.
+type NamingConvention string
+
+const (
+	// ScreamingSnakeCase is the environment variable style, e.g. "MY_API_URL".
+	ScreamingSnakeCase NamingConvention = "SCREAMING_SNAKE_CASE"
+	// KebabCase is the azure.yaml service name style, e.g. "my-api-url".
+	KebabCase NamingConvention = "kebab-case"
+	// CamelCase is the JSON/config key style, e.g. "myApiUrl".
+	CamelCase NamingConvention = "camelCase"
+)
+
+// ErrKeyCollision is returned by ConvertKeys when two keys convert to the
+// same name.
+var ErrKeyCollision = errors.New("key collision")
+
+// DenormalizeServiceName converts a service name to environment variable
+// naming. It is the inverse of NormalizeServiceName for names made of
+// lowercase letters, digits, and hyphens.
+//
+// Example:
+//
+//	name := env.DenormalizeServiceName("my-api-service")
+//	// Returns: "MY_API_SERVICE"
+func DenormalizeServiceName(serviceName string) string {
+	return ConvertName(serviceName, ScreamingSnakeCase)
+}
+
+// ConvertName converts name to the given convention. Words are split on
+// '_', '-', '.', spaces, and lower-to-upper case changes, keeping acronyms
+// together ("HTTPServer" is "http" + "server"). Digits stay attached to the
+// preceding word. An unknown convention returns name unchanged.
+//
+// Example:
+//
+//	env.ConvertName("MY_API_URL", env.CamelCase)     // "myApiUrl"
+//	env.ConvertName("myApiUrl", env.KebabCase)       // "my-api-url"
+//	env.ConvertName("web-app", env.ScreamingSnakeCase) // "WEB_APP"
+func ConvertName(name string, convention NamingConvention) string {
+	words := splitWords(name)
+	switch convention {
+	case ScreamingSnakeCase:
+		for i, w := range words {
+			words[i] = strings.ToUpper(w)
+		}
+		return strings.Join(words, "_")
+	case KebabCase:
+		return strings.Join(words, "-")
+	case CamelCase:
+		for i := 1; i < len(words); i++ {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+		return strings.Join(words, "")
+	}
+	return name
+}
+
+// ConvertKeys returns a copy of m with every key converted to the given
+// convention. If two keys convert to the same name (for example "API_URL"
+// and "api-url"), an error wrapping ErrKeyCollision names them and no map
+// is returned.
+//
+// Example:
+//
+//	config, err := env.ConvertKeys(map[string]string{"API_BASE_URL": "https://..."}, env.CamelCase)
+//	// Returns: {"apiBaseUrl": "https://..."}
+func ConvertKeys(m map[string]string, convention NamingConvention) (map[string]string, error) {
+	switch convention {
+	case ScreamingSnakeCase, KebabCase, CamelCase:
+	default:
+		return nil, fmt.Errorf("unknown naming convention %q", convention)
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]string, len(m))
+	sources := make(map[string]string, len(m))
+	for _, k := range keys {
+		converted := ConvertName(k, convention)
+		if prev, ok := sources[converted]; ok {
+			return nil, fmt.Errorf("%w: %q and %q both convert to %q", ErrKeyCollision, prev, k, converted)
+		}
+		sources[converted] = k
+		result[converted] = m[k]
+	}
+	return result, nil
+}
+
+// splitWords splits name into lowercase words.
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == '.' || unicode.IsSpace(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(current) > 0:
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Break at "aB" and at the last capital of an acronym in "HTTPServer".
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
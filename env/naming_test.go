@@ -0,0 +1,92 @@
+package env
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConvertName(t *testing.T) {
+	tests := []struct {
+		in         string
+		convention NamingConvention
+		want       string
+	}{
+		{"MY_API_URL", CamelCase, "myApiUrl"},
+		{"MY_API_URL", KebabCase, "my-api-url"},
+		{"myApiUrl", ScreamingSnakeCase, "MY_API_URL"},
+		{"myApiUrl", KebabCase, "my-api-url"},
+		{"web-app", ScreamingSnakeCase, "WEB_APP"},
+		{"web-app", CamelCase, "webApp"},
+		{"HTTPServerPort", KebabCase, "http-server-port"},
+		{"api2Url", ScreamingSnakeCase, "API2_URL"},
+		{"SERVICE_V2_API", KebabCase, "service-v2-api"},
+		{"my.config key", CamelCase, "myConfigKey"},
+		{"", KebabCase, ""},
+		{"MixedCase", NamingConvention("unknown"), "MixedCase"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in+"->"+string(tt.convention), func(t *testing.T) {
+			if got := ConvertName(tt.in, tt.convention); got != tt.want {
+				t.Errorf("ConvertName(%q, %s) = %q, want %q", tt.in, tt.convention, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDenormalizeServiceName(t *testing.T) {
+	for _, name := range []string{"my-api-service", "web", "api-v2"} {
+		envName := DenormalizeServiceName(name)
+		if got := NormalizeServiceName(envName); got != name {
+			t.Errorf("round trip %q -> %q -> %q", name, envName, got)
+		}
+	}
+	if got := DenormalizeServiceName("my-api-service"); got != "MY_API_SERVICE" {
+		t.Errorf("DenormalizeServiceName = %q, want MY_API_SERVICE", got)
+	}
+}
+
+func TestConvertKeys(t *testing.T) {
+	in := map[string]string{
+		"API_BASE_URL":  "https://api",
+		"MAX_RETRIES":   "3",
+		"webAppName":    "web",
+		"storage-count": "2",
+	}
+
+	got, err := ConvertKeys(in, CamelCase)
+	if err != nil {
+		t.Fatalf("ConvertKeys: %v", err)
+	}
+	want := map[string]string{
+		"apiBaseUrl":   "https://api",
+		"maxRetries":   "3",
+		"webAppName":   "web",
+		"storageCount": "2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ConvertKeys() = %v, want %v", got, want)
+	}
+
+	back, err := ConvertKeys(got, ScreamingSnakeCase)
+	if err != nil {
+		t.Fatalf("ConvertKeys back: %v", err)
+	}
+	if back["API_BASE_URL"] != "https://api" || back["STORAGE_COUNT"] != "2" {
+		t.Errorf("unexpected round trip %v", back)
+	}
+}
+
+func TestConvertKeys_Errors(t *testing.T) {
+	_, err := ConvertKeys(map[string]string{"API_URL": "a", "api-url": "b"}, KebabCase)
+	if !errors.Is(err, ErrKeyCollision) {
+		t.Fatalf("expected ErrKeyCollision, got %v", err)
+	}
+	if want := `key collision: "API_URL" and "api-url" both convert to "api-url"`; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+
+	if _, err := ConvertKeys(map[string]string{}, NamingConvention("snake")); err == nil {
+		t.Error("expected error for unknown convention")
+	}
+}
@@ -1,6 +1,8 @@
 package env
 
 import (
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -70,11 +72,127 @@ func FilterByPrefixSlice(envSlice []string, prefix string) []string {
 	return result
 }
 
+// FilterOptions configures Filter and FilterSlice.
+type FilterOptions struct {
+	// Prefixes are matched in one pass; a key is kept if it starts with any
+	// of them. No prefixes keeps every key not excluded.
+	Prefixes []string
+
+	// CaseSensitive makes prefix matching case-sensitive. By default it is
+	// case-insensitive, like FilterByPrefix.
+	CaseSensitive bool
+
+	// Exclude drops keys matching any of these patterns, even when a
+	// prefix matches.
+	// Example: regexp.MustCompile(`(?i)_(SECRET|PASSWORD)$`)
+	Exclude []*regexp.Regexp
+}
+
+// Filter returns environment variables matching opts. It extends
+// FilterByPrefix with several prefixes, case-sensitive matching, and
+// exclusion patterns. Returns a new map containing only the matching entries;
+// use SortedKeys to walk it in a deterministic order.
+//
+// Example:
+//
+//	vars := env.Filter(envVars, env.FilterOptions{
+//		Prefixes: []string{"AZURE_", "SERVICE_"},
+//		Exclude:  []*regexp.Regexp{regexp.MustCompile(`_SECRET$`)},
+//	})
+func Filter(envVars map[string]string, opts FilterOptions) map[string]string {
+	result := make(map[string]string)
+	for k, v := range envVars {
+		if _, ok := matchPrefix(k, opts.Prefixes, opts.CaseSensitive); ok && !isExcluded(k, opts.Exclude) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// FilterSlice returns KEY=VALUE pairs matching opts, sorted by key so
+// snapshots built from it are stable. Malformed entries (without "=") are
+// skipped.
+func FilterSlice(envSlice []string, opts FilterOptions) []string {
+	result := make([]string, 0)
+	for _, envVar := range envSlice {
+		key, _, ok := strings.Cut(envVar, "=")
+		if !ok {
+			continue
+		}
+		if _, ok := matchPrefix(key, opts.Prefixes, opts.CaseSensitive); ok && !isExcluded(key, opts.Exclude) {
+			result = append(result, envVar)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		ki, _, _ := strings.Cut(result[i], "=")
+		kj, _, _ := strings.Cut(result[j], "=")
+		return ki < kj
+	})
+	return result
+}
+
+// SortedKeys returns the keys of envVars in sorted order.
+func SortedKeys(envVars map[string]string) []string {
+	keys := make([]string, 0, len(envVars))
+	for k := range envVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// matchPrefix returns the longest of prefixes that key starts with. Empty
+// prefixes are ignored, and if none remain every key matches with "".
+func matchPrefix(key string, prefixes []string, caseSensitive bool) (string, bool) {
+	matched, found, hasAny := "", false, false
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			continue
+		}
+		hasAny = true
+		if hasPrefix(key, prefix, caseSensitive) && len(prefix) >= len(matched) {
+			matched, found = prefix, true
+		}
+	}
+	if !hasAny {
+		return "", true
+	}
+	return matched, found
+}
+
+func hasPrefix(s, prefix string, caseSensitive bool) bool {
+	if caseSensitive {
+		return strings.HasPrefix(s, prefix)
+	}
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func hasSuffix(s, suffix string, caseSensitive bool) bool {
+	if caseSensitive {
+		return strings.HasSuffix(s, suffix)
+	}
+	return len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
+
+func isExcluded(key string, exclude []*regexp.Regexp) bool {
+	for _, re := range exclude {
+		if re != nil && re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
 // PatternOptions configures pattern-based environment variable extraction.
 type PatternOptions struct {
 	// Prefix is the required prefix for keys (e.g., "SERVICE_")
 	Prefix string
 
+	// Prefixes are additional prefixes matched in the same pass as Prefix;
+	// a key matching any of them is included. The longest matching prefix
+	// is the one trimmed by TrimPrefix.
+	Prefixes []string
+
 	// Suffix is the optional suffix for keys (e.g., "_URL")
 	Suffix string
 
@@ -92,10 +210,27 @@ type PatternOptions struct {
 	// If provided, only entries where Validator(value) returns true are included
 	// Example: func(v string) bool { return v != "" }
 	Validator func(string) bool
+
+	// Regex is an optional pattern keys must also match. When set, the result
+	// key is the submatch named "key", or the first submatch if there is no
+	// such group, and TrimPrefix/TrimSuffix are ignored. Matching is case-sensitive
+	// unless the pattern uses (?i).
+	// Example: regexp.MustCompile(`^SERVICE_(?P<key>.+)_URL$`)
+	Regex *regexp.Regexp
+
+	// CaseSensitive makes Prefix, Prefixes, and Suffix matching and trimming
+	// case-sensitive. By default they are case-insensitive.
+	CaseSensitive bool
+
+	// Exclude drops keys matching any of these patterns.
+	// Example: regexp.MustCompile(`^SERVICE_INTERNAL_`)
+	Exclude []*regexp.Regexp
 }
 
 // ExtractPattern extracts environment variables matching prefix/suffix with key transformation.
-// Pattern matching is case-insensitive. Returns a new map with transformed keys.
+// Pattern matching is case-insensitive unless CaseSensitive is set. Keys are
+// processed in sorted order, so when two variables map to the same result
+// key the first one in sorted order wins. Returns a new map with transformed keys.
 //
 // Example:
 //
@@ -115,24 +250,24 @@ type PatternOptions struct {
 //	})
 //	// Returns: {"api": "https://api.example.com", "web": "https://web.example.com"}
 func ExtractPattern(envVars map[string]string, opts PatternOptions) map[string]string {
-	if envVars == nil {
-		return map[string]string{}
-	}
-
 	result := make(map[string]string)
-	prefixUpper := strings.ToUpper(opts.Prefix)
-	suffixUpper := strings.ToUpper(opts.Suffix)
+	prefixes := append([]string{opts.Prefix}, opts.Prefixes...)
 
-	for k, v := range envVars {
-		keyUpper := strings.ToUpper(k)
+	for _, k := range SortedKeys(envVars) {
+		v := envVars[k]
 
 		// Check prefix match
-		if opts.Prefix != "" && !strings.HasPrefix(keyUpper, prefixUpper) {
+		prefix, ok := matchPrefix(k, prefixes, opts.CaseSensitive)
+		if !ok {
 			continue
 		}
 
 		// Check suffix match
-		if opts.Suffix != "" && !strings.HasSuffix(keyUpper, suffixUpper) {
+		if opts.Suffix != "" && !hasSuffix(k, opts.Suffix, opts.CaseSensitive) {
+			continue
+		}
+
+		if isExcluded(k, opts.Exclude) {
 			continue
 		}
 
@@ -144,18 +279,26 @@ func ExtractPattern(envVars map[string]string, opts PatternOptions) map[string]s
 		// Transform key
 		resultKey := k
 
-		// Trim prefix (case-sensitive trim after case-insensitive match)
-		if opts.TrimPrefix && opts.Prefix != "" {
-			// Find actual prefix in original key
-			if len(k) >= len(opts.Prefix) && strings.EqualFold(k[:len(opts.Prefix)], opts.Prefix) {
-				resultKey = k[len(opts.Prefix):]
+		keyFromRegex := false
+		if opts.Regex != nil {
+			match := opts.Regex.FindStringSubmatch(k)
+			if match == nil {
+				continue
+			}
+			resultKey, keyFromRegex = regexKey(opts.Regex, match)
+			if !keyFromRegex {
+				resultKey = k
 			}
 		}
 
-		// Trim suffix (case-sensitive trim after case-insensitive match)
-		if opts.TrimSuffix && opts.Suffix != "" {
-			// Find actual suffix in original key
-			if len(resultKey) >= len(opts.Suffix) && strings.EqualFold(resultKey[len(resultKey)-len(opts.Suffix):], opts.Suffix) {
+		// Trim the matched prefix, keeping the key's original casing
+		if opts.TrimPrefix && prefix != "" && !keyFromRegex {
+			resultKey = k[len(prefix):]
+		}
+
+		// Trim suffix, keeping the key's original casing
+		if opts.TrimSuffix && opts.Suffix != "" && !keyFromRegex {
+			if hasSuffix(resultKey, opts.Suffix, opts.CaseSensitive) {
 				resultKey = resultKey[:len(resultKey)-len(opts.Suffix)]
 			}
 		}
@@ -165,7 +308,115 @@ func ExtractPattern(envVars map[string]string, opts PatternOptions) map[string]s
 			resultKey = opts.Transform(resultKey)
 		}
 
-		result[resultKey] = v
+		if _, exists := result[resultKey]; !exists {
+			result[resultKey] = v
+		}
+	}
+
+	return result
+}
+
+// regexKey returns the "key" submatch, or the first submatch, from match.
+func regexKey(re *regexp.Regexp, match []string) (string, bool) {
+	if i := re.SubexpIndex("key"); i > 0 {
+		return match[i], true
+	}
+	if len(match) > 1 {
+		return match[1], true
+	}
+	return "", false
+}
+
+// GroupOptions configures ExtractGroups.
+type GroupOptions struct {
+	// Patterns are matched against each key in order; the first match wins.
+	// Each pattern must have a named group "name" identifying the entity
+	// (such as a service) and should have a named group "attr" identifying
+	// the attribute. Without an "attr" group the full key is used.
+	// Example: regexp.MustCompile(`^SERVICE_(?P<name>.+)_(?P<attr>URL|PORT)$`)
+	Patterns []*regexp.Regexp
+
+	// Transform is an optional function applied to the "name" submatch
+	// Example: env.NormalizeServiceName
+	Transform func(string) string
+
+	// AttrTransform is an optional function applied to the "attr" submatch
+	// Example: strings.ToLower
+	AttrTransform func(string) string
+
+	// Validator is an optional value validation function
+	// If provided, only entries where Validator(value) returns true are included
+	Validator func(string) bool
+}
+
+// ExtractGroups extracts environment variables matching regular expressions
+// with named capture groups into a nested map keyed by the "name" group and
+// then the "attr" group. Keys are processed in sorted order, so when two
+// variables map to the same name and attribute the result is deterministic.
+// Patterns without a "name" group never match.
+//
+// Example:
+//
+//	envVars := map[string]string{
+//		"SERVICE_API_URL":  "https://api.example.com",
+//		"SERVICE_API_PORT": "8080",
+//		"SERVICE_WEB_URL":  "https://web.example.com",
+//	}
+//	services := env.ExtractGroups(envVars, env.GroupOptions{
+//		Patterns:      []*regexp.Regexp{regexp.MustCompile(`^SERVICE_(?P<name>.+)_(?P<attr>URL|PORT)$`)},
+//		Transform:     env.NormalizeServiceName,
+//		AttrTransform: strings.ToLower,
+//	})
+//	// Returns: {"api": {"url": "https://api.example.com", "port": "8080"},
+//	//           "web": {"url": "https://web.example.com"}}
+func ExtractGroups(envVars map[string]string, opts GroupOptions) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	if len(envVars) == 0 {
+		return result
+	}
+
+	keys := make([]string, 0, len(envVars))
+	for k := range envVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := envVars[k]
+		if opts.Validator != nil && !opts.Validator(v) {
+			continue
+		}
+
+		for _, re := range opts.Patterns {
+			nameIdx := re.SubexpIndex("name")
+			if nameIdx < 0 {
+				continue
+			}
+			match := re.FindStringSubmatch(k)
+			if match == nil {
+				continue
+			}
+
+			name := match[nameIdx]
+			attr := k
+			if attrIdx := re.SubexpIndex("attr"); attrIdx > 0 {
+				attr = match[attrIdx]
+			}
+			if opts.Transform != nil {
+				name = opts.Transform(name)
+			}
+			if opts.AttrTransform != nil {
+				attr = opts.AttrTransform(attr)
+			}
+
+			if result[name] == nil {
+				result[name] = make(map[string]string)
+			}
+			if _, exists := result[name][attr]; !exists {
+				result[name][attr] = v
+			}
+			break
+		}
 	}
 
 	return result
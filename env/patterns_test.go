@@ -2,6 +2,7 @@ package env
 
 import (
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -464,3 +465,261 @@ func TestExtractPatternEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestExtractPattern_Regex(t *testing.T) {
+	envVars := map[string]string{
+		"SERVICE_API_URL":    "https://api.example.com",
+		"SERVICE_WEB_URL":    "https://web.example.com",
+		"SERVICE_API_PORT":   "8080",
+		"SERVICE_ADMIN_URLS": "ignored",
+	}
+
+	tests := []struct {
+		name string
+		opts PatternOptions
+		want map[string]string
+	}{
+		{
+			name: "named key group",
+			opts: PatternOptions{
+				Regex:     regexp.MustCompile(`^SERVICE_(?P<key>.+)_URL$`),
+				Transform: NormalizeServiceName,
+			},
+			want: map[string]string{"api": "https://api.example.com", "web": "https://web.example.com"},
+		},
+		{
+			name: "first group",
+			opts: PatternOptions{Regex: regexp.MustCompile(`^SERVICE_(.+)_PORT$`)},
+			want: map[string]string{"API": "8080"},
+		},
+		{
+			name: "no groups keeps trimming",
+			opts: PatternOptions{
+				Prefix:     "SERVICE_",
+				TrimPrefix: true,
+				Regex:      regexp.MustCompile(`_URL$`),
+			},
+			want: map[string]string{"API_URL": "https://api.example.com", "WEB_URL": "https://web.example.com"},
+		},
+		{
+			name: "combined with prefix",
+			opts: PatternOptions{
+				Prefix: "OTHER_",
+				Regex:  regexp.MustCompile(`^SERVICE_(?P<key>.+)_URL$`),
+			},
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractPattern(envVars, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractPattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractGroups(t *testing.T) {
+	envVars := map[string]string{
+		"SERVICE_API_URL":      "https://api.example.com",
+		"SERVICE_API_PORT":     "8080",
+		"SERVICE_MY_WEB_URL":   "https://web.example.com",
+		"SERVICE_MY_WEB_PORT":  "",
+		"DB_ORDERS_CONNECTION": "Server=orders",
+		"UNRELATED":            "x",
+	}
+
+	got := ExtractGroups(envVars, GroupOptions{
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`^SERVICE_(?P<name>.+)_(?P<attr>URL|PORT)$`),
+			regexp.MustCompile(`^DB_(?P<name>[A-Z]+)_(?P<attr>CONNECTION)$`),
+			regexp.MustCompile(`^(?P<attr>UNRELATED)$`), // no name group: never matches
+		},
+		Transform:     NormalizeServiceName,
+		AttrTransform: strings.ToLower,
+		Validator:     func(v string) bool { return v != "" },
+	})
+
+	want := map[string]map[string]string{
+		"api":    {"url": "https://api.example.com", "port": "8080"},
+		"my-web": {"url": "https://web.example.com"},
+		"orders": {"connection": "Server=orders"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractGroups() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractGroups_NoAttrGroupAndCollisions(t *testing.T) {
+	envVars := map[string]string{
+		"APP_WEB":    "first",
+		"APP_web":    "second",
+		"APP_WORKER": "w",
+	}
+	got := ExtractGroups(envVars, GroupOptions{
+		Patterns:      []*regexp.Regexp{regexp.MustCompile(`^APP_(?P<name>.+)$`)},
+		Transform:     strings.ToLower,
+		AttrTransform: strings.ToLower,
+	})
+
+	// Without an attr group the full key is the attribute. "APP_WEB" and
+	// "APP_web" collide; keys are processed in sorted order so the first wins.
+	want := map[string]map[string]string{
+		"web":    {"app_web": "first"},
+		"worker": {"app_worker": "w"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractGroups() = %v, want %v", got, want)
+	}
+
+	if got := ExtractGroups(nil, GroupOptions{}); len(got) != 0 {
+		t.Errorf("expected empty map for nil input, got %v", got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	envVars := map[string]string{
+		"AZURE_TENANT_ID":     "tenant",
+		"azure_location":      "eastus",
+		"SERVICE_API_URL":     "https://api",
+		"SERVICE_API_SECRET":  "s3cret",
+		"DATABASE_URL":        "postgres://",
+		"SERVICE_WEB_URL":     "https://web",
+		"UNRELATED_VARIABLE":  "x",
+		"SERVICE_INTERNAL_ID": "42",
+	}
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []string
+	}{
+		{
+			name: "multiple prefixes case-insensitive",
+			opts: FilterOptions{Prefixes: []string{"AZURE_", "DATABASE_"}},
+			want: []string{"AZURE_TENANT_ID", "DATABASE_URL", "azure_location"},
+		},
+		{
+			name: "case-sensitive",
+			opts: FilterOptions{Prefixes: []string{"AZURE_"}, CaseSensitive: true},
+			want: []string{"AZURE_TENANT_ID"},
+		},
+		{
+			name: "exclusions",
+			opts: FilterOptions{
+				Prefixes: []string{"SERVICE_"},
+				Exclude:  []*regexp.Regexp{regexp.MustCompile(`_SECRET$`), regexp.MustCompile(`^SERVICE_INTERNAL_`)},
+			},
+			want: []string{"SERVICE_API_URL", "SERVICE_WEB_URL"},
+		},
+		{
+			name: "no prefixes keeps everything not excluded",
+			opts: FilterOptions{Exclude: []*regexp.Regexp{regexp.MustCompile(`(?i)^(azure|service)_`)}},
+			want: []string{"DATABASE_URL", "UNRELATED_VARIABLE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SortedKeys(Filter(envVars, tt.opts))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterSlice(t *testing.T) {
+	envSlice := []string{
+		"SERVICE_WEB_URL=https://web",
+		"AZURE_TENANT_ID=tenant",
+		"MALFORMED",
+		"SERVICE_API_URL=https://api=v2",
+		"OTHER=1",
+	}
+	got := FilterSlice(envSlice, FilterOptions{Prefixes: []string{"service_", "azure_"}})
+	want := []string{"AZURE_TENANT_ID=tenant", "SERVICE_API_URL=https://api=v2", "SERVICE_WEB_URL=https://web"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterSlice() = %v, want %v", got, want)
+	}
+
+	if got := FilterSlice(nil, FilterOptions{}); got == nil || len(got) != 0 {
+		t.Errorf("FilterSlice(nil) = %#v, want empty slice", got)
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	got := SortedKeys(map[string]string{"b": "", "a": "", "C": ""})
+	if want := []string{"C", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedKeys() = %v, want %v", got, want)
+	}
+	if got := SortedKeys(nil); len(got) != 0 {
+		t.Errorf("SortedKeys(nil) = %v, want empty", got)
+	}
+}
+
+func TestExtractPattern_MultiPrefixCaseAndExclude(t *testing.T) {
+	envVars := map[string]string{
+		"SERVICE_API_URL":      "https://api",
+		"APP_WEB_URL":          "https://web",
+		"app_docs_url":         "https://docs",
+		"SERVICE_INTERNAL_URL": "https://internal",
+		"SERVICE_DB_HOST":      "db",
+	}
+
+	got := ExtractPattern(envVars, PatternOptions{
+		Prefix:     "SERVICE_",
+		Prefixes:   []string{"APP_"},
+		Suffix:     "_URL",
+		TrimPrefix: true,
+		TrimSuffix: true,
+		Transform:  NormalizeServiceName,
+		Exclude:    []*regexp.Regexp{regexp.MustCompile(`INTERNAL`)},
+	})
+	want := map[string]string{"api": "https://api", "web": "https://web", "docs": "https://docs"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractPattern() = %v, want %v", got, want)
+	}
+
+	got = ExtractPattern(envVars, PatternOptions{
+		Prefixes:      []string{"APP_"},
+		Suffix:        "_URL",
+		TrimPrefix:    true,
+		TrimSuffix:    true,
+		CaseSensitive: true,
+	})
+	if want := map[string]string{"WEB": "https://web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractPattern(CaseSensitive) = %v, want %v", got, want)
+	}
+}
+
+func TestExtractPattern_LongestPrefixAndCollisions(t *testing.T) {
+	envVars := map[string]string{
+		"SERVICE_API_URL":     "short",
+		"SERVICE_API_V2_URL":  "v2",
+		"APP_SERVICE_API_URL": "app",
+	}
+
+	// Both SERVICE_ and SERVICE_API_ match; the longer one is trimmed.
+	got := ExtractPattern(envVars, PatternOptions{
+		Prefixes:   []string{"SERVICE_", "SERVICE_API_"},
+		TrimPrefix: true,
+	})
+	if want := map[string]string{"URL": "short", "V2_URL": "v2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractPattern(longest prefix) = %v, want %v", got, want)
+	}
+
+	// APP_SERVICE_API_URL and SERVICE_API_URL both trim to API_URL; the
+	// first key in sorted order wins every time.
+	for i := 0; i < 20; i++ {
+		got = ExtractPattern(envVars, PatternOptions{
+			Prefixes:   []string{"APP_SERVICE_", "SERVICE_"},
+			TrimPrefix: true,
+		})
+		if got["API_URL"] != "app" {
+			t.Fatalf("ExtractPattern collision = %q, want deterministic %q", got["API_URL"], "app")
+		}
+	}
+}
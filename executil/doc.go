@@ -0,0 +1,30 @@
+// Package executil provides a managed command runner for Azure Developer CLI
+// extensions.
+//
+// Run executes a command described by a Spec with a per-attempt timeout,
+// optional retries, and output that is both captured and streamed to
+// caller-supplied writers (for example a progress.SpinnerWriter or os.Stdout).
+// Failures are reported with consistent error values:
+//
+//   - ErrNotFound when the executable cannot be located
+//   - ErrTimeout when an attempt exceeds Spec.Timeout
+//   - *ExitError when the command exits with a non-zero status
+//
+// Command lines are logged at debug level with secrets redacted, so values
+// passed through flags such as --client-secret or embedded connection strings
+// never reach logs.
+//
+// Example:
+//
+//	res, err := executil.Run(ctx, executil.Spec{
+//	    Cmd:     "az",
+//	    Args:    []string{"account", "show"},
+//	    Timeout: 30 * time.Second,
+//	    Retries: 2,
+//	})
+//	var exitErr *executil.ExitError
+//	if errors.As(err, &exitErr) {
+//	    fmt.Println("az failed with code", exitErr.ExitCode)
+//	}
+//	fmt.Println(res.Stdout)
+package executil
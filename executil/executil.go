@@ -0,0 +1,243 @@
+package executil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-core/logutil"
+	"github.com/jongio/azd-core/retry"
+	"github.com/jongio/azd-core/security"
+)
+
+const (
+	// DefaultRetryDelay is the delay before the first retry. It doubles on
+	// each subsequent attempt.
+	DefaultRetryDelay = 500 * time.Millisecond
+
+	// waitDelay bounds how long Run waits for output pipes to close after
+	// the process exits or is killed.
+	waitDelay = 2 * time.Second
+)
+
+var (
+	// ErrNotFound indicates the executable could not be located.
+	ErrNotFound = errors.New("executable not found")
+	// ErrTimeout indicates an attempt exceeded Spec.Timeout.
+	ErrTimeout = errors.New("command timed out")
+)
+
+// Spec describes a command to run.
+type Spec struct {
+	// Cmd is the executable name or path.
+	Cmd string
+	// Args are the command arguments.
+	Args []string
+	// Dir is the working directory. Empty means the current directory.
+	Dir string
+	// Env holds extra "KEY=value" entries appended to the parent environment.
+	Env []string
+	// Stdin, if set, is connected to the command's standard input.
+	Stdin io.Reader
+	// Timeout bounds each attempt. Zero means no timeout.
+	Timeout time.Duration
+	// Retries is the number of additional attempts after a failed run.
+	// ErrNotFound and context cancellation are never retried.
+	Retries int
+	// RetryDelay is the delay before the first retry. Defaults to DefaultRetryDelay.
+	RetryDelay time.Duration
+	// Stdout and Stderr, if set, receive output as it is produced. Output is
+	// always captured in the Result as well.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Result holds the outcome of the last attempt.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+	Attempts int
+}
+
+// ExitError reports a command that exited with a non-zero status.
+type ExitError struct {
+	// Command is the redacted command line.
+	Command  string
+	ExitCode int
+	// Stderr is the captured standard error output.
+	Stderr string
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("command %q exited with code %d", e.Command, e.ExitCode)
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		msg += ": " + security.RedactSecrets(lastLine(stderr))
+	}
+	return msg
+}
+
+// Unwrap returns the underlying *exec.ExitError.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Run executes spec and returns the result of the final attempt.
+// The returned Result is non-nil whenever the command was started.
+func Run(ctx context.Context, spec Spec) (*Result, error) {
+	if spec.Cmd == "" {
+		return nil, errors.New("command cannot be empty")
+	}
+
+	delay := spec.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	cmdLine := CommandLine(spec.Cmd, spec.Args...)
+
+	var res *Result
+	attempts := 0
+	err := retry.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		logutil.Debug("running command", "command", cmdLine, "dir", spec.Dir, "attempt", attempts)
+		var runErr error
+		res, runErr = runOnce(ctx, spec, cmdLine)
+		if res != nil {
+			res.Attempts = attempts
+		}
+		return runErr
+	}, retry.Options{
+		MaxAttempts: spec.Retries + 1,
+		Backoff:     retry.Exponential(delay, 0),
+		RetryIf:     func(err error) bool { return retryable(ctx, err) },
+		OnRetry: func(_ int, err error, d time.Duration) {
+			logutil.Debug("command failed, retrying", "command", cmdLine, "error", err, "delay", d)
+		},
+	})
+	return res, err
+}
+
+// runOnce performs a single attempt.
+func runOnce(ctx context.Context, spec Spec, cmdLine string) (*Result, error) {
+	attemptCtx := ctx
+	if spec.Timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, spec.Timeout)
+		defer cancel()
+	}
+
+	// #nosec G204 -- running caller-specified commands is the purpose of this package
+	cmd := exec.CommandContext(attemptCtx, spec.Cmd, spec.Args...)
+	cmd.Dir = spec.Dir
+	cmd.Env = append(os.Environ(), spec.Env...)
+	cmd.Stdin = spec.Stdin
+	cmd.WaitDelay = waitDelay
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = teeWriter(&stdout, spec.Stdout)
+	cmd.Stderr = teeWriter(&stderr, spec.Stderr)
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, spec.Cmd)
+		}
+		return nil, fmt.Errorf("failed to start %q: %w", cmdLine, err)
+	}
+	waitErr := cmd.Wait()
+
+	res := &Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Duration: time.Since(start),
+	}
+
+	switch {
+	case waitErr == nil:
+		return res, nil
+	case ctx.Err() != nil:
+		return res, ctx.Err()
+	case errors.Is(attemptCtx.Err(), context.DeadlineExceeded):
+		return res, fmt.Errorf("%w after %s: %s", ErrTimeout, spec.Timeout, cmdLine)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		return res, &ExitError{Command: cmdLine, ExitCode: res.ExitCode, Stderr: res.Stderr, Err: waitErr}
+	}
+	return res, fmt.Errorf("command %q failed: %w", cmdLine, waitErr)
+}
+
+// retryable reports whether a failed attempt should be retried.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrNotFound) {
+		return false
+	}
+	return true
+}
+
+// teeWriter returns buf alone or a writer duplicating to buf and w.
+func teeWriter(buf *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(buf, w)
+}
+
+// CommandLine formats a command for display with secrets redacted. Values of
+// flags whose names look sensitive (--password, --client-secret=...) are
+// masked, as are secrets recognized by security.RedactSecrets.
+func CommandLine(name string, args ...string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, name)
+	parts = append(parts, RedactArgs(args)...)
+	return strings.Join(parts, " ")
+}
+
+// RedactArgs returns a copy of args with secret values masked.
+func RedactArgs(args []string) []string {
+	out := make([]string, len(args))
+	redactNext := false
+	for i, arg := range args {
+		if redactNext {
+			out[i] = security.RedactedPlaceholder
+			redactNext = false
+			continue
+		}
+
+		if flag, ok := strings.CutPrefix(arg, "-"); ok {
+			flag = strings.TrimPrefix(flag, "-")
+			if name, _, hasValue := strings.Cut(flag, "="); hasValue {
+				if security.IsSensitiveKey(name) {
+					out[i] = arg[:strings.Index(arg, "=")+1] + security.RedactedPlaceholder
+					continue
+				}
+			} else if security.IsSensitiveKey(flag) {
+				out[i] = arg
+				redactNext = true
+				continue
+			}
+		}
+		out[i] = security.RedactSecrets(arg)
+	}
+	return out
+}
+
+// lastLine returns the final line of s.
+func lastLine(s string) string {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[i+1:])
+	}
+	return s
+}
@@ -0,0 +1,191 @@
+package executil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess is re-executed by the tests below as a portable child
+// process. It is a no-op unless EXECUTIL_HELPER is set.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("EXECUTIL_HELPER") != "1" {
+		return
+	}
+	args := os.Args
+	for i, a := range args {
+		if a == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+
+	switch args[0] {
+	case "echo":
+		fmt.Fprintln(os.Stdout, strings.Join(args[1:], " "))
+		fmt.Fprintln(os.Stderr, "to stderr")
+		os.Exit(0)
+	case "env":
+		fmt.Fprint(os.Stdout, os.Getenv(args[1]))
+		os.Exit(0)
+	case "exit":
+		code, _ := strconv.Atoi(args[1])
+		fmt.Fprintln(os.Stderr, "something failed")
+		os.Exit(code)
+	case "sleep":
+		time.Sleep(10 * time.Second)
+		os.Exit(0)
+	case "flaky":
+		// Fails until the marker file exists, creating it on the first run.
+		marker := args[1]
+		if _, err := os.Stat(marker); err != nil {
+			_ = os.WriteFile(marker, nil, 0600)
+			os.Exit(3)
+		}
+		os.Exit(0)
+	}
+	os.Exit(2)
+}
+
+func helperSpec(args ...string) Spec {
+	return Spec{
+		Cmd:  os.Args[0],
+		Args: append([]string{"-test.run=TestHelperProcess", "--"}, args...),
+		Env:  []string{"EXECUTIL_HELPER=1"},
+	}
+}
+
+func TestRun_CapturesAndStreams(t *testing.T) {
+	var streamed bytes.Buffer
+	spec := helperSpec("echo", "hello", "world")
+	spec.Stdout = &streamed
+
+	res, err := Run(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if strings.TrimSpace(res.Stdout) != "hello world" {
+		t.Errorf("Stdout = %q", res.Stdout)
+	}
+	if !strings.Contains(res.Stderr, "to stderr") {
+		t.Errorf("Stderr = %q", res.Stderr)
+	}
+	if streamed.String() != res.Stdout {
+		t.Errorf("streamed %q, captured %q", streamed.String(), res.Stdout)
+	}
+	if res.ExitCode != 0 || res.Attempts != 1 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
+
+func TestRun_Env(t *testing.T) {
+	spec := helperSpec("env", "EXECUTIL_TEST_VALUE")
+	spec.Env = append(spec.Env, "EXECUTIL_TEST_VALUE=42")
+
+	res, err := Run(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Stdout != "42" {
+		t.Errorf("Stdout = %q, want 42", res.Stdout)
+	}
+}
+
+func TestRun_ExitError(t *testing.T) {
+	res, err := Run(context.Background(), helperSpec("exit", "7"))
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected *ExitError, got %v", err)
+	}
+	if exitErr.ExitCode != 7 || res.ExitCode != 7 {
+		t.Errorf("exit code = %d, want 7", exitErr.ExitCode)
+	}
+	if !strings.Contains(err.Error(), "something failed") {
+		t.Errorf("error %q should include stderr", err)
+	}
+}
+
+func TestRun_NotFound(t *testing.T) {
+	_, err := Run(context.Background(), Spec{Cmd: "definitely-not-a-real-command-xyz", Retries: 3})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := Run(context.Background(), Spec{}); err == nil {
+		t.Error("expected error for empty command")
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	spec := helperSpec("sleep")
+	spec.Timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	_, err := Run(context.Background(), spec)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("timeout not enforced, took %v", elapsed)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	spec := helperSpec("sleep")
+	spec.Retries = 3
+	res, err := Run(ctx, spec)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context error, got %v", err)
+	}
+	if res != nil && res.Attempts != 1 {
+		t.Errorf("canceled runs must not be retried, got %d attempts", res.Attempts)
+	}
+}
+
+func TestRun_Retries(t *testing.T) {
+	spec := helperSpec("flaky", filepath.Join(t.TempDir(), "marker"))
+	spec.Retries = 2
+	spec.RetryDelay = 10 * time.Millisecond
+
+	res, err := Run(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", res.Attempts)
+	}
+}
+
+func TestCommandLine_Redacts(t *testing.T) {
+	tests := []struct {
+		args   []string
+		want   string
+		leaked string
+	}{
+		{[]string{"login", "--client-secret", "s3cr3t"}, "az login --client-secret ***REDACTED***", "s3cr3t"},
+		{[]string{"login", "--password=hunter2"}, "az login --password=***REDACTED***", "hunter2"},
+		{[]string{"storage", "--connection-string", "AccountKey=abc;"}, "", "abc"},
+		{[]string{"run", "postgres://u:pw@db/app"}, "az run postgres://u:***REDACTED***@db/app", ""},
+		{[]string{"account", "show", "--output", "json"}, "az account show --output json", ""},
+	}
+	for _, tt := range tests {
+		got := CommandLine("az", tt.args...)
+		if tt.want != "" && got != tt.want {
+			t.Errorf("CommandLine(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+		if tt.leaked != "" && strings.Contains(got, tt.leaked) {
+			t.Errorf("CommandLine(%v) = %q leaked %q", tt.args, got, tt.leaked)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"io/fs"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile creates dst with clonefile, which shares data blocks on APFS,
+// and copies instead on other file systems or across volumes.
+func cloneFile(src, dst string, perm fs.FileMode) error {
+	if err := unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW); err == nil {
+		// clonefile copies the source's mode; apply perm for consistency
+		// with the copy path.
+		return os.Chmod(dst, perm)
+	} else if err == unix.EEXIST {
+		return &fs.PathError{Op: "clonefile", Path: dst, Err: err}
+	}
+	return copyFile(src, dst, perm)
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import "io/fs"
+
+// cloneFile copies src to dst; this platform has no supported clone call.
+func cloneFile(src, dst string, perm fs.FileMode) error {
+	return copyFile(src, dst, perm)
+}
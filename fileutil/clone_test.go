@@ -0,0 +1,153 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeSource(t *testing.T, dir string) (string, []byte) {
+	t.Helper()
+	data := bytes.Repeat([]byte("node_modules payload\n"), 4096)
+	src := filepath.Join(dir, "src.bin")
+	if err := os.WriteFile(src, data, 0640); err != nil {
+		t.Fatal(err)
+	}
+	return src, data
+}
+
+func assertContent(t *testing.T, path string, want []byte) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s has %d bytes, want %d matching bytes", path, len(got), len(want))
+	}
+}
+
+func TestCloneFile(t *testing.T) {
+	dir := t.TempDir()
+	src, data := writeSource(t, dir)
+	dst := filepath.Join(dir, "dst.bin")
+
+	if err := CloneFile(src, dst); err != nil {
+		t.Fatalf("CloneFile() error = %v", err)
+	}
+	assertContent(t, dst, data)
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(dst); err != nil || info.Mode().Perm() != 0640 {
+			t.Errorf("dst mode = %v (err %v), want 0640", info.Mode().Perm(), err)
+		}
+	}
+
+	// The clone is independent of the source.
+	if err := os.WriteFile(dst, []byte("changed"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, src, data)
+}
+
+func TestCloneFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	src, _ := writeSource(t, dir)
+
+	existing := filepath.Join(dir, "existing")
+	if err := os.WriteFile(existing, []byte("keep"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CloneFile(src, existing); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("CloneFile() onto existing file error = %v, want fs.ErrExist", err)
+	}
+	assertContent(t, existing, []byte("keep"))
+
+	if err := CloneFile(filepath.Join(dir, "missing"), filepath.Join(dir, "a")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("CloneFile() of missing file error = %v, want fs.ErrNotExist", err)
+	}
+	if err := CloneFile(dir, filepath.Join(dir, "b")); err == nil {
+		t.Error("CloneFile() of a directory succeeded, want error")
+	}
+	if err := CloneFile(src, filepath.Join(dir, "no-such-dir", "c")); err == nil {
+		t.Error("CloneFile() into a missing directory succeeded, want error")
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src, data := writeSource(t, dir)
+	dst := filepath.Join(dir, "copy.bin")
+
+	if err := copyFile(src, dst, 0600); err != nil {
+		t.Fatalf("copyFile() error = %v", err)
+	}
+	assertContent(t, dst, data)
+	if err := copyFile(src, dst, 0600); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("copyFile() onto existing file error = %v, want fs.ErrExist", err)
+	}
+}
+
+func TestLinkOrCopy_Link(t *testing.T) {
+	dir := t.TempDir()
+	src, data := writeSource(t, dir)
+	dst := filepath.Join(dir, "linked.bin")
+
+	if err := LinkOrCopy(src, dst); err != nil {
+		t.Fatalf("LinkOrCopy() error = %v", err)
+	}
+	assertContent(t, dst, data)
+
+	srcInfo, _ := os.Stat(src)
+	dstInfo, _ := os.Stat(dst)
+	if !os.SameFile(srcInfo, dstInfo) {
+		t.Error("LinkOrCopy() on the same volume did not create a hard link")
+	}
+}
+
+func TestLinkOrCopy_FallsBackToCopy(t *testing.T) {
+	old := linkFile
+	linkFile = func(oldname, newname string) error {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: errors.New("invalid cross-device link")}
+	}
+	t.Cleanup(func() { linkFile = old })
+
+	dir := t.TempDir()
+	src, data := writeSource(t, dir)
+	dst := filepath.Join(dir, "copied.bin")
+
+	if err := LinkOrCopy(src, dst); err != nil {
+		t.Fatalf("LinkOrCopy() error = %v", err)
+	}
+	assertContent(t, dst, data)
+
+	srcInfo, _ := os.Stat(src)
+	dstInfo, _ := os.Stat(dst)
+	if os.SameFile(srcInfo, dstInfo) {
+		t.Error("LinkOrCopy() fallback produced a hard link")
+	}
+}
+
+func TestLinkOrCopy_Errors(t *testing.T) {
+	dir := t.TempDir()
+	src, _ := writeSource(t, dir)
+
+	existing := filepath.Join(dir, "existing")
+	if err := os.WriteFile(existing, []byte("keep"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkOrCopy(src, existing); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("LinkOrCopy() onto existing file error = %v, want fs.ErrExist", err)
+	}
+	assertContent(t, existing, []byte("keep"))
+
+	if err := LinkOrCopy(dir, filepath.Join(dir, "d")); err == nil {
+		t.Error("LinkOrCopy() of a directory succeeded, want error")
+	}
+}
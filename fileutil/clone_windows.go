@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"io/fs"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procCopyFileW = windows.NewLazySystemDLL("kernel32.dll").NewProc("CopyFileW")
+
+// cloneFile copies with CopyFileW, which uses block cloning on ReFS and Dev
+// Drive volumes where Windows supports it, and falls back to a plain copy
+// if the call is unavailable or fails for a reason other than dst existing.
+func cloneFile(src, dst string, perm fs.FileMode) error {
+	srcPtr, err := windows.UTF16PtrFromString(src)
+	if err != nil {
+		return err
+	}
+	dstPtr, err := windows.UTF16PtrFromString(dst)
+	if err != nil {
+		return err
+	}
+	if procCopyFileW.Find() == nil {
+		failIfExists := uintptr(1)
+		r, _, callErr := procCopyFileW.Call(uintptr(unsafe.Pointer(srcPtr)), uintptr(unsafe.Pointer(dstPtr)), failIfExists)
+		if r != 0 {
+			return nil
+		}
+		if callErr == windows.ERROR_FILE_EXISTS || callErr == windows.ERROR_ALREADY_EXISTS {
+			return &fs.PathError{Op: "CopyFile", Path: dst, Err: callErr}
+		}
+	}
+	return copyFile(src, dst, perm)
+}
@@ -0,0 +1,368 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// Validator is implemented by config types that check their own values.
+// LoadConfig calls Validate after decoding. Returning a *FieldError (or
+// several joined with errors.Join) lets LoadConfig report the file and line
+// of each offending field.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError describes an invalid config field.
+type FieldError struct {
+	// File is the config file path.
+	File string
+	// Line is the 1-based line of the field in File, or 0 if unknown.
+	Line int
+	// Field is the dotted path of the field, e.g. "server.port" or
+	// "services.0.name". Names are the keys used in the file.
+	Field string
+	// Err describes the problem.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File)
+		if e.Line > 0 {
+			fmt.Fprintf(&b, ":%d", e.Line)
+		}
+		b.WriteString(": ")
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&b, "%s: ", e.Field)
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages.
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// LoadConfig reads a JSON or YAML config file into a copy of defaults and
+// returns it. defaults itself is never modified: maps, slices, and pointers
+// reachable from it are copied before decoding. The format is chosen by
+// extension: ".yaml" and ".yml" are decoded as YAML, anything else as JSON.
+// Fields absent from the file keep their values from defaults. A missing
+// file is not an error; defaults are returned as-is (after validation). The
+// path is checked with security.ValidatePath.
+//
+// If T (or *T) implements Validator, Validate is called on the result.
+// Decode and validation errors are returned as *FieldError values carrying
+// the file and line where possible. On a validation error the decoded config
+// is returned alongside the error.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port    int    `json:"port" yaml:"port"`
+//	    LogFile string `json:"logFile" yaml:"logFile"`
+//	}
+//
+//	func (c *Config) Validate() error {
+//	    if c.Port <= 0 {
+//	        return &fileutil.FieldError{Field: "port", Err: errors.New("must be positive")}
+//	    }
+//	    return nil
+//	}
+//
+//	cfg, err := fileutil.LoadConfig("config.yaml", Config{Port: 8080})
+//	// err: "config.yaml:3: port: must be positive"
+func LoadConfig[T any](path string, defaults T) (T, error) {
+	if err := security.ValidatePath(path); err != nil {
+		return defaults, fmt.Errorf("invalid path %s: %w", path, err)
+	}
+
+	cfg := defaults
+	deepCopy(reflect.ValueOf(&cfg).Elem())
+
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return defaults, fmt.Errorf("failed to read config: %w", err)
+		}
+	}
+
+	if len(bytes.TrimSpace(data)) > 0 {
+		if isYAMLPath(path) {
+			err = decodeYAMLConfig(path, data, &cfg)
+		} else {
+			err = decodeJSONConfig(path, data, &cfg)
+		}
+		if err != nil {
+			return defaults, err
+		}
+	}
+
+	if err := validateConfig(&cfg); err != nil {
+		locateFieldErrors(err, path, data)
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// deepCopy replaces the maps, slices, pointers, and interface values
+// reachable from v through settable fields with copies, so decoding into v
+// cannot write into values shared with the original. Unexported fields stay
+// shared; decoders never write to them. Values reached more than once, such
+// as a parent pointer or a map holding itself, are copied once and the copy
+// is reused, so cycles terminate and sharing is preserved.
+func deepCopy(v reflect.Value) {
+	copyValue(v, make(map[copyKey]reflect.Value))
+}
+
+// copyKey identifies a pointer, map, or slice already copied by deepCopy.
+type copyKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+func copyValue(v reflect.Value, seen map[copyKey]reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		key := copyKey{typ: v.Type(), ptr: v.Pointer()}
+		if c, ok := seen[key]; ok {
+			v.Set(c)
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		seen[key] = p
+		p.Elem().Set(v.Elem())
+		copyValue(p.Elem(), seen)
+		v.Set(p)
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		inner := reflect.New(v.Elem().Type()).Elem()
+		inner.Set(v.Elem())
+		copyValue(inner, seen)
+		v.Set(inner)
+	case reflect.Map:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		key := copyKey{typ: v.Type(), ptr: v.Pointer()}
+		if c, ok := seen[key]; ok {
+			v.Set(c)
+			return
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		seen[key] = m
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			copyValue(elem, seen)
+			m.SetMapIndex(iter.Key(), elem)
+		}
+		v.Set(m)
+	case reflect.Slice:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		key := copyKey{typ: v.Type(), ptr: v.Pointer(), len: v.Len()}
+		if c, ok := seen[key]; ok {
+			v.Set(c)
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		seen[key] = s
+		reflect.Copy(s, v)
+		for i := 0; i < s.Len(); i++ {
+			copyValue(s.Index(i), seen)
+		}
+		v.Set(s)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			copyValue(v.Index(i), seen)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				copyValue(f, seen)
+			}
+		}
+	}
+}
+
+// isYAMLPath reports whether path has a YAML extension.
+func isYAMLPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// decodeJSONConfig decodes data into target, converting decode errors to
+// FieldErrors with line numbers.
+func decodeJSONConfig(path string, data []byte, target any) error {
+	err := json.Unmarshal(data, target)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &FieldError{
+			File:  path,
+			Line:  lineAtOffset(data, typeErr.Offset),
+			Field: typeErr.Field,
+			Err:   fmt.Errorf("cannot use %s as %s", typeErr.Value, typeErr.Type),
+		}
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &FieldError{File: path, Line: lineAtOffset(data, syntaxErr.Offset), Err: syntaxErr}
+	}
+	return &FieldError{File: path, Err: err}
+}
+
+// decodeYAMLConfig decodes data into target, converting decode errors to
+// FieldErrors with line numbers. Multiple type errors are joined.
+func decodeYAMLConfig(path string, data []byte, target any) error {
+	err := yaml.Unmarshal(data, target)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		errs := make([]error, 0, len(typeErr.Errors))
+		for _, msg := range typeErr.Errors {
+			errs = append(errs, yamlFieldError(path, msg))
+		}
+		return errors.Join(errs...)
+	}
+	return yamlFieldError(path, err.Error())
+}
+
+// yamlFieldError builds a FieldError from a yaml.v3 error message.
+func yamlFieldError(path, msg string) *FieldError {
+	if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &FieldError{File: path, Line: line, Err: errors.New(m[2])}
+	}
+	return &FieldError{File: path, Err: errors.New(strings.TrimPrefix(msg, "yaml: "))}
+}
+
+// validateConfig calls Validate on cfg if it implements Validator.
+func validateConfig[T any](cfg *T) error {
+	if v, ok := any(cfg).(Validator); ok {
+		return v.Validate()
+	}
+	if v, ok := any(*cfg).(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// locateFieldErrors fills File and Line on every *FieldError in err.
+func locateFieldErrors(err error, path string, data []byte) {
+	var root *yaml.Node
+	if len(data) > 0 {
+		// YAML is a superset of JSON, so the node tree gives line numbers
+		// for keys in either format.
+		var doc yaml.Node
+		if yaml.Unmarshal(data, &doc) == nil && len(doc.Content) > 0 {
+			root = doc.Content[0]
+		}
+	}
+
+	var visit func(error)
+	visit = func(err error) {
+		switch e := err.(type) {
+		case *FieldError:
+			if e.File == "" {
+				e.File = path
+			}
+			if e.Line == 0 && e.Field != "" {
+				e.Line = fieldLine(root, e.Field)
+			}
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				visit(inner)
+			}
+		case interface{ Unwrap() error }:
+			visit(e.Unwrap())
+		}
+	}
+	visit(err)
+}
+
+// fieldLine returns the line of the dotted field path in node. When the field
+// itself is absent, the line of its closest present parent is returned, or 0.
+func fieldLine(node *yaml.Node, field string) int {
+	line := 0
+	for _, part := range strings.Split(field, ".") {
+		if node == nil {
+			return line
+		}
+		switch node.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == part {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+			node = next
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return line
+			}
+			node = node.Content[idx]
+			line = node.Line
+		default:
+			return line
+		}
+	}
+	return line
+}
+
+// lineAtOffset converts a byte offset in data to a 1-based line number.
+func lineAtOffset(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
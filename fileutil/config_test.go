@@ -0,0 +1,239 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testServerConfig struct {
+	Host string `json:"host" yaml:"host"`
+	Port int    `json:"port" yaml:"port"`
+}
+
+type testConfig struct {
+	Name     string             `json:"name" yaml:"name"`
+	Server   testServerConfig   `json:"server" yaml:"server"`
+	Services []testServerConfig `json:"services" yaml:"services"`
+	Debug    bool               `json:"debug" yaml:"debug"`
+}
+
+func (c *testConfig) Validate() error {
+	var errs []error
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, &FieldError{Field: "server.port", Err: errors.New("must be between 1 and 65535")})
+	}
+	for i, svc := range c.Services {
+		if svc.Host == "" {
+			errs = append(errs, &FieldError{Field: fmt.Sprintf("services.%d.host", i), Err: errors.New("is required")})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+var testDefaults = testConfig{Name: "default", Server: testServerConfig{Host: "localhost", Port: 8080}}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"json", "config.json", `{"name": "app", "server": {"port": 9090}}`},
+		{"yaml", "config.yaml", "name: app\nserver:\n  port: 9090\n"},
+		{"yml", "config.yml", "name: app\nserver:\n  port: 9090\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(writeConfig(t, tt.file, tt.content), testDefaults)
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if cfg.Name != "app" || cfg.Server.Port != 9090 {
+				t.Errorf("file values not applied: %+v", cfg)
+			}
+			if cfg.Server.Host != "localhost" {
+				t.Errorf("expected default host, got %q", cfg.Server.Host)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_MissingFile(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json"), testDefaults)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Name != "default" || cfg.Server != (testServerConfig{Host: "localhost", Port: 8080}) {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+}
+
+func TestLoadConfig_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		wantMsgs []string
+	}{
+		{
+			name:    "json",
+			file:    "config.json",
+			content: "{\n  \"server\": {\n    \"port\": 70000\n  },\n  \"services\": [\n    {\"port\": 1}\n  ]\n}\n",
+			wantMsgs: []string{
+				"config.json:3: server.port: must be between 1 and 65535",
+				"config.json:6: services.0.host: is required",
+			},
+		},
+		{
+			name:    "yaml",
+			file:    "config.yaml",
+			content: "name: app\nserver:\n  port: 0\nservices:\n  - port: 1\n",
+			wantMsgs: []string{
+				"config.yaml:3: server.port: must be between 1 and 65535",
+				"config.yaml:5: services.0.host: is required",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.file, tt.content), testDefaults)
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("expected *FieldError, got %T", err)
+			}
+			for _, want := range tt.wantMsgs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q missing %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"json type", "config.json", "{\n  \"server\": {\n    \"port\": \"abc\"\n  }\n}", "config.json:3: server.port: cannot use string as int"},
+		{"json syntax", "config.json", "{\n  \"name\": \"app\",\n}", "config.json:3:"},
+		{"yaml type", "config.yaml", "server:\n  port: abc\n", "config.yaml:2: cannot unmarshal"},
+		{"yaml syntax", "config.yaml", "name: app\n  bad: [\n", "config.yaml:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(writeConfig(t, tt.file, tt.content), testDefaults)
+			if err == nil {
+				t.Fatal("expected decode error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not contain %q", err, tt.want)
+			}
+			if cfg.Name != "default" {
+				t.Errorf("expected defaults on decode error, got %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_DoesNotModifyDefaults(t *testing.T) {
+	type sharedConfig struct {
+		Labels map[string]string `json:"labels" yaml:"labels"`
+		Hosts  []string          `json:"hosts" yaml:"hosts"`
+		Server *testServerConfig `json:"server" yaml:"server"`
+	}
+	defaults := sharedConfig{
+		Labels: map[string]string{"env": "dev"},
+		Hosts:  []string{"a", "b"},
+		Server: &testServerConfig{Host: "localhost", Port: 8080},
+	}
+
+	for _, content := range []string{
+		`{"labels": {"env": "prod"}, "hosts": ["x", "y"], "server": {"port": 9090}}`,
+		`{"labels": {"env": "prod"}, "hosts": ["x", "y"], "server": {"port": "bad"}}`,
+	} {
+		cfg, _ := LoadConfig(writeConfig(t, "config.json", content), defaults)
+		if defaults.Labels["env"] != "dev" || defaults.Hosts[0] != "a" || defaults.Server.Port != 8080 {
+			t.Fatalf("defaults modified by %s: %+v %+v", content, defaults, *defaults.Server)
+		}
+		if cfg.Server == nil || cfg.Server.Host != "localhost" {
+			t.Errorf("config lost default server values: %+v", cfg)
+		}
+	}
+}
+
+func TestLoadConfig_CyclicDefaults(t *testing.T) {
+	type node struct {
+		Name   string `json:"name"`
+		Parent *node  `json:"-"`
+	}
+	type cyclicConfig struct {
+		Name  string         `json:"name"`
+		Root  *node          `json:"root"`
+		Extra map[string]any `json:"-"`
+	}
+	root := &node{Name: "root"}
+	root.Parent = root
+	extra := map[string]any{"k": "v"}
+	extra["self"] = extra
+	defaults := cyclicConfig{Name: "default", Root: root, Extra: extra}
+
+	cfg, err := LoadConfig(writeConfig(t, "config.json", `{"name": "x", "root": {"name": "changed"}}`), defaults)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Root == root || cfg.Root.Name != "changed" || cfg.Root.Parent != cfg.Root {
+		t.Errorf("cycle not copied: root=%p copy=%p parent=%p", root, cfg.Root, cfg.Root.Parent)
+	}
+	if root.Name != "root" {
+		t.Errorf("defaults modified: %+v", root)
+	}
+	if self, ok := cfg.Extra["self"].(map[string]any); !ok || self["k"] != "v" {
+		t.Errorf("self-referencing map not copied: %T", cfg.Extra["self"])
+	}
+}
+
+func TestLoadConfig_InvalidPath(t *testing.T) {
+	cfg, err := LoadConfig("../config.json", testDefaults)
+	if err == nil || !strings.Contains(err.Error(), "invalid path") {
+		t.Errorf("expected invalid path error, got %v", err)
+	}
+	if cfg.Name != "default" {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	inner := errors.New("bad value")
+	err := &FieldError{File: "c.json", Line: 4, Field: "a.b", Err: inner}
+	if got := err.Error(); got != "c.json:4: a.b: bad value" {
+		t.Errorf("Error() = %q", got)
+	}
+	if !errors.Is(err, inner) {
+		t.Error("expected FieldError to unwrap")
+	}
+	if got := (&FieldError{Err: inner}).Error(); got != "bad value" {
+		t.Errorf("Error() = %q", got)
+	}
+}
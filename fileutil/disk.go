@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrInsufficientSpace is returned by EnsureFreeSpace when the volume does not
+// have enough free space.
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// CleanResult summarizes a CleanOlderThan run. It is suitable for display
+// with cliout.Print.
+type CleanResult struct {
+	// Files is the number of files removed (or that would be removed in a dry run).
+	Files int `json:"files"`
+	// Bytes is the total size of those files.
+	Bytes int64 `json:"bytes"`
+	// Paths lists the affected files.
+	Paths []string `json:"paths,omitempty"`
+	// DryRun reports whether files were left in place.
+	DryRun bool `json:"dryRun"`
+}
+
+// DirSize returns the total size in bytes of the regular files under path.
+// Symbolic links are not followed.
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // removed during the walk
+			}
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute size of %s: %w", path, err)
+	}
+	return size, nil
+}
+
+// FreeSpace returns the number of bytes available to the current user on the
+// volume containing path.
+func FreeSpace(path string) (uint64, error) {
+	free, err := freeSpace(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query free space for %s: %w", path, err)
+	}
+	return free, nil
+}
+
+// EnsureFreeSpace returns an error wrapping ErrInsufficientSpace if the volume
+// containing path has fewer than bytes available. path must exist; pass the
+// destination directory when checking before a download.
+//
+// Example:
+//
+//	if err := fileutil.EnsureFreeSpace(cacheDir, 500<<20); err != nil {
+//	    return err // "insufficient disk space: need 500.0 MiB, 120.3 MiB available in /home/me/.azd"
+//	}
+func EnsureFreeSpace(path string, bytes uint64) error {
+	free, err := FreeSpace(path)
+	if err != nil {
+		return err
+	}
+	if free < bytes {
+		return fmt.Errorf("%w: need %s, %s available in %s", ErrInsufficientSpace, FormatBytes(int64(bytes)), FormatBytes(int64(free)), path)
+	}
+	return nil
+}
+
+// CleanOlderThan removes regular files under dir whose modification time is
+// older than age. If patterns is non-empty, only files whose base name
+// matches at least one filepath.Match pattern are considered. Directories and
+// symbolic links are never removed. With dryRun set, nothing is deleted and
+// the result reports what would have been removed.
+//
+// Example:
+//
+//	res, err := fileutil.CleanOlderThan(logsDir, 7*24*time.Hour, []string{"*.log"}, false)
+//	if err == nil {
+//	    fmt.Printf("Removed %d files (%s)\n", res.Files, fileutil.FormatBytes(res.Bytes))
+//	}
+func CleanOlderThan(dir string, age time.Duration, patterns []string, dryRun bool) (*CleanResult, error) {
+	for _, p := range patterns {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+
+	cutoff := time.Now().Add(-age)
+	result := &CleanResult{DryRun: dryRun}
+	var errs []error
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			errs = append(errs, err)
+			return nil
+		}
+		if !d.Type().IsRegular() || !matchesAny(d.Name(), patterns) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			return nil
+		}
+		if !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				if !os.IsNotExist(err) {
+					errs = append(errs, err)
+				}
+				return nil
+			}
+		}
+		result.Files++
+		result.Bytes += info.Size()
+		result.Paths = append(result.Paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to clean %s: %w", dir, err)
+	}
+	return result, errors.Join(errs...)
+}
+
+// matchesAny reports whether name matches one of patterns. An empty pattern
+// list matches everything.
+func matchesAny(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatBytes formats a byte count using binary units, e.g. "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,146 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func writeAged(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	writeAged(t, filepath.Join(dir, "a.bin"), 100, 0)
+	writeAged(t, filepath.Join(dir, "sub", "b.bin"), 250, 0)
+
+	size, err := DirSize(dir)
+	if err != nil {
+		t.Fatalf("DirSize: %v", err)
+	}
+	if size != 350 {
+		t.Errorf("DirSize = %d, want 350", size)
+	}
+
+	if _, err := DirSize(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestEnsureFreeSpace(t *testing.T) {
+	dir := t.TempDir()
+	free, err := FreeSpace(dir)
+	if err != nil {
+		t.Fatalf("FreeSpace: %v", err)
+	}
+	if free == 0 {
+		t.Skip("no free space reported")
+	}
+	if err := EnsureFreeSpace(dir, 1); err != nil {
+		t.Errorf("EnsureFreeSpace(1): %v", err)
+	}
+	if err := EnsureFreeSpace(dir, math.MaxInt64); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("expected ErrInsufficientSpace, got %v", err)
+	}
+	if err := EnsureFreeSpace(filepath.Join(dir, "missing"), 1); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestCleanOlderThan(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.log")
+	oldNested := filepath.Join(dir, "nested", "older.log")
+	oldOther := filepath.Join(dir, "old.zip")
+	fresh := filepath.Join(dir, "fresh.log")
+	writeAged(t, old, 10, 48*time.Hour)
+	writeAged(t, oldNested, 20, 72*time.Hour)
+	writeAged(t, oldOther, 30, 48*time.Hour)
+	writeAged(t, fresh, 40, time.Minute)
+
+	res, err := CleanOlderThan(dir, 24*time.Hour, []string{"*.log"}, true)
+	if err != nil {
+		t.Fatalf("CleanOlderThan dry run: %v", err)
+	}
+	if !res.DryRun || res.Files != 2 || res.Bytes != 30 {
+		t.Errorf("dry run result = %+v", res)
+	}
+	for _, p := range []string{old, oldNested, oldOther, fresh} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("dry run removed %s", p)
+		}
+	}
+
+	res, err = CleanOlderThan(dir, 24*time.Hour, []string{"*.log"}, false)
+	if err != nil {
+		t.Fatalf("CleanOlderThan: %v", err)
+	}
+	sort.Strings(res.Paths)
+	if res.Files != 2 || res.Bytes != 30 || len(res.Paths) != 2 || res.Paths[0] != oldNested || res.Paths[1] != old {
+		t.Errorf("result = %+v", res)
+	}
+	for _, p := range []string{old, oldNested} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("expected %s removed", p)
+		}
+	}
+	for _, p := range []string{oldOther, fresh} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("expected %s kept", p)
+		}
+	}
+
+	res, err = CleanOlderThan(dir, 24*time.Hour, nil, false)
+	if err != nil {
+		t.Fatalf("CleanOlderThan without patterns: %v", err)
+	}
+	if res.Files != 1 || res.Paths[0] != oldOther {
+		t.Errorf("expected only old.zip removed, got %+v", res)
+	}
+}
+
+func TestCleanOlderThan_Errors(t *testing.T) {
+	if _, err := CleanOlderThan(t.TempDir(), time.Hour, []string{"["}, false); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+	if _, err := CleanOlderThan(filepath.Join(t.TempDir(), "missing"), time.Hour, nil, false); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.in); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -7,11 +7,18 @@
 // # Key Features
 //
 //   - Atomic file writes with retry logic to prevent partial writes
+//   - Multi-file transactions that update several files all-or-nothing
 //   - JSON read/write with graceful handling of missing files
+//   - Typed JSON/YAML config loading with defaults and field-level validation errors
 //   - Directory creation with secure permissions (0750)
+//   - Symlink-aware directory and temp directory creation inside a base (EnsureDirWithin, TempDirWithin)
+//   - Directory size, free space checks, and age-based cleanup with dry-run support
+//   - Fast copies of large artifacts via hard links (LinkOrCopy) or copy-on-write clones (CloneFile)
 //   - File existence checks (single, any, all patterns)
 //   - File extension detection
 //   - Text containment checks with security validation
+//   - Structured JSON/YAML queries (QueryJSONFile, QueryYAMLFile) for reliable project detection
+//   - Expansion of ~, ~user, $VAR, and %VAR% in user-supplied paths (ExpandPath)
 //
 // # Security Considerations
 //
@@ -19,6 +26,13 @@
 // files, preventing path traversal attacks. Files are created with 0644 permissions
 // and directories with 0750 permissions to prevent unauthorized access.
 //
+// When writing into user-supplied directories, EnsureDirWithin and
+// TempDirWithin resolve every path component while creating it, so a
+// symbolic link that points outside the base directory is rejected instead
+// of followed:
+//
+//	outDir, err := fileutil.EnsureDirWithin(projectDir, flags.Output)
+//
 // # Atomic Write Operations
 //
 // AtomicWriteJSON and AtomicWriteFile ensure that files are never left in a partial
@@ -30,6 +44,20 @@
 //   - Retry logic (5 attempts with 20ms backoff) for rename operations
 //   - Automatic cleanup of temporary files on failure
 //
+// # Transactions
+//
+// When several files must change together (for example config, lock, and
+// manifest), stage them on a Transaction. Commit writes every temp file before
+// replacing any target and restores already-replaced files if a later rename
+// fails:
+//
+//	tx := fileutil.NewTransaction()
+//	tx.Stage("azure.yaml", configData)
+//	tx.Stage("azure.lock", lockData)
+//	if err := tx.Commit(); err != nil {
+//	    return err
+//	}
+//
 // # Example Usage
 //
 //	// Write configuration as JSON atomically
@@ -68,7 +96,7 @@
 //	}
 //
 //	// Check for framework-specific configuration
-//	if fileutil.ContainsTextInFile(".", "package.json", "\"next\"") {
+//	if fileutil.HasJSONPath("package.json", "dependencies.next") {
 //	    fmt.Println("Next.js project detected")
 //	}
 //
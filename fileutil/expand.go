@@ -0,0 +1,173 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// ErrUndefinedVariable is returned by ExpandPath when a path references an
+// environment variable that is not set. Expanding it to the empty string
+// could silently turn "$APP_DIR/data" into "/data".
+var ErrUndefinedVariable = errors.New("undefined environment variable")
+
+var (
+	// expandHomeDir and lookupUserHome are replaced in tests.
+	expandHomeDir  = os.UserHomeDir
+	lookupUserHome = func(name string) (string, error) {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return u.HomeDir, nil
+	}
+)
+
+// ExpandPath expands a user-supplied path and validates the result with
+// security.ValidatePath. It handles:
+//
+//   - A leading "~" or "~/" for the current user's home directory
+//   - A leading "~user/" for another user's home directory (not on Windows)
+//   - $VAR and ${VAR} environment variables
+//   - %VAR% environment variables
+//
+// Both variable styles are expanded on every platform, so config files can be
+// shared between Windows and Unix users. A literal "$" or "%" that doesn't
+// form a variable reference is kept as is. ErrUndefinedVariable is returned
+// for a variable that is not set. The expanded path is cleaned but not made
+// absolute.
+//
+// Example:
+//
+//	dir, err := fileutil.ExpandPath("~/projects/app")        // /home/me/projects/app
+//	dir, err := fileutil.ExpandPath("%USERPROFILE%\\.azure") // C:\Users\me\.azure
+func ExpandPath(p string) (string, error) {
+	if p == "" {
+		return "", fmt.Errorf("%w: empty path", security.ErrInvalidPath)
+	}
+
+	home, rest, err := expandTilde(p)
+	if err != nil {
+		return "", err
+	}
+	expanded, err := expandVariables(rest)
+	if err != nil {
+		return "", err
+	}
+	// Validate before cleaning, which would hide ".." segments.
+	expanded = home + expanded
+	if err := security.ValidatePath(expanded); err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", p, err)
+	}
+	return filepath.Clean(expanded), nil
+}
+
+// expandTilde resolves a leading "~" or "~user" and returns the home
+// directory and the remainder of p. If p has no tilde prefix, home is empty
+// and rest is p.
+func expandTilde(p string) (home, rest string, err error) {
+	if !strings.HasPrefix(p, "~") {
+		return "", p, nil
+	}
+	end := strings.IndexAny(p, `/\`)
+	if end < 0 {
+		end = len(p)
+	}
+	name, rest := p[1:end], p[end:]
+
+	if name == "" {
+		home, err = expandHomeDir()
+		if err != nil {
+			return "", "", fmt.Errorf("cannot expand ~: %w", err)
+		}
+		return home, rest, nil
+	}
+	if runtime.GOOS == "windows" {
+		return "", "", fmt.Errorf("%w: ~%s is not supported on Windows", security.ErrInvalidPath, name)
+	}
+	home, err = lookupUserHome(name)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot expand ~%s: %w", name, err)
+	}
+	return home, rest, nil
+}
+
+// expandVariables replaces $VAR, ${VAR}, and %VAR% references in s.
+func expandVariables(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		name, width := variableRef(s[i:])
+		if width == 0 {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUndefinedVariable, name)
+		}
+		b.WriteString(value)
+		i += width
+	}
+	return b.String(), nil
+}
+
+// variableRef parses a variable reference at the start of s and returns its
+// name and length in bytes, or a zero length if s doesn't start with one.
+func variableRef(s string) (name string, width int) {
+	switch {
+	case strings.HasPrefix(s, "${"):
+		end := strings.IndexByte(s, '}')
+		if end > 2 && isVariableName(s[2:end]) {
+			return s[2:end], end + 1
+		}
+	case strings.HasPrefix(s, "$"):
+		end := 1
+		for end < len(s) && isVariableChar(s[end], end == 1) {
+			end++
+		}
+		if end > 1 {
+			return s[1:end], end
+		}
+	case strings.HasPrefix(s, "%"):
+		end := strings.IndexByte(s[1:], '%') + 1
+		if end > 1 && isVariableName(s[1:end]) {
+			return s[1:end], end + 1
+		}
+	}
+	return "", 0
+}
+
+// isVariableName reports whether s is a valid name in a ${VAR} or %VAR%
+// reference. Parentheses are allowed after the first character for Windows
+// names such as ProgramFiles(x86).
+func isVariableName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isVariableChar(s[i], i == 0) && (i == 0 || (s[i] != '(' && s[i] != ')')) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isVariableChar reports whether c can appear in a variable name. Names
+// cannot start with a digit.
+func isVariableChar(c byte, first bool) bool {
+	switch {
+	case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	default:
+		return false
+	}
+}
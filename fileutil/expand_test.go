@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jongio/azd-core/security"
+)
+
+func withExpandHome(t *testing.T, home string, users map[string]string) {
+	t.Helper()
+	oldHome, oldLookup := expandHomeDir, lookupUserHome
+	expandHomeDir = func() (string, error) { return home, nil }
+	lookupUserHome = func(name string) (string, error) {
+		if dir, ok := users[name]; ok {
+			return dir, nil
+		}
+		return "", errors.New("unknown user " + name)
+	}
+	t.Cleanup(func() { expandHomeDir, lookupUserHome = oldHome, oldLookup })
+}
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	other := t.TempDir()
+	withExpandHome(t, home, map[string]string{"alice": other})
+	t.Setenv("AZD_EXPAND_DIR", filepath.Join(home, "work"))
+	t.Setenv("AZD_EXPAND_NAME", "app")
+	t.Setenv("ProgramFiles(x86)", filepath.Join(home, "pf86"))
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"home", "~", home},
+		{"home subdir", "~/projects/app", filepath.Join(home, "projects", "app")},
+		{"dollar", "$AZD_EXPAND_DIR/data", filepath.Join(home, "work", "data")},
+		{"braces", "${AZD_EXPAND_DIR}/${AZD_EXPAND_NAME}1", filepath.Join(home, "work", "app1")},
+		{"percent", "%AZD_EXPAND_DIR%/%AZD_EXPAND_NAME%", filepath.Join(home, "work", "app")},
+		{"percent with parens", "%ProgramFiles(x86)%/tool", filepath.Join(home, "pf86", "tool")},
+		{"tilde and variable", "~/$AZD_EXPAND_NAME", filepath.Join(home, "app")},
+		{"literal dollar", "data/$1/100%", filepath.Join("data", "$1", "100%")},
+		{"literal percent pair", "50% off 20%", "50% off 20%"},
+		{"tilde not at start", "a~b", "a~b"},
+		{"cleaned", "./a//b/", filepath.Join("a", "b")},
+	}
+	if runtime.GOOS != "windows" {
+		tests = append(tests, struct {
+			name string
+			in   string
+			want string
+		}{"other user", "~alice/src", filepath.Join(other, "src")})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandPath(tt.in)
+			if err != nil {
+				t.Fatalf("ExpandPath(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandPath(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPath_Errors(t *testing.T) {
+	withExpandHome(t, t.TempDir(), nil)
+	t.Setenv("AZD_EXPAND_TRAVERSAL", "..")
+
+	tests := []struct {
+		name    string
+		in      string
+		wantErr error
+	}{
+		{"empty", "", security.ErrInvalidPath},
+		{"undefined dollar", "$AZD_EXPAND_UNSET_VAR/data", ErrUndefinedVariable},
+		{"undefined braces", "${AZD_EXPAND_UNSET_VAR}", ErrUndefinedVariable},
+		{"undefined percent", "%AZD_EXPAND_UNSET_VAR%", ErrUndefinedVariable},
+		{"traversal", "~/../etc", security.ErrPathTraversal},
+		{"traversal via variable", "data/$AZD_EXPAND_TRAVERSAL/x", security.ErrPathTraversal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExpandPath(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExpandPath(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := ExpandPath("~nobody-azd-test/x"); err == nil {
+		t.Error("ExpandPath(~unknown user) should fail")
+	}
+}
@@ -4,6 +4,7 @@
 package fileutil
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jongio/azd-core/retry"
 	"github.com/jongio/azd-core/security"
 )
 
@@ -68,21 +70,10 @@ func AtomicWriteJSON(path string, data interface{}) error {
 	}
 
 	// Rename temp file to final file (atomic operation on most filesystems).
-	// Perform a few retries with exponential backoff to mitigate transient rename races.
-	var renameErr error
-	for attempt := 0; attempt < 5; attempt++ {
-		renameErr = os.Rename(tmpPath, path)
-		if renameErr == nil {
-			break
-		}
-		if attempt < 4 { // Don't sleep on last attempt
-			delay := time.Duration(20*(attempt+1)) * time.Millisecond // 20ms, 40ms, 60ms, 80ms
-			time.Sleep(delay)
-		}
-	}
-	if renameErr != nil {
+	// Retries mitigate transient rename races.
+	if err := renameWithRetry(tmpPath, path); err != nil {
 		_ = os.Remove(tmpPath)
-		return fmt.Errorf("failed to rename temp file: %w", renameErr)
+		return fmt.Errorf("failed to rename temp file: %w", err)
 	}
 
 	return nil
@@ -125,21 +116,10 @@ func AtomicWriteFile(path string, data []byte, perm os.FileMode) error {
 	}
 
 	// Rename temp file to final file (atomic operation on most filesystems).
-	// Perform a few retries with exponential backoff to mitigate transient rename races.
-	var renameErr2 error
-	for attempt := 0; attempt < 5; attempt++ {
-		renameErr2 = os.Rename(tmpPath, path)
-		if renameErr2 == nil {
-			break
-		}
-		if attempt < 4 { // Don't sleep on last attempt
-			delay := time.Duration(20*(attempt+1)) * time.Millisecond // 20ms, 40ms, 60ms, 80ms
-			time.Sleep(delay)
-		}
-	}
-	if renameErr2 != nil {
+	// Retries mitigate transient rename races.
+	if err := renameWithRetry(tmpPath, path); err != nil {
 		_ = os.Remove(tmpPath)
-		return fmt.Errorf("failed to rename temp file: %w", renameErr2)
+		return fmt.Errorf("failed to rename temp file: %w", err)
 	}
 
 	// Ensure final permissions are set
@@ -150,6 +130,14 @@ func AtomicWriteFile(path string, data []byte, perm os.FileMode) error {
 	return nil
 }
 
+// renameWithRetry renames src to dst, retrying up to 5 attempts with a linear
+// backoff (20ms, 40ms, 60ms, 80ms) to ride out transient sharing violations.
+func renameWithRetry(src, dst string) error {
+	return retry.Do(context.Background(), func(context.Context) error {
+		return os.Rename(src, dst)
+	}, retry.Options{MaxAttempts: 5, Backoff: retry.Linear(20 * time.Millisecond)})
+}
+
 // ReadJSON reads JSON from a file into the target interface.
 // Returns nil error if file doesn't exist (target unchanged).
 func ReadJSON(path string, target interface{}) error {
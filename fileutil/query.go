@@ -0,0 +1,223 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoMatch is returned by QueryJSONFile and QueryYAMLFile when the query
+// path does not exist in the document.
+var ErrNoMatch = errors.New("no match for query path")
+
+// QueryJSONFile reads a JSON file and returns the value at jsonPath.
+//
+// A query path is a dot-separated list of object keys and array indexes,
+// optionally starting with "$". Keys containing dots or other special
+// characters can be quoted in brackets, and indexes can be written either
+// as a segment or in brackets:
+//
+//	dependencies.next
+//	$.scripts.build
+//	workspaces[0]
+//	devDependencies["@types/node"]
+//
+// Objects are returned as map[string]interface{}, arrays as
+// []interface{}, and scalars as string, float64, bool, or nil. The empty
+// path returns the whole document. ErrNoMatch is returned when any part of
+// the path is missing.
+//
+// Example:
+//
+//	if _, err := fileutil.QueryJSONFile("package.json", "dependencies.next"); err == nil {
+//	    fmt.Println("Next.js project detected")
+//	}
+func QueryJSONFile(path string, jsonPath string) (interface{}, error) {
+	data, err := readQueryFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON %s: %w", path, err)
+	}
+	return queryValue(doc, jsonPath)
+}
+
+// QueryYAMLFile reads a YAML file and returns the value at the query path.
+// It uses the same path syntax as QueryJSONFile. Only the first document
+// of a multi-document file is queried.
+//
+// Example:
+//
+//	host, err := fileutil.QueryYAMLFile("azure.yaml", "services.api.host")
+func QueryYAMLFile(path string, yamlPath string) (interface{}, error) {
+	data, err := readQueryFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML %s: %w", path, err)
+	}
+	return queryValue(doc, yamlPath)
+}
+
+// HasJSONPath reports whether the JSON file at filePath contains jsonPath.
+// Returns false if the file doesn't exist, can't be parsed, or validation fails.
+func HasJSONPath(filePath string, jsonPath string) bool {
+	_, err := QueryJSONFile(filePath, jsonPath)
+	return err == nil
+}
+
+// HasYAMLPath reports whether the YAML file at filePath contains yamlPath.
+// Returns false if the file doesn't exist, can't be parsed, or validation fails.
+func HasYAMLPath(filePath string, yamlPath string) bool {
+	_, err := QueryYAMLFile(filePath, yamlPath)
+	return err == nil
+}
+
+func readQueryFile(path string) ([]byte, error) {
+	if err := security.ValidatePath(path); err != nil {
+		return nil, fmt.Errorf("invalid path %s: %w", path, err)
+	}
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// querySegment is one step of a query path: an object key or array index.
+type querySegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// queryValue walks doc along the parsed query path.
+func queryValue(doc interface{}, query string) (interface{}, error) {
+	segments, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	current := doc
+	for i, seg := range segments {
+		next, ok := step(current, seg)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNoMatch, formatQuery(segments[:i+1]))
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// step resolves a single segment against value. A numeric dotted segment
+// such as "items.0" is treated as an index for arrays and as a key for
+// objects.
+func step(value interface{}, seg querySegment) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if seg.isIndex && seg.key == "" {
+			return nil, false
+		}
+		next, ok := v[seg.key]
+		return next, ok
+	case map[interface{}]interface{}:
+		if seg.isIndex && seg.key == "" {
+			return nil, false
+		}
+		for k, next := range v {
+			if fmt.Sprint(k) == seg.key {
+				return next, true
+			}
+		}
+		return nil, false
+	case []interface{}:
+		if !seg.isIndex || seg.index < 0 || seg.index >= len(v) {
+			return nil, false
+		}
+		return v[seg.index], true
+	default:
+		return nil, false
+	}
+}
+
+// parseQuery splits a query path into segments.
+func parseQuery(query string) ([]querySegment, error) {
+	rest := strings.TrimSpace(query)
+	rest = strings.TrimPrefix(rest, "$")
+	var segments []querySegment
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' {
+				return nil, fmt.Errorf("invalid query %q: empty key", query)
+			}
+		case '[':
+			if len(rest) > 1 && (rest[1] == '"' || rest[1] == '\'') {
+				// Quoted keys may contain '.' and ']', so look for the closing quote.
+				closeQuote := strings.IndexByte(rest[2:], rest[1])
+				if closeQuote < 0 || 2+closeQuote+1 >= len(rest) || rest[2+closeQuote+1] != ']' {
+					return nil, fmt.Errorf("invalid query %q: unterminated quoted key", query)
+				}
+				segments = append(segments, querySegment{key: rest[2 : 2+closeQuote]})
+				rest = rest[2+closeQuote+2:]
+				continue
+			}
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid query %q: missing ]", query)
+			}
+			n, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid query %q: index %q is not a number", query, rest[1:end])
+			}
+			segments = append(segments, querySegment{index: n, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			seg := querySegment{key: key}
+			if n, err := strconv.Atoi(key); err == nil {
+				seg.index, seg.isIndex = n, true
+			}
+			segments = append(segments, seg)
+			rest = rest[end:]
+		}
+	}
+	return segments, nil
+}
+
+// formatQuery renders segments back into a path for error messages.
+func formatQuery(segments []querySegment) string {
+	var b strings.Builder
+	for i, seg := range segments {
+		switch {
+		case seg.isIndex && seg.key == "":
+			fmt.Fprintf(&b, "[%d]", seg.index)
+		case strings.ContainsAny(seg.key, ".[]"):
+			fmt.Fprintf(&b, "[%q]", seg.key)
+		default:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(seg.key)
+		}
+	}
+	return b.String()
+}
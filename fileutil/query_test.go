@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testPackageJSON = `{
+  "name": "web",
+  "scripts": {"build": "next build"},
+  "dependencies": {"next": "14.0.0", "react": "18.2.0"},
+  "devDependencies": {"@types/node": "20.0.0", "eslint-config-next": "14.0.0"},
+  "workspaces": ["apps/web", "apps/api"],
+  "config": {"a.b": {"c]": true}}
+}`
+
+const testAzureYAML = `name: todo
+services:
+  api:
+    host: containerapp
+    ports: [8080, 8443]
+  web:
+    host: staticwebapp
+1: numeric key
+`
+
+func writeQueryFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestQueryJSONFile(t *testing.T) {
+	path := writeQueryFile(t, "package.json", testPackageJSON)
+
+	tests := []struct {
+		query string
+		want  interface{}
+	}{
+		{"dependencies.next", "14.0.0"},
+		{"$.scripts.build", "next build"},
+		{"workspaces[1]", "apps/api"},
+		{"workspaces.0", "apps/web"},
+		{`devDependencies["@types/node"]`, "20.0.0"},
+		{`config["a.b"]['c]']`, true},
+		{"workspaces", []interface{}{"apps/web", "apps/api"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := QueryJSONFile(path, tt.query)
+			if err != nil {
+				t.Fatalf("QueryJSONFile(%q) error = %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryJSONFile(%q) = %#v, want %#v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryJSONFile_WholeDocument(t *testing.T) {
+	path := writeQueryFile(t, "package.json", testPackageJSON)
+	got, err := QueryJSONFile(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc, ok := got.(map[string]interface{}); !ok || doc["name"] != "web" {
+		t.Errorf("QueryJSONFile(\"\") = %#v", got)
+	}
+}
+
+func TestQueryJSONFile_NoMatch(t *testing.T) {
+	path := writeQueryFile(t, "package.json", testPackageJSON)
+
+	// "next" appears as a substring elsewhere, but only dependencies.next is a match.
+	for _, query := range []string{
+		"devDependencies.next",
+		"dependencies.next.version",
+		"workspaces[5]",
+		"workspaces.name",
+		"dependencies[0]",
+		"missing",
+	} {
+		_, err := QueryJSONFile(path, query)
+		if !errors.Is(err, ErrNoMatch) {
+			t.Errorf("QueryJSONFile(%q) error = %v, want ErrNoMatch", query, err)
+		}
+	}
+}
+
+func TestQueryJSONFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	bad := writeQueryFile(t, "bad.json", "{not json")
+
+	tests := []struct {
+		name  string
+		path  string
+		query string
+	}{
+		{"missing file", filepath.Join(dir, "missing.json"), "a"},
+		{"invalid JSON", bad, "a"},
+		{"traversal", "../../etc/passwd", "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := QueryJSONFile(tt.path, tt.query); err == nil || errors.Is(err, ErrNoMatch) {
+				t.Errorf("QueryJSONFile() error = %v, want read or parse error", err)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Invalid(t *testing.T) {
+	for _, query := range []string{"a..b", "a.", "a[", "a[x]", `a["b]`, `a["b"`} {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("parseQuery(%q) expected error", query)
+		}
+	}
+}
+
+func TestQueryYAMLFile(t *testing.T) {
+	path := writeQueryFile(t, "azure.yaml", testAzureYAML)
+
+	tests := []struct {
+		query string
+		want  interface{}
+	}{
+		{"name", "todo"},
+		{"services.api.host", "containerapp"},
+		{"services.api.ports[1]", 8443},
+		{"1", "numeric key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := QueryYAMLFile(path, tt.query)
+			if err != nil {
+				t.Fatalf("QueryYAMLFile(%q) error = %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryYAMLFile(%q) = %#v, want %#v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := QueryYAMLFile(path, "services.worker"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("QueryYAMLFile(services.worker) error = %v, want ErrNoMatch", err)
+	}
+}
+
+func TestHasJSONPathAndHasYAMLPath(t *testing.T) {
+	pkg := writeQueryFile(t, "package.json", testPackageJSON)
+	azure := writeQueryFile(t, "azure.yaml", testAzureYAML)
+
+	if !HasJSONPath(pkg, "dependencies.next") {
+		t.Error("HasJSONPath(dependencies.next) = false, want true")
+	}
+	if HasJSONPath(pkg, "devDependencies.next") {
+		t.Error("HasJSONPath(devDependencies.next) = true, want false")
+	}
+	if HasJSONPath(filepath.Join(t.TempDir(), "missing.json"), "a") {
+		t.Error("HasJSONPath on missing file = true, want false")
+	}
+	if !HasYAMLPath(azure, "services.web") {
+		t.Error("HasYAMLPath(services.web) = false, want true")
+	}
+	if HasYAMLPath(azure, "services.web.port") {
+		t.Error("HasYAMLPath(services.web.port) = true, want false")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrTransactionDone is returned when a Transaction is used after Commit or
+// Discard.
+var ErrTransactionDone = errors.New("transaction already committed or discarded")
+
+// commitRename renames a staged temp file onto its target. Tests replace it
+// to simulate failures part-way through a commit.
+var commitRename = renameWithRetry
+
+// Transaction writes several files so that either all of them are updated or
+// none are. Stage records the new contents; Commit writes every temp file
+// first, then renames them into place, restoring already-replaced files if a
+// later rename fails.
+//
+// A Transaction is not safe for concurrent use.
+//
+// Example:
+//
+//	tx := fileutil.NewTransaction()
+//	tx.Stage("azure.yaml", configData)
+//	tx.Stage("azure.lock", lockData)
+//	if err := tx.StageJSON("manifest.json", manifest); err != nil {
+//	    return err
+//	}
+//	if err := tx.Commit(); err != nil {
+//	    return err // no file was changed
+//	}
+type Transaction struct {
+	staged []stagedFile
+	done   bool
+}
+
+// stagedFile is a pending write.
+type stagedFile struct {
+	path string
+	data []byte
+	perm os.FileMode
+}
+
+// original is a target's state before Commit, used for rollback.
+type original struct {
+	existed bool
+	data    []byte
+	perm    os.FileMode
+}
+
+// NewTransaction creates an empty Transaction.
+func NewTransaction() *Transaction {
+	return &Transaction{}
+}
+
+// Stage records data to be written to path with FilePermission on Commit.
+// Staging the same path again replaces the earlier data.
+func (tx *Transaction) Stage(path string, data []byte) {
+	tx.StagePerm(path, data, FilePermission)
+}
+
+// StagePerm records data to be written to path with perm on Commit.
+func (tx *Transaction) StagePerm(path string, data []byte, perm os.FileMode) {
+	path = filepath.Clean(path)
+	for i := range tx.staged {
+		if tx.staged[i].path == path {
+			tx.staged[i] = stagedFile{path: path, data: data, perm: perm}
+			return
+		}
+	}
+	tx.staged = append(tx.staged, stagedFile{path: path, data: data, perm: perm})
+}
+
+// StageJSON records v, encoded as indented JSON, to be written to path on Commit.
+func (tx *Transaction) StageJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON for %s: %w", path, err)
+	}
+	tx.Stage(path, data)
+	return nil
+}
+
+// Paths returns the staged paths in the order they were first staged.
+func (tx *Transaction) Paths() []string {
+	paths := make([]string, len(tx.staged))
+	for i, f := range tx.staged {
+		paths[i] = f.path
+	}
+	return paths
+}
+
+// Discard abandons the staged writes. It is a no-op after Commit.
+func (tx *Transaction) Discard() {
+	tx.staged = nil
+	tx.done = true
+}
+
+// Commit writes all staged files. Temp files are written and synced next to
+// each target before any target is touched; if that fails, no file changes.
+// Targets are then replaced by rename in staging order. If a rename fails,
+// targets already replaced are restored to their previous contents (or
+// removed if they did not exist) and the error is returned, joined with any
+// rollback errors.
+func (tx *Transaction) Commit() error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+	tx.done = true
+
+	originals := make([]original, len(tx.staged))
+	for i, f := range tx.staged {
+		orig, err := readOriginal(f.path)
+		if err != nil {
+			return err
+		}
+		originals[i] = orig
+	}
+
+	temps := make([]string, 0, len(tx.staged))
+	removeTemps := func(paths []string) {
+		for _, p := range paths {
+			_ = os.Remove(p)
+		}
+	}
+	for _, f := range tx.staged {
+		tmp, err := writeTemp(f)
+		if err != nil {
+			removeTemps(temps)
+			return err
+		}
+		temps = append(temps, tmp)
+	}
+
+	for i, f := range tx.staged {
+		if err := commitRename(temps[i], f.path); err != nil {
+			removeTemps(temps[i:])
+			err = fmt.Errorf("failed to commit %s: %w", f.path, err)
+			return errors.Join(err, tx.rollback(i, originals))
+		}
+	}
+	return nil
+}
+
+// rollback restores the first n staged targets to their original state.
+func (tx *Transaction) rollback(n int, originals []original) error {
+	var errs []error
+	for i := n - 1; i >= 0; i-- {
+		path := tx.staged[i].path
+		orig := originals[i]
+		var err error
+		if orig.existed {
+			err = AtomicWriteFile(path, orig.data, orig.perm)
+		} else {
+			err = os.Remove(path)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to roll back %s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readOriginal captures the current contents of path for rollback.
+func readOriginal(path string) (original, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return original{}, nil
+	}
+	if err != nil {
+		return original{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return original{}, fmt.Errorf("cannot write %s: is a directory", path)
+	}
+	// #nosec G304 -- path is a caller-staged target being backed up
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return original{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return original{existed: true, data: data, perm: info.Mode().Perm()}, nil
+}
+
+// writeTemp writes f's data to a synced temp file next to its target and
+// returns the temp path.
+func writeTemp(f stagedFile) (string, error) {
+	tmpFile, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp.*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file for %s: %w", f.path, err)
+	}
+	tmpPath := tmpFile.Name()
+
+	_, err = tmpFile.Write(f.data)
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, f.perm)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write temp file for %s: %w", f.path, err)
+	}
+	return tmpPath, nil
+}
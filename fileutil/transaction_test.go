@@ -0,0 +1,167 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s): %v", path, err)
+	}
+	return string(data)
+}
+
+// assertNoTempFiles fails if any transaction temp files remain in dir.
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp.") {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestTransaction_Commit(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "azure.yaml")
+	lock := filepath.Join(dir, "azure.lock")
+	manifest := filepath.Join(dir, "manifest.json")
+	if err := os.WriteFile(config, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := NewTransaction()
+	tx.Stage(config, []byte("new config"))
+	tx.Stage(lock, []byte("draft"))
+	tx.Stage(lock, []byte("lock"))
+	if err := tx.StageJSON(manifest, map[string]int{"version": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got := tx.Paths(); len(got) != 3 {
+		t.Errorf("Paths() = %v, want 3 unique paths", got)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if got := readString(t, config); got != "new config" {
+		t.Errorf("config = %q", got)
+	}
+	if got := readString(t, lock); got != "lock" {
+		t.Errorf("lock = %q, want last staged data", got)
+	}
+	if got := readString(t, manifest); !strings.Contains(got, `"version": 2`) {
+		t.Errorf("manifest = %q", got)
+	}
+	assertNoTempFiles(t, dir)
+
+	if err := tx.Commit(); !errors.Is(err, ErrTransactionDone) {
+		t.Errorf("second Commit() error = %v, want ErrTransactionDone", err)
+	}
+}
+
+func TestTransaction_RollbackOnRenameFailure(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "config.json")
+	created := filepath.Join(dir, "new.json")
+	failing := filepath.Join(dir, "manifest.json")
+	if err := os.WriteFile(existing, []byte("original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := commitRename
+	defer func() { commitRename = orig }()
+	commitRename = func(src, dst string) error {
+		if dst == failing {
+			return errors.New("simulated rename failure")
+		}
+		return orig(src, dst)
+	}
+
+	tx := NewTransaction()
+	tx.Stage(existing, []byte("updated"))
+	tx.Stage(created, []byte("created"))
+	tx.Stage(failing, []byte("manifest"))
+
+	err := tx.Commit()
+	if err == nil || !strings.Contains(err.Error(), "simulated rename failure") {
+		t.Fatalf("Commit() error = %v, want simulated failure", err)
+	}
+	if got := readString(t, existing); got != "original" {
+		t.Errorf("existing file = %q, want rolled back to original", got)
+	}
+	if info, err := os.Stat(existing); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("existing file mode = %v, want 0600 preserved", info.Mode().Perm())
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("newly created file should be removed on rollback, stat err = %v", err)
+	}
+	if _, err := os.Stat(failing); !os.IsNotExist(err) {
+		t.Errorf("failing target should not exist, stat err = %v", err)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestTransaction_StageFailureLeavesFilesUntouched(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(existing, []byte("original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := NewTransaction()
+	tx.Stage(existing, []byte("updated"))
+	tx.Stage(filepath.Join(dir, "missing", "file.json"), []byte("x"))
+
+	if err := tx.Commit(); err == nil {
+		t.Fatal("Commit() expected error for missing directory")
+	}
+	if got := readString(t, existing); got != "original" {
+		t.Errorf("existing file = %q, want untouched", got)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestTransaction_DirectoryTarget(t *testing.T) {
+	dir := t.TempDir()
+	tx := NewTransaction()
+	tx.Stage(dir, []byte("x"))
+	if err := tx.Commit(); err == nil {
+		t.Error("Commit() expected error when target is a directory")
+	}
+}
+
+func TestTransaction_Discard(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+
+	tx := NewTransaction()
+	tx.Stage(path, []byte("x"))
+	tx.Discard()
+	if err := tx.Commit(); !errors.Is(err, ErrTransactionDone) {
+		t.Errorf("Commit() after Discard error = %v, want ErrTransactionDone", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("discarded file was written")
+	}
+}
+
+func TestTransaction_StageJSONError(t *testing.T) {
+	tx := NewTransaction()
+	if err := tx.StageJSON(filepath.Join(t.TempDir(), "x.json"), func() {}); err == nil {
+		t.Error("StageJSON() expected marshal error")
+	}
+}
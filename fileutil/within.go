@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// EnsureDirWithin creates the directory rel (and any missing parents) inside
+// base and returns its resolved absolute path.
+//
+// Unlike EnsureDir followed by a path check, every existing component is
+// resolved as it is walked, so a symbolic link (or Windows junction) in the
+// middle of rel that points outside base is rejected before anything is
+// created through it. The final directory is checked with
+// security.ValidatePathWithinBases. base must already exist; rel must be
+// relative and must not contain "..".
+//
+// Example:
+//
+//	outDir, err := fileutil.EnsureDirWithin(projectDir, userOutputDir)
+//	if err != nil {
+//	    return fmt.Errorf("invalid output directory: %w", err)
+//	}
+func EnsureDirWithin(base, rel string) (string, error) {
+	realBase, err := resolveBaseDir(base)
+	if err != nil {
+		return "", err
+	}
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" {
+		return "", fmt.Errorf("%w: %q must be relative to the base directory", security.ErrInvalidPath, rel)
+	}
+
+	cur := realBase
+	for _, part := range strings.Split(filepath.Clean(rel), string(filepath.Separator)) {
+		if part == "" || part == "." {
+			continue
+		}
+		if part == ".." {
+			return "", fmt.Errorf("%w: %q contains a parent directory reference", security.ErrPathTraversal, rel)
+		}
+
+		next := filepath.Join(cur, part)
+		if err := os.Mkdir(next, DirPermission); err != nil && !os.IsExist(err) {
+			return "", fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		resolved, err := filepath.EvalSymlinks(next)
+		if err != nil {
+			return "", fmt.Errorf("%w: cannot resolve %s: %w", security.ErrInvalidPath, next, err)
+		}
+		if !isWithin(resolved, realBase) {
+			return "", fmt.Errorf("%w: %s resolves outside %s", security.ErrPathTraversal, next, base)
+		}
+		info, err := os.Stat(resolved)
+		if err != nil {
+			return "", fmt.Errorf("failed to create directory: %w", err)
+		}
+		if !info.IsDir() {
+			return "", fmt.Errorf("failed to create directory: %s is not a directory", next)
+		}
+		cur = resolved
+	}
+
+	return security.ValidatePathWithinBases(cur, realBase)
+}
+
+// TempDirWithin creates a new temporary directory inside base, like
+// os.MkdirTemp, and returns its resolved absolute path. base must exist and
+// its resolved location is used, so a symlinked base cannot redirect the
+// directory between validation and creation. The caller is responsible for
+// removing the directory.
+//
+// Example:
+//
+//	staging, err := fileutil.TempDirWithin(outDir, "stage-*")
+//	if err != nil {
+//	    return err
+//	}
+//	defer os.RemoveAll(staging)
+func TempDirWithin(base, pattern string) (string, error) {
+	realBase, err := resolveBaseDir(base)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp(realBase, pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	resolved, err := security.ValidatePathWithinBases(dir, realBase)
+	if err != nil {
+		_ = os.Remove(dir)
+		return "", err
+	}
+	return resolved, nil
+}
+
+// resolveBaseDir returns the absolute, symlink-resolved path of base, which
+// must be an existing directory.
+func resolveBaseDir(base string) (string, error) {
+	if base == "" {
+		return "", fmt.Errorf("%w: empty base directory", security.ErrInvalidPath)
+	}
+	abs, err := filepath.Abs(base)
+	if err != nil {
+		return "", fmt.Errorf("%w: cannot resolve base directory: %w", security.ErrInvalidPath, err)
+	}
+	realBase, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("%w: cannot resolve base directory: %w", security.ErrInvalidPath, err)
+	}
+	info, err := os.Stat(realBase)
+	if err != nil {
+		return "", fmt.Errorf("%w: cannot resolve base directory: %w", security.ErrInvalidPath, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%w: base %s is not a directory", security.ErrInvalidPath, base)
+	}
+	return realBase, nil
+}
+
+// isWithin reports whether path is dir or inside it. Both must be clean,
+// absolute, and symlink-resolved.
+func isWithin(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
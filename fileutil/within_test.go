@@ -0,0 +1,192 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// resolvedTempDir returns a temp dir with symlinks resolved (macOS /var).
+func resolvedTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func symlinkOrSkip(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		if runtime.GOOS == "windows" {
+			t.Skipf("symlinks unavailable: %v", err)
+		}
+		t.Fatal(err)
+	}
+}
+
+func TestEnsureDirWithin(t *testing.T) {
+	base := resolvedTempDir(t)
+
+	tests := []struct {
+		name string
+		rel  string
+		want string
+	}{
+		{"single", "out", filepath.Join(base, "out")},
+		{"nested", filepath.Join("a", "b", "c"), filepath.Join(base, "a", "b", "c")},
+		{"existing", "out", filepath.Join(base, "out")},
+		{"dot", ".", base},
+		{"redundant separators", "x" + string(filepath.Separator) + "." + string(filepath.Separator) + "y", filepath.Join(base, "x", "y")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EnsureDirWithin(base, tt.rel)
+			if err != nil {
+				t.Fatalf("EnsureDirWithin(%q) error = %v", tt.rel, err)
+			}
+			if got != tt.want {
+				t.Errorf("EnsureDirWithin(%q) = %q, want %q", tt.rel, got, tt.want)
+			}
+			if info, err := os.Stat(got); err != nil || !info.IsDir() {
+				t.Errorf("EnsureDirWithin(%q) did not create a directory: %v", tt.rel, err)
+			}
+		})
+	}
+}
+
+func TestEnsureDirWithin_Rejects(t *testing.T) {
+	base := resolvedTempDir(t)
+	if err := os.WriteFile(filepath.Join(base, "file"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		base    string
+		rel     string
+		wantErr error
+	}{
+		{"parent reference", base, filepath.Join("a", "..", "..", "etc"), security.ErrPathTraversal},
+		{"leading parent", base, "..", security.ErrPathTraversal},
+		{"absolute", base, filepath.Join(base, "abs"), security.ErrInvalidPath},
+		{"empty base", "", "out", security.ErrInvalidPath},
+		{"missing base", filepath.Join(base, "missing"), "out", security.ErrInvalidPath},
+		{"file base", filepath.Join(base, "file"), "out", security.ErrInvalidPath},
+		{"through file", base, filepath.Join("file", "sub"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EnsureDirWithin(tt.base, tt.rel)
+			if err == nil {
+				t.Fatalf("EnsureDirWithin(%q, %q) error = nil, want error", tt.base, tt.rel)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("EnsureDirWithin(%q, %q) error = %v, want %v", tt.base, tt.rel, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnsureDirWithin_SymlinkEscape(t *testing.T) {
+	base := resolvedTempDir(t)
+	outside := resolvedTempDir(t)
+	symlinkOrSkip(t, outside, filepath.Join(base, "link"))
+
+	_, err := EnsureDirWithin(base, filepath.Join("link", "pwned"))
+	if !errors.Is(err, security.ErrPathTraversal) {
+		t.Fatalf("EnsureDirWithin() through escaping symlink error = %v, want ErrPathTraversal", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "pwned")); !os.IsNotExist(err) {
+		t.Error("EnsureDirWithin() created a directory outside the base")
+	}
+}
+
+func TestEnsureDirWithin_SymlinkInsideBase(t *testing.T) {
+	base := resolvedTempDir(t)
+	real := filepath.Join(base, "real")
+	if err := os.Mkdir(real, 0750); err != nil {
+		t.Fatal(err)
+	}
+	symlinkOrSkip(t, real, filepath.Join(base, "alias"))
+
+	got, err := EnsureDirWithin(base, filepath.Join("alias", "sub"))
+	if err != nil {
+		t.Fatalf("EnsureDirWithin() error = %v", err)
+	}
+	if want := filepath.Join(real, "sub"); got != want {
+		t.Errorf("EnsureDirWithin() = %q, want resolved %q", got, want)
+	}
+}
+
+func TestEnsureDirWithin_SymlinkedBase(t *testing.T) {
+	real := resolvedTempDir(t)
+	link := filepath.Join(resolvedTempDir(t), "base")
+	symlinkOrSkip(t, real, link)
+
+	got, err := EnsureDirWithin(link, "out")
+	if err != nil {
+		t.Fatalf("EnsureDirWithin() error = %v", err)
+	}
+	if want := filepath.Join(real, "out"); got != want {
+		t.Errorf("EnsureDirWithin() = %q, want %q", got, want)
+	}
+}
+
+func TestTempDirWithin(t *testing.T) {
+	base := resolvedTempDir(t)
+
+	dir, err := TempDirWithin(base, "stage-*")
+	if err != nil {
+		t.Fatalf("TempDirWithin() error = %v", err)
+	}
+	if filepath.Dir(dir) != base || !strings.HasPrefix(filepath.Base(dir), "stage-") {
+		t.Errorf("TempDirWithin() = %q, want stage-* under %q", dir, base)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("TempDirWithin() did not create a directory: %v", err)
+	}
+
+	other, err := TempDirWithin(base, "stage-*")
+	if err != nil || other == dir {
+		t.Errorf("second TempDirWithin() = %q, %v, want a new directory", other, err)
+	}
+
+	if _, err := TempDirWithin(filepath.Join(base, "missing"), ""); !errors.Is(err, security.ErrInvalidPath) {
+		t.Errorf("TempDirWithin() with missing base error = %v, want ErrInvalidPath", err)
+	}
+	if _, err := TempDirWithin(base, "bad"+string(filepath.Separator)+"*"); err == nil {
+		t.Error("TempDirWithin() with a separator in the pattern should fail")
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	sep := string(filepath.Separator)
+	root := sep + "base"
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{root, true},
+		{root + sep + "a", true},
+		{root + "2", false},
+		{sep + "other", false},
+	}
+	for _, tt := range tests {
+		if got := isWithin(tt.path, root); got != tt.want {
+			t.Errorf("isWithin(%q, %q) = %v, want %v", tt.path, root, got, tt.want)
+		}
+	}
+	if !isWithin(root+"a", sep) {
+		t.Error("isWithin() should treat the filesystem root as containing everything")
+	}
+}
@@ -0,0 +1,110 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultCheckAllConcurrency is the number of services CheckAll checks at
+// once when CheckAllOptions.MaxConcurrency is zero.
+const DefaultCheckAllConcurrency = 8
+
+// CheckAllOptions configures CheckAll.
+type CheckAllOptions struct {
+	// MaxConcurrency is the number of services checked at once. Defaults to
+	// DefaultCheckAllConcurrency; a negative value checks every service at
+	// once. MonitorConfig.MaxConcurrentChecks still applies on top of it.
+	MaxConcurrency int
+}
+
+// CheckAll checks services with a bounded pool of workers and returns a
+// report whose Services are in the same order as services, with the summary
+// computed as by Summarize. The report's Project is left for the caller to
+// fill in.
+//
+// Services not yet checked when ctx is canceled are reported as
+// HealthStatusUnknown with the context error.
+//
+// Example:
+//
+//	report := checker.CheckAll(ctx, services, healthcheck.CheckAllOptions{MaxConcurrency: 4})
+//	if report.Summary.Overall != healthcheck.HealthStatusHealthy {
+//	    return fmt.Errorf("%d of %d services unhealthy", report.Summary.Unhealthy, report.Summary.Total)
+//	}
+func (c *HealthChecker) CheckAll(ctx context.Context, services []ServiceInfo, opts CheckAllOptions) HealthReport {
+	limit := opts.MaxConcurrency
+	if limit == 0 {
+		limit = DefaultCheckAllConcurrency
+	}
+
+	indexes := make([]int, len(services))
+	for i := range services {
+		indexes[i] = i
+	}
+	results := make([]HealthCheckResult, len(services))
+	c.checkConcurrently(ctx, services, indexes, results, limit)
+
+	return HealthReport{
+		Timestamp: time.Now(),
+		Services:  results,
+		Summary:   calculateSummary(results),
+	}
+}
+
+// checkConcurrently checks the services at the given indexes, storing each
+// result at the same index in results. At most limit checks run at once;
+// limit <= 0 means no limit.
+func (c *HealthChecker) checkConcurrently(ctx context.Context, services []ServiceInfo, indexes []int, results []HealthCheckResult, limit int) {
+	if limit <= 0 || limit > len(indexes) {
+		limit = len(indexes)
+	}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range limit {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				if err := ctx.Err(); err != nil {
+					results[i] = canceledResult(services[i], err)
+					continue
+				}
+				results[i] = c.CheckService(ctx, services[i])
+			}
+		}()
+	}
+	for _, i := range indexes {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+}
+
+// acquireCheckSlot waits for one of the MonitorConfig.MaxConcurrentChecks
+// slots and returns a function that releases it.
+func (c *HealthChecker) acquireCheckSlot(ctx context.Context) (func(), error) {
+	if c.checkSlots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.checkSlots <- struct{}{}:
+		return func() { <-c.checkSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// canceledResult reports a service that was not checked because ctx ended.
+func canceledResult(svc ServiceInfo, err error) HealthCheckResult {
+	return HealthCheckResult{
+		ServiceName: svc.Name,
+		Timestamp:   time.Now(),
+		Status:      HealthStatusUnknown,
+		Error:       fmt.Sprintf("health check not run: %v", err),
+		ServiceType: svc.Type,
+		ServiceMode: svc.Mode,
+	}
+}
@@ -0,0 +1,161 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyServer counts concurrent requests and records the peak.
+type concurrencyServer struct {
+	*httptest.Server
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func newConcurrencyServer(t *testing.T, delay time.Duration) *concurrencyServer {
+	t.Helper()
+	cs := &concurrencyServer{}
+	cs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := cs.inFlight.Add(1)
+		defer cs.inFlight.Add(-1)
+		for {
+			peak := cs.peak.Load()
+			if n <= peak || cs.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		if strings.Contains(r.URL.Path, "down") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(cs.Close)
+	return cs
+}
+
+func (cs *concurrencyServer) services(n int, down ...int) []ServiceInfo {
+	services := make([]ServiceInfo, n)
+	for i := range services {
+		path := fmt.Sprintf("/svc%d/health", i)
+		for _, d := range down {
+			if d == i {
+				path = fmt.Sprintf("/svc%d/down", i)
+			}
+		}
+		services[i] = ServiceInfo{
+			Name:        fmt.Sprintf("svc%d", i),
+			HealthCheck: &HealthCheckConfig{Test: []string{cs.URL + path}},
+		}
+	}
+	return services
+}
+
+func TestCheckAll_OrderAndSummary(t *testing.T) {
+	cs := newConcurrencyServer(t, 10*time.Millisecond)
+	services := cs.services(10, 3, 7)
+	checker := NewHealthChecker(MonitorConfig{Timeout: 2 * time.Second})
+
+	report := checker.CheckAll(context.Background(), services, CheckAllOptions{MaxConcurrency: 3})
+
+	if len(report.Services) != len(services) {
+		t.Fatalf("got %d results, want %d", len(report.Services), len(services))
+	}
+	for i, r := range report.Services {
+		want := HealthStatusHealthy
+		if i == 3 || i == 7 {
+			want = HealthStatusUnhealthy
+		}
+		if r.ServiceName != services[i].Name || r.Status != want {
+			t.Errorf("result %d = %s/%s, want %s/%s", i, r.ServiceName, r.Status, services[i].Name, want)
+		}
+	}
+	if s := report.Summary; s.Total != 10 || s.Healthy != 8 || s.Unhealthy != 2 || s.Overall != HealthStatusUnhealthy {
+		t.Errorf("Summary = %+v", s)
+	}
+	if report.Timestamp.IsZero() {
+		t.Error("Timestamp not set")
+	}
+	if peak := cs.peak.Load(); peak > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak)
+	}
+}
+
+func TestCheckAll_Concurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      MonitorConfig
+		opts        CheckAllOptions
+		wantAtMost  int32
+		wantAtLeast int32
+	}{
+		{"default limit", MonitorConfig{}, CheckAllOptions{}, DefaultCheckAllConcurrency, 2},
+		{"unbounded", MonitorConfig{}, CheckAllOptions{MaxConcurrency: -1}, 12, DefaultCheckAllConcurrency + 1},
+		{"global limit", MonitorConfig{MaxConcurrentChecks: 2}, CheckAllOptions{MaxConcurrency: -1}, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := newConcurrencyServer(t, 50*time.Millisecond)
+			tt.config.Timeout = 5 * time.Second
+			checker := NewHealthChecker(tt.config)
+
+			report := checker.CheckAll(context.Background(), cs.services(12), tt.opts)
+			if report.Summary.Healthy != 12 {
+				t.Fatalf("Summary = %+v, want 12 healthy", report.Summary)
+			}
+			if peak := cs.peak.Load(); peak > tt.wantAtMost || peak < tt.wantAtLeast {
+				t.Errorf("peak concurrency = %d, want between %d and %d", peak, tt.wantAtLeast, tt.wantAtMost)
+			}
+		})
+	}
+}
+
+func TestCheckAll_GlobalLimitAcrossCalls(t *testing.T) {
+	cs := newConcurrencyServer(t, 30*time.Millisecond)
+	checker := NewHealthChecker(MonitorConfig{Timeout: 5 * time.Second, MaxConcurrentChecks: 3})
+
+	done := make(chan HealthReport, 2)
+	for range 2 {
+		go func() {
+			done <- checker.CheckAll(context.Background(), cs.services(6), CheckAllOptions{MaxConcurrency: -1})
+		}()
+	}
+	for range 2 {
+		if report := <-done; report.Summary.Healthy != 6 {
+			t.Errorf("Summary = %+v, want 6 healthy", report.Summary)
+		}
+	}
+	if peak := cs.peak.Load(); peak > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak)
+	}
+}
+
+func TestCheckAll_Canceled(t *testing.T) {
+	cs := newConcurrencyServer(t, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report := NewHealthChecker(MonitorConfig{}).CheckAll(ctx, cs.services(3), CheckAllOptions{})
+	for i, r := range report.Services {
+		if r.ServiceName != fmt.Sprintf("svc%d", i) || r.Status != HealthStatusUnknown || !strings.Contains(r.Error, "context canceled") {
+			t.Errorf("result %d = %+v, want unknown with context error", i, r)
+		}
+	}
+	if report.Summary.Unknown != 3 {
+		t.Errorf("Summary = %+v, want 3 unknown", report.Summary)
+	}
+}
+
+func TestCheckAll_Empty(t *testing.T) {
+	report := NewHealthChecker(MonitorConfig{}).CheckAll(context.Background(), nil, CheckAllOptions{})
+	if len(report.Services) != 0 || report.Summary.Total != 0 || report.Summary.Overall != HealthStatusUnknown {
+		t.Errorf("CheckAll(nil) = %+v", report)
+	}
+}
@@ -0,0 +1,167 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// ErrBreakerDisabled is returned by TripBreaker for a service whose circuit
+// breaker is not enabled.
+var ErrBreakerDisabled = errors.New("circuit breaker is not enabled")
+
+// BreakerState is the state of a service's circuit breaker.
+type BreakerState string
+
+const (
+	// BreakerClosed means checks run normally.
+	BreakerClosed BreakerState = "closed"
+	// BreakerHalfOpen means a limited number of trial checks are allowed
+	// after the breaker timeout.
+	BreakerHalfOpen BreakerState = "half-open"
+	// BreakerOpen means checks are skipped and the service is reported
+	// unhealthy until the breaker timeout passes.
+	BreakerOpen BreakerState = "open"
+	// BreakerDisabled means the service has no circuit breaker.
+	BreakerDisabled BreakerState = "disabled"
+)
+
+// BreakerStatus describes one service's circuit breaker, as returned by
+// BreakerStates.
+type BreakerStatus struct {
+	Service string       `json:"service"`
+	State   BreakerState `json:"state"`
+	// OpenUntil is when an open breaker allows checks again. It is zero
+	// unless State is BreakerOpen.
+	OpenUntil time.Time `json:"openUntil,omitempty"`
+	// ConsecutiveFailures counts failed checks since the last success. The
+	// count starts over each time the breaker changes state.
+	ConsecutiveFailures uint32 `json:"consecutiveFailures"`
+}
+
+// BreakerState returns the state of the named service's circuit breaker.
+// A service that has not been checked yet is BreakerClosed, and one whose
+// breaker is turned off by MonitorConfig or a ServiceOverride is
+// BreakerDisabled.
+func (c *HealthChecker) BreakerState(serviceName string) BreakerState {
+	return c.breakerStatus(serviceName).State
+}
+
+// BreakerStates returns the circuit breaker of every service that has been
+// checked or tripped, sorted by service name. Status commands can show it
+// alongside health results.
+//
+// Example:
+//
+//	for _, b := range checker.BreakerStates() {
+//	    if b.State == healthcheck.BreakerOpen {
+//	        fmt.Printf("%s: breaker open until %s\n", b.Service, b.OpenUntil.Format(time.Kitchen))
+//	    }
+//	}
+func (c *HealthChecker) BreakerStates() []BreakerStatus {
+	names := make(map[string]bool)
+	c.mu.RLock()
+	for name := range c.breakers {
+		names[name] = true
+	}
+	c.mu.RUnlock()
+	c.stateMu.Lock()
+	for name := range c.trippedUntil {
+		names[name] = true
+	}
+	c.stateMu.Unlock()
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	statuses := make([]BreakerStatus, 0, len(sorted))
+	for _, name := range sorted {
+		statuses = append(statuses, c.breakerStatus(name))
+	}
+	return statuses
+}
+
+// ResetBreaker closes the named service's circuit breaker and clears its
+// failure counts, including a trip restored from MonitorConfig.StateFile, so
+// the next check runs immediately. Use it after fixing a service instead of
+// waiting for the breaker timeout or restarting the monitor.
+func (c *HealthChecker) ResetBreaker(serviceName string) {
+	c.mu.Lock()
+	delete(c.breakers, serviceName)
+	c.mu.Unlock()
+
+	c.stateMu.Lock()
+	if _, ok := c.trippedUntil[serviceName]; ok {
+		delete(c.trippedUntil, serviceName)
+		c.stateDirty = true
+	}
+	c.stateMu.Unlock()
+
+	if metricsEnabled.Load() {
+		recordCircuitBreakerState(serviceName, gobreaker.StateClosed)
+	}
+	c.saveStateIfDirty()
+}
+
+// TripBreaker opens the named service's circuit breaker for the breaker
+// timeout, so checks report it unhealthy without contacting it, for example
+// while it is being redeployed. It returns ErrBreakerDisabled if the service
+// has no circuit breaker.
+func (c *HealthChecker) TripBreaker(serviceName string) error {
+	cfg := c.settingsFor(serviceName)
+	if !cfg.enableBreaker {
+		return fmt.Errorf("%w for service %s", ErrBreakerDisabled, serviceName)
+	}
+	c.getOrCreateCircuitBreaker(serviceName)
+
+	c.stateMu.Lock()
+	c.trippedUntil[serviceName] = time.Now().Add(cfg.breakerTimeout)
+	c.stateDirty = true
+	c.stateMu.Unlock()
+
+	if metricsEnabled.Load() {
+		recordCircuitBreakerState(serviceName, gobreaker.StateOpen)
+	}
+	c.saveStateIfDirty()
+	return nil
+}
+
+// breakerStatus builds the status of one service's breaker without creating
+// it.
+func (c *HealthChecker) breakerStatus(serviceName string) BreakerStatus {
+	status := BreakerStatus{Service: serviceName, State: BreakerClosed}
+	if !c.settingsFor(serviceName).enableBreaker {
+		status.State = BreakerDisabled
+		return status
+	}
+
+	c.mu.RLock()
+	breaker := c.breakers[serviceName]
+	c.mu.RUnlock()
+	if breaker != nil {
+		// State may move an expired open breaker to half-open, which calls
+		// recordBreakerState, so it must run before stateMu is taken.
+		switch breaker.State() {
+		case gobreaker.StateOpen:
+			status.State = BreakerOpen
+		case gobreaker.StateHalfOpen:
+			status.State = BreakerHalfOpen
+		}
+		status.ConsecutiveFailures = breaker.Counts().ConsecutiveFailures
+	}
+
+	c.stateMu.Lock()
+	until, ok := c.trippedUntil[serviceName]
+	c.stateMu.Unlock()
+	if ok && time.Now().Before(until) {
+		status.State = BreakerOpen
+		status.OpenUntil = until
+	}
+	return status
+}
@@ -0,0 +1,133 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/jongio/azd-core/testutil"
+)
+
+func newBreakerChecker(t *testing.T, stateFile string) *HealthChecker {
+	t.Helper()
+	disabled := false
+	return NewHealthChecker(MonitorConfig{
+		Timeout:                time.Second,
+		EnableCircuitBreaker:   true,
+		CircuitBreakerFailures: 2,
+		CircuitBreakerTimeout:  time.Minute,
+		StartupGracePeriod:     time.Nanosecond,
+		StateFile:              stateFile,
+		ServiceOverrides: map[string]ServiceOverride{
+			"worker": {EnableCircuitBreaker: &disabled},
+		},
+	})
+}
+
+func TestBreakerState_OpensAfterFailures(t *testing.T) {
+	checker := newBreakerChecker(t, "")
+	down := ServiceInfo{Name: "api", Port: testutil.FreePort(t)}
+
+	if got := checker.BreakerState("api"); got != BreakerClosed {
+		t.Errorf("unchecked service: got %s, want %s", got, BreakerClosed)
+	}
+	if got := checker.BreakerState("worker"); got != BreakerDisabled {
+		t.Errorf("overridden service: got %s, want %s", got, BreakerDisabled)
+	}
+
+	checker.CheckService(context.Background(), down)
+	states := checker.BreakerStates()
+	if len(states) != 1 || states[0].State != BreakerClosed || states[0].ConsecutiveFailures != 1 {
+		t.Fatalf("after one failure: BreakerStates() = %+v", states)
+	}
+
+	checker.CheckService(context.Background(), down)
+	if got := checker.BreakerState("api"); got != BreakerOpen {
+		t.Fatalf("after failures: got %s, want %s", got, BreakerOpen)
+	}
+
+	states = checker.BreakerStates()
+	if len(states) != 1 || states[0].Service != "api" || states[0].State != BreakerOpen {
+		t.Fatalf("BreakerStates() = %+v", states)
+	}
+	if states[0].OpenUntil.IsZero() {
+		t.Errorf("expected OpenUntil for an open breaker, got %+v", states[0])
+	}
+}
+
+func TestResetBreaker(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	checker := newBreakerChecker(t, stateFile)
+	down := ServiceInfo{Name: "api", Port: testutil.FreePort(t)}
+	for range 2 {
+		checker.CheckService(context.Background(), down)
+	}
+	if checker.BreakerState("api") != BreakerOpen {
+		t.Fatal("expected breaker to open")
+	}
+
+	checker.ResetBreaker("api")
+	if got := checker.BreakerState("api"); got != BreakerClosed {
+		t.Errorf("after reset: got %s, want %s", got, BreakerClosed)
+	}
+
+	server := testutil.NewHealthServer(t, testutil.HealthScript{})
+	up := ServiceInfo{Name: "api", Port: server.Port()}
+	if r := checker.CheckService(context.Background(), up); r.Status != HealthStatusHealthy {
+		t.Errorf("expected check to run after reset, got %s (%s)", r.Status, r.Error)
+	}
+
+	var state checkerState
+	if err := fileutil.ReadJSON(stateFile, &state); err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	if _, ok := state.Breakers["api"]; ok {
+		t.Error("reset breaker should be removed from the state file")
+	}
+}
+
+func TestTripBreaker(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	checker := newBreakerChecker(t, stateFile)
+	server := testutil.NewHealthServer(t, testutil.HealthScript{})
+	svc := ServiceInfo{Name: "api", Port: server.Port()}
+
+	if err := checker.TripBreaker("api"); err != nil {
+		t.Fatalf("TripBreaker: %v", err)
+	}
+	if got := checker.BreakerState("api"); got != BreakerOpen {
+		t.Errorf("after trip: got %s, want %s", got, BreakerOpen)
+	}
+	r := checker.CheckService(context.Background(), svc)
+	if r.Status != HealthStatusUnhealthy || server.Requests() != 0 {
+		t.Errorf("expected tripped breaker to short-circuit, got %s with %d requests", r.Status, server.Requests())
+	}
+
+	// The trip is persisted for the next run.
+	restored := newBreakerChecker(t, stateFile)
+	if got := restored.BreakerState("api"); got != BreakerOpen {
+		t.Errorf("restored checker: got %s, want %s", got, BreakerOpen)
+	}
+
+	checker.ResetBreaker("api")
+	if r := checker.CheckService(context.Background(), svc); r.Status != HealthStatusHealthy {
+		t.Errorf("expected healthy after reset, got %s (%s)", r.Status, r.Error)
+	}
+}
+
+func TestTripBreaker_Disabled(t *testing.T) {
+	checker := newBreakerChecker(t, "")
+	if err := checker.TripBreaker("worker"); !errors.Is(err, ErrBreakerDisabled) {
+		t.Errorf("expected ErrBreakerDisabled, got %v", err)
+	}
+	plain := NewHealthChecker(MonitorConfig{})
+	if err := plain.TripBreaker("api"); !errors.Is(err, ErrBreakerDisabled) {
+		t.Errorf("expected ErrBreakerDisabled, got %v", err)
+	}
+	if states := plain.BreakerStates(); len(states) != 0 {
+		t.Errorf("BreakerStates() = %+v, want none", states)
+	}
+}
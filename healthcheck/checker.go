@@ -8,14 +8,19 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"os/exec"
-	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/jongio/azd-core/logutil"
 	"github.com/jongio/azd-core/procutil"
+	"github.com/jongio/azd-core/security"
+	"github.com/jongio/azd-core/urlutil"
 	"github.com/sony/gobreaker"
 	"golang.org/x/time/rate"
 )
@@ -30,10 +35,10 @@ var (
 		MaxIdleConnsPerHost: 10,
 		IdleConnTimeout:     HTTPIdleConnTimeout,
 		DisableKeepAlives:   false,
-		DialContext: (&net.Dialer{
+		DialContext: dualStackDialer(&net.Dialer{
 			Timeout:   HTTPDialTimeout,
 			KeepAlive: HTTPKeepAliveTimeout,
-		}).DialContext,
+		}),
 		TLSHandshakeTimeout:   HTTPTLSHandshakeTimeout,
 		ExpectContinueTimeout: HTTPExpectContinueTimeout,
 	}
@@ -47,12 +52,41 @@ type HealthChecker struct {
 	breakers           map[string]*gobreaker.CircuitBreaker
 	rateLimiters       map[string]*rate.Limiter
 	endpointCache      map[string]string // Maps service:port to successful endpoint path
+	endpointUpdated    map[string]time.Time
 	mu                 sync.RWMutex
 	enableBreaker      bool
 	breakerFailures    int
 	breakerTimeout     time.Duration
 	rateLimit          int
 	startupGracePeriod time.Duration
+	overrides          map[string]ServiceOverride
+
+	logger    *logutil.ComponentLogger
+	logChecks bool
+
+	// disableShellChecks rejects CMD-SHELL checks; shellWarned records the
+	// commands already warned about (see shell.go).
+	disableShellChecks bool
+	shellWarned        sync.Map
+
+	// maintenance holds the valid MonitorConfig.MaintenanceWindows (see
+	// maintenance.go).
+	maintenance []*maintenanceWindow
+
+	// checkSlots bounds concurrent checks when MaxConcurrentChecks is set.
+	checkSlots chan struct{}
+
+	// Recent results per service for Stats (see stats.go).
+	historySize int
+	historyMu   sync.Mutex
+	history     map[string]*resultHistory
+
+	// Persistence of discovered endpoints and breaker trips (see state.go).
+	stateFile    string
+	stateTTL     time.Duration
+	stateMu      sync.Mutex
+	stateDirty   bool
+	trippedUntil map[string]time.Time
 }
 
 // NewHealthChecker creates a new HealthChecker from the given config.
@@ -64,30 +98,113 @@ func NewHealthChecker(config MonitorConfig) *HealthChecker {
 		gracePeriod = startupGracePeriod
 	}
 
-	return &HealthChecker{
+	historySize := config.HistorySize
+	if historySize == 0 {
+		historySize = DefaultHistorySize
+	}
+
+	stateTTL := config.StateTTL
+	if stateTTL == 0 {
+		stateTTL = DefaultStateTTL
+	}
+
+	// The client timeout is a ceiling; CheckService enforces each service's
+	// effective timeout through its context.
+	clientTimeout := config.Timeout
+	for _, o := range config.ServiceOverrides {
+		if clientTimeout > 0 && o.Timeout > clientTimeout {
+			clientTimeout = o.Timeout
+		}
+	}
+
+	checker := &HealthChecker{
 		timeout:            config.Timeout,
 		defaultEndpoint:    config.DefaultEndpoint,
 		breakers:           make(map[string]*gobreaker.CircuitBreaker),
 		rateLimiters:       make(map[string]*rate.Limiter),
 		endpointCache:      make(map[string]string),
+		endpointUpdated:    make(map[string]time.Time),
+		stateFile:          config.StateFile,
+		stateTTL:           stateTTL,
+		trippedUntil:       make(map[string]time.Time),
 		enableBreaker:      config.EnableCircuitBreaker,
 		breakerFailures:    config.CircuitBreakerFailures,
 		breakerTimeout:     config.CircuitBreakerTimeout,
 		rateLimit:          config.RateLimit,
 		startupGracePeriod: gracePeriod,
+		overrides:          config.ServiceOverrides,
+		logger:             config.Logger,
+		logChecks:          config.LogChecks,
+		disableShellChecks: config.DisableShellChecks,
+		maintenance:        compileMaintenanceWindows(config.MaintenanceWindows, config.Logger),
+		historySize:        historySize,
+		history:            make(map[string]*resultHistory),
 		httpClient: &http.Client{
-			Timeout:   config.Timeout,
+			Timeout:   clientTimeout,
 			Transport: sharedHTTPTransport,
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse
 			},
 		},
 	}
+	if config.MaxConcurrentChecks > 0 {
+		checker.checkSlots = make(chan struct{}, config.MaxConcurrentChecks)
+	}
+	if checker.stateFile != "" {
+		checker.loadState()
+	}
+	return checker
+}
+
+// serviceSettings are the effective check settings for one service.
+type serviceSettings struct {
+	timeout            time.Duration
+	startupGracePeriod time.Duration
+	rateLimit          int
+	enableBreaker      bool
+	breakerFailures    int
+	breakerTimeout     time.Duration
+}
+
+// settingsFor merges any ServiceOverride for serviceName over the checker defaults.
+func (c *HealthChecker) settingsFor(serviceName string) serviceSettings {
+	s := serviceSettings{
+		timeout:            c.timeout,
+		startupGracePeriod: c.startupGracePeriod,
+		rateLimit:          c.rateLimit,
+		enableBreaker:      c.enableBreaker,
+		breakerFailures:    c.breakerFailures,
+		breakerTimeout:     c.breakerTimeout,
+	}
+	o, ok := c.overrides[serviceName]
+	if !ok {
+		return s
+	}
+	if o.Timeout > 0 {
+		s.timeout = o.Timeout
+	}
+	if o.StartupGracePeriod > 0 {
+		s.startupGracePeriod = o.StartupGracePeriod
+	}
+	if o.RateLimit > 0 {
+		s.rateLimit = o.RateLimit
+	}
+	if o.EnableCircuitBreaker != nil {
+		s.enableBreaker = *o.EnableCircuitBreaker
+	}
+	if o.CircuitBreakerFailures != 0 {
+		s.breakerFailures = o.CircuitBreakerFailures
+	}
+	if o.CircuitBreakerTimeout > 0 {
+		s.breakerTimeout = o.CircuitBreakerTimeout
+	}
+	return s
 }
 
 // getOrCreateCircuitBreaker gets or creates a circuit breaker for a service.
 func (c *HealthChecker) getOrCreateCircuitBreaker(serviceName string) *gobreaker.CircuitBreaker {
-	if !c.enableBreaker {
+	cfg := c.settingsFor(serviceName)
+	if !cfg.enableBreaker {
 		return nil
 	}
 
@@ -109,19 +226,20 @@ func (c *HealthChecker) getOrCreateCircuitBreaker(serviceName string) *gobreaker
 	settings := gobreaker.Settings{
 		Name:        serviceName,
 		MaxRequests: 3,
-		Interval:    c.breakerTimeout,
-		Timeout:     c.breakerTimeout,
+		Interval:    cfg.breakerTimeout,
+		Timeout:     cfg.breakerTimeout,
 		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			if c.breakerFailures < 0 {
+			if cfg.breakerFailures < 0 {
 				return false
 			}
 			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
-			return counts.Requests >= uint32(c.breakerFailures) && failureRatio >= 0.6
+			return counts.Requests >= uint32(cfg.breakerFailures) && failureRatio >= 0.6
 		},
 		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
 			if metricsEnabled.Load() {
 				recordCircuitBreakerState(name, to)
 			}
+			c.recordBreakerState(name, to)
 		},
 	}
 
@@ -132,7 +250,8 @@ func (c *HealthChecker) getOrCreateCircuitBreaker(serviceName string) *gobreaker
 
 // getOrCreateRateLimiter gets or creates a rate limiter for a service.
 func (c *HealthChecker) getOrCreateRateLimiter(serviceName string) *rate.Limiter {
-	if c.rateLimit <= 0 {
+	limit := c.settingsFor(serviceName).rateLimit
+	if limit <= 0 {
 		return nil
 	}
 
@@ -151,7 +270,7 @@ func (c *HealthChecker) getOrCreateRateLimiter(serviceName string) *rate.Limiter
 		return limiter
 	}
 
-	limiter = rate.NewLimiter(rate.Limit(c.rateLimit), c.rateLimit*2)
+	limiter = rate.NewLimiter(rate.Limit(limit), limit*2)
 	c.rateLimiters[serviceName] = limiter
 
 	return limiter
@@ -173,6 +292,12 @@ func (c *HealthChecker) CheckService(ctx context.Context, svc ServiceInfo) Healt
 		}
 	}
 
+	release, err := c.acquireCheckSlot(ctx)
+	if err != nil {
+		return canceledResult(svc, err)
+	}
+	defer release()
+
 	// Apply rate limiting if configured
 	limiter := c.getOrCreateRateLimiter(serviceName)
 	if limiter != nil {
@@ -186,11 +311,29 @@ func (c *HealthChecker) CheckService(ctx context.Context, svc ServiceInfo) Healt
 		}
 	}
 
+	if timeout := c.settingsFor(serviceName).timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Failures during maintenance are expected, so they bypass the breaker.
+	window, inMaintenance := c.InMaintenance(serviceName, startTime)
 	breaker := c.getOrCreateCircuitBreaker(serviceName)
+	if inMaintenance {
+		breaker = nil
+	}
 
 	var result HealthCheckResult
 
-	if breaker != nil {
+	if breaker != nil && c.isTripped(serviceName) {
+		result = HealthCheckResult{
+			ServiceName: serviceName,
+			Timestamp:   time.Now(),
+			Status:      HealthStatusUnhealthy,
+			Error:       "circuit breaker open - service unavailable",
+		}
+	} else if breaker != nil {
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
@@ -244,6 +387,10 @@ func (c *HealthChecker) CheckService(ctx context.Context, svc ServiceInfo) Healt
 		result = c.performServiceCheck(ctx, svc)
 	}
 
+	if inMaintenance {
+		applyMaintenance(&result, window)
+	}
+
 	duration := time.Since(startTime)
 	result.ResponseTime = duration
 
@@ -254,6 +401,10 @@ func (c *HealthChecker) CheckService(ctx context.Context, svc ServiceInfo) Healt
 	result.ServiceType = svc.Type
 	result.ServiceMode = svc.Mode
 
+	c.recordHistory(result)
+	c.logResult(result)
+	c.saveStateIfDirty()
+
 	return result
 }
 
@@ -268,7 +419,7 @@ func (c *HealthChecker) performServiceCheck(ctx context.Context, svc ServiceInfo
 		result.Uptime = time.Since(svc.StartTime)
 	}
 
-	gracePeriod := c.startupGracePeriod
+	gracePeriod := c.settingsFor(svc.Name).startupGracePeriod
 	if gracePeriod == 0 {
 		gracePeriod = startupGracePeriod
 	}
@@ -291,7 +442,7 @@ func (c *HealthChecker) performServiceCheck(ctx context.Context, svc ServiceInfo
 
 	// 1. Try HTTP health check
 	if svc.Port > 0 {
-		if httpResult := c.tryHTTPHealthCheck(ctx, svc.Port); httpResult != nil {
+		if httpResult := c.tryHTTPHealthCheck(ctx, svc.Name, svc.Host, svc.Port, svc.HealthCheck); httpResult != nil {
 			result.Port = svc.Port
 			return c.buildResultFromHTTPCheck(result, httpResult, svc.Port, isInStartupGracePeriod)
 		}
@@ -306,9 +457,8 @@ func (c *HealthChecker) performServiceCheck(ctx context.Context, svc ServiceInfo
 		portCtx, cancel := context.WithTimeout(ctx, defaultPortCheckTimeout)
 		defer cancel()
 
-		address := fmt.Sprintf("localhost:%d", svc.Port)
-		dialer := net.Dialer{Timeout: defaultPortCheckTimeout}
-		conn, err := dialer.DialContext(portCtx, "tcp", address)
+		dialer := &net.Dialer{Timeout: defaultPortCheckTimeout}
+		conn, err := dualStackDialer(dialer)(portCtx, "tcp", checkAddress(svc.Host, svc.Port))
 
 		if err == nil {
 			_ = conn.Close()
@@ -392,7 +542,7 @@ func (c *HealthChecker) tryCustomHealthCheck(ctx context.Context, config *Health
 	test := config.Test[0]
 
 	if strings.HasPrefix(test, "http://") || strings.HasPrefix(test, "https://") {
-		return c.performHTTPCheck(ctx, test)
+		return c.performHTTPCheck(ctx, test, config)
 	}
 
 	if len(config.Test) > 1 {
@@ -413,9 +563,10 @@ func (c *HealthChecker) tryCustomHealthCheck(ctx context.Context, config *Health
 }
 
 // performHTTPCheck performs a direct HTTP health check to a specific URL.
-func (c *HealthChecker) performHTTPCheck(ctx context.Context, urlStr string) *httpHealthCheckResult {
+// The method, headers, and body come from config when set.
+func (c *HealthChecker) performHTTPCheck(ctx context.Context, urlStr string, config *HealthCheckConfig) *httpHealthCheckResult {
 	startTime := time.Now()
-	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
+	req, err := newHealthRequest(ctx, urlStr, config)
 	if err != nil {
 		return &httpHealthCheckResult{
 			Endpoint: urlStr,
@@ -428,6 +579,11 @@ func (c *HealthChecker) performHTTPCheck(ctx context.Context, urlStr string) *ht
 	responseTime := time.Since(startTime)
 
 	if err != nil {
+		// The error repeats the URL, which may carry a key or token.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = urlutil.RedactForLogging(urlErr.URL)
+		}
 		return &httpHealthCheckResult{
 			Endpoint:     urlStr,
 			ResponseTime: responseTime,
@@ -471,13 +627,33 @@ func (c *HealthChecker) performCommandCheck(ctx context.Context, args []string,
 		return nil
 	}
 
-	startTime := time.Now()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	return c.runExecCheck(cmd, strings.Join(args, " "), svc.HealthCheck)
+}
+
+// runExecCheck runs cmd with the environment and working directory from
+// config and converts the outcome to a health check result.
+func (c *HealthChecker) runExecCheck(cmd *exec.Cmd, endpoint string, config *HealthCheckConfig) *httpHealthCheckResult {
 	result := &httpHealthCheckResult{
-		Endpoint:     strings.Join(args, " "),
+		Endpoint:     endpoint,
 		ResponseTime: 0,
 	}
 
-	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stderr tailBuffer
+	if config != nil {
+		cmd.Dir = config.WorkDir
+		if len(config.Env) > 0 {
+			cmd.Env = os.Environ()
+			for k, v := range config.Env {
+				cmd.Env = append(cmd.Env, k+"="+v)
+			}
+		}
+		if config.CaptureOutput {
+			cmd.Stderr = &stderr
+		}
+	}
+
+	startTime := time.Now()
 	err := cmd.Run()
 	result.ResponseTime = time.Since(startTime)
 
@@ -488,45 +664,54 @@ func (c *HealthChecker) performCommandCheck(ctx context.Context, args []string,
 		result.Status = HealthStatusHealthy
 	}
 
+	if lines := stderr.lastLines(maxCapturedOutputLines); len(lines) > 0 {
+		result.Details = map[string]interface{}{"stderr": lines}
+	}
+
 	return result
 }
 
-// performShellCheck executes a shell command for health check (CMD-SHELL format).
-func (c *HealthChecker) performShellCheck(ctx context.Context, command string, svc ServiceInfo) *httpHealthCheckResult {
-	startTime := time.Now()
-	result := &httpHealthCheckResult{
-		Endpoint:     command,
-		ResponseTime: 0,
-	}
+// tailBuffer is an io.Writer that keeps only the most recent
+// maxCapturedOutputSize bytes written to it.
+type tailBuffer struct {
+	buf []byte
+}
 
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
-	} else {
-		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+// Write implements io.Writer.
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - maxCapturedOutputSize; over > 0 {
+		b.buf = b.buf[over:]
 	}
+	return len(p), nil
+}
 
-	err := cmd.Run()
-	result.ResponseTime = time.Since(startTime)
-
-	if err != nil {
-		result.Status = HealthStatusUnhealthy
-		result.Error = fmt.Sprintf("command failed: %v", err)
-	} else {
-		result.Status = HealthStatusHealthy
+// lastLines returns up to n trailing non-empty lines with secrets redacted.
+func (b *tailBuffer) lastLines(n int) []string {
+	var lines []string
+	for _, line := range strings.Split(string(b.buf), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, security.RedactSecrets(line))
+		}
 	}
-
-	return result
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
 }
 
 // tryHTTPHealthCheck attempts HTTP health checks using smart endpoint discovery.
-func (c *HealthChecker) tryHTTPHealthCheck(ctx context.Context, port int) *httpHealthCheckResult {
-	cacheKey := fmt.Sprintf("port:%d", port)
+// Discovered endpoints are cached per service and port.
+func (c *HealthChecker) tryHTTPHealthCheck(ctx context.Context, serviceName, host string, port int, config *HealthCheckConfig) *httpHealthCheckResult {
+	cacheKey := endpointCacheKey(serviceName, port)
 
 	c.mu.Lock()
 	if c.endpointCache == nil {
 		c.endpointCache = make(map[string]string)
 	}
+	if c.endpointUpdated == nil {
+		c.endpointUpdated = make(map[string]time.Time)
+	}
 	c.mu.Unlock()
 
 	c.mu.RLock()
@@ -538,13 +723,15 @@ func (c *HealthChecker) tryHTTPHealthCheck(ctx context.Context, port int) *httpH
 			return nil
 		}
 
-		result := c.checkSingleEndpoint(ctx, port, cachedEndpoint)
+		result := c.checkSingleEndpoint(ctx, host, port, cachedEndpoint, config)
 		if result != nil && result.Status == HealthStatusHealthy {
 			return result
 		}
 		c.mu.Lock()
 		delete(c.endpointCache, cacheKey)
+		delete(c.endpointUpdated, cacheKey)
 		c.mu.Unlock()
+		c.markStateDirty()
 	}
 
 	endpoints := []string{c.defaultEndpoint}
@@ -561,12 +748,10 @@ func (c *HealthChecker) tryHTTPHealthCheck(ctx context.Context, port int) *httpH
 			return nil
 		}
 
-		result := c.checkSingleEndpoint(ctx, port, endpoint)
+		result := c.checkSingleEndpoint(ctx, host, port, endpoint, config)
 		if result != nil {
 			if result.Status == HealthStatusHealthy {
-				c.mu.Lock()
-				c.endpointCache[cacheKey] = endpoint
-				c.mu.Unlock()
+				c.setCachedEndpoint(cacheKey, endpoint)
 				return result
 			}
 			lastResult = result
@@ -574,20 +759,18 @@ func (c *HealthChecker) tryHTTPHealthCheck(ctx context.Context, port int) *httpH
 	}
 
 	if lastResult == nil {
-		c.mu.Lock()
-		c.endpointCache[cacheKey] = endpointCacheNone
-		c.mu.Unlock()
+		c.setCachedEndpoint(cacheKey, endpointCacheNone)
 	}
 
 	return lastResult
 }
 
 // checkSingleEndpoint performs a single HTTP health check on a specific endpoint.
-func (c *HealthChecker) checkSingleEndpoint(ctx context.Context, port int, endpoint string) *httpHealthCheckResult {
-	url := fmt.Sprintf("http://localhost:%d%s", port, endpoint)
+func (c *HealthChecker) checkSingleEndpoint(ctx context.Context, host string, port int, endpoint string, config *HealthCheckConfig) *httpHealthCheckResult {
+	url := "http://" + checkAddress(host, port) + endpoint
 
 	startTime := time.Now()
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := newHealthRequest(ctx, url, config)
 	if err != nil {
 		return nil
 	}
@@ -631,6 +814,54 @@ func (c *HealthChecker) checkSingleEndpoint(ctx context.Context, port int, endpo
 	return result
 }
 
+// newHealthRequest builds the request for an HTTP health check. Without a
+// config it is a plain GET. A Body without a Method is sent as a POST, and
+// a "Host" entry in Headers overrides the request host.
+func newHealthRequest(ctx context.Context, urlStr string, config *HealthCheckConfig) (*http.Request, error) {
+	if config == nil {
+		return http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(config.Method))
+	if method == "" {
+		method = http.MethodGet
+		if config.Body != "" {
+			method = http.MethodPost
+		}
+	}
+
+	var body io.Reader
+	if config.Body != "" {
+		body = strings.NewReader(config.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range config.Headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	if config.Body != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", defaultHealthBodyContentType(config.Body))
+	}
+	return req, nil
+}
+
+// defaultHealthBodyContentType guesses a Content-Type for a request body
+// that did not set one.
+func defaultHealthBodyContentType(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return "application/json"
+	}
+	return "text/plain; charset=utf-8"
+}
+
 // statusFromHTTPCode determines health status from HTTP status code.
 func (c *HealthChecker) statusFromHTTPCode(statusCode int) HealthStatus {
 	switch {
@@ -772,11 +1003,10 @@ func (c *HealthChecker) performBuildTaskHealthCheck(svc ServiceInfo, isInStartup
 	return result
 }
 
-// checkPort checks if a TCP port is listening.
+// checkPort checks if a TCP port is listening on the IPv4 or IPv6 loopback address.
 func (c *HealthChecker) checkPort(ctx context.Context, port int) bool {
-	address := fmt.Sprintf("localhost:%d", port)
-	dialer := net.Dialer{Timeout: defaultPortCheckTimeout}
-	conn, err := dialer.DialContext(ctx, "tcp", address)
+	dialer := &net.Dialer{Timeout: defaultPortCheckTimeout}
+	conn, err := dualStackDialer(dialer)(ctx, "tcp", checkAddress("", port))
 	if err != nil {
 		return false
 	}
@@ -784,6 +1014,45 @@ func (c *HealthChecker) checkPort(ctx context.Context, port int) bool {
 	return true
 }
 
+// checkAddress returns the host:port to check for a service. Empty and
+// wildcard hosts (0.0.0.0, ::) map to localhost, since a service listening on
+// all interfaces is reachable through loopback.
+func checkAddress(host string, port int) string {
+	switch strings.Trim(host, "[]") {
+	case "", "0.0.0.0", "::":
+		host = "localhost"
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port))
+}
+
+// dualStackDialer wraps dialer so that "localhost" addresses try both
+// 127.0.0.1 and ::1, since services often bind to only one of them and
+// localhost may resolve to a single family. Other hosts are dialed as-is;
+// net.Dialer already tries every address a name resolves to.
+func dualStackDialer(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || !strings.EqualFold(host, "localhost") {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		var firstErr error
+		for _, ip := range []string{"127.0.0.1", "::1"} {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return nil, firstErr
+	}
+}
+
 // suggestTCPErrorAction provides actionable suggestions for TCP connection errors.
 func suggestTCPErrorAction(err error, port int) string {
 	if err == nil {
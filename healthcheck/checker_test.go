@@ -3,13 +3,16 @@ package healthcheck
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jongio/azd-core/testutil"
 	"github.com/sony/gobreaker"
 	"golang.org/x/time/rate"
 )
@@ -326,7 +329,7 @@ func TestPerformHTTPCheck(t *testing.T) {
 			defer server.Close()
 
 			ctx := context.Background()
-			result := checker.performHTTPCheck(ctx, server.URL)
+			result := checker.performHTTPCheck(ctx, server.URL, nil)
 
 			if result == nil {
 				t.Fatal("Expected non-nil result")
@@ -353,7 +356,7 @@ func TestPerformHTTPCheck_Timeout(t *testing.T) {
 	defer server.Close()
 
 	ctx := context.Background()
-	result := checker.performHTTPCheck(ctx, server.URL)
+	result := checker.performHTTPCheck(ctx, server.URL, nil)
 
 	if result == nil {
 		t.Fatal("Expected non-nil result")
@@ -366,6 +369,104 @@ func TestPerformHTTPCheck_Timeout(t *testing.T) {
 	}
 }
 
+func TestPerformHTTPCheck_RequestConfig(t *testing.T) {
+	type seen struct {
+		method, accept, contentType, host, body string
+	}
+
+	tests := []struct {
+		name   string
+		config *HealthCheckConfig
+		want   seen
+	}{
+		{
+			name:   "nil config uses GET",
+			config: nil,
+			want:   seen{method: http.MethodGet},
+		},
+		{
+			name:   "custom method and headers",
+			config: &HealthCheckConfig{Method: "head", Headers: map[string]string{"Accept": "application/health+json"}},
+			want:   seen{method: http.MethodHead, accept: "application/health+json"},
+		},
+		{
+			name:   "body defaults to POST with JSON content type",
+			config: &HealthCheckConfig{Body: `{"probe":"ready"}`},
+			want:   seen{method: http.MethodPost, contentType: "application/json", body: `{"probe":"ready"}`},
+		},
+		{
+			name: "explicit content type and host",
+			config: &HealthCheckConfig{
+				Method:  http.MethodPut,
+				Body:    "ping",
+				Headers: map[string]string{"Content-Type": "application/x-ping", "Host": "api.internal"},
+			},
+			want: seen{method: http.MethodPut, contentType: "application/x-ping", host: "api.internal", body: "ping"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got seen
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				got = seen{
+					method:      r.Method,
+					accept:      r.Header.Get("Accept"),
+					contentType: r.Header.Get("Content-Type"),
+					host:        r.Host,
+					body:        string(body),
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			checker := &HealthChecker{httpClient: &http.Client{Timeout: time.Second}}
+			result := checker.performHTTPCheck(context.Background(), server.URL, tt.config)
+			if result.Status != HealthStatusHealthy {
+				t.Fatalf("Status = %v, error = %s", result.Status, result.Error)
+			}
+			if tt.want.host == "" {
+				tt.want.host = strings.TrimPrefix(server.URL, "http://")
+			}
+			if got != tt.want {
+				t.Errorf("request = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerformHTTPCheck_InvalidMethod(t *testing.T) {
+	checker := &HealthChecker{httpClient: &http.Client{Timeout: time.Second}}
+	result := checker.performHTTPCheck(context.Background(), "http://127.0.0.1:1", &HealthCheckConfig{Method: "BAD METHOD"})
+	if result.Status != HealthStatusUnhealthy || !strings.Contains(result.Error, "failed to create request") {
+		t.Errorf("result = %+v, want request creation failure", result)
+	}
+}
+
+func TestCheckSingleEndpoint_RequestConfig(t *testing.T) {
+	var gotMethod, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotAuth = r.Method, r.Header.Get("X-Health-Key")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, portStr, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	var port int
+	_, _ = fmt.Sscanf(portStr, "%d", &port)
+
+	checker := &HealthChecker{httpClient: &http.Client{Timeout: time.Second}}
+	config := &HealthCheckConfig{Method: http.MethodPost, Headers: map[string]string{"X-Health-Key": "secret"}}
+	result := checker.checkSingleEndpoint(context.Background(), "", port, "/health", config)
+	if result == nil || result.Status != HealthStatusHealthy {
+		t.Fatalf("result = %+v, want healthy", result)
+	}
+	if gotMethod != http.MethodPost || gotAuth != "secret" {
+		t.Errorf("request method = %q, key = %q", gotMethod, gotAuth)
+	}
+}
+
 func TestPerformShellCheck(t *testing.T) {
 	checker := &HealthChecker{}
 	ctx := context.Background()
@@ -441,6 +542,63 @@ func TestPerformCommandCheck(t *testing.T) {
 	}
 }
 
+func TestPerformShellCheck_EnvWorkDirAndOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell syntax")
+	}
+	checker := &HealthChecker{}
+	dir := t.TempDir()
+	svc := ServiceInfo{
+		Name: "api",
+		HealthCheck: &HealthCheckConfig{
+			Env:           map[string]string{"HC_EXPECTED": dir},
+			WorkDir:       dir,
+			CaptureOutput: true,
+		},
+	}
+
+	result := checker.performShellCheck(context.Background(), `test "$(pwd -P)" = "$(cd "$HC_EXPECTED" && pwd -P)"`, svc)
+	if result.Status != HealthStatusHealthy {
+		t.Fatalf("expected command to see Env and WorkDir, got %s (%s)", result.Status, result.Error)
+	}
+	if result.Details != nil {
+		t.Errorf("expected no details without stderr, got %v", result.Details)
+	}
+
+	result = checker.performShellCheck(context.Background(), "for i in $(seq 1 15); do echo line$i >&2; done; echo password=hunter2 >&2; exit 3", svc)
+	if result.Status != HealthStatusUnhealthy {
+		t.Fatalf("expected unhealthy, got %s", result.Status)
+	}
+	lines, ok := result.Details["stderr"].([]string)
+	if !ok || len(lines) != maxCapturedOutputLines {
+		t.Fatalf("expected %d stderr lines, got %v", maxCapturedOutputLines, result.Details["stderr"])
+	}
+	if lines[0] != "line7" {
+		t.Errorf("first captured line = %q, want line7", lines[0])
+	}
+	if last := lines[len(lines)-1]; strings.Contains(last, "hunter2") {
+		t.Errorf("expected secret redacted, got %q", last)
+	}
+
+	svc.HealthCheck.CaptureOutput = false
+	result = checker.performShellCheck(context.Background(), "echo oops >&2; exit 1", svc)
+	if result.Details != nil {
+		t.Errorf("expected no captured output, got %v", result.Details)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	var b tailBuffer
+	_, _ = b.Write([]byte(strings.Repeat("x", maxCapturedOutputSize)))
+	_, _ = b.Write([]byte("\nend\n"))
+	if len(b.buf) != maxCapturedOutputSize {
+		t.Errorf("buffer size = %d, want %d", len(b.buf), maxCapturedOutputSize)
+	}
+	if lines := b.lastLines(1); len(lines) != 1 || lines[0] != "end" {
+		t.Errorf("lastLines = %v", lines)
+	}
+}
+
 func TestBuildResultFromHTTPCheck(t *testing.T) {
 	checker := &HealthChecker{}
 
@@ -616,7 +774,7 @@ func TestCheckSingleEndpoint_404(t *testing.T) {
 	port := tcpAddr.Port
 	ctx := context.Background()
 
-	result := checker.checkSingleEndpoint(ctx, port, "/nonexistent")
+	result := checker.checkSingleEndpoint(ctx, "", port, "/nonexistent", nil)
 
 	if result != nil {
 		t.Error("Expected nil result for 404 response")
@@ -631,7 +789,7 @@ func TestCheckSingleEndpoint_ContextCancelled(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
-	result := checker.checkSingleEndpoint(ctx, 8080, "/health")
+	result := checker.checkSingleEndpoint(ctx, "", 8080, "/health", nil)
 
 	if result != nil {
 		t.Error("Expected nil result for cancelled context")
@@ -826,7 +984,7 @@ func TestPerformHTTPCheck_WithErrorDetails(t *testing.T) {
 		},
 	}
 
-	result := checker.performHTTPCheck(context.Background(), server.URL)
+	result := checker.performHTTPCheck(context.Background(), server.URL, nil)
 
 	if result == nil {
 		t.Fatal("Expected result, got nil")
@@ -881,7 +1039,7 @@ func TestHTTPCheck_StatusCodeSuggestions(t *testing.T) {
 				endpointCache: make(map[string]string),
 			}
 
-			result := checker.tryHTTPHealthCheck(context.Background(), port)
+			result := checker.tryHTTPHealthCheck(context.Background(), "", "", port, nil)
 
 			if result == nil {
 				t.Fatal("Expected result, got nil")
@@ -934,7 +1092,7 @@ func TestTCPCheck_WithSuggestion(t *testing.T) {
 }
 
 func TestProcessCheck_WithSuggestion(t *testing.T) {
-	deadPID := 99999
+	deadPID := testutil.DeadPID(t)
 
 	checker := &HealthChecker{
 		timeout:            5 * time.Second,
@@ -964,3 +1122,194 @@ func TestProcessCheck_WithSuggestion(t *testing.T) {
 		t.Error("Expected non-empty suggestion for failed process check")
 	}
 }
+
+func TestPerformHTTPCheck_ScriptedTransitions(t *testing.T) {
+	checker := &HealthChecker{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+
+	server := testutil.NewHealthServer(t, testutil.HealthScript{
+		Steps: []testutil.HealthStep{
+			testutil.Healthy(1),
+			testutil.Degraded(1),
+			testutil.Failing(http.StatusInternalServerError, 1),
+		},
+	})
+	url := server.URL + testutil.DefaultHealthPath
+
+	for i, want := range []HealthStatus{HealthStatusHealthy, HealthStatusDegraded, HealthStatusUnhealthy} {
+		result := checker.performHTTPCheck(context.Background(), url, nil)
+		if result == nil {
+			t.Fatalf("check %d: expected non-nil result", i)
+		}
+		if result.Status != want {
+			t.Errorf("check %d: Status = %v, want %v", i, result.Status, want)
+		}
+	}
+}
+
+func TestServiceOverrides(t *testing.T) {
+	disabled := false
+	checker := NewHealthChecker(MonitorConfig{
+		Timeout:                2 * time.Second,
+		EnableCircuitBreaker:   true,
+		CircuitBreakerFailures: 5,
+		CircuitBreakerTimeout:  30 * time.Second,
+		RateLimit:              10,
+		StartupGracePeriod:     30 * time.Second,
+		ServiceOverrides: map[string]ServiceOverride{
+			"slow":   {Timeout: 10 * time.Second, StartupGracePeriod: 2 * time.Minute, RateLimit: 1},
+			"legacy": {EnableCircuitBreaker: &disabled},
+		},
+	})
+
+	slow := checker.settingsFor("slow")
+	if slow.timeout != 10*time.Second || slow.startupGracePeriod != 2*time.Minute || slow.rateLimit != 1 {
+		t.Errorf("slow overrides not applied: %+v", slow)
+	}
+	if !slow.enableBreaker || slow.breakerFailures != 5 || slow.breakerTimeout != 30*time.Second {
+		t.Errorf("slow should inherit breaker settings: %+v", slow)
+	}
+	if got := checker.settingsFor("other"); got.timeout != 2*time.Second || got.rateLimit != 10 {
+		t.Errorf("services without overrides should use defaults: %+v", got)
+	}
+
+	if checker.httpClient.Timeout != 10*time.Second {
+		t.Errorf("client timeout = %v, want the largest override", checker.httpClient.Timeout)
+	}
+	if checker.getOrCreateCircuitBreaker("legacy") != nil {
+		t.Error("expected circuit breaker disabled for legacy")
+	}
+	if checker.getOrCreateCircuitBreaker("slow") == nil {
+		t.Error("expected circuit breaker for slow")
+	}
+	if limiter := checker.getOrCreateRateLimiter("slow"); limiter == nil || limiter.Limit() != 1 {
+		t.Errorf("expected 1/s limiter for slow, got %v", limiter)
+	}
+}
+
+func TestServiceOverrides_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	checker := NewHealthChecker(MonitorConfig{
+		Timeout: 2 * time.Second,
+		ServiceOverrides: map[string]ServiceOverride{
+			"fast": {Timeout: 50 * time.Millisecond},
+		},
+	})
+
+	fast := checker.CheckService(context.Background(), ServiceInfo{Name: "fast", HealthCheck: &HealthCheckConfig{Test: []string{server.URL}}})
+	if fast.Status == HealthStatusHealthy {
+		t.Error("expected override timeout to fail the slow endpoint")
+	}
+	normal := checker.CheckService(context.Background(), ServiceInfo{Name: "normal", HealthCheck: &HealthCheckConfig{Test: []string{server.URL}}})
+	if normal.Status != HealthStatusHealthy {
+		t.Errorf("expected healthy with default timeout, got %s (%s)", normal.Status, normal.Error)
+	}
+}
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"", "localhost:8080"},
+		{"localhost", "localhost:8080"},
+		{"0.0.0.0", "localhost:8080"},
+		{"::", "localhost:8080"},
+		{"[::]", "localhost:8080"},
+		{"127.0.0.1", "127.0.0.1:8080"},
+		{"::1", "[::1]:8080"},
+		{"[::1]", "[::1]:8080"},
+		{"devbox.example.com", "devbox.example.com:8080"},
+	}
+	for _, tt := range tests {
+		if got := checkAddress(tt.host, 8080); got != tt.want {
+			t.Errorf("checkAddress(%q, 8080) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+// listenIPv6Loopback listens on [::1], skipping the test if IPv6 is unavailable.
+func listenIPv6Loopback(t *testing.T) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	return listener
+}
+
+func TestCheckPort_IPv6Only(t *testing.T) {
+	listener := listenIPv6Loopback(t)
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	checker := &HealthChecker{}
+	if !checker.checkPort(context.Background(), port) {
+		t.Errorf("checkPort(%d) = false for a service listening only on ::1", port)
+	}
+}
+
+func TestCheckService_Host(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+	port := listener.Addr().(*net.TCPAddr).Port
+	// Close connections immediately so the HTTP probe fails fast and the
+	// check falls back to TCP.
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	checker := NewHealthChecker(MonitorConfig{Timeout: time.Second})
+
+	result := checker.CheckService(context.Background(), ServiceInfo{Name: "v4", Host: "127.0.0.1", Port: port})
+	if result.Status != HealthStatusHealthy || result.CheckType != HealthCheckTypeTCP {
+		t.Errorf("Host 127.0.0.1: got %s/%s (%s), want healthy tcp", result.Status, result.CheckType, result.Error)
+	}
+
+	result = checker.CheckService(context.Background(), ServiceInfo{Name: "v6", Host: "::1", Port: port})
+	if result.Status == HealthStatusHealthy {
+		t.Error("Host ::1 should not reach a service listening only on 127.0.0.1")
+	}
+}
+
+func TestCheckService_HTTPOnIPv6Loopback(t *testing.T) {
+	listener := listenIPv6Loopback(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	_ = server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	checker := NewHealthChecker(MonitorConfig{Timeout: 2 * time.Second, DefaultEndpoint: "/health"})
+	for _, host := range []string{"", "localhost", "::"} {
+		result := checker.CheckService(context.Background(), ServiceInfo{Name: "svc-" + host, Host: host, Port: port})
+		if result.Status != HealthStatusHealthy || result.CheckType != HealthCheckTypeHTTP {
+			t.Errorf("Host %q: got %s/%s (%s), want healthy http", host, result.Status, result.CheckType, result.Error)
+		}
+	}
+}
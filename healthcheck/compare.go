@@ -0,0 +1,153 @@
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Trend is the overall direction of a SummaryComparison.
+type Trend string
+
+const (
+	// TrendUnchanged means no service recovered or regressed.
+	TrendUnchanged Trend = "unchanged"
+	// TrendImproved means services recovered and none regressed.
+	TrendImproved Trend = "improved"
+	// TrendRegressed means services regressed and none recovered.
+	TrendRegressed Trend = "regressed"
+	// TrendMixed means some services recovered and others regressed.
+	TrendMixed Trend = "mixed"
+)
+
+// ServiceChange is a service whose status changed between two reports.
+type ServiceChange struct {
+	Service  string       `json:"service"`
+	Previous HealthStatus `json:"previous"`
+	Current  HealthStatus `json:"current"`
+}
+
+// SummaryComparison describes how health changed between two consecutive
+// reports.
+type SummaryComparison struct {
+	// Regressed lists services that became degraded or unhealthy, or went
+	// from degraded to unhealthy.
+	Regressed []ServiceChange `json:"regressed,omitempty"`
+	// Recovered lists services that became healthy after being degraded or
+	// unhealthy.
+	Recovered []ServiceChange `json:"recovered,omitempty"`
+	// Added and Removed list services present in only one report.
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+
+	PreviousOverall HealthStatus `json:"previousOverall"`
+	CurrentOverall  HealthStatus `json:"currentOverall"`
+	Trend           Trend        `json:"trend"`
+}
+
+// CompareSummaries compares two consecutive reports, matching services by
+// name. Only transitions between healthy, degraded, and unhealthy count as
+// recoveries or regressions: starting, maintenance, and unknown results are
+// neither, so a restart or maintenance window does not look like an
+// incident. Pass a zero HealthReport as prev for the first run.
+//
+// Example:
+//
+//	cmp := healthcheck.CompareSummaries(last, report)
+//	if cmp.Changed() {
+//	    cliout.Info("%s", cmp) // 2 services recovered, 1 regressed since last check
+//	    notify(cmp.Regressed)
+//	}
+func CompareSummaries(prev, curr HealthReport) SummaryComparison {
+	cmp := SummaryComparison{
+		PreviousOverall: prev.Summary.Overall,
+		CurrentOverall:  curr.Summary.Overall,
+	}
+
+	before := make(map[string]HealthStatus, len(prev.Services))
+	for _, r := range prev.Services {
+		before[r.ServiceName] = r.Status
+	}
+	seen := make(map[string]bool, len(curr.Services))
+	for _, r := range curr.Services {
+		seen[r.ServiceName] = true
+		previous, ok := before[r.ServiceName]
+		if !ok {
+			cmp.Added = append(cmp.Added, r.ServiceName)
+			continue
+		}
+		change := ServiceChange{Service: r.ServiceName, Previous: previous, Current: r.Status}
+		from, fromOK := failureSeverity(previous)
+		to, toOK := failureSeverity(r.Status)
+		switch {
+		case !fromOK || !toOK:
+		case to > from:
+			cmp.Regressed = append(cmp.Regressed, change)
+		case to == 0 && from > 0:
+			cmp.Recovered = append(cmp.Recovered, change)
+		}
+	}
+	for _, r := range prev.Services {
+		if !seen[r.ServiceName] {
+			cmp.Removed = append(cmp.Removed, r.ServiceName)
+		}
+	}
+
+	switch {
+	case len(cmp.Regressed) > 0 && len(cmp.Recovered) > 0:
+		cmp.Trend = TrendMixed
+	case len(cmp.Regressed) > 0:
+		cmp.Trend = TrendRegressed
+	case len(cmp.Recovered) > 0:
+		cmp.Trend = TrendImproved
+	default:
+		cmp.Trend = TrendUnchanged
+	}
+	return cmp
+}
+
+// failureSeverity ranks healthy, degraded, and unhealthy; other statuses
+// are not comparable.
+func failureSeverity(status HealthStatus) (int, bool) {
+	switch status {
+	case HealthStatusHealthy:
+		return 0, true
+	case HealthStatusDegraded:
+		return 1, true
+	case HealthStatusUnhealthy:
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
+// Changed reports whether any service recovered or regressed. Use it to
+// notify only on transitions.
+func (c SummaryComparison) Changed() bool {
+	return len(c.Regressed) > 0 || len(c.Recovered) > 0
+}
+
+// String describes the comparison, for example "2 services recovered, 1
+// regressed since last check" or "no changes since last check".
+func (c SummaryComparison) String() string {
+	var parts []string
+	noun := func(n int) string {
+		if n == 1 {
+			return "1 service"
+		}
+		return fmt.Sprintf("%d services", n)
+	}
+	if n := len(c.Recovered); n > 0 {
+		parts = append(parts, noun(n)+" recovered")
+	}
+	if n := len(c.Regressed); n > 0 {
+		if len(parts) > 0 {
+			parts = append(parts, fmt.Sprintf("%d regressed", n))
+		} else {
+			parts = append(parts, noun(n)+" regressed")
+		}
+	}
+	if len(parts) == 0 {
+		return "no changes since last check"
+	}
+	return strings.Join(parts, ", ") + " since last check"
+}
@@ -0,0 +1,141 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func report(statuses ...string) HealthReport {
+	var results []HealthCheckResult
+	for _, s := range statuses {
+		name, status, _ := strings.Cut(s, "=")
+		results = append(results, HealthCheckResult{ServiceName: name, Status: HealthStatus(status)})
+	}
+	return HealthReport{Services: results, Summary: calculateSummary(results)}
+}
+
+func changeNames(changes []ServiceChange) string {
+	names := make([]string, len(changes))
+	for i, c := range changes {
+		names[i] = c.Service
+	}
+	return strings.Join(names, ",")
+}
+
+func TestCompareSummaries(t *testing.T) {
+	tests := []struct {
+		name          string
+		prev, curr    HealthReport
+		wantRegressed string
+		wantRecovered string
+		wantTrend     Trend
+		wantString    string
+	}{
+		{
+			name:       "unchanged",
+			prev:       report("api=healthy", "web=unhealthy"),
+			curr:       report("api=healthy", "web=unhealthy"),
+			wantTrend:  TrendUnchanged,
+			wantString: "no changes since last check",
+		},
+		{
+			name:          "recovered",
+			prev:          report("api=unhealthy", "web=degraded", "db=healthy"),
+			curr:          report("api=healthy", "web=healthy", "db=healthy"),
+			wantRecovered: "api,web",
+			wantTrend:     TrendImproved,
+			wantString:    "2 services recovered since last check",
+		},
+		{
+			name:          "regressed",
+			prev:          report("api=healthy", "web=degraded"),
+			curr:          report("api=degraded", "web=unhealthy"),
+			wantRegressed: "api,web",
+			wantTrend:     TrendRegressed,
+			wantString:    "2 services regressed since last check",
+		},
+		{
+			name:          "mixed",
+			prev:          report("api=unhealthy", "web=healthy", "db=unhealthy"),
+			curr:          report("api=healthy", "web=unhealthy", "db=healthy"),
+			wantRegressed: "web",
+			wantRecovered: "api,db",
+			wantTrend:     TrendMixed,
+			wantString:    "2 services recovered, 1 regressed since last check",
+		},
+		{
+			name:       "partial improvement is not a recovery",
+			prev:       report("api=unhealthy"),
+			curr:       report("api=degraded"),
+			wantTrend:  TrendUnchanged,
+			wantString: "no changes since last check",
+		},
+		{
+			name:       "restarts and maintenance are not transitions",
+			prev:       report("api=healthy", "web=unhealthy", "db=healthy"),
+			curr:       report("api=starting", "web=maintenance", "db=unknown"),
+			wantTrend:  TrendUnchanged,
+			wantString: "no changes since last check",
+		},
+		{
+			name:       "first run",
+			curr:       report("api=unhealthy"),
+			wantTrend:  TrendUnchanged,
+			wantString: "no changes since last check",
+		},
+		{
+			name:          "single",
+			prev:          report("api=healthy"),
+			curr:          report("api=unhealthy"),
+			wantRegressed: "api",
+			wantTrend:     TrendRegressed,
+			wantString:    "1 service regressed since last check",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmp := CompareSummaries(tt.prev, tt.curr)
+			if got := changeNames(cmp.Regressed); got != tt.wantRegressed {
+				t.Errorf("Regressed = %q, want %q", got, tt.wantRegressed)
+			}
+			if got := changeNames(cmp.Recovered); got != tt.wantRecovered {
+				t.Errorf("Recovered = %q, want %q", got, tt.wantRecovered)
+			}
+			if cmp.Trend != tt.wantTrend {
+				t.Errorf("Trend = %s, want %s", cmp.Trend, tt.wantTrend)
+			}
+			if got := cmp.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+			if cmp.Changed() != (tt.wantRegressed != "" || tt.wantRecovered != "") {
+				t.Errorf("Changed() = %v", cmp.Changed())
+			}
+		})
+	}
+}
+
+func TestCompareSummaries_AddedRemovedAndOverall(t *testing.T) {
+	prev := report("api=healthy", "old=unhealthy")
+	curr := report("api=unhealthy", "new=healthy")
+
+	cmp := CompareSummaries(prev, curr)
+	if strings.Join(cmp.Added, ",") != "new" || strings.Join(cmp.Removed, ",") != "old" {
+		t.Errorf("Added = %v, Removed = %v", cmp.Added, cmp.Removed)
+	}
+	if cmp.PreviousOverall != HealthStatusUnhealthy || cmp.CurrentOverall != HealthStatusUnhealthy {
+		t.Errorf("overall = %s -> %s", cmp.PreviousOverall, cmp.CurrentOverall)
+	}
+	change := cmp.Regressed[0]
+	if change.Previous != HealthStatusHealthy || change.Current != HealthStatusUnhealthy {
+		t.Errorf("change = %+v", change)
+	}
+
+	data, err := json.Marshal(cmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"trend":"regressed"`) || !strings.Contains(string(data), `"regressed":[{"service":"api"`) {
+		t.Errorf("JSON = %s", data)
+	}
+}
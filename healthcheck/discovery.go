@@ -0,0 +1,328 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-core/procutil"
+	"github.com/jongio/azd-core/registry"
+	"github.com/jongio/azd-core/security"
+	"gopkg.in/yaml.v3"
+)
+
+// DiscoverOptions selects the sources Discover reads services from. Empty
+// fields are skipped.
+type DiscoverOptions struct {
+	// ComposeFile is the path to a docker compose file.
+	ComposeFile string
+	// RegistryFile is the path to an azd service registry JSON file.
+	RegistryFile string
+	// ParentPID is the process whose descendants are scanned for
+	// listening ports.
+	ParentPID int
+}
+
+// Discover collects services from every source configured in opts, in the
+// order registry file, compose file, process scan. When two sources report
+// a service with the same name, the first one wins.
+//
+// Example:
+//
+//	services, err := healthcheck.Discover(ctx, healthcheck.DiscoverOptions{
+//	    ComposeFile: "docker-compose.yml",
+//	    ParentPID:   os.Getpid(),
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	monitor.Watch(services)
+func Discover(ctx context.Context, opts DiscoverOptions) ([]ServiceInfo, error) {
+	var services []ServiceInfo
+	seen := make(map[string]bool)
+	add := func(found []ServiceInfo) {
+		for _, svc := range found {
+			if !seen[svc.Name] {
+				seen[svc.Name] = true
+				services = append(services, svc)
+			}
+		}
+	}
+
+	if opts.RegistryFile != "" {
+		found, err := DiscoverFromRegistryFile(opts.RegistryFile)
+		if err != nil {
+			return nil, err
+		}
+		add(found)
+	}
+	if opts.ComposeFile != "" {
+		found, err := DiscoverFromCompose(opts.ComposeFile)
+		if err != nil {
+			return nil, err
+		}
+		add(found)
+	}
+	if opts.ParentPID > 0 {
+		found, err := DiscoverFromProcesses(ctx, opts.ParentPID)
+		if err != nil {
+			return nil, err
+		}
+		add(found)
+	}
+	return services, nil
+}
+
+// composeFile is the subset of a docker compose file used for discovery.
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Ports       []any               `yaml:"ports"`
+	HealthCheck *composeHealthCheck `yaml:"healthcheck"`
+}
+
+type composeHealthCheck struct {
+	Disable     bool   `yaml:"disable"`
+	Interval    string `yaml:"interval"`
+	Timeout     string `yaml:"timeout"`
+	Retries     int    `yaml:"retries"`
+	StartPeriod string `yaml:"start_period"`
+}
+
+// DiscoverFromCompose returns a ServiceInfo for each service in the docker
+// compose file at path, sorted by name. Port and Host come from the first
+// published TCP port; services that publish no ports are returned with
+// Port 0. Healthcheck timings (interval, timeout, retries, start_period)
+// are carried over, but the test command is not, since it runs inside the
+// container.
+func DiscoverFromCompose(path string) ([]ServiceInfo, error) {
+	if err := security.ValidatePath(path); err != nil {
+		return nil, fmt.Errorf("invalid compose file path: %w", err)
+	}
+	// #nosec G304 -- path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	var compose composeFile
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, fmt.Errorf("failed to parse compose file: %w", err)
+	}
+
+	services := make([]ServiceInfo, 0, len(compose.Services))
+	for name, svc := range compose.Services {
+		info := ServiceInfo{Name: name, Type: ServiceTypeContainer}
+		for _, p := range svc.Ports {
+			if host, port, ok := parseComposePort(p); ok {
+				info.Host = host
+				info.Port = port
+				break
+			}
+		}
+		hc, err := svc.HealthCheck.config()
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", name, err)
+		}
+		info.HealthCheck = hc
+		services = append(services, info)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+// config converts the compose healthcheck timings. It returns nil when the
+// healthcheck is absent or disabled.
+func (hc *composeHealthCheck) config() (*HealthCheckConfig, error) {
+	if hc == nil || hc.Disable {
+		return nil, nil
+	}
+	cfg := &HealthCheckConfig{Retries: hc.Retries}
+	for _, field := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"interval", hc.Interval, &cfg.Interval},
+		{"timeout", hc.Timeout, &cfg.Timeout},
+		{"start_period", hc.StartPeriod, &cfg.StartPeriod},
+	} {
+		if field.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(field.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid healthcheck %s %q: %w", field.name, field.value, err)
+		}
+		*field.dst = d
+	}
+	return cfg, nil
+}
+
+// parseComposePort extracts the published host address and port from a
+// compose port entry in short ("8080:80", "127.0.0.1:5432:5432",
+// "3000-3005:3000-3005/tcp") or long ({published: 8080, target: 80}) syntax.
+// It reports false for entries that publish no host port or use UDP.
+func parseComposePort(entry any) (string, int, bool) {
+	switch v := entry.(type) {
+	case string:
+		spec, proto, _ := strings.Cut(v, "/")
+		if proto != "" && proto != "tcp" {
+			return "", 0, false
+		}
+		// The container port is always last; an optional host IP (which
+		// may be a bracketed IPv6 address) comes first.
+		i := strings.LastIndex(spec, ":")
+		if i < 0 {
+			return "", 0, false
+		}
+		hostPart := spec[:i]
+		host := ""
+		if j := strings.LastIndex(hostPart, ":"); j >= 0 {
+			host = strings.Trim(hostPart[:j], "[]")
+			hostPart = hostPart[j+1:]
+		}
+		port, ok := firstPort(hostPart)
+		return host, port, ok
+	case map[string]any:
+		if proto, _ := v["protocol"].(string); proto != "" && proto != "tcp" {
+			return "", 0, false
+		}
+		host, _ := v["host_ip"].(string)
+		switch published := v["published"].(type) {
+		case int:
+			return host, published, published > 0
+		case string:
+			port, ok := firstPort(published)
+			return host, port, ok
+		}
+	}
+	return "", 0, false
+}
+
+// firstPort parses a port or the start of a port range such as "3000-3005".
+func firstPort(s string) (int, bool) {
+	start, _, _ := strings.Cut(s, "-")
+	port, err := strconv.Atoi(start)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, false
+	}
+	return port, true
+}
+
+// FromRegistryEntries converts azd service registry entries to ServiceInfo
+// values. Host is taken from the entry URL when present.
+func FromRegistryEntries(entries []*registry.ServiceRegistryEntry) []ServiceInfo {
+	services := make([]ServiceInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		info := ServiceInfo{
+			Name:           entry.Name,
+			Port:           entry.Port,
+			PID:            entry.PID,
+			StartTime:      entry.StartTime,
+			RegistryStatus: entry.Status,
+			Type:           entry.Type,
+			Mode:           entry.Mode,
+			ExitCode:       entry.ExitCode,
+			EndTime:        entry.EndTime,
+		}
+		if u, err := url.Parse(entry.URL); err == nil && u.Host != "" {
+			info.Host = u.Hostname()
+			if info.Port == 0 {
+				if _, port, err := net.SplitHostPort(u.Host); err == nil {
+					info.Port, _ = strconv.Atoi(port)
+				}
+			}
+		}
+		services = append(services, info)
+	}
+	return services
+}
+
+// DiscoverFromRegistryFile reads an azd service registry JSON file and
+// converts its entries with FromRegistryEntries. The file may hold either an
+// array of entries or an object mapping service names to entries; in the
+// latter case entries without a name take the key, and the result is
+// sorted by name.
+func DiscoverFromRegistryFile(path string) ([]ServiceInfo, error) {
+	if err := security.ValidatePath(path); err != nil {
+		return nil, fmt.Errorf("invalid registry file path: %w", err)
+	}
+	// #nosec G304 -- path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file: %w", err)
+	}
+
+	var entries []*registry.ServiceRegistryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var byName map[string]*registry.ServiceRegistryEntry
+		if err := json.Unmarshal(data, &byName); err != nil {
+			return nil, fmt.Errorf("failed to parse registry file: %w", err)
+		}
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			entry := byName[name]
+			if entry == nil {
+				continue
+			}
+			if entry.Name == "" {
+				entry.Name = name
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return FromRegistryEntries(entries), nil
+}
+
+// DiscoverFromProcesses scans the descendants of parentPID for listening TCP
+// ports and returns one ServiceInfo per process and port, named
+// "<process>:<port>". The parent process itself is not scanned. Processes
+// that exit during the scan are skipped.
+func DiscoverFromProcesses(ctx context.Context, parentPID int) ([]ServiceInfo, error) {
+	pids, err := procutil.Descendants(ctx, parentPID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child processes: %w", err)
+	}
+
+	var services []ServiceInfo
+	for _, pid := range pids {
+		ports, err := procutil.ListeningPorts(ctx, pid)
+		if err != nil || len(ports) == 0 {
+			continue
+		}
+		handle, err := procutil.Capture(pid)
+		if err != nil {
+			continue
+		}
+		name, err := procutil.ProcessName(ctx, pid)
+		if err != nil || name == "" {
+			name = strconv.Itoa(pid)
+		}
+		for _, port := range ports {
+			services = append(services, ServiceInfo{
+				Name:      fmt.Sprintf("%s:%d", name, port),
+				Port:      port,
+				PID:       pid,
+				StartTime: handle.StartTime,
+			})
+		}
+	}
+	return services, nil
+}
@@ -0,0 +1,261 @@
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/registry"
+)
+
+// TestDiscoveryHelperProcess is not a real test. DiscoverFromProcesses tests
+// launch the test binary with HEALTHCHECK_DISCOVERY_HELPER=1 to get a child
+// process that listens on a port.
+func TestDiscoveryHelperProcess(t *testing.T) {
+	if os.Getenv("HEALTHCHECK_DISCOVERY_HELPER") != "1" {
+		return
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		os.Exit(1)
+	}
+	fmt.Println(ln.Addr().(*net.TCPAddr).Port)
+	time.Sleep(30 * time.Second)
+	os.Exit(0)
+}
+
+func writeDiscoveryFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiscoverFromCompose(t *testing.T) {
+	path := writeDiscoveryFile(t, "docker-compose.yml", `
+services:
+  web:
+    image: nginx
+    ports:
+      - "8080:80"
+    healthcheck:
+      test: ["CMD", "curl", "-f", "http://localhost"]
+      interval: 10s
+      timeout: 3s
+      retries: 5
+      start_period: 30s
+  db:
+    image: postgres
+    ports:
+      - "127.0.0.1:5432:5432"
+  cache:
+    image: redis
+    ports:
+      - target: 6379
+        published: "6380"
+  worker:
+    image: worker
+`)
+
+	services, err := DiscoverFromCompose(path)
+	if err != nil {
+		t.Fatalf("DiscoverFromCompose: %v", err)
+	}
+	want := []struct {
+		name string
+		host string
+		port int
+	}{
+		{"cache", "", 6380},
+		{"db", "127.0.0.1", 5432},
+		{"web", "", 8080},
+		{"worker", "", 0},
+	}
+	if len(services) != len(want) {
+		t.Fatalf("got %d services, want %d: %+v", len(services), len(want), services)
+	}
+	for i, w := range want {
+		svc := services[i]
+		if svc.Name != w.name || svc.Host != w.host || svc.Port != w.port {
+			t.Errorf("services[%d] = %s %s:%d, want %s %s:%d", i, svc.Name, svc.Host, svc.Port, w.name, w.host, w.port)
+		}
+		if svc.Type != ServiceTypeContainer {
+			t.Errorf("services[%d].Type = %q, want %q", i, svc.Type, ServiceTypeContainer)
+		}
+	}
+
+	hc := services[2].HealthCheck
+	if hc == nil {
+		t.Fatal("expected healthcheck config for web")
+	}
+	if hc.Interval != 10*time.Second || hc.Timeout != 3*time.Second || hc.Retries != 5 || hc.StartPeriod != 30*time.Second {
+		t.Errorf("healthcheck = %+v", hc)
+	}
+	if len(hc.Test) != 0 {
+		t.Errorf("container test command should not be copied, got %v", hc.Test)
+	}
+	if services[1].HealthCheck != nil {
+		t.Errorf("expected no healthcheck for db, got %+v", services[1].HealthCheck)
+	}
+}
+
+func TestDiscoverFromCompose_Errors(t *testing.T) {
+	if _, err := DiscoverFromCompose(filepath.Join(t.TempDir(), "missing.yml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := DiscoverFromCompose(writeDiscoveryFile(t, "bad.yml", "services: [")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+	bad := writeDiscoveryFile(t, "interval.yml", "services:\n  web:\n    healthcheck:\n      interval: soon\n")
+	if _, err := DiscoverFromCompose(bad); err == nil {
+		t.Error("expected error for invalid interval")
+	}
+}
+
+func TestParseComposePort(t *testing.T) {
+	tests := []struct {
+		entry any
+		host  string
+		port  int
+		ok    bool
+	}{
+		{"8080:80", "", 8080, true},
+		{"127.0.0.1:5432:5432", "127.0.0.1", 5432, true},
+		{"[::1]:9000:9000", "::1", 9000, true},
+		{"3000-3005:3000-3005/tcp", "", 3000, true},
+		{"5353:53/udp", "", 0, false},
+		{"80", "", 0, false},
+		{80, "", 0, false},
+		{map[string]any{"target": 80, "published": 8081}, "", 8081, true},
+		{map[string]any{"target": 80, "published": "8082", "host_ip": "0.0.0.0"}, "0.0.0.0", 8082, true},
+		{map[string]any{"target": 53, "published": 53, "protocol": "udp"}, "", 0, false},
+		{map[string]any{"target": 80}, "", 0, false},
+	}
+	for _, tt := range tests {
+		host, port, ok := parseComposePort(tt.entry)
+		if host != tt.host || port != tt.port || ok != tt.ok {
+			t.Errorf("parseComposePort(%v) = %q, %d, %v; want %q, %d, %v", tt.entry, host, port, ok, tt.host, tt.port, tt.ok)
+		}
+	}
+}
+
+func TestFromRegistryEntries(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	services := FromRegistryEntries([]*registry.ServiceRegistryEntry{
+		{Name: "api", PID: 42, Port: 3000, URL: "http://127.0.0.1:3000", Status: "ready", StartTime: start},
+		nil,
+		{Name: "web", URL: "http://localhost:5173", Type: ServiceTypeProcess, Mode: ServiceModeWatch},
+	})
+	if len(services) != 2 {
+		t.Fatalf("got %d services, want 2", len(services))
+	}
+	api := services[0]
+	if api.Name != "api" || api.PID != 42 || api.Port != 3000 || api.Host != "127.0.0.1" || api.RegistryStatus != "ready" || !api.StartTime.Equal(start) {
+		t.Errorf("api = %+v", api)
+	}
+	web := services[1]
+	if web.Port != 5173 || web.Host != "localhost" || web.Type != ServiceTypeProcess || web.Mode != ServiceModeWatch {
+		t.Errorf("web = %+v", web)
+	}
+}
+
+func TestDiscoverFromRegistryFile(t *testing.T) {
+	array := writeDiscoveryFile(t, "array.json", `[{"name": "api", "port": 3000}]`)
+	services, err := DiscoverFromRegistryFile(array)
+	if err != nil {
+		t.Fatalf("array: %v", err)
+	}
+	if len(services) != 1 || services[0].Name != "api" || services[0].Port != 3000 {
+		t.Errorf("array services = %+v", services)
+	}
+
+	byName := writeDiscoveryFile(t, "map.json", `{"web": {"port": 8080}, "api": {"name": "backend", "port": 3000}}`)
+	services, err = DiscoverFromRegistryFile(byName)
+	if err != nil {
+		t.Fatalf("map: %v", err)
+	}
+	if len(services) != 2 || services[0].Name != "backend" || services[1].Name != "web" || services[1].Port != 8080 {
+		t.Errorf("map services = %+v", services)
+	}
+
+	if _, err := DiscoverFromRegistryFile(writeDiscoveryFile(t, "bad.json", `"nope"`)); err == nil {
+		t.Error("expected error for invalid registry file")
+	}
+}
+
+func TestDiscoverFromProcesses(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestDiscoveryHelperProcess$")
+	cmd.Env = append(os.Environ(), "HEALTHCHECK_DISCOVERY_HELPER=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading helper port: %v", err)
+	}
+	port, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		t.Fatalf("helper printed %q: %v", line, err)
+	}
+
+	services, err := DiscoverFromProcesses(context.Background(), os.Getpid())
+	if err != nil {
+		t.Fatalf("DiscoverFromProcesses: %v", err)
+	}
+	var found *ServiceInfo
+	for i := range services {
+		if services[i].PID == cmd.Process.Pid && services[i].Port == port {
+			found = &services[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("helper pid %d port %d not discovered in %+v", cmd.Process.Pid, port, services)
+	}
+	if !strings.HasSuffix(found.Name, ":"+strconv.Itoa(port)) {
+		t.Errorf("Name = %q, want suffix :%d", found.Name, port)
+	}
+	if found.StartTime.IsZero() {
+		t.Error("expected StartTime to be set")
+	}
+}
+
+func TestDiscover_FirstSourceWins(t *testing.T) {
+	registryFile := writeDiscoveryFile(t, "registry.json", `[{"name": "web", "port": 3000}]`)
+	composeFile := writeDiscoveryFile(t, "compose.yml", "services:\n  web:\n    ports: [\"8080:80\"]\n  db:\n    ports: [\"5432:5432\"]\n")
+
+	services, err := Discover(context.Background(), DiscoverOptions{
+		RegistryFile: registryFile,
+		ComposeFile:  composeFile,
+	})
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(services) != 2 {
+		t.Fatalf("got %d services, want 2: %+v", len(services), services)
+	}
+	if services[0].Name != "web" || services[0].Port != 3000 {
+		t.Errorf("registry entry should win, got %+v", services[0])
+	}
+	if services[1].Name != "db" {
+		t.Errorf("services[1] = %+v, want db", services[1])
+	}
+}
@@ -0,0 +1,55 @@
+package healthcheck
+
+import (
+	"github.com/jongio/azd-core/logutil"
+	"github.com/jongio/azd-core/security"
+	"github.com/jongio/azd-core/urlutil"
+)
+
+// logResult records the outcome of one check when MonitorConfig.LogChecks is
+// set: healthy and starting services at debug level, anything else at warn
+// level with the error and suggestion.
+func (c *HealthChecker) logResult(result HealthCheckResult) {
+	if !c.logChecks {
+		return
+	}
+	logger := c.logger
+	if logger == nil {
+		logger = logutil.NewLogger("healthcheck")
+	}
+	logger = logger.WithService(result.ServiceName)
+
+	args := []any{
+		"checkType", string(result.CheckType),
+		"status", string(result.Status),
+		"duration", result.ResponseTime,
+	}
+	if result.Endpoint != "" {
+		args = append(args, "endpoint", urlutil.RedactForLogging(result.Endpoint))
+	}
+	if result.Port > 0 {
+		args = append(args, "port", result.Port)
+	}
+	if result.StatusCode > 0 {
+		args = append(args, "statusCode", result.StatusCode)
+	}
+
+	switch result.Status {
+	case HealthStatusHealthy, HealthStatusStarting:
+		logger.Debug("health check passed", args...)
+	case HealthStatusMaintenance:
+		if result.Error != "" {
+			args = append(args, "error", security.RedactSecrets(result.Error))
+		}
+		args = append(args, "maintenance", result.Details["maintenance"])
+		logger.Info("health check failed during maintenance", args...)
+	default:
+		if result.Error != "" {
+			args = append(args, "error", security.RedactSecrets(result.Error))
+		}
+		if suggestion, ok := result.Details["suggestion"].(string); ok && suggestion != "" {
+			args = append(args, "suggestion", suggestion)
+		}
+		logger.Warn("health check failed", args...)
+	}
+}
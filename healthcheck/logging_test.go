@@ -0,0 +1,158 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/logutil"
+)
+
+// captureLogs routes the global logger to a JSON buffer at debug level.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logutil.SetupLoggerWithWriter(&buf, true, true)
+	t.Cleanup(func() { logutil.SetupLoggerWithWriter(os.Stderr, false, false) })
+	return &buf
+}
+
+func decodeLogs(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestLogResult(t *testing.T) {
+	buf := captureLogs(t)
+	checker := &HealthChecker{logChecks: true}
+
+	checker.logResult(HealthCheckResult{
+		ServiceName:  "web",
+		CheckType:    HealthCheckTypeHTTP,
+		Status:       HealthStatusHealthy,
+		Endpoint:     "http://localhost:3000/health",
+		StatusCode:   200,
+		ResponseTime: 15 * time.Millisecond,
+	})
+	checker.logResult(HealthCheckResult{
+		ServiceName: "api",
+		CheckType:   HealthCheckTypeTCP,
+		Status:      HealthStatusUnhealthy,
+		Port:        8080,
+		Error:       "port 8080 not listening password=hunter2",
+		Details:     map[string]interface{}{"suggestion": "Start the service"},
+	})
+
+	records := decodeLogs(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2:\n%s", len(records), buf.String())
+	}
+
+	ok := records[0]
+	if ok["level"] != "DEBUG" || ok["service"] != "web" || ok["checkType"] != "http" || ok["statusCode"] != float64(200) || ok["component"] != "healthcheck" {
+		t.Errorf("success record = %v", ok)
+	}
+
+	failed := records[1]
+	if failed["level"] != "WARN" || failed["service"] != "api" || failed["port"] != float64(8080) || failed["suggestion"] != "Start the service" {
+		t.Errorf("failure record = %v", failed)
+	}
+	if errMsg, _ := failed["error"].(string); !strings.Contains(errMsg, "not listening") || strings.Contains(errMsg, "hunter2") {
+		t.Errorf("failure error = %q, want redacted message", errMsg)
+	}
+}
+
+func TestLogResult_CustomLoggerAndOptIn(t *testing.T) {
+	buf := captureLogs(t)
+
+	checker := &HealthChecker{logger: logutil.NewLogger("custom"), logChecks: true}
+	checker.logResult(HealthCheckResult{ServiceName: "web", Status: HealthStatusDegraded})
+	records := decodeLogs(t, buf)
+	if len(records) != 1 || records[0]["component"] != "custom" || records[0]["level"] != "WARN" {
+		t.Errorf("records = %v, want one warn from the custom logger", records)
+	}
+
+	buf.Reset()
+	checker = &HealthChecker{}
+	checker.logResult(HealthCheckResult{ServiceName: "web", Status: HealthStatusUnhealthy})
+	if buf.Len() != 0 {
+		t.Errorf("logging disabled but got %q", buf.String())
+	}
+}
+
+func TestCheckServiceLogs(t *testing.T) {
+	buf := captureLogs(t)
+
+	checker := NewHealthChecker(MonitorConfig{Timeout: time.Second, DefaultEndpoint: "/health", LogChecks: true})
+	checker.CheckService(context.Background(), ServiceInfo{Name: "idle"})
+
+	records := decodeLogs(t, buf)
+	if len(records) == 0 {
+		t.Fatal("CheckService wrote no log record")
+	}
+	last := records[len(records)-1]
+	if last["service"] != "idle" || last["msg"] != "health check failed" {
+		t.Errorf("last record = %v", last)
+	}
+}
+
+func TestCheckServiceQuietByDefault(t *testing.T) {
+	buf := captureLogs(t)
+
+	checker := NewHealthChecker(MonitorConfig{Timeout: time.Second, DefaultEndpoint: "/health"})
+	checker.CheckService(context.Background(), ServiceInfo{Name: "idle"})
+
+	for _, rec := range decodeLogs(t, buf) {
+		if rec["msg"] == "health check failed" {
+			t.Errorf("check result logged without LogChecks: %v", rec)
+		}
+	}
+}
+
+func TestLogResult_RedactsEndpoint(t *testing.T) {
+	buf := captureLogs(t)
+	checker := &HealthChecker{logChecks: true}
+
+	checker.logResult(HealthCheckResult{
+		ServiceName: "func",
+		CheckType:   HealthCheckTypeHTTP,
+		Status:      HealthStatusHealthy,
+		Endpoint:    "http://localhost:7071/api/health?code=functionKey123",
+	})
+
+	records := decodeLogs(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	if endpoint, _ := records[0]["endpoint"].(string); strings.Contains(endpoint, "functionKey123") || !strings.HasPrefix(endpoint, "http://localhost:7071/api/health?code=") {
+		t.Errorf("endpoint = %q, want redacted code", endpoint)
+	}
+}
+
+func TestPerformHTTPCheck_RedactsConnectionError(t *testing.T) {
+	checker := &HealthChecker{httpClient: &http.Client{Timeout: time.Second}}
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	result := checker.performHTTPCheck(context.Background(), server.URL+"/health?token=abc123", nil)
+	if result.Status != HealthStatusUnhealthy || strings.Contains(result.Error, "abc123") {
+		t.Errorf("Error = %q, want connection failure without the token", result.Error)
+	}
+}
@@ -0,0 +1,261 @@
+package healthcheck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-core/logutil"
+)
+
+// maxMaintenanceDuration bounds recurring windows; longer ones are better
+// expressed as a Start/End range.
+const maxMaintenanceDuration = 7 * 24 * time.Hour
+
+// MaintenanceWindow is a period during which failing checks are expected,
+// such as a planned restart. Inside a window, unhealthy and degraded
+// results are reported as HealthStatusMaintenance, are logged at info level
+// instead of warn, return nil from HealthCheckResult.Err, and do not count
+// toward the circuit breaker.
+//
+// A window is either a one-off range (Start and End) or recurring (Cron and
+// Duration):
+//
+//	// Weekdays 02:00-02:30 for the api service
+//	{Services: []string{"api"}, Cron: "0 2 * * 1-5", Duration: 30 * time.Minute, Reason: "nightly restart"}
+//	// A single demo slot for every service
+//	{Start: demoStart, End: demoStart.Add(time.Hour), Reason: "demo"}
+type MaintenanceWindow struct {
+	// Services limits the window to these service names. Empty means every
+	// service.
+	Services []string
+	// Start and End bound a one-off window; End is exclusive.
+	Start time.Time
+	End   time.Time
+	// Cron starts a recurring window at each time matching a five-field
+	// cron expression: minute, hour, day of month, month, and day of week
+	// (0 or 7 is Sunday). Fields accept *, numbers, ranges (1-5), lists
+	// (1,15), and steps (*/10). Each occurrence lasts Duration, up to 7 days.
+	Cron     string
+	Duration time.Duration
+	// Location is the time zone for Cron. Defaults to time.Local.
+	Location *time.Location
+	// Reason is recorded in HealthCheckResult.Details["maintenance"].
+	Reason string
+}
+
+// Validate reports whether w describes exactly one valid one-off or
+// recurring window.
+func (w MaintenanceWindow) Validate() error {
+	_, err := compileMaintenanceWindow(w)
+	return err
+}
+
+// maintenanceWindow is a validated MaintenanceWindow.
+type maintenanceWindow struct {
+	MaintenanceWindow
+	schedule *cronSchedule
+	services map[string]bool
+}
+
+func compileMaintenanceWindow(w MaintenanceWindow) (*maintenanceWindow, error) {
+	compiled := &maintenanceWindow{MaintenanceWindow: w}
+	ranged := !w.Start.IsZero() || !w.End.IsZero()
+
+	switch {
+	case ranged && w.Cron != "":
+		return nil, fmt.Errorf("maintenance window cannot set both Start/End and Cron")
+	case ranged:
+		if w.Start.IsZero() || w.End.IsZero() || !w.End.After(w.Start) {
+			return nil, fmt.Errorf("maintenance window End must be after Start")
+		}
+	case w.Cron != "":
+		if w.Duration <= 0 || w.Duration > maxMaintenanceDuration {
+			return nil, fmt.Errorf("maintenance window Duration must be positive and at most %s", maxMaintenanceDuration)
+		}
+		schedule, err := parseCron(w.Cron)
+		if err != nil {
+			return nil, err
+		}
+		compiled.schedule = schedule
+	default:
+		return nil, fmt.Errorf("maintenance window needs Start/End or Cron")
+	}
+
+	if len(w.Services) > 0 {
+		compiled.services = make(map[string]bool, len(w.Services))
+		for _, name := range w.Services {
+			compiled.services[name] = true
+		}
+	}
+	return compiled, nil
+}
+
+// activeFor reports whether the window covers service at t.
+func (w *maintenanceWindow) activeFor(service string, t time.Time) bool {
+	if w.services != nil && !w.services[service] {
+		return false
+	}
+	if w.schedule == nil {
+		return !t.Before(w.Start) && t.Before(w.End)
+	}
+
+	loc := w.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	// Look back over the window's duration for a matching start minute.
+	start := t.In(loc).Truncate(time.Minute)
+	for offset := time.Duration(0); offset < w.Duration; offset += time.Minute {
+		if w.schedule.matches(start.Add(-offset)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileMaintenanceWindows validates windows, logging and skipping invalid ones.
+func compileMaintenanceWindows(windows []MaintenanceWindow, logger *logutil.ComponentLogger) []*maintenanceWindow {
+	var compiled []*maintenanceWindow
+	for i, w := range windows {
+		mw, err := compileMaintenanceWindow(w)
+		if err != nil {
+			if logger == nil {
+				logger = logutil.NewLogger("healthcheck")
+			}
+			logger.Warn("ignoring invalid maintenance window", "index", i, "error", err.Error())
+			continue
+		}
+		compiled = append(compiled, mw)
+	}
+	return compiled
+}
+
+// InMaintenance returns the first of MonitorConfig.MaintenanceWindows that
+// covers service at t. Callers that send their own notifications or
+// webhooks can use it to suppress them.
+func (c *HealthChecker) InMaintenance(service string, t time.Time) (MaintenanceWindow, bool) {
+	for _, w := range c.maintenance {
+		if w.activeFor(service, t) {
+			return w.MaintenanceWindow, true
+		}
+	}
+	return MaintenanceWindow{}, false
+}
+
+// applyMaintenance reports a failing result as HealthStatusMaintenance.
+func applyMaintenance(result *HealthCheckResult, w MaintenanceWindow) {
+	if result.Status != HealthStatusUnhealthy && result.Status != HealthStatusDegraded {
+		return
+	}
+	result.Status = HealthStatusMaintenance
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	reason := w.Reason
+	if reason == "" {
+		reason = "scheduled maintenance"
+	}
+	result.Details["maintenance"] = reason
+}
+
+// cronSchedule is a parsed five-field cron expression. Each field is a
+// bitset of the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record a "*" day field; as in cron, when both day
+	// fields are restricted a time matches if either does.
+	domAny, dowAny bool
+}
+
+// parseCron parses a five-field cron expression.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: want 5 fields, got %d", expr, len(fields))
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	// Sunday may be written as 7.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &cronSchedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses one comma-separated cron field into a bitset.
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+
+		start, end := lo, hi
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var errA, errB error
+			start, errA = strconv.Atoi(a)
+			end, errB = strconv.Atoi(b)
+			if errA != nil || errB != nil || start > end {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			start = n
+			if !hasStep {
+				end = n
+			}
+		}
+		if start < lo || end > hi {
+			return 0, fmt.Errorf("value out of range %d-%d in %q", lo, hi, part)
+		}
+		for v := start; v <= end; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// matches reports whether t (already in the schedule's location) is a
+// start minute of the schedule.
+func (s *cronSchedule) matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 || s.hour&(1<<uint(t.Hour())) == 0 || s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
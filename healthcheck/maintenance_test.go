@@ -0,0 +1,166 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+func TestMaintenanceWindow_Validate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		window  MaintenanceWindow
+		wantErr bool
+	}{
+		{"range", MaintenanceWindow{Start: now, End: now.Add(time.Hour)}, false},
+		{"cron", MaintenanceWindow{Cron: "0 2 * * 1-5", Duration: 30 * time.Minute}, false},
+		{"cron list and step", MaintenanceWindow{Cron: "*/15 0,12 1-7 * 7", Duration: time.Minute}, false},
+		{"empty", MaintenanceWindow{}, true},
+		{"end before start", MaintenanceWindow{Start: now, End: now.Add(-time.Hour)}, true},
+		{"start only", MaintenanceWindow{Start: now}, true},
+		{"range and cron", MaintenanceWindow{Start: now, End: now.Add(time.Hour), Cron: "* * * * *", Duration: time.Minute}, true},
+		{"cron without duration", MaintenanceWindow{Cron: "0 2 * * *"}, true},
+		{"duration too long", MaintenanceWindow{Cron: "0 2 * * *", Duration: 8 * 24 * time.Hour}, true},
+		{"too few fields", MaintenanceWindow{Cron: "0 2 * *", Duration: time.Minute}, true},
+		{"minute out of range", MaintenanceWindow{Cron: "60 2 * * *", Duration: time.Minute}, true},
+		{"bad range", MaintenanceWindow{Cron: "0 5-2 * * *", Duration: time.Minute}, true},
+		{"bad step", MaintenanceWindow{Cron: "*/0 * * * *", Duration: time.Minute}, true},
+		{"not a number", MaintenanceWindow{Cron: "0 two * * *", Duration: time.Minute}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow_ActiveFor(t *testing.T) {
+	// 2026-03-02 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 30, 0, time.UTC)
+	}
+	tests := []struct {
+		name    string
+		window  MaintenanceWindow
+		service string
+		t       time.Time
+		want    bool
+	}{
+		{"range inside", MaintenanceWindow{Start: at(2, 10, 0), End: at(2, 11, 0)}, "api", at(2, 10, 30), true},
+		{"range end exclusive", MaintenanceWindow{Start: at(2, 10, 0), End: at(2, 11, 0)}, "api", at(2, 11, 0), false},
+		{"other service", MaintenanceWindow{Services: []string{"web"}, Start: at(2, 10, 0), End: at(2, 11, 0)}, "api", at(2, 10, 30), false},
+		{"listed service", MaintenanceWindow{Services: []string{"web", "api"}, Start: at(2, 10, 0), End: at(2, 11, 0)}, "api", at(2, 10, 30), true},
+		{"cron start minute", MaintenanceWindow{Cron: "0 2 * * 1-5", Duration: 30 * time.Minute}, "api", at(2, 2, 0), true},
+		{"cron inside duration", MaintenanceWindow{Cron: "0 2 * * 1-5", Duration: 30 * time.Minute}, "api", at(2, 2, 29), true},
+		{"cron after duration", MaintenanceWindow{Cron: "0 2 * * 1-5", Duration: 30 * time.Minute}, "api", at(2, 2, 30), false},
+		{"cron weekend", MaintenanceWindow{Cron: "0 2 * * 1-5", Duration: 30 * time.Minute}, "api", at(1, 2, 10), false},
+		{"cron sunday as 7", MaintenanceWindow{Cron: "0 2 * * 7", Duration: time.Hour}, "api", at(1, 2, 10), true},
+		{"cron across midnight", MaintenanceWindow{Cron: "30 23 * * *", Duration: time.Hour}, "api", at(3, 0, 15), true},
+		{"cron step", MaintenanceWindow{Cron: "*/20 * * * *", Duration: 5 * time.Minute}, "api", at(2, 9, 42), true},
+		{"cron step gap", MaintenanceWindow{Cron: "*/20 * * * *", Duration: 5 * time.Minute}, "api", at(2, 9, 47), false},
+		// With both day fields restricted, either may match.
+		{"cron dom or dow", MaintenanceWindow{Cron: "0 2 15 * 1", Duration: time.Hour}, "api", at(2, 2, 10), true},
+		{"cron location", MaintenanceWindow{Cron: "0 2 * * *", Duration: time.Hour, Location: time.FixedZone("UTC+2", 2*60*60)}, "api", at(2, 0, 10), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := compileMaintenanceWindow(tt.window)
+			if err != nil {
+				t.Fatalf("compileMaintenanceWindow() error = %v", err)
+			}
+			if got := w.activeFor(tt.service, tt.t); got != tt.want {
+				t.Errorf("activeFor(%q, %s) = %v, want %v", tt.service, tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckService_Maintenance(t *testing.T) {
+	now := time.Now()
+	checker := NewHealthChecker(MonitorConfig{
+		Timeout:                time.Second,
+		EnableCircuitBreaker:   true,
+		CircuitBreakerFailures: 1,
+		CircuitBreakerTimeout:  time.Minute,
+		StartupGracePeriod:     time.Nanosecond,
+		MaintenanceWindows: []MaintenanceWindow{
+			{Services: []string{"api"}, Start: now.Add(-time.Minute), End: now.Add(time.Hour), Reason: "demo restart"},
+			{Cron: "not a schedule"}, // ignored
+		},
+	})
+	if len(checker.maintenance) != 1 {
+		t.Fatalf("compiled %d windows, want 1", len(checker.maintenance))
+	}
+
+	port := testutil.FreePort(t)
+	for i := 0; i < 3; i++ {
+		result := checker.CheckService(context.Background(), ServiceInfo{Name: "api", Port: port})
+		if result.Status != HealthStatusMaintenance {
+			t.Fatalf("check %d: status = %s, want %s", i, result.Status, HealthStatusMaintenance)
+		}
+		if result.Details["maintenance"] != "demo restart" {
+			t.Errorf("Details[maintenance] = %v, want reason", result.Details["maintenance"])
+		}
+		if err := result.Err(); err != nil {
+			t.Errorf("Err() = %v, want nil during maintenance", err)
+		}
+	}
+	if got := checker.BreakerState("api"); got != BreakerClosed {
+		t.Errorf("breaker after maintenance failures = %s, want %s", got, BreakerClosed)
+	}
+
+	other := checker.CheckService(context.Background(), ServiceInfo{Name: "web", Port: port})
+	if other.Status != HealthStatusUnhealthy {
+		t.Errorf("service outside window: status = %s, want %s", other.Status, HealthStatusUnhealthy)
+	}
+
+	if _, ok := checker.InMaintenance("api", now); !ok {
+		t.Error("InMaintenance(api) = false, want true")
+	}
+	if _, ok := checker.InMaintenance("api", now.Add(2*time.Hour)); ok {
+		t.Error("InMaintenance(api) after window = true, want false")
+	}
+}
+
+func TestCalculateSummary_Maintenance(t *testing.T) {
+	results := []HealthCheckResult{
+		{Status: HealthStatusHealthy},
+		{Status: HealthStatusMaintenance},
+	}
+	summary := calculateSummary(results)
+	if summary.Maintenance != 1 || summary.Overall != HealthStatusMaintenance {
+		t.Errorf("summary = %+v, want 1 maintenance and overall maintenance", summary)
+	}
+
+	results = append(results, HealthCheckResult{Status: HealthStatusDegraded})
+	if got := calculateSummary(results).Overall; got != HealthStatusDegraded {
+		t.Errorf("Overall = %s, want %s", got, HealthStatusDegraded)
+	}
+}
+
+func TestLogResult_Maintenance(t *testing.T) {
+	buf := captureLogs(t)
+	checker := &HealthChecker{logChecks: true}
+
+	checker.logResult(HealthCheckResult{
+		ServiceName: "api",
+		CheckType:   HealthCheckTypeTCP,
+		Status:      HealthStatusMaintenance,
+		Error:       "port 8080 not listening",
+		Details:     map[string]interface{}{"maintenance": "demo restart"},
+	})
+
+	records := decodeLogs(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1:\n%s", len(records), buf.String())
+	}
+	if rec := records[0]; rec["level"] != "INFO" || rec["maintenance"] != "demo restart" || rec["error"] != "port 8080 not listening" {
+		t.Errorf("maintenance record = %v", rec)
+	}
+}
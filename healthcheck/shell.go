@@ -0,0 +1,133 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jongio/azd-core/logutil"
+	"github.com/jongio/azd-core/security"
+	"github.com/jongio/azd-core/shellutil"
+)
+
+// ErrShellChecksDisabled is reported for CMD-SHELL checks when
+// MonitorConfig.DisableShellChecks is set.
+var ErrShellChecksDisabled = errors.New("shell health checks are disabled")
+
+// shellScriptExts are the extensions that make a single-word CMD-SHELL test
+// run as a script file with the interpreter shellutil.DetectShell picks.
+var shellScriptExts = map[string]bool{
+	".ps1": true,
+	".cmd": true,
+	".bat": true,
+	".sh":  true,
+	".zsh": true,
+}
+
+// performShellCheck executes a shell command for health check (CMD-SHELL format).
+func (c *HealthChecker) performShellCheck(ctx context.Context, command string, svc ServiceInfo) *httpHealthCheckResult {
+	if c.disableShellChecks {
+		return &httpHealthCheckResult{
+			Endpoint: command,
+			Status:   HealthStatusUnhealthy,
+			Error:    fmt.Sprintf("%v; use a CMD check instead", ErrShellChecksDisabled),
+		}
+	}
+
+	var shell string
+	if svc.HealthCheck != nil {
+		shell = svc.HealthCheck.Shell
+	}
+	args, warnings, err := shellCommandArgs(shell, command)
+	if err != nil {
+		return &httpHealthCheckResult{
+			Endpoint: command,
+			Status:   HealthStatusUnhealthy,
+			Error:    err.Error(),
+		}
+	}
+	c.warnShellCommand(svc.Name, command, warnings)
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	return c.runExecCheck(cmd, command, svc.HealthCheck)
+}
+
+// shellCommandArgs returns the interpreter and arguments that run command
+// with shell, along with warnings about risky constructs in it.
+//
+// An empty shell picks an interpreter from the extension of a single-word
+// script path (for example pwsh or powershell for .ps1), and otherwise uses
+// cmd on Windows and sh elsewhere. Script files are passed to the
+// interpreter as a file rather than through -c, so their path is never
+// parsed as shell code.
+func shellCommandArgs(shell, command string) ([]string, []string, error) {
+	script := ""
+	if !strings.ContainsAny(command, " \t\r\n") && shellScriptExts[strings.ToLower(filepath.Ext(command))] {
+		script = command
+	}
+
+	if shell == "" {
+		switch {
+		case script != "":
+			shell = shellutil.DetectShell(script)
+		case runtime.GOOS == "windows":
+			shell = shellutil.ShellCmd
+		default:
+			shell = shellutil.ShellSh
+		}
+	}
+
+	var warnings []string
+	if script != "" {
+		if err := security.SanitizeScriptName(filepath.Base(script)); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	switch strings.ToLower(shell) {
+	case shellutil.ShellSh, shellutil.ShellBash, shellutil.ShellZsh:
+		shell = strings.ToLower(shell)
+		if script != "" {
+			return []string{shell, script}, warnings, nil
+		}
+		// AnalyzeShellCommand understands POSIX syntax only.
+		for _, f := range security.AnalyzeShellCommand(command) {
+			warnings = append(warnings, fmt.Sprintf("%s %s at offset %d: %s", f.Severity, f.Kind, f.Offset, f.Message))
+		}
+		return []string{shell, "-c", command}, warnings, nil
+	case shellutil.ShellPwsh, shellutil.ShellPowerShell:
+		shell = strings.ToLower(shell)
+		if script != "" {
+			return []string{shell, "-NoProfile", "-NonInteractive", "-File", script}, warnings, nil
+		}
+		return []string{shell, "-NoProfile", "-NonInteractive", "-Command", command}, warnings, nil
+	case shellutil.ShellCmd:
+		return []string{shellutil.ShellCmd, "/C", command}, warnings, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported health check shell %q (use sh, bash, zsh, pwsh, powershell, or cmd)", shell)
+	}
+}
+
+// warnShellCommand logs warnings for a service's shell command the first
+// time the command is seen, so recurring checks do not repeat them.
+func (c *HealthChecker) warnShellCommand(serviceName, command string, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	if _, seen := c.shellWarned.LoadOrStore(serviceName+"\x00"+command, struct{}{}); seen {
+		return
+	}
+
+	logger := c.logger
+	if logger == nil {
+		logger = logutil.NewLogger("healthcheck")
+	}
+	logger = logger.WithService(serviceName)
+	for _, w := range warnings {
+		logger.Warn("risky shell health check", "command", security.RedactSecrets(command), "warning", w)
+	}
+}
@@ -0,0 +1,145 @@
+package healthcheck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestShellCommandArgs(t *testing.T) {
+	ps := "pwsh"
+	defaultShell := []string{"sh", "-c"}
+	if runtime.GOOS == "windows" {
+		ps = "powershell"
+		defaultShell = []string{"cmd", "/C"}
+	}
+
+	tests := []struct {
+		name    string
+		shell   string
+		command string
+		want    []string
+	}{
+		{"default", "", "curl -f localhost", append(defaultShell, "curl -f localhost")},
+		{"bash", "bash", "echo ok", []string{"bash", "-c", "echo ok"}},
+		{"case insensitive", "ZSH", "echo ok", []string{"zsh", "-c", "echo ok"}},
+		{"pwsh command", "pwsh", "Get-Date", []string{"pwsh", "-NoProfile", "-NonInteractive", "-Command", "Get-Date"}},
+		{"cmd", "cmd", "dir", []string{"cmd", "/C", "dir"}},
+		{"ps1 script detected", "", "./check.ps1", []string{ps, "-NoProfile", "-NonInteractive", "-File", "./check.ps1"}},
+		{"sh script detected", "", "scripts/check.sh", []string{"bash", "scripts/check.sh"}},
+		{"bat script detected", "", "check.bat", []string{"cmd", "/C", "check.bat"}},
+		{"script with explicit shell", "sh", "check.sh", []string{"sh", "check.sh"}},
+		{"script with arguments is a command", "bash", "check.sh --quick", []string{"bash", "-c", "check.sh --quick"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := shellCommandArgs(tt.shell, tt.command)
+			if err != nil {
+				t.Fatalf("shellCommandArgs(%q, %q) error = %v", tt.shell, tt.command, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shellCommandArgs(%q, %q) = %q, want %q", tt.shell, tt.command, got, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := shellCommandArgs("python", "print(1)"); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("shellCommandArgs() with unknown shell error = %v, want unsupported", err)
+	}
+}
+
+func TestShellCommandArgs_Warnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		shell   string
+		command string
+		want    string
+	}{
+		{"command substitution", "sh", "curl $(cat url)", "command-substitution"},
+		{"unquoted variable", "bash", "curl $HEALTH_URL", "unquoted-variable"},
+		{"eval", "sh", "eval $CHECK", "eval"},
+		{"script name", "", "check$x.sh", "dangerous character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, warnings, err := shellCommandArgs(tt.shell, tt.command)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(strings.Join(warnings, "\n"), tt.want) {
+				t.Errorf("warnings = %q, want one mentioning %q", warnings, tt.want)
+			}
+		})
+	}
+
+	if _, warnings, _ := shellCommandArgs("sh", `curl -f "$HEALTH_URL"`); len(warnings) != 0 {
+		t.Errorf("quoted variable warnings = %q, want none", warnings)
+	}
+}
+
+func TestPerformShellCheck_Disabled(t *testing.T) {
+	checker := NewHealthChecker(MonitorConfig{DisableShellChecks: true})
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+
+	svc := ServiceInfo{Name: "api", HealthCheck: &HealthCheckConfig{Test: []string{"CMD-SHELL", "touch " + marker}}}
+	result := checker.tryCustomHealthCheck(context.Background(), svc.HealthCheck, svc)
+	if result.Status != HealthStatusUnhealthy || !strings.Contains(result.Error, ErrShellChecksDisabled.Error()) {
+		t.Errorf("result = %+v, want unhealthy with disabled error", result)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("disabled shell check still ran the command")
+	}
+
+	svc.HealthCheck.Test = []string{"touch " + marker}
+	if result := checker.tryCustomHealthCheck(context.Background(), svc.HealthCheck, svc); result.Status != HealthStatusUnhealthy {
+		t.Errorf("bare command status = %s, want unhealthy", result.Status)
+	}
+}
+
+func TestPerformShellCheck_UnsupportedShell(t *testing.T) {
+	checker := &HealthChecker{}
+	svc := ServiceInfo{Name: "api", HealthCheck: &HealthCheckConfig{Shell: "fish"}}
+	result := checker.performShellCheck(context.Background(), "true", svc)
+	if result.Status != HealthStatusUnhealthy || !strings.Contains(result.Error, "fish") {
+		t.Errorf("result = %+v, want unhealthy naming the shell", result)
+	}
+}
+
+func TestPerformShellCheck_ScriptFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell script")
+	}
+	dir := t.TempDir()
+	// No execute bit: the script is passed to the interpreter as a file.
+	if err := os.WriteFile(filepath.Join(dir, "check.sh"), []byte("exit 0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	checker := &HealthChecker{}
+	svc := ServiceInfo{Name: "api", HealthCheck: &HealthCheckConfig{WorkDir: dir, Shell: "sh"}}
+	if result := checker.performShellCheck(context.Background(), "check.sh", svc); result.Status != HealthStatusHealthy {
+		t.Errorf("script check = %s (%s), want healthy", result.Status, result.Error)
+	}
+}
+
+func TestWarnShellCommand_LogsOnce(t *testing.T) {
+	buf := captureLogs(t)
+	checker := &HealthChecker{}
+
+	checker.warnShellCommand("api", "curl $URL", []string{"medium unquoted-variable"})
+	checker.warnShellCommand("api", "curl $URL", []string{"medium unquoted-variable"})
+	checker.warnShellCommand("web", "curl $URL", []string{"medium unquoted-variable"})
+	checker.warnShellCommand("web", "true", nil)
+
+	records := decodeLogs(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2:\n%s", len(records), buf.String())
+	}
+	if records[0]["level"] != "WARN" || records[0]["service"] != "api" || records[0]["warning"] != "medium unquoted-variable" {
+		t.Errorf("record = %v", records[0])
+	}
+}
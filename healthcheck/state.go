@@ -0,0 +1,169 @@
+package healthcheck
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/sony/gobreaker"
+)
+
+// DefaultStateTTL is how long persisted endpoints and breaker trips remain
+// valid when MonitorConfig.StateTTL is zero.
+const DefaultStateTTL = time.Hour
+
+// stateFileVersion is the schema version of the persisted checker state.
+const stateFileVersion = 1
+
+// checkerState is the on-disk form of a HealthChecker's learned state.
+type checkerState struct {
+	Version   int                      `json:"version"`
+	Endpoints map[string]endpointState `json:"endpoints,omitempty"`
+	Breakers  map[string]breakerState  `json:"breakers,omitempty"`
+}
+
+// endpointState records a discovered health endpoint for a service:port key.
+type endpointState struct {
+	Endpoint  string    `json:"endpoint"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// breakerState records a tripped circuit breaker.
+type breakerState struct {
+	OpenUntil time.Time `json:"openUntil"`
+}
+
+// endpointCacheKey builds the endpoint cache key for a service and port.
+func endpointCacheKey(serviceName string, port int) string {
+	if serviceName == "" {
+		return fmt.Sprintf("port:%d", port)
+	}
+	return fmt.Sprintf("%s:%d", serviceName, port)
+}
+
+// setCachedEndpoint stores a discovered endpoint and marks state for saving.
+func (c *HealthChecker) setCachedEndpoint(key, endpoint string) {
+	c.mu.Lock()
+	c.endpointCache[key] = endpoint
+	c.endpointUpdated[key] = time.Now()
+	c.mu.Unlock()
+	c.markStateDirty()
+}
+
+// recordBreakerState tracks breaker trips so they survive restarts.
+func (c *HealthChecker) recordBreakerState(serviceName string, to gobreaker.State) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	if c.trippedUntil == nil {
+		c.trippedUntil = make(map[string]time.Time)
+	}
+	if to == gobreaker.StateOpen {
+		c.trippedUntil[serviceName] = time.Now().Add(c.settingsFor(serviceName).breakerTimeout)
+	} else {
+		delete(c.trippedUntil, serviceName)
+	}
+	c.stateDirty = true
+}
+
+// isTripped reports whether a breaker trip loaded from disk is still in effect.
+func (c *HealthChecker) isTripped(serviceName string) bool {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	until, ok := c.trippedUntil[serviceName]
+	if !ok {
+		return false
+	}
+	if time.Now().Before(until) {
+		return true
+	}
+	delete(c.trippedUntil, serviceName)
+	c.stateDirty = true
+	return false
+}
+
+// markStateDirty flags the state for the next save.
+func (c *HealthChecker) markStateDirty() {
+	c.stateMu.Lock()
+	c.stateDirty = true
+	c.stateMu.Unlock()
+}
+
+// loadState restores endpoints and breaker trips from the state file,
+// discarding entries older than the state TTL. A missing or unreadable file
+// leaves the checker empty.
+func (c *HealthChecker) loadState() {
+	var state checkerState
+	if err := fileutil.ReadJSON(c.stateFile, &state); err != nil || state.Version != stateFileVersion {
+		return
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	for key, e := range state.Endpoints {
+		if now.Sub(e.UpdatedAt) > c.stateTTL {
+			continue
+		}
+		c.endpointCache[key] = e.Endpoint
+		c.endpointUpdated[key] = e.UpdatedAt
+	}
+	c.mu.Unlock()
+
+	c.stateMu.Lock()
+	for name, b := range state.Breakers {
+		if c.settingsFor(name).enableBreaker && now.Before(b.OpenUntil) {
+			c.trippedUntil[name] = b.OpenUntil
+		}
+	}
+	c.stateMu.Unlock()
+}
+
+// SaveState writes discovered endpoints and breaker trips to
+// MonitorConfig.StateFile. It is a no-op when no state file is configured.
+// CheckService saves automatically whenever the state changes.
+func (c *HealthChecker) SaveState() error {
+	if c.stateFile == "" {
+		return nil
+	}
+
+	state := checkerState{
+		Version:   stateFileVersion,
+		Endpoints: make(map[string]endpointState),
+		Breakers:  make(map[string]breakerState),
+	}
+
+	c.mu.RLock()
+	for key, endpoint := range c.endpointCache {
+		state.Endpoints[key] = endpointState{Endpoint: endpoint, UpdatedAt: c.endpointUpdated[key]}
+	}
+	c.mu.RUnlock()
+
+	c.stateMu.Lock()
+	for name, until := range c.trippedUntil {
+		state.Breakers[name] = breakerState{OpenUntil: until}
+	}
+	c.stateDirty = false
+	c.stateMu.Unlock()
+
+	if err := fileutil.EnsureDir(filepath.Dir(c.stateFile)); err != nil {
+		return err
+	}
+	if err := fileutil.AtomicWriteJSON(c.stateFile, state); err != nil {
+		return fmt.Errorf("failed to save health check state: %w", err)
+	}
+	return nil
+}
+
+// saveStateIfDirty persists state after a change. Errors are ignored because
+// persistence is an optimization; the next run simply rediscovers endpoints.
+func (c *HealthChecker) saveStateIfDirty() {
+	if c.stateFile == "" {
+		return
+	}
+	c.stateMu.Lock()
+	dirty := c.stateDirty
+	c.stateMu.Unlock()
+	if dirty {
+		_ = c.SaveState()
+	}
+}
@@ -0,0 +1,110 @@
+package healthcheck
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/jongio/azd-core/testutil"
+)
+
+func TestHealthCheckerState_PersistsEndpoints(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "health", "state.json")
+	server := testutil.NewHealthServer(t, testutil.HealthScript{Path: "/ready"})
+	svc := ServiceInfo{Name: "api", Port: server.Port()}
+
+	first := NewHealthChecker(MonitorConfig{Timeout: 2 * time.Second, StateFile: stateFile})
+	if r := first.CheckService(context.Background(), svc); r.Status != HealthStatusHealthy {
+		t.Fatalf("expected healthy, got %s (%s)", r.Status, r.Error)
+	}
+
+	var state checkerState
+	if err := fileutil.ReadJSON(stateFile, &state); err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	key := endpointCacheKey("api", server.Port())
+	if state.Endpoints[key].Endpoint != "/ready" {
+		t.Fatalf("expected persisted /ready endpoint, got %+v", state.Endpoints)
+	}
+
+	second := NewHealthChecker(MonitorConfig{Timeout: 2 * time.Second, StateFile: stateFile})
+	if got := second.endpointCache[key]; got != "/ready" {
+		t.Fatalf("expected endpoint loaded from state, got %q", got)
+	}
+
+	before := server.Requests()
+	if r := second.CheckService(context.Background(), svc); r.Status != HealthStatusHealthy {
+		t.Fatalf("expected healthy, got %s", r.Status)
+	}
+	if probes := server.Requests() - before; probes != 1 {
+		t.Errorf("expected a single request using the cached endpoint, got %d", probes)
+	}
+}
+
+func TestHealthCheckerState_TTL(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	stale := checkerState{
+		Version: stateFileVersion,
+		Endpoints: map[string]endpointState{
+			"old:8080":   {Endpoint: "/health", UpdatedAt: time.Now().Add(-2 * time.Hour)},
+			"fresh:8081": {Endpoint: "/healthz", UpdatedAt: time.Now()},
+		},
+	}
+	if err := fileutil.AtomicWriteJSON(stateFile, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	checker := NewHealthChecker(MonitorConfig{StateFile: stateFile, StateTTL: time.Hour})
+	if _, ok := checker.endpointCache["old:8080"]; ok {
+		t.Error("stale endpoint should be discarded")
+	}
+	if checker.endpointCache["fresh:8081"] != "/healthz" {
+		t.Error("fresh endpoint should be loaded")
+	}
+}
+
+func TestHealthCheckerState_BreakerTrip(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	state := checkerState{
+		Version: stateFileVersion,
+		Breakers: map[string]breakerState{
+			"api":     {OpenUntil: time.Now().Add(time.Minute)},
+			"expired": {OpenUntil: time.Now().Add(-time.Minute)},
+		},
+	}
+	if err := fileutil.AtomicWriteJSON(stateFile, state); err != nil {
+		t.Fatal(err)
+	}
+
+	server := testutil.NewHealthServer(t, testutil.HealthScript{})
+	checker := NewHealthChecker(MonitorConfig{
+		Timeout:                2 * time.Second,
+		EnableCircuitBreaker:   true,
+		CircuitBreakerFailures: 3,
+		CircuitBreakerTimeout:  time.Minute,
+		StateFile:              stateFile,
+	})
+
+	r := checker.CheckService(context.Background(), ServiceInfo{Name: "api", Port: server.Port()})
+	if r.Status != HealthStatusUnhealthy || server.Requests() != 0 {
+		t.Errorf("expected persisted breaker trip to short-circuit, got %s with %d requests", r.Status, server.Requests())
+	}
+	if checker.isTripped("expired") {
+		t.Error("expired breaker trip should not be loaded")
+	}
+
+	// Without the breaker enabled, persisted trips are ignored.
+	plain := NewHealthChecker(MonitorConfig{Timeout: 2 * time.Second, StateFile: stateFile})
+	if r := plain.CheckService(context.Background(), ServiceInfo{Name: "api", Port: server.Port()}); r.Status != HealthStatusHealthy {
+		t.Errorf("expected healthy without breaker, got %s", r.Status)
+	}
+}
+
+func TestHealthCheckerState_Disabled(t *testing.T) {
+	checker := NewHealthChecker(MonitorConfig{})
+	if err := checker.SaveState(); err != nil {
+		t.Errorf("SaveState without a state file should be a no-op: %v", err)
+	}
+}
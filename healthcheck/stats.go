@@ -0,0 +1,227 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+// DefaultHistorySize is the number of results kept per service when
+// MonitorConfig.HistorySize is zero: an hour of checks every five seconds.
+const DefaultHistorySize = 720
+
+// StatsTableHeaders are the column headers of ServiceStats.TableRow, for
+// cliout.Table.
+var StatsTableHeaders = []string{"Service", "Checks", "Uptime", "Mean", "P95", "Incidents", "MTTR", "Streak"}
+
+// ServiceStats summarizes a service's health over a window of results.
+// Durations are serialized as nanoseconds, like HealthCheckResult.
+type ServiceStats struct {
+	Service string `json:"service"`
+	// From and To are the timestamps of the first and last result used.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Checks counts the results used. Results with HealthStatusStarting,
+	// HealthStatusMaintenance, or HealthStatusUnknown are excluded from
+	// every statistic.
+	Checks int `json:"checks"`
+	// Failures counts unhealthy results.
+	Failures int `json:"failures"`
+	// UptimePercent is the share of checks that were healthy or degraded,
+	// from 0 to 100.
+	UptimePercent float64 `json:"uptimePercent"`
+
+	MeanResponseTime time.Duration `json:"meanResponseTime"`
+	P50ResponseTime  time.Duration `json:"p50ResponseTime"`
+	P95ResponseTime  time.Duration `json:"p95ResponseTime"`
+	P99ResponseTime  time.Duration `json:"p99ResponseTime"`
+	MaxResponseTime  time.Duration `json:"maxResponseTime"`
+
+	// CurrentFailureStreak is the number of consecutive failures at the
+	// end of the window; 0 if the last check passed.
+	CurrentFailureStreak int `json:"currentFailureStreak"`
+	// LongestFailureStreak is the longest run of consecutive failures.
+	LongestFailureStreak int `json:"longestFailureStreak"`
+	// Incidents counts runs of consecutive failures.
+	Incidents int `json:"incidents"`
+	// MTTR is the mean time to recovery: the average time from the first
+	// failure of an incident to the next passing check. Incidents still in
+	// progress are not included. It is zero if no incident recovered.
+	MTTR time.Duration `json:"mttr"`
+}
+
+// Stats summarizes the named service's recorded results from the last
+// window; window <= 0 uses the whole history. The history holds the most
+// recent MonitorConfig.HistorySize results of CheckService, CheckAll, and
+// WaitForHealthy.
+//
+// Example:
+//
+//	var rows []cliout.TableRow
+//	for _, name := range []string{"api", "web"} {
+//	    rows = append(rows, checker.Stats(name, time.Hour).TableRow())
+//	}
+//	cliout.Table(healthcheck.StatsTableHeaders, rows)
+func (c *HealthChecker) Stats(serviceName string, window time.Duration) ServiceStats {
+	results := c.History(serviceName)
+	if window > 0 {
+		cutoff := time.Now().Add(-window)
+		i := sort.Search(len(results), func(i int) bool { return !results[i].Timestamp.Before(cutoff) })
+		results = results[i:]
+	}
+	stats := ComputeStats(results)
+	stats.Service = serviceName
+	return stats
+}
+
+// History returns the named service's recorded results, oldest first.
+func (c *HealthChecker) History(serviceName string) []HealthCheckResult {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+	h := c.history[serviceName]
+	if h == nil {
+		return nil
+	}
+	return h.snapshot()
+}
+
+// ComputeStats summarizes results, which must be in chronological order,
+// for callers that keep their own history. Service is taken from the first
+// result.
+func ComputeStats(results []HealthCheckResult) ServiceStats {
+	var stats ServiceStats
+	var times []time.Duration
+	var total time.Duration
+	var up int
+	var streak int
+	var incidentStart time.Time
+	var recoveries int
+	var recoveryTotal time.Duration
+
+	for _, r := range results {
+		if r.Status == HealthStatusStarting || r.Status == HealthStatusMaintenance || r.Status == HealthStatusUnknown {
+			continue
+		}
+		if stats.Checks == 0 {
+			stats.Service = r.ServiceName
+			stats.From = r.Timestamp
+		}
+		stats.To = r.Timestamp
+		stats.Checks++
+		times = append(times, r.ResponseTime)
+		total += r.ResponseTime
+
+		if r.Status == HealthStatusUnhealthy {
+			stats.Failures++
+			if streak == 0 {
+				stats.Incidents++
+				incidentStart = r.Timestamp
+			}
+			streak++
+			stats.LongestFailureStreak = max(stats.LongestFailureStreak, streak)
+			continue
+		}
+		up++
+		if streak > 0 {
+			recoveries++
+			recoveryTotal += r.Timestamp.Sub(incidentStart)
+			streak = 0
+		}
+	}
+	if stats.Checks == 0 {
+		return stats
+	}
+
+	stats.CurrentFailureStreak = streak
+	stats.UptimePercent = float64(up) * 100 / float64(stats.Checks)
+	stats.MeanResponseTime = total / time.Duration(stats.Checks)
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	stats.P50ResponseTime = percentile(times, 50)
+	stats.P95ResponseTime = percentile(times, 95)
+	stats.P99ResponseTime = percentile(times, 99)
+	stats.MaxResponseTime = times[len(times)-1]
+	if recoveries > 0 {
+		stats.MTTR = recoveryTotal / time.Duration(recoveries)
+	}
+	return stats
+}
+
+// TableRow formats the stats as a row for cliout.Table with
+// StatsTableHeaders.
+func (s ServiceStats) TableRow() cliout.TableRow {
+	row := cliout.TableRow{
+		"Service":   s.Service,
+		"Checks":    strconv.Itoa(s.Checks),
+		"Uptime":    "-",
+		"Mean":      "-",
+		"P95":       "-",
+		"Incidents": strconv.Itoa(s.Incidents),
+		"MTTR":      "-",
+		"Streak":    strconv.Itoa(s.CurrentFailureStreak),
+	}
+	if s.Checks > 0 {
+		row["Uptime"] = fmt.Sprintf("%.1f%%", s.UptimePercent)
+		row["Mean"] = s.MeanResponseTime.Round(time.Millisecond).String()
+		row["P95"] = s.P95ResponseTime.Round(time.Millisecond).String()
+	}
+	if s.MTTR > 0 {
+		row["MTTR"] = s.MTTR.Round(time.Second).String()
+	}
+	return row
+}
+
+// percentile returns the nearest-rank percentile p of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// resultHistory is a fixed-size ring of a service's most recent results.
+type resultHistory struct {
+	buf  []HealthCheckResult
+	next int
+	full bool
+}
+
+func newResultHistory(size int) *resultHistory {
+	return &resultHistory{buf: make([]HealthCheckResult, size)}
+}
+
+func (h *resultHistory) add(r HealthCheckResult) {
+	h.buf[h.next] = r
+	h.next = (h.next + 1) % len(h.buf)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// snapshot returns the results oldest first.
+func (h *resultHistory) snapshot() []HealthCheckResult {
+	if !h.full {
+		return append([]HealthCheckResult(nil), h.buf[:h.next]...)
+	}
+	out := make([]HealthCheckResult, 0, len(h.buf))
+	out = append(out, h.buf[h.next:]...)
+	return append(out, h.buf[:h.next]...)
+}
+
+// recordHistory appends result to its service's history.
+func (c *HealthChecker) recordHistory(result HealthCheckResult) {
+	if c.historySize <= 0 || result.ServiceName == "" {
+		return
+	}
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+	h := c.history[result.ServiceName]
+	if h == nil {
+		h = newResultHistory(c.historySize)
+		c.history[result.ServiceName] = h
+	}
+	h.add(result)
+}
@@ -0,0 +1,142 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+// statsResults builds results one second apart from a status string:
+// 'h' healthy, 'd' degraded, 'u' unhealthy, 's' starting. Response times
+// are 10ms, 20ms, 30ms, ...
+func statsResults(start time.Time, statuses string) []HealthCheckResult {
+	codes := map[rune]HealthStatus{
+		'h': HealthStatusHealthy,
+		'd': HealthStatusDegraded,
+		'u': HealthStatusUnhealthy,
+		's': HealthStatusStarting,
+	}
+	results := make([]HealthCheckResult, 0, len(statuses))
+	for i, c := range statuses {
+		results = append(results, HealthCheckResult{
+			ServiceName:  "api",
+			Status:       codes[c],
+			Timestamp:    start.Add(time.Duration(i) * time.Second),
+			ResponseTime: time.Duration(i+1) * 10 * time.Millisecond,
+		})
+	}
+	return results
+}
+
+func TestComputeStats(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	// Checks at seconds 1..9 (the starting result at 0 is ignored):
+	// two incidents, one recovered after 2s, one still failing.
+	stats := ComputeStats(statsResults(start, "shhuuhdhuu"))
+
+	if stats.Service != "api" || stats.Checks != 9 || stats.Failures != 4 {
+		t.Errorf("service/checks/failures = %q/%d/%d", stats.Service, stats.Checks, stats.Failures)
+	}
+	if !stats.From.Equal(start.Add(time.Second)) || !stats.To.Equal(start.Add(9*time.Second)) {
+		t.Errorf("from/to = %v/%v", stats.From, stats.To)
+	}
+	if want := 500.0 / 9; stats.UptimePercent != want {
+		t.Errorf("UptimePercent = %v, want %v", stats.UptimePercent, want)
+	}
+	if stats.Incidents != 2 || stats.LongestFailureStreak != 2 || stats.CurrentFailureStreak != 2 {
+		t.Errorf("incidents/longest/current = %d/%d/%d", stats.Incidents, stats.LongestFailureStreak, stats.CurrentFailureStreak)
+	}
+	if stats.MTTR != 2*time.Second {
+		t.Errorf("MTTR = %v, want 2s", stats.MTTR)
+	}
+	// Response times 20ms..100ms.
+	if stats.MeanResponseTime != 60*time.Millisecond || stats.P50ResponseTime != 60*time.Millisecond ||
+		stats.P95ResponseTime != 100*time.Millisecond || stats.MaxResponseTime != 100*time.Millisecond {
+		t.Errorf("response times = mean %v p50 %v p95 %v max %v", stats.MeanResponseTime, stats.P50ResponseTime, stats.P95ResponseTime, stats.MaxResponseTime)
+	}
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	for _, results := range [][]HealthCheckResult{nil, statsResults(time.Now(), "ss")} {
+		if stats := ComputeStats(results); stats.Checks != 0 || stats.UptimePercent != 0 {
+			t.Errorf("ComputeStats() = %+v, want zero stats", stats)
+		}
+	}
+}
+
+func TestHealthCheckerStats_Window(t *testing.T) {
+	checker := NewHealthChecker(MonitorConfig{HistorySize: 5})
+	now := time.Now()
+	for _, r := range statsResults(now.Add(-90*time.Minute), "uuuhhhh") {
+		checker.recordHistory(r)
+	}
+	// Only the last five results are kept.
+	if history := checker.History("api"); len(history) != 5 || history[0].Status != HealthStatusUnhealthy || history[4].Status != HealthStatusHealthy {
+		t.Fatalf("History() = %+v", history)
+	}
+	recent := statsResults(now.Add(-time.Minute), "u")[0]
+	checker.recordHistory(recent)
+
+	if stats := checker.Stats("api", time.Hour); stats.Checks != 1 || stats.Failures != 1 || stats.Service != "api" {
+		t.Errorf("Stats(1h) = %+v, want only the recent failure", stats)
+	}
+	if stats := checker.Stats("api", 0); stats.Checks != 5 {
+		t.Errorf("Stats(0) checks = %d, want the whole history of 5", stats.Checks)
+	}
+	if stats := checker.Stats("missing", time.Hour); stats.Checks != 0 || stats.Service != "missing" {
+		t.Errorf("Stats(missing) = %+v", stats)
+	}
+}
+
+func TestHealthCheckerStats_RecordsChecks(t *testing.T) {
+	server := testutil.NewHealthServer(t, testutil.HealthScript{})
+	svc := ServiceInfo{Name: "api", Port: server.Port()}
+
+	checker := NewHealthChecker(MonitorConfig{Timeout: 2 * time.Second})
+	for range 3 {
+		checker.CheckService(context.Background(), svc)
+	}
+	stats := checker.Stats("api", time.Hour)
+	if stats.Checks != 3 || stats.UptimePercent != 100 || stats.MaxResponseTime <= 0 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	disabled := NewHealthChecker(MonitorConfig{Timeout: 2 * time.Second, HistorySize: -1})
+	disabled.CheckService(context.Background(), svc)
+	if history := disabled.History("api"); len(history) != 0 {
+		t.Errorf("expected no history with HistorySize < 0, got %d results", len(history))
+	}
+}
+
+func TestServiceStats_TableRowAndJSON(t *testing.T) {
+	stats := ComputeStats(statsResults(time.Now(), "huuh"))
+	row := stats.TableRow()
+	for header, want := range map[string]string{"Service": "api", "Checks": "4", "Uptime": "50.0%", "Incidents": "1", "MTTR": "2s", "Streak": "0"} {
+		if row[header] != want {
+			t.Errorf("row[%q] = %q, want %q", header, row[header], want)
+		}
+	}
+	for _, header := range StatsTableHeaders {
+		if _, ok := row[header]; !ok {
+			t.Errorf("row is missing column %q", header)
+		}
+	}
+	if empty := (ServiceStats{Service: "web"}).TableRow(); empty["Uptime"] != "-" || empty["MTTR"] != "-" {
+		t.Errorf("empty row = %v", empty)
+	}
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["uptimePercent"] != 50.0 || decoded["mttr"] != float64(2*time.Second) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
@@ -2,7 +2,12 @@
 package healthcheck
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/logutil"
+	"github.com/jongio/azd-core/security"
 )
 
 const (
@@ -12,6 +17,13 @@ const (
 	// defaultPortCheckTimeout is the timeout for TCP port checks
 	defaultPortCheckTimeout = 2 * time.Second
 
+	// maxCapturedOutputLines is the number of trailing stderr lines kept
+	// when HealthCheckConfig.CaptureOutput is set
+	maxCapturedOutputLines = 10
+
+	// maxCapturedOutputSize bounds the stderr buffered for exec-based checks
+	maxCapturedOutputSize = 64 * 1024
+
 	// startupGracePeriod is the time during which services are considered "starting"
 	// before being marked as unhealthy if health checks fail
 	startupGracePeriod = 30 * time.Second
@@ -57,6 +69,9 @@ const (
 	HealthStatusUnhealthy HealthStatus = "unhealthy"
 	HealthStatusStarting  HealthStatus = "starting"
 	HealthStatusUnknown   HealthStatus = "unknown"
+	// HealthStatusMaintenance replaces unhealthy or degraded for a service
+	// inside one of MonitorConfig.MaintenanceWindows.
+	HealthStatusMaintenance HealthStatus = "maintenance"
 )
 
 // HealthCheckType indicates the method used for health checking.
@@ -89,6 +104,34 @@ type HealthCheckResult struct {
 	ServiceMode         string                 `json:"serviceMode,omitempty"`
 }
 
+// CodeServiceUnhealthy is the cliout.RichError code returned by
+// HealthCheckResult.Err.
+const CodeServiceUnhealthy = "SERVICE_UNHEALTHY"
+
+// Err returns nil for healthy, starting, or maintenance results and otherwise a
+// *cliout.RichError describing the failure, with the check's suggestion (if
+// any) as a suggested action. Pass it to cliout.PresentError for display.
+func (r HealthCheckResult) Err() error {
+	if r.Status == HealthStatusHealthy || r.Status == HealthStatusStarting || r.Status == HealthStatusMaintenance {
+		return nil
+	}
+
+	rich := &cliout.RichError{
+		Code:    CodeServiceUnhealthy,
+		Summary: fmt.Sprintf("service %s is %s", r.ServiceName, r.Status),
+	}
+	// ErrorDetails holds the untruncated message when Error was shortened.
+	details := r.ErrorDetails
+	if details == "" {
+		details = r.Error
+	}
+	rich.Details = security.RedactSecrets(details)
+	if suggestion, ok := r.Details["suggestion"].(string); ok && suggestion != "" {
+		rich.Suggestions = []string{suggestion}
+	}
+	return rich
+}
+
 // HealthReport contains aggregated health check results.
 type HealthReport struct {
 	Timestamp time.Time           `json:"timestamp"`
@@ -99,13 +142,14 @@ type HealthReport struct {
 
 // HealthSummary provides overall health statistics.
 type HealthSummary struct {
-	Total     int          `json:"total"`
-	Healthy   int          `json:"healthy"`
-	Degraded  int          `json:"degraded"`
-	Unhealthy int          `json:"unhealthy"`
-	Starting  int          `json:"starting"`
-	Unknown   int          `json:"unknown"`
-	Overall   HealthStatus `json:"overall"`
+	Total       int          `json:"total"`
+	Healthy     int          `json:"healthy"`
+	Degraded    int          `json:"degraded"`
+	Unhealthy   int          `json:"unhealthy"`
+	Starting    int          `json:"starting"`
+	Maintenance int          `json:"maintenance"`
+	Unknown     int          `json:"unknown"`
+	Overall     HealthStatus `json:"overall"`
 }
 
 // MonitorConfig holds configuration for the health monitor.
@@ -124,11 +168,58 @@ type MonitorConfig struct {
 	MetricsPort            int
 	CacheTTL               time.Duration
 	StartupGracePeriod     time.Duration
+	// StateFile, if set, persists discovered health endpoints and circuit
+	// breaker trips across runs. It is loaded by NewHealthChecker.
+	StateFile string
+	// StateTTL discards persisted entries older than this. Defaults to DefaultStateTTL.
+	StateTTL time.Duration
+	// ServiceOverrides customizes settings for individual services by name.
+	ServiceOverrides map[string]ServiceOverride
+	// Logger receives check records and CMD-SHELL command warnings. Defaults
+	// to the logutil "healthcheck" component logger.
+	Logger *logutil.ComponentLogger
+	// LogChecks logs every check result to Logger: debug for healthy
+	// services, warn with the error and suggestion for failures. Off by
+	// default, since polling loops such as WaitForHealthy would otherwise
+	// log a warning for every failed attempt.
+	LogChecks bool
+	// MaxConcurrentChecks limits how many checks the checker runs at once
+	// across all callers, including concurrent CheckAll and WaitForHealthy
+	// calls. 0 means no limit.
+	MaxConcurrentChecks int
+	// HistorySize is the number of recent results kept per service for
+	// HealthChecker.Stats and History. Defaults to DefaultHistorySize; a
+	// negative value keeps no history.
+	HistorySize int
+	// DisableShellChecks rejects CMD-SHELL checks (and bare command strings,
+	// which run through a shell) as unhealthy instead of running them. Use it
+	// in restricted environments where health check configuration is not
+	// trusted; CMD checks, which run without a shell, are unaffected.
+	DisableShellChecks bool
+	// MaintenanceWindows are planned periods, such as restarts during a
+	// demo, in which failing checks are reported as HealthStatusMaintenance
+	// rather than unhealthy. Invalid windows are logged and ignored.
+	MaintenanceWindows []MaintenanceWindow
+}
+
+// ServiceOverride replaces MonitorConfig settings for a single service.
+// Zero-valued fields inherit the MonitorConfig value.
+type ServiceOverride struct {
+	Timeout                time.Duration
+	StartupGracePeriod     time.Duration
+	RateLimit              int
+	EnableCircuitBreaker   *bool
+	CircuitBreakerFailures int
+	CircuitBreakerTimeout  time.Duration
 }
 
 // ServiceInfo holds information about a service for health checking.
 type ServiceInfo struct {
-	Name           string
+	Name string
+	// Host is the host name or IP address checked by HTTP and TCP checks.
+	// Empty, "localhost", and wildcard addresses (0.0.0.0, ::) check both
+	// the IPv4 and IPv6 loopback addresses.
+	Host           string
 	Port           int
 	PID            int
 	StartTime      time.Time
@@ -150,6 +241,29 @@ type HealthCheckConfig struct {
 	Retries       int
 	StartPeriod   time.Duration
 	StartInterval time.Duration
+	// Env holds extra variables for CMD and CMD-SHELL checks, added to the
+	// inherited environment.
+	Env map[string]string
+	// WorkDir is the working directory for CMD and CMD-SHELL checks.
+	// Empty means the current directory.
+	WorkDir string
+	// CaptureOutput records the last lines of stderr from CMD and CMD-SHELL
+	// checks in HealthCheckResult.Details["stderr"].
+	CaptureOutput bool
+	// Shell is the interpreter for CMD-SHELL checks: "sh", "bash", "zsh",
+	// "pwsh", "powershell", or "cmd". Empty picks one from a script path's
+	// extension (via shellutil.DetectShell), and otherwise uses cmd on
+	// Windows and sh elsewhere.
+	Shell string
+	// Method is the HTTP method for HTTP checks. Empty means GET, or POST
+	// when Body is set.
+	Method string
+	// Headers are added to every HTTP check request, for example an Accept
+	// header or an API key. A "Host" entry overrides the request host.
+	Headers map[string]string
+	// Body is sent with HTTP check requests. A Content-Type header is
+	// added for it when Headers does not set one.
+	Body string
 }
 
 // httpHealthCheckResult holds the result of an HTTP health check.
@@ -162,6 +276,12 @@ type httpHealthCheckResult struct {
 	Error        string
 }
 
+// Summarize calculates aggregate health statistics, including the overall
+// status, for a set of check results.
+func Summarize(results []HealthCheckResult) HealthSummary {
+	return calculateSummary(results)
+}
+
 // calculateSummary calculates health statistics from a slice of results.
 func calculateSummary(results []HealthCheckResult) HealthSummary {
 	summary := HealthSummary{
@@ -178,6 +298,8 @@ func calculateSummary(results []HealthCheckResult) HealthSummary {
 			summary.Unhealthy++
 		case HealthStatusStarting:
 			summary.Starting++
+		case HealthStatusMaintenance:
+			summary.Maintenance++
 		default:
 			summary.Unknown++
 		}
@@ -187,6 +309,8 @@ func calculateSummary(results []HealthCheckResult) HealthSummary {
 		summary.Overall = HealthStatusUnhealthy
 	} else if summary.Degraded > 0 {
 		summary.Overall = HealthStatusDegraded
+	} else if summary.Maintenance > 0 {
+		summary.Overall = HealthStatusMaintenance
 	} else if summary.Healthy > 0 {
 		summary.Overall = HealthStatusHealthy
 	} else if summary.Starting > 0 {
@@ -2,13 +2,18 @@ package healthcheck
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/testutil"
 )
 
 func TestHealthStatus(t *testing.T) {
@@ -118,6 +123,36 @@ func TestCalculateSummary(t *testing.T) {
 	}
 }
 
+func TestHealthCheckResultErr(t *testing.T) {
+	if err := (HealthCheckResult{Status: HealthStatusHealthy}).Err(); err != nil {
+		t.Errorf("healthy result: got %v, want nil", err)
+	}
+	if err := (HealthCheckResult{Status: HealthStatusStarting}).Err(); err != nil {
+		t.Errorf("starting result: got %v, want nil", err)
+	}
+
+	result := HealthCheckResult{
+		ServiceName:  "api",
+		Status:       HealthStatusUnhealthy,
+		Error:        "connection fai...",
+		ErrorDetails: "connection failed: AccountKey=abc123secret",
+		Details:      map[string]interface{}{"suggestion": "Check that the service is listening on port 8080"},
+	}
+	var rich *cliout.RichError
+	if !errors.As(result.Err(), &rich) {
+		t.Fatalf("expected *cliout.RichError, got %T", result.Err())
+	}
+	if rich.Code != CodeServiceUnhealthy || rich.Summary != "service api is unhealthy" {
+		t.Errorf("unexpected error: %+v", rich)
+	}
+	if !strings.HasPrefix(rich.Details, "connection failed") || strings.Contains(rich.Details, "abc123secret") {
+		t.Errorf("Details = %q, want full redacted message", rich.Details)
+	}
+	if len(rich.Suggestions) != 1 || rich.Suggestions[0] != "Check that the service is listening on port 8080" {
+		t.Errorf("Suggestions = %v", rich.Suggestions)
+	}
+}
+
 func TestHTTPHealthCheck(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -173,7 +208,7 @@ func TestHTTPHealthCheck(t *testing.T) {
 				},
 			}
 
-			result := checker.tryHTTPHealthCheck(context.Background(), port)
+			result := checker.tryHTTPHealthCheck(context.Background(), "", "", port, nil)
 
 			if result == nil {
 				t.Fatal("Expected result, got nil")
@@ -207,7 +242,7 @@ func TestPortCheck(t *testing.T) {
 		t.Error("Expected port to be listening")
 	}
 
-	if checker.checkPort(context.Background(), 64999) {
+	if checker.checkPort(context.Background(), testutil.FreePort(t)) {
 		t.Error("Expected port to not be listening")
 	}
 }
@@ -285,7 +320,7 @@ func TestCheckServiceFallback(t *testing.T) {
 
 	svc := ServiceInfo{
 		Name: "test-service",
-		Port: 64998,
+		Port: testutil.FreePort(t),
 	}
 
 	result := checker.CheckService(context.Background(), svc)
@@ -390,7 +425,7 @@ func TestTryHTTPHealthCheck_Skips404(t *testing.T) {
 		},
 	}
 
-	result := checker.tryHTTPHealthCheck(context.Background(), port)
+	result := checker.tryHTTPHealthCheck(context.Background(), "", "", port, nil)
 
 	if result == nil {
 		t.Fatal("Expected non-nil result")
@@ -420,7 +455,7 @@ func TestTryHTTPHealthCheck_Skips400BadRequest(t *testing.T) {
 		},
 	}
 
-	result := checker.tryHTTPHealthCheck(context.Background(), port)
+	result := checker.tryHTTPHealthCheck(context.Background(), "", "", port, nil)
 
 	if result != nil {
 		t.Errorf("Expected nil result for 400 responses (cascade to port check), got status: %s", result.Status)
@@ -438,7 +473,7 @@ func TestCheckService_StoppedService(t *testing.T) {
 
 	svc := ServiceInfo{
 		Name:           "stopped-service",
-		Port:           64998,
+		Port:           testutil.FreePort(t),
 		RegistryStatus: "stopped",
 	}
 
@@ -464,7 +499,7 @@ func TestCheckService_RunningService(t *testing.T) {
 
 	svc := ServiceInfo{
 		Name:           "running-service",
-		Port:           64998,
+		Port:           testutil.FreePort(t),
 		RegistryStatus: "running",
 	}
 
@@ -0,0 +1,169 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultWaitTimeout is the overall deadline used by WaitForHealthy when
+	// WaitOptions.Timeout is zero.
+	DefaultWaitTimeout = 60 * time.Second
+
+	// DefaultWaitInterval is the delay between polling rounds used by
+	// WaitForHealthy when WaitOptions.Interval is zero.
+	DefaultWaitInterval = time.Second
+)
+
+// ErrWaitTimeout is returned by WaitForHealthy when services do not become
+// healthy before their deadlines.
+var ErrWaitTimeout = errors.New("timed out waiting for services to become healthy")
+
+// WaitOptions configures WaitForHealthy.
+type WaitOptions struct {
+	// Timeout is the deadline for each service to become healthy.
+	// Defaults to DefaultWaitTimeout.
+	Timeout time.Duration
+	// ServiceTimeouts overrides Timeout for individual services by name.
+	ServiceTimeouts map[string]time.Duration
+	// Interval is the delay between polling rounds. Defaults to DefaultWaitInterval.
+	Interval time.Duration
+	// RequireAll waits for every service to become healthy. When false,
+	// WaitForHealthy returns as soon as any service is healthy.
+	RequireAll bool
+	// OnResult, if set, receives every interim check result. It is called
+	// from the waiting goroutine, never concurrently.
+	OnResult func(HealthCheckResult)
+}
+
+// WaitForHealthy polls services with checker until all (RequireAll) or any of
+// them report HealthStatusHealthy, or until their deadlines pass. It returns
+// the most recent result for each service in input order.
+//
+// On timeout the error wraps ErrWaitTimeout and names the services that did
+// not become healthy. If ctx is canceled, ctx.Err() is returned.
+//
+// Example:
+//
+//	results, err := healthcheck.WaitForHealthy(ctx, checker, services, healthcheck.WaitOptions{
+//	    Timeout:    2 * time.Minute,
+//	    RequireAll: true,
+//	    OnResult: func(r healthcheck.HealthCheckResult) {
+//	        fmt.Printf("%s: %s\n", r.ServiceName, r.Status)
+//	    },
+//	})
+func WaitForHealthy(ctx context.Context, checker *HealthChecker, services []ServiceInfo, opts WaitOptions) ([]HealthCheckResult, error) {
+	if len(services) == 0 {
+		return nil, nil
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultWaitTimeout
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWaitInterval
+	}
+
+	start := time.Now()
+	deadlines := make([]time.Time, len(services))
+	for i, svc := range services {
+		timeout := opts.Timeout
+		if t, ok := opts.ServiceTimeouts[svc.Name]; ok && t > 0 {
+			timeout = t
+		}
+		deadlines[i] = start.Add(timeout)
+	}
+
+	results := make([]HealthCheckResult, len(services))
+	healthy := make([]bool, len(services))
+	expired := make([]bool, len(services))
+
+	for {
+		pending := make([]int, 0, len(services))
+		for i := range services {
+			if !healthy[i] && !expired[i] {
+				pending = append(pending, i)
+			}
+		}
+
+		checker.checkConcurrently(ctx, services, pending, results, 0)
+		now := time.Now()
+		for _, i := range pending {
+			if opts.OnResult != nil {
+				opts.OnResult(results[i])
+			}
+			if results[i].Status == HealthStatusHealthy {
+				healthy[i] = true
+			} else if !now.Before(deadlines[i]) {
+				expired[i] = true
+			}
+		}
+
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		done, err := waitOutcome(services, healthy, expired, opts.RequireAll)
+		if done {
+			return results, err
+		}
+
+		// Sleep until the next round, but not past the earliest pending deadline.
+		wait := opts.Interval
+		for i := range services {
+			if !healthy[i] && !expired[i] {
+				if remaining := time.Until(deadlines[i]); remaining < wait {
+					wait = max(remaining, 0)
+				}
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return results, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// waitOutcome decides whether waiting is finished and with what error.
+func waitOutcome(services []ServiceInfo, healthy, expired []bool, requireAll bool) (bool, error) {
+	var healthyCount, expiredCount int
+	var failed []string
+	for i := range services {
+		switch {
+		case healthy[i]:
+			healthyCount++
+		case expired[i]:
+			expiredCount++
+			failed = append(failed, services[i].Name)
+		}
+	}
+
+	if requireAll {
+		switch {
+		case healthyCount == len(services):
+			return true, nil
+		case expiredCount > 0:
+			return true, waitTimeoutError(failed)
+		}
+		return false, nil
+	}
+
+	switch {
+	case healthyCount > 0:
+		return true, nil
+	case expiredCount == len(services):
+		return true, waitTimeoutError(failed)
+	}
+	return false, nil
+}
+
+// waitTimeoutError wraps ErrWaitTimeout with the failed service names.
+func waitTimeoutError(failed []string) error {
+	sort.Strings(failed)
+	return fmt.Errorf("%w: %s", ErrWaitTimeout, strings.Join(failed, ", "))
+}
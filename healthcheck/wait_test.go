@@ -0,0 +1,115 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+func scriptedService(t *testing.T, name string, steps ...testutil.HealthStep) ServiceInfo {
+	t.Helper()
+	server := testutil.NewHealthServer(t, testutil.HealthScript{Steps: steps})
+	return ServiceInfo{
+		Name:        name,
+		HealthCheck: &HealthCheckConfig{Test: []string{server.URL + testutil.DefaultHealthPath}},
+	}
+}
+
+func newWaitChecker() *HealthChecker {
+	return NewHealthChecker(MonitorConfig{Timeout: 2 * time.Second})
+}
+
+func TestWaitForHealthy_RequireAll(t *testing.T) {
+	services := []ServiceInfo{
+		scriptedService(t, "api", testutil.Failing(http.StatusServiceUnavailable, 2), testutil.Healthy(1)),
+		scriptedService(t, "web", testutil.Healthy(1)),
+	}
+
+	var seen []HealthCheckResult
+	results, err := WaitForHealthy(context.Background(), newWaitChecker(), services, WaitOptions{
+		Timeout:    5 * time.Second,
+		Interval:   10 * time.Millisecond,
+		RequireAll: true,
+		OnResult:   func(r HealthCheckResult) { seen = append(seen, r) },
+	})
+	if err != nil {
+		t.Fatalf("WaitForHealthy: %v", err)
+	}
+	for i, r := range results {
+		if r.ServiceName != services[i].Name || r.Status != HealthStatusHealthy {
+			t.Errorf("result %d = %s/%s, want %s healthy", i, r.ServiceName, r.Status, services[i].Name)
+		}
+	}
+	// api: 2 failures + 1 success; web: checked once and then skipped.
+	if len(seen) != 4 {
+		t.Errorf("expected 4 interim results, got %d", len(seen))
+	}
+}
+
+func TestWaitForHealthy_Any(t *testing.T) {
+	services := []ServiceInfo{
+		scriptedService(t, "never", testutil.Failing(http.StatusInternalServerError, 1)),
+		scriptedService(t, "ready", testutil.Failing(http.StatusServiceUnavailable, 1), testutil.Healthy(1)),
+	}
+
+	results, err := WaitForHealthy(context.Background(), newWaitChecker(), services, WaitOptions{
+		Timeout:  5 * time.Second,
+		Interval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("WaitForHealthy: %v", err)
+	}
+	if results[1].Status != HealthStatusHealthy {
+		t.Errorf("expected ready to be healthy, got %s", results[1].Status)
+	}
+}
+
+func TestWaitForHealthy_PerServiceTimeout(t *testing.T) {
+	services := []ServiceInfo{
+		scriptedService(t, "slow", testutil.Failing(http.StatusServiceUnavailable, 1)),
+		scriptedService(t, "fast", testutil.Healthy(1)),
+	}
+
+	start := time.Now()
+	_, err := WaitForHealthy(context.Background(), newWaitChecker(), services, WaitOptions{
+		Timeout:         time.Minute,
+		ServiceTimeouts: map[string]time.Duration{"slow": 100 * time.Millisecond},
+		Interval:        20 * time.Millisecond,
+		RequireAll:      true,
+	})
+	if !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("expected ErrWaitTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "slow") || strings.Contains(err.Error(), "fast") {
+		t.Errorf("error should name only the slow service: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("per-service timeout not honored, took %v", elapsed)
+	}
+}
+
+func TestWaitForHealthy_ContextCanceled(t *testing.T) {
+	services := []ServiceInfo{
+		scriptedService(t, "down", testutil.Failing(http.StatusServiceUnavailable, 1)),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := WaitForHealthy(ctx, newWaitChecker(), services, WaitOptions{Interval: 10 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context error, got %v", err)
+	}
+}
+
+func TestWaitForHealthy_NoServices(t *testing.T) {
+	results, err := WaitForHealthy(context.Background(), newWaitChecker(), nil, WaitOptions{})
+	if err != nil || results != nil {
+		t.Errorf("expected nil, nil; got %v, %v", results, err)
+	}
+}
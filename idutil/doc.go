@@ -0,0 +1,22 @@
+// Package idutil generates and validates identifiers for Azure Developer CLI
+// extensions, such as names for temporary resources, service instance IDs,
+// and correlation IDs.
+//
+// NewULID returns a ULID (https://github.com/ulid/spec): 26 Crockford
+// base32 characters holding a millisecond timestamp and 80 random bits.
+// ULIDs sort by creation time, and IDs created in the same millisecond by
+// one process are strictly increasing. NewShortID returns n random lowercase
+// letters and digits, which are valid in DNS labels and most Azure resource
+// names. Both use crypto/rand.
+//
+// Example:
+//
+//	correlationID := idutil.NewULID()
+//	logger := slog.Default().With("correlation_id", correlationID)
+//
+//	name := "tmp-" + idutil.NewShortID(8) // e.g. "tmp-k3x9q2mz"
+//
+//	if err := idutil.ValidateULID(r.Header.Get("X-Correlation-ID")); err != nil {
+//	    return err
+//	}
+package idutil
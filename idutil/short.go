@@ -0,0 +1,61 @@
+package idutil
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// DefaultShortIDLength is the length NewShortID uses when n is not positive.
+// Eight characters give about 41 bits of randomness.
+const DefaultShortIDLength = 8
+
+// shortIDAlphabet holds the characters used by NewShortID: lowercase
+// letters and digits, valid in DNS labels and most Azure resource names.
+const shortIDAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// NewShortID returns n random lowercase letters and digits, or
+// DefaultShortIDLength characters if n is not positive. Each character is
+// chosen uniformly, so collisions become likely after about 36^(n/2) IDs;
+// use NewULID when IDs must be unique across many resources.
+func NewShortID(n int) string {
+	if n <= 0 {
+		n = DefaultShortIDLength
+	}
+
+	// Reject bytes at or above the largest multiple of the alphabet size
+	// so every character is equally likely.
+	const limit = 256 - 256%len(shortIDAlphabet)
+	out := make([]byte, 0, n)
+	buf := make([]byte, n+n/4+1)
+	for len(out) < n {
+		_, _ = rand.Read(buf)
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			out = append(out, shortIDAlphabet[int(b)%len(shortIDAlphabet)])
+			if len(out) == n {
+				break
+			}
+		}
+	}
+	return string(out)
+}
+
+// ValidateShortID checks that id consists only of lowercase letters and
+// digits and, when n is positive, is exactly n characters long.
+func ValidateShortID(id string, n int) error {
+	if id == "" {
+		return fmt.Errorf("%w: empty short ID", ErrInvalidID)
+	}
+	if n > 0 && len(id) != n {
+		return fmt.Errorf("%w: short ID must be %d characters, got %d", ErrInvalidID, n, len(id))
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'z') {
+			return fmt.Errorf("%w: short ID contains invalid character %q at position %d", ErrInvalidID, c, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package idutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewShortID(t *testing.T) {
+	for _, n := range []int{1, 6, 8, 32, 100} {
+		id := NewShortID(n)
+		if len(id) != n {
+			t.Errorf("NewShortID(%d) = %q, want %d characters", n, id, n)
+		}
+		if err := ValidateShortID(id, n); err != nil {
+			t.Errorf("NewShortID(%d) = %q is invalid: %v", n, id, err)
+		}
+	}
+	for _, n := range []int{0, -1} {
+		if id := NewShortID(n); len(id) != DefaultShortIDLength {
+			t.Errorf("NewShortID(%d) = %q, want %d characters", n, id, DefaultShortIDLength)
+		}
+	}
+}
+
+func TestNewShortID_Distribution(t *testing.T) {
+	seen := make(map[string]bool)
+	counts := make(map[rune]int)
+	for i := 0; i < 2000; i++ {
+		id := NewShortID(12)
+		if seen[id] {
+			t.Fatalf("duplicate short ID %q", id)
+		}
+		seen[id] = true
+		for _, r := range id {
+			counts[r]++
+		}
+	}
+	// Every character should appear; 24000 draws over 36 characters.
+	for _, r := range shortIDAlphabet {
+		if counts[r] == 0 {
+			t.Errorf("character %q never generated", r)
+		}
+	}
+}
+
+func TestValidateShortID(t *testing.T) {
+	tests := []struct {
+		id      string
+		n       int
+		wantErr bool
+	}{
+		{"abc123", 6, false},
+		{"abc123", 0, false},
+		{"abc123", 8, true},
+		{"", 0, true},
+		{"ABC123", 0, true},
+		{"abc-12", 0, true},
+		{"abc 12", 0, true},
+		{strings.Repeat("z", 40), 0, false},
+	}
+	for _, tt := range tests {
+		err := ValidateShortID(tt.id, tt.n)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateShortID(%q, %d) error = %v, wantErr %v", tt.id, tt.n, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ValidateShortID(%q, %d) error = %v, want ErrInvalidID", tt.id, tt.n, err)
+		}
+	}
+}
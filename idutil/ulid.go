@@ -0,0 +1,132 @@
+package idutil
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ULIDLength is the length of a ULID string.
+const ULIDLength = 26
+
+// ErrInvalidID is returned, wrapped, by the Validate functions.
+var ErrInvalidID = errors.New("invalid ID")
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// maxULIDTime is the largest timestamp a ULID can hold (48 bits).
+const maxULIDTime = 1<<48 - 1
+
+var (
+	// ulidMu guards the last timestamp and random part, used to keep ULIDs
+	// from one process strictly increasing.
+	ulidMu      sync.Mutex
+	ulidLastMs  uint64
+	ulidLastRnd [10]byte
+
+	// ulidNow is replaced in tests.
+	ulidNow = time.Now
+)
+
+// NewULID returns a new ULID in canonical uppercase form. It is safe for
+// concurrent use. Within one process, each ULID sorts after the previous
+// one, even when several are created in the same millisecond or the clock
+// moves backwards.
+func NewULID() string {
+	ulidMu.Lock()
+	defer ulidMu.Unlock()
+
+	ms := uint64(ulidNow().UnixMilli())
+	if ms <= ulidLastMs {
+		// Same millisecond (or a clock step back): increment the random
+		// part, moving to the next millisecond if it overflows.
+		ms = ulidLastMs
+		if !incrementBytes(ulidLastRnd[:]) {
+			ms++
+			_, _ = rand.Read(ulidLastRnd[:])
+		}
+	} else {
+		_, _ = rand.Read(ulidLastRnd[:])
+	}
+	ms &= maxULIDTime
+	ulidLastMs = ms
+
+	var b [16]byte
+	for i := 0; i < 6; i++ {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	copy(b[6:], ulidLastRnd[:])
+	return encodeULID(b)
+}
+
+// ValidateULID checks that id is a well-formed ULID. Lowercase letters are
+// accepted.
+func ValidateULID(id string) error {
+	if len(id) != ULIDLength {
+		return fmt.Errorf("%w: ULID must be %d characters, got %d", ErrInvalidID, ULIDLength, len(id))
+	}
+	for i := 0; i < len(id); i++ {
+		if crockfordValue(id[i]) < 0 {
+			return fmt.Errorf("%w: ULID contains invalid character %q at position %d", ErrInvalidID, id[i], i)
+		}
+	}
+	// 26 characters hold 130 bits; the top two must be zero.
+	if crockfordValue(id[0]) > 7 {
+		return fmt.Errorf("%w: ULID timestamp overflows 48 bits", ErrInvalidID)
+	}
+	return nil
+}
+
+// ULIDTime returns the creation time encoded in a ULID, with millisecond
+// precision.
+func ULIDTime(id string) (time.Time, error) {
+	if err := ValidateULID(id); err != nil {
+		return time.Time{}, err
+	}
+	var ms int64
+	for i := 0; i < 10; i++ {
+		ms = ms<<5 | int64(crockfordValue(id[i]))
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters. The first
+// character carries only 3 bits, as if the value had two leading zero bits.
+func encodeULID(b [16]byte) string {
+	var out [ULIDLength]byte
+	for i := range out {
+		var v byte
+		for bit := i*5 - 2; bit < i*5+3; bit++ {
+			v <<= 1
+			if bit >= 0 && b[bit/8]&(0x80>>(bit%8)) != 0 {
+				v |= 1
+			}
+		}
+		out[i] = crockford[v]
+	}
+	return string(out[:])
+}
+
+// crockfordValue returns the value of a Crockford base32 character, or -1.
+func crockfordValue(c byte) int {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	return strings.IndexByte(crockford, c)
+}
+
+// incrementBytes adds one to b as a big-endian number and reports whether
+// it did not overflow.
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package idutil
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// withULIDClock fixes the clock used by NewULID and resets the monotonic
+// state for a test.
+func withULIDClock(t *testing.T, now time.Time) *time.Time {
+	t.Helper()
+	ulidMu.Lock()
+	oldNow, oldMs, oldRnd := ulidNow, ulidLastMs, ulidLastRnd
+	ulidLastMs, ulidLastRnd = 0, [10]byte{}
+	ulidMu.Unlock()
+
+	clock := now
+	ulidNow = func() time.Time { return clock }
+	t.Cleanup(func() {
+		ulidMu.Lock()
+		ulidNow, ulidLastMs, ulidLastRnd = oldNow, oldMs, oldRnd
+		ulidMu.Unlock()
+	})
+	return &clock
+}
+
+func TestEncodeULID(t *testing.T) {
+	// Timestamp from the ULID specification's example.
+	const ms = 1469918176385
+	var b [16]byte
+	for i := 0; i < 6; i++ {
+		b[i] = byte(uint64(ms) >> (40 - 8*i))
+	}
+	if got, want := encodeULID(b), "01ARYZ6S410000000000000000"; got != want {
+		t.Errorf("encodeULID() = %s, want %s", got, want)
+	}
+
+	for i := range b {
+		b[i] = 0xFF
+	}
+	if got, want := encodeULID(b), "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"; got != want {
+		t.Errorf("encodeULID(max) = %s, want %s", got, want)
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	withULIDClock(t, now)
+
+	id := NewULID()
+	if err := ValidateULID(id); err != nil {
+		t.Fatalf("NewULID() = %q is invalid: %v", id, err)
+	}
+	if id != strings.ToUpper(id) {
+		t.Errorf("NewULID() = %q, want uppercase", id)
+	}
+	got, err := ULIDTime(id)
+	if err != nil || !got.Equal(now) {
+		t.Errorf("ULIDTime(%q) = %v, %v, want %v", id, got, err, now)
+	}
+}
+
+func TestNewULID_Monotonic(t *testing.T) {
+	clock := withULIDClock(t, time.UnixMilli(1700000000000))
+
+	var ids []string
+	for i := 0; i < 100; i++ {
+		ids = append(ids, NewULID())
+	}
+	*clock = clock.Add(-time.Second) // clock moves backwards
+	ids = append(ids, NewULID())
+	*clock = clock.Add(2 * time.Second)
+	ids = append(ids, NewULID())
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ULID %d (%s) does not sort after %s", i, ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestNewULID_RandomOverflow(t *testing.T) {
+	withULIDClock(t, time.UnixMilli(1700000000000))
+	first := NewULID()
+
+	ulidMu.Lock()
+	for i := range ulidLastRnd {
+		ulidLastRnd[i] = 0xFF
+	}
+	ulidMu.Unlock()
+
+	next := NewULID()
+	if next <= first {
+		t.Errorf("ULID after overflow %s does not sort after %s", next, first)
+	}
+	if ts, _ := ULIDTime(next); ts.UnixMilli() != 1700000000001 {
+		t.Errorf("ULID after overflow has time %d, want the next millisecond", ts.UnixMilli())
+	}
+}
+
+func TestNewULID_Concurrent(t *testing.T) {
+	const n = 1000
+	ids := make([]string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids[i] = NewULID()
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Strings(ids)
+	for i := 1; i < n; i++ {
+		if ids[i] == ids[i-1] {
+			t.Fatalf("duplicate ULID %s", ids[i])
+		}
+	}
+}
+
+func TestValidateULID(t *testing.T) {
+	tests := []struct {
+		id      string
+		wantErr bool
+	}{
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", false},
+		{"01arz3ndektsv4rrffq69g5fav", false},
+		{"7ZZZZZZZZZZZZZZZZZZZZZZZZZ", false},
+		{"", true},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FA", true},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAVX", true},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAU", true}, // U is not in the alphabet
+		{"01ARZ3NDEKTSV4RRFFQ69G5FA-", true},
+		{"8ZZZZZZZZZZZZZZZZZZZZZZZZZ", true}, // overflows 128 bits
+	}
+	for _, tt := range tests {
+		err := ValidateULID(tt.id)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateULID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ValidateULID(%q) error = %v, want ErrInvalidID", tt.id, err)
+		}
+	}
+}
+
+func TestULIDTime(t *testing.T) {
+	got, err := ULIDTime("01ARYZ6S410000000000000000")
+	if err != nil || got.UnixMilli() != 1469918176385 {
+		t.Errorf("ULIDTime() = %v (%d), %v", got, got.UnixMilli(), err)
+	}
+	if _, err := ULIDTime("not-a-ulid"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("ULIDTime() error = %v, want ErrInvalidID", err)
+	}
+}
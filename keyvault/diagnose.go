@@ -0,0 +1,188 @@
+package keyvault
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+const (
+	// keyVaultScope is the token scope requested when diagnosing credentials.
+	keyVaultScope = "https://vault.azure.net/.default"
+
+	// diagnoseTimeout bounds each credential attempt so an unreachable
+	// managed identity endpoint does not stall diagnostics.
+	diagnoseTimeout = 15 * time.Second
+)
+
+// CredentialStatus is the outcome of a single credential attempt.
+type CredentialStatus string
+
+const (
+	// CredentialSucceeded means the credential acquired a Key Vault token.
+	CredentialSucceeded CredentialStatus = "succeeded"
+	// CredentialFailed means the credential was configured but could not
+	// acquire a token.
+	CredentialFailed CredentialStatus = "failed"
+	// CredentialUnavailable means the credential is not configured in this
+	// environment and was skipped.
+	CredentialUnavailable CredentialStatus = "unavailable"
+)
+
+// CredentialAttempt records how one credential source fared.
+type CredentialAttempt struct {
+	Name   string           `json:"name"`
+	Status CredentialStatus `json:"status"`
+	Error  string           `json:"error,omitempty"`
+	// Hint is an action that would make this credential work.
+	Hint string `json:"hint,omitempty"`
+}
+
+// Diagnosis reports which credential sources were tried for Key Vault access.
+type Diagnosis struct {
+	// Attempts lists credentials in the order they were tried. Like
+	// DefaultAzureCredential, diagnosis stops at the first success.
+	Attempts []CredentialAttempt `json:"attempts"`
+	// Succeeded names the credential that acquired a token, if any.
+	Succeeded string `json:"succeeded,omitempty"`
+}
+
+// OK reports whether any credential acquired a token.
+func (d *Diagnosis) OK() bool {
+	return d.Succeeded != ""
+}
+
+// Hints returns the hints for every credential that did not succeed, in
+// chain order. It is empty when a credential succeeded.
+func (d *Diagnosis) Hints() []string {
+	if d.OK() {
+		return nil
+	}
+	var hints []string
+	for _, a := range d.Attempts {
+		if a.Hint != "" {
+			hints = append(hints, fmt.Sprintf("%s: %s", a.Name, a.Hint))
+		}
+	}
+	return hints
+}
+
+// credentialSource builds one member of the DefaultAzureCredential chain.
+type credentialSource struct {
+	name string
+	hint string
+	new  func() (azcore.TokenCredential, error)
+}
+
+// defaultCredentialSources mirrors the DefaultAzureCredential chain order.
+// Tests replace it.
+var defaultCredentialSources = []credentialSource{
+	{
+		name: "EnvironmentCredential",
+		hint: "set AZURE_TENANT_ID, AZURE_CLIENT_ID, and AZURE_CLIENT_SECRET (or AZURE_CLIENT_CERTIFICATE_PATH)",
+		new: func() (azcore.TokenCredential, error) {
+			return azidentity.NewEnvironmentCredential(nil)
+		},
+	},
+	{
+		name: "WorkloadIdentityCredential",
+		hint: "set AZURE_TENANT_ID, AZURE_CLIENT_ID, and AZURE_FEDERATED_TOKEN_FILE for federated (workload identity) sign-in",
+		new: func() (azcore.TokenCredential, error) {
+			return azidentity.NewWorkloadIdentityCredential(nil)
+		},
+	},
+	{
+		name: "ManagedIdentityCredential",
+		hint: "enable a managed identity on this host; set AZURE_CLIENT_ID to use a user-assigned identity",
+		new: func() (azcore.TokenCredential, error) {
+			return azidentity.NewManagedIdentityCredential(nil)
+		},
+	},
+	{
+		name: "AzureCLICredential",
+		hint: "run 'az login'",
+		new: func() (azcore.TokenCredential, error) {
+			return azidentity.NewAzureCLICredential(nil)
+		},
+	},
+	{
+		name: "AzureDeveloperCLICredential",
+		hint: "run 'azd auth login'",
+		new: func() (azcore.TokenCredential, error) {
+			return azidentity.NewAzureDeveloperCLICredential(nil)
+		},
+	},
+	{
+		name: "AzurePowerShellCredential",
+		hint: "run 'Connect-AzAccount'",
+		new: func() (azcore.TokenCredential, error) {
+			return azidentity.NewAzurePowerShellCredential(nil)
+		},
+	},
+}
+
+// Diagnose tries to acquire a Key Vault token with each credential source
+// and reports which were attempted, which succeeded, and how to fix the
+// rest. For a resolver created with NewKeyVaultResolver, each member of the
+// DefaultAzureCredential chain is tried individually; for a resolver with a
+// caller-supplied credential, only that credential is tried.
+//
+// Call it after resolution fails to explain why:
+//
+//	if _, _, err := env.ResolveMap(ctx, envMap, resolver, opts); err != nil {
+//	    d := resolver.Diagnose(ctx)
+//	    cliout.Hint(d.Hints()...)
+//	}
+func (r *KeyVaultResolver) Diagnose(ctx context.Context) *Diagnosis {
+	sources := defaultCredentialSources
+	if !r.defaultChain {
+		cred := r.credential
+		sources = []credentialSource{{
+			name: fmt.Sprintf("%T", cred),
+			new:  func() (azcore.TokenCredential, error) { return cred, nil },
+		}}
+	}
+
+	d := &Diagnosis{}
+	for _, src := range sources {
+		if ctx.Err() != nil {
+			break
+		}
+		attempt := diagnoseSource(ctx, src)
+		d.Attempts = append(d.Attempts, attempt)
+		if attempt.Status == CredentialSucceeded {
+			d.Succeeded = attempt.Name
+			break
+		}
+	}
+	return d
+}
+
+// diagnoseSource builds src and requests a Key Vault token with it.
+func diagnoseSource(ctx context.Context, src credentialSource) CredentialAttempt {
+	attempt := CredentialAttempt{Name: src.name}
+
+	cred, err := src.new()
+	if err != nil {
+		attempt.Status = CredentialUnavailable
+		attempt.Error = err.Error()
+		attempt.Hint = src.hint
+		return attempt
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnoseTimeout)
+	defer cancel()
+	if _, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{keyVaultScope}}); err != nil {
+		attempt.Status = CredentialFailed
+		attempt.Error = err.Error()
+		attempt.Hint = src.hint
+		return attempt
+	}
+
+	attempt.Status = CredentialSucceeded
+	return attempt
+}
@@ -0,0 +1,102 @@
+package keyvault
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+type failingTestCredential struct{ err error }
+
+func (c failingTestCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{}, c.err
+}
+
+func withCredentialSources(t *testing.T, sources []credentialSource) {
+	t.Helper()
+	orig := defaultCredentialSources
+	defaultCredentialSources = sources
+	t.Cleanup(func() { defaultCredentialSources = orig })
+}
+
+func TestDiagnose_DefaultChain(t *testing.T) {
+	var built []string
+	source := func(name string, cred azcore.TokenCredential, err error) credentialSource {
+		return credentialSource{name: name, hint: "fix " + name, new: func() (azcore.TokenCredential, error) {
+			built = append(built, name)
+			return cred, err
+		}}
+	}
+	withCredentialSources(t, []credentialSource{
+		source("Env", nil, errors.New("missing AZURE_CLIENT_ID")),
+		source("MI", failingTestCredential{errors.New("no identity endpoint")}, nil),
+		source("CLI", staticTestCredential{}, nil),
+		source("AZD", staticTestCredential{}, nil),
+	})
+
+	resolver := &KeyVaultResolver{defaultChain: true}
+	d := resolver.Diagnose(context.Background())
+
+	if !d.OK() || d.Succeeded != "CLI" {
+		t.Fatalf("expected CLI to succeed, got %+v", d)
+	}
+	if len(built) != 3 {
+		t.Errorf("expected diagnosis to stop after success, built %v", built)
+	}
+	want := []CredentialStatus{CredentialUnavailable, CredentialFailed, CredentialSucceeded}
+	for i, status := range want {
+		if d.Attempts[i].Status != status {
+			t.Errorf("attempt %d status = %s, want %s", i, d.Attempts[i].Status, status)
+		}
+	}
+	if d.Attempts[1].Error != "no identity endpoint" || d.Attempts[1].Hint != "fix MI" {
+		t.Errorf("unexpected failed attempt %+v", d.Attempts[1])
+	}
+	if d.Hints() != nil {
+		t.Errorf("expected no hints after success, got %v", d.Hints())
+	}
+}
+
+func TestDiagnose_AllFail(t *testing.T) {
+	withCredentialSources(t, []credentialSource{
+		{name: "Env", hint: "set AZURE_TENANT_ID", new: func() (azcore.TokenCredential, error) { return nil, errors.New("unset") }},
+		{name: "CLI", hint: "run 'az login'", new: func() (azcore.TokenCredential, error) {
+			return failingTestCredential{errors.New("not logged in")}, nil
+		}},
+	})
+
+	d := (&KeyVaultResolver{defaultChain: true}).Diagnose(context.Background())
+	if d.OK() || len(d.Attempts) != 2 {
+		t.Fatalf("unexpected diagnosis %+v", d)
+	}
+	hints := d.Hints()
+	if len(hints) != 2 || hints[0] != "Env: set AZURE_TENANT_ID" || !strings.Contains(hints[1], "az login") {
+		t.Errorf("Hints() = %v", hints)
+	}
+}
+
+func TestDiagnose_CustomCredential(t *testing.T) {
+	resolver, err := NewKeyVaultResolverWithCredential(failingTestCredential{errors.New("denied")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := resolver.Diagnose(context.Background())
+	if len(d.Attempts) != 1 || d.Attempts[0].Status != CredentialFailed || d.Attempts[0].Name != "keyvault.failingTestCredential" {
+		t.Errorf("unexpected diagnosis %+v", d)
+	}
+}
+
+func TestDiagnose_CanceledContext(t *testing.T) {
+	withCredentialSources(t, []credentialSource{
+		{name: "CLI", new: func() (azcore.TokenCredential, error) { return staticTestCredential{}, nil }},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if d := (&KeyVaultResolver{defaultChain: true}).Diagnose(ctx); len(d.Attempts) != 0 {
+		t.Errorf("expected no attempts with canceled context, got %+v", d.Attempts)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 )
@@ -26,20 +27,43 @@ var (
 
 // KeyVaultResolver resolves Azure Key Vault references to secret values.
 type KeyVaultResolver struct {
-	credential *azidentity.DefaultAzureCredential
+	credential azcore.TokenCredential
 	clients    map[string]*azsecrets.Client
 	mu         sync.RWMutex
+	// defaultChain is true when credential is a DefaultAzureCredential.
+	defaultChain bool
+	// hooks are set by SetResolveHooks (see telemetry.go).
+	hooks ResolveHooks
 }
 
 // KeyVaultResolutionWarning captures non-fatal resolution failures.
+// Build one with NewResolutionWarning to fill in the vault, secret, and
+// category, and use String for a message that is safe to show users.
 type KeyVaultResolutionWarning struct {
 	Key string
 	Err error
+	// VaultName and SecretName identify the referenced secret. They are
+	// empty if the reference could not be parsed.
+	VaultName  string
+	SecretName string
+	// Category classifies Err for aggregation and remediation hints.
+	Category WarningCategory
+	// HTTPStatus is the Key Vault response status, or 0 if the request
+	// did not get a response.
+	HTTPStatus int
 }
 
 // ResolveEnvironmentOptions configures environment resolution behavior.
 type ResolveEnvironmentOptions struct {
 	StopOnError bool
+	// LocalSecretsPath points at a developer secrets file (for example
+	// DefaultLocalSecretsPath) consulted before Key Vault, for local
+	// development without Azure access. Every value taken from it is
+	// reported as a warning wrapping ErrLocalSecret.
+	LocalSecretsPath string
+	// LocalSecretsOnly resolves references from LocalSecretsPath only and
+	// never calls Key Vault. Missing secrets fail with ErrLocalSecretNotFound.
+	LocalSecretsOnly bool
 }
 
 // NewKeyVaultResolver builds a resolver using DefaultAzureCredential.
@@ -49,6 +73,21 @@ func NewKeyVaultResolver() (*KeyVaultResolver, error) {
 		return nil, fmt.Errorf("failed to create DefaultAzureCredential: %w", err)
 	}
 
+	return &KeyVaultResolver{
+		credential:   cred,
+		clients:      make(map[string]*azsecrets.Client),
+		defaultChain: true,
+	}, nil
+}
+
+// NewKeyVaultResolverWithCredential builds a resolver using the provided credential.
+// This allows callers to supply a specific credential type (or a test stub)
+// instead of DefaultAzureCredential.
+func NewKeyVaultResolverWithCredential(cred azcore.TokenCredential) (*KeyVaultResolver, error) {
+	if cred == nil {
+		return nil, fmt.Errorf("credential cannot be nil")
+	}
+
 	return &KeyVaultResolver{
 		credential: cred,
 		clients:    make(map[string]*azsecrets.Client),
@@ -76,11 +115,19 @@ func IsKeyVaultReference(value string) bool {
 
 // ResolveReference resolves a single Key Vault reference to its secret value.
 func (r *KeyVaultResolver) ResolveReference(ctx context.Context, reference string) (string, error) {
+	end := r.startResolve(ctx, reference)
+	var info resolveInfo
+	value, err := r.resolveReference(ctx, reference, &info)
+	end(ResolveOutcomeSuccess, info.clientReused, err)
+	return value, err
+}
+
+func (r *KeyVaultResolver) resolveReference(ctx context.Context, reference string, info *resolveInfo) (string, error) {
 	reference = normalizeKeyVaultReferenceValue(reference)
 
 	if matches := kvRefSecretURIPattern.FindStringSubmatch(reference); matches != nil {
 		secretURI := strings.TrimSpace(matches[1])
-		return r.resolveBySecretURI(ctx, secretURI)
+		return r.resolveBySecretURI(ctx, secretURI, info)
 	}
 
 	if matches := kvRefVaultNamePattern.FindStringSubmatch(reference); matches != nil {
@@ -90,7 +137,7 @@ func (r *KeyVaultResolver) ResolveReference(ctx context.Context, reference strin
 		if len(matches) > 3 && matches[3] != "" {
 			version = matches[3]
 		}
-		return r.resolveByVaultNameAndSecret(ctx, vaultName, secretName, version)
+		return r.resolveByVaultNameAndSecret(ctx, vaultName, secretName, version, info)
 	}
 
 	if strings.HasPrefix(reference, "akvs://") {
@@ -103,17 +150,29 @@ func (r *KeyVaultResolver) ResolveReference(ctx context.Context, reference strin
 		if err != nil {
 			return "", err
 		}
-		return r.resolveByVaultNameAndSecret(ctx, vaultName, secretName, version)
+		return r.resolveByVaultNameAndSecret(ctx, vaultName, secretName, version, info)
 	}
 
 	return "", fmt.Errorf("invalid Key Vault reference format")
 }
 
 // ResolveEnvironmentVariables resolves references in KEY=VALUE entries.
+// When options.LocalSecretsPath is set, references found in that file are
+// resolved locally and reported as ErrLocalSecret warnings.
 func (r *KeyVaultResolver) ResolveEnvironmentVariables(ctx context.Context, envVars []string, options ResolveEnvironmentOptions) ([]string, []KeyVaultResolutionWarning, error) {
 	resolved := make([]string, 0, len(envVars))
 	var warnings []KeyVaultResolutionWarning
 
+	var local *localSecrets
+	if options.LocalSecretsPath != "" {
+		var err error
+		if local, err = loadLocalSecrets(options.LocalSecretsPath); err != nil {
+			return nil, nil, err
+		}
+	} else if options.LocalSecretsOnly {
+		return nil, nil, fmt.Errorf("LocalSecretsOnly requires LocalSecretsPath")
+	}
+
 	for _, envVar := range envVars {
 		// Check for context cancellation to allow early termination
 		select {
@@ -136,13 +195,25 @@ func (r *KeyVaultResolver) ResolveEnvironmentVariables(ctx context.Context, envV
 			continue
 		}
 
-		secretValue, err := r.ResolveReference(ctx, value)
-		if err != nil {
-			warning := KeyVaultResolutionWarning{
-				Key: key,
-				Err: err,
+		if local != nil {
+			if secretValue, ok := local.lookup(value); ok {
+				r.startResolve(ctx, value)(ResolveOutcomeLocal, false, nil)
+				warnings = append(warnings, NewResolutionWarning(key, value, fmt.Errorf("%w (%s)", ErrLocalSecret, local.path)))
+				resolved = append(resolved, fmt.Sprintf("%s=%s", key, secretValue))
+				continue
 			}
-			warnings = append(warnings, warning)
+		}
+
+		var secretValue string
+		var err error
+		if options.LocalSecretsOnly {
+			err = ErrLocalSecretNotFound
+			r.startResolve(ctx, value)(ResolveOutcomeError, false, err)
+		} else {
+			secretValue, err = r.ResolveReference(ctx, value)
+		}
+		if err != nil {
+			warnings = append(warnings, NewResolutionWarning(key, value, err))
 
 			if options.StopOnError {
 				return nil, warnings, fmt.Errorf("failed to resolve Key Vault reference for %s: %w", key, err)
@@ -159,12 +230,19 @@ func (r *KeyVaultResolver) ResolveEnvironmentVariables(ctx context.Context, envV
 }
 
 func (r *KeyVaultResolver) getClient(vaultURL string) (*azsecrets.Client, error) {
+	client, _, err := r.getCachedClient(vaultURL)
+	return client, err
+}
+
+// getCachedClient is getClient that also reports whether the client was
+// already cached.
+func (r *KeyVaultResolver) getCachedClient(vaultURL string) (*azsecrets.Client, bool, error) {
 	// Double-checked locking pattern: check without lock first for performance,
 	// then acquire write lock only if client doesn't exist
 	r.mu.RLock()
 	if client, ok := r.clients[vaultURL]; ok {
 		r.mu.RUnlock()
-		return client, nil
+		return client, true, nil
 	}
 	r.mu.RUnlock()
 
@@ -173,19 +251,19 @@ func (r *KeyVaultResolver) getClient(vaultURL string) (*azsecrets.Client, error)
 
 	// Re-check after acquiring write lock in case another goroutine created it
 	if client, ok := r.clients[vaultURL]; ok {
-		return client, nil
+		return client, true, nil
 	}
 
 	client, err := azsecrets.NewClient(vaultURL, r.credential, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Key Vault client: %w", err)
+		return nil, false, fmt.Errorf("failed to create Key Vault client: %w", err)
 	}
 
 	r.clients[vaultURL] = client
-	return client, nil
+	return client, false, nil
 }
 
-func (r *KeyVaultResolver) resolveBySecretURI(ctx context.Context, secretURI string) (string, error) {
+func (r *KeyVaultResolver) resolveBySecretURI(ctx context.Context, secretURI string, info *resolveInfo) (string, error) {
 	parts := strings.Split(secretURI, "/secrets/")
 	if len(parts) != 2 {
 		return "", fmt.Errorf("invalid secret URI format")
@@ -198,10 +276,11 @@ func (r *KeyVaultResolver) resolveBySecretURI(ctx context.Context, secretURI str
 		return "", err
 	}
 
-	client, err := r.getClient(vaultURL)
+	client, cached, err := r.getCachedClient(vaultURL)
 	if err != nil {
 		return "", err
 	}
+	info.clientReused = cached
 
 	secretParts := strings.Split(secretPath, "/")
 	secretName := secretParts[0]
@@ -229,17 +308,18 @@ func (r *KeyVaultResolver) resolveBySecretURI(ctx context.Context, secretURI str
 	return *resp.Value, nil
 }
 
-func (r *KeyVaultResolver) resolveByVaultNameAndSecret(ctx context.Context, vaultName, secretName, version string) (string, error) {
+func (r *KeyVaultResolver) resolveByVaultNameAndSecret(ctx context.Context, vaultName, secretName, version string, info *resolveInfo) (string, error) {
 	if err := validateVaultName(vaultName); err != nil {
 		return "", err
 	}
 
 	vaultURL := fmt.Sprintf("https://%s.vault.azure.net", vaultName)
 
-	client, err := r.getClient(vaultURL)
+	client, cached, err := r.getCachedClient(vaultURL)
 	if err != nil {
 		return "", err
 	}
+	info.clientReused = cached
 
 	var resp azsecrets.GetSecretResponse
 	if version != "" {
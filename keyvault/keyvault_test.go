@@ -3,6 +3,10 @@ package keyvault
 import (
 	"context"
 	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 )
 
 func TestIsKeyVaultReference(t *testing.T) {
@@ -856,3 +860,23 @@ func TestResolveEnvironmentVariables_ContextCancellation(t *testing.T) {
 
 	_ = warnings
 }
+
+type staticTestCredential struct{}
+
+func (staticTestCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "test-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestKeyVaultResolver_NewWithCredential(t *testing.T) {
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatalf("NewKeyVaultResolverWithCredential() error = %v", err)
+	}
+	if resolver.clients == nil {
+		t.Error("expected clients map to be initialized")
+	}
+
+	if _, err := NewKeyVaultResolverWithCredential(nil); err == nil {
+		t.Error("expected error for nil credential")
+	}
+}
@@ -0,0 +1,154 @@
+package keyvault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ListOptions configures ListSecrets.
+type ListOptions struct {
+	// Prefix limits results to secrets whose name starts with Prefix,
+	// compared case-insensitively as Key Vault secret names are.
+	Prefix string
+	// MaxResults caps the number of secrets returned. Zero means no limit.
+	MaxResults int
+}
+
+// SecretInfo describes a Key Vault secret without its value.
+type SecretInfo struct {
+	Name        string
+	VaultName   string
+	ID          string
+	ContentType string
+	Tags        map[string]string
+	Enabled     bool
+	// Managed is true for secrets backing a Key Vault certificate.
+	Managed bool
+	Created time.Time
+	Updated time.Time
+	Expires time.Time // zero if the secret does not expire
+}
+
+// Reference returns an App Service style reference to the latest version of
+// the secret, suitable for environment values resolved by ResolveReference.
+func (s SecretInfo) Reference() string {
+	return fmt.Sprintf("@Microsoft.KeyVault(VaultName=%s;SecretName=%s)", s.VaultName, s.Name)
+}
+
+// ListSecrets returns the names and metadata of secrets in a vault, sorted
+// by name. Secret values are never fetched. vault may be a vault name or a
+// https://<name>.vault.azure.net URL.
+//
+// Example:
+//
+//	secrets, err := resolver.ListSecrets(ctx, "myvault", keyvault.ListOptions{Prefix: "db-"})
+//	if err != nil {
+//	    return err
+//	}
+//	names := make([]string, len(secrets))
+//	for i, s := range secrets {
+//	    names[i] = s.Name
+//	}
+//	name, err := cliout.Select("Select a secret", names)
+func (r *KeyVaultResolver) ListSecrets(ctx context.Context, vault string, options ListOptions) ([]SecretInfo, error) {
+	if options.MaxResults < 0 {
+		return nil, fmt.Errorf("max results cannot be negative, got %d", options.MaxResults)
+	}
+
+	vaultURL, vaultName, err := parseVault(vault)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := r.getClient(vaultURL)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.ToLower(options.Prefix)
+	var secrets []SecretInfo
+	pager := client.NewListSecretPropertiesPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			// Don't include vault name in error to avoid information disclosure
+			return nil, fmt.Errorf("failed to list secrets from Key Vault: %w", err)
+		}
+		for _, props := range page.Value {
+			if props == nil || props.ID == nil {
+				continue
+			}
+			name := props.ID.Name()
+			if !strings.HasPrefix(strings.ToLower(name), prefix) {
+				continue
+			}
+
+			info := SecretInfo{
+				Name:      name,
+				VaultName: vaultName,
+				ID:        string(*props.ID),
+				Enabled:   true,
+			}
+			if props.ContentType != nil {
+				info.ContentType = *props.ContentType
+			}
+			if props.Managed != nil {
+				info.Managed = *props.Managed
+			}
+			if len(props.Tags) > 0 {
+				info.Tags = make(map[string]string, len(props.Tags))
+				for k, v := range props.Tags {
+					if v != nil {
+						info.Tags[k] = *v
+					}
+				}
+			}
+			if attrs := props.Attributes; attrs != nil {
+				if attrs.Enabled != nil {
+					info.Enabled = *attrs.Enabled
+				}
+				info.Created = derefTime(attrs.Created)
+				info.Updated = derefTime(attrs.Updated)
+				info.Expires = derefTime(attrs.Expires)
+			}
+			secrets = append(secrets, info)
+		}
+		if options.MaxResults > 0 && len(secrets) >= options.MaxResults {
+			break
+		}
+	}
+
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+	if options.MaxResults > 0 && len(secrets) > options.MaxResults {
+		secrets = secrets[:options.MaxResults]
+	}
+	return secrets, nil
+}
+
+// parseVault accepts a vault name or vault URL and returns both forms.
+func parseVault(vault string) (vaultURL, vaultName string, err error) {
+	vault = strings.TrimSpace(vault)
+	if strings.Contains(vault, "://") {
+		vaultURL = strings.TrimSuffix(vault, "/")
+		if err := validateVaultURL(vaultURL); err != nil {
+			return "", "", err
+		}
+		vaultName = strings.TrimSuffix(strings.TrimPrefix(vaultURL, "https://"), ".vault.azure.net")
+		return vaultURL, vaultName, nil
+	}
+
+	if err := validateVaultName(vault); err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("https://%s.vault.azure.net", vault), vault, nil
+}
+
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
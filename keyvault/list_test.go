@@ -0,0 +1,177 @@
+package keyvault
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// fakeVaultTransport serves Key Vault list-secrets pages, answering the
+// first unauthenticated request with the challenge azsecrets expects.
+type fakeVaultTransport struct {
+	pages    []map[string]any
+	requests int
+}
+
+func (f *fakeVaultTransport) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}
+		resp.Header.Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/tenant", resource="https://vault.azure.net"`)
+		return resp, nil
+	}
+
+	page := 0
+	if p := req.URL.Query().Get("page"); p != "" {
+		page = int(p[0] - '0')
+	}
+	f.requests++
+	body, _ := json.Marshal(f.pages[page])
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(string(body))), Request: req}, nil
+}
+
+func newFakeVaultResolver(t *testing.T, transport *fakeVaultTransport) *KeyVaultResolver {
+	t.Helper()
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vaultURL := "https://myvault.vault.azure.net"
+	client, err := azsecrets.NewClient(vaultURL, staticTestCredential{}, &azsecrets.ClientOptions{
+		ClientOptions: azcore.ClientOptions{Transport: transport},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver.clients[vaultURL] = client
+	return resolver
+}
+
+func secretItem(name string, enabled bool, extra map[string]any) map[string]any {
+	item := map[string]any{
+		"id":         "https://myvault.vault.azure.net/secrets/" + name,
+		"attributes": map[string]any{"enabled": enabled, "created": 1700000000, "updated": 1700000100},
+	}
+	for k, v := range extra {
+		item[k] = v
+	}
+	return item
+}
+
+func testVaultPages() []map[string]any {
+	return []map[string]any{
+		{
+			"value": []any{
+				secretItem("db-password", true, map[string]any{"contentType": "text/plain", "tags": map[string]any{"env": "dev"}}),
+				secretItem("api-key", true, nil),
+			},
+			"nextLink": "https://myvault.vault.azure.net/secrets?api-version=7.5&page=1",
+		},
+		{
+			"value": []any{
+				secretItem("DB-User", false, nil),
+				secretItem("cert", true, map[string]any{"managed": true}),
+			},
+		},
+	}
+}
+
+func TestListSecrets(t *testing.T) {
+	transport := &fakeVaultTransport{pages: testVaultPages()}
+	resolver := newFakeVaultResolver(t, transport)
+
+	secrets, err := resolver.ListSecrets(context.Background(), "myvault", ListOptions{})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+
+	var names []string
+	for _, s := range secrets {
+		names = append(names, s.Name)
+	}
+	if strings.Join(names, ",") != "DB-User,api-key,cert,db-password" {
+		t.Errorf("names = %v", names)
+	}
+
+	byName := map[string]SecretInfo{}
+	for _, s := range secrets {
+		byName[s.Name] = s
+	}
+	db := byName["db-password"]
+	if db.ContentType != "text/plain" || db.Tags["env"] != "dev" || !db.Enabled || db.Created.IsZero() || db.VaultName != "myvault" {
+		t.Errorf("db-password = %+v", db)
+	}
+	if byName["DB-User"].Enabled {
+		t.Error("DB-User should be disabled")
+	}
+	if !byName["cert"].Managed {
+		t.Error("cert should be managed")
+	}
+	if got, want := db.Reference(), "@Microsoft.KeyVault(VaultName=myvault;SecretName=db-password)"; got != want {
+		t.Errorf("Reference() = %q, want %q", got, want)
+	}
+	if !IsKeyVaultReference(db.Reference()) {
+		t.Error("Reference() should be recognized by IsKeyVaultReference")
+	}
+}
+
+func TestListSecrets_PrefixAndMaxResults(t *testing.T) {
+	transport := &fakeVaultTransport{pages: testVaultPages()}
+	resolver := newFakeVaultResolver(t, transport)
+
+	secrets, err := resolver.ListSecrets(context.Background(), "https://myvault.vault.azure.net/", ListOptions{Prefix: "db-"})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if len(secrets) != 2 || secrets[0].Name != "DB-User" || secrets[1].Name != "db-password" {
+		t.Errorf("prefix results = %+v", secrets)
+	}
+
+	transport.requests = 0
+	secrets, err = resolver.ListSecrets(context.Background(), "myvault", ListOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if len(secrets) != 1 {
+		t.Errorf("got %d secrets, want 1", len(secrets))
+	}
+	if transport.requests != 1 {
+		t.Errorf("fetched %d pages, want 1", transport.requests)
+	}
+}
+
+func TestListSecrets_InvalidInput(t *testing.T) {
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		vault   string
+		options ListOptions
+	}{
+		{"invalid vault name", "1vault", ListOptions{}},
+		{"short vault name", "ab", ListOptions{}},
+		{"http URL", "http://myvault.vault.azure.net", ListOptions{}},
+		{"wrong domain", "https://myvault.example.com", ListOptions{}},
+		{"negative max", "myvault", ListOptions{MaxResults: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := resolver.ListSecrets(context.Background(), tt.vault, tt.options); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+	if len(resolver.clients) != 0 {
+		t.Errorf("invalid input should not create clients, got %d", len(resolver.clients))
+	}
+}
@@ -0,0 +1,111 @@
+package keyvault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// DefaultLocalSecretsPath is the conventional location of a developer
+// secrets file, relative to the project root.
+const DefaultLocalSecretsPath = ".azure/dev-secrets.json"
+
+// ErrLocalSecret is reported as a KeyVaultResolutionWarning for every
+// reference resolved from ResolveEnvironmentOptions.LocalSecretsPath, so
+// callers can warn that the value did not come from Key Vault.
+var ErrLocalSecret = errors.New("value resolved from local secrets file, not Key Vault")
+
+// ErrLocalSecretNotFound is returned for a reference missing from the local
+// secrets file when ResolveEnvironmentOptions.LocalSecretsOnly is set.
+var ErrLocalSecretNotFound = errors.New("secret not found in local secrets file")
+
+// localSecrets maps secret names, optionally qualified as "vault/secret",
+// to development values.
+type localSecrets struct {
+	path   string
+	values map[string]string
+}
+
+// loadLocalSecrets reads a local secrets file: a JSON object mapping secret
+// names, or "<vault>/<secret>" for a specific vault, to values:
+//
+//	{"db-password": "local-dev", "myvault/api-key": "test-key"}
+//
+// Keys are matched case-insensitively, as Key Vault names are. The file is
+// rejected if it is writable by other users.
+func loadLocalSecrets(path string) (*localSecrets, error) {
+	if err := security.ValidatePath(path); err != nil {
+		return nil, fmt.Errorf("invalid local secrets path: %w", err)
+	}
+	if err := security.ValidateFilePermissions(path); err != nil {
+		if errors.Is(err, security.ErrInsecureFilePermissions) {
+			return nil, fmt.Errorf("local secrets file %s is writable by other users; run chmod 600 on it: %w", path, err)
+		}
+		return nil, fmt.Errorf("failed to check local secrets file: %w", err)
+	}
+
+	// #nosec G304 -- path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local secrets file: %w", err)
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse local secrets file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		values[strings.ToLower(strings.TrimSpace(k))] = v
+	}
+	return &localSecrets{path: path, values: values}, nil
+}
+
+// lookup returns the local value for reference, preferring a vault-qualified
+// entry over a bare secret name.
+func (l *localSecrets) lookup(reference string) (string, bool) {
+	vaultName, secretName, err := referenceTarget(reference)
+	if err != nil {
+		return "", false
+	}
+	if v, ok := l.values[strings.ToLower(vaultName+"/"+secretName)]; ok {
+		return v, true
+	}
+	v, ok := l.values[strings.ToLower(secretName)]
+	return v, ok
+}
+
+// referenceTarget extracts the vault and secret names from any supported
+// reference format.
+func referenceTarget(reference string) (vaultName, secretName string, err error) {
+	reference = normalizeKeyVaultReferenceValue(reference)
+
+	if matches := kvRefSecretURIPattern.FindStringSubmatch(reference); matches != nil {
+		parts := strings.Split(strings.TrimSpace(matches[1]), "/secrets/")
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid secret URI format")
+		}
+		_, vaultName, err = parseVault(parts[0])
+		if err != nil {
+			return "", "", err
+		}
+		secretName, _, _ = strings.Cut(parts[1], "/")
+		return vaultName, secretName, nil
+	}
+
+	if matches := kvRefVaultNamePattern.FindStringSubmatch(reference); matches != nil {
+		return matches[1], matches[2], nil
+	}
+
+	if strings.HasPrefix(reference, "akvs://") {
+		_, vaultName, secretName, _, err = parseAzdAkvsURI(reference)
+		return vaultName, secretName, err
+	}
+
+	return "", "", fmt.Errorf("invalid Key Vault reference format")
+}
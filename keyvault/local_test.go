@@ -0,0 +1,154 @@
+package keyvault
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeLocalSecrets(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "dev-secrets.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReferenceTarget(t *testing.T) {
+	tests := []struct {
+		reference string
+		vault     string
+		secret    string
+		wantErr   bool
+	}{
+		{"@Microsoft.KeyVault(SecretUri=https://myvault.vault.azure.net/secrets/db-password/abc123)", "myvault", "db-password", false},
+		{"@Microsoft.KeyVault(VaultName=myvault;SecretName=api-key)", "myvault", "api-key", false},
+		{"akvs://00000000-0000-0000-0000-000000000000/myvault/token", "myvault", "token", false},
+		{`"@Microsoft.KeyVault(VaultName=myvault;SecretName=quoted)"`, "myvault", "quoted", false},
+		{"@Microsoft.KeyVault(SecretUri=https://evil.example.com/secrets/x)", "", "", true},
+		{"plain-value", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			vault, secret, err := referenceTarget(tt.reference)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("referenceTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if vault != tt.vault || secret != tt.secret {
+				t.Errorf("referenceTarget() = %q, %q; want %q, %q", vault, secret, tt.vault, tt.secret)
+			}
+		})
+	}
+}
+
+func TestLocalSecretsLookup(t *testing.T) {
+	path := writeLocalSecrets(t, `{"DB-Password": "local-db", "othervault/api-key": "other-key", "api-key": "any-key"}`)
+	local, err := loadLocalSecrets(path)
+	if err != nil {
+		t.Fatalf("loadLocalSecrets() error = %v", err)
+	}
+
+	tests := []struct {
+		reference string
+		want      string
+		found     bool
+	}{
+		{"@Microsoft.KeyVault(VaultName=myvault;SecretName=db-password)", "local-db", true},
+		{"@Microsoft.KeyVault(VaultName=othervault;SecretName=api-key)", "other-key", true},
+		{"@Microsoft.KeyVault(VaultName=myvault;SecretName=api-key)", "any-key", true},
+		{"@Microsoft.KeyVault(VaultName=myvault;SecretName=missing)", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := local.lookup(tt.reference)
+		if ok != tt.found || got != tt.want {
+			t.Errorf("lookup(%q) = %q, %v; want %q, %v", tt.reference, got, ok, tt.want, tt.found)
+		}
+	}
+}
+
+func TestLoadLocalSecrets_Errors(t *testing.T) {
+	if _, err := loadLocalSecrets(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := loadLocalSecrets(writeLocalSecrets(t, `["not", "an", "object"]`)); err == nil {
+		t.Error("expected error for non-object JSON")
+	}
+	if _, err := loadLocalSecrets("../../etc/secrets.json"); err == nil {
+		t.Error("expected error for path traversal")
+	}
+
+	if runtime.GOOS != "windows" {
+		path := writeLocalSecrets(t, `{"a": "b"}`)
+		if err := os.Chmod(path, 0666); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadLocalSecrets(path); err == nil {
+			t.Error("expected error for world-writable secrets file")
+		}
+	}
+}
+
+func TestResolveEnvironmentVariables_LocalSecrets(t *testing.T) {
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeLocalSecrets(t, `{"db-password": "local-db"}`)
+
+	envVars := []string{
+		"PLAIN=value",
+		"DB_PASSWORD=@Microsoft.KeyVault(VaultName=myvault;SecretName=db-password)",
+	}
+	resolved, warnings, err := resolver.ResolveEnvironmentVariables(context.Background(), envVars, ResolveEnvironmentOptions{
+		LocalSecretsPath: path,
+	})
+	if err != nil {
+		t.Fatalf("ResolveEnvironmentVariables() error = %v", err)
+	}
+	if len(resolved) != 2 || resolved[0] != "PLAIN=value" || resolved[1] != "DB_PASSWORD=local-db" {
+		t.Errorf("resolved = %v", resolved)
+	}
+	if len(warnings) != 1 || warnings[0].Key != "DB_PASSWORD" || !errors.Is(warnings[0].Err, ErrLocalSecret) {
+		t.Errorf("warnings = %+v, want one ErrLocalSecret warning", warnings)
+	}
+}
+
+func TestResolveEnvironmentVariables_LocalSecretsOnly(t *testing.T) {
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeLocalSecrets(t, `{"db-password": "local-db"}`)
+	envVars := []string{"API_KEY=@Microsoft.KeyVault(VaultName=myvault;SecretName=api-key)"}
+
+	resolved, warnings, err := resolver.ResolveEnvironmentVariables(context.Background(), envVars, ResolveEnvironmentOptions{
+		LocalSecretsPath: path,
+		LocalSecretsOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("ResolveEnvironmentVariables() error = %v", err)
+	}
+	if len(resolved) != 1 || resolved[0] != envVars[0] {
+		t.Errorf("unresolved reference should be kept, got %v", resolved)
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0].Err, ErrLocalSecretNotFound) {
+		t.Errorf("warnings = %+v, want ErrLocalSecretNotFound", warnings)
+	}
+
+	_, _, err = resolver.ResolveEnvironmentVariables(context.Background(), envVars, ResolveEnvironmentOptions{
+		LocalSecretsPath: path,
+		LocalSecretsOnly: true,
+		StopOnError:      true,
+	})
+	if !errors.Is(err, ErrLocalSecretNotFound) {
+		t.Errorf("StopOnError error = %v, want ErrLocalSecretNotFound", err)
+	}
+
+	if _, _, err := resolver.ResolveEnvironmentVariables(context.Background(), envVars, ResolveEnvironmentOptions{LocalSecretsOnly: true}); err == nil {
+		t.Error("LocalSecretsOnly without a path should fail")
+	}
+}
@@ -0,0 +1,109 @@
+package keyvault
+
+import (
+	"context"
+	"time"
+)
+
+// ResolveOutcome is how a reference resolution reported to ResolveHooks ended.
+type ResolveOutcome string
+
+const (
+	// ResolveOutcomeSuccess means the secret was read from Key Vault.
+	ResolveOutcomeSuccess ResolveOutcome = "success"
+	// ResolveOutcomeLocal means the secret came from the local secrets file
+	// (ResolveEnvironmentOptions.LocalSecretsPath).
+	ResolveOutcomeLocal ResolveOutcome = "local"
+	// ResolveOutcomeError means the reference was not resolved.
+	ResolveOutcomeError ResolveOutcome = "error"
+)
+
+// ResolveEvent describes one reference resolution. Secret values are never
+// included.
+type ResolveEvent struct {
+	// VaultName and SecretName identify the referenced secret. They are
+	// empty if the reference could not be parsed.
+	VaultName  string
+	SecretName string
+
+	// The fields below are set only for OnResolveEnd.
+
+	// Duration is the time spent resolving the reference.
+	Duration time.Duration
+	Outcome  ResolveOutcome
+	// ClientReused reports whether the resolver reused its existing client
+	// (and the tokens its credential cached) for the vault instead of
+	// creating a new one. It is false for values from the local secrets
+	// file. Resolved secret values are not cached, so every Key Vault
+	// resolution still reads the secret from the vault.
+	ClientReused bool
+	// Err, Category, and HTTPStatus describe a failed resolution, as in
+	// KeyVaultResolutionWarning.
+	Err        error
+	Category   WarningCategory
+	HTTPStatus int
+}
+
+// ResolveHooks are callbacks invoked around every reference resolution,
+// including each reference resolved by ResolveEnvironmentVariables, so
+// callers can record Key Vault latency and client reuse in their own
+// telemetry. Either hook may be nil. Hooks run synchronously on the
+// resolving goroutine and must be safe for concurrent use.
+//
+// Example:
+//
+//	resolver.SetResolveHooks(keyvault.ResolveHooks{
+//	    OnResolveEnd: func(ctx context.Context, e keyvault.ResolveEvent) {
+//	        metrics.RecordDuration("keyvault.resolve", e.Duration, "outcome", string(e.Outcome))
+//	    },
+//	})
+type ResolveHooks struct {
+	OnResolveStart func(ctx context.Context, event ResolveEvent)
+	OnResolveEnd   func(ctx context.Context, event ResolveEvent)
+}
+
+// SetResolveHooks installs hooks for subsequent resolutions, replacing any
+// set before. Pass a zero ResolveHooks to remove them.
+func (r *KeyVaultResolver) SetResolveHooks(hooks ResolveHooks) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = hooks
+}
+
+// resolveInfo collects details about a resolution for ResolveHooks.
+type resolveInfo struct {
+	clientReused bool
+}
+
+// startResolve reports the start of resolving reference and returns a
+// function that reports its end.
+func (r *KeyVaultResolver) startResolve(ctx context.Context, reference string) func(outcome ResolveOutcome, clientReused bool, err error) {
+	r.mu.RLock()
+	hooks := r.hooks
+	r.mu.RUnlock()
+	if hooks.OnResolveStart == nil && hooks.OnResolveEnd == nil {
+		return func(ResolveOutcome, bool, error) {}
+	}
+
+	var event ResolveEvent
+	event.VaultName, event.SecretName, _ = referenceTarget(reference)
+	if hooks.OnResolveStart != nil {
+		hooks.OnResolveStart(ctx, event)
+	}
+
+	start := time.Now()
+	return func(outcome ResolveOutcome, clientReused bool, err error) {
+		if hooks.OnResolveEnd == nil {
+			return
+		}
+		event.Duration = time.Since(start)
+		event.Outcome = outcome
+		event.ClientReused = clientReused
+		if err != nil {
+			event.Outcome = ResolveOutcomeError
+			event.Err = err
+			event.Category, event.HTTPStatus = categorizeError(err)
+		}
+		hooks.OnResolveEnd(ctx, event)
+	}
+}
@@ -0,0 +1,199 @@
+package keyvault
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// fakeSecretTransport answers get-secret requests with a fixed status and
+// body, after the challenge azsecrets expects.
+type fakeSecretTransport struct {
+	status int
+	body   string
+}
+
+func (f *fakeSecretTransport) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}
+		resp.Header.Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/tenant", resource="https://vault.azure.net"`)
+		return resp, nil
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{StatusCode: f.status, Header: header, Body: io.NopCloser(strings.NewReader(f.body)), Request: req}, nil
+}
+
+// hookRecorder collects the events passed to ResolveHooks.
+type hookRecorder struct {
+	mu     sync.Mutex
+	starts []ResolveEvent
+	ends   []ResolveEvent
+}
+
+func (h *hookRecorder) hooks() ResolveHooks {
+	return ResolveHooks{
+		OnResolveStart: func(_ context.Context, e ResolveEvent) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.starts = append(h.starts, e)
+		},
+		OnResolveEnd: func(_ context.Context, e ResolveEvent) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.ends = append(h.ends, e)
+		},
+	}
+}
+
+func newFakeSecretResolver(t *testing.T, transport *fakeSecretTransport) *KeyVaultResolver {
+	t.Helper()
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vaultURL := "https://myvault.vault.azure.net"
+	client, err := azsecrets.NewClient(vaultURL, staticTestCredential{}, &azsecrets.ClientOptions{
+		ClientOptions: azcore.ClientOptions{Transport: transport},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver.clients[vaultURL] = client
+	return resolver
+}
+
+func TestResolveHooks_Success(t *testing.T) {
+	resolver := newFakeSecretResolver(t, &fakeSecretTransport{
+		status: http.StatusOK,
+		body:   `{"value": "s3cret", "id": "https://myvault.vault.azure.net/secrets/db/1"}`,
+	})
+	var rec hookRecorder
+	resolver.SetResolveHooks(rec.hooks())
+
+	value, err := resolver.ResolveReference(context.Background(), "@Microsoft.KeyVault(VaultName=myvault;SecretName=db)")
+	if err != nil || value != "s3cret" {
+		t.Fatalf("ResolveReference() = %q, %v", value, err)
+	}
+
+	if len(rec.starts) != 1 || rec.starts[0].VaultName != "myvault" || rec.starts[0].SecretName != "db" {
+		t.Errorf("start events = %+v", rec.starts)
+	}
+	if len(rec.ends) != 1 {
+		t.Fatalf("got %d end events, want 1", len(rec.ends))
+	}
+	end := rec.ends[0]
+	if end.Outcome != ResolveOutcomeSuccess || !end.ClientReused || end.Err != nil || end.VaultName != "myvault" || end.Duration < 0 {
+		t.Errorf("end event = %+v", end)
+	}
+}
+
+func TestResolveHooks_Error(t *testing.T) {
+	resolver := newFakeSecretResolver(t, &fakeSecretTransport{
+		status: http.StatusNotFound,
+		body:   `{"error": {"code": "SecretNotFound", "message": "not found"}}`,
+	})
+	var rec hookRecorder
+	resolver.SetResolveHooks(rec.hooks())
+
+	if _, err := resolver.ResolveReference(context.Background(), "akvs://guid/myvault/missing"); err == nil {
+		t.Fatal("ResolveReference() error = nil, want not found")
+	}
+	if len(rec.ends) != 1 {
+		t.Fatalf("got %d end events, want 1", len(rec.ends))
+	}
+	end := rec.ends[0]
+	if end.Outcome != ResolveOutcomeError || end.Category != WarningNotFound || end.HTTPStatus != http.StatusNotFound || end.Err == nil {
+		t.Errorf("end event = %+v", end)
+	}
+
+	rec.ends = nil
+	if _, err := resolver.ResolveReference(context.Background(), "not-a-reference"); err == nil {
+		t.Fatal("ResolveReference() error = nil for invalid reference")
+	}
+	if len(rec.ends) != 1 || rec.ends[0].Outcome != ResolveOutcomeError || rec.ends[0].ClientReused || rec.ends[0].VaultName != "" {
+		t.Errorf("end events for invalid reference = %+v", rec.ends)
+	}
+}
+
+func TestResolveHooks_EnvironmentVariables(t *testing.T) {
+	resolver := newFakeSecretResolver(t, &fakeSecretTransport{
+		status: http.StatusOK,
+		body:   `{"value": "from-vault", "id": "https://myvault.vault.azure.net/secrets/api-key/1"}`,
+	})
+	var rec hookRecorder
+	resolver.SetResolveHooks(rec.hooks())
+	path := writeLocalSecrets(t, `{"db-password": "local-db"}`)
+
+	envVars := []string{
+		"PLAIN=value",
+		"DB_PASSWORD=@Microsoft.KeyVault(VaultName=myvault;SecretName=db-password)",
+		"API_KEY=@Microsoft.KeyVault(VaultName=myvault;SecretName=api-key)",
+	}
+	if _, _, err := resolver.ResolveEnvironmentVariables(context.Background(), envVars, ResolveEnvironmentOptions{LocalSecretsPath: path}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.starts) != 2 || len(rec.ends) != 2 {
+		t.Fatalf("got %d start and %d end events, want 2 each", len(rec.starts), len(rec.ends))
+	}
+	if e := rec.ends[0]; e.SecretName != "db-password" || e.Outcome != ResolveOutcomeLocal || e.ClientReused {
+		t.Errorf("local end event = %+v", e)
+	}
+	if e := rec.ends[1]; e.SecretName != "api-key" || e.Outcome != ResolveOutcomeSuccess {
+		t.Errorf("vault end event = %+v", e)
+	}
+
+	rec.ends = nil
+	_, _, err := resolver.ResolveEnvironmentVariables(context.Background(), envVars[2:], ResolveEnvironmentOptions{LocalSecretsPath: path, LocalSecretsOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.ends) != 1 || rec.ends[0].Outcome != ResolveOutcomeError || rec.ends[0].Category != WarningNotFound {
+		t.Errorf("local-only miss end events = %+v", rec.ends)
+	}
+}
+
+func TestSetResolveHooks_Remove(t *testing.T) {
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec hookRecorder
+	resolver.SetResolveHooks(rec.hooks())
+	resolver.SetResolveHooks(ResolveHooks{})
+
+	_, _ = resolver.ResolveReference(context.Background(), "not-a-reference")
+	if len(rec.starts) != 0 || len(rec.ends) != 0 {
+		t.Errorf("removed hooks were called: %+v %+v", rec.starts, rec.ends)
+	}
+
+	// A single hook may be set on its own.
+	calls := 0
+	resolver.SetResolveHooks(ResolveHooks{OnResolveEnd: func(context.Context, ResolveEvent) { calls++ }})
+	_, _ = resolver.ResolveReference(context.Background(), "not-a-reference")
+	if calls != 1 {
+		t.Errorf("OnResolveEnd calls = %d, want 1", calls)
+	}
+}
+
+func TestGetCachedClient(t *testing.T) {
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const vaultURL = "https://myvault.vault.azure.net"
+
+	if _, cached, err := resolver.getCachedClient(vaultURL); err != nil || cached {
+		t.Errorf("first getCachedClient() cached = %v, err = %v, want false", cached, err)
+	}
+	if _, cached, err := resolver.getCachedClient(vaultURL); err != nil || !cached {
+		t.Errorf("second getCachedClient() cached = %v, err = %v, want true", cached, err)
+	}
+}
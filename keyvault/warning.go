@@ -0,0 +1,150 @@
+package keyvault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// WarningCategory classifies why a Key Vault reference was not resolved.
+type WarningCategory string
+
+const (
+	// WarningNotFound means the secret (or the requested version) does not
+	// exist, in Key Vault or in the local secrets file.
+	WarningNotFound WarningCategory = "NotFound"
+	// WarningForbidden means the identity may not read the secret.
+	WarningForbidden WarningCategory = "Forbidden"
+	// WarningUnauthenticated means no credential could sign in.
+	WarningUnauthenticated WarningCategory = "Unauthenticated"
+	// WarningThrottled means Key Vault rejected the request with HTTP 429.
+	WarningThrottled WarningCategory = "Throttled"
+	// WarningNetwork means Key Vault could not be reached or did not answer.
+	WarningNetwork WarningCategory = "Network"
+	// WarningLocalSecret means the value came from the local secrets file
+	// (ErrLocalSecret) rather than Key Vault.
+	WarningLocalSecret WarningCategory = "LocalSecret"
+	// WarningOther covers invalid references and unrecognized errors.
+	WarningOther WarningCategory = "Other"
+)
+
+// NewResolutionWarning builds a warning for the environment variable key
+// whose Key Vault reference failed with err. The vault and secret names
+// are parsed from reference, and the category and HTTP status are taken
+// from err. Custom env.Resolver implementations can use it to report
+// warnings the same way KeyVaultResolver does.
+func NewResolutionWarning(key, reference string, err error) KeyVaultResolutionWarning {
+	w := KeyVaultResolutionWarning{Key: key, Err: err}
+	w.VaultName, w.SecretName, _ = referenceTarget(reference)
+	w.Category, w.HTTPStatus = categorizeError(err)
+	return w
+}
+
+// String returns a one-line message naming the variable, the vault and
+// secret, and what to do about the failure. Secret values are never
+// included, and for categorized failures neither is the underlying error,
+// whose text can be long and include request details.
+//
+// Example:
+//
+//	for _, w := range warnings {
+//	    cliout.Warning("%s", w)
+//	}
+//	// DB_PASSWORD: secret db-password not found in vault myvault (HTTP 404); check the secret name and that it is enabled
+func (w KeyVaultResolutionWarning) String() string {
+	target := "the Key Vault reference"
+	switch {
+	case w.SecretName != "" && w.VaultName != "":
+		target = fmt.Sprintf("secret %s in vault %s", w.SecretName, w.VaultName)
+	case w.SecretName != "":
+		target = "secret " + w.SecretName
+	}
+
+	var msg, hint string
+	switch w.Category {
+	case WarningNotFound:
+		if w.HTTPStatus == 0 {
+			msg, hint = fmt.Sprintf("%s not found in the local secrets file", target), "add it to the file or resolve from Key Vault"
+		} else {
+			msg, hint = fmt.Sprintf("%s not found", target), "check the secret name and that it is enabled"
+		}
+	case WarningForbidden:
+		msg, hint = fmt.Sprintf("access denied to %s", target), "grant the signed-in identity the Key Vault Secrets User role or a get-secret access policy"
+	case WarningUnauthenticated:
+		msg, hint = fmt.Sprintf("could not authenticate to read %s", target), "sign in with 'azd auth login' or 'az login'"
+	case WarningThrottled:
+		msg, hint = fmt.Sprintf("Key Vault throttled the request for %s", target), "retry later or resolve fewer secrets at once"
+	case WarningNetwork:
+		msg, hint = fmt.Sprintf("could not reach Key Vault for %s", target), "check network access and the vault's firewall settings"
+	case WarningLocalSecret:
+		msg = fmt.Sprintf("%s was resolved from the local secrets file, not Key Vault", target)
+	default:
+		msg = fmt.Sprintf("failed to resolve %s", target)
+		if w.Err != nil {
+			msg += ": " + w.Err.Error()
+		}
+	}
+
+	if w.HTTPStatus != 0 {
+		msg += fmt.Sprintf(" (HTTP %d)", w.HTTPStatus)
+	}
+	if hint != "" {
+		msg += "; " + hint
+	}
+	if w.Key == "" {
+		return msg
+	}
+	return w.Key + ": " + msg
+}
+
+// categorizeError classifies a resolution error and extracts the HTTP
+// status of a Key Vault response error.
+func categorizeError(err error) (WarningCategory, int) {
+	if err == nil {
+		return WarningOther, 0
+	}
+	switch {
+	case errors.Is(err, ErrLocalSecret):
+		return WarningLocalSecret, 0
+	case errors.Is(err, ErrLocalSecretNotFound):
+		return WarningNotFound, 0
+	}
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		status := respErr.StatusCode
+		switch {
+		case status == http.StatusNotFound:
+			return WarningNotFound, status
+		case status == http.StatusForbidden:
+			return WarningForbidden, status
+		case status == http.StatusUnauthorized:
+			return WarningUnauthenticated, status
+		case status == http.StatusTooManyRequests:
+			return WarningThrottled, status
+		case status >= http.StatusInternalServerError:
+			return WarningNetwork, status
+		}
+		return WarningOther, status
+	}
+
+	// azidentity's credential-unavailable error is unexported; like the
+	// other credential errors it is marked non-retriable.
+	var authErr *azidentity.AuthenticationFailedError
+	var requiredErr *azidentity.AuthenticationRequiredError
+	var nonRetriable interface{ NonRetriable() }
+	if errors.As(err, &authErr) || errors.As(err, &requiredErr) || errors.As(err, &nonRetriable) {
+		return WarningUnauthenticated, 0
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return WarningNetwork, 0
+	}
+	return WarningOther, 0
+}
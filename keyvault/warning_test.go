@@ -0,0 +1,175 @@
+package keyvault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// statusVaultTransport answers every authenticated Key Vault request with
+// an error status.
+type statusVaultTransport struct {
+	status int
+}
+
+func (f statusVaultTransport) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}
+		resp.Header.Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/tenant", resource="https://vault.azure.net"`)
+		return resp, nil
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	body := fmt.Sprintf(`{"error":{"code":"Status%d","message":"request failed"}}`, f.status)
+	return &http.Response{StatusCode: f.status, Header: header, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+func TestNewResolutionWarning_Categories(t *testing.T) {
+	ref := "@Microsoft.KeyVault(VaultName=myvault;SecretName=db-password)"
+	tests := []struct {
+		name       string
+		err        error
+		category   WarningCategory
+		httpStatus int
+	}{
+		{"not found", &azcore.ResponseError{StatusCode: http.StatusNotFound}, WarningNotFound, 404},
+		{"forbidden", fmt.Errorf("failed to get secret: %w", &azcore.ResponseError{StatusCode: http.StatusForbidden}), WarningForbidden, 403},
+		{"unauthorized", &azcore.ResponseError{StatusCode: http.StatusUnauthorized}, WarningUnauthenticated, 401},
+		{"throttled", &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}, WarningThrottled, 429},
+		{"server error", &azcore.ResponseError{StatusCode: http.StatusServiceUnavailable}, WarningNetwork, 503},
+		{"bad request", &azcore.ResponseError{StatusCode: http.StatusBadRequest}, WarningOther, 400},
+		{"credential unavailable", azidentity.NewCredentialUnavailableError("no login"), WarningUnauthenticated, 0},
+		{"dns", &net.DNSError{Err: "no such host", Name: "myvault.vault.azure.net"}, WarningNetwork, 0},
+		{"deadline", context.DeadlineExceeded, WarningNetwork, 0},
+		{"local secret", fmt.Errorf("%w (dev-secrets.json)", ErrLocalSecret), WarningLocalSecret, 0},
+		{"local not found", ErrLocalSecretNotFound, WarningNotFound, 0},
+		{"other", errors.New("boom"), WarningOther, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewResolutionWarning("DB_PASSWORD", ref, tt.err)
+			if w.Category != tt.category || w.HTTPStatus != tt.httpStatus {
+				t.Errorf("got category %s, status %d; want %s, %d", w.Category, w.HTTPStatus, tt.category, tt.httpStatus)
+			}
+			if w.VaultName != "myvault" || w.SecretName != "db-password" || w.Key != "DB_PASSWORD" || w.Err != tt.err {
+				t.Errorf("unexpected identity fields: %+v", w)
+			}
+		})
+	}
+}
+
+func TestNewResolutionWarning_ReferenceFormats(t *testing.T) {
+	tests := []struct {
+		ref, vault, secret string
+	}{
+		{"@Microsoft.KeyVault(SecretUri=https://kv1.vault.azure.net/secrets/api-key/abc)", "kv1", "api-key"},
+		{"akvs://00000000-0000-0000-0000-000000000000/kv2/conn", "kv2", "conn"},
+		{"not-a-reference", "", ""},
+	}
+	for _, tt := range tests {
+		w := NewResolutionWarning("KEY", tt.ref, errors.New("x"))
+		if w.VaultName != tt.vault || w.SecretName != tt.secret {
+			t.Errorf("%q: got vault %q secret %q, want %q %q", tt.ref, w.VaultName, w.SecretName, tt.vault, tt.secret)
+		}
+	}
+}
+
+func TestKeyVaultResolutionWarning_String(t *testing.T) {
+	ref := "@Microsoft.KeyVault(VaultName=myvault;SecretName=db-password)"
+	tests := []struct {
+		name string
+		w    KeyVaultResolutionWarning
+		want []string
+	}{
+		{
+			name: "not found",
+			w:    NewResolutionWarning("DB_PASSWORD", ref, &azcore.ResponseError{StatusCode: 404}),
+			want: []string{"DB_PASSWORD: secret db-password in vault myvault not found (HTTP 404); check the secret name"},
+		},
+		{
+			name: "forbidden",
+			w:    NewResolutionWarning("DB_PASSWORD", ref, &azcore.ResponseError{StatusCode: 403}),
+			want: []string{"access denied", "(HTTP 403)", "Key Vault Secrets User"},
+		},
+		{
+			name: "unauthenticated",
+			w:    NewResolutionWarning("DB_PASSWORD", ref, azidentity.NewCredentialUnavailableError("no login")),
+			want: []string{"could not authenticate", "azd auth login"},
+		},
+		{
+			name: "local not found",
+			w:    NewResolutionWarning("DB_PASSWORD", ref, ErrLocalSecretNotFound),
+			want: []string{"not found in the local secrets file"},
+		},
+		{
+			name: "other includes the error",
+			w:    NewResolutionWarning("API", "bad", errors.New("invalid Key Vault reference format")),
+			want: []string{"API: failed to resolve the Key Vault reference: invalid Key Vault reference format"},
+		},
+		{
+			name: "literal struct",
+			w:    KeyVaultResolutionWarning{Key: "SECRET", Err: errors.New("resolve failed")},
+			want: []string{"SECRET: failed to resolve the Key Vault reference: resolve failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.w.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("String() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyVaultResolutionWarning_StringOmitsResponseDetails(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://myvault.vault.azure.net/secrets/db-password?api-version=7.5", nil)
+	err := &azcore.ResponseError{StatusCode: 403, ErrorCode: "Forbidden", RawResponse: &http.Response{
+		StatusCode: 403,
+		Request:    req,
+		Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"oid=1234 is not authorized"}}`)),
+	}}
+	got := NewResolutionWarning("DB_PASSWORD", "@Microsoft.KeyVault(VaultName=myvault;SecretName=db-password)", err).String()
+	if strings.Contains(got, "oid=1234") || strings.Contains(got, "api-version") {
+		t.Errorf("String() leaked response details: %q", got)
+	}
+}
+
+func TestResolveEnvironmentVariables_EnrichedWarnings(t *testing.T) {
+	resolver, err := NewKeyVaultResolverWithCredential(staticTestCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vaultURL := "https://myvault.vault.azure.net"
+	client, err := azsecrets.NewClient(vaultURL, staticTestCredential{}, &azsecrets.ClientOptions{
+		ClientOptions: azcore.ClientOptions{Transport: statusVaultTransport{status: http.StatusForbidden}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver.clients[vaultURL] = client
+
+	env := []string{"DB_PASSWORD=@Microsoft.KeyVault(VaultName=myvault;SecretName=db-password)"}
+	_, warnings, err := resolver.ResolveEnvironmentVariables(context.Background(), env, ResolveEnvironmentOptions{})
+	if err != nil {
+		t.Fatalf("ResolveEnvironmentVariables: %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1", len(warnings))
+	}
+	w := warnings[0]
+	if w.Category != WarningForbidden || w.HTTPStatus != http.StatusForbidden || w.VaultName != "myvault" || w.SecretName != "db-password" {
+		t.Errorf("unexpected warning: %+v", w)
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package lockfile provides cross-process named mutexes backed by lock files.
+//
+// A lock file holds a procutil.Handle for the owning process, so a lock left
+// behind by a crashed process is detected (even if its PID has been reused)
+// and reclaimed by the next caller:
+//
+//	lock, err := lockfile.Acquire("app-up:"+projectDir, lockfile.Options{Timeout: 30 * time.Second})
+//	if errors.Is(err, lockfile.ErrLocked) {
+//	    return fmt.Errorf("azd app up is already running for this project: %w", err)
+//	}
+//	if err != nil {
+//	    return err
+//	}
+//	defer lock.Release()
+package lockfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/jongio/azd-core/procutil"
+)
+
+var (
+	// ErrLocked is returned when the lock is held by another live process.
+	ErrLocked = errors.New("lock is held by another process")
+	// ErrNotHeld is returned by Release when the lock file no longer belongs
+	// to the caller, for example because it was reclaimed as stale.
+	ErrNotHeld = errors.New("lock is not held")
+)
+
+// Options configures Acquire.
+type Options struct {
+	Dir     string        // Directory for lock files (default: <user cache dir>/azd/locks)
+	Timeout time.Duration // How long to wait for a held lock; zero tries once
+	Stale   time.Duration // Age after which a lock is reclaimed even if its owner is alive; zero disables
+}
+
+var (
+	// pollInterval is how often Acquire retries a held lock.
+	pollInterval = 100 * time.Millisecond
+
+	// partialWriteGrace is how long an unreadable lock file is assumed to be
+	// mid-write by another process before it is treated as stale.
+	partialWriteGrace = 5 * time.Second
+
+	nameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9_\-.]`)
+)
+
+// owner is the content of a lock file.
+type owner struct {
+	procutil.Handle
+	AcquiredAt time.Time `json:"acquiredAt"`
+}
+
+func (o owner) equal(other owner) bool {
+	return o.PID == other.PID && o.StartTime.Equal(other.StartTime) && o.AcquiredAt.Equal(other.AcquiredAt)
+}
+
+// Lock is a held named lock. Release it when done.
+type Lock struct {
+	name  string
+	path  string
+	owner owner
+
+	mu       sync.Mutex
+	released bool
+}
+
+// Acquire takes the named lock for the current process. Names may contain
+// any characters; they are mapped to a safe file name in opts.Dir.
+//
+// If another live process holds the lock, Acquire polls until opts.Timeout
+// elapses and then returns an error wrapping ErrLocked. Locks whose owner
+// has exited, or that are older than opts.Stale, are reclaimed.
+func Acquire(name string, opts Options) (*Lock, error) {
+	if name == "" {
+		return nil, errors.New("lock name must not be empty")
+	}
+	dir := opts.Dir
+	if dir == "" {
+		dir = defaultDir()
+	}
+	if err := fileutil.EnsureDir(dir); err != nil {
+		return nil, err
+	}
+
+	self, err := procutil.Capture(os.Getpid())
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify current process: %w", err)
+	}
+
+	path := filepath.Join(dir, fileName(name))
+	deadline := time.Now().Add(opts.Timeout)
+	for {
+		o := owner{Handle: self, AcquiredAt: time.Now().UTC()}
+		created, err := create(path, o)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return &Lock{name: name, path: path, owner: o}, nil
+		}
+
+		holder, reclaimed, err := reclaimIfStale(path, opts.Stale)
+		if err != nil {
+			return nil, err
+		}
+		if reclaimed {
+			continue
+		}
+
+		if !time.Now().Before(deadline) {
+			if holder.PID > 0 {
+				return nil, fmt.Errorf("%w: %s (pid %d)", ErrLocked, name, holder.PID)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrLocked, name)
+		}
+		time.Sleep(min(pollInterval, time.Until(deadline)))
+	}
+}
+
+// Name returns the name the lock was acquired with.
+func (l *Lock) Name() string {
+	return l.name
+}
+
+// Path returns the lock file path.
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Release removes the lock file. It returns an error wrapping ErrNotHeld if
+// the lock was already released or the file now belongs to another process.
+func (l *Lock) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.released {
+		return fmt.Errorf("%w: %s already released", ErrNotHeld, l.name)
+	}
+	l.released = true
+
+	current, err := readOwner(l.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s was removed", ErrNotHeld, l.name)
+		}
+		return err
+	}
+	if !current.equal(l.owner) {
+		return fmt.Errorf("%w: %s was reclaimed by pid %d", ErrNotHeld, l.name, current.PID)
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove lock file: %w", err)
+	}
+	return nil
+}
+
+// create atomically creates the lock file at path holding o. It reports
+// false if the file already exists. The content is written to a temporary
+// file first and hard-linked into place so other processes never observe a
+// partially written lock; filesystems without hard links fall back to an
+// exclusive create.
+func create(path string, o owner) (bool, error) {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal lock owner: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return false, fmt.Errorf("failed to create lock file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		return false, fmt.Errorf("failed to write lock file: %w", errors.Join(werr, cerr))
+	}
+
+	linkErr := os.Link(tmpPath, path)
+	if linkErr == nil {
+		return true, nil
+	}
+	if errors.Is(linkErr, fs.ErrExist) {
+		return false, nil
+	}
+
+	// #nosec G304 -- path is built from a sanitized lock name
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileutil.FilePermission)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create lock file: %w", err)
+	}
+	_, werr = f.Write(data)
+	cerr = f.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(path)
+		return false, fmt.Errorf("failed to write lock file: %w", errors.Join(werr, cerr))
+	}
+	return true, nil
+}
+
+// reclaimIfStale removes the lock file at path if its owner is gone or it
+// is older than staleAfter. It returns the current owner and whether the
+// file was removed. Removal happens under a short-lived guard file and only
+// if the lock still has the owner that was judged stale, so two processes
+// reclaiming at once cannot delete a freshly acquired lock.
+func reclaimIfStale(path string, staleAfter time.Duration) (owner, bool, error) {
+	current, err := readOwner(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return owner{}, true, nil
+	}
+	if !isStale(path, current, err, staleAfter) {
+		return current, false, nil
+	}
+
+	guard := path + ".reclaim"
+	if !takeGuard(guard) {
+		return current, false, nil
+	}
+	defer func() { _ = os.Remove(guard) }()
+
+	again, err2 := readOwner(path)
+	if errors.Is(err2, fs.ErrNotExist) {
+		return owner{}, true, nil
+	}
+	if (err == nil) != (err2 == nil) || (err == nil && !again.equal(current)) {
+		// Someone else reclaimed and re-acquired the lock meanwhile.
+		return again, false, nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return current, false, fmt.Errorf("failed to remove stale lock file: %w", err)
+	}
+	return owner{}, true, nil
+}
+
+// isStale reports whether a lock with the given owner (or read error) may be
+// reclaimed.
+func isStale(path string, o owner, readErr error, staleAfter time.Duration) bool {
+	if readErr != nil {
+		info, err := os.Stat(path)
+		return err == nil && time.Since(info.ModTime()) > partialWriteGrace
+	}
+	if staleAfter > 0 && time.Since(o.AcquiredAt) > staleAfter {
+		return true
+	}
+	if o.StartTime.IsZero() {
+		return !procutil.IsProcessRunning(o.PID)
+	}
+	return !o.StillRunning()
+}
+
+// takeGuard creates the reclaim guard file, replacing a guard abandoned by a
+// process that crashed mid-reclaim.
+func takeGuard(guard string) bool {
+	for range 2 {
+		// #nosec G304 -- guard path is built from a sanitized lock name
+		f, err := os.OpenFile(guard, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileutil.FilePermission)
+		if err == nil {
+			_ = f.Close()
+			return true
+		}
+		info, statErr := os.Stat(guard)
+		if statErr != nil || time.Since(info.ModTime()) <= partialWriteGrace {
+			return false
+		}
+		_ = os.Remove(guard)
+	}
+	return false
+}
+
+// readOwner reads and parses the lock file at path.
+func readOwner(path string) (owner, error) {
+	// #nosec G304 -- path is built from a sanitized lock name
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return owner{}, err
+	}
+	var o owner
+	if err := json.Unmarshal(data, &o); err != nil {
+		return owner{}, fmt.Errorf("failed to parse lock file %s: %w", path, err)
+	}
+	if o.PID <= 0 {
+		return owner{}, fmt.Errorf("invalid pid %d in lock file %s", o.PID, path)
+	}
+	return o, nil
+}
+
+// fileName maps a lock name to a file name. Names that need sanitizing get
+// a hash suffix so that, for example, "a/b" and "a_b" do not collide.
+func fileName(name string) string {
+	safe := nameSanitizer.ReplaceAllString(name, "_")
+	if safe != name || len(safe) > 100 {
+		sum := sha256.Sum256([]byte(name))
+		if len(safe) > 100 {
+			safe = safe[:100]
+		}
+		safe += "-" + hex.EncodeToString(sum[:6])
+	}
+	return safe + ".lock"
+}
+
+// defaultDir returns the directory used when Options.Dir is empty.
+func defaultDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "azd", "locks")
+	}
+	return filepath.Join(os.TempDir(), "azd-locks")
+}
@@ -0,0 +1,277 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package lockfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/procutil"
+	"github.com/jongio/azd-core/testutil"
+)
+
+// TestLockfileHelperProcess is not a real test. Cross-process tests launch
+// the test binary with LOCKFILE_HELPER_DIR set to hold a lock in a child.
+func TestLockfileHelperProcess(t *testing.T) {
+	dir := os.Getenv("LOCKFILE_HELPER_DIR")
+	if dir == "" {
+		return
+	}
+	if _, err := Acquire("shared", Options{Dir: dir}); err != nil {
+		os.Exit(1)
+	}
+	os.Stdout.WriteString("locked\n")
+	time.Sleep(30 * time.Second)
+	os.Exit(0)
+}
+
+func writeOwner(t *testing.T, path string, o owner) {
+	t.Helper()
+	data, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireRelease(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := Acquire("deploy", Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if lock.Name() != "deploy" {
+		t.Errorf("Name = %q", lock.Name())
+	}
+	if lock.Path() != filepath.Join(dir, "deploy.lock") {
+		t.Errorf("Path = %q", lock.Path())
+	}
+
+	o, err := readOwner(lock.Path())
+	if err != nil {
+		t.Fatalf("readOwner: %v", err)
+	}
+	if o.PID != os.Getpid() || o.StartTime.IsZero() {
+		t.Errorf("owner = %+v, want current process", o)
+	}
+
+	if _, err := Acquire("deploy", Options{Dir: dir}); !errors.Is(err, ErrLocked) {
+		t.Errorf("second Acquire error = %v, want ErrLocked", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := os.Stat(lock.Path()); !os.IsNotExist(err) {
+		t.Errorf("lock file still exists after Release: %v", err)
+	}
+	if err := lock.Release(); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("second Release error = %v, want ErrNotHeld", err)
+	}
+
+	again, err := Acquire("deploy", Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Acquire after Release: %v", err)
+	}
+	_ = again.Release()
+}
+
+func TestAcquireWaitsForRelease(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := Acquire("wait", Options{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		_ = lock.Release()
+	}()
+
+	start := time.Now()
+	second, err := Acquire("wait", Options{Dir: dir, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer second.Release()
+	if time.Since(start) < 100*time.Millisecond {
+		t.Error("Acquire returned before the lock was released")
+	}
+}
+
+func TestAcquireTimeout(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := Acquire("busy", Options{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Release()
+
+	start := time.Now()
+	_, err = Acquire("busy", Options{Dir: dir, Timeout: 200 * time.Millisecond})
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("error = %v, want ErrLocked", err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Acquire gave up after %v, want at least the timeout", elapsed)
+	}
+}
+
+func TestAcquireReclaimsDeadOwner(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, fileName("stale"))
+	writeOwner(t, path, owner{
+		Handle:     procutil.Handle{PID: testutil.DeadPID(t), StartTime: time.Now().Add(-time.Hour)},
+		AcquiredAt: time.Now().Add(-time.Hour),
+	})
+
+	lock, err := Acquire("stale", Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer lock.Release()
+}
+
+func TestAcquireReclaimsReusedPID(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, fileName("reused"))
+	// The current process is alive, but with a different start time the
+	// handle refers to an earlier process that had the same PID.
+	writeOwner(t, path, owner{
+		Handle:     procutil.Handle{PID: os.Getpid(), StartTime: time.Unix(1, 0)},
+		AcquiredAt: time.Now(),
+	})
+
+	lock, err := Acquire("reused", Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer lock.Release()
+}
+
+func TestAcquireStaleAge(t *testing.T) {
+	dir := t.TempDir()
+	self, err := procutil.Capture(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, fileName("old"))
+	writeOwner(t, path, owner{Handle: self, AcquiredAt: time.Now().Add(-time.Hour)})
+
+	if _, err := Acquire("old", Options{Dir: dir}); !errors.Is(err, ErrLocked) {
+		t.Fatalf("without Stale, error = %v, want ErrLocked", err)
+	}
+	lock, err := Acquire("old", Options{Dir: dir, Stale: time.Minute})
+	if err != nil {
+		t.Fatalf("with Stale: %v", err)
+	}
+	defer lock.Release()
+}
+
+func TestAcquireCorruptLockFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, fileName("corrupt"))
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh unreadable file may be mid-write by another process.
+	if _, err := Acquire("corrupt", Options{Dir: dir}); !errors.Is(err, ErrLocked) {
+		t.Fatalf("fresh corrupt file: error = %v, want ErrLocked", err)
+	}
+
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := Acquire("corrupt", Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("old corrupt file: %v", err)
+	}
+	defer lock.Release()
+}
+
+func TestReleaseAfterReclaim(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := Acquire("taken", Options{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := owner{Handle: procutil.Handle{PID: os.Getpid(), StartTime: time.Now()}, AcquiredAt: time.Now()}
+	writeOwner(t, lock.Path(), other)
+
+	if err := lock.Release(); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Release error = %v, want ErrNotHeld", err)
+	}
+	if _, err := os.Stat(lock.Path()); err != nil {
+		t.Errorf("Release removed a lock it did not own: %v", err)
+	}
+}
+
+func TestAcquireCrossProcess(t *testing.T) {
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLockfileHelperProcess$")
+	cmd.Env = append(os.Environ(), "LOCKFILE_HELPER_DIR="+dir)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != "locked" {
+		t.Fatalf("helper did not acquire the lock: %q, %v", line, err)
+	}
+
+	_, err = Acquire("shared", Options{Dir: dir})
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("error = %v, want ErrLocked", err)
+	}
+
+	// The helper exits without releasing; its lock must be reclaimed.
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	lock, err := Acquire("shared", Options{Dir: dir, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Acquire after helper exit: %v", err)
+	}
+	defer lock.Release()
+}
+
+func TestFileName(t *testing.T) {
+	if got := fileName("deploy"); got != "deploy.lock" {
+		t.Errorf("fileName(deploy) = %q", got)
+	}
+	a, b := fileName("app-up:/src/a"), fileName("app-up_/src_a")
+	if a == b {
+		t.Errorf("sanitized names collide: %q", a)
+	}
+	for _, name := range []string{"app-up:/src/a", `C:\proj`, strings.Repeat("x", 300)} {
+		got := fileName(name)
+		if strings.ContainsAny(got, `/\:`) || len(got) > 120 {
+			t.Errorf("fileName(%q) = %q, want a short safe file name", name, got)
+		}
+	}
+}
+
+func TestAcquireEmptyName(t *testing.T) {
+	if _, err := Acquire("", Options{Dir: t.TempDir()}); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+// consoleMessageWidth is the column attributes start at, so messages of
+// different lengths still line up their key=value pairs.
+const consoleMessageWidth = 40
+
+// consoleTimeFormat is the timestamp layout written by ConsoleHandler.
+const consoleTimeFormat = "15:04:05"
+
+// ConsoleHandlerOptions configures NewConsoleHandler.
+type ConsoleHandlerOptions struct {
+	// Level is the minimum level written. Defaults to slog.LevelInfo.
+	Level slog.Leveler
+	// NoColor disables ANSI colors. Colors are also disabled after
+	// cliout.NoColor.
+	NoColor bool
+	// ReplaceAttr rewrites attributes before they are written, as in
+	// slog.HandlerOptions. It is not called for the time, level, or message.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+}
+
+// ConsoleHandler is a slog.Handler for people reading logs in a terminal.
+// Each record is one line with a short timestamp, a colored level, the
+// message, and dimmed key=value attributes aligned after it:
+//
+//	10:30:00 ℹ INFO  service started                          port=8080
+//	10:30:02 ⚠ WARN  health check failed                      service=api status=503
+//
+// Colors and symbols come from the active cliout theme, and symbols are
+// omitted on terminals without Unicode support. Use the text or JSON
+// handlers (SetupLogger) for log files and CI.
+// ConsoleHandler is safe for concurrent use.
+type ConsoleHandler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	opts   ConsoleHandlerOptions
+	attrs  string // preformatted attributes from WithAttrs
+	groups []string
+}
+
+// NewConsoleHandler creates a ConsoleHandler that writes to w. opts may be nil.
+func NewConsoleHandler(w io.Writer, opts *ConsoleHandlerOptions) *ConsoleHandler {
+	h := &ConsoleHandler{w: w, mu: &sync.Mutex{}}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+	return h
+}
+
+// SetupConsoleLogger configures the global logger to write ConsoleHandler
+// lines to stderr. It is the interactive counterpart of SetupLogger.
+// This function is safe for concurrent use.
+//
+// Example:
+//
+//	if term.IsTerminal(int(os.Stderr.Fd())) && !cliout.IsJSON() {
+//	    logutil.SetupConsoleLogger(debug)
+//	} else {
+//	    logutil.SetupLogger(debug, cliout.IsJSON())
+//	}
+func SetupConsoleLogger(debug bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if debug {
+		currentLevel = LevelDebug
+	} else {
+		currentLevel = LevelInfo
+	}
+	isStructured = false
+	isConsole = true
+	outputWriter = os.Stderr
+
+	setupLoggerInternal()
+}
+
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+func (h *ConsoleHandler) Handle(_ context.Context, r slog.Record) error {
+	color := !h.opts.NoColor && cliout.ColorEnabled()
+	paint := func(code, s string) string {
+		if !color || code == "" {
+			return s
+		}
+		return code + s + cliout.Reset
+	}
+
+	var b bytes.Buffer
+	if !r.Time.IsZero() {
+		b.WriteString(paint(cliout.Dim, r.Time.Format(consoleTimeFormat)))
+		b.WriteByte(' ')
+	}
+
+	levelColor, symbol, label := consoleLevel(r.Level)
+	if cliout.SupportsUnicode() {
+		b.WriteString(paint(levelColor, symbol))
+		b.WriteByte(' ')
+	}
+	b.WriteString(paint(levelColor, fmt.Sprintf("%-5s", label)))
+	b.WriteByte(' ')
+
+	var attrs strings.Builder
+	attrs.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		h.writeAttr(&attrs, h.groups, a, color)
+		return true
+	})
+
+	b.WriteString(r.Message)
+	if attrs.Len() > 0 {
+		if pad := consoleMessageWidth - utf8.RuneCountInString(r.Message); pad > 0 {
+			b.WriteString(strings.Repeat(" ", pad))
+		}
+		b.WriteString(attrs.String())
+	}
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(b.Bytes())
+	return err
+}
+
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	color := !h.opts.NoColor && cliout.ColorEnabled()
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		h.writeAttr(&b, h.groups, a, color)
+	}
+	next := *h
+	next.attrs = b.String()
+	return &next
+}
+
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := *h
+	next.groups = append(append([]string(nil), h.groups...), name)
+	return &next
+}
+
+// writeAttr appends " key=value" to b, flattening groups into dotted keys.
+func (h *ConsoleHandler) writeAttr(b *strings.Builder, groups []string, a slog.Attr, color bool) {
+	a.Value = a.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(append([]string(nil), groups...), a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			h.writeAttr(b, groups, ga, color)
+		}
+		return
+	}
+
+	key := a.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + key
+	}
+	b.WriteByte(' ')
+	if color {
+		b.WriteString(cliout.Dim + key + "=" + cliout.Reset)
+	} else {
+		b.WriteString(key + "=")
+	}
+	b.WriteString(consoleValue(a.Value))
+}
+
+// consoleLevel returns the theme color, symbol, and label for level.
+func consoleLevel(level slog.Level) (color, symbol, label string) {
+	t := cliout.CurrentTheme()
+	switch {
+	case level >= slog.LevelError:
+		return t.Error, t.ErrorSymbol, "ERROR"
+	case level >= slog.LevelWarn:
+		return t.Warning, t.WarningSymbol, "WARN"
+	case level >= slog.LevelInfo:
+		return t.Info, t.InfoSymbol, "INFO"
+	default:
+		return cliout.Gray, cliout.SymbolDot, "DEBUG"
+	}
+}
+
+// consoleValue formats v, quoting strings that would be ambiguous unquoted.
+func consoleValue(v slog.Value) string {
+	s := v.String()
+	if s == "" || strings.ContainsAny(s, " \t\n\r\"=") || !utf8.ValidString(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,143 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+func consoleRecord(level slog.Level, msg string, attrs ...slog.Attr) slog.Record {
+	r := slog.NewRecord(time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC), level, msg, 0)
+	r.AddAttrs(attrs...)
+	return r
+}
+
+func TestConsoleHandler_Format(t *testing.T) {
+	symbol := func(s string) string {
+		if cliout.SupportsUnicode() {
+			return s + " "
+		}
+		return ""
+	}
+	theme := cliout.CurrentTheme()
+
+	tests := []struct {
+		name   string
+		record slog.Record
+		want   string
+	}{
+		{
+			name:   "info with attrs",
+			record: consoleRecord(slog.LevelInfo, "service started", slog.Int("port", 8080)),
+			want:   "10:30:00 " + symbol(theme.InfoSymbol) + "INFO  service started" + strings.Repeat(" ", 25) + " port=8080\n",
+		},
+		{
+			name:   "warn quotes values",
+			record: consoleRecord(slog.LevelWarn, "slow", slog.String("reason", "disk busy"), slog.String("empty", "")),
+			want:   "10:30:00 " + symbol(theme.WarningSymbol) + "WARN  slow" + strings.Repeat(" ", 36) + ` reason="disk busy" empty=""` + "\n",
+		},
+		{
+			name:   "error without attrs",
+			record: consoleRecord(slog.LevelError, "failed"),
+			want:   "10:30:00 " + symbol(theme.ErrorSymbol) + "ERROR failed\n",
+		},
+		{
+			name:   "debug with group",
+			record: consoleRecord(slog.LevelDebug, "tick", slog.Group("req", slog.String("method", "GET"))),
+			want:   "10:30:00 " + symbol(cliout.SymbolDot) + "DEBUG tick" + strings.Repeat(" ", 36) + " req.method=GET\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := NewConsoleHandler(&buf, &ConsoleHandlerOptions{Level: slog.LevelDebug, NoColor: true})
+			if err := h.Handle(context.Background(), tt.record); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output =\n%q\nwant\n%q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleHandler_Color(t *testing.T) {
+	t.Cleanup(cliout.ForceColor)
+	theme := cliout.CurrentTheme()
+
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, nil)
+	_ = h.Handle(context.Background(), consoleRecord(slog.LevelError, "boom", slog.String("k", "v")))
+	if out := buf.String(); !strings.Contains(out, theme.Error+"ERROR"+cliout.Reset) || !strings.Contains(out, cliout.Dim+"k="+cliout.Reset+"v") {
+		t.Errorf("colored output = %q", out)
+	}
+
+	cliout.NoColor()
+	buf.Reset()
+	_ = h.Handle(context.Background(), consoleRecord(slog.LevelError, "boom"))
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("output after cliout.NoColor() has ANSI codes: %q", buf.String())
+	}
+}
+
+func TestConsoleHandler_LevelAndDerived(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewConsoleHandler(&buf, &ConsoleHandlerOptions{NoColor: true}))
+
+	logger.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("debug record written at info level: %q", buf.String())
+	}
+
+	logger.With("component", "health").WithGroup("check").With("type", "http").Info("done", "status", 200)
+	out := buf.String()
+	if !strings.HasSuffix(out, " component=health check.type=http check.status=200\n") {
+		t.Errorf("output = %q", out)
+	}
+
+	// Derived handlers must not affect the parent.
+	buf.Reset()
+	logger.Info("plain")
+	if strings.Contains(buf.String(), "component=") {
+		t.Errorf("parent handler picked up derived attrs: %q", buf.String())
+	}
+}
+
+func TestSetupConsoleLogger(t *testing.T) {
+	t.Cleanup(func() { SetupLogger(false, false) })
+
+	SetupConsoleLogger(true)
+	if !IsDebugEnabled() {
+		t.Error("SetupConsoleLogger(true) should enable debug")
+	}
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	cliout.NoColor()
+	t.Cleanup(cliout.ForceColor)
+
+	Debug("connecting", "password", "hunter2", "error", errors.New("failed"))
+	out := buf.String()
+	if !strings.Contains(out, "DEBUG connecting") {
+		t.Errorf("console output = %q", out)
+	}
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "level=") {
+		t.Errorf("console output not redacted or not console format: %q", out)
+	}
+
+	// SetupLoggerWithWriter switches back to the text handler.
+	buf.Reset()
+	SetupLoggerWithWriter(&buf, false, false)
+	Info("back")
+	if !strings.Contains(buf.String(), "level=INFO") {
+		t.Errorf("text output = %q", buf.String())
+	}
+}
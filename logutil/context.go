@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys used for trace correlation.
+const (
+	// TraceIDKey is the attribute key for the OpenTelemetry trace ID.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the attribute key for the OpenTelemetry span ID.
+	SpanIDKey = "span_id"
+)
+
+// loggerContextKey is the context key for a request-scoped logger.
+type loggerContextKey struct{}
+
+// WithContext returns a copy of ctx that carries the given logger.
+// A nil logger stores the global logger.
+//
+// Example:
+//
+//	ctx = logutil.WithContext(ctx, logutil.Logger().With("request_id", id))
+//	logutil.FromContext(ctx).Info("handling request")
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	if logger == nil {
+		logger = Logger()
+	}
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by WithContext, falling back
+// to the global logger. When ctx carries an active OpenTelemetry span, the
+// returned logger includes trace_id and span_id attributes so log lines can
+// be correlated with distributed traces.
+// This function is safe for concurrent use.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return Logger()
+	}
+
+	logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger)
+	if !ok || logger == nil {
+		logger = Logger()
+	}
+
+	if attrs := TraceAttrs(ctx); len(attrs) > 0 {
+		logger = logger.With(attrs...)
+	}
+	return logger
+}
+
+// TraceAttrs returns trace_id and span_id attributes for the span in ctx.
+// It returns nil when ctx has no valid span context.
+func TraceAttrs(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []any{
+		slog.String(TraceIDKey, sc.TraceID().String()),
+		slog.String(SpanIDKey, sc.SpanID().String()),
+	}
+}
+
+// WithContext returns a new Logger that includes trace correlation attributes
+// from ctx when a span is active. If no span is active, l is returned unchanged.
+func (l *ComponentLogger) WithContext(ctx context.Context) *ComponentLogger {
+	attrs := TraceAttrs(ctx)
+	if len(attrs) == 0 {
+		return l
+	}
+	return &ComponentLogger{
+		slogger:   l.slogger.With(attrs...),
+		component: l.component,
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func contextWithSpan(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatalf("TraceIDFromHex: %v", err)
+	}
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatalf("SpanIDFromHex: %v", err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc), sc
+}
+
+func TestFromContextFallsBackToGlobal(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+
+	FromContext(context.Background()).Info("fallback")
+
+	output := buf.String()
+	if !strings.Contains(output, "fallback") {
+		t.Errorf("expected message in output, got: %s", output)
+	}
+	if strings.Contains(output, TraceIDKey) {
+		t.Errorf("expected no trace_id without active span, got: %s", output)
+	}
+}
+
+func TestWithContextStoresLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+
+	ctx := WithContext(context.Background(), Logger().With("request_id", "abc"))
+	FromContext(ctx).Info("scoped")
+
+	output := buf.String()
+	if !strings.Contains(output, "request_id=abc") {
+		t.Errorf("expected request_id=abc in output, got: %s", output)
+	}
+}
+
+func TestWithContextNilLogger(t *testing.T) {
+	ctx := WithContext(context.Background(), nil)
+	if FromContext(ctx) == nil {
+		t.Fatal("FromContext returned nil")
+	}
+}
+
+func TestFromContextInjectsTraceIDs(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+
+	ctx, sc := contextWithSpan(t)
+	FromContext(ctx).Info("traced")
+
+	output := buf.String()
+	if !strings.Contains(output, "trace_id="+sc.TraceID().String()) {
+		t.Errorf("expected trace_id in output, got: %s", output)
+	}
+	if !strings.Contains(output, "span_id="+sc.SpanID().String()) {
+		t.Errorf("expected span_id in output, got: %s", output)
+	}
+}
+
+func TestTraceAttrsWithoutSpan(t *testing.T) {
+	if attrs := TraceAttrs(context.Background()); attrs != nil {
+		t.Errorf("expected nil attrs, got %v", attrs)
+	}
+}
+
+func TestComponentLoggerWithContext(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+
+	logger := NewLogger("comp")
+	if got := logger.WithContext(context.Background()); got != logger {
+		t.Error("expected same logger when no span is active")
+	}
+
+	ctx, sc := contextWithSpan(t)
+	traced := logger.WithContext(ctx)
+	if traced.Component() != "comp" {
+		t.Errorf("expected component 'comp', got %q", traced.Component())
+	}
+	traced.Info("test")
+
+	output := buf.String()
+	if !strings.Contains(output, "component=comp") {
+		t.Errorf("expected component=comp in output, got: %s", output)
+	}
+	if !strings.Contains(output, "trace_id="+sc.TraceID().String()) {
+		t.Errorf("expected trace_id in output, got: %s", output)
+	}
+}
@@ -33,4 +33,60 @@
 // Otherwise, logs use a human-readable text format:
 //
 //	time=2024-01-15T10:30:00Z level=INFO msg="operation completed" duration=1.5s
+//
+// # Console Output
+//
+// For interactive runs, SetupConsoleLogger writes aligned lines with colored
+// levels using the cliout theme. Keep the text or JSON formats for log files
+// and CI:
+//
+//	10:30:00 ℹ INFO  operation completed                      duration=1.5s
+//
+// NewConsoleHandler builds the same handler for a custom writer.
+//
+// # Context-Scoped Loggers
+//
+// WithContext stores a logger in a context and FromContext retrieves it,
+// falling back to the global logger. When the context carries an active
+// OpenTelemetry span, FromContext adds trace_id and span_id attributes:
+//
+//	ctx = logutil.WithContext(ctx, logutil.Logger().With("service", name))
+//	logutil.FromContext(ctx).Info("health check passed")
+//
+// # Redaction
+//
+// Sensitive attributes are masked before they reach the handler. Values for
+// keys such as token, secret, password, and connection_string are replaced,
+// and string values are scrubbed with security.RedactSecrets. Use
+// AddSensitiveKeys to register extra keys or SetRedaction(false) to disable.
+//
+// # Crash Context
+//
+// A RingBuffer retains the most recent records at every level, so recent
+// debug output can be attached to an error report:
+//
+//	recent := logutil.NewRingBuffer(200)
+//	logutil.SetRingBuffer(recent)
+//	...
+//	_ = recent.Dump(os.Stderr)
+//
+// # Sampling
+//
+// A Sampler rate-limits repeated messages such as a debug line per health
+// check: in each window the first N records of a message are logged, then
+// every Mth, and a "suppressed N similar messages" record reports the rest.
+// Rules can be set per message:
+//
+//	s := logutil.NewSampler(logutil.SamplingRule{First: 10, Thereafter: 100})
+//	s.SetRule("file changed", logutil.SamplingRule{First: 1, Window: 5 * time.Second})
+//	logutil.SetSampler(s)
+//	defer s.Flush(context.Background())
+//
+// # Console Mirroring
+//
+// In interactive runs, a ConsoleMirror also shows WARN and ERROR records to
+// the user via cliout.Warning and cliout.Error. It prints nothing in JSON
+// output mode and shows repeated messages once per dedup window:
+//
+//	logutil.SetConsoleMirror(logutil.NewConsoleMirror(0))
 package logutil
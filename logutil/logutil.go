@@ -36,6 +36,7 @@ var (
 	globalLogger *slog.Logger
 	currentLevel           = LevelInfo
 	isStructured           = false
+	isConsole              = false
 	outputWriter io.Writer = os.Stderr
 )
 
@@ -65,21 +66,43 @@ func SetupLogger(debug, structured bool) {
 	}
 
 	isStructured = structured
+	isConsole = false
 	outputWriter = os.Stderr
 
-	var handler slog.Handler
+	globalLogger = slog.New(newHandler(level))
+	slog.SetDefault(globalLogger)
+}
+
+// newHandler builds the text, JSON, or console handler for the current
+// configuration.
+// Caller must hold mu.Lock().
+func newHandler(level slog.Level) slog.Handler {
 	opts := &slog.HandlerOptions{
 		Level: level,
 	}
+	if redactionEnabled {
+		opts.ReplaceAttr = RedactAttr
+	}
 
-	if structured {
+	var handler slog.Handler
+	if isConsole {
+		handler = NewConsoleHandler(outputWriter, &ConsoleHandlerOptions{Level: level, ReplaceAttr: opts.ReplaceAttr})
+	} else if isStructured {
 		handler = slog.NewJSONHandler(outputWriter, opts)
 	} else {
 		handler = slog.NewTextHandler(outputWriter, opts)
 	}
 
-	globalLogger = slog.New(handler)
-	slog.SetDefault(globalLogger)
+	if sampler != nil {
+		handler = sampler.Handler(handler)
+	}
+	if ringBuffer != nil {
+		handler = &teeHandler{primary: handler, secondary: ringBuffer.Handler()}
+	}
+	if consoleMirror != nil {
+		handler = &teeHandler{primary: handler, secondary: consoleMirror.Handler()}
+	}
+	return handler
 }
 
 // SetOutput sets the output writer for the logger.
@@ -104,18 +127,7 @@ func setupLoggerInternal() {
 		level = slog.LevelInfo
 	}
 
-	var handler slog.Handler
-	opts := &slog.HandlerOptions{
-		Level: level,
-	}
-
-	if isStructured {
-		handler = slog.NewJSONHandler(outputWriter, opts)
-	} else {
-		handler = slog.NewTextHandler(outputWriter, opts)
-	}
-
-	globalLogger = slog.New(handler)
+	globalLogger = slog.New(newHandler(level))
 	slog.SetDefault(globalLogger)
 }
 
@@ -138,19 +150,9 @@ func SetupLoggerWithWriter(w io.Writer, debug, structured bool) {
 	}
 
 	isStructured = structured
+	isConsole = false
 
-	var handler slog.Handler
-	opts := &slog.HandlerOptions{
-		Level: level,
-	}
-
-	if structured {
-		handler = slog.NewJSONHandler(outputWriter, opts)
-	} else {
-		handler = slog.NewTextHandler(outputWriter, opts)
-	}
-
-	globalLogger = slog.New(handler)
+	globalLogger = slog.New(newHandler(level))
 	slog.SetDefault(globalLogger)
 }
 
@@ -165,13 +167,14 @@ func IsDebugEnabled() bool {
 }
 
 // Debug logs a debug message with optional key-value pairs.
-// Debug messages are only logged when debug mode is enabled.
+// Debug messages are only written to the output when debug mode is enabled,
+// but are always retained by an installed RingBuffer.
 //
 // Example:
 //
 //	logutil.Debug("processing request", "method", "GET", "path", "/api/users")
 func Debug(msg string, args ...any) {
-	if IsDebugEnabled() {
+	if IsDebugEnabled() || hasRingBuffer() {
 		globalLogger.Debug(msg, args...)
 	}
 }
@@ -237,32 +240,22 @@ func SetLevel(level Level) {
 
 	currentLevel = level
 
-	// Map our Level to slog.Level
-	var slogLevel slog.Level
+	globalLogger = slog.New(newHandler(toSlogLevel(level)))
+	slog.SetDefault(globalLogger)
+}
+
+// toSlogLevel maps a Level to the equivalent slog.Level.
+func toSlogLevel(level Level) slog.Level {
 	switch level {
 	case LevelDebug:
-		slogLevel = slog.LevelDebug
+		return slog.LevelDebug
 	case LevelWarn:
-		slogLevel = slog.LevelWarn
+		return slog.LevelWarn
 	case LevelError:
-		slogLevel = slog.LevelError
+		return slog.LevelError
 	default:
-		slogLevel = slog.LevelInfo
+		return slog.LevelInfo
 	}
-
-	var handler slog.Handler
-	opts := &slog.HandlerOptions{
-		Level: slogLevel,
-	}
-
-	if isStructured {
-		handler = slog.NewJSONHandler(outputWriter, opts)
-	} else {
-		handler = slog.NewTextHandler(outputWriter, opts)
-	}
-
-	globalLogger = slog.New(handler)
-	slog.SetDefault(globalLogger)
 }
 
 // Logger returns the underlying slog.Logger for advanced usage.
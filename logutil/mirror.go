@@ -0,0 +1,170 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/security"
+)
+
+// DefaultMirrorDedupWindow is how long a ConsoleMirror suppresses repeats of
+// the same message when NewConsoleMirror is called with a non-positive window.
+const DefaultMirrorDedupWindow = 30 * time.Second
+
+// consoleMirror is the mirror installed into the global logger by
+// SetConsoleMirror. Guarded by mu.
+var consoleMirror *ConsoleMirror
+
+// ConsoleMirror forwards WARN and ERROR records to the user through
+// cliout.Warning and cliout.Error, so problems logged during an interactive
+// run are not buried in structured logs. Nothing is printed in JSON output
+// mode, keeping stdout machine-readable. Identical messages repeated within
+// the dedup window are shown once.
+// ConsoleMirror is safe for concurrent use.
+type ConsoleMirror struct {
+	window     time.Duration
+	mu         sync.Mutex
+	lastShown  map[string]time.Time
+	suppressed int
+	now        func() time.Time
+}
+
+// NewConsoleMirror creates a ConsoleMirror that suppresses repeated messages
+// for window. A non-positive window uses DefaultMirrorDedupWindow.
+func NewConsoleMirror(window time.Duration) *ConsoleMirror {
+	if window <= 0 {
+		window = DefaultMirrorDedupWindow
+	}
+	return &ConsoleMirror{
+		window:    window,
+		lastShown: make(map[string]time.Time),
+		now:       time.Now,
+	}
+}
+
+// SetConsoleMirror installs m on the global logger so WARN and ERROR records
+// are also shown to the user. Passing nil removes the current mirror.
+// This function is safe for concurrent use.
+//
+// Example:
+//
+//	if !cliout.IsJSON() {
+//	    logutil.SetConsoleMirror(logutil.NewConsoleMirror(0))
+//	}
+func SetConsoleMirror(m *ConsoleMirror) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	consoleMirror = m
+	globalLogger = slog.New(newHandler(toSlogLevel(currentLevel)))
+	slog.SetDefault(globalLogger)
+}
+
+// Handler returns a slog.Handler that mirrors WARN and ERROR records to the
+// console. Use it to add mirroring to a custom handler chain.
+func (m *ConsoleMirror) Handler() slog.Handler {
+	return &mirrorHandler{mirror: m}
+}
+
+// Suppressed returns the number of repeated messages that were not shown.
+func (m *ConsoleMirror) Suppressed() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.suppressed
+}
+
+// show prints text at level unless the same text was shown within the window.
+func (m *ConsoleMirror) show(level slog.Level, text string) {
+	key := level.String() + "\x00" + text
+
+	m.mu.Lock()
+	now := m.now()
+	if last, ok := m.lastShown[key]; ok && now.Sub(last) < m.window {
+		m.suppressed++
+		m.mu.Unlock()
+		return
+	}
+	m.lastShown[key] = now
+	m.mu.Unlock()
+
+	if level >= slog.LevelError {
+		cliout.Error("%s", text)
+	} else {
+		cliout.Warning("%s", text)
+	}
+}
+
+// mirrorHandler is the slog.Handler that feeds a ConsoleMirror. Attributes
+// from WithAttrs are kept with group-qualified keys.
+type mirrorHandler struct {
+	mirror *ConsoleMirror
+	attrs  []slog.Attr
+	prefix string
+}
+
+func (h *mirrorHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn
+}
+
+func (h *mirrorHandler) Handle(_ context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn || cliout.IsJSON() {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString(security.RedactSecrets(r.Message))
+	for _, a := range h.attrs {
+		writeMirrorAttr(&b, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeMirrorAttr(&b, h.prefix, a)
+		return true
+	})
+	h.mirror.show(r.Level, b.String())
+	return nil
+}
+
+func (h *mirrorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := &mirrorHandler{mirror: h.mirror, prefix: h.prefix}
+	next.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	next.attrs = append(next.attrs, h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		next.attrs = append(next.attrs, a)
+	}
+	return next
+}
+
+func (h *mirrorHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &mirrorHandler{mirror: h.mirror, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// writeMirrorAttr appends a redacted " key=value" pair to b, flattening groups.
+func writeMirrorAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	a = RedactAttr(nil, a)
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			writeMirrorAttr(b, prefix, ga)
+		}
+		return
+	}
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	fmt.Fprintf(b, " %s%s=%v", prefix, a.Key, a.Value)
+}
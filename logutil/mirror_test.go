@@ -0,0 +1,132 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	fn()
+
+	_ = w.Close()
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	return buf.String()
+}
+
+func TestConsoleMirrorLevels(t *testing.T) {
+	logger := slog.New(NewConsoleMirror(0).Handler())
+
+	out := captureStdout(t, func() {
+		logger.Debug("debug message")
+		logger.Info("info message")
+		logger.Warn("disk almost full", "free", "2GB")
+		logger.Error("deploy failed", "service", "api")
+	})
+
+	if strings.Contains(out, "debug message") || strings.Contains(out, "info message") {
+		t.Errorf("records below WARN were mirrored: %q", out)
+	}
+	if !strings.Contains(out, "disk almost full free=2GB") {
+		t.Errorf("warning not mirrored with attrs: %q", out)
+	}
+	if !strings.Contains(out, "deploy failed service=api") {
+		t.Errorf("error not mirrored with attrs: %q", out)
+	}
+}
+
+func TestConsoleMirrorDedup(t *testing.T) {
+	m := NewConsoleMirror(time.Minute)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	logger := slog.New(m.Handler())
+
+	out := captureStdout(t, func() {
+		logger.Warn("retrying")
+		logger.Warn("retrying")
+		logger.Warn("retrying", "attempt", 2)
+	})
+	if n := strings.Count(out, "retrying"); n != 2 {
+		t.Errorf("expected 2 lines (distinct attrs shown once each), got %d: %q", n, out)
+	}
+	if m.Suppressed() != 1 {
+		t.Errorf("Suppressed() = %d, want 1", m.Suppressed())
+	}
+
+	now = now.Add(2 * time.Minute)
+	out = captureStdout(t, func() { logger.Warn("retrying") })
+	if !strings.Contains(out, "retrying") {
+		t.Error("message should be shown again after the dedup window")
+	}
+}
+
+func TestConsoleMirrorJSONMode(t *testing.T) {
+	if err := cliout.SetFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cliout.SetFormat("default") }()
+
+	logger := slog.New(NewConsoleMirror(0).Handler())
+	out := captureStdout(t, func() { logger.Error("should not print") })
+	if out != "" {
+		t.Errorf("mirror printed in JSON mode: %q", out)
+	}
+}
+
+func TestConsoleMirrorRedactsAndGroups(t *testing.T) {
+	logger := slog.New(NewConsoleMirror(0).Handler()).With("component", "auth").WithGroup("req")
+
+	out := captureStdout(t, func() {
+		logger.Warn("login slow", "password", "hunter2", "id", 7)
+	})
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("sensitive value leaked: %q", out)
+	}
+	if !strings.Contains(out, "component=auth") || !strings.Contains(out, "req.id=7") {
+		t.Errorf("attrs not rendered with groups: %q", out)
+	}
+}
+
+func TestSetConsoleMirror(t *testing.T) {
+	var logs bytes.Buffer
+	SetupLoggerWithWriter(&logs, false, false)
+	defer func() {
+		SetConsoleMirror(nil)
+		SetupLogger(false, false)
+	}()
+
+	SetConsoleMirror(NewConsoleMirror(0))
+	out := captureStdout(t, func() { Warn("config missing", "file", "azure.yaml") })
+	if !strings.Contains(out, "config missing file=azure.yaml") {
+		t.Errorf("global warning not mirrored: %q", out)
+	}
+	if !strings.Contains(logs.String(), "config missing") {
+		t.Errorf("warning missing from structured log: %q", logs.String())
+	}
+
+	SetConsoleMirror(nil)
+	out = captureStdout(t, func() { Warn("second warning") })
+	if out != "" {
+		t.Errorf("mirror still active after SetConsoleMirror(nil): %q", out)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+var (
+	// redactionEnabled controls whether sensitive attributes are masked.
+	// Guarded by mu.
+	redactionEnabled = true
+
+	// extraSensitiveKeys holds caller-registered keys (lowercased) to redact
+	// in addition to those recognized by security.IsSensitiveKey. Guarded by mu.
+	extraSensitiveKeys = map[string]bool{}
+)
+
+// SetRedaction enables or disables masking of sensitive log attributes.
+// Redaction is enabled by default. When enabled, values for attribute keys
+// such as token, secret, password, and connection_string are replaced with
+// security.RedactedPlaceholder, and string values are scrubbed with
+// security.RedactSecrets before reaching the handler.
+// This function is safe for concurrent use.
+func SetRedaction(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	redactionEnabled = enabled
+	globalLogger = slog.New(newHandler(toSlogLevel(currentLevel)))
+	slog.SetDefault(globalLogger)
+}
+
+// IsRedactionEnabled reports whether sensitive attribute masking is enabled.
+// This function is safe for concurrent use.
+func IsRedactionEnabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return redactionEnabled
+}
+
+// AddSensitiveKeys registers additional attribute keys whose values are
+// always redacted. Keys are matched case-insensitively.
+// This function is safe for concurrent use.
+func AddSensitiveKeys(keys ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, key := range keys {
+		extraSensitiveKeys[strings.ToLower(key)] = true
+	}
+}
+
+// RedactAttr masks an attribute value when its key is sensitive, and scrubs
+// embedded secrets from string and error values. It has the signature of
+// slog.HandlerOptions.ReplaceAttr so custom handlers can reuse it.
+// It runs while records are handled and therefore only takes mu.RLock().
+func RedactAttr(groups []string, a slog.Attr) slog.Attr {
+	mu.RLock()
+	extra := extraSensitiveKeys[strings.ToLower(a.Key)]
+	mu.RUnlock()
+	return redact(a, extra)
+}
+
+func redact(a slog.Attr, extra bool) slog.Attr {
+	if a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.SourceKey {
+		return a
+	}
+
+	if extra || security.IsSensitiveKey(a.Key) {
+		return slog.String(a.Key, security.RedactedPlaceholder)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if v := a.Value.String(); v != "" {
+			if redacted := security.RedactSecrets(v); redacted != v {
+				return slog.String(a.Key, redacted)
+			}
+		}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok && err != nil {
+			msg := err.Error()
+			if redacted := security.RedactSecrets(msg); redacted != msg {
+				return slog.String(a.Key, redacted)
+			}
+		}
+	}
+	return a
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-core/security"
+)
+
+func TestRedactionEnabledByDefault(t *testing.T) {
+	if !IsRedactionEnabled() {
+		t.Error("expected redaction to be enabled by default")
+	}
+}
+
+func TestRedactsSensitiveKeys(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+
+	Info("auth", "token", "abc123", "client_secret", "s3cret", "password", "hunter2", "connection_string", "Server=db", "service", "api")
+
+	output := buf.String()
+	for _, leaked := range []string{"abc123", "s3cret", "hunter2", "Server=db"} {
+		if strings.Contains(output, leaked) {
+			t.Errorf("expected %q to be redacted, got: %s", leaked, output)
+		}
+	}
+	if !strings.Contains(output, "token="+security.RedactedPlaceholder) {
+		t.Errorf("expected redacted token in output, got: %s", output)
+	}
+	if !strings.Contains(output, "service=api") {
+		t.Errorf("expected non-sensitive attribute preserved, got: %s", output)
+	}
+}
+
+func TestRedactsEmbeddedSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, true)
+
+	err := errors.New("connect failed: AccountName=a;AccountKey=topsecret")
+	Error("resolve failed", "error", err, "url", "https://a.blob.core.windows.net/c?sig=sigvalue")
+
+	output := buf.String()
+	if strings.Contains(output, "topsecret") {
+		t.Errorf("expected account key redacted in error, got: %s", output)
+	}
+	if strings.Contains(output, "sigvalue") {
+		t.Errorf("expected SAS signature redacted, got: %s", output)
+	}
+}
+
+func TestRedactsComponentLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+
+	NewLogger("kv").WithFields("secret", "value1").Info("resolved")
+
+	if strings.Contains(buf.String(), "value1") {
+		t.Errorf("expected secret field redacted, got: %s", buf.String())
+	}
+}
+
+func TestSetRedactionDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+	SetRedaction(false)
+	defer SetRedaction(true)
+
+	Info("raw", "token", "abc123")
+
+	if !strings.Contains(buf.String(), "token=abc123") {
+		t.Errorf("expected unredacted token when disabled, got: %s", buf.String())
+	}
+}
+
+func TestSetRedactionPreservesLevel(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+	SetLevel(LevelWarn)
+	SetRedaction(true)
+	defer SetLevel(LevelInfo)
+
+	Info("hidden")
+	if strings.Contains(buf.String(), "hidden") {
+		t.Errorf("expected info suppressed at warn level, got: %s", buf.String())
+	}
+}
+
+func TestAddSensitiveKeys(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+	AddSensitiveKeys("Subscription_ID")
+	defer func() {
+		mu.Lock()
+		delete(extraSensitiveKeys, "subscription_id")
+		mu.Unlock()
+	}()
+
+	Info("deploy", "subscription_id", "0000-1111")
+
+	if strings.Contains(buf.String(), "0000-1111") {
+		t.Errorf("expected custom key redacted, got: %s", buf.String())
+	}
+}
+
+func TestRedactAttrKeepsBuiltins(t *testing.T) {
+	a := slog.String(slog.LevelKey, "INFO")
+	if got := RedactAttr(nil, a); !got.Equal(a) {
+		t.Errorf("expected built-in attribute unchanged, got %v", got)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// DefaultRingBufferSize is the number of records retained when NewRingBuffer
+// is called with a non-positive size.
+const DefaultRingBufferSize = 200
+
+// ringBuffer is the buffer installed into the global logger by SetRingBuffer.
+// Guarded by mu.
+var ringBuffer *RingBuffer
+
+// RingBuffer retains the most recent log records at all levels in memory.
+// It lets an extension include recent debug context in a crash or error
+// report even when the logger is running at info level.
+// RingBuffer is safe for concurrent use.
+type RingBuffer struct {
+	mu      sync.Mutex
+	records []slog.Record
+	next    int
+	full    bool
+}
+
+// NewRingBuffer creates a RingBuffer holding up to size records.
+// A non-positive size uses DefaultRingBufferSize.
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		size = DefaultRingBufferSize
+	}
+	return &RingBuffer{records: make([]slog.Record, size)}
+}
+
+// SetRingBuffer installs b on the global logger so every record, including
+// debug records below the configured level, is also captured in b.
+// Passing nil removes the current buffer.
+// This function is safe for concurrent use.
+func SetRingBuffer(b *RingBuffer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	ringBuffer = b
+	globalLogger = slog.New(newHandler(toSlogLevel(currentLevel)))
+	slog.SetDefault(globalLogger)
+}
+
+// GetRingBuffer returns the buffer installed by SetRingBuffer, or nil.
+// This function is safe for concurrent use.
+func GetRingBuffer() *RingBuffer {
+	mu.RLock()
+	defer mu.RUnlock()
+	return ringBuffer
+}
+
+// hasRingBuffer reports whether a ring buffer is installed.
+func hasRingBuffer() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return ringBuffer != nil
+}
+
+// Handler returns a slog.Handler that records into b at every level.
+func (b *RingBuffer) Handler() slog.Handler {
+	return &ringHandler{buf: b}
+}
+
+// Len returns the number of records currently retained.
+func (b *RingBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.full {
+		return len(b.records)
+	}
+	return b.next
+}
+
+// Records returns a copy of the retained records, oldest first.
+func (b *RingBuffer) Records() []slog.Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out []slog.Record
+	if b.full {
+		out = make([]slog.Record, 0, len(b.records))
+		for _, r := range b.records[b.next:] {
+			out = append(out, r.Clone())
+		}
+	} else {
+		out = make([]slog.Record, 0, b.next)
+	}
+	for _, r := range b.records[:b.next] {
+		out = append(out, r.Clone())
+	}
+	return out
+}
+
+// Dump writes the retained records to w in text format, oldest first.
+// Sensitive attributes are redacted with RedactAttr.
+func (b *RingBuffer) Dump(w io.Writer) error {
+	handler := slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level:       slog.LevelDebug,
+		ReplaceAttr: RedactAttr,
+	})
+	for _, r := range b.Records() {
+		if err := handler.Handle(context.Background(), r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset discards all retained records.
+func (b *RingBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	clear(b.records)
+	b.next = 0
+	b.full = false
+}
+
+func (b *RingBuffer) add(r slog.Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.records[b.next] = r
+	b.next++
+	if b.next == len(b.records) {
+		b.next = 0
+		b.full = true
+	}
+}
+
+// ringHandler is the slog.Handler that feeds a RingBuffer. Attributes from
+// WithAttrs are flattened into each record, qualified by any open groups.
+type ringHandler struct {
+	buf    *RingBuffer
+	attrs  []slog.Attr
+	groups []string
+}
+
+func (h *ringHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *ringHandler) Handle(_ context.Context, r slog.Record) error {
+	rec := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	rec.AddAttrs(h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		rec.AddAttrs(h.qualify(a))
+		return true
+	})
+	h.buf.add(rec)
+	return nil
+}
+
+func (h *ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := &ringHandler{buf: h.buf, groups: h.groups}
+	next.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	next.attrs = append(next.attrs, h.attrs...)
+	for _, a := range attrs {
+		next.attrs = append(next.attrs, h.qualify(a))
+	}
+	return next
+}
+
+func (h *ringHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	groups = append(groups, name)
+	return &ringHandler{buf: h.buf, attrs: h.attrs, groups: groups}
+}
+
+// qualify prefixes a's key with the handler's open groups.
+func (h *ringHandler) qualify(a slog.Attr) slog.Attr {
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		a = slog.Attr{Key: h.groups[i], Value: slog.GroupValue(a)}
+	}
+	return a
+}
+
+// teeHandler sends records to the level-filtered primary handler and
+// unconditionally to a secondary handler (a ring buffer or console mirror),
+// which applies its own filtering.
+type teeHandler struct {
+	primary   slog.Handler
+	secondary slog.Handler
+}
+
+func (h *teeHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if h.primary.Enabled(ctx, r.Level) {
+		err = h.primary.Handle(ctx, r.Clone())
+	}
+	if secondaryErr := h.secondary.Handle(ctx, r); err == nil {
+		err = secondaryErr
+	}
+	return err
+}
+
+func (h *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &teeHandler{primary: h.primary.WithAttrs(attrs), secondary: h.secondary.WithAttrs(attrs)}
+}
+
+func (h *teeHandler) WithGroup(name string) slog.Handler {
+	return &teeHandler{primary: h.primary.WithGroup(name), secondary: h.secondary.WithGroup(name)}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRingBufferRetainsLastN(t *testing.T) {
+	b := NewRingBuffer(3)
+	logger := slog.New(b.Handler())
+
+	for i := range 5 {
+		logger.Info(fmt.Sprintf("msg-%d", i))
+	}
+
+	if b.Len() != 3 {
+		t.Fatalf("expected 3 records, got %d", b.Len())
+	}
+	records := b.Records()
+	for i, want := range []string{"msg-2", "msg-3", "msg-4"} {
+		if records[i].Message != want {
+			t.Errorf("record %d = %q, want %q", i, records[i].Message, want)
+		}
+	}
+}
+
+func TestRingBufferDefaultSize(t *testing.T) {
+	b := NewRingBuffer(0)
+	if len(b.records) != DefaultRingBufferSize {
+		t.Errorf("expected default size %d, got %d", DefaultRingBufferSize, len(b.records))
+	}
+}
+
+func TestRingBufferDump(t *testing.T) {
+	b := NewRingBuffer(10)
+	logger := slog.New(b.Handler()).With("component", "kv").WithGroup("req")
+	logger.Debug("fetching", "id", 7, "token", "abc123")
+
+	var out bytes.Buffer
+	if err := b.Dump(&out); err != nil {
+		t.Fatalf("Dump: %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"level=DEBUG", "msg=fetching", "component=kv", "req.id=7"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in dump, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "abc123") {
+		t.Errorf("expected token redacted in dump, got: %s", output)
+	}
+}
+
+func TestRingBufferReset(t *testing.T) {
+	b := NewRingBuffer(2)
+	logger := slog.New(b.Handler())
+	logger.Info("a")
+	logger.Info("b")
+	logger.Info("c")
+	b.Reset()
+
+	if b.Len() != 0 {
+		t.Errorf("expected empty buffer after Reset, got %d", b.Len())
+	}
+}
+
+func TestGetRingBuffer(t *testing.T) {
+	if GetRingBuffer() != nil {
+		t.Fatal("expected no ring buffer installed")
+	}
+	b := NewRingBuffer(10)
+	SetRingBuffer(b)
+	defer SetRingBuffer(nil)
+	if GetRingBuffer() != b {
+		t.Error("GetRingBuffer did not return the installed buffer")
+	}
+}
+
+func TestSetRingBufferCapturesDebugAtInfoLevel(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, false, false)
+
+	b := NewRingBuffer(10)
+	SetRingBuffer(b)
+	defer SetRingBuffer(nil)
+
+	Debug("hidden detail", "step", 1)
+	NewLogger("svc").Info("visible")
+
+	if strings.Contains(buf.String(), "hidden detail") {
+		t.Errorf("expected debug suppressed from output, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "visible") {
+		t.Errorf("expected info in output, got: %s", buf.String())
+	}
+
+	records := b.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 buffered records, got %d", len(records))
+	}
+	if records[0].Message != "hidden detail" || records[0].Level != slog.LevelDebug {
+		t.Errorf("unexpected first record: %v %q", records[0].Level, records[0].Message)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultSamplingWindow is the counting period of a SamplingRule with no
+// Window.
+const DefaultSamplingWindow = time.Second
+
+// sampler is the sampler installed into the global logger by SetSampler.
+// Guarded by mu.
+var sampler *Sampler
+
+// SamplingRule limits how often records with the same message are logged:
+// in each Window the First records are logged, then every Thereafter-th
+// one. A rule with neither First nor Thereafter set logs everything.
+type SamplingRule struct {
+	// First is the number of records logged at the start of each window.
+	First int
+	// Thereafter logs every Thereafter-th record after the first ones;
+	// 0 drops them all until the window ends.
+	Thereafter int
+	// Window is the counting period. Defaults to DefaultSamplingWindow.
+	Window time.Duration
+}
+
+// enabled reports whether r drops anything.
+func (r SamplingRule) enabled() bool {
+	return r.First > 0 || r.Thereafter > 0
+}
+
+// Sampler rate-limits high-frequency log lines, such as a debug line per
+// health check or per file event. Records are grouped by message: each
+// message is counted separately against its rule. When a message's window
+// ends with records dropped, a "suppressed N similar messages" record is
+// logged at the same level, with the message in a "message" attribute,
+// before the next record of that message, or by Flush.
+//
+// The default rule applies to DEBUG and INFO records; rules set for a
+// message with SetRule apply at every level. Sampler is safe for concurrent
+// use.
+type Sampler struct {
+	mu          sync.Mutex
+	defaultRule SamplingRule
+	rules       map[string]SamplingRule
+	counters    map[string]*sampleCounter
+	suppressed  int
+	now         func() time.Time
+}
+
+// sampleCounter tracks one message in the current window.
+type sampleCounter struct {
+	start      time.Time
+	seen       int
+	suppressed int
+	level      slog.Level
+	// next is the handler that last received the message, used by Flush.
+	next slog.Handler
+}
+
+// NewSampler creates a Sampler applying defaultRule to DEBUG and INFO
+// records. Pass a zero SamplingRule to sample only messages given a rule
+// with SetRule.
+//
+// Example:
+//
+//	s := logutil.NewSampler(logutil.SamplingRule{First: 10, Thereafter: 100})
+//	s.SetRule("file changed", logutil.SamplingRule{First: 1, Window: 5 * time.Second})
+//	logutil.SetSampler(s)
+//	defer s.Flush(context.Background())
+func NewSampler(defaultRule SamplingRule) *Sampler {
+	return &Sampler{
+		defaultRule: defaultRule,
+		rules:       make(map[string]SamplingRule),
+		counters:    make(map[string]*sampleCounter),
+		now:         time.Now,
+	}
+}
+
+// SetRule sets the rule for records whose message is msg, at any level.
+// A zero rule logs every such record.
+func (s *Sampler) SetRule(msg string, rule SamplingRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules[msg] = rule
+	delete(s.counters, msg)
+}
+
+// SetSampler installs s on the global logger, sampling the records written
+// to the log output. A RingBuffer and ConsoleMirror still receive every
+// record. Passing nil removes the current sampler.
+// This function is safe for concurrent use.
+func SetSampler(s *Sampler) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sampler = s
+	globalLogger = slog.New(newHandler(toSlogLevel(currentLevel)))
+	slog.SetDefault(globalLogger)
+}
+
+// Handler returns a slog.Handler that samples records before passing them
+// to next. Use it to add sampling to a custom handler chain.
+func (s *Sampler) Handler(next slog.Handler) slog.Handler {
+	return &samplingHandler{sampler: s, next: next}
+}
+
+// Suppressed returns the total number of records dropped.
+func (s *Sampler) Suppressed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.suppressed
+}
+
+// Flush logs a summary for every message with records dropped in its
+// current window. Call it before exiting so the last burst is reported.
+func (s *Sampler) Flush(ctx context.Context) {
+	s.mu.Lock()
+	type pending struct {
+		msg     string
+		counter sampleCounter
+	}
+	var summaries []pending
+	for msg, c := range s.counters {
+		if c.suppressed > 0 {
+			summaries = append(summaries, pending{msg, *c})
+			c.suppressed = 0
+		}
+	}
+	s.mu.Unlock()
+
+	for _, p := range summaries {
+		_ = p.counter.next.Handle(ctx, s.summaryRecord(p.msg, p.counter.level, p.counter.suppressed))
+	}
+}
+
+// sample counts a record and reports whether to log it, along with a
+// summary of the records dropped in the window that just ended, if any.
+func (s *Sampler) sample(r slog.Record, next slog.Handler) (keep bool, summary *slog.Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule, ok := s.rules[r.Message]
+	if !ok {
+		if r.Level >= slog.LevelWarn {
+			return true, nil
+		}
+		rule = s.defaultRule
+	}
+	if !rule.enabled() {
+		return true, nil
+	}
+	window := rule.Window
+	if window <= 0 {
+		window = DefaultSamplingWindow
+	}
+
+	now := s.now()
+	c := s.counters[r.Message]
+	if c == nil || now.Sub(c.start) >= window {
+		if c != nil && c.suppressed > 0 {
+			rec := s.summaryRecord(r.Message, c.level, c.suppressed)
+			summary = &rec
+		}
+		c = &sampleCounter{start: now}
+		s.counters[r.Message] = c
+	}
+	c.seen++
+	c.level = r.Level
+	c.next = next
+
+	switch {
+	case c.seen <= rule.First:
+		return true, summary
+	case rule.Thereafter > 0 && (c.seen-rule.First)%rule.Thereafter == 0:
+		return true, summary
+	default:
+		c.suppressed++
+		s.suppressed++
+		return false, summary
+	}
+}
+
+// summaryRecord reports n dropped records of msg.
+func (s *Sampler) summaryRecord(msg string, level slog.Level, n int) slog.Record {
+	r := slog.NewRecord(s.now(), level, fmt.Sprintf("suppressed %d similar messages", n), 0)
+	r.AddAttrs(slog.String("message", msg))
+	return r
+}
+
+// samplingHandler is the slog.Handler that applies a Sampler.
+type samplingHandler struct {
+	sampler *Sampler
+	next    slog.Handler
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	keep, summary := h.sampler.sample(r, h.next)
+	if summary != nil {
+		if err := h.next.Handle(ctx, *summary); err != nil {
+			return err
+		}
+	}
+	if !keep {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{sampler: h.sampler, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{sampler: h.sampler, next: h.next.WithGroup(name)}
+}
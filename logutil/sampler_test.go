@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestSampler returns a sampler on a fake clock and a JSON logger
+// writing through it.
+func newTestSampler(rule SamplingRule) (*Sampler, *slog.Logger, *bytes.Buffer, *time.Time) {
+	var buf bytes.Buffer
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := NewSampler(rule)
+	s.now = func() time.Time { return clock }
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return s, slog.New(s.Handler(handler)), &buf, &clock
+}
+
+func sampledMessages(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestSamplerFirstThenEveryNth(t *testing.T) {
+	s, logger, buf, _ := newTestSampler(SamplingRule{First: 3, Thereafter: 5})
+
+	for i := 1; i <= 20; i++ {
+		logger.Debug("health check passed", "n", i)
+	}
+
+	var kept []float64
+	for _, rec := range sampledMessages(t, buf) {
+		kept = append(kept, rec["n"].(float64))
+	}
+	want := []float64{1, 2, 3, 8, 13, 18}
+	if len(kept) != len(want) {
+		t.Fatalf("kept %v, want %v", kept, want)
+	}
+	for i := range want {
+		if kept[i] != want[i] {
+			t.Errorf("kept %v, want %v", kept, want)
+			break
+		}
+	}
+	if got := s.Suppressed(); got != 14 {
+		t.Errorf("Suppressed() = %d, want 14", got)
+	}
+}
+
+func TestSamplerSummaryOnNextWindow(t *testing.T) {
+	_, logger, buf, clock := newTestSampler(SamplingRule{First: 2, Window: time.Second})
+
+	for i := 0; i < 10; i++ {
+		logger.Debug("file changed")
+	}
+	logger.Debug("other message")
+	*clock = clock.Add(time.Second)
+	logger.Debug("file changed")
+
+	records := sampledMessages(t, buf)
+	var msgs []string
+	for _, rec := range records {
+		msgs = append(msgs, rec["msg"].(string))
+	}
+	want := []string{"file changed", "file changed", "other message", "suppressed 8 similar messages", "file changed"}
+	if strings.Join(msgs, "|") != strings.Join(want, "|") {
+		t.Fatalf("messages = %q, want %q", msgs, want)
+	}
+	summary := records[3]
+	if summary["message"] != "file changed" || summary["level"] != "DEBUG" {
+		t.Errorf("summary record = %v", summary)
+	}
+}
+
+func TestSamplerRules(t *testing.T) {
+	s, logger, buf, _ := newTestSampler(SamplingRule{})
+	s.SetRule("noisy warning", SamplingRule{First: 1})
+
+	for i := 0; i < 5; i++ {
+		logger.Info("unsampled")
+		logger.Warn("noisy warning")
+	}
+
+	counts := map[string]int{}
+	for _, rec := range sampledMessages(t, buf) {
+		counts[rec["msg"].(string)]++
+	}
+	if counts["unsampled"] != 5 || counts["noisy warning"] != 1 {
+		t.Errorf("counts = %v, want 5 unsampled and 1 noisy warning", counts)
+	}
+}
+
+func TestSamplerDefaultRuleSkipsWarnings(t *testing.T) {
+	_, logger, buf, _ := newTestSampler(SamplingRule{First: 1})
+
+	for i := 0; i < 3; i++ {
+		logger.Warn("disk almost full")
+		logger.Error("request failed")
+	}
+	if got := len(sampledMessages(t, buf)); got != 6 {
+		t.Errorf("logged %d records, want all 6 warnings and errors", got)
+	}
+}
+
+func TestSamplerFlush(t *testing.T) {
+	s, logger, buf, _ := newTestSampler(SamplingRule{First: 1})
+
+	svc := logger.With("service", "api")
+	for i := 0; i < 4; i++ {
+		svc.Info("port open")
+	}
+	s.Flush(context.Background())
+	s.Flush(context.Background()) // nothing new to report
+
+	records := sampledMessages(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2: %s", len(records), buf.String())
+	}
+	summary := records[1]
+	if summary["msg"] != "suppressed 3 similar messages" || summary["message"] != "port open" || summary["service"] != "api" || summary["level"] != "INFO" {
+		t.Errorf("summary record = %v", summary)
+	}
+}
+
+func TestSetSampler(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLoggerWithWriter(&buf, true, false)
+	defer SetupLoggerWithWriter(&buf, false, false)
+
+	ring := NewRingBuffer(50)
+	SetRingBuffer(ring)
+	defer SetRingBuffer(nil)
+	SetSampler(NewSampler(SamplingRule{First: 2}))
+	defer SetSampler(nil)
+
+	for i := 0; i < 10; i++ {
+		Debug("watch event")
+	}
+
+	if got := strings.Count(buf.String(), "watch event"); got != 2 {
+		t.Errorf("output has %d records, want 2:\n%s", got, buf.String())
+	}
+	if got := ring.Len(); got != 10 {
+		t.Errorf("ring buffer has %d records, want all 10", got)
+	}
+}
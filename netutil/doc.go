@@ -0,0 +1,25 @@
+// Package netutil provides local network diagnostics for Azure Developer CLI
+// extensions.
+//
+// Each check returns a Diagnostic describing what was tested, whether it
+// succeeded, how long it took, and, on failure, a suggested next step. The
+// results are JSON-serializable and can be rendered with Render, which
+// respects the cliout output format, making the package a building block for
+// "doctor"-style commands.
+//
+//   - ResolveHost resolves a host name and reports its addresses
+//   - CheckConnectivity performs an HTTP request through the configured proxy
+//   - DetectProxy reports proxy settings from HTTP_PROXY, HTTPS_PROXY and
+//     NO_PROXY, falling back to the Windows system proxy
+//
+// Example:
+//
+//	diags := []netutil.Diagnostic{
+//	    netutil.DetectProxy().Diagnostic(),
+//	    netutil.ResolveHost(ctx, "management.azure.com"),
+//	    netutil.CheckConnectivity(ctx, "https://management.azure.com"),
+//	}
+//	if err := netutil.Render(diags); err != nil {
+//	    return err
+//	}
+package netutil
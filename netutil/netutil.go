@@ -0,0 +1,176 @@
+package netutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/urlutil"
+)
+
+// DefaultTimeout bounds checks whose context has no deadline.
+const DefaultTimeout = 10 * time.Second
+
+// Check names used in Diagnostic.Check.
+const (
+	CheckTypeDNS          = "dns"
+	CheckTypeConnectivity = "connectivity"
+	CheckTypeProxy        = "proxy"
+)
+
+// Diagnostic is the structured result of a single network check.
+type Diagnostic struct {
+	// Check is the kind of check (CheckTypeDNS, CheckTypeConnectivity, CheckTypeProxy).
+	Check string `json:"check"`
+	// Target is the host, URL, or setting that was checked.
+	Target string `json:"target"`
+	// OK reports whether the check succeeded.
+	OK bool `json:"ok"`
+	// Duration is how long the check took.
+	Duration time.Duration `json:"duration"`
+	// Detail summarizes the outcome, e.g. resolved addresses or HTTP status.
+	Detail string `json:"detail,omitempty"`
+	// Addresses holds resolved IP addresses for DNS checks.
+	Addresses []string `json:"addresses,omitempty"`
+	// StatusCode is the HTTP status for connectivity checks.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Proxy is the proxy URL used for connectivity checks, if any.
+	Proxy string `json:"proxy,omitempty"`
+	// Error is the failure message, if any.
+	Error string `json:"error,omitempty"`
+	// Suggestion is an actionable next step for failed checks.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// resolver performs DNS lookups. Tests replace it.
+var resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+} = net.DefaultResolver
+
+// ResolveHost resolves host and reports its addresses and lookup time.
+func ResolveHost(ctx context.Context, host string) Diagnostic {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	d := Diagnostic{Check: CheckTypeDNS, Target: host}
+	start := time.Now()
+	addrs, err := resolver.LookupHost(ctx, host)
+	d.Duration = time.Since(start)
+	if err != nil {
+		d.Error = err.Error()
+		d.Suggestion = "Check your DNS settings and that the host name is spelled correctly"
+		return d
+	}
+
+	d.OK = true
+	d.Addresses = addrs
+	d.Detail = strings.Join(addrs, ", ")
+	return d
+}
+
+// CheckConnectivity sends a HEAD request to rawURL using the proxy settings
+// from the environment. Any HTTP response, including 4xx and 5xx, counts as
+// reachable; only transport failures are reported as errors. The Target and
+// Error fields carry the URL redacted with urlutil.RedactForLogging, since
+// URLs often include SAS tokens or keys.
+func CheckConnectivity(ctx context.Context, rawURL string) Diagnostic {
+	target := urlutil.RedactForLogging(rawURL)
+	d := Diagnostic{Check: CheckTypeConnectivity, Target: target}
+	if err := urlutil.Validate(rawURL); err != nil {
+		d.Error = redactURLError(err, rawURL, target)
+		return d
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, rawURL, nil)
+	if err != nil {
+		d.Error = redactURLError(err, rawURL, target)
+		return d
+	}
+	if proxyURL, err := http.ProxyFromEnvironment(req); err == nil && proxyURL != nil {
+		d.Proxy = proxyURL.Redacted()
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	d.Duration = time.Since(start)
+	if err != nil {
+		d.Error = redactURLError(err, rawURL, target)
+		d.Suggestion = connectivitySuggestion(d.Proxy)
+		return d
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	d.OK = true
+	d.StatusCode = resp.StatusCode
+	d.Detail = "HTTP " + resp.Status
+	return d
+}
+
+// redactURLError returns the text of err with rawURL replaced by its
+// redacted form.
+func redactURLError(err error, rawURL, redacted string) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redacted
+	}
+	msg := err.Error()
+	if rawURL != "" {
+		msg = strings.ReplaceAll(msg, rawURL, redacted)
+	}
+	return msg
+}
+
+// connectivitySuggestion returns a next step for a failed request.
+func connectivitySuggestion(proxy string) string {
+	if proxy != "" {
+		return fmt.Sprintf("Verify the proxy %s is reachable, or add the host to NO_PROXY", proxy)
+	}
+	return "Check your network connection and firewall; set HTTPS_PROXY if a proxy is required"
+}
+
+// withDefaultTimeout applies DefaultTimeout when ctx has no deadline.
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, DefaultTimeout)
+}
+
+// Render prints diagnostics in the current cliout format: a JSON array when
+// --output json is active, otherwise one status line per check.
+func Render(diags []Diagnostic) error {
+	return cliout.Print(diags, func() {
+		for _, d := range diags {
+			line := fmt.Sprintf("%-12s %s (%s)", d.Check, d.Target, d.Duration.Round(time.Millisecond))
+			switch {
+			case d.OK && d.Detail != "":
+				cliout.ItemSuccess("%s: %s", line, d.Detail)
+			case d.OK:
+				cliout.ItemSuccess("%s", line)
+			default:
+				cliout.ItemError("%s: %s", line, d.Error)
+				if d.Suggestion != "" {
+					cliout.Hint(d.Suggestion)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package netutil
+
+import (
+	"os"
+	"strings"
+)
+
+// Proxy setting sources reported in ProxyConfig.Source.
+const (
+	ProxySourceNone        = "none"
+	ProxySourceEnvironment = "environment"
+	ProxySourceSystem      = "system"
+)
+
+// ProxyConfig describes the proxy settings in effect for the current user.
+type ProxyConfig struct {
+	HTTPProxy  string `json:"httpProxy,omitempty"`
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	NoProxy    string `json:"noProxy,omitempty"`
+	// Source is where the settings came from: ProxySourceEnvironment,
+	// ProxySourceSystem, or ProxySourceNone.
+	Source string `json:"source"`
+}
+
+// DetectProxy reports proxy settings from HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY (upper or lower case). If none are set, it falls back to the
+// operating system proxy, which is currently only read on Windows.
+//
+// Note that Go's HTTP client honors only the environment variables; a system
+// proxy is reported so diagnostics can suggest exporting it.
+func DetectProxy() ProxyConfig {
+	cfg := ProxyConfig{
+		HTTPProxy:  getenvAny("HTTP_PROXY", "http_proxy"),
+		HTTPSProxy: getenvAny("HTTPS_PROXY", "https_proxy"),
+		NoProxy:    getenvAny("NO_PROXY", "no_proxy"),
+	}
+	if cfg.HTTPProxy != "" || cfg.HTTPSProxy != "" {
+		cfg.Source = ProxySourceEnvironment
+		return cfg
+	}
+
+	if sys, ok := systemProxy(); ok {
+		sys.Source = ProxySourceSystem
+		return sys
+	}
+
+	cfg.Source = ProxySourceNone
+	return cfg
+}
+
+// Diagnostic converts the configuration to a Diagnostic. Proxy credentials
+// are never included.
+func (c ProxyConfig) Diagnostic() Diagnostic {
+	d := Diagnostic{Check: CheckTypeProxy, Target: c.Source, OK: true}
+	switch c.Source {
+	case ProxySourceNone:
+		d.Detail = "no proxy configured"
+	default:
+		var parts []string
+		if c.HTTPSProxy != "" {
+			parts = append(parts, "https="+redactProxy(c.HTTPSProxy))
+		}
+		if c.HTTPProxy != "" {
+			parts = append(parts, "http="+redactProxy(c.HTTPProxy))
+		}
+		if c.NoProxy != "" {
+			parts = append(parts, "no_proxy="+c.NoProxy)
+		}
+		d.Detail = strings.Join(parts, " ")
+		if c.Source == ProxySourceSystem {
+			d.Suggestion = "Go-based tools ignore the system proxy; set HTTPS_PROXY to use it"
+		}
+	}
+	return d
+}
+
+// getenvAny returns the first non-empty value among keys.
+func getenvAny(keys ...string) string {
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// redactProxy removes credentials from a proxy URL.
+func redactProxy(proxy string) string {
+	scheme, rest, hasScheme := strings.Cut(proxy, "://")
+	if !hasScheme {
+		rest, scheme = proxy, ""
+	}
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		rest = rest[at+1:]
+	}
+	if hasScheme {
+		return scheme + "://" + rest
+	}
+	return rest
+}
+
+// parseWinINetProxy parses a WinINet ProxyServer value, which is either a
+// single "host:port" used for all protocols or a list such as
+// "http=host:port;https=host:port".
+func parseWinINetProxy(server string) ProxyConfig {
+	var cfg ProxyConfig
+	if !strings.Contains(server, "=") {
+		cfg.HTTPProxy = withHTTPScheme(server)
+		cfg.HTTPSProxy = cfg.HTTPProxy
+		return cfg
+	}
+	for _, entry := range strings.Split(server, ";") {
+		proto, addr, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || addr == "" {
+			continue
+		}
+		switch strings.ToLower(proto) {
+		case "http":
+			cfg.HTTPProxy = withHTTPScheme(addr)
+		case "https":
+			cfg.HTTPSProxy = withHTTPScheme(addr)
+		}
+	}
+	return cfg
+}
+
+// withHTTPScheme prefixes addr with http:// when it has no scheme.
+func withHTTPScheme(addr string) string {
+	if strings.Contains(addr, "://") {
+		return addr
+	}
+	return "http://" + addr
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package netutil
+
+// systemProxy reports the operating system proxy. Only Windows exposes a
+// per-user system proxy that can be read without desktop-specific tooling.
+func systemProxy() (ProxyConfig, bool) {
+	return ProxyConfig{}, false
+}
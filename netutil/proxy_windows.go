@@ -0,0 +1,35 @@
+//go:build windows
+
+package netutil
+
+import (
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// internetSettingsKey holds the WinINet per-user proxy configuration.
+const internetSettingsKey = `Software\Microsoft\Windows\CurrentVersion\Internet Settings`
+
+// systemProxy reads the WinINet proxy configuration from the registry.
+func systemProxy() (ProxyConfig, bool) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, internetSettingsKey, registry.QUERY_VALUE)
+	if err != nil {
+		return ProxyConfig{}, false
+	}
+	defer key.Close()
+
+	enabled, _, err := key.GetIntegerValue("ProxyEnable")
+	if err != nil || enabled == 0 {
+		return ProxyConfig{}, false
+	}
+	server, _, err := key.GetStringValue("ProxyServer")
+	if err != nil || server == "" {
+		return ProxyConfig{}, false
+	}
+	override, _, _ := key.GetStringValue("ProxyOverride")
+
+	cfg := parseWinINetProxy(server)
+	cfg.NoProxy = strings.ReplaceAll(override, ";", ",")
+	return cfg, cfg.HTTPProxy != "" || cfg.HTTPSProxy != ""
+}
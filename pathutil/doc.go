@@ -12,6 +12,8 @@
 //   - Search common system directories for tools not in PATH
 //   - Installation suggestions for popular development tools
 //   - Automatic handling of Windows executable extensions (.exe)
+//   - Detection of nvm, pyenv, asdf, and Volta shims and managed installs
+//   - Conventional per-OS install directories for user and machine scope
 //
 // # Cross-Platform Behavior
 //
@@ -80,6 +82,38 @@
 //	        toolName, pathutil.GetInstallSuggestion(toolName))
 //	}
 //
+// # Example: Version Manager Shims
+//
+// Tools installed through a version manager often resolve to a shim that
+// fails at run time when the selected version is not installed.
+// ClassifyToolPath reports which manager owns a path, the version it selects,
+// and the real binary:
+//
+//	info := pathutil.ClassifyToolPath(pathutil.FindToolInPath("python"))
+//	if info.Missing {
+//	    // e.g. "Run 'pyenv install 3.12.1'"
+//	    fmt.Println(info.Suggestion)
+//	}
+//
+// GetInstallSuggestion returns the manager command instead of a download URL
+// when a managed version is missing or a manager is set up for the tool.
+//
+// # Example: Choosing an Install Directory
+//
+// GetInstallDir returns where an installer should place a binary for the
+// current OS and scope (~/.local/bin, %LOCALAPPDATA%\Programs\<tool>,
+// /usr/local/bin, or %ProgramFiles%\<tool>), whether that directory is
+// already on PATH, and whether the current user can write to it:
+//
+//	dir, err := pathutil.GetInstallDir("bicep", pathutil.ScopeMachine)
+//	if err != nil {
+//	    return err
+//	}
+//	if !dir.Writable {
+//	    dir, err = pathutil.GetInstallDir("bicep", pathutil.ScopeUser)
+//	}
+//	// ... install into dir.Path; if !dir.OnPath, tell the user to add it
+//
 // # Supported Installation Suggestions
 //
 // The package provides installation URLs for common development tools:
@@ -0,0 +1,179 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pathutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Scope selects who an installed tool is available to.
+type Scope int
+
+const (
+	// ScopeUser installs for the current user only and never needs elevation.
+	ScopeUser Scope = iota
+	// ScopeMachine installs for every user and usually needs administrator
+	// or root permissions.
+	ScopeMachine
+)
+
+// String returns "user" or "machine".
+func (s Scope) String() string {
+	switch s {
+	case ScopeUser:
+		return "user"
+	case ScopeMachine:
+		return "machine"
+	default:
+		return fmt.Sprintf("Scope(%d)", int(s))
+	}
+}
+
+// ErrInvalidToolName is returned by GetInstallDir for empty tool names and
+// names containing path separators or "..".
+var ErrInvalidToolName = errors.New("invalid tool name")
+
+// InstallDir is the recommended directory for installing a tool binary.
+type InstallDir struct {
+	// Path is the directory the binary should be placed in. It may not exist
+	// yet; the installer is expected to create it.
+	Path string
+	// Scope is the scope Path was chosen for.
+	Scope Scope
+	// OnPath is true when Path is already listed in the current PATH, so the
+	// tool is runnable right after install. When false, add Path to PATH.
+	OnPath bool
+	// Writable is true when the current user can create files in Path (or,
+	// if it does not exist yet, in its nearest existing parent). Machine
+	// scope directories are typically not writable without sudo or an
+	// elevated prompt.
+	Writable bool
+}
+
+// GetInstallDir returns the conventional directory for installing the
+// binary of tool with the given scope:
+//
+//	           User                          Machine
+//	Windows    %LOCALAPPDATA%\Programs\tool  %ProgramFiles%\tool
+//	macOS      ~/.local/bin                  /usr/local/bin
+//	Linux      ~/.local/bin                  /usr/local/bin
+//
+// On Windows each tool gets its own directory; on Unix binaries share a bin
+// directory, so tool only affects the Windows paths. The result reports
+// whether the directory is already on PATH and whether it is writable, so
+// installers can decide whether to update PATH or ask for elevation.
+//
+// Example:
+//
+//	dir, err := pathutil.GetInstallDir("bicep", pathutil.ScopeUser)
+//	if err != nil {
+//	    return err
+//	}
+//	if !dir.OnPath {
+//	    fmt.Printf("Add %s to your PATH to run bicep\n", dir.Path)
+//	}
+func GetInstallDir(tool string, scope Scope) (InstallDir, error) {
+	path, err := installDirPath(runtime.GOOS, tool, scope)
+	if err != nil {
+		return InstallDir{}, err
+	}
+	return InstallDir{
+		Path:     path,
+		Scope:    scope,
+		OnPath:   dirInPath(path, os.Getenv("PATH")),
+		Writable: dirWritable(path),
+	}, nil
+}
+
+// installDirPath returns the install directory for goos without touching
+// the file system.
+func installDirPath(goos, tool string, scope Scope) (string, error) {
+	if tool == "" || tool == "." || strings.Contains(tool, "..") || strings.ContainsAny(tool, `/\`) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidToolName, tool)
+	}
+	if scope != ScopeUser && scope != ScopeMachine {
+		return "", fmt.Errorf("unknown install scope %s", scope)
+	}
+
+	if goos == "windows" {
+		if scope == ScopeMachine {
+			programFiles := os.Getenv("ProgramFiles")
+			if programFiles == "" {
+				programFiles = `C:\Program Files`
+			}
+			return filepath.Join(programFiles, tool), nil
+		}
+		localAppData := os.Getenv("LOCALAPPDATA")
+		if localAppData == "" {
+			home, err := userHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to determine home directory: %w", err)
+			}
+			localAppData = filepath.Join(home, "AppData", "Local")
+		}
+		return filepath.Join(localAppData, "Programs", tool), nil
+	}
+
+	if scope == ScopeMachine {
+		return "/usr/local/bin", nil
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "bin"), nil
+}
+
+// dirInPath reports whether dir is one of the entries of pathEnv. Entries
+// are compared after cleaning, and case-insensitively on Windows.
+func dirInPath(dir, pathEnv string) bool {
+	dir = filepath.Clean(dir)
+	for _, entry := range filepath.SplitList(pathEnv) {
+		if entry == "" {
+			continue
+		}
+		entry = filepath.Clean(entry)
+		if entry == dir || (runtime.GOOS == "windows" && strings.EqualFold(entry, dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirWritable reports whether a file can be created in dir, or in its
+// nearest existing parent when dir does not exist yet. It probes with a
+// temporary file because permission bits alone miss ACLs and read-only mounts.
+func dirWritable(dir string) bool {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return false
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".azd-write-check-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	_ = f.Close()
+	_ = os.Remove(name)
+	return true
+}
@@ -0,0 +1,209 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pathutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func withInstallHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	old := userHomeDir
+	userHomeDir = func() (string, error) { return home, nil }
+	t.Cleanup(func() { userHomeDir = old })
+	return home
+}
+
+func TestScopeString(t *testing.T) {
+	tests := []struct {
+		scope Scope
+		want  string
+	}{
+		{ScopeUser, "user"},
+		{ScopeMachine, "machine"},
+		{Scope(7), "Scope(7)"},
+	}
+	for _, tt := range tests {
+		if got := tt.scope.String(); got != tt.want {
+			t.Errorf("Scope(%d).String() = %q, want %q", int(tt.scope), got, tt.want)
+		}
+	}
+}
+
+func TestInstallDirPath(t *testing.T) {
+	home := withInstallHome(t)
+	t.Setenv("LOCALAPPDATA", filepath.Join("C:", "Users", "me", "AppData", "Local"))
+	t.Setenv("ProgramFiles", filepath.Join("C:", "Program Files"))
+
+	tests := []struct {
+		name  string
+		goos  string
+		scope Scope
+		want  string
+	}{
+		{"linux user", "linux", ScopeUser, filepath.Join(home, ".local", "bin")},
+		{"linux machine", "linux", ScopeMachine, "/usr/local/bin"},
+		{"darwin user", "darwin", ScopeUser, filepath.Join(home, ".local", "bin")},
+		{"darwin machine", "darwin", ScopeMachine, "/usr/local/bin"},
+		{"windows user", "windows", ScopeUser, filepath.Join("C:", "Users", "me", "AppData", "Local", "Programs", "bicep")},
+		{"windows machine", "windows", ScopeMachine, filepath.Join("C:", "Program Files", "bicep")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := installDirPath(tt.goos, "bicep", tt.scope)
+			if err != nil {
+				t.Fatalf("installDirPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("installDirPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallDirPathWindowsFallbacks(t *testing.T) {
+	home := withInstallHome(t)
+	t.Setenv("LOCALAPPDATA", "")
+	t.Setenv("ProgramFiles", "")
+
+	got, err := installDirPath("windows", "bicep", ScopeUser)
+	if err != nil {
+		t.Fatalf("installDirPath() error = %v", err)
+	}
+	if want := filepath.Join(home, "AppData", "Local", "Programs", "bicep"); got != want {
+		t.Errorf("installDirPath() user = %q, want %q", got, want)
+	}
+
+	got, err = installDirPath("windows", "bicep", ScopeMachine)
+	if err != nil {
+		t.Fatalf("installDirPath() error = %v", err)
+	}
+	if want := filepath.Join(`C:\Program Files`, "bicep"); got != want {
+		t.Errorf("installDirPath() machine = %q, want %q", got, want)
+	}
+}
+
+func TestInstallDirPathErrors(t *testing.T) {
+	withInstallHome(t)
+
+	for _, tool := range []string{"", ".", "..", "../bin", "a/b", `a\b`} {
+		if _, err := installDirPath("linux", tool, ScopeUser); !errors.Is(err, ErrInvalidToolName) {
+			t.Errorf("installDirPath(%q) error = %v, want ErrInvalidToolName", tool, err)
+		}
+	}
+	if _, err := installDirPath("linux", "bicep", Scope(5)); err == nil {
+		t.Error("installDirPath() with unknown scope should fail")
+	}
+
+	old := userHomeDir
+	userHomeDir = func() (string, error) { return "", errors.New("no home") }
+	defer func() { userHomeDir = old }()
+	if _, err := installDirPath("linux", "bicep", ScopeUser); err == nil {
+		t.Error("installDirPath() without a home directory should fail")
+	}
+}
+
+func TestDirInPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "bin")
+	sep := string(os.PathListSeparator)
+
+	tests := []struct {
+		name    string
+		pathEnv string
+		want    bool
+	}{
+		{"empty", "", false},
+		{"present", "/usr/bin" + sep + dir, true},
+		{"trailing separator", dir + string(filepath.Separator), true},
+		{"absent", "/usr/bin" + sep + "/bin", false},
+		{"empty entries", sep + sep + dir + sep, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dirInPath(dir, tt.pathEnv); got != tt.want {
+				t.Errorf("dirInPath(%q, %q) = %v, want %v", dir, tt.pathEnv, got, tt.want)
+			}
+		})
+	}
+
+	if runtime.GOOS == "windows" && !dirInPath(dir, strings.ToUpper(dir)) {
+		t.Error("dirInPath() should ignore case on Windows")
+	}
+}
+
+func TestDirWritable(t *testing.T) {
+	dir := t.TempDir()
+
+	if !dirWritable(dir) {
+		t.Errorf("dirWritable(%q) = false, want true", dir)
+	}
+	if !dirWritable(filepath.Join(dir, "not", "yet", "created")) {
+		t.Error("dirWritable() should check the nearest existing parent")
+	}
+
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if dirWritable(file) {
+		t.Error("dirWritable() on a regular file should be false")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("dirWritable() left %d entries behind, want only the test file", len(entries))
+	}
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		return
+	}
+	readOnly := filepath.Join(dir, "ro")
+	if err := os.Mkdir(readOnly, 0o555); err != nil {
+		t.Fatal(err)
+	}
+	if dirWritable(readOnly) {
+		t.Error("dirWritable() on a read-only directory should be false")
+	}
+}
+
+func TestGetInstallDir(t *testing.T) {
+	home := withInstallHome(t)
+	t.Setenv("LOCALAPPDATA", filepath.Join(home, "AppData", "Local"))
+
+	dir, err := GetInstallDir("bicep", ScopeUser)
+	if err != nil {
+		t.Fatalf("GetInstallDir() error = %v", err)
+	}
+	if !strings.HasPrefix(dir.Path, home) {
+		t.Errorf("GetInstallDir() path = %q, want under %q", dir.Path, home)
+	}
+	if dir.Scope != ScopeUser {
+		t.Errorf("GetInstallDir() scope = %v, want user", dir.Scope)
+	}
+	if dir.OnPath {
+		t.Error("GetInstallDir() OnPath = true before PATH was updated")
+	}
+	if !dir.Writable {
+		t.Error("GetInstallDir() Writable = false for a temp home")
+	}
+
+	t.Setenv("PATH", dir.Path+string(os.PathListSeparator)+os.Getenv("PATH"))
+	dir, err = GetInstallDir("bicep", ScopeUser)
+	if err != nil {
+		t.Fatalf("GetInstallDir() error = %v", err)
+	}
+	if !dir.OnPath {
+		t.Error("GetInstallDir() OnPath = false after adding the directory to PATH")
+	}
+
+	if _, err := GetInstallDir("", ScopeMachine); !errors.Is(err, ErrInvalidToolName) {
+		t.Errorf("GetInstallDir(\"\") error = %v, want ErrInvalidToolName", err)
+	}
+}
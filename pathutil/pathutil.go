@@ -141,7 +141,15 @@ func SearchToolInSystemPath(toolName string) string {
 }
 
 // GetInstallSuggestion returns a suggestion for how to install a missing tool.
+// When the tool is managed by nvm, pyenv, asdf, or Volta and the selected
+// version is not installed (or the manager is set up but the tool is not on
+// PATH), the suggestion is the manager command instead, such as
+// "Run 'nvm install 20' and 'nvm use 20'".
 func GetInstallSuggestion(toolName string) string {
+	if suggestion := managedInstallSuggestion(toolName); suggestion != "" {
+		return suggestion
+	}
+
 	suggestions := map[string]string{
 		"node":   "Install from https://nodejs.org/",
 		"pnpm":   "Install from https://pnpm.io/installation",
@@ -172,3 +180,17 @@ func GetInstallSuggestion(toolName string) string {
 	}
 	return fmt.Sprintf("Please install %s manually", toolName)
 }
+
+// managedInstallSuggestion returns a version-manager command for toolName,
+// or "" when no manager is involved or the managed tool is usable.
+func managedInstallSuggestion(toolName string) string {
+	path := FindToolInPath(toolName)
+	if path == "" {
+		return activeManagerSuggestion(toolName)
+	}
+	info := ClassifyToolPath(path)
+	if info.Manager == ManagerNone || !info.Missing {
+		return ""
+	}
+	return info.Suggestion
+}
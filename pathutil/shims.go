@@ -0,0 +1,559 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pathutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// VersionManager identifies a tool version manager.
+type VersionManager string
+
+const (
+	// ManagerNone means the path is not managed by a known version manager.
+	ManagerNone VersionManager = ""
+	// ManagerNVM is nvm (Unix) or nvm-windows.
+	ManagerNVM VersionManager = "nvm"
+	// ManagerPyenv is pyenv or pyenv-win.
+	ManagerPyenv VersionManager = "pyenv"
+	// ManagerAsdf is asdf.
+	ManagerAsdf VersionManager = "asdf"
+	// ManagerVolta is Volta.
+	ManagerVolta VersionManager = "volta"
+)
+
+// ToolPathInfo describes a tool path returned by FindToolInPath.
+type ToolPathInfo struct {
+	// Path is the classified path.
+	Path string
+	// Manager is the version manager owning Path, or ManagerNone.
+	Manager VersionManager
+	// IsShim is true when Path is a shim (or symlink) that dispatches to a
+	// version selected at run time rather than the binary itself.
+	IsShim bool
+	// RealPath is the binary that actually runs, when it could be resolved.
+	RealPath string
+	// Version is the selected tool version, when it could be determined.
+	// "system" means the manager defers to a tool outside its control.
+	Version string
+	// Missing is true when the selected version is not installed, so
+	// running Path will fail.
+	Missing bool
+	// Suggestion is a manager-aware command to install or activate the
+	// tool, such as "Run 'nvm install 20' and 'nvm use 20'".
+	Suggestion string
+}
+
+var (
+	// userHomeDir and workingDir are replaced in tests.
+	userHomeDir = os.UserHomeDir
+	workingDir  = os.Getwd
+)
+
+// ClassifyToolPath identifies whether path belongs to nvm, pyenv, asdf, or
+// Volta and, for shims, resolves the version the manager will select and the
+// real binary it will run. Manager roots honor NVM_DIR, NVM_HOME,
+// NVM_SYMLINK, PYENV_ROOT, ASDF_DATA_DIR, and VOLTA_HOME. Versions come from
+// the same sources the managers use: environment overrides, version files
+// (.nvmrc, .python-version, .tool-versions, package.json "volta") found
+// from the working directory upward, then global defaults.
+//
+// Paths outside every known manager are returned with ManagerNone and their
+// symlinks resolved in RealPath.
+//
+// Example:
+//
+//	info := pathutil.ClassifyToolPath(pathutil.FindToolInPath("python"))
+//	if info.Missing {
+//	    return fmt.Errorf("python %s is not installed: %s", info.Version, info.Suggestion)
+//	}
+func ClassifyToolPath(path string) ToolPathInfo {
+	info := ToolPathInfo{Path: path}
+	if path == "" {
+		return info
+	}
+	path = filepath.Clean(path)
+	tool := toolBaseName(path)
+
+	switch {
+	case classifyNVM(&info, path, tool):
+	case classifyPyenv(&info, path, tool):
+	case classifyAsdf(&info, path, tool):
+	case classifyVolta(&info, path, tool):
+	default:
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			info.RealPath = real
+		}
+		return info
+	}
+
+	if info.RealPath != "" && !info.Missing {
+		if _, err := os.Stat(info.RealPath); err != nil {
+			info.Missing = true
+		}
+	}
+	info.Suggestion = managerSuggestion(info.Manager, tool, info.Version, info.Missing)
+	return info
+}
+
+func classifyNVM(info *ToolPathInfo, path, _ string) bool {
+	if runtime.GOOS == "windows" {
+		if link := os.Getenv("NVM_SYMLINK"); link != "" {
+			if _, ok := within(path, link); ok {
+				info.Manager, info.IsShim = ManagerNVM, true
+				if real, err := filepath.EvalSymlinks(path); err == nil {
+					info.RealPath = real
+					info.Version = versionFromDir(filepath.Base(filepath.Dir(real)))
+				} else {
+					info.Missing = true
+				}
+				return true
+			}
+		}
+		if rel, ok := within(path, nvmRoot()); ok {
+			// nvm-windows: <root>\v20.11.0\node.exe
+			info.Manager, info.RealPath = ManagerNVM, path
+			info.Version = versionFromDir(firstElement(rel))
+			return true
+		}
+		return false
+	}
+
+	rel, ok := within(path, nvmRoot())
+	if !ok {
+		return false
+	}
+	info.Manager = ManagerNVM
+	// <root>/versions/node/v20.11.0/bin/node
+	if parts := splitRel(rel); len(parts) >= 3 && parts[0] == "versions" {
+		info.RealPath = path
+		info.Version = versionFromDir(parts[2])
+	} else if v := readVersionFile(".nvmrc"); v != "" && !isNVMAlias(v) {
+		info.Version = strings.TrimPrefix(v, "v")
+	}
+	return true
+}
+
+func classifyPyenv(info *ToolPathInfo, path, tool string) bool {
+	root := pyenvRoot()
+	rel, ok := within(path, root)
+	if !ok {
+		return false
+	}
+	info.Manager = ManagerPyenv
+	parts := splitRel(rel)
+
+	switch {
+	case len(parts) >= 2 && parts[0] == "versions":
+		info.RealPath = path
+		info.Version = parts[1]
+	case len(parts) >= 1 && parts[0] == "shims":
+		info.IsShim = true
+		info.Version = pyenvVersion(root)
+		if info.Version != "" && info.Version != "system" {
+			info.RealPath = managedBinary(filepath.Join(root, "versions", info.Version), tool)
+		}
+	}
+	return true
+}
+
+func classifyAsdf(info *ToolPathInfo, path, tool string) bool {
+	root := asdfRoot()
+	rel, ok := within(path, root)
+	if !ok {
+		return false
+	}
+	info.Manager = ManagerAsdf
+	parts := splitRel(rel)
+
+	switch {
+	case len(parts) >= 3 && parts[0] == "installs":
+		info.RealPath = path
+		info.Version = parts[2]
+	case len(parts) >= 1 && parts[0] == "shims":
+		info.IsShim = true
+		plugin := asdfPlugin(tool)
+		info.Version = asdfVersion(plugin)
+		if info.Version != "" && info.Version != "system" {
+			info.RealPath = managedBinary(filepath.Join(root, "installs", plugin, info.Version), tool)
+		}
+	}
+	return true
+}
+
+func classifyVolta(info *ToolPathInfo, path, tool string) bool {
+	root := voltaRoot()
+	rel, ok := within(path, root)
+	if !ok {
+		return false
+	}
+	info.Manager = ManagerVolta
+	parts := splitRel(rel)
+
+	switch {
+	case len(parts) >= 4 && parts[0] == "tools" && parts[1] == "image":
+		info.RealPath = path
+		info.Version = parts[3]
+	case len(parts) >= 1 && parts[0] == "bin":
+		info.IsShim = true
+		if isNodeTool(tool) {
+			info.Version = voltaNodeVersion(root)
+			if info.Version != "" {
+				info.RealPath = managedBinary(filepath.Join(root, "tools", "image", "node", info.Version), tool)
+			}
+		}
+	}
+	return true
+}
+
+// managerSuggestion returns the command that installs or activates tool
+// with manager.
+func managerSuggestion(manager VersionManager, tool, version string, missing bool) string {
+	v := version
+	switch manager {
+	case ManagerNVM:
+		if v == "" {
+			v = "--lts"
+		}
+		return fmt.Sprintf("Run 'nvm install %s' and 'nvm use %s'", v, v)
+	case ManagerPyenv:
+		if v == "" || v == "system" {
+			return "Run 'pyenv install --list' to pick a version, then 'pyenv install <version>' and 'pyenv local <version>'"
+		}
+		if missing {
+			return fmt.Sprintf("Run 'pyenv install %s'", v)
+		}
+		return fmt.Sprintf("Run 'pyenv local %s'", v)
+	case ManagerAsdf:
+		plugin := asdfPlugin(tool)
+		if v == "" || v == "system" {
+			v = "latest"
+		}
+		if missing {
+			return fmt.Sprintf("Run 'asdf install %s %s'", plugin, v)
+		}
+		return fmt.Sprintf("Run 'asdf install %s %s' and 'asdf set %s %s'", plugin, v, plugin, v)
+	case ManagerVolta:
+		pkg := tool
+		if isNodeTool(tool) {
+			pkg = "node"
+		}
+		if v == "" {
+			return fmt.Sprintf("Run 'volta install %s'", pkg)
+		}
+		return fmt.Sprintf("Run 'volta install %s@%s'", pkg, v)
+	}
+	return ""
+}
+
+// activeManagerSuggestion returns a suggestion for a tool that is not on
+// PATH at all but whose version manager is set up in the environment.
+func activeManagerSuggestion(tool string) string {
+	switch {
+	case isNodeTool(tool) && os.Getenv("VOLTA_HOME") != "":
+		return managerSuggestion(ManagerVolta, tool, "", true)
+	case isNodeTool(tool) && (os.Getenv("NVM_DIR") != "" || os.Getenv("NVM_HOME") != ""):
+		v := readVersionFile(".nvmrc")
+		if isNVMAlias(v) {
+			v = ""
+		}
+		return managerSuggestion(ManagerNVM, tool, strings.TrimPrefix(v, "v"), true)
+	case isPythonTool(tool) && os.Getenv("PYENV_ROOT") != "":
+		return managerSuggestion(ManagerPyenv, tool, pyenvVersion(pyenvRoot()), true)
+	case os.Getenv("ASDF_DATA_DIR") != "" || os.Getenv("ASDF_DIR") != "":
+		// asdf can only install tools it has a plugin for, so it is
+		// suggested only when the plugin is added or the tool is pinned.
+		plugin := asdfPlugin(tool)
+		v := asdfVersion(plugin)
+		if v == "" && !asdfPluginInstalled(plugin) {
+			return ""
+		}
+		return managerSuggestion(ManagerAsdf, tool, v, true)
+	}
+	return ""
+}
+
+// asdfPluginInstalled reports whether plugin has been added to asdf.
+func asdfPluginInstalled(plugin string) bool {
+	info, err := os.Stat(filepath.Join(asdfRoot(), "plugins", plugin))
+	return err == nil && info.IsDir()
+}
+
+func nvmRoot() string {
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("NVM_HOME"); dir != "" {
+			return dir
+		}
+		return filepath.Join(os.Getenv("APPDATA"), "nvm")
+	}
+	if dir := os.Getenv("NVM_DIR"); dir != "" {
+		return dir
+	}
+	return homeJoin(".nvm")
+}
+
+func pyenvRoot() string {
+	if dir := os.Getenv("PYENV_ROOT"); dir != "" {
+		return dir
+	}
+	if runtime.GOOS == "windows" {
+		return homeJoin(".pyenv", "pyenv-win")
+	}
+	return homeJoin(".pyenv")
+}
+
+func asdfRoot() string {
+	if dir := os.Getenv("ASDF_DATA_DIR"); dir != "" {
+		return dir
+	}
+	return homeJoin(".asdf")
+}
+
+func voltaRoot() string {
+	if dir := os.Getenv("VOLTA_HOME"); dir != "" {
+		return dir
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("LOCALAPPDATA"), "Volta")
+	}
+	return homeJoin(".volta")
+}
+
+// pyenvVersion follows pyenv's lookup order: PYENV_VERSION, .python-version
+// from the working directory upward, then <root>/version. Only the first of
+// several configured versions is returned.
+func pyenvVersion(root string) string {
+	v := os.Getenv("PYENV_VERSION")
+	if v == "" {
+		v = readVersionFile(".python-version")
+	}
+	if v == "" {
+		v = firstLine(filepath.Join(root, "version"))
+	}
+	if v == "" {
+		return "system"
+	}
+	v, _, _ = strings.Cut(v, ":")
+	if fields := strings.Fields(v); len(fields) > 0 {
+		return fields[0]
+	}
+	return "system"
+}
+
+// asdfVersion follows asdf's lookup order: ASDF_<PLUGIN>_VERSION, then
+// .tool-versions from the working directory upward, then in the home
+// directory.
+func asdfVersion(plugin string) string {
+	envName := "ASDF_" + strings.ToUpper(strings.ReplaceAll(plugin, "-", "_")) + "_VERSION"
+	if v := os.Getenv(envName); v != "" {
+		return v
+	}
+	dirs := parentDirs()
+	if home, err := userHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	for _, dir := range dirs {
+		if v := toolVersionsEntry(filepath.Join(dir, ".tool-versions"), plugin); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// voltaNodeVersion reads the "volta" pin from the nearest package.json,
+// then the default platform in <root>/tools/user/platform.json.
+func voltaNodeVersion(root string) string {
+	for _, dir := range parentDirs() {
+		// #nosec G304 -- reading package.json from the project directory tree
+		data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+		if err != nil {
+			continue
+		}
+		var pkg struct {
+			Volta struct {
+				Node string `json:"node"`
+			} `json:"volta"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && pkg.Volta.Node != "" {
+			return pkg.Volta.Node
+		}
+	}
+
+	// #nosec G304 -- path is inside the Volta home directory
+	data, err := os.ReadFile(filepath.Join(root, "tools", "user", "platform.json"))
+	if err != nil {
+		return ""
+	}
+	var platform struct {
+		Node struct {
+			Runtime string `json:"runtime"`
+		} `json:"node"`
+	}
+	if json.Unmarshal(data, &platform) != nil {
+		return ""
+	}
+	return platform.Node.Runtime
+}
+
+// toolVersionsEntry returns the first version listed for plugin in an asdf
+// .tool-versions file.
+func toolVersionsEntry(path, plugin string) string {
+	// #nosec G304 -- reading .tool-versions from the project directory tree
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == plugin {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// readVersionFile returns the first line of name in the working directory
+// or its nearest parent that has one.
+func readVersionFile(name string) string {
+	for _, dir := range parentDirs() {
+		if v := firstLine(filepath.Join(dir, name)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstLine(path string) string {
+	// #nosec G304 -- reading a version file chosen by the version manager's rules
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(line)
+}
+
+// parentDirs returns the working directory and each of its parents.
+func parentDirs() []string {
+	dir, err := workingDir()
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for {
+		dirs = append(dirs, dir)
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dirs
+		}
+		dir = parent
+	}
+}
+
+// managedBinary returns where a manager installs tool inside a version
+// directory.
+func managedBinary(versionDir, tool string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(versionDir, tool+".exe")
+	}
+	return filepath.Join(versionDir, "bin", tool)
+}
+
+// within reports whether path is inside root and returns the relative path.
+func within(path, root string) (string, bool) {
+	if root == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(filepath.Clean(root), path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if runtime.GOOS == "windows" && !strings.EqualFold(filepath.VolumeName(root), filepath.VolumeName(path)) {
+		return "", false
+	}
+	return rel, true
+}
+
+func splitRel(rel string) []string {
+	return strings.Split(filepath.ToSlash(rel), "/")
+}
+
+func firstElement(rel string) string {
+	return splitRel(rel)[0]
+}
+
+func homeJoin(elem ...string) string {
+	home, err := userHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(append([]string{home}, elem...)...)
+}
+
+// toolBaseName strips the directory and any Windows executable extension.
+func toolBaseName(path string) string {
+	name := filepath.Base(path)
+	for _, ext := range []string{".exe", ".cmd", ".bat"} {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+// versionFromDir turns an nvm directory name such as "v20.11.0" into a version.
+func versionFromDir(name string) string {
+	if len(name) > 1 && name[0] == 'v' && name[1] >= '0' && name[1] <= '9' {
+		return name[1:]
+	}
+	return ""
+}
+
+// isNVMAlias reports whether v is an nvm alias such as "lts/iron" or "node"
+// rather than a version number.
+func isNVMAlias(v string) bool {
+	v = strings.TrimPrefix(v, "v")
+	return v == "" || v[0] < '0' || v[0] > '9'
+}
+
+// asdfPlugin maps a tool name to the asdf plugin that provides it.
+func asdfPlugin(tool string) string {
+	switch {
+	case isNodeTool(tool):
+		return "nodejs"
+	case isPythonTool(tool):
+		return "python"
+	case tool == "go" || tool == "gofmt":
+		return "golang"
+	case tool == "dotnet":
+		return "dotnet-core"
+	}
+	return tool
+}
+
+func isNodeTool(tool string) bool {
+	switch tool {
+	case "node", "npm", "npx", "corepack":
+		return true
+	}
+	return false
+}
+
+func isPythonTool(tool string) bool {
+	switch tool {
+	case "python", "python3", "pip", "pip3":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pathutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// shimEnv isolates manager roots, the home directory, and the working
+// directory in temp directories.
+type shimEnv struct {
+	home, project string
+}
+
+func newShimEnv(t *testing.T) shimEnv {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix manager layouts")
+	}
+	env := shimEnv{home: t.TempDir(), project: t.TempDir()}
+	for _, name := range []string{"NVM_DIR", "PYENV_ROOT", "PYENV_VERSION", "ASDF_DATA_DIR", "ASDF_DIR", "ASDF_NODEJS_VERSION", "ASDF_PYTHON_VERSION", "VOLTA_HOME"} {
+		t.Setenv(name, "")
+	}
+
+	oldHome, oldWd := userHomeDir, workingDir
+	userHomeDir = func() (string, error) { return env.home, nil }
+	workingDir = func() (string, error) { return env.project, nil }
+	t.Cleanup(func() { userHomeDir, workingDir = oldHome, oldWd })
+	return env
+}
+
+func writeShimFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClassifyToolPath_Unmanaged(t *testing.T) {
+	env := newShimEnv(t)
+	bin := writeShimFile(t, filepath.Join(env.home, "usr", "bin", "node"), "")
+
+	info := ClassifyToolPath(bin)
+	if info.Manager != ManagerNone || info.IsShim || info.Suggestion != "" {
+		t.Errorf("ClassifyToolPath() = %+v, want unmanaged", info)
+	}
+	if info.RealPath == "" {
+		t.Error("RealPath should be resolved for an unmanaged binary")
+	}
+	if got := ClassifyToolPath(""); got != (ToolPathInfo{}) {
+		t.Errorf("ClassifyToolPath(\"\") = %+v", got)
+	}
+}
+
+func TestClassifyToolPath_NVM(t *testing.T) {
+	env := newShimEnv(t)
+	node := writeShimFile(t, filepath.Join(env.home, ".nvm", "versions", "node", "v20.11.0", "bin", "node"), "")
+
+	info := ClassifyToolPath(node)
+	if info.Manager != ManagerNVM || info.IsShim || info.Version != "20.11.0" || info.RealPath != node || info.Missing {
+		t.Errorf("ClassifyToolPath(nvm node) = %+v", info)
+	}
+	if info.Suggestion != "Run 'nvm install 20.11.0' and 'nvm use 20.11.0'" {
+		t.Errorf("Suggestion = %q", info.Suggestion)
+	}
+
+	// A custom NVM_DIR is honored.
+	custom := t.TempDir()
+	t.Setenv("NVM_DIR", custom)
+	node = writeShimFile(t, filepath.Join(custom, "versions", "node", "v18.19.0", "bin", "npm"), "")
+	if info := ClassifyToolPath(node); info.Manager != ManagerNVM || info.Version != "18.19.0" {
+		t.Errorf("ClassifyToolPath(NVM_DIR npm) = %+v", info)
+	}
+}
+
+func TestClassifyToolPath_PyenvShim(t *testing.T) {
+	env := newShimEnv(t)
+	root := filepath.Join(env.home, ".pyenv")
+	shim := writeShimFile(t, filepath.Join(root, "shims", "python"), "#!/bin/sh\n")
+	writeShimFile(t, filepath.Join(root, "version"), "3.11.7\n")
+	real := writeShimFile(t, filepath.Join(root, "versions", "3.11.7", "bin", "python"), "")
+
+	info := ClassifyToolPath(shim)
+	if info.Manager != ManagerPyenv || !info.IsShim || info.Version != "3.11.7" || info.RealPath != real || info.Missing {
+		t.Errorf("global version: %+v", info)
+	}
+
+	// .python-version in the project wins over the global version, and a
+	// version that is not installed is reported as missing.
+	writeShimFile(t, filepath.Join(env.project, ".python-version"), "3.12.1\n3.11.7\n")
+	info = ClassifyToolPath(shim)
+	if info.Version != "3.12.1" || !info.Missing || info.Suggestion != "Run 'pyenv install 3.12.1'" {
+		t.Errorf("local version: %+v", info)
+	}
+
+	// PYENV_VERSION wins over both.
+	t.Setenv("PYENV_VERSION", "system:3.11.7")
+	info = ClassifyToolPath(shim)
+	if info.Version != "system" || info.RealPath != "" || info.Missing {
+		t.Errorf("PYENV_VERSION=system: %+v", info)
+	}
+}
+
+func TestClassifyToolPath_AsdfShim(t *testing.T) {
+	env := newShimEnv(t)
+	root := filepath.Join(env.home, ".asdf")
+	shim := writeShimFile(t, filepath.Join(root, "shims", "node"), "#!/bin/sh\n")
+	writeShimFile(t, filepath.Join(env.home, ".tool-versions"), "nodejs 18.19.0\npython 3.12.1\n")
+
+	info := ClassifyToolPath(shim)
+	if info.Manager != ManagerAsdf || !info.IsShim || info.Version != "18.19.0" || !info.Missing {
+		t.Errorf("home .tool-versions: %+v", info)
+	}
+	if info.Suggestion != "Run 'asdf install nodejs 18.19.0'" {
+		t.Errorf("Suggestion = %q", info.Suggestion)
+	}
+
+	writeShimFile(t, filepath.Join(env.project, ".tool-versions"), "# pinned\nnodejs 20.11.0 # comment\n")
+	real := writeShimFile(t, filepath.Join(root, "installs", "nodejs", "20.11.0", "bin", "node"), "")
+	info = ClassifyToolPath(shim)
+	if info.Version != "20.11.0" || info.RealPath != real || info.Missing {
+		t.Errorf("project .tool-versions: %+v", info)
+	}
+
+	t.Setenv("ASDF_NODEJS_VERSION", "21.0.0")
+	if info := ClassifyToolPath(shim); info.Version != "21.0.0" {
+		t.Errorf("ASDF_NODEJS_VERSION: %+v", info)
+	}
+
+	if info := ClassifyToolPath(real); info.IsShim || info.Version != "20.11.0" {
+		t.Errorf("asdf install path: %+v", info)
+	}
+}
+
+func TestClassifyToolPath_VoltaShim(t *testing.T) {
+	env := newShimEnv(t)
+	root := filepath.Join(env.home, ".volta")
+	shim := writeShimFile(t, filepath.Join(root, "bin", "node"), "")
+	writeShimFile(t, filepath.Join(root, "tools", "user", "platform.json"), `{"node":{"runtime":"20.11.0","npm":null}}`)
+	real := writeShimFile(t, filepath.Join(root, "tools", "image", "node", "20.11.0", "bin", "node"), "")
+
+	info := ClassifyToolPath(shim)
+	if info.Manager != ManagerVolta || !info.IsShim || info.Version != "20.11.0" || info.RealPath != real || info.Missing {
+		t.Errorf("default platform: %+v", info)
+	}
+
+	writeShimFile(t, filepath.Join(env.project, "package.json"), `{"name":"web","volta":{"node":"18.19.0"}}`)
+	info = ClassifyToolPath(shim)
+	if info.Version != "18.19.0" || !info.Missing || info.Suggestion != "Run 'volta install node@18.19.0'" {
+		t.Errorf("package.json pin: %+v", info)
+	}
+}
+
+func TestGetInstallSuggestion_VersionManagers(t *testing.T) {
+	env := newShimEnv(t)
+	root := filepath.Join(env.home, ".pyenv")
+	writeShimFile(t, filepath.Join(root, "shims", "pyenvtool-xyz"), "#!/bin/sh\n")
+	writeShimFile(t, filepath.Join(root, "version"), "3.99.0\n")
+	t.Setenv("PATH", filepath.Join(root, "shims"))
+
+	// The shim is on PATH but its selected version is not installed.
+	if got := GetInstallSuggestion("pyenvtool-xyz"); got != "Run 'pyenv install 3.99.0'" {
+		t.Errorf("GetInstallSuggestion(missing pyenv version) = %q", got)
+	}
+
+	// Not on PATH, but nvm is set up: suggest the .nvmrc version.
+	t.Setenv("NVM_DIR", filepath.Join(env.home, ".nvm"))
+	writeShimFile(t, filepath.Join(env.project, ".nvmrc"), "v20\n")
+	if got := GetInstallSuggestion("node"); got != "Run 'nvm install 20' and 'nvm use 20'" {
+		t.Errorf("GetInstallSuggestion(node with nvm) = %q", got)
+	}
+
+	// Without any manager the generic suggestion is returned.
+	t.Setenv("NVM_DIR", "")
+	if got := GetInstallSuggestion("node"); !strings.Contains(got, "nodejs.org") {
+		t.Errorf("GetInstallSuggestion(node) = %q", got)
+	}
+}
+
+func TestGetInstallSuggestion_Asdf(t *testing.T) {
+	env := newShimEnv(t)
+	root := filepath.Join(env.home, ".asdf")
+	t.Setenv("ASDF_DATA_DIR", root)
+	t.Setenv("PATH", t.TempDir())
+
+	// Tools asdf has no plugin or pin for keep their install URLs.
+	for tool, want := range map[string]string{"docker": "docker.com", "azd": "aka.ms/install-azd"} {
+		if got := GetInstallSuggestion(tool); !strings.Contains(got, want) {
+			t.Errorf("GetInstallSuggestion(%s) = %q, want the install URL", tool, got)
+		}
+	}
+
+	writeShimFile(t, filepath.Join(env.project, ".tool-versions"), "nodejs 20.11.0\n")
+	if got := GetInstallSuggestion("node"); got != "Run 'asdf install nodejs 20.11.0'" {
+		t.Errorf("GetInstallSuggestion(pinned node) = %q", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "plugins", "golang"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetInstallSuggestion("go"); got != "Run 'asdf install golang latest'" {
+		t.Errorf("GetInstallSuggestion(go with plugin) = %q", got)
+	}
+}
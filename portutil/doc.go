@@ -0,0 +1,18 @@
+// Package portutil provides TCP port allocation and readiness utilities for
+// Azure Developer CLI extensions.
+//
+// Use GetFreePort or GetFreePortInRange to pick a port for a local server,
+// IsPortAvailable to check a configured port before binding, and WaitForPort
+// to block until a started service accepts connections.
+//
+// Example:
+//
+//	port, err := portutil.GetFreePortInRange(3000, 3100)
+//	if err != nil {
+//	    return err
+//	}
+//	startDevServer(port)
+//	if err := portutil.WaitForPort(ctx, "localhost", port, 30*time.Second); err != nil {
+//	    return fmt.Errorf("dev server did not start: %w", err)
+//	}
+package portutil
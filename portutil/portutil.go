@@ -0,0 +1,128 @@
+package portutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// MinPort is the lowest valid TCP port.
+	MinPort = 1
+	// MaxPort is the highest valid TCP port.
+	MaxPort = 65535
+
+	// initialWaitInterval is the first delay between WaitForPort dial attempts.
+	initialWaitInterval = 50 * time.Millisecond
+	// maxWaitInterval caps the backoff between WaitForPort dial attempts.
+	maxWaitInterval = time.Second
+	// dialTimeout bounds each WaitForPort dial attempt.
+	dialTimeout = time.Second
+)
+
+// ErrNoFreePort is returned when no port in a requested range is available.
+var ErrNoFreePort = errors.New("no free port available")
+
+// loopback is the address used for allocation and availability checks.
+const loopback = "127.0.0.1"
+
+// loopbackIPv6 is also probed by IsPortAvailable.
+const loopbackIPv6 = "::1"
+
+// GetFreePort returns a TCP port on the loopback interface that was free at
+// the time of the call. The port is released before returning, so another
+// process could claim it before the caller binds it.
+func GetFreePort() (int, error) {
+	ln, err := net.Listen("tcp", net.JoinHostPort(loopback, "0"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate port: %w", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	if err := ln.Close(); err != nil {
+		return 0, fmt.Errorf("failed to release port %d: %w", port, err)
+	}
+	return port, nil
+}
+
+// GetFreePortInRange returns the first available port between minPort and
+// maxPort, inclusive. It returns ErrNoFreePort if every port is in use.
+func GetFreePortInRange(minPort, maxPort int) (int, error) {
+	if minPort < MinPort || maxPort > MaxPort || minPort > maxPort {
+		return 0, fmt.Errorf("invalid port range %d-%d", minPort, maxPort)
+	}
+	for port := minPort; port <= maxPort; port++ {
+		if IsPortAvailable(port) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("%w in range %d-%d", ErrNoFreePort, minPort, maxPort)
+}
+
+// IsPortAvailable reports whether port can be bound on the IPv4 loopback,
+// the wildcard address, and (when the host supports IPv6) the IPv6 loopback.
+// A server listening on any of them, such as a dev server bound to [::1] or
+// 0.0.0.0, makes the port unavailable. Invalid port numbers are reported as
+// unavailable.
+func IsPortAvailable(port int) bool {
+	if port < MinPort || port > MaxPort {
+		return false
+	}
+	hosts := []string{loopback, ""}
+	if hasIPv6Loopback() {
+		hosts = append(hosts, loopbackIPv6)
+	}
+	for _, host := range hosts {
+		ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			return false
+		}
+		_ = ln.Close()
+	}
+	return true
+}
+
+// hasIPv6Loopback reports whether the IPv6 loopback can be bound at all, so
+// hosts without IPv6 do not report every port as unavailable.
+var hasIPv6Loopback = sync.OnceValue(func() bool {
+	ln, err := net.Listen("tcp6", net.JoinHostPort(loopbackIPv6, "0"))
+	if err != nil {
+		return false
+	}
+	_ = ln.Close()
+	return true
+})
+
+// WaitForPort blocks until host:port accepts TCP connections, timeout elapses,
+// or ctx is canceled. Dial attempts back off exponentially from 50ms up to 1s.
+func WaitForPort(ctx context.Context, host string, port int, timeout time.Duration) error {
+	if port < MinPort || port > MaxPort {
+		return fmt.Errorf("invalid port %d", port)
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	dialer := net.Dialer{Timeout: dialTimeout}
+	interval := initialWaitInterval
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err == nil {
+			_ = conn.Close()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s: %w", address, ctx.Err())
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxWaitInterval)
+	}
+}
@@ -0,0 +1,140 @@
+package portutil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func listen(t *testing.T, port int) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", net.JoinHostPort(loopback, strconv.Itoa(port)))
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	return ln
+}
+
+func TestGetFreePort(t *testing.T) {
+	port, err := GetFreePort()
+	if err != nil {
+		t.Fatalf("GetFreePort: %v", err)
+	}
+	if port < MinPort || port > MaxPort {
+		t.Errorf("port %d out of range", port)
+	}
+	if !IsPortAvailable(port) {
+		t.Errorf("expected port %d to be available", port)
+	}
+}
+
+func TestIsPortAvailable(t *testing.T) {
+	ln := listen(t, 0)
+	port := ln.Addr().(*net.TCPAddr).Port
+	if IsPortAvailable(port) {
+		t.Errorf("expected bound port %d to be unavailable", port)
+	}
+
+	for _, invalid := range []int{0, -1, 65536} {
+		if IsPortAvailable(invalid) {
+			t.Errorf("expected invalid port %d to be unavailable", invalid)
+		}
+	}
+}
+
+func TestIsPortAvailable_OtherAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		host    string
+	}{
+		{"wildcard", "tcp", ""},
+		{"ipv6 loopback", "tcp6", loopbackIPv6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := net.Listen(tt.network, net.JoinHostPort(tt.host, "0"))
+			if err != nil {
+				t.Skipf("cannot listen on %q: %v", tt.host, err)
+			}
+			defer ln.Close()
+			port := ln.Addr().(*net.TCPAddr).Port
+			if IsPortAvailable(port) {
+				t.Errorf("expected port %d bound on %q to be unavailable", port, tt.host)
+			}
+		})
+	}
+}
+
+func TestGetFreePortInRange(t *testing.T) {
+	ln := listen(t, 0)
+	busy := ln.Addr().(*net.TCPAddr).Port
+
+	if _, err := GetFreePortInRange(busy, busy); !errors.Is(err, ErrNoFreePort) {
+		t.Errorf("expected ErrNoFreePort, got %v", err)
+	}
+
+	free, err := GetFreePort()
+	if err != nil {
+		t.Fatalf("GetFreePort: %v", err)
+	}
+	got, err := GetFreePortInRange(free, free)
+	if err != nil || got != free {
+		t.Errorf("GetFreePortInRange(%d, %d) = %d, %v", free, free, got, err)
+	}
+
+	for _, r := range [][2]int{{0, 10}, {10, 5}, {65000, 70000}} {
+		if _, err := GetFreePortInRange(r[0], r[1]); err == nil {
+			t.Errorf("expected error for range %v", r)
+		}
+	}
+}
+
+func TestWaitForPort(t *testing.T) {
+	port, err := GetFreePort()
+	if err != nil {
+		t.Fatalf("GetFreePort: %v", err)
+	}
+
+	listeners := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		ln, err := net.Listen("tcp", net.JoinHostPort(loopback, strconv.Itoa(port)))
+		if err != nil {
+			close(listeners)
+			return
+		}
+		listeners <- ln
+	}()
+
+	if err := WaitForPort(context.Background(), loopback, port, 5*time.Second); err != nil {
+		t.Errorf("WaitForPort: %v", err)
+	}
+	if ln, ok := <-listeners; ok {
+		_ = ln.Close()
+	}
+}
+
+func TestWaitForPort_Timeout(t *testing.T) {
+	port, err := GetFreePort()
+	if err != nil {
+		t.Fatalf("GetFreePort: %v", err)
+	}
+
+	start := time.Now()
+	err = WaitForPort(context.Background(), loopback, port, 300*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("WaitForPort exceeded timeout: %v", elapsed)
+	}
+
+	if err := WaitForPort(context.Background(), loopback, 0, time.Second); err == nil {
+		t.Error("expected error for invalid port")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// Spec describes a process to launch with StartDetached.
+type Spec struct {
+	// Cmd is the executable name or path.
+	Cmd string
+	// Args are the command arguments.
+	Args []string
+	// Dir is the working directory. Empty means the current directory.
+	Dir string
+	// Env holds extra "KEY=value" entries appended to the parent environment.
+	Env []string
+	// LogFile receives the process's stdout and stderr, appended. Its parent
+	// directory is created if needed. Empty discards output.
+	LogFile string
+}
+
+// StartDetached launches spec as a process that outlives the caller. On Unix
+// the process runs in a new session (setsid); on Windows it is created with
+// DETACHED_PROCESS and CREATE_NEW_PROCESS_GROUP so it has no console and
+// does not receive the parent's Ctrl+C. Stdin is connected to the null device.
+//
+// ctx only governs the launch; canceling it later does not stop the process.
+// The returned Handle is verified with Capture, so an error is returned if
+// the process exits before it can be identified.
+//
+// Example:
+//
+//	h, err := procutil.StartDetached(ctx, procutil.Spec{
+//	    Cmd:     "node",
+//	    Args:    []string{"server.js"},
+//	    Dir:     serviceDir,
+//	    LogFile: filepath.Join(logsDir, "api.log"),
+//	})
+func StartDetached(ctx context.Context, spec Spec) (Handle, error) {
+	if spec.Cmd == "" {
+		return Handle{}, errors.New("command cannot be empty")
+	}
+	if err := ctx.Err(); err != nil {
+		return Handle{}, err
+	}
+
+	// #nosec G204 -- launching caller-specified commands is the purpose of this function
+	cmd := exec.Command(spec.Cmd, spec.Args...)
+	cmd.Dir = spec.Dir
+	cmd.Env = append(os.Environ(), spec.Env...)
+	cmd.SysProcAttr = detachedSysProcAttr()
+
+	if spec.LogFile != "" {
+		if err := fileutil.EnsureDir(filepath.Dir(spec.LogFile)); err != nil {
+			return Handle{}, err
+		}
+		// #nosec G304 -- log file path is provided by the caller
+		logFile, err := os.OpenFile(spec.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileutil.FilePermission)
+		if err != nil {
+			return Handle{}, fmt.Errorf("failed to open log file: %w", err)
+		}
+		// The child has its own copy of the descriptor once started.
+		defer func() { _ = logFile.Close() }()
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+	}
+
+	if err := cmd.Start(); err != nil {
+		return Handle{}, fmt.Errorf("failed to start %s: %w", spec.Cmd, err)
+	}
+	pid := cmd.Process.Pid
+
+	// Reap the child if it exits while this process is still alive so it
+	// does not linger as a zombie.
+	go func() { _ = cmd.Wait() }()
+
+	h, err := Capture(pid)
+	if err != nil {
+		msg := fmt.Sprintf("process %d exited immediately", pid)
+		if spec.LogFile != "" {
+			msg += "; see " + spec.LogFile
+		}
+		return Handle{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	return h, nil
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import "syscall"
+
+// detachedSysProcAttr starts the child in a new session, detaching it from
+// the parent's controlling terminal and process group.
+func detachedSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
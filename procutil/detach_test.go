@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDetachedHelperProcess is not a real test. StartDetached tests launch the
+// test binary with PROCUTIL_DETACHED_HELPER=1 to get a portable child process.
+func TestDetachedHelperProcess(t *testing.T) {
+	if os.Getenv("PROCUTIL_DETACHED_HELPER") != "1" {
+		return
+	}
+	wd, _ := os.Getwd()
+	fmt.Printf("cwd=%s\n", wd)
+	fmt.Fprintf(os.Stderr, "value=%s\n", os.Getenv("DETACHED_VALUE"))
+	time.Sleep(30 * time.Second)
+	os.Exit(0)
+}
+
+func TestStartDetached(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "logs", "svc.log")
+
+	h, err := StartDetached(context.Background(), Spec{
+		Cmd:     os.Args[0],
+		Args:    []string{"-test.run=^TestDetachedHelperProcess$"},
+		Dir:     dir,
+		Env:     []string{"PROCUTIL_DETACHED_HELPER=1", "DETACHED_VALUE=hello"},
+		LogFile: logFile,
+	})
+	if err != nil {
+		t.Fatalf("StartDetached: %v", err)
+	}
+	t.Cleanup(func() {
+		if p, err := os.FindProcess(h.PID); err == nil {
+			_ = p.Kill()
+		}
+	})
+
+	if !h.StillRunning() {
+		t.Fatalf("expected %s to be running", h)
+	}
+
+	var output string
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		data, _ := os.ReadFile(logFile)
+		output = string(data)
+		if strings.Contains(output, "cwd=") && strings.Contains(output, "value=") {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !strings.Contains(output, "value=hello") {
+		t.Errorf("log missing environment value:\n%s", output)
+	}
+	wantDir, _ := filepath.EvalSymlinks(dir)
+	if got := evalLogDir(output); got != wantDir {
+		t.Errorf("working directory = %q, want %q", got, wantDir)
+	}
+}
+
+// evalLogDir extracts and resolves the cwd= line written by the helper.
+func evalLogDir(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if cwd, ok := strings.CutPrefix(strings.TrimSpace(line), "cwd="); ok {
+			if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+				return resolved
+			}
+			return cwd
+		}
+	}
+	return ""
+}
+
+func TestStartDetachedErrors(t *testing.T) {
+	if _, err := StartDetached(context.Background(), Spec{}); err == nil {
+		t.Error("expected error for empty command")
+	}
+	if _, err := StartDetached(context.Background(), Spec{Cmd: "definitely-not-a-real-command-xyz"}); err == nil {
+		t.Error("expected error for missing executable")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := StartDetached(ctx, Spec{Cmd: os.Args[0]}); err == nil {
+		t.Error("expected error for canceled context")
+	}
+}
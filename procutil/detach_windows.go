@@ -0,0 +1,21 @@
+//go:build windows
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// detachedSysProcAttr starts the child without a console in its own process
+// group so it survives the parent and ignores the parent's Ctrl+C.
+func detachedSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
+	}
+}
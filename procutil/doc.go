@@ -10,6 +10,15 @@
 //   - Reliable process existence validation using gopsutil
 //   - Handles stale PIDs correctly on Windows
 //   - Consistent behavior across all supported platforms
+//   - Start-time-verified process handles that detect PID reuse (Capture, Handle)
+//   - PID files with stale detection (WritePIDFile, ReadPIDFile, IsPIDFileStale)
+//   - Detached process launching with log file redirection (StartDetached)
+//   - Per-process CPU and resident memory sampling (GetUsage, GetUsageOver)
+//   - Listening TCP ports, names, and child process trees (ListeningPorts, ProcessName, Descendants)
+//   - Finding processes by name, command line pattern, and owner (FindProcesses)
+//   - Environment and working directory inspection (GetProcessEnv, GetProcessCwd)
+//   - Portable signals and config reload requests (Signal, ReloadProcess, NotifyReload)
+//   - Reaping exited child processes on Unix (ReapChildren, StartReaper)
 //
 // # Implementation
 //
@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Filter selects processes for FindProcesses. Empty fields match every
+// process; set fields must all match.
+type Filter struct {
+	// NameContains matches processes whose executable name contains this
+	// string, ignoring case, for example "func" or "node".
+	NameContains string
+	// CmdlineRegex matches processes whose full command line matches this
+	// regular expression, for example `vite.*--port 5173`.
+	CmdlineRegex string
+	// OwnerIsCurrentUser limits the results to processes owned by the
+	// current user, which are the ones it can usually stop.
+	OwnerIsCurrentUser bool
+}
+
+// ProcessInfo describes a process found by FindProcesses.
+type ProcessInfo struct {
+	PID  int    `json:"pid"`
+	Name string `json:"name"`
+	// Cmdline is the full command line, or empty if it could not be read.
+	Cmdline string `json:"cmdline,omitempty"`
+}
+
+// FindProcesses returns the running processes that match f, sorted by PID,
+// so extensions can detect stray dev servers and offer to stop them. The
+// calling process is never included. Processes that exit during the scan,
+// or whose details cannot be read for a set filter field, are skipped. An
+// invalid CmdlineRegex returns an error.
+//
+// Example:
+//
+//	procs, err := procutil.FindProcesses(ctx, procutil.Filter{
+//	    NameContains:       "func",
+//	    CmdlineRegex:       `\bhost start\b`,
+//	    OwnerIsCurrentUser: true,
+//	})
+//	if err == nil && len(procs) > 0 {
+//	    cliout.Warning("Functions host already running (pid %d)", procs[0].PID)
+//	}
+func FindProcesses(ctx context.Context, f Filter) ([]ProcessInfo, error) {
+	var cmdlineRe *regexp.Regexp
+	if f.CmdlineRegex != "" {
+		re, err := regexp.Compile(f.CmdlineRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command line pattern %q: %w", f.CmdlineRegex, err)
+		}
+		cmdlineRe = re
+	}
+
+	var isOwner func(*process.Process) bool
+	if f.OwnerIsCurrentUser {
+		owner, err := currentOwnerMatcher(ctx)
+		if err != nil {
+			return nil, err
+		}
+		isOwner = owner
+	}
+
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	self := int32(os.Getpid())
+	nameContains := strings.ToLower(f.NameContains)
+	var found []ProcessInfo
+	for _, proc := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if proc.Pid == self {
+			continue
+		}
+
+		// Cheapest checks first: listing all processes is common, reading
+		// each command line is not free.
+		name, err := proc.NameWithContext(ctx)
+		if err != nil && nameContains != "" {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(name), nameContains) {
+			continue
+		}
+		if isOwner != nil && !isOwner(proc) {
+			continue
+		}
+		cmdline, err := proc.CmdlineWithContext(ctx)
+		if err != nil && cmdlineRe != nil {
+			continue
+		}
+		if cmdlineRe != nil && !cmdlineRe.MatchString(cmdline) {
+			continue
+		}
+
+		found = append(found, ProcessInfo{PID: int(proc.Pid), Name: name, Cmdline: cmdline})
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].PID < found[j].PID })
+	return found, nil
+}
+
+// currentOwnerMatcher returns a func reporting whether a process belongs to
+// the current user: by real UID on Unix and by account name on Windows,
+// where processes have no UIDs.
+func currentOwnerMatcher(ctx context.Context) (func(*process.Process) bool, error) {
+	if runtime.GOOS != "windows" {
+		uid := int64(os.Getuid())
+		return func(proc *process.Process) bool {
+			uids, err := proc.UidsWithContext(ctx)
+			return err == nil && len(uids) > 0 && int64(uids[0]) == uid
+		}, nil
+	}
+
+	current, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine current user: %w", err)
+	}
+	return func(proc *process.Process) bool {
+		name, err := proc.UsernameWithContext(ctx)
+		return err == nil && strings.EqualFold(name, current.Username)
+	}, nil
+}
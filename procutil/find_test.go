@@ -0,0 +1,88 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+func foundPIDs(procs []ProcessInfo) []int {
+	pids := make([]int, len(procs))
+	for i, p := range procs {
+		pids[i] = p.PID
+	}
+	return pids
+}
+
+func TestFindProcesses(t *testing.T) {
+	proc := testutil.StartDummyProcess(t)
+	name, pattern := "sleep", `\b300\b`
+	if runtime.GOOS == "windows" {
+		name, pattern = "powershell", `Start-Sleep -Seconds 300`
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"name", Filter{NameContains: name}, true},
+		{"name ignores case", Filter{NameContains: strings.ToUpper(name)}, true},
+		{"cmdline", Filter{CmdlineRegex: pattern}, true},
+		{"all fields", Filter{NameContains: name, CmdlineRegex: pattern, OwnerIsCurrentUser: true}, true},
+		{"other name", Filter{NameContains: "no-such-process-name"}, false},
+		{"other cmdline", Filter{NameContains: name, CmdlineRegex: `--no-such-flag`}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			procs, err := FindProcesses(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("FindProcesses() error = %v", err)
+			}
+			pids := foundPIDs(procs)
+			if got := slices.Contains(pids, proc.PID); got != tt.want {
+				t.Errorf("FindProcesses(%+v) = %v, contains %d = %v, want %v", tt.filter, pids, proc.PID, got, tt.want)
+			}
+			if !slices.IsSorted(pids) {
+				t.Errorf("FindProcesses() = %v, want sorted by PID", pids)
+			}
+		})
+	}
+}
+
+func TestFindProcessesExcludesSelf(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot determine test executable:", err)
+	}
+	procs, err := FindProcesses(context.Background(), Filter{NameContains: strings.TrimSuffix(filepath.Base(exe), ".exe")})
+	if err != nil {
+		t.Fatalf("FindProcesses() error = %v", err)
+	}
+	if slices.Contains(foundPIDs(procs), os.Getpid()) {
+		t.Error("FindProcesses() included the calling process")
+	}
+}
+
+func TestFindProcessesInvalidPattern(t *testing.T) {
+	if _, err := FindProcesses(context.Background(), Filter{CmdlineRegex: "("}); err == nil {
+		t.Error("FindProcesses() with an invalid pattern succeeded, want error")
+	}
+}
+
+func TestFindProcessesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := FindProcesses(ctx, Filter{}); err == nil {
+		t.Error("FindProcesses() with a canceled context succeeded, want error")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Handle identifies a specific process instance. Operating systems reuse
+// PIDs, so a PID alone can refer to an unrelated process after the original
+// exits (a common problem on Windows). Handle pairs the PID with the
+// process start time so that reuse can be detected.
+//
+// Handle marshals to JSON as {"pid": 1234, "startTime": "..."} and can be
+// persisted in service registries.
+type Handle struct {
+	PID       int       `json:"pid"`
+	StartTime time.Time `json:"startTime"`
+}
+
+// Capture returns a Handle for the running process with the given PID.
+// It returns an error wrapping ErrProcessNotFound if no such process exists.
+//
+// Example:
+//
+//	h, err := procutil.Capture(cmd.Process.Pid)
+//	if err != nil {
+//	    return err
+//	}
+//	registry.Save(name, h)
+//	// Later, possibly in another azd invocation:
+//	if !h.StillRunning() {
+//	    fmt.Println("service stopped")
+//	}
+func Capture(pid int) (Handle, error) {
+	start, err := startTime(pid)
+	if err != nil {
+		return Handle{}, err
+	}
+	return Handle{PID: pid, StartTime: start}, nil
+}
+
+// StillRunning reports whether the process identified by h is running: a
+// process with h.PID exists and it started at h.StartTime. A zero Handle is
+// never running.
+func (h Handle) StillRunning() bool {
+	if h.PID <= 0 || h.StartTime.IsZero() {
+		return false
+	}
+	if !IsProcessRunning(h.PID) {
+		return false
+	}
+	start, err := startTime(h.PID)
+	if err != nil {
+		return false
+	}
+	return start.Equal(h.StartTime)
+}
+
+// String returns the handle as "pid 1234 (started 2024-01-02T15:04:05Z)".
+func (h Handle) String() string {
+	return fmt.Sprintf("pid %d (started %s)", h.PID, h.StartTime.UTC().Format(time.RFC3339))
+}
+
+// startTime returns the start time of pid at millisecond precision.
+func startTime(pid int) (time.Time, error) {
+	ctx := context.Background()
+	proc, err := findProcess(ctx, pid)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ms, err := proc.CreateTimeWithContext(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read start time for pid %d: %w", pid, err)
+	}
+	return time.UnixMilli(ms).UTC(), nil
+}
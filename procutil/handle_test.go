@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+func TestCaptureCurrentProcess(t *testing.T) {
+	h, err := Capture(os.Getpid())
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if h.PID != os.Getpid() || h.StartTime.IsZero() {
+		t.Fatalf("unexpected handle %+v", h)
+	}
+	if h.StartTime.After(time.Now()) {
+		t.Errorf("start time %v is in the future", h.StartTime)
+	}
+	if !h.StillRunning() {
+		t.Error("expected current process handle to be running")
+	}
+}
+
+func TestCaptureMissingProcess(t *testing.T) {
+	if _, err := Capture(testutil.DeadPID(t)); !errors.Is(err, ErrProcessNotFound) {
+		t.Errorf("expected ErrProcessNotFound, got %v", err)
+	}
+}
+
+func TestHandleStillRunning(t *testing.T) {
+	h, err := Capture(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		handle Handle
+		want   bool
+	}{
+		{"zero handle", Handle{}, false},
+		{"missing start time", Handle{PID: h.PID}, false},
+		{"reused pid", Handle{PID: h.PID, StartTime: h.StartTime.Add(-time.Hour)}, false},
+		{"dead pid", Handle{PID: testutil.DeadPID(t), StartTime: h.StartTime}, false},
+		{"matching", h, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.handle.StillRunning(); got != tt.want {
+				t.Errorf("StillRunning() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleJSONRoundTrip(t *testing.T) {
+	h, err := Capture(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"pid":`) || !strings.Contains(string(data), `"startTime":`) {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	var restored Handle
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if !restored.StillRunning() {
+		t.Errorf("restored handle %s not running", restored)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jongio/azd-core/fileutil"
+	"github.com/jongio/azd-core/security"
+)
+
+// WritePIDFile atomically writes a PID file for the current process at path,
+// creating parent directories as needed. The file holds a JSON Handle so
+// readers can detect PID reuse.
+func WritePIDFile(path string) error {
+	h, err := Capture(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return WritePIDFileFor(path, h)
+}
+
+// WritePIDFileFor atomically writes a PID file for h, typically a Handle
+// returned by StartDetached.
+//
+// Example:
+//
+//	h, err := procutil.StartDetached(ctx, spec)
+//	if err != nil {
+//	    return err
+//	}
+//	return procutil.WritePIDFileFor(filepath.Join(runDir, "api.pid"), h)
+func WritePIDFileFor(path string, h Handle) error {
+	if h.PID <= 0 {
+		return fmt.Errorf("invalid pid %d", h.PID)
+	}
+	if err := fileutil.EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := fileutil.AtomicWriteJSON(path, h); err != nil {
+		return fmt.Errorf("failed to write PID file: %w", err)
+	}
+	return nil
+}
+
+// ReadPIDFile reads the Handle stored in the PID file at path. Files
+// containing only a decimal PID, as written by other tools, are accepted and
+// return a Handle with a zero StartTime.
+//
+// The path is validated with security.ValidatePath, and a file that is
+// writable by group or others is rejected with an error wrapping
+// security.ErrInsecureFilePermissions, since its contents could direct
+// callers to signal an arbitrary process. A missing file returns an error
+// satisfying errors.Is(err, fs.ErrNotExist).
+func ReadPIDFile(path string) (Handle, error) {
+	if err := security.ValidatePath(path); err != nil {
+		return Handle{}, err
+	}
+
+	// #nosec G304 -- path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Handle{}, fmt.Errorf("failed to read PID file: %w", err)
+	}
+	if err := security.ValidateFilePermissions(path); err != nil {
+		return Handle{}, fmt.Errorf("PID file %s: %w", path, err)
+	}
+
+	text := strings.TrimSpace(string(data))
+	if pid, err := strconv.Atoi(text); err == nil {
+		if pid <= 0 {
+			return Handle{}, fmt.Errorf("invalid pid %d in PID file %s", pid, path)
+		}
+		return Handle{PID: pid}, nil
+	}
+
+	var h Handle
+	if err := json.Unmarshal(data, &h); err != nil {
+		return Handle{}, fmt.Errorf("failed to parse PID file %s: %w", path, err)
+	}
+	if h.PID <= 0 {
+		return Handle{}, fmt.Errorf("invalid pid %d in PID file %s", h.PID, path)
+	}
+	return h, nil
+}
+
+// IsPIDFileStale reports whether the PID file at path refers to a process
+// that is no longer running. Handles with a start time are verified with
+// Handle.StillRunning; plain PIDs fall back to IsProcessRunning. A missing
+// PID file is not stale.
+//
+// Example:
+//
+//	if stale, err := procutil.IsPIDFileStale(pidFile); err == nil && stale {
+//	    _ = os.Remove(pidFile)
+//	}
+func IsPIDFileStale(path string) (bool, error) {
+	h, err := ReadPIDFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if h.StartTime.IsZero() {
+		return !IsProcessRunning(h.PID), nil
+	}
+	return !h.StillRunning(), nil
+}
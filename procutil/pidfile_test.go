@@ -0,0 +1,122 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/security"
+	"github.com/jongio/azd-core/testutil"
+)
+
+func TestWriteAndReadPIDFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "ext.pid")
+	if err := WritePIDFile(path); err != nil {
+		t.Fatalf("WritePIDFile: %v", err)
+	}
+
+	h, err := ReadPIDFile(path)
+	if err != nil {
+		t.Fatalf("ReadPIDFile: %v", err)
+	}
+	if h.PID != os.Getpid() || h.StartTime.IsZero() {
+		t.Errorf("unexpected handle %+v", h)
+	}
+
+	stale, err := IsPIDFileStale(path)
+	if err != nil || stale {
+		t.Errorf("IsPIDFileStale = %v, %v; want false, nil", stale, err)
+	}
+}
+
+func TestIsPIDFileStale(t *testing.T) {
+	dir := t.TempDir()
+	self, err := Capture(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := testutil.DeadPID(t)
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	reused := filepath.Join(dir, "reused.pid")
+	if err := WritePIDFileFor(reused, Handle{PID: self.PID, StartTime: self.StartTime.Add(-time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"missing", filepath.Join(dir, "missing.pid"), false},
+		{"plain running pid", write("plain.pid", strconv.Itoa(os.Getpid())+"\n"), false},
+		{"plain dead pid", write("dead.pid", strconv.Itoa(dead)), true},
+		{"reused pid", reused, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsPIDFileStale(tt.path)
+			if err != nil {
+				t.Fatalf("IsPIDFileStale: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsPIDFileStale = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPIDFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := ReadPIDFile(filepath.Join(dir, "missing.pid")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: got %v, want fs.ErrNotExist", err)
+	}
+	if _, err := ReadPIDFile("../escape.pid"); !errors.Is(err, security.ErrPathTraversal) {
+		t.Errorf("traversal: got %v, want ErrPathTraversal", err)
+	}
+
+	for name, content := range map[string]string{"garbage.pid": "not a pid", "zero.pid": "0", "json.pid": `{"pid": -4}`} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadPIDFile(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if err := WritePIDFileFor(filepath.Join(dir, "bad.pid"), Handle{}); err == nil {
+		t.Error("expected error writing zero handle")
+	}
+}
+
+func TestReadPIDFileInsecurePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not enforced on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "open.pid")
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadPIDFile(path); !errors.Is(err, security.ErrInsecureFilePermissions) {
+		t.Errorf("got %v, want ErrInsecureFilePermissions", err)
+	}
+}
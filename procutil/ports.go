@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+// ListeningPorts returns the TCP ports the process with the given PID is
+// listening on, sorted and without duplicates. A port bound on both IPv4
+// and IPv6 is reported once. It returns an error wrapping ErrProcessNotFound
+// if no such process exists.
+//
+// Example:
+//
+//	ports, err := procutil.ListeningPorts(ctx, cmd.Process.Pid)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("listening on %v\n", ports)
+func ListeningPorts(ctx context.Context, pid int) ([]int, error) {
+	if _, err := findProcess(ctx, pid); err != nil {
+		return nil, err
+	}
+
+	conns, err := net.ConnectionsPidWithContext(ctx, "tcp", int32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connections for process %d: %w", pid, err)
+	}
+
+	seen := make(map[int]bool)
+	var ports []int
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" || conn.Laddr.Port == 0 {
+			continue
+		}
+		port := int(conn.Laddr.Port)
+		if !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	sort.Ints(ports)
+	return ports, nil
+}
+
+// Descendants returns the PIDs of all children of the process with the
+// given PID, recursively, in breadth-first order. The process itself is not
+// included. It returns an error wrapping ErrProcessNotFound if no such
+// process exists.
+func Descendants(ctx context.Context, pid int) ([]int, error) {
+	root, err := findProcess(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	seen := map[int32]bool{root.Pid: true}
+	queue := []int32{root.Pid}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		proc, err := findProcess(ctx, int(queue[0]))
+		queue = queue[1:]
+		if err != nil {
+			// The process exited while walking the tree.
+			continue
+		}
+		children, err := proc.ChildrenWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			if seen[child.Pid] {
+				continue
+			}
+			seen[child.Pid] = true
+			pids = append(pids, int(child.Pid))
+			queue = append(queue, child.Pid)
+		}
+	}
+	return pids, nil
+}
+
+// ProcessName returns the executable name of the process with the given PID,
+// such as "node" or "python.exe". It returns an error wrapping
+// ErrProcessNotFound if no such process exists.
+func ProcessName(ctx context.Context, pid int) (string, error) {
+	proc, err := findProcess(ctx, pid)
+	if err != nil {
+		return "", err
+	}
+	name, err := proc.NameWithContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get name of process %d: %w", pid, err)
+	}
+	return name, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+func TestListeningPortsCurrentProcess(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	ports, err := ListeningPorts(context.Background(), os.Getpid())
+	if err != nil {
+		t.Fatalf("ListeningPorts: %v", err)
+	}
+	if !slices.Contains(ports, port) {
+		t.Errorf("ListeningPorts = %v, want it to contain %d", ports, port)
+	}
+	if !slices.IsSorted(ports) {
+		t.Errorf("ListeningPorts = %v, want sorted", ports)
+	}
+}
+
+func TestListeningPortsMissingProcess(t *testing.T) {
+	for _, pid := range []int{0, -1, testutil.DeadPID(t)} {
+		if _, err := ListeningPorts(context.Background(), pid); !errors.Is(err, ErrProcessNotFound) {
+			t.Errorf("ListeningPorts(%d) error = %v, want ErrProcessNotFound", pid, err)
+		}
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	proc := testutil.StartDummyProcess(t)
+
+	pids, err := Descendants(context.Background(), os.Getpid())
+	if err != nil {
+		t.Fatalf("Descendants: %v", err)
+	}
+	if !slices.Contains(pids, proc.PID) {
+		t.Errorf("Descendants = %v, want it to contain %d", pids, proc.PID)
+	}
+	if slices.Contains(pids, os.Getpid()) {
+		t.Errorf("Descendants = %v, should not contain the process itself", pids)
+	}
+}
+
+func TestDescendantsMissingProcess(t *testing.T) {
+	if _, err := Descendants(context.Background(), testutil.DeadPID(t)); !errors.Is(err, ErrProcessNotFound) {
+		t.Errorf("error = %v, want ErrProcessNotFound", err)
+	}
+}
+
+func TestProcessName(t *testing.T) {
+	proc := testutil.StartDummyProcess(t)
+
+	name, err := ProcessName(context.Background(), proc.PID)
+	if err != nil {
+		t.Fatalf("ProcessName: %v", err)
+	}
+	if name == "" {
+		t.Error("expected a non-empty process name")
+	}
+
+	if _, err := ProcessName(context.Background(), testutil.DeadPID(t)); !errors.Is(err, ErrProcessNotFound) {
+		t.Errorf("error = %v, want ErrProcessNotFound", err)
+	}
+}
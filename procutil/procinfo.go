@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ErrNotSupported is returned when the current platform cannot inspect
+// another process in the requested way.
+var ErrNotSupported = errors.New("not supported on this platform")
+
+// errNotImplemented is gopsutil's not-implemented sentinel, which lives in
+// an internal package. Process.IOnice returns it on every platform.
+var errNotImplemented = func() error {
+	_, err := new(process.Process).IOniceWithContext(context.Background())
+	return err
+}()
+
+// GetProcessEnv returns the environment variables of the process with the
+// given PID. Values are returned as-is; use security.IsSensitiveKey before
+// displaying them.
+//
+// Environments are read from /proc on Linux, the process arguments area
+// (KERN_PROCARGS2) on macOS, and the process environment block on Windows.
+// Other platforms return ErrNotSupported. Reading another user's process
+// usually requires elevated privileges; in that case the error wraps
+// fs.ErrPermission. A missing process returns an error wrapping
+// ErrProcessNotFound.
+//
+// Example:
+//
+//	env, err := procutil.GetProcessEnv(pid)
+//	if errors.Is(err, fs.ErrPermission) {
+//	    cliout.Warning("run as the service's user to inspect its environment")
+//	}
+//	fmt.Println(env["ASPNETCORE_ENVIRONMENT"])
+func GetProcessEnv(pid int) (map[string]string, error) {
+	ctx := context.Background()
+	proc, err := findProcess(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	environ, err := processEnviron(ctx, proc)
+	if err != nil {
+		return nil, inspectError("environment", pid, err)
+	}
+	return parseEnviron(environ), nil
+}
+
+// GetProcessCwd returns the current working directory of the process with
+// the given PID. Errors follow the same conventions as GetProcessEnv.
+func GetProcessCwd(pid int) (string, error) {
+	ctx := context.Background()
+	proc, err := findProcess(ctx, pid)
+	if err != nil {
+		return "", err
+	}
+	cwd, err := proc.CwdWithContext(ctx)
+	if err != nil {
+		return "", inspectError("working directory", pid, err)
+	}
+	if cwd == "" {
+		return "", fmt.Errorf("%w: working directory of process %d", ErrNotSupported, pid)
+	}
+	return cwd, nil
+}
+
+// inspectError classifies an error from reading another process's state.
+func inspectError(what string, pid int, err error) error {
+	switch {
+	case errors.Is(err, ErrNotSupported):
+		return fmt.Errorf("cannot read %s of process %d: %w", what, pid, err)
+	case errors.Is(err, fs.ErrPermission) || errors.Is(err, process.ErrorNotPermitted):
+		return fmt.Errorf("cannot read %s of process %d (requires the same user or elevated privileges): %w",
+			what, pid, errors.Join(fs.ErrPermission, err))
+	case errors.Is(err, fs.ErrNotExist):
+		// The process exited between lookup and inspection.
+		return fmt.Errorf("%w: pid %d", ErrProcessNotFound, pid)
+	case errNotImplemented != nil && errors.Is(err, errNotImplemented):
+		return fmt.Errorf("cannot read %s of process %d: %w", what, pid, ErrNotSupported)
+	default:
+		return fmt.Errorf("failed to read %s of process %d: %w", what, pid, err)
+	}
+}
+
+// parseEnviron converts KEY=VALUE entries to a map. Windows per-drive
+// entries such as "=C:=C:\dir" keep their leading "=" in the key.
+func parseEnviron(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, entry := range environ {
+		if entry == "" {
+			continue
+		}
+		i := strings.IndexByte(entry[1:], '=') + 1
+		if i <= 0 {
+			continue
+		}
+		env[entry[:i]] = entry[i+1:]
+	}
+	return env
+}
+
+// parseProcArgs extracts the environment from a macOS KERN_PROCARGS2 buffer:
+// argc (int32), the executable path, NUL padding, argc arguments, and then
+// the environment, each NUL-terminated.
+func parseProcArgs(buf []byte) ([]string, error) {
+	if len(buf) < 4 {
+		return nil, errors.New("truncated process arguments")
+	}
+	argc := int(binary.LittleEndian.Uint32(buf[:4]))
+	rest := buf[4:]
+
+	// Skip the executable path and its padding.
+	end := bytes.IndexByte(rest, 0)
+	if end < 0 {
+		return nil, errors.New("malformed process arguments")
+	}
+	rest = rest[end:]
+	for len(rest) > 0 && rest[0] == 0 {
+		rest = rest[1:]
+	}
+
+	var environ []string
+	for i := 0; len(rest) > 0; i++ {
+		end := bytes.IndexByte(rest, 0)
+		if end < 0 {
+			end = len(rest)
+		}
+		field := string(rest[:end])
+		if end < len(rest) {
+			rest = rest[end+1:]
+		} else {
+			rest = nil
+		}
+		if i < argc {
+			continue
+		}
+		if field == "" {
+			break
+		}
+		environ = append(environ, field)
+	}
+	return environ, nil
+}
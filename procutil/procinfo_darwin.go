@@ -0,0 +1,27 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/sys/unix"
+)
+
+// processEnviron reads the environment of proc from KERN_PROCARGS2, which
+// gopsutil does not implement on macOS.
+func processEnviron(_ context.Context, proc *process.Process) ([]string, error) {
+	buf, err := unix.SysctlRaw("kern.procargs2", int(proc.Pid))
+	if err != nil {
+		if errors.Is(err, unix.EINVAL) {
+			// The kernel reports EINVAL for processes owned by other users.
+			return nil, fmt.Errorf("%w: %w", unix.EPERM, err)
+		}
+		return nil, err
+	}
+	return parseProcArgs(buf)
+}
@@ -0,0 +1,22 @@
+//go:build !darwin
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// processEnviron reads the environment of proc via gopsutil, which supports
+// Linux (/proc/<pid>/environ) and Windows (NtQueryInformationProcess).
+func processEnviron(ctx context.Context, proc *process.Process) ([]string, error) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "windows" {
+		return nil, ErrNotSupported
+	}
+	return proc.EnvironWithContext(ctx)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+// TestProcInfoHelperProcess is not a real test. Inspection tests launch the
+// test binary with PROCUTIL_PROCINFO_HELPER=1 to get a child with a known
+// environment and working directory.
+func TestProcInfoHelperProcess(t *testing.T) {
+	if os.Getenv("PROCUTIL_PROCINFO_HELPER") != "1" {
+		return
+	}
+	time.Sleep(30 * time.Second)
+	os.Exit(0)
+}
+
+func startInspectableProcess(t *testing.T, dir string) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProcInfoHelperProcess$")
+	cmd.Env = append(os.Environ(), "PROCUTIL_PROCINFO_HELPER=1", "PROCINFO_VALUE=a=b")
+	cmd.Dir = dir
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd.Process.Pid
+}
+
+func TestGetProcessEnvAndCwd(t *testing.T) {
+	dir := t.TempDir()
+	pid := startInspectableProcess(t, dir)
+
+	// The environment is visible once the child has exec'd.
+	var env map[string]string
+	var err error
+	for i := 0; i < 50; i++ {
+		env, err = GetProcessEnv(pid)
+		if err != nil || env["PROCUTIL_PROCINFO_HELPER"] == "1" {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	switch runtime.GOOS {
+	case "linux", "windows", "darwin":
+		if err != nil {
+			t.Fatalf("GetProcessEnv: %v", err)
+		}
+		if env["PROCINFO_VALUE"] != "a=b" {
+			t.Errorf("PROCINFO_VALUE = %q, want %q", env["PROCINFO_VALUE"], "a=b")
+		}
+	default:
+		if !errors.Is(err, ErrNotSupported) {
+			t.Errorf("GetProcessEnv error = %v, want ErrNotSupported", err)
+		}
+	}
+
+	cwd, err := GetProcessCwd(pid)
+	if err != nil {
+		if errors.Is(err, ErrNotSupported) {
+			t.Skipf("GetProcessCwd not supported: %v", err)
+		}
+		t.Fatalf("GetProcessCwd: %v", err)
+	}
+	want, _ := filepath.EvalSymlinks(dir)
+	got, _ := filepath.EvalSymlinks(cwd)
+	if got != want {
+		t.Errorf("GetProcessCwd = %q, want %q", cwd, dir)
+	}
+}
+
+func TestGetProcessEnvMissingProcess(t *testing.T) {
+	pid := testutil.DeadPID(t)
+	if _, err := GetProcessEnv(pid); !errors.Is(err, ErrProcessNotFound) {
+		t.Errorf("GetProcessEnv error = %v, want ErrProcessNotFound", err)
+	}
+	if _, err := GetProcessCwd(pid); !errors.Is(err, ErrProcessNotFound) {
+		t.Errorf("GetProcessCwd error = %v, want ErrProcessNotFound", err)
+	}
+}
+
+func TestInspectErrorNotImplemented(t *testing.T) {
+	if errNotImplemented == nil {
+		t.Fatal("errNotImplemented is nil; gopsutil no longer returns its sentinel from IOnice")
+	}
+	err := inspectError("environment", 42, fmt.Errorf("wrapped: %w", errNotImplemented))
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("inspectError = %v, want ErrNotSupported", err)
+	}
+	if err := inspectError("environment", 42, errors.New("not implemented yet")); errors.Is(err, ErrNotSupported) {
+		t.Errorf("inspectError matched an unrelated error by its text: %v", err)
+	}
+}
+
+func TestParseEnviron(t *testing.T) {
+	env := parseEnviron([]string{"PATH=/bin", "EMPTY=", "EQ=a=b", "=C:=C:\\src", "", "NOVALUE"})
+	want := map[string]string{"PATH": "/bin", "EMPTY": "", "EQ": "a=b", "=C:": "C:\\src"}
+	if len(env) != len(want) {
+		t.Errorf("parseEnviron = %v, want %v", env, want)
+	}
+	for k, v := range want {
+		if got, ok := env[k]; !ok || got != v {
+			t.Errorf("env[%q] = %q, %v; want %q", k, got, ok, v)
+		}
+	}
+}
+
+func TestParseProcArgs(t *testing.T) {
+	procArgs := func(argc uint32, fields ...string) []byte {
+		buf := binary.LittleEndian.AppendUint32(nil, argc)
+		for _, f := range fields {
+			buf = append(buf, f...)
+			buf = append(buf, 0)
+		}
+		return buf
+	}
+
+	buf := procArgs(2, "/usr/bin/node", "", "", "node", "server.js", "HOME=/Users/me", "PORT=3000", "", "trailing")
+	environ, err := parseProcArgs(buf)
+	if err != nil {
+		t.Fatalf("parseProcArgs: %v", err)
+	}
+	if !slices.Equal(environ, []string{"HOME=/Users/me", "PORT=3000"}) {
+		t.Errorf("environ = %q", environ)
+	}
+
+	if _, err := parseProcArgs([]byte{1, 0}); err == nil {
+		t.Error("expected error for truncated buffer")
+	}
+	if _, err := parseProcArgs(binary.LittleEndian.AppendUint32(nil, 1)); err == nil {
+		t.Error("expected error for missing executable path")
+	}
+}
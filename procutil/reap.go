@@ -0,0 +1,150 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// DefaultReaperGrace is how long StartReaper leaves an exited child for its
+// owner to wait on when ReaperOptions.Grace is zero.
+const DefaultReaperGrace = 5 * time.Second
+
+// ReaperOptions configures StartReaper.
+type ReaperOptions struct {
+	// Grace is how long a child must have been exited (a zombie) before it
+	// is reaped, leaving time for an exec.Cmd.Wait that is about to run.
+	// Defaults to DefaultReaperGrace.
+	Grace time.Duration
+	// OnReap, if set, is called with the PID and exit code of each reaped
+	// child. The exit code is -1 if the child was killed by a signal.
+	OnReap func(pid, exitCode int)
+}
+
+// ReapChildren waits for every child of the current process that has
+// already exited but was never waited for (a zombie) and returns their PIDs.
+// It never blocks on running children. On Windows, where exited processes
+// do not linger, it does nothing.
+//
+// An exec.Cmd whose child is reaped here gets an error from Wait, so call
+// ReapChildren only when no such Wait is pending, or use StartReaper, which
+// waits a grace period first.
+func ReapChildren() ([]int, error) {
+	if !reapSupported {
+		return nil, nil
+	}
+	zombies, err := zombieChildren(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	var reaped []int
+	for _, pid := range zombies {
+		if ok, _, err := reapChild(pid); err != nil {
+			return reaped, err
+		} else if ok {
+			reaped = append(reaped, pid)
+		}
+	}
+	return reaped, nil
+}
+
+// StartReaper reaps exited children in the background until ctx is done,
+// for long-running daemons that start many short-lived processes and may
+// miss a Wait. It checks for zombies on every SIGCHLD and every
+// ReaperOptions.Grace, and reaps only those that have stayed unreaped for
+// the grace period, so it is safe to use alongside os/exec as long as
+// exec.Cmd.Wait is called promptly after the child exits. On Windows it does
+// nothing.
+//
+// Example:
+//
+//	procutil.StartReaper(ctx, procutil.ReaperOptions{
+//	    OnReap: func(pid, code int) {
+//	        log.Debug("reaped orphaned child", "pid", pid, "exitCode", code)
+//	    },
+//	})
+func StartReaper(ctx context.Context, opts ReaperOptions) {
+	if !reapSupported {
+		return
+	}
+	if opts.Grace <= 0 {
+		opts.Grace = DefaultReaperGrace
+	}
+	go runReaper(ctx, opts, notifyChildExit(ctx))
+}
+
+// runReaper is the StartReaper loop.
+func runReaper(ctx context.Context, opts ReaperOptions, childExit <-chan struct{}) {
+	ticker := time.NewTicker(opts.Grace)
+	defer ticker.Stop()
+
+	firstSeen := make(map[int]time.Time)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-childExit:
+		case <-ticker.C:
+		}
+
+		zombies, err := zombieChildren(ctx)
+		if err != nil {
+			continue
+		}
+		now := time.Now()
+		current := make(map[int]bool, len(zombies))
+		for _, pid := range zombies {
+			current[pid] = true
+			seen, ok := firstSeen[pid]
+			if !ok {
+				firstSeen[pid] = now
+				continue
+			}
+			if now.Sub(seen) < opts.Grace {
+				continue
+			}
+			if ok, code, err := reapChild(pid); err == nil && ok && opts.OnReap != nil {
+				opts.OnReap(pid, code)
+			}
+		}
+		// Forget children that were waited for by their owner or reaped.
+		for pid := range firstSeen {
+			if !current[pid] {
+				delete(firstSeen, pid)
+			}
+		}
+	}
+}
+
+// zombieChildren returns the PIDs of direct children of the current process
+// that have exited but not been waited for.
+func zombieChildren(ctx context.Context) ([]int, error) {
+	self, err := findProcess(ctx, os.Getpid())
+	if err != nil {
+		return nil, err
+	}
+	children, err := self.ChildrenWithContext(ctx)
+	if err != nil {
+		// gopsutil reports no children as an error on some platforms.
+		return nil, nil
+	}
+	var pids []int
+	for _, child := range children {
+		status, err := child.StatusWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		for _, s := range status {
+			if s == process.Zombie {
+				pids = append(pids, int(child.Pid))
+				break
+			}
+		}
+	}
+	return pids, nil
+}
@@ -0,0 +1,57 @@
+//go:build !windows
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const reapSupported = true
+
+// reapChild waits for pid without blocking. It reports false if the child
+// has not exited or was already waited for by someone else.
+func reapChild(pid int) (bool, int, error) {
+	var status syscall.WaitStatus
+	wpid, err := syscall.Wait4(pid, &status, syscall.WNOHANG, nil)
+	if errors.Is(err, syscall.ECHILD) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, err
+	}
+	if wpid != pid {
+		return false, 0, nil
+	}
+	return true, status.ExitStatus(), nil
+}
+
+// notifyChildExit returns a channel that receives a value on SIGCHLD until
+// ctx is done. Go's os/exec does not rely on SIGCHLD, so listening for it
+// does not interfere with exec.Cmd.
+func notifyChildExit(ctx context.Context) <-chan struct{} {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGCHLD)
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startExitingChild starts a child that exits immediately and is never
+// waited for, leaving a zombie on Unix.
+func startExitingChild(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start child: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+// waitForZombie waits until pid is listed as an exited child.
+func waitForZombie(t *testing.T, pid int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		zombies, err := zombieChildren(context.Background())
+		if err != nil {
+			t.Fatalf("zombieChildren: %v", err)
+		}
+		if slices.Contains(zombies, pid) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("child %d did not become a zombie", pid)
+}
+
+func TestReapChildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		if pids, err := ReapChildren(); err != nil || pids != nil {
+			t.Errorf("ReapChildren() on Windows = %v, %v; want nil, nil", pids, err)
+		}
+		return
+	}
+
+	pid := startExitingChild(t)
+	waitForZombie(t, pid)
+
+	reaped, err := ReapChildren()
+	if err != nil {
+		t.Fatalf("ReapChildren: %v", err)
+	}
+	if !slices.Contains(reaped, pid) {
+		t.Errorf("ReapChildren() = %v, want it to include %d", reaped, pid)
+	}
+	if zombies, _ := zombieChildren(context.Background()); slices.Contains(zombies, pid) {
+		t.Errorf("child %d is still a zombie after reaping", pid)
+	}
+
+	// Nothing left to reap.
+	if again, err := ReapChildren(); err != nil || slices.Contains(again, pid) {
+		t.Errorf("second ReapChildren() = %v, %v", again, err)
+	}
+}
+
+func TestStartReaper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no zombie processes")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	reaped := make(map[int]int)
+	StartReaper(ctx, ReaperOptions{
+		Grace: 100 * time.Millisecond,
+		OnReap: func(pid, code int) {
+			mu.Lock()
+			reaped[pid] = code
+			mu.Unlock()
+		},
+	})
+
+	pid := startExitingChild(t)
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		code, ok := reaped[pid]
+		mu.Unlock()
+		if ok {
+			if code != 0 {
+				t.Errorf("exit code = %d, want 0", code)
+			}
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("child %d was not reaped", pid)
+}
+
+func TestStartReaper_ExecCmdStillWaits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no zombie processes")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	StartReaper(ctx, ReaperOptions{Grace: time.Second})
+
+	// Children that are waited for promptly keep their exit status.
+	for range 2 {
+		if err := exec.Command(os.Args[0], "-test.run=^$").Run(); err != nil {
+			t.Fatalf("exec.Cmd.Run with reaper running: %v", err)
+		}
+	}
+}
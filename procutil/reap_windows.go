@@ -0,0 +1,20 @@
+//go:build windows
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import "context"
+
+// Exited Windows processes do not need to be waited for, so there is
+// nothing to reap.
+const reapSupported = false
+
+func reapChild(int) (bool, int, error) {
+	return false, 0, ErrNotSupported
+}
+
+func notifyChildExit(context.Context) <-chan struct{} {
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Signal sends sig to the process with the given PID. The process is looked
+// up first, so a missing process returns an error wrapping
+// ErrProcessNotFound, and signaling another user's process returns an error
+// wrapping fs.ErrPermission.
+//
+// On Unix any signal can be sent. Windows has no signals: os.Kill
+// terminates the process, os.Interrupt sends CTRL_BREAK_EVENT (delivered only
+// to a process group leader that shares the caller's console), and other
+// signals return ErrNotSupported.
+//
+// Example:
+//
+//	if err := procutil.Signal(pid, syscall.SIGUSR1); err != nil {
+//	    return err
+//	}
+func Signal(pid int, sig os.Signal) error {
+	if _, err := findProcess(context.Background(), pid); err != nil {
+		return err
+	}
+	if err := signalProcess(pid, sig); err != nil {
+		return fmt.Errorf("failed to send %v to process %d: %w", sig, pid, err)
+	}
+	return nil
+}
+
+// ReloadProcess asks the process with the given PID to reload its
+// configuration.
+//
+// On Unix it sends SIGHUP, the conventional reload signal. Windows has no
+// equivalent, so the request is delivered through a named event that the
+// target creates by calling NotifyReload; if the target is not listening,
+// the error wraps ErrNotSupported and the caller should fall back to
+// restarting it.
+//
+// Example:
+//
+//	err := procutil.ReloadProcess(h.PID)
+//	if errors.Is(err, procutil.ErrNotSupported) {
+//	    err = restart(h)
+//	}
+func ReloadProcess(pid int) error {
+	if _, err := findProcess(context.Background(), pid); err != nil {
+		return err
+	}
+	if err := reloadProcess(pid); err != nil {
+		return fmt.Errorf("failed to reload process %d: %w", pid, err)
+	}
+	return nil
+}
+
+// NotifyReload returns a channel that receives a value each time
+// ReloadProcess targets the current process: on SIGHUP on Unix, or when the
+// named reload event is set on Windows. Requests that arrive while a
+// previous one is still pending are merged. Listening stops and the channel
+// is closed when ctx is done.
+//
+// Example:
+//
+//	for range procutil.NotifyReload(ctx) {
+//	    if err := loadConfig(); err != nil {
+//	        log.Error("reload failed", "error", err)
+//	    }
+//	}
+func NotifyReload(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	notifyReload(ctx, ch)
+	return ch
+}
+
+// sendReload delivers a reload request to ch without blocking; a request
+// already pending absorbs it.
+func sendReload(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,48 @@
+//go:build !windows
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func signalProcess(pid int, sig os.Signal) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	err = proc.Signal(sig)
+	if errors.Is(err, os.ErrProcessDone) || errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("%w: pid %d", ErrProcessNotFound, pid)
+	}
+	return err
+}
+
+func reloadProcess(pid int) error {
+	return signalProcess(pid, syscall.SIGHUP)
+}
+
+func notifyReload(ctx context.Context, ch chan struct{}) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	go func() {
+		defer close(ch)
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				sendReload(ch)
+			}
+		}
+	}()
+}
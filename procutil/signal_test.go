@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSignal_Kill(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestDetachedHelperProcess$")
+	cmd.Env = append(os.Environ(), "PROCUTIL_DETACHED_HELPER=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start helper: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	if err := Signal(cmd.Process.Pid, os.Kill); err != nil {
+		t.Fatalf("Signal(os.Kill) error = %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatal("helper still running after Signal(os.Kill)")
+	}
+}
+
+func TestSignal_ProcessNotFound(t *testing.T) {
+	for _, pid := range []int{-1, 0, 1 << 30} {
+		if err := Signal(pid, os.Interrupt); !errors.Is(err, ErrProcessNotFound) {
+			t.Errorf("Signal(%d) error = %v, want ErrProcessNotFound", pid, err)
+		}
+		if err := ReloadProcess(pid); !errors.Is(err, ErrProcessNotFound) {
+			t.Errorf("ReloadProcess(%d) error = %v, want ErrProcessNotFound", pid, err)
+		}
+	}
+}
+
+func TestReloadProcess_NotifyReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reloads := NotifyReload(ctx)
+
+	for i := 0; i < 2; i++ {
+		if err := ReloadProcess(os.Getpid()); err != nil {
+			cancel()
+			t.Fatalf("ReloadProcess() error = %v", err)
+		}
+		select {
+		case <-reloads:
+		case <-time.After(5 * time.Second):
+			cancel()
+			t.Fatalf("reload %d not delivered", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-reloads:
+		if ok {
+			// A merged pending request may still be buffered; the channel
+			// must close after it.
+			if _, ok := <-reloads; ok {
+				t.Error("NotifyReload channel not closed after cancel")
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("NotifyReload channel not closed after cancel")
+	}
+}
+
+func TestReloadProcess_NotListening(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("on Unix an unhandled SIGHUP terminates the process")
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestDetachedHelperProcess$")
+	cmd.Env = append(os.Environ(), "PROCUTIL_DETACHED_HELPER=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start helper: %v", err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill(); _ = cmd.Wait() })
+
+	if err := ReloadProcess(cmd.Process.Pid); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ReloadProcess() error = %v, want ErrNotSupported", err)
+	}
+}
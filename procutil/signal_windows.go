@@ -0,0 +1,82 @@
+//go:build windows
+
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// reloadEventName is the named event ReloadProcess sets and NotifyReload
+// waits on. It is per-session, so only processes in the caller's session
+// can be reloaded.
+func reloadEventName(pid int) string {
+	return fmt.Sprintf(`Local\azd-reload-%d`, pid)
+}
+
+func signalProcess(pid int, sig os.Signal) error {
+	switch sig {
+	case os.Kill:
+		proc, err := os.FindProcess(pid)
+		if err != nil {
+			return err
+		}
+		return proc.Kill()
+	case os.Interrupt:
+		// #nosec G115 -- pid validated by findProcess
+		return windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(pid))
+	default:
+		return fmt.Errorf("%w: signal %v", ErrNotSupported, sig)
+	}
+}
+
+func reloadProcess(pid int) error {
+	name, err := windows.UTF16PtrFromString(reloadEventName(pid))
+	if err != nil {
+		return err
+	}
+	event, err := windows.OpenEvent(windows.EVENT_MODIFY_STATE, false, name)
+	if err != nil {
+		if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+			return fmt.Errorf("%w: process is not listening for reload requests", ErrNotSupported)
+		}
+		return err
+	}
+	defer func() { _ = windows.CloseHandle(event) }()
+	return windows.SetEvent(event)
+}
+
+func notifyReload(ctx context.Context, ch chan struct{}) {
+	name, err := windows.UTF16PtrFromString(reloadEventName(os.Getpid()))
+	if err != nil {
+		close(ch)
+		return
+	}
+	// Auto-reset, so each SetEvent wakes the waiter once.
+	event, err := windows.CreateEvent(nil, 0, 0, name)
+	if err != nil {
+		close(ch)
+		return
+	}
+	go func() {
+		defer close(ch)
+		defer func() { _ = windows.CloseHandle(event) }()
+		for {
+			// Wake up periodically to notice ctx cancellation.
+			result, err := windows.WaitForSingleObject(event, 250)
+			if ctx.Err() != nil || err != nil {
+				return
+			}
+			if result == windows.WAIT_OBJECT_0 {
+				sendReload(ch)
+			}
+		}
+	}()
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ErrProcessNotFound indicates that no process exists with the given PID.
+var ErrProcessNotFound = errors.New("process not found")
+
+// Usage is a point-in-time resource usage sample for a process.
+type Usage struct {
+	PID int `json:"pid"`
+	// CPUPercent is CPU utilization where 100 means one full core.
+	CPUPercent float64 `json:"cpuPercent"`
+	// MemoryRSS is the resident set size in bytes (working set on Windows).
+	MemoryRSS uint64 `json:"memoryRss"`
+}
+
+// GetUsage returns the CPU and memory usage of the process with the given
+// PID. CPUPercent is averaged over the process lifetime; use GetUsageOver for
+// current utilization.
+//
+// Example:
+//
+//	usage, err := procutil.GetUsage(pid)
+//	if err == nil {
+//	    fmt.Printf("%.1f%% CPU, %d MB\n", usage.CPUPercent, usage.MemoryRSS>>20)
+//	}
+func GetUsage(pid int) (*Usage, error) {
+	return getUsage(context.Background(), pid, 0)
+}
+
+// GetUsageOver samples the process for interval and returns its CPU
+// utilization during that window along with its current memory usage. It
+// blocks for interval or until ctx is canceled.
+func GetUsageOver(ctx context.Context, pid int, interval time.Duration) (*Usage, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive, got %s", interval)
+	}
+	return getUsage(ctx, pid, interval)
+}
+
+// getUsage collects a Usage sample. A zero interval uses the lifetime CPU average.
+func getUsage(ctx context.Context, pid int, interval time.Duration) (*Usage, error) {
+	proc, err := findProcess(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	var cpu float64
+	if interval > 0 {
+		cpu, err = proc.PercentWithContext(ctx, interval)
+	} else {
+		cpu, err = proc.CPUPercentWithContext(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CPU usage for pid %d: %w", pid, err)
+	}
+
+	mem, err := proc.MemoryInfoWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory usage for pid %d: %w", pid, err)
+	}
+
+	return &Usage{PID: pid, CPUPercent: cpu, MemoryRSS: mem.RSS}, nil
+}
+
+// findProcess returns a gopsutil handle for pid, mapping a missing process to
+// ErrProcessNotFound.
+func findProcess(ctx context.Context, pid int) (*process.Process, error) {
+	if pid <= 0 || pid > int(^uint32(0)>>1) {
+		return nil, fmt.Errorf("%w: invalid pid %d", ErrProcessNotFound, pid)
+	}
+	proc, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		if errors.Is(err, process.ErrorProcessNotRunning) {
+			return nil, fmt.Errorf("%w: pid %d", ErrProcessNotFound, pid)
+		}
+		return nil, fmt.Errorf("failed to open process %d: %w", pid, err)
+	}
+	return proc, nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package procutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/testutil"
+)
+
+func TestGetUsageCurrentProcess(t *testing.T) {
+	usage, err := GetUsage(os.Getpid())
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	if usage.PID != os.Getpid() {
+		t.Errorf("PID = %d, want %d", usage.PID, os.Getpid())
+	}
+	if usage.MemoryRSS == 0 {
+		t.Error("expected non-zero RSS for the current process")
+	}
+	if usage.CPUPercent < 0 {
+		t.Errorf("CPUPercent = %f, want >= 0", usage.CPUPercent)
+	}
+}
+
+func TestGetUsageOver(t *testing.T) {
+	usage, err := GetUsageOver(context.Background(), os.Getpid(), 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("GetUsageOver: %v", err)
+	}
+	if usage.MemoryRSS == 0 {
+		t.Error("expected non-zero RSS")
+	}
+
+	if _, err := GetUsageOver(context.Background(), os.Getpid(), 0); err == nil {
+		t.Error("expected error for zero interval")
+	}
+}
+
+func TestGetUsageMissingProcess(t *testing.T) {
+	for _, pid := range []int{0, -1, testutil.DeadPID(t)} {
+		if _, err := GetUsage(pid); !errors.Is(err, ErrProcessNotFound) {
+			t.Errorf("GetUsage(%d) error = %v, want ErrProcessNotFound", pid, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package progress
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// EnvAccessible requests accessible output when set to a true value such as
+// "1" or "true". Progress is then printed as one line per status change,
+// without spinners, redraws, or cursor movement, which screen readers
+// handle poorly.
+const EnvAccessible = "AZD_ACCESSIBLE"
+
+// stdoutIsTerminal is replaced in tests.
+var stdoutIsTerminal = func() bool { return term.IsTerminal(int(os.Stdout.Fd())) }
+
+// AccessibleModeRequested reports whether the environment asks for
+// accessible output: EnvAccessible is set to a true value, or TERM is
+// "dumb".
+func AccessibleModeRequested() bool {
+	if v := strings.TrimSpace(os.Getenv(EnvAccessible)); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			return enabled
+		}
+	}
+	return os.Getenv("TERM") == "dumb"
+}
+
+// SetAccessible turns accessible mode on or off, overriding
+// AccessibleModeRequested. In accessible mode, as when stdout is not a
+// terminal, each task prints a line when it starts and when it finishes
+// instead of an animated bar. Call it before Start.
+func (mp *MultiProgress) SetAccessible(enabled bool) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	mp.lineMode = enabled || !stdoutIsTerminal()
+}
+
+// announceChanges prints a line for every task whose status changed since
+// the last call. Pending tasks are not announced.
+func (mp *MultiProgress) announceChanges(ids []string, bars []*ProgressSpinner) {
+	mp.announceMu.Lock()
+	defer mp.announceMu.Unlock()
+	if mp.announced == nil {
+		mp.announced = make(map[string]TaskStatus)
+	}
+
+	now := time.Now()
+	for i, bar := range bars {
+		bar.mu.Lock()
+		status := bar.status
+		if status == TaskStatusPending || mp.announced[ids[i]] == status {
+			bar.mu.Unlock()
+			continue
+		}
+		mp.announced[ids[i]] = status
+		line := mp.statusChangeLine(bar, now)
+		bar.mu.Unlock()
+		fmt.Println(line)
+	}
+}
+
+// statusChangeLine describes a task's current status in words, for
+// example "Build web: done in 1.2s". The caller must hold bar.mu.
+func (mp *MultiProgress) statusChangeLine(bar *ProgressSpinner, now time.Time) string {
+	elapsed := mp.calculateElapsed(bar, now)
+	var size string
+	if bar.trackBytes {
+		size = ", " + FormatBytes(bar.bytesWritten)
+	}
+
+	switch bar.status {
+	case TaskStatusRunning:
+		return bar.description + ": started"
+	case TaskStatusSuccess:
+		return fmt.Sprintf("%s: done in %.1fs%s", bar.description, elapsed, size)
+	case TaskStatusFailed:
+		line := fmt.Sprintf("%s: failed after %.1fs%s", bar.description, elapsed, size)
+		if bar.errorMsg != "" {
+			line += ": " + bar.errorMsg
+		}
+		return line
+	case TaskStatusSkipped:
+		return bar.description + ": skipped"
+	default:
+		return bar.description + ": " + string(bar.status)
+	}
+}
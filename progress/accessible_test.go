@@ -0,0 +1,131 @@
+package progress
+
+import (
+	"strings"
+	"testing"
+)
+
+// withTerminal makes stdout look like a terminal, or not, for a test.
+func withTerminal(t *testing.T, isTTY bool) {
+	t.Helper()
+	old := stdoutIsTerminal
+	stdoutIsTerminal = func() bool { return isTTY }
+	t.Cleanup(func() { stdoutIsTerminal = old })
+}
+
+func TestAccessibleModeRequested(t *testing.T) {
+	tests := []struct {
+		accessible string
+		term       string
+		want       bool
+	}{
+		{"", "xterm-256color", false},
+		{"1", "xterm-256color", true},
+		{"true", "", true},
+		{"0", "dumb", false},
+		{"", "dumb", true},
+		{"maybe", "xterm", false},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvAccessible, tt.accessible)
+		t.Setenv("TERM", tt.term)
+		if got := AccessibleModeRequested(); got != tt.want {
+			t.Errorf("AccessibleModeRequested() with %s=%q TERM=%q = %v, want %v", EnvAccessible, tt.accessible, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestNewMultiProgress_LineMode(t *testing.T) {
+	t.Setenv(EnvAccessible, "")
+	t.Setenv("TERM", "xterm")
+
+	withTerminal(t, true)
+	if NewMultiProgress().lineMode {
+		t.Error("lineMode on a terminal = true, want false")
+	}
+	t.Setenv(EnvAccessible, "1")
+	if !NewMultiProgress().lineMode {
+		t.Error("lineMode with AZD_ACCESSIBLE=1 = false, want true")
+	}
+
+	t.Setenv(EnvAccessible, "")
+	withTerminal(t, false)
+	if !NewMultiProgress().lineMode {
+		t.Error("lineMode without a terminal = false, want true")
+	}
+}
+
+func TestSetAccessible(t *testing.T) {
+	withTerminal(t, true)
+	mp := NewMultiProgress()
+	mp.SetAccessible(true)
+	if !mp.lineMode {
+		t.Error("SetAccessible(true) did not enable line mode")
+	}
+	mp.SetAccessible(false)
+	if mp.lineMode {
+		t.Error("SetAccessible(false) on a terminal left line mode on")
+	}
+
+	withTerminal(t, false)
+	mp.SetAccessible(false)
+	if !mp.lineMode {
+		t.Error("SetAccessible(false) without a terminal should keep line mode")
+	}
+}
+
+func TestLineModeOutput(t *testing.T) {
+	withTerminal(t, true)
+	mp := NewMultiProgress()
+	mp.SetAccessible(true)
+
+	web := mp.AddBar("web", "Build web")
+	api := mp.AddBar("api", "Build api")
+	docs := mp.AddBar("docs", "Build docs")
+	mp.AddBar("idle", "Never started")
+
+	output := captureStdout(t, func() {
+		mp.Start()
+		web.Start()
+		mp.render()
+		mp.render() // unchanged statuses are not repeated
+		web.AddBytes(2048)
+		web.Complete()
+		api.Start()
+		api.Fail("exit status 1")
+		docs.Skip()
+		mp.Stop()
+	})
+
+	if strings.Contains(output, "\033[") {
+		t.Errorf("line mode output contains escape sequences: %q", output)
+	}
+	for _, frame := range unicodeGlyphs.spinner {
+		if strings.Contains(output, frame) {
+			t.Errorf("line mode output contains spinner frame %q", frame)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	want := []string{
+		"Build web: started",
+		"Build web: done in ",
+		"Build api: failed after ",
+		"Build docs: skipped",
+		"Never started: done in ", // Stop marks unfinished tasks as done
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), output)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], prefix)
+		}
+	}
+	if !strings.HasSuffix(lines[1], ", 2.0 KiB") {
+		t.Errorf("done line = %q, want transferred size", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], ": exit status 1") {
+		t.Errorf("failed line = %q, want error message", lines[2])
+	}
+}
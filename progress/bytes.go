@@ -0,0 +1,45 @@
+package progress
+
+import (
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// transferWidth is the widest transfer column, e.g. "1023.9 MiB 1023.9 MiB/s".
+const transferWidth = 24
+
+// FormatBytes formats a byte count using binary units, e.g. "12.3 MiB".
+// It matches fileutil.FormatBytes so sizes read the same across packages.
+func FormatBytes(n int64) string {
+	if n < 0 {
+		n = 0
+	}
+	return fileutil.FormatBytes(n)
+}
+
+// FormatRate formats the average throughput of n bytes transferred over
+// elapsed, e.g. "2.4 MiB/s". A non-positive elapsed reports "0 B/s".
+func FormatRate(n int64, elapsed time.Duration) string {
+	if elapsed <= 0 || n <= 0 {
+		return "0 B/s"
+	}
+	perSecond := float64(n) / elapsed.Seconds()
+	return fileutil.FormatBytes(int64(perSecond)) + "/s"
+}
+
+// formatTransfer returns the size and throughput shown next to a bar that
+// tracks bytes, or "" when the task does not track bytes or has not started.
+// The caller must hold bar.mu.
+func formatTransfer(bar *ProgressSpinner, elapsed float64) string {
+	if !bar.trackBytes {
+		return ""
+	}
+	switch bar.status {
+	case TaskStatusRunning, TaskStatusSuccess, TaskStatusFailed:
+	default:
+		return ""
+	}
+	d := time.Duration(elapsed * float64(time.Second))
+	return FormatBytes(bar.bytesWritten) + " " + FormatRate(bar.bytesWritten, d)
+}
@@ -0,0 +1,163 @@
+package progress
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{-1, "0 B"},
+		{0, "0 B"},
+		{512, "512 B"},
+		{1536, "1.5 KiB"},
+		{12*1024*1024 + 300*1024, "12.3 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.in); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int64
+		elapsed time.Duration
+		want    string
+	}{
+		{"zero elapsed", 1024, 0, "0 B/s"},
+		{"zero bytes", 0, time.Second, "0 B/s"},
+		{"bytes per second", 500, time.Second, "500 B/s"},
+		{"mebibytes per second", 5 << 20, 2 * time.Second, "2.5 MiB/s"},
+		{"sub-second", 1 << 20, 500 * time.Millisecond, "2.0 MiB/s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatRate(tt.n, tt.elapsed); got != tt.want {
+				t.Errorf("FormatRate(%d, %v) = %q, want %q", tt.n, tt.elapsed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddBytesEnablesTransfer(t *testing.T) {
+	mp := NewMultiProgress()
+	bar := mp.AddBar("dl", "Download")
+	bar.Start()
+
+	bar.Increment()
+	if bar.trackBytes {
+		t.Error("Increment() should not enable the transfer column")
+	}
+	bar.AddBytes(0)
+	if bar.trackBytes {
+		t.Error("AddBytes(0) should not enable the transfer column")
+	}
+	bar.AddBytes(2048)
+	if !bar.trackBytes {
+		t.Error("AddBytes(2048) should enable the transfer column")
+	}
+}
+
+func TestFormatTransfer(t *testing.T) {
+	tests := []struct {
+		name string
+		bar  *ProgressSpinner
+		want string
+	}{
+		{
+			name: "not tracking bytes",
+			bar:  &ProgressSpinner{status: TaskStatusRunning, bytesWritten: 4096},
+			want: "",
+		},
+		{
+			name: "pending",
+			bar:  &ProgressSpinner{status: TaskStatusPending, bytesWritten: 4096, trackBytes: true},
+			want: "",
+		},
+		{
+			name: "running",
+			bar:  &ProgressSpinner{status: TaskStatusRunning, bytesWritten: 4 << 20, trackBytes: true},
+			want: "4.0 MiB 2.0 MiB/s",
+		},
+		{
+			name: "success",
+			bar:  &ProgressSpinner{status: TaskStatusSuccess, bytesWritten: 4 << 20, trackBytes: true},
+			want: "4.0 MiB 2.0 MiB/s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTransfer(tt.bar, 2); got != tt.want {
+				t.Errorf("formatTransfer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildProgressLineTransfer(t *testing.T) {
+	mp := NewMultiProgress()
+	mp.termWidth = 80
+
+	bar := &ProgressSpinner{
+		description:  "Downloading bicep",
+		status:       TaskStatusRunning,
+		bytesWritten: 24 << 20,
+		trackBytes:   true,
+	}
+	line := mp.buildProgressLine(bar, 50, 10)
+	if !strings.Contains(line, "24.0 MiB 2.4 MiB/s") {
+		t.Errorf("buildProgressLine() = %q, want transfer column", line)
+	}
+
+	plain := mp.buildProgressLine(&ProgressSpinner{description: "Building", status: TaskStatusRunning}, 50, 10)
+	if strings.Contains(plain, "/s") {
+		t.Errorf("buildProgressLine() = %q, want no transfer column", plain)
+	}
+
+	visible := len([]rune(stripColors(line)))
+	if visible > mp.termWidth {
+		t.Errorf("buildProgressLine() visible width = %d, want <= %d", visible, mp.termWidth)
+	}
+
+	mp.termWidth = 50
+	compact := mp.buildProgressLine(bar, 50, 10)
+	if !strings.Contains(compact, "2.4 MiB/s") {
+		t.Errorf("compact buildProgressLine() = %q, want transfer column", compact)
+	}
+}
+
+func TestSnapshotBytes(t *testing.T) {
+	mp := NewMultiProgress()
+	dl := mp.AddBar("dl", "Download")
+	build := mp.AddBar("build", "Build")
+	dl.Start()
+	_, _ = NewSpinnerWriter(dl).Write(make([]byte, 1500))
+	build.Start()
+	build.Increment()
+
+	states := mp.Snapshot()
+	if states[0].Bytes != 1500 {
+		t.Errorf("Snapshot() dl bytes = %d, want 1500", states[0].Bytes)
+	}
+	if states[1].Bytes != 0 {
+		t.Errorf("Snapshot() build bytes = %d, want 0", states[1].Bytes)
+	}
+}
+
+// stripColors removes the cliout color codes used in progress lines.
+func stripColors(s string) string {
+	for _, code := range []string{cliout.Reset, cliout.Dim, cliout.Cyan, cliout.Green, cliout.Red, cliout.Gray} {
+		s = strings.ReplaceAll(s, code, "")
+	}
+	return s
+}
@@ -1,6 +1,12 @@
 // Package progress provides a multi-progress bar system for concurrent task tracking.
 // It supports multiple simultaneous progress bars with spinner animations,
-// status tracking, and terminal-aware rendering.
+// status tracking, and terminal-aware rendering. Bars fed through AddBytes or a
+// SpinnerWriter also show the transferred size and throughput; FormatBytes and
+// FormatRate expose the same formatting for other output.
+//
+// When stdout is not a terminal, or accessible mode is on (AZD_ACCESSIBLE=1,
+// TERM=dumb, or SetAccessible), tasks are reported as discrete lines when
+// they start and finish, with no animation or cursor movement.
 package progress
 
 import (
@@ -61,6 +67,7 @@ type ProgressSpinner struct {
 	description   string
 	status        TaskStatus
 	bytesWritten  int64
+	trackBytes    bool // Set once AddBytes reports real bytes; enables the transfer column
 	finalProgress float64
 	startTime     time.Time
 	endTime       time.Time
@@ -78,6 +85,13 @@ type MultiProgress struct {
 	stopped       bool
 	lastLineCount int
 	termWidth     int
+	showSummary   bool
+
+	// lineMode prints status changes as lines instead of redrawing bars
+	// (see accessible.go); announced holds the last status printed per task.
+	lineMode   bool
+	announceMu sync.Mutex
+	announced  map[string]TaskStatus
 }
 
 // NewMultiProgress creates a new multi-progress manager.
@@ -106,12 +120,16 @@ func NewMultiProgress() *MultiProgress {
 		barOrder:  []string{},
 		stopChan:  make(chan struct{}),
 		termWidth: width,
+		lineMode:  AccessibleModeRequested() || !stdoutIsTerminal(),
+		announced: make(map[string]TaskStatus),
 	}
 
 	// Debug: show detected terminal width and source
 	if os.Getenv("AZD_APP_DEBUG") == "true" {
 		mode := "full"
-		if mp.termWidth < minTermWidthForBar {
+		if mp.lineMode {
+			mode = "line"
+		} else if mp.termWidth < minTermWidthForBar {
 			mode = "compact"
 		}
 		fmt.Fprintf(os.Stderr, "[DEBUG] Terminal width: %d columns from %s (using %s mode)\n",
@@ -147,12 +165,14 @@ func (mp *MultiProgress) GetBar(id string) *ProgressSpinner {
 
 // Start starts the multi-progress display (renders all bars periodically).
 func (mp *MultiProgress) Start() {
-	// Hide cursor during progress display
-	fmt.Print("\033[?25l")
-
-	// Set initial line count based on number of bars
 	mp.mu.Lock()
-	mp.lastLineCount = len(mp.bars)
+	if !mp.lineMode {
+		// Hide cursor during progress display
+		fmt.Print("\033[?25l")
+
+		// Set initial line count based on number of bars
+		mp.lastLineCount = len(mp.bars)
+	}
 	mp.mu.Unlock()
 
 	go func() {
@@ -189,8 +209,14 @@ func (mp *MultiProgress) Stop() {
 	// Render one final time to show completed states with frozen timers
 	mp.renderFinal()
 
-	// Show cursor again
-	fmt.Print("\033[?25h")
+	if !mp.lineMode {
+		// Show cursor again
+		fmt.Print("\033[?25h")
+	}
+
+	if mp.showSummary {
+		PrintTaskSummary(mp.summaryLocked())
+	}
 }
 
 // render renders all active progress bars.
@@ -203,14 +229,15 @@ func (mp *MultiProgress) render() {
 	}
 
 	// Copy bar references while holding read lock
-	bars := make([]*ProgressSpinner, 0, len(mp.barOrder))
-	for _, id := range mp.barOrder {
-		if bar, exists := mp.bars[id]; exists {
-			bars = append(bars, bar)
-		}
-	}
+	ids, bars := mp.orderedBarsLocked()
+	lineMode := mp.lineMode
 	mp.mu.RUnlock()
 
+	if lineMode {
+		mp.announceChanges(ids, bars)
+		return
+	}
+
 	// Move cursor and process bars without holding mp.mu
 	mp.moveCursorToStart()
 
@@ -249,6 +276,11 @@ func (mp *MultiProgress) render() {
 
 // renderFinal renders the final state of all progress bars (called once on Stop).
 func (mp *MultiProgress) renderFinal() {
+	if mp.lineMode {
+		mp.announceChanges(mp.orderedBarsLocked())
+		return
+	}
+
 	mp.moveCursorToStart()
 
 	lineCount := 0
@@ -276,6 +308,20 @@ func (mp *MultiProgress) renderFinal() {
 	mp.lastLineCount = 0
 }
 
+// orderedBarsLocked returns the IDs and bars in insertion order; the caller
+// must hold mp.mu.
+func (mp *MultiProgress) orderedBarsLocked() ([]string, []*ProgressSpinner) {
+	ids := make([]string, 0, len(mp.barOrder))
+	bars := make([]*ProgressSpinner, 0, len(mp.barOrder))
+	for _, id := range mp.barOrder {
+		if bar, exists := mp.bars[id]; exists {
+			ids = append(ids, id)
+			bars = append(bars, bar)
+		}
+	}
+	return ids, bars
+}
+
 // buildProgressLine constructs a single progress bar line
 func (mp *MultiProgress) buildProgressLine(bar *ProgressSpinner, progressPct, elapsed float64) string {
 	// Use compact mode for narrow terminals to prevent wrapping and duplication
@@ -284,10 +330,18 @@ func (mp *MultiProgress) buildProgressLine(bar *ProgressSpinner, progressPct, el
 	}
 
 	barWidth := mp.calculateBarWidth()
+	transfer := formatTransfer(bar, elapsed)
+	if transfer != "" {
+		// Shrink the bar to make room for the transfer column
+		barWidth = mp.fitBarWidth(transferWidth + 1)
+	}
 	icon, color := mp.getStatusIconAndColor(bar.status, time.Now())
 	barContent := mp.formatBarContent(bar.status, barWidth, progressPct)
 	desc := truncateString(bar.description, maxDescWidth)
 	timeStr := mp.formatElapsedTime(bar.status, elapsed)
+	if transfer != "" {
+		timeStr += " " + transfer
+	}
 
 	return mp.assembleProgressLine(icon, color, desc, barContent, progressPct, timeStr, bar.status)
 }
@@ -303,8 +357,14 @@ func (mp *MultiProgress) buildCompactLine(bar *ProgressSpinner, progressPct, ela
 		maxDesc = 10 // Minimum description length
 	}
 
-	desc := truncateString(bar.description, maxDesc)
 	timeStr := mp.formatElapsedTime(bar.status, elapsed)
+	if transfer := formatTransfer(bar, elapsed); transfer != "" {
+		timeStr += " " + transfer
+		if maxDesc-len(transfer)-1 >= 10 {
+			maxDesc -= len(transfer) + 1
+		}
+	}
+	desc := truncateString(bar.description, maxDesc)
 
 	if bar.status == TaskStatusPending {
 		return fmt.Sprintf("%s%s%s %s", color, icon, cliout.Reset, desc)
@@ -320,7 +380,13 @@ func (mp *MultiProgress) buildCompactLine(bar *ProgressSpinner, progressPct, ela
 
 // calculateBarWidth determines the width of the progress bar
 func (mp *MultiProgress) calculateBarWidth() int {
-	barWidth := mp.termWidth - iconWidth - maxDescWidth - percentWidth - timeWidth - layoutPadding
+	return mp.fitBarWidth(0)
+}
+
+// fitBarWidth determines the width of the progress bar when reserved extra
+// columns are needed on the line
+func (mp *MultiProgress) fitBarWidth(reserved int) int {
+	barWidth := mp.termWidth - iconWidth - maxDescWidth - percentWidth - timeWidth - layoutPadding - reserved
 	if barWidth < minBarWidth {
 		return minBarWidth
 	}
@@ -332,20 +398,20 @@ func (mp *MultiProgress) calculateBarWidth() int {
 
 // getStatusIconAndColor returns the appropriate icon and color for a task status
 func (mp *MultiProgress) getStatusIconAndColor(status TaskStatus, t time.Time) (string, string) {
-
+	g := currentGlyphs()
 	switch status {
 	case TaskStatusPending:
-		return "○", cliout.Dim
+		return g.pending, cliout.Dim
 	case TaskStatusRunning:
 		return getSpinnerFrame(t), cliout.Cyan
 	case TaskStatusSuccess:
-		return "✓", cliout.Green
+		return g.success, cliout.Green
 	case TaskStatusFailed:
-		return "✗", cliout.Red
+		return g.failed, cliout.Red
 	case TaskStatusSkipped:
 		return "-", cliout.Gray
 	default:
-		return "○", cliout.Dim
+		return g.pending, cliout.Dim
 	}
 }
 
@@ -356,18 +422,19 @@ func (mp *MultiProgress) formatBarContent(status TaskStatus, barWidth int, progr
 		filled = barWidth
 	}
 
+	g := currentGlyphs()
 	switch status {
 	case TaskStatusSuccess:
-		return strings.Repeat("━", barWidth)
+		return strings.Repeat(g.barFilled, barWidth)
 	case TaskStatusFailed:
-		return strings.Repeat("╍", filled) + strings.Repeat("╌", barWidth-filled)
+		return strings.Repeat(g.barFailed, filled) + strings.Repeat(g.barFailedRest, barWidth-filled)
 	case TaskStatusRunning:
 		if filled > 0 {
-			return strings.Repeat("━", filled-1) + "▶" + strings.Repeat("─", barWidth-filled)
+			return strings.Repeat(g.barFilled, filled-1) + g.barHead + strings.Repeat(g.barEmpty, barWidth-filled)
 		}
-		return strings.Repeat("─", barWidth)
+		return strings.Repeat(g.barEmpty, barWidth)
 	default:
-		return strings.Repeat("─", barWidth)
+		return strings.Repeat(g.barEmpty, barWidth)
 	}
 }
 
@@ -477,11 +544,15 @@ func (pb *ProgressSpinner) Increment() {
 	pb.bytesWritten += bytesPerIncrement
 }
 
-// AddBytes adds bytes to the progress tracker.
+// AddBytes adds bytes to the progress tracker. Once real bytes are reported
+// the bar also shows the transferred size and throughput, e.g. "12.3 MiB 2.4 MiB/s".
 func (pb *ProgressSpinner) AddBytes(n int64) {
 	pb.mu.Lock()
 	defer pb.mu.Unlock()
 	pb.bytesWritten += n
+	if n > 0 {
+		pb.trackBytes = true
+	}
 }
 
 // Start marks the task as started and running.
@@ -547,14 +618,70 @@ func (pb *ProgressSpinner) isIncomplete() bool {
 	return pb.status == TaskStatusRunning || pb.status == TaskStatusPending
 }
 
+// glyphSet holds the symbols used to draw icons, bars, and spinners. Every
+// glyph occupies a single terminal column so both sets keep the same layout.
+type glyphSet struct {
+	pending       string
+	success       string
+	failed        string
+	barFilled     string
+	barHead       string
+	barEmpty      string
+	barFailed     string
+	barFailedRest string
+	spinner       []string
+}
+
+var (
+	unicodeGlyphs = glyphSet{
+		pending:       "○",
+		success:       cliout.SymbolCheck,
+		failed:        cliout.SymbolCross,
+		barFilled:     "━",
+		barHead:       "▶",
+		barEmpty:      "─",
+		barFailed:     "╍",
+		barFailedRest: "╌",
+		spinner:       []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+	}
+
+	// asciiGlyphs are used on terminals without Unicode support, such as the
+	// legacy Windows console, where box-drawing and Braille characters render
+	// as garbage.
+	asciiGlyphs = glyphSet{
+		pending:       "o",
+		success:       "*",
+		failed:        "x",
+		barFilled:     "=",
+		barHead:       ">",
+		barEmpty:      "-",
+		barFailed:     "x",
+		barFailedRest: "-",
+		spinner:       []string{"|", "/", "-", "\\"},
+	}
+
+	// unicodeSupported reports whether to use Unicode glyphs. It is a variable
+	// so tests can force either set.
+	unicodeSupported = cliout.SupportsUnicode
+)
+
+// currentGlyphs returns the glyph set for the current terminal.
+func currentGlyphs() *glyphSet {
+	if unicodeSupported() {
+		return &unicodeGlyphs
+	}
+	return &asciiGlyphs
+}
+
 // getSpinnerFrame returns the current spinner character based on time.
 func getSpinnerFrame(t time.Time) string {
-	spinnerChars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	spinnerChars := currentGlyphs().spinner
 	index := (t.UnixNano() / 80_000_000) % int64(len(spinnerChars))
 	return spinnerChars[index]
 }
 
 // SpinnerWriter is an io.Writer that increments the progress bar on each write.
+// The bytes written are shown next to the bar along with the transfer rate.
 type SpinnerWriter struct {
 	bar *ProgressSpinner
 }
@@ -581,12 +708,17 @@ type ProgressResult struct {
 	Error   error
 }
 
-// PrintStatus prints the final status for a completed task.
+// PrintStatus prints the final status for a completed task, using
+// cliout's check and cross symbols or their ASCII fallbacks.
 func PrintStatus(description string, success bool, err error) {
+	check, cross := cliout.SymbolCheck, cliout.SymbolCross
+	if !unicodeSupported() {
+		check, cross = cliout.ASCIICheck, cliout.ASCIICross
+	}
 	if success {
-		fmt.Printf("%s%s%s %s\n", cliout.Green, cliout.SymbolCheck, cliout.Reset, description)
+		fmt.Printf("%s%s%s %s\n", cliout.Green, check, cliout.Reset, description)
 	} else {
-		fmt.Printf("%s%s%s %s\n", cliout.Red, cliout.SymbolCross, cliout.Reset, description)
+		fmt.Printf("%s%s%s %s\n", cliout.Red, cross, cliout.Reset, description)
 	}
 }
 
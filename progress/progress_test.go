@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/testutil"
 )
 
 // TestTaskStatus tests the TaskStatus type and constants
@@ -55,6 +56,23 @@ func TestNewMultiProgress(t *testing.T) {
 	}
 }
 
+// TestNewMultiProgressTermSize tests that COLUMNS sets the render width
+func TestNewMultiProgressTermSize(t *testing.T) {
+	testutil.WithTermSize(t, 100, 30)
+
+	mp := NewMultiProgress()
+	if mp.termWidth != 100 {
+		t.Errorf("termWidth = %d, want 100", mp.termWidth)
+	}
+
+	bar := mp.AddBar("api", "Building api")
+	bar.Complete()
+	line := testutil.StripANSI(mp.buildProgressLine(bar, 100, 1.5))
+	if !strings.Contains(line, "Building api") || strings.Contains(line, "\x1b") {
+		t.Errorf("buildProgressLine() = %q", line)
+	}
+}
+
 // TestAddBar tests adding progress bars
 func TestAddBar(t *testing.T) {
 	mp := NewMultiProgress()
@@ -913,3 +931,88 @@ func TestProgressStatusTransitions(t *testing.T) {
 		t.Errorf("status after Fail() = %q, want %q", bar3.status, TaskStatusFailed)
 	}
 }
+
+// TestASCIIFallback tests that legacy consoles get ASCII glyphs with the same layout widths
+func TestASCIIFallback(t *testing.T) {
+	orig := unicodeSupported
+	defer func() { unicodeSupported = orig }()
+	unicodeSupported = func() bool { return false }
+
+	mp := NewMultiProgress()
+	now := time.Now()
+
+	icons := map[TaskStatus]string{
+		TaskStatusPending: "o",
+		TaskStatusSuccess: "*",
+		TaskStatusFailed:  "x",
+		TaskStatusSkipped: "-",
+	}
+	for status, want := range icons {
+		if icon, _ := mp.getStatusIconAndColor(status, now); icon != want {
+			t.Errorf("icon for %s = %q, want %q", status, icon, want)
+		}
+	}
+
+	bars := []struct {
+		status TaskStatus
+		pct    float64
+		want   string
+	}{
+		{TaskStatusSuccess, 100, "=========="},
+		{TaskStatusRunning, 50, "====>-----"},
+		{TaskStatusRunning, 0, "----------"},
+		{TaskStatusFailed, 30, "xxx-------"},
+		{TaskStatusPending, 0, "----------"},
+	}
+	for _, tt := range bars {
+		if got := mp.formatBarContent(tt.status, 10, tt.pct); got != tt.want {
+			t.Errorf("formatBarContent(%s, %.0f%%) = %q, want %q", tt.status, tt.pct, got, tt.want)
+		}
+	}
+
+	for i := 0; i < 8; i++ {
+		frame := getSpinnerFrame(now.Add(time.Duration(i) * 80 * time.Millisecond))
+		if len(frame) != 1 || !strings.Contains(`|/-\`, frame) {
+			t.Errorf("getSpinnerFrame() = %q, want an ASCII spinner frame", frame)
+		}
+	}
+}
+
+// TestGlyphSetsSameWidth tests that Unicode and ASCII glyphs each occupy one column
+func TestGlyphSetsSameWidth(t *testing.T) {
+	for _, g := range []glyphSet{unicodeGlyphs, asciiGlyphs} {
+		for _, glyph := range []string{g.pending, g.success, g.failed, g.barFilled, g.barHead, g.barEmpty, g.barFailed, g.barFailedRest} {
+			if n := len([]rune(glyph)); n != 1 {
+				t.Errorf("glyph %q has %d runes, want 1", glyph, n)
+			}
+		}
+		for _, frame := range g.spinner {
+			if n := len([]rune(frame)); n != 1 {
+				t.Errorf("spinner frame %q has %d runes, want 1", frame, n)
+			}
+		}
+	}
+}
+
+func TestPrintStatusSymbols(t *testing.T) {
+	orig := unicodeSupported
+	defer func() { unicodeSupported = orig }()
+
+	tests := []struct {
+		unicode bool
+		success bool
+		want    string
+	}{
+		{true, true, cliout.SymbolCheck},
+		{true, false, cliout.SymbolCross},
+		{false, true, cliout.ASCIICheck},
+		{false, false, cliout.ASCIICross},
+	}
+	for _, tt := range tests {
+		unicodeSupported = func() bool { return tt.unicode }
+		got := captureStdout(t, func() { PrintStatus("api", tt.success, nil) })
+		if !strings.Contains(got, tt.want+cliout.Reset+" api") {
+			t.Errorf("PrintStatus(unicode=%v, success=%v) = %q, want symbol %q", tt.unicode, tt.success, got, tt.want)
+		}
+	}
+}
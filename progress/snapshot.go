@@ -0,0 +1,60 @@
+package progress
+
+import "time"
+
+// TaskState is a point-in-time view of one task.
+type TaskState struct {
+	ID          string        `json:"id"`
+	Description string        `json:"description"`
+	Status      TaskStatus    `json:"status"`
+	Progress    float64       `json:"progress"`        // Percentage from 0 to 100
+	Elapsed     time.Duration `json:"elapsed"`         // Zero for pending and skipped tasks
+	Bytes       int64         `json:"bytes,omitempty"` // Bytes reported through AddBytes or a SpinnerWriter
+	Error       string        `json:"error,omitempty"`
+}
+
+// Snapshot returns the state of every task in the order they were added.
+// It is safe to call concurrently with task updates and rendering.
+//
+// Example:
+//
+//	for _, task := range mp.Snapshot() {
+//	    fmt.Printf("%s: %s (%.0f%%)\n", task.Description, task.Status, task.Progress)
+//	}
+func (mp *MultiProgress) Snapshot() []TaskState {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return mp.snapshotLocked()
+}
+
+// snapshotLocked builds the snapshot; the caller must hold mp.mu.
+func (mp *MultiProgress) snapshotLocked() []TaskState {
+	now := time.Now()
+	states := make([]TaskState, 0, len(mp.barOrder))
+	for _, id := range mp.barOrder {
+		bar, exists := mp.bars[id]
+		if !exists {
+			continue
+		}
+		bar.mu.Lock()
+		state := TaskState{
+			ID:          id,
+			Description: bar.description,
+			Status:      bar.status,
+			Error:       bar.errorMsg,
+		}
+		if bar.trackBytes {
+			state.Bytes = bar.bytesWritten
+		}
+		switch bar.status {
+		case TaskStatusSuccess, TaskStatusFailed:
+			state.Elapsed = bar.endTime.Sub(bar.startTime)
+		case TaskStatusRunning:
+			state.Elapsed = now.Sub(bar.startTime)
+		}
+		state.Progress = mp.calculateProgress(bar, state.Elapsed.Seconds())
+		bar.mu.Unlock()
+		states = append(states, state)
+	}
+	return states
+}
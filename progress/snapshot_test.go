@@ -0,0 +1,88 @@
+package progress
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	mp := NewMultiProgress()
+	running := mp.AddBar("install", "Install deps")
+	done := mp.AddBar("build", "Build")
+	failed := mp.AddBar("test", "Test")
+	mp.AddBar("lint", "Lint")
+
+	running.Start()
+	running.AddBytes(estimatedTotalBytes / 2)
+	done.Start()
+	done.Complete()
+	failed.Start()
+	failed.Fail("2 tests failed")
+
+	states := mp.Snapshot()
+	if len(states) != 4 {
+		t.Fatalf("got %d states, want 4", len(states))
+	}
+
+	install := states[0]
+	if install.ID != "install" || install.Status != TaskStatusRunning {
+		t.Errorf("install = %+v", install)
+	}
+	if install.Progress < 49 || install.Progress > 51 {
+		t.Errorf("install progress = %.1f, want about 50", install.Progress)
+	}
+	if install.Elapsed <= 0 {
+		t.Errorf("install elapsed = %v, want > 0", install.Elapsed)
+	}
+
+	if states[1].Status != TaskStatusSuccess || states[1].Progress != 100 {
+		t.Errorf("build = %+v", states[1])
+	}
+	if states[2].Status != TaskStatusFailed || states[2].Error != "2 tests failed" {
+		t.Errorf("test = %+v", states[2])
+	}
+	if lint := states[3]; lint.Status != TaskStatusPending || lint.Progress != 0 || lint.Elapsed != 0 {
+		t.Errorf("lint = %+v", lint)
+	}
+
+	if _, err := json.Marshal(states); err != nil {
+		t.Errorf("snapshot should marshal to JSON: %v", err)
+	}
+}
+
+func TestSnapshotEmpty(t *testing.T) {
+	if states := NewMultiProgress().Snapshot(); len(states) != 0 {
+		t.Errorf("Snapshot() = %v, want empty", states)
+	}
+}
+
+func TestSnapshotConcurrent(t *testing.T) {
+	mp := NewMultiProgress()
+	bars := []*ProgressSpinner{mp.AddBar("a", "A"), mp.AddBar("b", "B")}
+
+	var wg sync.WaitGroup
+	for _, bar := range bars {
+		wg.Add(1)
+		go func(bar *ProgressSpinner) {
+			defer wg.Done()
+			bar.Start()
+			for i := 0; i < 100; i++ {
+				bar.Increment()
+			}
+			bar.Complete()
+		}(bar)
+	}
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		_ = mp.Snapshot()
+	}
+	wg.Wait()
+
+	for _, state := range mp.Snapshot() {
+		if state.Status != TaskStatusSuccess {
+			t.Errorf("%s status = %s, want success", state.ID, state.Status)
+		}
+	}
+}
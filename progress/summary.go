@@ -0,0 +1,95 @@
+package progress
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+// TaskSummary is the final state of one task, as shown in the summary
+// printed after Stop.
+type TaskSummary struct {
+	ID          string        `json:"id"`
+	Description string        `json:"description"`
+	Status      TaskStatus    `json:"status"`
+	Duration    time.Duration `json:"duration"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// SetShowSummary controls whether Stop prints a summary table of every task
+// with its status, duration, and error below the final progress frame.
+func (mp *MultiProgress) SetShowSummary(show bool) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	mp.showSummary = show
+}
+
+// Summary returns the current state of every task in the order they were
+// added. Pending and skipped tasks have a zero Duration.
+func (mp *MultiProgress) Summary() []TaskSummary {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return mp.summaryLocked()
+}
+
+// summaryLocked builds the summary; the caller must hold mp.mu.
+func (mp *MultiProgress) summaryLocked() []TaskSummary {
+	states := mp.snapshotLocked()
+	tasks := make([]TaskSummary, len(states))
+	for i, state := range states {
+		tasks[i] = TaskSummary{
+			ID:          state.ID,
+			Description: state.Description,
+			Status:      state.Status,
+			Duration:    state.Elapsed,
+			Error:       state.Error,
+		}
+	}
+	return tasks
+}
+
+// PrintTaskSummary prints tasks as a table with a status icon, duration, and
+// error column. In JSON mode the tasks are printed as JSON instead.
+//
+// Example:
+//
+//	mp.Stop()
+//	progress.PrintTaskSummary(mp.Summary())
+func PrintTaskSummary(tasks []TaskSummary) {
+	_ = cliout.Print(tasks, func() {
+		if len(tasks) == 0 {
+			return
+		}
+		rows := make([]cliout.TableRow, 0, len(tasks))
+		for _, task := range tasks {
+			duration := "-"
+			if task.Duration > 0 {
+				duration = fmt.Sprintf("%.1fs", task.Duration.Seconds())
+			}
+			rows = append(rows, cliout.TableRow{
+				"Task":     task.Description,
+				"Status":   summaryStatus(task.Status),
+				"Duration": duration,
+				"Error":    task.Error,
+			})
+		}
+		cliout.Newline()
+		cliout.Table([]string{"Task", "Status", "Duration", "Error"}, rows)
+	})
+}
+
+// summaryStatus renders a colored status icon followed by the status name.
+func summaryStatus(status TaskStatus) string {
+	g := currentGlyphs()
+	switch status {
+	case TaskStatusSuccess:
+		return cliout.Green + g.success + cliout.Reset + " " + string(status)
+	case TaskStatusFailed:
+		return cliout.Red + g.failed + cliout.Reset + " " + string(status)
+	case TaskStatusSkipped:
+		return cliout.Gray + "-" + cliout.Reset + " " + string(status)
+	default:
+		return cliout.Dim + g.pending + cliout.Reset + " " + string(status)
+	}
+}
@@ -0,0 +1,129 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+// captureStdout returns everything fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+	fn()
+	_ = w.Close()
+	return <-done
+}
+
+func TestSummary(t *testing.T) {
+	mp := NewMultiProgress()
+	ok := mp.AddBar("web", "Build web")
+	bad := mp.AddBar("api", "Build api")
+	mp.AddBar("docs", "Build docs").Skip()
+	mp.AddBar("db", "Migrate db")
+
+	ok.Start()
+	bad.Start()
+	time.Sleep(10 * time.Millisecond)
+	ok.Complete()
+	bad.Fail("exit status 1")
+
+	tasks := mp.Summary()
+	if len(tasks) != 4 {
+		t.Fatalf("got %d tasks, want 4", len(tasks))
+	}
+	wantIDs := []string{"web", "api", "docs", "db"}
+	wantStatus := []TaskStatus{TaskStatusSuccess, TaskStatusFailed, TaskStatusSkipped, TaskStatusPending}
+	for i, task := range tasks {
+		if task.ID != wantIDs[i] || task.Status != wantStatus[i] {
+			t.Errorf("tasks[%d] = %s %s, want %s %s", i, task.ID, task.Status, wantIDs[i], wantStatus[i])
+		}
+	}
+	if tasks[0].Duration < 10*time.Millisecond {
+		t.Errorf("web duration = %v, want at least 10ms", tasks[0].Duration)
+	}
+	if tasks[1].Error != "exit status 1" {
+		t.Errorf("api error = %q", tasks[1].Error)
+	}
+	if tasks[2].Duration != 0 || tasks[3].Duration != 0 {
+		t.Errorf("skipped and pending tasks should have zero duration, got %v and %v", tasks[2].Duration, tasks[3].Duration)
+	}
+}
+
+func TestStopPrintsSummary(t *testing.T) {
+	mp := NewMultiProgress()
+	mp.SetShowSummary(true)
+	mp.AddBar("web", "Build web").Complete()
+	mp.AddBar("api", "Build api").Fail("compile error")
+
+	output := captureStdout(t, mp.Stop)
+
+	for _, want := range []string{"Task", "Status", "Duration", "Error", "Build web", "success", "Build api", "failed", "compile error"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("summary missing %q:\n%s", want, output)
+		}
+	}
+
+	// A second Stop must not print the summary again.
+	if again := captureStdout(t, mp.Stop); again != "" {
+		t.Errorf("second Stop printed %q", again)
+	}
+}
+
+func TestStopWithoutSummary(t *testing.T) {
+	mp := NewMultiProgress()
+	mp.AddBar("web", "Build web").Complete()
+
+	output := captureStdout(t, mp.Stop)
+	if strings.Contains(output, "Duration") {
+		t.Errorf("summary printed without SetShowSummary:\n%s", output)
+	}
+}
+
+func TestPrintTaskSummaryJSON(t *testing.T) {
+	if err := cliout.SetFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cliout.SetFormat("default") }()
+
+	tasks := []TaskSummary{{ID: "web", Description: "Build web", Status: TaskStatusSuccess, Duration: 1500 * time.Millisecond}}
+	output := captureStdout(t, func() { PrintTaskSummary(tasks) })
+
+	var decoded []TaskSummary
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, output)
+	}
+	if len(decoded) != 1 || decoded[0].ID != "web" || decoded[0].Duration != 1500*time.Millisecond {
+		t.Errorf("decoded = %+v", decoded)
+	}
+}
+
+func TestSummaryStatusASCII(t *testing.T) {
+	old := unicodeSupported
+	unicodeSupported = func() bool { return false }
+	defer func() { unicodeSupported = old }()
+
+	got := summaryStatus(TaskStatusFailed)
+	if !strings.Contains(got, asciiGlyphs.failed) || !strings.HasSuffix(got, "failed") {
+		t.Errorf("summaryStatus(failed) = %q", got)
+	}
+}
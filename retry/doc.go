@@ -0,0 +1,19 @@
+// Package retry provides a generic retry loop with pluggable backoff
+// strategies for Azure Developer CLI extensions.
+//
+// Do calls a function until it succeeds, the attempt budget is exhausted, the
+// error is not retryable, or the context is canceled. Delays between attempts
+// come from a Backoff strategy (Constant, Linear, Exponential) with optional
+// jitter. Wrap an error with Permanent to stop retrying immediately.
+//
+// Example:
+//
+//	err := retry.Do(ctx, func(ctx context.Context) error {
+//	    return client.Send(ctx, req)
+//	}, retry.Options{
+//	    MaxAttempts: 5,
+//	    Backoff:     retry.Exponential(200*time.Millisecond, 5*time.Second),
+//	    Jitter:      0.2,
+//	    RetryIf:     isThrottled,
+//	})
+package retry
@@ -0,0 +1,163 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Defaults applied when Options fields are zero.
+const (
+	DefaultMaxAttempts = 3
+	DefaultBaseDelay   = 100 * time.Millisecond
+	DefaultMaxDelay    = 5 * time.Second
+)
+
+// Backoff returns the delay to wait after the given failed attempt (1-based).
+type Backoff func(attempt int) time.Duration
+
+// Constant waits d between every attempt.
+func Constant(d time.Duration) Backoff {
+	return func(int) time.Duration { return d }
+}
+
+// Linear waits base*attempt: base, 2*base, 3*base, ...
+func Linear(base time.Duration) Backoff {
+	return func(attempt int) time.Duration { return base * time.Duration(attempt) }
+}
+
+// Exponential waits base*2^(attempt-1), capped at maxDelay when maxDelay > 0.
+func Exponential(base, maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt; i++ {
+			d *= 2
+			if maxDelay > 0 && d >= maxDelay {
+				return maxDelay
+			}
+		}
+		if maxDelay > 0 && d > maxDelay {
+			return maxDelay
+		}
+		return d
+	}
+}
+
+// Options configures Do.
+type Options struct {
+	// MaxAttempts is the total number of calls, including the first.
+	// Defaults to DefaultMaxAttempts.
+	MaxAttempts int
+	// Backoff computes the delay between attempts. Defaults to
+	// Exponential(DefaultBaseDelay, DefaultMaxDelay).
+	Backoff Backoff
+	// Jitter randomizes each delay by up to ±Jitter (a fraction between 0
+	// and 1) to avoid synchronized retries.
+	Jitter float64
+	// RetryIf reports whether an error should be retried. Defaults to
+	// retrying every error except context cancellation.
+	RetryIf func(error) bool
+	// OnRetry, if set, is called before sleeping for the next attempt.
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// permanentError marks an error as not retryable.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Do returns it without further attempts. Do unwraps
+// it before returning, so callers see err itself.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do calls fn until it returns nil or retrying stops. It returns the last
+// error from fn, or the context error if ctx is canceled while waiting.
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts Options) error {
+	_, err := DoValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts)
+	return err
+}
+
+// DoValue is like Do for functions that return a value. The value from the
+// successful attempt (or the last attempt) is returned.
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts Options) (T, error) {
+	opts = withDefaults(opts)
+
+	for attempt := 1; ; attempt++ {
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return v, perm.err
+		}
+		if attempt >= opts.MaxAttempts || ctx.Err() != nil || !opts.RetryIf(err) {
+			return v, err
+		}
+
+		delay := applyJitter(opts.Backoff(attempt), opts.Jitter)
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, err, delay)
+		}
+		if err := Sleep(ctx, delay); err != nil {
+			return v, err
+		}
+	}
+}
+
+// Sleep waits for d or until ctx is canceled, returning ctx.Err() in the
+// latter case.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// withDefaults fills zero-valued options.
+func withDefaults(opts Options) Options {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = Exponential(DefaultBaseDelay, DefaultMaxDelay)
+	}
+	if opts.RetryIf == nil {
+		opts.RetryIf = func(err error) bool {
+			return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+		}
+	}
+	return opts
+}
+
+// applyJitter scales d by a random factor in [1-jitter, 1+jitter].
+func applyJitter(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 || d <= 0 {
+		return d
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+	// #nosec G404 -- jitter does not need a cryptographic source
+	factor := 1 + jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(d) * factor)
+}
@@ -0,0 +1,156 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+func TestDo_SucceedsAfterRetries(t *testing.T) {
+	calls := 0
+	var retried []int
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	}, Options{
+		MaxAttempts: 5,
+		Backoff:     Constant(time.Millisecond),
+		OnRetry:     func(attempt int, _ error, _ time.Duration) { retried = append(retried, attempt) },
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if len(retried) != 2 || retried[0] != 1 || retried[1] != 2 {
+		t.Errorf("OnRetry attempts = %v", retried)
+	}
+}
+
+func TestDo_Exhausted(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		return errTransient
+	}, Options{MaxAttempts: 3, Backoff: Constant(0)})
+	if !errors.Is(err, errTransient) {
+		t.Errorf("expected last error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestDo_Defaults(t *testing.T) {
+	calls := 0
+	_ = Do(context.Background(), func(context.Context) error {
+		calls++
+		return errTransient
+	}, Options{Backoff: Constant(0)})
+	if calls != DefaultMaxAttempts {
+		t.Errorf("calls = %d, want %d", calls, DefaultMaxAttempts)
+	}
+}
+
+func TestDo_RetryIf(t *testing.T) {
+	fatal := errors.New("fatal")
+	calls := 0
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		return fatal
+	}, Options{
+		MaxAttempts: 5,
+		Backoff:     Constant(0),
+		RetryIf:     func(err error) bool { return errors.Is(err, errTransient) },
+	})
+	if !errors.Is(err, fatal) || calls != 1 {
+		t.Errorf("expected single call with fatal error, got %d calls, %v", calls, err)
+	}
+}
+
+func TestDo_Permanent(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		return Permanent(errTransient)
+	}, Options{MaxAttempts: 5, Backoff: Constant(0)})
+	if err != errTransient || calls != 1 {
+		t.Errorf("expected unwrapped permanent error after 1 call, got %d calls, %v", calls, err)
+	}
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) should be nil")
+	}
+}
+
+func TestDo_ContextCanceledDuringSleep(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := Do(ctx, func(context.Context) error { return errTransient }, Options{
+		MaxAttempts: 10,
+		Backoff:     Constant(time.Hour),
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context error, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Do did not stop on context cancellation")
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	calls := 0
+	v, err := DoValue(context.Background(), func(context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errTransient
+		}
+		return "ok", nil
+	}, Options{Backoff: Constant(0)})
+	if err != nil || v != "ok" {
+		t.Errorf("DoValue = %q, %v", v, err)
+	}
+}
+
+func TestBackoffStrategies(t *testing.T) {
+	exp := Exponential(100*time.Millisecond, time.Second)
+	wantExp := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, want := range wantExp {
+		if got := exp(i + 1); got != want*time.Millisecond {
+			t.Errorf("Exponential attempt %d = %v, want %v", i+1, got, want*time.Millisecond)
+		}
+	}
+	if got := Exponential(time.Second, 0)(4); got != 8*time.Second {
+		t.Errorf("uncapped Exponential attempt 4 = %v, want 8s", got)
+	}
+
+	lin := Linear(20 * time.Millisecond)
+	if lin(1) != 20*time.Millisecond || lin(4) != 80*time.Millisecond {
+		t.Errorf("Linear = %v, %v", lin(1), lin(4))
+	}
+
+	if Constant(time.Second)(7) != time.Second {
+		t.Error("Constant should ignore attempt")
+	}
+}
+
+func TestApplyJitter(t *testing.T) {
+	base := 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		d := applyJitter(base, 0.5)
+		if d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("jittered delay %v out of range", d)
+		}
+	}
+	if applyJitter(base, 0) != base {
+		t.Error("zero jitter should not change delay")
+	}
+}
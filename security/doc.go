@@ -8,12 +8,17 @@
 // # Key Features
 //
 // - Path validation (prevents directory traversal attacks)
+// - Windows path compatibility checks (ValidateWindowsPathCompat)
 // - Symbolic link resolution and validation
 // - Service name validation (DNS-safe, container-safe identifiers)
+// - Unicode identifier normalization and homoglyph detection (NormalizeIdentifier)
 // - Package manager name validation (allowlist-based)
 // - Script name sanitization (detects shell metacharacters)
+// - Shell command analysis for likely injection points (AnalyzeShellCommand)
 // - Container environment detection
 // - File permission validation (detects world-writable files)
+// - Secret redaction for log and error text (RedactSecrets, IsSensitiveKey)
+// - Secret filtering for child process environments (FilterEnvForChild)
 //
 // # Security Model
 //
@@ -32,6 +37,17 @@
 //   - Validates paths are within expected boundaries
 //   - Handles both absolute and relative paths
 //
+// For repeated checks against the same directories, build a PathValidator once;
+// its Validate method returns the canonical resolved path:
+//
+//	v, err := security.NewPathValidator([]string{projectDir}, security.PathValidatorOptions{AllowNonExistent: true})
+//	path, err := v.Validate("logs/app.log")
+//
+// ValidateWindowsPathCompat, or the PathValidatorOptions.WindowsCompat option,
+// additionally rejects paths that break on Windows on any platform: paths
+// over MAX_PATH, reserved device names such as CON and NUL, and names ending
+// in a dot or space.
+//
 // # Input Sanitization
 //
 // Service names:
@@ -40,11 +56,23 @@
 //   - DNS-safe (RFC 1123)
 //   - Container-safe (Docker naming rules)
 //
+// ValidateServiceNameWithOptions with AllowUnicode accepts non-ASCII names.
+// They are normalized with NormalizeIdentifier (NFC plus case folding) and
+// rejected when they mix scripts or contain invisible or confusable
+// characters, such as a Cyrillic "а" inside an otherwise Latin name:
+//
+//	name, err := security.ValidateServiceNameWithOptions(input, security.ServiceNameOptions{AllowUnicode: true})
+//
 // Script names:
 //   - No shell metacharacters: ; | & $ ` \ < > ( ) { }
 //   - Prevents command injection
 //   - Safe for use in shell commands
 //
+// Composed shell commands (for example user-provided CMD-SHELL health checks)
+// can be checked with AnalyzeShellCommand, which reports unquoted variables,
+// command substitutions, metacharacters inside ${...} expansions, eval, and
+// unterminated quotes so callers can warn before execution.
+//
 // Package managers:
 //   - Allowlist: npm, pip, maven, gradle, dotnet, go
 //   - Prevents arbitrary package manager execution
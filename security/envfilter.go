@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultDeniedEnvVars are removed by FilterEnvForChild unless allowed by the
+// policy, in addition to every name IsSensitiveKey reports. Entries are
+// case-insensitive glob patterns as accepted by path.Match.
+var DefaultDeniedEnvVars = []string{
+	"AZURE_CLIENT_SECRET",
+	"AZURE_CLIENT_CERTIFICATE_PASSWORD",
+	"AZURE_FEDERATED_TOKEN*",
+	"ARM_CLIENT_SECRET",
+	"ARM_OIDC_TOKEN*",
+	"IDENTITY_HEADER",
+	"MSI_SECRET",
+	"ACTIONS_ID_TOKEN_REQUEST_*",
+	"ACTIONS_RUNTIME_TOKEN",
+	"SYSTEM_ACCESSTOKEN",
+	"*_TOKEN",
+	"*_SECRET",
+	"*_PASSWORD",
+}
+
+// EssentialEnvVars are kept in AllowlistOnly mode so child processes can
+// still find executables, temp directories, and the user's profile.
+var EssentialEnvVars = []string{
+	"PATH", "PATHEXT", "HOME", "USER", "USERNAME", "USERPROFILE", "SHELL",
+	"TMPDIR", "TEMP", "TMP", "LANG", "LC_*", "TERM", "TZ",
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC",
+	"APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES*",
+}
+
+// notSensitiveEnvVars match IsSensitiveKey but hold no secret ("PWD" is the
+// working directory, not a password).
+var notSensitiveEnvVars = []string{"PWD", "OLDPWD"}
+
+// EnvPolicy controls which variables FilterEnvForChild passes to a child
+// process. Names are matched case-insensitively and may be glob patterns
+// such as "MYAPP_*".
+type EnvPolicy struct {
+	// Allow passes matching variables through even when the default rules
+	// would remove them, for example a token the child needs.
+	Allow []string
+	// Deny removes matching variables. It takes precedence over Allow.
+	Deny []string
+	// AllowlistOnly removes every variable not matched by Allow or
+	// EssentialEnvVars, for fully isolated children.
+	AllowlistOnly bool
+	// DisableDefaults turns off the default rules (DefaultDeniedEnvVars and
+	// IsSensitiveKey), so only Deny and AllowlistOnly remove variables.
+	DisableDefaults bool
+}
+
+// EnvFilterReport describes what FilterEnvForChild removed.
+type EnvFilterReport struct {
+	// Removed holds the names of the removed variables, sorted. Values are
+	// never included.
+	Removed []string
+}
+
+// FilterEnvForChild returns a copy of env, a list of "KEY=value" entries as
+// returned by os.Environ, without the variables policy denies, so secrets
+// in the parent's environment do not leak into user scripts. By default,
+// variables named in DefaultDeniedEnvVars or reported by IsSensitiveKey
+// (tokens, secrets, passwords, connection strings, ...) are removed.
+// The order of the remaining entries is preserved.
+//
+// Example:
+//
+//	env, report := security.FilterEnvForChild(os.Environ(), security.EnvPolicy{
+//	    Allow: []string{"NPM_TOKEN"},
+//	})
+//	cmd.Env = env
+//	if len(report.Removed) > 0 {
+//	    logutil.Debug("filtered child environment", "removed", report.Removed)
+//	}
+func FilterEnvForChild(env []string, policy EnvPolicy) ([]string, EnvFilterReport) {
+	filtered := make([]string, 0, len(env))
+	var report EnvFilterReport
+	for _, entry := range env {
+		name, _, _ := strings.Cut(entry, "=")
+		// Windows keeps per-drive working directories in entries such as
+		// "=C:=C:\work"; they have no name and are always kept.
+		if name == "" || policy.keep(name) {
+			filtered = append(filtered, entry)
+			continue
+		}
+		report.Removed = append(report.Removed, name)
+	}
+	sort.Strings(report.Removed)
+	return filtered, report
+}
+
+// keep reports whether the variable name passes the policy.
+func (p EnvPolicy) keep(name string) bool {
+	if matchEnvPattern(name, p.Deny) {
+		return false
+	}
+	if matchEnvPattern(name, p.Allow) {
+		return true
+	}
+	if p.AllowlistOnly && !matchEnvPattern(name, EssentialEnvVars) {
+		return false
+	}
+	if p.DisableDefaults {
+		return true
+	}
+	if matchEnvPattern(name, DefaultDeniedEnvVars) {
+		return false
+	}
+	return !IsSensitiveKey(name) || matchEnvPattern(name, notSensitiveEnvVars)
+}
+
+// matchEnvPattern reports whether name matches any of patterns,
+// ignoring case.
+func matchEnvPattern(name string, patterns []string) bool {
+	name = strings.ToUpper(name)
+	for _, pattern := range patterns {
+		if ok, err := path.Match(strings.ToUpper(pattern), name); ok && err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterEnvForChild(t *testing.T) {
+	env := []string{
+		"PATH=/usr/bin",
+		"HOME=/home/dev",
+		"PWD=/src/app",
+		"AZURE_CLIENT_ID=11111111-1111-1111-1111-111111111111",
+		"AZURE_CLIENT_SECRET=s3cr3t",
+		"GITHUB_TOKEN=ghp_abc",
+		"NPM_TOKEN=npm_abc",
+		"DB_PASSWORD=hunter2",
+		"STORAGE_CONNECTION_STRING=DefaultEndpointsProtocol=https;AccountKey=abc",
+		"azure_federated_token_file=/var/run/token",
+		"MYAPP_DEBUG=1",
+		"MYAPP_LICENSE=xyz",
+		"=C:=C:\\work",
+	}
+
+	tests := []struct {
+		name        string
+		policy      EnvPolicy
+		wantKept    []string
+		wantRemoved []string
+	}{
+		{
+			name: "defaults",
+			wantKept: []string{
+				"PATH=/usr/bin", "HOME=/home/dev", "PWD=/src/app",
+				"AZURE_CLIENT_ID=11111111-1111-1111-1111-111111111111",
+				"MYAPP_DEBUG=1", "MYAPP_LICENSE=xyz", "=C:=C:\\work",
+			},
+			wantRemoved: []string{
+				"AZURE_CLIENT_SECRET", "DB_PASSWORD", "GITHUB_TOKEN", "NPM_TOKEN",
+				"STORAGE_CONNECTION_STRING", "azure_federated_token_file",
+			},
+		},
+		{
+			name:   "allow and deny",
+			policy: EnvPolicy{Allow: []string{"npm_token", "MYAPP_*"}, Deny: []string{"MYAPP_LICENSE"}},
+			wantKept: []string{
+				"PATH=/usr/bin", "HOME=/home/dev", "PWD=/src/app",
+				"AZURE_CLIENT_ID=11111111-1111-1111-1111-111111111111",
+				"NPM_TOKEN=npm_abc", "MYAPP_DEBUG=1", "=C:=C:\\work",
+			},
+			wantRemoved: []string{
+				"AZURE_CLIENT_SECRET", "DB_PASSWORD", "GITHUB_TOKEN", "MYAPP_LICENSE",
+				"STORAGE_CONNECTION_STRING", "azure_federated_token_file",
+			},
+		},
+		{
+			name:     "allowlist only",
+			policy:   EnvPolicy{AllowlistOnly: true, Allow: []string{"MYAPP_DEBUG", "DB_PASSWORD"}},
+			wantKept: []string{"PATH=/usr/bin", "HOME=/home/dev", "DB_PASSWORD=hunter2", "MYAPP_DEBUG=1", "=C:=C:\\work"},
+			wantRemoved: []string{
+				"AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "GITHUB_TOKEN", "MYAPP_LICENSE", "NPM_TOKEN",
+				"PWD", "STORAGE_CONNECTION_STRING", "azure_federated_token_file",
+			},
+		},
+		{
+			name:        "defaults disabled",
+			policy:      EnvPolicy{DisableDefaults: true, Deny: []string{"*_SECRET"}},
+			wantKept:    append(append([]string{}, env[:4]...), env[5:]...),
+			wantRemoved: []string{"AZURE_CLIENT_SECRET"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report := FilterEnvForChild(env, tt.policy)
+			if !reflect.DeepEqual(got, tt.wantKept) {
+				t.Errorf("kept = %v\nwant %v", got, tt.wantKept)
+			}
+			if !reflect.DeepEqual(report.Removed, tt.wantRemoved) {
+				t.Errorf("removed = %v\nwant %v", report.Removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestFilterEnvForChild_Empty(t *testing.T) {
+	got, report := FilterEnvForChild(nil, EnvPolicy{})
+	if len(got) != 0 || len(report.Removed) != 0 {
+		t.Errorf("FilterEnvForChild(nil) = %v, %v", got, report)
+	}
+}
+
+func TestMatchEnvPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     bool
+	}{
+		{"GITHUB_TOKEN", []string{"*_TOKEN"}, true},
+		{"github_token", []string{"*_TOKEN"}, true},
+		{"TOKENIZER", []string{"*_TOKEN"}, false},
+		{"LC_ALL", []string{"LC_*"}, true},
+		{"PATH", []string{"[bad"}, false},
+		{"PATH", nil, false},
+	}
+	for _, tt := range tests {
+		if got := matchEnvPattern(tt.name, tt.patterns); got != tt.want {
+			t.Errorf("matchEnvPattern(%q, %v) = %v, want %v", tt.name, tt.patterns, got, tt.want)
+		}
+	}
+}
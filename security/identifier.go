@@ -0,0 +1,196 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// unicodeServiceNamePattern is the Unicode-aware counterpart of serviceNamePattern:
+// a letter or digit first, then letters, digits, combining marks, dot, underscore, or hyphen.
+var unicodeServiceNamePattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}\p{M}._-]{0,62}$`)
+
+// latinConfusables holds Cyrillic and Greek lowercase letters that render
+// identically (or nearly so) to a Latin letter in common fonts.
+var latinConfusables = map[rune]bool{
+	// Cyrillic
+	'а': true, 'е': true, 'о': true, 'р': true, 'с': true, 'у': true, 'х': true,
+	'і': true, 'ј': true, 'ѕ': true, 'һ': true, 'ӏ': true, 'ԁ': true, 'ԛ': true,
+	'ԝ': true, 'ү': true,
+	// Greek
+	'α': true, 'ο': true, 'ν': true, 'ι': true, 'κ': true, 'ρ': true, 'υ': true,
+}
+
+// allowedScriptMixes lists script combinations that are routinely written
+// together and therefore are not treated as suspicious (UTS #39 "highly restrictive").
+var allowedScriptMixes = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// NormalizeIdentifier returns the canonical form of an identifier: NFC
+// normalized and Unicode case folded, so that visually and semantically equal
+// names compare equal with ==.
+//
+// suspicious reports whether the identifier looks like a spoofing attempt:
+//   - it mixes scripts (for example Latin and Cyrillic), other than the
+//     combinations customary for Chinese, Japanese, and Korean text
+//   - it contains invisible format characters such as zero-width spaces,
+//     joiners, or bidirectional controls
+//   - it contains fullwidth or mathematical alphanumeric forms that mimic ASCII
+//   - every letter belongs to a single non-Latin script but is a lookalike of a
+//     Latin letter (for example Cyrillic "аре" standing in for "ape")
+//
+// A suspicious identifier should be rejected or shown to the user with a
+// warning; the normalized value alone does not make it safe.
+func NormalizeIdentifier(s string) (normalized string, suspicious bool) {
+	normalized = norm.NFC.String(cases.Fold().String(norm.NFC.String(s)))
+	return normalized, isSuspiciousIdentifier(normalized)
+}
+
+// isSuspiciousIdentifier applies the checks documented on NormalizeIdentifier
+// to an already normalized identifier.
+func isSuspiciousIdentifier(s string) bool {
+	scripts := make(map[string]bool)
+	allConfusable := true
+	letters := 0
+
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Cf, r):
+			return true
+		case r >= 0xFF00 && r <= 0xFFEF:
+			return true
+		case r >= 0x1D400 && r <= 0x1D7FF:
+			return true
+		}
+
+		script := scriptOf(r)
+		if script == "" {
+			continue
+		}
+		scripts[script] = true
+
+		if unicode.IsLetter(r) {
+			letters++
+			if script == "Latin" || !latinConfusables[r] {
+				allConfusable = false
+			}
+		}
+	}
+
+	if len(scripts) > 1 && !isAllowedScriptMix(scripts) {
+		return true
+	}
+
+	return letters > 0 && allConfusable
+}
+
+// scriptOf returns the name of the Unicode script r belongs to, or "" for
+// characters shared between scripts (Common and Inherited).
+func scriptOf(r rune) string {
+	if r < utf8.RuneSelf {
+		if unicode.IsLetter(r) {
+			return "Latin"
+		}
+		return ""
+	}
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return ""
+	}
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// isAllowedScriptMix reports whether every script in scripts belongs to one of
+// the allowedScriptMixes combinations.
+func isAllowedScriptMix(scripts map[string]bool) bool {
+	for _, mix := range allowedScriptMixes {
+		covered := true
+		for script := range scripts {
+			if !containsString(mix, script) {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceNameOptions configures ValidateServiceNameWithOptions.
+type ServiceNameOptions struct {
+	// AllowEmpty accepts the empty string (for optional parameters).
+	AllowEmpty bool
+
+	// AllowUnicode accepts non-ASCII letters, digits, and combining marks.
+	// The name is passed through NormalizeIdentifier first and rejected if it
+	// is suspicious (mixed scripts, invisible characters, or confusables).
+	AllowUnicode bool
+}
+
+// ValidateServiceNameWithOptions validates a service name like
+// ValidateServiceName and returns the name to use from then on.
+//
+// Without AllowUnicode the rules are those of ValidateServiceName and the name
+// is returned unchanged. With AllowUnicode the returned name is the
+// NormalizeIdentifier form, and the 63-character limit counts runes.
+func ValidateServiceNameWithOptions(name string, opts ServiceNameOptions) (string, error) {
+	if name == "" {
+		if opts.AllowEmpty {
+			return "", nil
+		}
+		return "", fmt.Errorf("%w: service name cannot be empty", ErrInvalidServiceName)
+	}
+
+	pattern := serviceNamePattern
+	length := len(name)
+	if opts.AllowUnicode {
+		normalized, suspicious := NormalizeIdentifier(name)
+		if suspicious {
+			return "", fmt.Errorf("%w: contains mixed scripts, invisible, or confusable characters", ErrInvalidServiceName)
+		}
+		name = normalized
+		pattern = unicodeServiceNamePattern
+		length = utf8.RuneCountInString(name)
+	}
+
+	if length > 63 {
+		return "", fmt.Errorf("%w: exceeds maximum length of 63 characters", ErrInvalidServiceName)
+	}
+
+	if !pattern.MatchString(name) {
+		return "", fmt.Errorf("%w: must start with alphanumeric and contain only alphanumeric, underscore, hyphen, or dot", ErrInvalidServiceName)
+	}
+
+	// Extra check for path traversal attempts
+	if strings.Contains(name, "..") || strings.Contains(name, "/") || strings.Contains(name, "\\") {
+		return "", fmt.Errorf("%w: contains invalid path characters", ErrInvalidServiceName)
+	}
+
+	return name, nil
+}
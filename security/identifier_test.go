@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeIdentifier(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantNormalized string
+		wantSuspicious bool
+	}{
+		{name: "plain ascii", input: "api", wantNormalized: "api"},
+		{name: "case folded", input: "MyService", wantNormalized: "myservice"},
+		{name: "digits and punctuation", input: "web-01.v2_a", wantNormalized: "web-01.v2_a"},
+		{name: "decomposed to composed", input: "cafe\u0301", wantNormalized: "caf\u00e9"},
+		{name: "composed stays composed", input: "Café", wantNormalized: "café"},
+		{name: "sharp s folds", input: "Straße", wantNormalized: "strasse"},
+		{name: "single script greek", input: "δελτα", wantNormalized: "δελτα"},
+		{name: "single script cyrillic", input: "сервис", wantNormalized: "сервис"},
+		{name: "japanese mix", input: "api日本サービス", wantNormalized: "api日本サービス"},
+		{name: "korean mix", input: "web한국", wantNormalized: "web한국"},
+		{name: "latin with cyrillic a", input: "pаypal", wantNormalized: "pаypal", wantSuspicious: true},
+		{name: "latin with greek omicron", input: "gοogle", wantNormalized: "gοogle", wantSuspicious: true},
+		{name: "whole script cyrillic confusable", input: "аре", wantNormalized: "аре", wantSuspicious: true},
+		{name: "zero width space", input: "ap\u200bi", wantNormalized: "ap\u200bi", wantSuspicious: true},
+		{name: "zero width joiner", input: "api\u200d", wantNormalized: "api\u200d", wantSuspicious: true},
+		{name: "bidi override", input: "api\u202eexe", wantNormalized: "api\u202eexe", wantSuspicious: true},
+		{name: "fullwidth letters", input: "ａｐｉ", wantNormalized: "ａｐｉ", wantSuspicious: true},
+		{name: "mathematical bold", input: "\U0001d41a\U0001d429\U0001d422", wantNormalized: "\U0001d41a\U0001d429\U0001d422", wantSuspicious: true},
+		{name: "empty", input: "", wantNormalized: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, suspicious := NormalizeIdentifier(tt.input)
+			if got != tt.wantNormalized {
+				t.Errorf("NormalizeIdentifier(%q) normalized = %q, want %q", tt.input, got, tt.wantNormalized)
+			}
+			if suspicious != tt.wantSuspicious {
+				t.Errorf("NormalizeIdentifier(%q) suspicious = %v, want %v", tt.input, suspicious, tt.wantSuspicious)
+			}
+		})
+	}
+}
+
+func TestNormalizeIdentifier_EquivalentFormsCompareEqual(t *testing.T) {
+	a, _ := NormalizeIdentifier("Café")
+	b, _ := NormalizeIdentifier("CAFÉ")
+	if a != b {
+		t.Errorf("NormalizeIdentifier() = %q and %q, want equal", a, b)
+	}
+}
+
+func TestValidateServiceNameWithOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    ServiceNameOptions
+		want    string
+		wantErr bool
+	}{
+		{name: "ascii unchanged", input: "MyService", want: "MyService"},
+		{name: "ascii rejects unicode", input: "café", wantErr: true},
+		{name: "empty rejected", input: "", wantErr: true},
+		{name: "empty allowed", input: "", opts: ServiceNameOptions{AllowEmpty: true}, want: ""},
+		{name: "unicode normalized", input: "CAFÉ", opts: ServiceNameOptions{AllowUnicode: true}, want: "café"},
+		{name: "unicode single script", input: "сервис-1", opts: ServiceNameOptions{AllowUnicode: true}, want: "сервис-1"},
+		{name: "unicode ascii folded", input: "Web.API", opts: ServiceNameOptions{AllowUnicode: true}, want: "web.api"},
+		{name: "unicode mixed script rejected", input: "pаypal", opts: ServiceNameOptions{AllowUnicode: true}, wantErr: true},
+		{name: "unicode zero width rejected", input: "ap\u200bi", opts: ServiceNameOptions{AllowUnicode: true}, wantErr: true},
+		{name: "unicode symbol rejected", input: "api☃", opts: ServiceNameOptions{AllowUnicode: true}, wantErr: true},
+		{name: "unicode leading hyphen rejected", input: "-été", opts: ServiceNameOptions{AllowUnicode: true}, wantErr: true},
+		{name: "unicode traversal rejected", input: "a..b", opts: ServiceNameOptions{AllowUnicode: true}, wantErr: true},
+		{name: "unicode 63 runes", input: strings.Repeat("é", 63), opts: ServiceNameOptions{AllowUnicode: true}, want: strings.Repeat("é", 63)},
+		{name: "unicode 64 runes", input: strings.Repeat("é", 64), opts: ServiceNameOptions{AllowUnicode: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateServiceNameWithOptions(tt.input, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateServiceNameWithOptions(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidServiceName) {
+					t.Errorf("ValidateServiceNameWithOptions(%q) error = %v, want ErrInvalidServiceName", tt.input, err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ValidateServiceNameWithOptions(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
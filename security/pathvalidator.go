@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathValidatorOptions configures a PathValidator.
+type PathValidatorOptions struct {
+	// AllowSymlinkEscape accepts paths that lie inside an allowed base but
+	// resolve, through a symbolic link, to a location outside every base.
+	AllowSymlinkEscape bool
+	// AllowNonExistent accepts paths that do not exist yet, such as output
+	// files about to be created. Missing paths are rejected otherwise.
+	AllowNonExistent bool
+	// WindowsCompat also rejects paths that would not work on Windows, as
+	// checked by ValidateWindowsPathCompat, on every platform.
+	WindowsCompat bool
+}
+
+// PathValidator validates paths against a fixed set of allowed base
+// directories and returns their canonical form. Build one per set of bases
+// and reuse it instead of calling ValidatePathWithinBases repeatedly.
+type PathValidator struct {
+	opts PathValidatorOptions
+	// bases holds each allowed base as an absolute path; realBases holds
+	// the same bases with symbolic links resolved.
+	bases     []string
+	realBases []string
+}
+
+// NewPathValidator returns a validator that only accepts paths inside one of
+// bases. With no bases, any well-formed path is accepted. Bases are resolved
+// once, here; they do not need to exist.
+//
+// Example:
+//
+//	v, err := security.NewPathValidator([]string{projectDir}, security.PathValidatorOptions{AllowNonExistent: true})
+//	if err != nil {
+//	    return err
+//	}
+//	out, err := v.Validate("build/output.json") // relative to projectDir
+func NewPathValidator(bases []string, opts PathValidatorOptions) (*PathValidator, error) {
+	v := &PathValidator{opts: opts}
+	for _, base := range bases {
+		if base == "" {
+			return nil, fmt.Errorf("%w: empty base directory", ErrInvalidPath)
+		}
+		abs, err := filepath.Abs(base)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot resolve base %q: %w", ErrInvalidPath, base, err)
+		}
+		real, err := resolveExisting(abs)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot resolve base %q: %w", ErrInvalidPath, base, err)
+		}
+		v.bases = append(v.bases, abs)
+		v.realBases = append(v.realBases, real)
+	}
+	return v, nil
+}
+
+// Validate checks path and returns its canonical absolute form with
+// symbolic links resolved. Relative paths are interpreted against the first
+// base, or the working directory when there are no bases.
+//
+// Errors wrap ErrInvalidPath for empty, missing (unless AllowNonExistent),
+// or unresolvable paths, ErrWindowsIncompatiblePath (with WindowsCompat) for
+// paths Windows cannot use, and ErrPathTraversal for paths containing ".."
+// elements or lying outside every base.
+func (v *PathValidator) Validate(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("%w: empty path", ErrInvalidPath)
+	}
+	for _, elem := range strings.FieldsFunc(filepath.ToSlash(path), func(r rune) bool { return r == '/' }) {
+		if elem == ".." {
+			return "", fmt.Errorf("%w: path contains parent directory reference", ErrPathTraversal)
+		}
+	}
+
+	abs := path
+	if !filepath.IsAbs(abs) {
+		if len(v.bases) > 0 {
+			abs = filepath.Join(v.bases[0], abs)
+		} else {
+			var err error
+			if abs, err = filepath.Abs(abs); err != nil {
+				return "", fmt.Errorf("%w: cannot resolve path: %w", ErrInvalidPath, err)
+			}
+		}
+	}
+	abs = filepath.Clean(abs)
+
+	if v.opts.WindowsCompat {
+		if err := ValidateWindowsPathCompat(abs); err != nil {
+			return "", err
+		}
+	}
+
+	if !v.opts.AllowNonExistent {
+		if _, err := os.Lstat(abs); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidPath, err)
+		}
+	}
+
+	real, err := resolveExisting(abs)
+	if err != nil {
+		return "", fmt.Errorf("%w: cannot resolve symbolic links: %w", ErrInvalidPath, err)
+	}
+
+	if len(v.bases) == 0 || withinAny(real, v.realBases) {
+		return real, nil
+	}
+	if v.opts.AllowSymlinkEscape && (withinAny(abs, v.bases) || withinAny(abs, v.realBases)) {
+		return real, nil
+	}
+	return "", fmt.Errorf("%w: path is outside allowed directories", ErrPathTraversal)
+}
+
+// resolveExisting resolves symbolic links in the longest existing prefix of
+// the absolute path abs and appends the remaining, not yet created, elements.
+func resolveExisting(abs string) (string, error) {
+	var missing []string
+	current := abs
+	for {
+		real, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			for i := len(missing) - 1; i >= 0; i-- {
+				real = filepath.Join(real, missing[i])
+			}
+			return real, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return abs, nil
+		}
+		missing = append(missing, filepath.Base(current))
+		current = parent
+	}
+}
+
+// withinAny reports whether path equals or is nested inside one of bases.
+func withinAny(path string, bases []string) bool {
+	for _, base := range bases {
+		if path == base || strings.HasPrefix(path, strings.TrimSuffix(base, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// realTempDir returns t.TempDir with symbolic links resolved (macOS /var).
+func realTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestPathValidator_Validate(t *testing.T) {
+	base := realTempDir(t)
+	outside := realTempDir(t)
+	existing := filepath.Join(base, "sub", "file.txt")
+	if err := os.MkdirAll(filepath.Dir(existing), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	strict, err := NewPathValidator([]string{base}, PathValidatorOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lenient, err := NewPathValidator([]string{base}, PathValidatorOptions{AllowNonExistent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		validator *PathValidator
+		path      string
+		want      string
+		wantErr   error
+	}{
+		{"absolute existing", strict, existing, existing, nil},
+		{"relative to base", strict, filepath.Join("sub", "file.txt"), existing, nil},
+		{"base itself", strict, base, base, nil},
+		{"unclean path", strict, base + string(filepath.Separator) + "sub" + string(filepath.Separator) + "." + string(filepath.Separator) + "file.txt", existing, nil},
+		{"missing rejected", strict, filepath.Join(base, "new.txt"), "", ErrInvalidPath},
+		{"missing allowed", lenient, filepath.Join("out", "new.txt"), filepath.Join(base, "out", "new.txt"), nil},
+		{"outside base", lenient, filepath.Join(outside, "x.txt"), "", ErrPathTraversal},
+		{"parent reference", lenient, filepath.Join("sub", "..", "..", "x"), "", ErrPathTraversal},
+		{"empty", lenient, "", "", ErrInvalidPath},
+		{"dots in name allowed", lenient, "file..txt", filepath.Join(base, "file..txt"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.validator.Validate(tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Validate(%q) error = %v, want %v", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate(%q) error = %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("Validate(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathValidator_Symlinks(t *testing.T) {
+	base := realTempDir(t)
+	outside := realTempDir(t)
+	target := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(target, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(base, "link.txt")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	strict, err := NewPathValidator([]string{base}, PathValidatorOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.Validate(link); !errors.Is(err, ErrPathTraversal) {
+		t.Errorf("expected symlink escape to be rejected, got %v", err)
+	}
+
+	permissive, err := NewPathValidator([]string{base}, PathValidatorOptions{AllowSymlinkEscape: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := permissive.Validate(link)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if got != target {
+		t.Errorf("Validate = %q, want resolved target %q", got, target)
+	}
+}
+
+func TestPathValidator_NoBasesAndMultipleBases(t *testing.T) {
+	first := realTempDir(t)
+	second := realTempDir(t)
+
+	unbound, err := NewPathValidator(nil, PathValidatorOptions{AllowNonExistent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := unbound.Validate(filepath.Join(second, "x")); err != nil || got != filepath.Join(second, "x") {
+		t.Errorf("Validate without bases = %q, %v", got, err)
+	}
+
+	multi, err := NewPathValidator([]string{first, second}, PathValidatorOptions{AllowNonExistent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := multi.Validate(filepath.Join(second, "y")); err != nil {
+		t.Errorf("expected path in second base to be accepted: %v", err)
+	}
+	if got, _ := multi.Validate("rel"); got != filepath.Join(first, "rel") {
+		t.Errorf("relative path should resolve against first base, got %q", got)
+	}
+
+	if _, err := NewPathValidator([]string{""}, PathValidatorOptions{}); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected ErrInvalidPath for empty base, got %v", err)
+	}
+}
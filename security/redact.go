@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// RedactedPlaceholder replaces secret values removed by RedactSecrets.
+const RedactedPlaceholder = "***REDACTED***"
+
+var (
+	// connectionStringSecretPattern matches secret-bearing segments of Azure
+	// connection strings (e.g. "AccountKey=...;", "SharedAccessKey=...").
+	connectionStringSecretPattern = regexp.MustCompile(`(?i)\b(AccountKey|SharedAccessKey|SharedAccessSignature|Password|Pwd|ClientSecret|AccessKey)=([^;\s]+)`)
+
+	// bearerTokenPattern matches bearer tokens in Authorization header values.
+	bearerTokenPattern = regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+
+	// jwtPattern matches JSON Web Tokens (three base64url segments, header starts with "eyJ").
+	jwtPattern = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+
+	// sasSignaturePattern matches the signature parameter of SAS URLs.
+	sasSignaturePattern = regexp.MustCompile(`(?i)([?&]sig=)[^&\s"']+`)
+
+	// urlPasswordPattern matches the password component of URL userinfo.
+	urlPasswordPattern = regexp.MustCompile(`(://[^:/?#@\s]+:)[^@/?#\s]+@`)
+
+	// sensitiveKeyWords are the key words, or runs of adjacent key words
+	// joined together, that mark a key as sensitive.
+	sensitiveKeyWords = map[string]bool{
+		"token":                 true,
+		"secret":                true,
+		"password":              true,
+		"passwd":                true,
+		"connectionstring":      true,
+		"apikey":                true,
+		"accountkey":            true,
+		"accesskey":             true,
+		"sharedaccesskey":       true,
+		"sharedaccesssignature": true,
+		"credential":            true,
+		"credentials":           true,
+		"authorization":         true,
+		"privatekey":            true,
+	}
+)
+
+// RedactSecrets masks well-known secret formats embedded in s.
+// It redacts connection string keys and passwords, bearer tokens, JWTs,
+// SAS signatures, and passwords in URL userinfo. Text that does not look
+// like a secret is returned unchanged.
+func RedactSecrets(s string) string {
+	if s == "" {
+		return s
+	}
+	s = connectionStringSecretPattern.ReplaceAllString(s, "${1}="+RedactedPlaceholder)
+	s = bearerTokenPattern.ReplaceAllString(s, "${1}"+RedactedPlaceholder)
+	s = jwtPattern.ReplaceAllString(s, RedactedPlaceholder)
+	s = sasSignaturePattern.ReplaceAllString(s, "${1}"+RedactedPlaceholder)
+	s = urlPasswordPattern.ReplaceAllString(s, "${1}"+RedactedPlaceholder+"@")
+	return s
+}
+
+// IsSensitiveKey reports whether a field or variable name likely holds a secret.
+// The key is split into lowercase words at '_', '-', '.', and camelCase
+// boundaries, and a word (or run of adjacent words) must match a sensitive
+// word exactly, so "client_secret", "ClientSecret", and "storage.accountKey"
+// match while "max_tokens" and "PWD" do not.
+func IsSensitiveKey(key string) bool {
+	words := splitKey(key)
+	for i := range words {
+		joined := ""
+		for _, w := range words[i:] {
+			joined += w
+			if sensitiveKeyWords[joined] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitKey splits key into lowercase words at '_', '-', '.', and camelCase
+// boundaries. An acronym followed by a word is split before the word's first
+// letter, so "APIKey" becomes "api", "key".
+func splitKey(key string) []string {
+	var words []string
+	runes := []rune(key)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, strings.ToLower(string(runes[start:end])))
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == '.':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush(i)
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
@@ -82,28 +82,10 @@ func ValidatePath(path string) error {
 // - Not contain path traversal sequences
 //
 // If allowEmpty is true, empty strings are accepted (for optional parameters).
+// Use ValidateServiceNameWithOptions to accept normalized Unicode names.
 func ValidateServiceName(name string, allowEmpty bool) error {
-	if name == "" {
-		if allowEmpty {
-			return nil
-		}
-		return fmt.Errorf("%w: service name cannot be empty", ErrInvalidServiceName)
-	}
-
-	if len(name) > 63 {
-		return fmt.Errorf("%w: exceeds maximum length of 63 characters", ErrInvalidServiceName)
-	}
-
-	if !serviceNamePattern.MatchString(name) {
-		return fmt.Errorf("%w: must start with alphanumeric and contain only alphanumeric, underscore, hyphen, or dot", ErrInvalidServiceName)
-	}
-
-	// Extra check for path traversal attempts
-	if strings.Contains(name, "..") || strings.Contains(name, "/") || strings.Contains(name, "\\") {
-		return fmt.Errorf("%w: contains invalid path characters", ErrInvalidServiceName)
-	}
-
-	return nil
+	_, err := ValidateServiceNameWithOptions(name, ServiceNameOptions{AllowEmpty: allowEmpty})
+	return err
 }
 
 // ValidatePackageManager checks if the package manager name is allowed.
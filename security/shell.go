@@ -0,0 +1,242 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"strings"
+)
+
+// ShellFindingKind classifies a potential injection point in a shell command.
+type ShellFindingKind string
+
+const (
+	// ShellFindingUnquotedVariable is a variable expansion outside double
+	// quotes. Its value is subject to word splitting and globbing, so
+	// user-controlled values can inject extra arguments.
+	ShellFindingUnquotedVariable ShellFindingKind = "unquoted-variable"
+	// ShellFindingCommandSubstitution is a $(...) or `...` substitution,
+	// which runs a nested command.
+	ShellFindingCommandSubstitution ShellFindingKind = "command-substitution"
+	// ShellFindingInterpolationMetachar is a ${...} expansion whose
+	// operator word contains shell metacharacters or nested substitutions.
+	ShellFindingInterpolationMetachar ShellFindingKind = "interpolation-metachar"
+	// ShellFindingEval is a use of eval, which re-parses its arguments as
+	// shell code.
+	ShellFindingEval ShellFindingKind = "eval"
+	// ShellFindingUnterminatedQuote is a quote or substitution that is never
+	// closed, which usually means the string was composed incorrectly.
+	ShellFindingUnterminatedQuote ShellFindingKind = "unterminated-quote"
+)
+
+// ShellSeverity ranks shell findings.
+type ShellSeverity string
+
+const (
+	// ShellSeverityMedium findings are risky when the value is user-controlled.
+	ShellSeverityMedium ShellSeverity = "medium"
+	// ShellSeverityHigh findings execute or re-parse code.
+	ShellSeverityHigh ShellSeverity = "high"
+)
+
+// ShellFinding describes a likely injection point in a shell command.
+type ShellFinding struct {
+	Kind     ShellFindingKind `json:"kind"`
+	Severity ShellSeverity    `json:"severity"`
+	// Offset is the byte offset of the finding in the analyzed command.
+	Offset int `json:"offset"`
+	// Text is the offending fragment, such as "$NAME" or "$(whoami)".
+	Text    string `json:"text"`
+	Message string `json:"message"`
+}
+
+// shellMetachars are characters that change command structure when they
+// appear inside an expansion's operator word.
+const shellMetachars = ";&|<>`\n"
+
+// AnalyzeShellCommand tokenizes a POSIX shell command and reports likely
+// injection points: unquoted variable expansions, command substitutions,
+// metacharacters inside ${...} interpolations, eval, and unterminated
+// quotes. It does not execute or modify the command. Findings are ordered
+// by offset; an empty result means nothing suspicious was found, not that
+// the command is safe.
+//
+// Use it to warn before running user-provided CMD-SHELL health checks or
+// hook scripts:
+//
+//	for _, f := range security.AnalyzeShellCommand(hook.Run) {
+//	    cliout.Warning("%s at offset %d: %s (%s)", f.Kind, f.Offset, f.Text, f.Message)
+//	}
+func AnalyzeShellCommand(cmd string) []ShellFinding {
+	var findings []ShellFinding
+	add := func(kind ShellFindingKind, severity ShellSeverity, start, end int, msg string) {
+		findings = append(findings, ShellFinding{Kind: kind, Severity: severity, Offset: start, Text: cmd[start:end], Message: msg})
+	}
+
+	inSingle, inDouble := false, false
+	quoteStart := 0
+	commandStart := true
+
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+
+		if inSingle {
+			if c == '\'' {
+				inSingle = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '\\':
+			i++ // escaped character
+			commandStart = false
+			continue
+		case c == '\'' && !inDouble:
+			inSingle, quoteStart = true, i
+			commandStart = false
+			continue
+		case c == '"':
+			if !inDouble {
+				quoteStart = i
+			}
+			inDouble = !inDouble
+			commandStart = false
+			continue
+		case c == '`':
+			end := strings.IndexByte(cmd[i+1:], '`')
+			if end < 0 {
+				add(ShellFindingUnterminatedQuote, ShellSeverityHigh, i, len(cmd), "unterminated backtick substitution")
+				return findings
+			}
+			end += i + 2
+			add(ShellFindingCommandSubstitution, ShellSeverityHigh, i, end, "backtick substitution runs a nested command")
+			i = end - 1
+			commandStart = false
+			continue
+		case c == '$':
+			next := i + consumeExpansion(cmd[i:], inDouble, add, i)
+			if next < 0 {
+				return findings
+			}
+			if next > i+1 {
+				i = next - 1
+				commandStart = false
+			}
+			continue
+		}
+
+		if inDouble {
+			continue
+		}
+		switch {
+		case strings.IndexByte(";&|(\n", c) >= 0:
+			commandStart = true
+		case c == ' ' || c == '\t':
+		case commandStart:
+			end := i
+			for end < len(cmd) && !isShellWordBreak(cmd[end]) {
+				end++
+			}
+			if cmd[i:end] == "eval" {
+				add(ShellFindingEval, ShellSeverityHigh, i, end, "eval re-parses its arguments as shell code")
+			}
+			i = end - 1
+			commandStart = false
+		}
+	}
+
+	if inSingle || inDouble {
+		add(ShellFindingUnterminatedQuote, ShellSeverityHigh, quoteStart, len(cmd), "unterminated quoted string")
+	}
+	return findings
+}
+
+// consumeExpansion analyzes the $-expansion at the start of s, which begins
+// at offset base of the full command, and returns its length. It returns -1
+// after recording an unterminated-quote finding.
+func consumeExpansion(s string, inDouble bool, add func(ShellFindingKind, ShellSeverity, int, int, string), base int) int {
+	if len(s) < 2 {
+		return 1
+	}
+
+	switch c := s[1]; {
+	case c == '(' && !strings.HasPrefix(s, "$(("):
+		end := matchClose(s, 1, '(', ')')
+		if end < 0 {
+			add(ShellFindingUnterminatedQuote, ShellSeverityHigh, base, base+len(s), "unterminated command substitution")
+			return -1
+		}
+		add(ShellFindingCommandSubstitution, ShellSeverityHigh, base, base+end+1, "command substitution runs a nested command")
+		return end + 1
+
+	case c == '{':
+		end := matchClose(s, 1, '{', '}')
+		if end < 0 {
+			add(ShellFindingUnterminatedQuote, ShellSeverityHigh, base, base+len(s), "unterminated ${...} expansion")
+			return -1
+		}
+		body := s[2:end]
+		if strings.ContainsAny(body, shellMetachars) || strings.Contains(body, "$(") {
+			add(ShellFindingInterpolationMetachar, ShellSeverityHigh, base, base+end+1, "expansion contains shell metacharacters or nested substitution")
+		} else if !inDouble {
+			add(ShellFindingUnquotedVariable, ShellSeverityMedium, base, base+end+1, `unquoted expansion is subject to word splitting; wrap it in double quotes`)
+		}
+		return end + 1
+
+	case c == '_' || isASCIILetter(c) || c == '@' || c == '*' || (c >= '0' && c <= '9'):
+		end := 2
+		if c == '_' || isASCIILetter(c) {
+			for end < len(s) && (s[end] == '_' || isASCIILetter(s[end]) || (s[end] >= '0' && s[end] <= '9')) {
+				end++
+			}
+		}
+		if !inDouble {
+			add(ShellFindingUnquotedVariable, ShellSeverityMedium, base, base+end, `unquoted expansion is subject to word splitting; wrap it in double quotes`)
+		}
+		return end
+	}
+	// $?, $$, $!, $#, $- and a lone $ are not user-controlled.
+	return 1
+}
+
+// matchClose returns the index in s of the delimiter closing the one at
+// open, honoring nesting and quotes, or -1.
+func matchClose(s string, open int, openCh, closeCh byte) int {
+	depth := 0
+	inSingle, inDouble := false, false
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inSingle:
+			if c == '\'' {
+				inSingle = false
+			}
+		case c == '\\':
+			i++
+		case c == '\'' && !inDouble:
+			inSingle = true
+		case c == '"':
+			inDouble = !inDouble
+		case inDouble:
+		case c == openCh:
+			depth++
+		case c == closeCh:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isShellWordBreak reports whether c ends an unquoted shell word.
+func isShellWordBreak(c byte) bool {
+	return strings.IndexByte(" \t\n;&|()<>'\"`$\\", c) >= 0
+}
+
+// isASCIILetter reports whether c is an ASCII letter.
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,143 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"testing"
+)
+
+func TestAnalyzeShellCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		cmd   string
+		kinds []ShellFindingKind
+		texts []string
+	}{
+		{name: "plain command", cmd: "curl -f http://localhost:8080/health"},
+		{name: "quoted variable", cmd: `curl -f "http://localhost:$PORT/health"`},
+		{name: "single quoted", cmd: `echo '$HOME $(whoami) ; eval'`},
+		{name: "escaped dollar", cmd: `echo \$HOME`},
+		{name: "special parameters", cmd: `test $? -eq 0 && echo $$`},
+		{name: "arithmetic", cmd: `echo "$((1 + 2))"`},
+		{
+			name:  "unquoted variable",
+			cmd:   "curl -f http://localhost:$PORT/health",
+			kinds: []ShellFindingKind{ShellFindingUnquotedVariable},
+			texts: []string{"$PORT"},
+		},
+		{
+			name:  "unquoted braced variable",
+			cmd:   "ls ${DIR}/bin",
+			kinds: []ShellFindingKind{ShellFindingUnquotedVariable},
+			texts: []string{"${DIR}"},
+		},
+		{
+			name:  "positional and all args",
+			cmd:   "run $1 $@",
+			kinds: []ShellFindingKind{ShellFindingUnquotedVariable, ShellFindingUnquotedVariable},
+			texts: []string{"$1", "$@"},
+		},
+		{
+			name:  "command substitution",
+			cmd:   `echo "$(whoami)"`,
+			kinds: []ShellFindingKind{ShellFindingCommandSubstitution},
+			texts: []string{"$(whoami)"},
+		},
+		{
+			name:  "nested command substitution",
+			cmd:   "echo $(cat $(ls))",
+			kinds: []ShellFindingKind{ShellFindingCommandSubstitution},
+			texts: []string{"$(cat $(ls))"},
+		},
+		{
+			name:  "backticks",
+			cmd:   "echo `id`",
+			kinds: []ShellFindingKind{ShellFindingCommandSubstitution},
+			texts: []string{"`id`"},
+		},
+		{
+			name:  "metachar in interpolation",
+			cmd:   `echo "${NAME:-x; rm -rf /}"`,
+			kinds: []ShellFindingKind{ShellFindingInterpolationMetachar},
+			texts: []string{"${NAME:-x; rm -rf /}"},
+		},
+		{
+			name:  "substitution in interpolation",
+			cmd:   `echo "${NAME:-$(id)}"`,
+			kinds: []ShellFindingKind{ShellFindingInterpolationMetachar},
+		},
+		{
+			name:  "eval",
+			cmd:   `eval "$CMD"`,
+			kinds: []ShellFindingKind{ShellFindingEval},
+			texts: []string{"eval"},
+		},
+		{
+			name:  "eval after separator",
+			cmd:   `cd /app && eval "$CMD"`,
+			kinds: []ShellFindingKind{ShellFindingEval},
+		},
+		{name: "eval as argument", cmd: "echo eval"},
+		{
+			name:  "unterminated double quote",
+			cmd:   `echo "hello`,
+			kinds: []ShellFindingKind{ShellFindingUnterminatedQuote},
+			texts: []string{`"hello`},
+		},
+		{
+			name:  "unterminated single quote",
+			cmd:   `echo 'hello`,
+			kinds: []ShellFindingKind{ShellFindingUnterminatedQuote},
+		},
+		{
+			name:  "unterminated substitution",
+			cmd:   "echo $(id",
+			kinds: []ShellFindingKind{ShellFindingUnterminatedQuote},
+		},
+		{
+			name:  "unterminated backtick",
+			cmd:   "echo `id",
+			kinds: []ShellFindingKind{ShellFindingUnterminatedQuote},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := AnalyzeShellCommand(tt.cmd)
+			if len(findings) != len(tt.kinds) {
+				t.Fatalf("AnalyzeShellCommand(%q) = %+v, want kinds %v", tt.cmd, findings, tt.kinds)
+			}
+			for i, f := range findings {
+				if f.Kind != tt.kinds[i] {
+					t.Errorf("finding %d kind = %q, want %q", i, f.Kind, tt.kinds[i])
+				}
+				if i < len(tt.texts) && f.Text != tt.texts[i] {
+					t.Errorf("finding %d text = %q, want %q", i, f.Text, tt.texts[i])
+				}
+				if tt.cmd[f.Offset:f.Offset+len(f.Text)] != f.Text {
+					t.Errorf("finding %d offset %d does not locate %q", i, f.Offset, f.Text)
+				}
+				if f.Message == "" {
+					t.Errorf("finding %d has empty message", i)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeShellCommand_Severity(t *testing.T) {
+	findings := AnalyzeShellCommand("echo $NAME $(id)")
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(findings))
+	}
+	if findings[0].Severity != ShellSeverityMedium {
+		t.Errorf("unquoted variable severity = %q, want %q", findings[0].Severity, ShellSeverityMedium)
+	}
+	if findings[1].Severity != ShellSeverityHigh {
+		t.Errorf("command substitution severity = %q, want %q", findings[1].Severity, ShellSeverityHigh)
+	}
+	if findings[0].Offset >= findings[1].Offset {
+		t.Errorf("findings not ordered by offset: %+v", findings)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrWindowsIncompatiblePath indicates a path that cannot be used on Windows
+// without the \\?\ extended-length prefix.
+var ErrWindowsIncompatiblePath = errors.New("path is not compatible with Windows")
+
+const (
+	// WindowsMaxPath is the classic MAX_PATH limit, including the
+	// terminating NUL, so paths may be at most WindowsMaxPath-1 characters.
+	WindowsMaxPath = 260
+	// windowsMaxComponent is the longest file or directory name NTFS allows.
+	windowsMaxComponent = 255
+)
+
+// windowsReservedNames are device names that cannot be used as a file or
+// directory name, with or without an extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true, "CONIN$": true, "CONOUT$": true,
+}
+
+// ValidateWindowsPathCompat checks that path can be used on Windows. It
+// works on every platform, so tools running on Linux or macOS can reject
+// names that would break for Windows users of the same project.
+//
+// It rejects paths longer than MAX_PATH, reserved device names (CON, PRN,
+// AUX, NUL, COM0-COM9, LPT0-LPT9, also with an extension such as "nul.txt"),
+// names ending in a dot or space, names longer than 255 characters, and the
+// characters < > : " | ? * and control characters. Both / and \ are treated
+// as separators. Paths with the \\?\ prefix bypass the Win32 limits, so
+// only the name length is checked for them; \\.\ device paths skip the name
+// checks as well.
+//
+// Errors wrap both ErrInvalidPath and ErrWindowsIncompatiblePath.
+//
+// Example:
+//
+//	if err := security.ValidateWindowsPathCompat(filepath.Join(outDir, name)); err != nil {
+//	    return fmt.Errorf("output file: %w", err)
+//	}
+func ValidateWindowsPathCompat(path string) error {
+	if path == "" {
+		return windowsPathError("empty path")
+	}
+
+	slashed := strings.ReplaceAll(path, `\`, `/`)
+	extended := strings.HasPrefix(slashed, "//?/")
+	device := strings.HasPrefix(slashed, "//./")
+	if !extended {
+		if n := len(utf16.Encode([]rune(path))); n >= WindowsMaxPath {
+			return windowsPathError(fmt.Sprintf(
+				"path is %d characters, exceeding MAX_PATH (%d); shorten it, prefix it with \\\\?\\, or enable long path support",
+				n, WindowsMaxPath-1))
+		}
+	}
+
+	rest := stripWindowsVolume(path)
+	for _, name := range strings.FieldsFunc(rest, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if n := len(utf16.Encode([]rune(name))); n > windowsMaxComponent {
+			return windowsPathError(fmt.Sprintf("name %q is %d characters, exceeding %d", truncateName(name), n, windowsMaxComponent))
+		}
+		if extended || device || name == "." || name == ".." {
+			continue
+		}
+		if err := validateWindowsName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateWindowsName checks a single path component.
+func validateWindowsName(name string) error {
+	for _, r := range name {
+		if r < 32 || strings.ContainsRune(`<>:"|?*`, r) {
+			return windowsPathError(fmt.Sprintf("name %q contains invalid character %q", name, r))
+		}
+	}
+	if last := name[len(name)-1]; last == '.' || last == ' ' {
+		return windowsPathError(fmt.Sprintf("name %q ends with a dot or space, which Windows strips", name))
+	}
+	if isWindowsReservedName(name) {
+		return windowsPathError(fmt.Sprintf("name %q is a reserved device name", name))
+	}
+	return nil
+}
+
+// isWindowsReservedName reports whether name, ignoring any extension and
+// trailing spaces, is a reserved device name.
+func isWindowsReservedName(name string) bool {
+	base, _, _ := strings.Cut(name, ".")
+	base = strings.ToUpper(strings.TrimRight(base, " "))
+	if windowsReservedNames[base] {
+		return true
+	}
+	if len(base) >= 4 && (strings.HasPrefix(base, "COM") || strings.HasPrefix(base, "LPT")) {
+		switch base[3:] {
+		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "¹", "²", "³":
+			return true
+		}
+	}
+	return false
+}
+
+// stripWindowsVolume removes a drive letter, UNC server and share, or
+// device prefix so the remainder can be split into names.
+func stripWindowsVolume(path string) string {
+	slashed := strings.ReplaceAll(path, `\`, `/`)
+	switch {
+	case strings.HasPrefix(slashed, "//?/") || strings.HasPrefix(slashed, "//./"):
+		rest := slashed[4:]
+		if strings.HasPrefix(strings.ToUpper(rest), "UNC/") {
+			return skipElements(rest[4:], 2)
+		}
+		return stripDrive(rest)
+	case strings.HasPrefix(slashed, "//"):
+		return skipElements(slashed[2:], 2)
+	default:
+		return stripDrive(slashed)
+	}
+}
+
+func stripDrive(path string) string {
+	if len(path) >= 2 && path[1] == ':' && isASCIILetter(path[0]) {
+		return path[2:]
+	}
+	return path
+}
+
+// skipElements drops the first n slash-separated elements of path.
+func skipElements(path string, n int) string {
+	for ; n > 0; n-- {
+		i := strings.IndexByte(path, '/')
+		if i < 0 {
+			return ""
+		}
+		path = path[i+1:]
+	}
+	return path
+}
+
+func truncateName(name string) string {
+	if runes := []rune(name); len(runes) > 32 {
+		return string(runes[:32]) + "..."
+	}
+	return name
+}
+
+func windowsPathError(msg string) error {
+	return fmt.Errorf("%w: %w: %s", ErrInvalidPath, ErrWindowsIncompatiblePath, msg)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package security
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateWindowsPathCompat(t *testing.T) {
+	long := `C:\` + strings.Repeat(`abcdefghij\`, 26) + "file.txt"
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"simple relative", `src\app\main.go`, false},
+		{"drive absolute", `C:\Users\me\project\azure.yaml`, false},
+		{"forward slashes", "src/app/main.go", false},
+		{"unix absolute", "/home/me/project/azure.yaml", false},
+		{"UNC", `\\server\share\dir\file.txt`, false},
+		{"dot elements", `.\src\..\file.txt`, false},
+		{"reserved name", `logs\CON`, true},
+		{"reserved lowercase with extension", "out/nul.txt", true},
+		{"reserved with trailing space before extension", "out/aux .log", true},
+		{"COM port", `C:\temp\com1`, true},
+		{"LPT superscript", "lpt¹.txt", true},
+		{"not reserved prefix", "console.log", false},
+		{"not reserved COM10", "com10.txt", false},
+		{"trailing dot", `docs\readme.`, true},
+		{"trailing space", "docs/notes ", true},
+		{"invalid char", `data\a<b>.txt`, true},
+		{"colon in name", "data/a:b.txt", true},
+		{"control char", "data/a\x01b", true},
+		{"too long", long, true},
+		{"extended prefix allows long", `\\?\` + long, false},
+		{"extended prefix allows reserved", `\\?\C:\temp\NUL`, false},
+		{"device path", `\\.\COM1`, false},
+		{"component too long", "dir/" + strings.Repeat("x", 256), true},
+		{"empty", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWindowsPathCompat(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateWindowsPathCompat(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if err != nil && (!errors.Is(err, ErrWindowsIncompatiblePath) || !errors.Is(err, ErrInvalidPath)) {
+				t.Errorf("error %v should wrap ErrWindowsIncompatiblePath and ErrInvalidPath", err)
+			}
+		})
+	}
+}
+
+func TestValidateWindowsPathCompat_MaxPathGuidance(t *testing.T) {
+	err := ValidateWindowsPathCompat(`C:\` + strings.Repeat("a", 300))
+	if err == nil || !strings.Contains(err.Error(), `\\?\`) {
+		t.Errorf("expected MAX_PATH error mentioning the \\\\?\\ prefix, got %v", err)
+	}
+
+	// Exactly MAX_PATH-1 characters is allowed.
+	if err := ValidateWindowsPathCompat(`C:\` + strings.Repeat("a", 200) + `\` + strings.Repeat("b", WindowsMaxPath-205)); err != nil {
+		t.Errorf("path of %d characters rejected: %v", WindowsMaxPath-1, err)
+	}
+}
+
+func TestPathValidator_WindowsCompat(t *testing.T) {
+	base := t.TempDir()
+
+	lenient, err := NewPathValidator([]string{base}, PathValidatorOptions{AllowNonExistent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lenient.Validate("out/nul.txt"); err != nil {
+		t.Errorf("without WindowsCompat: %v", err)
+	}
+
+	strict, err := NewPathValidator([]string{base}, PathValidatorOptions{AllowNonExistent: true, WindowsCompat: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.Validate("out/nul.txt"); !errors.Is(err, ErrWindowsIncompatiblePath) {
+		t.Errorf("with WindowsCompat, error = %v, want ErrWindowsIncompatiblePath", err)
+	}
+	got, err := strict.Validate("out/app.log")
+	if err != nil {
+		t.Fatalf("with WindowsCompat, valid path rejected: %v", err)
+	}
+	if filepath.Base(got) != "app.log" {
+		t.Errorf("Validate = %q", got)
+	}
+}
@@ -0,0 +1,278 @@
+package semverutil
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// partialPattern matches a version that may omit trailing parts or use
+	// x/X/* wildcards, e.g. "1", "1.2", "1.2.x", "*".
+	partialPattern = regexp.MustCompile(`^v?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+	// operatorSpacePattern joins an operator with a following version ("> = 1" is not valid).
+	operatorSpacePattern = regexp.MustCompile(`(>=|<=|>|<|=|\^|~)\s+`)
+)
+
+// comparator is a single primitive comparison produced by desugaring.
+type comparator struct {
+	op string // one of "=", ">", ">=", "<", "<="
+	v  Version
+	// explicitPre is true when the user wrote a pre-release on this version,
+	// allowing pre-releases of the same major.minor.patch to match.
+	explicitPre bool
+}
+
+func (c comparator) matches(v Version) bool {
+	cmp := v.Compare(c.v)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+// Constraint is a parsed version range.
+type Constraint struct {
+	// IncludePrerelease lets a pre-release version satisfy the constraint
+	// whenever its precedence does, like npm's includePrerelease option, so
+	// "1.11.0-beta.1" satisfies ">=1.10.0". By default the pre-release rule
+	// in the package documentation applies.
+	IncludePrerelease bool
+
+	raw string
+	// sets is a union of intersections.
+	sets [][]comparator
+}
+
+// ParseConstraint parses an npm-style range. See the package documentation
+// for the supported syntax.
+func ParseConstraint(s string) (*Constraint, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("version constraint cannot be empty")
+	}
+
+	c := &Constraint{raw: s}
+	for _, group := range strings.Split(s, "||") {
+		set, err := parseSet(group)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		c.sets = append(c.sets, set)
+	}
+	return c, nil
+}
+
+// String returns the constraint as written.
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether v satisfies the constraint.
+func (c *Constraint) Check(v Version) bool {
+	for _, set := range c.sets {
+		if setMatches(set, v, c.IncludePrerelease) {
+			return true
+		}
+	}
+	return false
+}
+
+// Satisfies parses version and constraint and reports whether the version
+// satisfies the constraint.
+func Satisfies(version, constraint string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v), nil
+}
+
+// setMatches checks every comparator in set plus, unless includePre is
+// set, the pre-release rule.
+func setMatches(set []comparator, v Version, includePre bool) bool {
+	for _, c := range set {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	if includePre || !v.IsPrerelease() {
+		return true
+	}
+	for _, c := range set {
+		if c.explicitPre && c.v.sameCore(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSet parses one intersection (the text between "||").
+func parseSet(group string) ([]comparator, error) {
+	group = strings.TrimSpace(group)
+	if group == "" {
+		return nil, fmt.Errorf("empty range")
+	}
+
+	if lo, hi, ok := strings.Cut(group, " - "); ok {
+		return parseHyphenRange(strings.TrimSpace(lo), strings.TrimSpace(hi))
+	}
+
+	group = operatorSpacePattern.ReplaceAllString(strings.ReplaceAll(group, ",", " "), "$1")
+	var set []comparator
+	for _, token := range strings.Fields(group) {
+		op, rest := splitOperator(token)
+		p, err := parsePartial(rest)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, desugar(op, p)...)
+	}
+	return set, nil
+}
+
+// parseHyphenRange converts "lo - hi" to comparators.
+func parseHyphenRange(lo, hi string) ([]comparator, error) {
+	plo, err := parsePartial(lo)
+	if err != nil {
+		return nil, err
+	}
+	phi, err := parsePartial(hi)
+	if err != nil {
+		return nil, err
+	}
+	set := desugar(">=", plo)
+	return append(set, desugar("<=", phi)...), nil
+}
+
+// splitOperator separates a leading operator from a version token.
+func splitOperator(token string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if rest, ok := strings.CutPrefix(token, op); ok {
+			return op, rest
+		}
+	}
+	return "", token
+}
+
+// partial is a version with n (0-3) specified numeric parts.
+type partial struct {
+	major, minor, patch int
+	n                   int
+	pre                 string
+}
+
+func (p partial) version() Version {
+	return Version{Major: p.major, Minor: p.minor, Patch: p.patch, Prerelease: p.pre}
+}
+
+// parsePartial parses a possibly incomplete version.
+func parsePartial(s string) (partial, error) {
+	m := partialPattern.FindStringSubmatch(s)
+	if m == nil {
+		return partial{}, fmt.Errorf("invalid version %q", s)
+	}
+	var p partial
+	nums := []*int{&p.major, &p.minor, &p.patch}
+	for i, part := range m[1:4] {
+		if part == "" || part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return partial{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*nums[i] = n
+		p.n++
+	}
+	if m[4] != "" {
+		if p.n != 3 {
+			return partial{}, fmt.Errorf("invalid version %q: pre-release requires major.minor.patch", s)
+		}
+		p.pre = m[4]
+	}
+	return p, nil
+}
+
+// floor returns the lowest pre-release of a version, used for exclusive upper bounds.
+func floor(major, minor, patch int) Version {
+	return Version{Major: major, Minor: minor, Patch: patch, Prerelease: "0"}
+}
+
+// nextPartial returns the exclusive upper bound for a partial version:
+// 1 -> 2.0.0-0, 1.2 -> 1.3.0-0.
+func nextPartial(p partial) Version {
+	if p.n == 1 {
+		return floor(p.major+1, 0, 0)
+	}
+	return floor(p.major, p.minor+1, 0)
+}
+
+// desugar expands an operator applied to a partial version into primitive comparators.
+func desugar(op string, p partial) []comparator {
+	v := p.version()
+	explicit := p.pre != ""
+
+	if p.n == 0 {
+		if op == ">" || op == "<" {
+			// Nothing is greater or less than every version.
+			return []comparator{{op: "<", v: floor(0, 0, 0)}}
+		}
+		return nil
+	}
+
+	switch op {
+	case "^":
+		var upper Version
+		switch {
+		case p.major > 0 || p.n == 1:
+			upper = floor(p.major+1, 0, 0)
+		case p.minor > 0 || p.n == 2:
+			upper = floor(0, p.minor+1, 0)
+		default:
+			upper = floor(0, 0, p.patch+1)
+		}
+		return []comparator{{op: ">=", v: v, explicitPre: explicit}, {op: "<", v: upper}}
+	case "~":
+		upper := floor(p.major, p.minor+1, 0)
+		if p.n == 1 {
+			upper = floor(p.major+1, 0, 0)
+		}
+		return []comparator{{op: ">=", v: v, explicitPre: explicit}, {op: "<", v: upper}}
+	}
+
+	if p.n == 3 {
+		if op == "" {
+			op = "="
+		}
+		return []comparator{{op: op, v: v, explicitPre: explicit}}
+	}
+
+	switch op {
+	case ">":
+		next := nextPartial(p)
+		next.Prerelease = ""
+		return []comparator{{op: ">=", v: next}}
+	case ">=":
+		return []comparator{{op: ">=", v: v}}
+	case "<":
+		return []comparator{{op: "<", v: floor(p.major, p.minor, 0)}}
+	case "<=":
+		return []comparator{{op: "<", v: nextPartial(p)}}
+	default:
+		return []comparator{{op: ">=", v: v}, {op: "<", v: nextPartial(p)}}
+	}
+}
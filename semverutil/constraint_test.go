@@ -0,0 +1,132 @@
+package semverutil
+
+import "testing"
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		// Comparisons
+		{"1.2.3", "1.2.3", true},
+		{"1.2.4", "=1.2.3", false},
+		{"1.11.0", ">=1.10.0", true},
+		{"1.9.9", ">=1.10.0", false},
+		{"1.10.0", ">1.10.0", false},
+		{"1.10.0", "< 2.0.0", true},
+		{"1.5.0", ">=1.2.0 <2.0.0", true},
+		{"2.0.0", ">=1.2.0, <2.0.0", false},
+
+		// Caret
+		{"1.9.0", "^1.2.3", true},
+		{"2.0.0", "^1.2.3", false},
+		{"1.2.2", "^1.2.3", false},
+		{"0.2.9", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.3", "^0.0.3", true},
+		{"0.0.4", "^0.0.3", false},
+		{"1.9.0", "^1.2", true},
+		{"0.0.9", "^0.0", true},
+		{"0.1.0", "^0.0", false},
+
+		// Tilde
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"1.2.0", "~1.2", true},
+		{"1.9.0", "~1", true},
+		{"2.0.0", "~1", false},
+
+		// Wildcards and partials
+		{"1.2.7", "1.2.x", true},
+		{"1.3.0", "1.2.x", false},
+		{"1.9.9", "1", true},
+		{"3.4.5", "*", true},
+		{"1.3.0", ">1.2", true},
+		{"1.2.9", ">1.2", false},
+		{"1.2.9", "<=1.2", true},
+		{"1.1.9", "<1.2", true},
+		{"1.2.0", "<1.2", false},
+
+		// Hyphen ranges
+		{"1.2.3", "1.2.3 - 2.3.4", true},
+		{"2.3.4", "1.2.3 - 2.3.4", true},
+		{"2.3.5", "1.2.3 - 2.3.4", false},
+		{"2.3.9", "1.2 - 2.3", true},
+		{"2.4.0", "1.2 - 2.3", false},
+
+		// Unions
+		{"2.1.0", "^1.2.0 || ^2.0.0", true},
+		{"3.0.0", "^1.2.0 || ^2.0.0", false},
+
+		// Pre-releases
+		{"1.3.0-beta", "^1.2.0", false},
+		{"1.3.0-beta", "*", false},
+		{"1.3.0-beta.2", ">=1.3.0-beta.1", true},
+		{"1.3.0-alpha", ">=1.3.0-beta.1", false},
+		{"1.4.0-beta.2", ">=1.3.0-beta.1", false},
+		{"1.2.3-rc.1", "^1.2.3-beta", true},
+		{"2.0.0-beta", "^1.2.3", false},
+
+		// Go-style v prefix
+		{"v1.2.3", "^v1.2.0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			got, err := Satisfies(tt.version, tt.constraint)
+			if err != nil {
+				t.Fatalf("Satisfies: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Satisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintIncludePrerelease(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.11.0-beta.1", ">=1.10.0", true},
+		{"1.10.0-beta.1", ">=1.10.0", false},
+		{"1.3.0-beta", "^1.2.0", true},
+		{"2.0.0-beta", "^1.2.3", false},
+		{"1.3.0-beta", "*", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.IncludePrerelease = true
+			if got := c.Check(MustParse(tt.version)); got != tt.want {
+				t.Errorf("Check(%q) with IncludePrerelease = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, bad := range []string{"", "   ", ">=", "~>1.0", "1.2.3 ||", "1.2-beta", "abc"} {
+		if _, err := ParseConstraint(bad); err == nil {
+			t.Errorf("ParseConstraint(%q) should fail", bad)
+		}
+	}
+	if _, err := Satisfies("bad", ">=1.0.0"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
+
+func TestConstraintString(t *testing.T) {
+	c, err := ParseConstraint("^1.2.0 || ^2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.String() != "^1.2.0 || ^2.0.0" {
+		t.Errorf("String() = %q", c.String())
+	}
+}
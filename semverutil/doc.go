@@ -0,0 +1,30 @@
+// Package semverutil provides semantic version parsing, comparison, and
+// constraint checking for Azure Developer CLI extensions.
+//
+// Versions follow Semantic Versioning 2.0.0 with an optional leading "v"
+// (Go style). Constraints follow npm conventions:
+//
+//   - Comparisons: =1.2.3, >1.2.3, >=1.2.3, <1.2.3, <=1.2.3
+//   - Caret: ^1.2.3 (>=1.2.3 <2.0.0), ^0.2.3 (>=0.2.3 <0.3.0)
+//   - Tilde: ~1.2.3 (>=1.2.3 <1.3.0)
+//   - Wildcards and partials: 1.2.x, 1.2, 1, *
+//   - Hyphen ranges: 1.2.3 - 2.3.4
+//   - Intersections separated by spaces or commas: >=1.2.0 <2.0.0
+//   - Unions separated by ||: ^1.2.0 || ^2.0.0
+//
+// Pre-release versions only satisfy a range if a comparator in the same
+// intersection names the same major.minor.patch with a pre-release, so
+// "1.3.0-beta" does not satisfy "^1.2.0" but "1.3.0-beta.2" satisfies
+// ">=1.3.0-beta.1". Set Constraint.IncludePrerelease to order pre-releases
+// like any other version instead.
+//
+// Example:
+//
+//	ok, err := semverutil.Satisfies("1.11.0", ">=1.10.0")
+//	if err != nil {
+//	    return err
+//	}
+//	if semverutil.MustParse("v2.0.0").Compare(semverutil.MustParse("1.9.9")) > 0 {
+//	    fmt.Println("upgrade available")
+//	}
+package semverutil
@@ -0,0 +1,81 @@
+package semverutil
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}},
+		{"v0.10.0", Version{Minor: 10}},
+		{" 1.0.0-beta.1+build.7 ", Version{Major: 1, Prerelease: "beta.1", Build: "build.7"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "1", "1.2", "01.2.3", "1.2.3-", "a.b.c", "1.2.3.4"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if got := MustParse("v1.2.3-rc.1+abc").String(); got != "1.2.3-rc.1+abc" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestMustParsePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	MustParse("not-a-version")
+}
+
+func TestCompare(t *testing.T) {
+	// Ordered per the Semantic Versioning 2.0.0 specification example.
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		c, err := Compare(ordered[i], ordered[i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != -1 {
+			t.Errorf("Compare(%s, %s) = %d, want -1", ordered[i], ordered[i+1], c)
+		}
+		if MustParse(ordered[i+1]).Compare(MustParse(ordered[i])) != 1 {
+			t.Errorf("Compare(%s, %s) should be 1", ordered[i+1], ordered[i])
+		}
+	}
+
+	if c, _ := Compare("1.0.0+a", "v1.0.0+b"); c != 0 {
+		t.Errorf("build metadata should be ignored, got %d", c)
+	}
+	if _, err := Compare("1.0.0", "bad"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
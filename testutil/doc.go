@@ -2,9 +2,17 @@
 //
 // This package includes helpers for:
 //   - Capturing stdout during test execution (CaptureOutput)
+//   - Capturing stderr or both streams (CaptureStderr, CaptureAll)
+//   - Setting environment variables with automatic restore (SetEnv, UnsetEnv, SetEnvs)
 //   - Locating test fixture directories (FindTestData)
 //   - Creating temporary directories with automatic cleanup (TempDir)
 //   - Common string assertions (Contains)
+//   - Real child processes and stale PIDs (StartDummyProcess, DeadPID)
+//   - TCP port fixtures (FreePort, OccupyPort)
+//   - Golden file comparison with a -update flag (Golden)
+//   - ANSI stripping and fake terminal sizes (StripANSI, WithTermSize)
+//   - Scripted HTTP health endpoints (NewHealthServer)
+//   - Key Vault resolution without Azure (FakeKeyVaultResolver, FakeCredential)
 //
 // All functions use t.Helper() for proper test line reporting.
 //
@@ -0,0 +1,50 @@
+package testutil
+
+import (
+	"os"
+	"testing"
+)
+
+// SetEnv sets an environment variable for the duration of the test using
+// t.Setenv, so the previous value (or absence) is restored automatically.
+// Like t.Setenv, it panics when called from a test that uses t.Parallel(),
+// because the environment is shared by the whole process.
+//
+// Example:
+//
+//	testutil.SetEnv(t, "AZD_DEBUG", "true")
+func SetEnv(t *testing.T, key, value string) {
+	t.Helper()
+	t.Setenv(key, value)
+}
+
+// UnsetEnv removes an environment variable for the duration of the test.
+// The previous value is restored automatically. Like SetEnv, it must not be
+// used from parallel tests.
+//
+// Example:
+//
+//	testutil.UnsetEnv(t, "CODESPACES")
+func UnsetEnv(t *testing.T, key string) {
+	t.Helper()
+
+	// t.Setenv enforces the parallel-test guard and registers the restore.
+	t.Setenv(key, "")
+	if err := os.Unsetenv(key); err != nil {
+		t.Fatalf("Failed to unset environment variable %s: %v", key, err)
+	}
+}
+
+// SetEnvs sets multiple environment variables for the duration of the test.
+// Each variable is restored automatically via t.Cleanup.
+//
+// Example:
+//
+//	testutil.SetEnvs(t, map[string]string{"CODESPACES": "", "KUBERNETES_SERVICE_HOST": ""})
+func SetEnvs(t *testing.T, vars map[string]string) {
+	t.Helper()
+
+	for key, value := range vars {
+		SetEnv(t, key, value)
+	}
+}
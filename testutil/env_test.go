@@ -0,0 +1,82 @@
+package testutil
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSetEnv(t *testing.T) {
+	const key = "AZD_CORE_TESTUTIL_SETENV"
+	os.Unsetenv(key)
+
+	t.Run("sets value", func(t *testing.T) {
+		SetEnv(t, key, "value")
+		if got := os.Getenv(key); got != "value" {
+			t.Errorf("expected %q, got %q", "value", got)
+		}
+	})
+
+	if _, ok := os.LookupEnv(key); ok {
+		t.Error("expected variable to be unset after subtest cleanup")
+	}
+}
+
+func TestSetEnvRestoresPrevious(t *testing.T) {
+	const key = "AZD_CORE_TESTUTIL_RESTORE"
+	t.Setenv(key, "original")
+
+	t.Run("override", func(t *testing.T) {
+		SetEnv(t, key, "override")
+		if got := os.Getenv(key); got != "override" {
+			t.Errorf("expected %q, got %q", "override", got)
+		}
+	})
+
+	if got := os.Getenv(key); got != "original" {
+		t.Errorf("expected original value restored, got %q", got)
+	}
+}
+
+func TestUnsetEnv(t *testing.T) {
+	const key = "AZD_CORE_TESTUTIL_UNSET"
+	t.Setenv(key, "present")
+
+	t.Run("unset", func(t *testing.T) {
+		UnsetEnv(t, key)
+		if _, ok := os.LookupEnv(key); ok {
+			t.Error("expected variable to be unset")
+		}
+	})
+
+	if got := os.Getenv(key); got != "present" {
+		t.Errorf("expected value restored, got %q", got)
+	}
+}
+
+func TestSetEnvs(t *testing.T) {
+	t.Run("multiple", func(t *testing.T) {
+		SetEnvs(t, map[string]string{
+			"AZD_CORE_TESTUTIL_A": "a",
+			"AZD_CORE_TESTUTIL_B": "b",
+		})
+		if os.Getenv("AZD_CORE_TESTUTIL_A") != "a" || os.Getenv("AZD_CORE_TESTUTIL_B") != "b" {
+			t.Error("expected both variables to be set")
+		}
+	})
+
+	if _, ok := os.LookupEnv("AZD_CORE_TESTUTIL_A"); ok {
+		t.Error("expected variables to be restored after cleanup")
+	}
+}
+
+func TestSetEnvPanicsInParallelTest(t *testing.T) {
+	t.Run("parallel", func(t *testing.T) {
+		t.Parallel()
+		defer func() {
+			if recover() == nil {
+				t.Error("expected SetEnv to panic in a parallel test")
+			}
+		}()
+		SetEnv(t, "AZD_CORE_TESTUTIL_PARALLEL", "value")
+	})
+}
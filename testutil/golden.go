@@ -0,0 +1,92 @@
+package testutil
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// updateGolden is set by passing -update to go test and causes Golden to
+// rewrite golden files with the actual output instead of comparing.
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+var (
+	// ansiPattern matches ANSI CSI escape sequences (colors, cursor movement)
+	// and OSC sequences (hyperlinks, window titles) terminated by BEL or ST.
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+	// timestampPattern matches RFC 3339 / ISO 8601 timestamps and clock times.
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?|\b\d{2}:\d{2}:\d{2}(?:\.\d+)?\b`)
+)
+
+// Normalizer rewrites output before it is compared against a golden file,
+// removing content that legitimately varies between runs.
+type Normalizer func([]byte) []byte
+
+// NormalizeANSI removes ANSI escape sequences so colored and plain output
+// share a golden file.
+func NormalizeANSI(b []byte) []byte {
+	return ansiPattern.ReplaceAll(b, nil)
+}
+
+// NormalizeTimestamps replaces timestamps and clock times with "<TIMESTAMP>".
+func NormalizeTimestamps(b []byte) []byte {
+	return timestampPattern.ReplaceAll(b, []byte("<TIMESTAMP>"))
+}
+
+// NormalizeLineEndings converts CRLF line endings to LF.
+func NormalizeLineEndings(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+}
+
+// Golden compares got against testdata/<name>.golden, failing the test with
+// a diff-friendly message on mismatch. Normalizers are applied to got first;
+// line endings are always normalized so golden files are portable.
+// When go test is run with -update, the golden file is (re)written instead.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	renderTable(&buf)
+//	testutil.Golden(t, "table", buf.Bytes(), testutil.NormalizeANSI)
+//
+// Regenerate with:
+//
+//	go test ./cliout -run TestTable -update
+func Golden(t *testing.T, name string, got []byte, normalizers ...Normalizer) {
+	t.Helper()
+
+	for _, normalize := range normalizers {
+		got = normalize(got)
+	}
+	got = NormalizeLineEndings(got)
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0600); err != nil {
+			t.Fatalf("Failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	// #nosec G304 -- path is constructed from the test's own testdata directory
+	want, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Fatalf("Golden file %s not found (run with -update to create it)", path)
+		}
+		t.Fatalf("Failed to read golden file %s: %v", path, err)
+	}
+	want = NormalizeLineEndings(want)
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("Output does not match golden file %s (run with -update to accept)\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
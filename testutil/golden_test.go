@@ -0,0 +1,54 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGolden(t *testing.T) {
+	got := []byte("\x1b[32mStatus: ok\x1b[0m\r\nStarted at 2024-01-15T10:30:00.123Z\r\n")
+	Golden(t, "sample", got, NormalizeANSI, NormalizeTimestamps)
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	prev := *updateGolden
+	*updateGolden = true
+	t.Cleanup(func() { *updateGolden = prev })
+
+	Golden(t, "nested/output", []byte("hello\n"))
+
+	data, err := os.ReadFile(filepath.Join("testdata", "nested", "output.golden"))
+	if err != nil {
+		t.Fatalf("expected golden file to be written: %v", err)
+	}
+	if string(data) != "hello\n" {
+		t.Errorf("unexpected golden content: %q", data)
+	}
+}
+
+func TestNormalizers(t *testing.T) {
+	tests := []struct {
+		name      string
+		normalize Normalizer
+		input     string
+		want      string
+	}{
+		{"ansi color", NormalizeANSI, "\x1b[1;31merror\x1b[0m", "error"},
+		{"ansi cursor", NormalizeANSI, "\x1b[2K\x1b[1Gdone", "done"},
+		{"rfc3339", NormalizeTimestamps, "at 2024-01-15T10:30:00Z", "at <TIMESTAMP>"},
+		{"offset", NormalizeTimestamps, "2024-01-15 10:30:00+01:00 ok", "<TIMESTAMP> ok"},
+		{"clock", NormalizeTimestamps, "[15:04:05] started", "[<TIMESTAMP>] started"},
+		{"crlf", NormalizeLineEndings, "a\r\nb\r\n", "a\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.normalize([]byte(tt.input))); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package testutil
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DefaultHealthPath is the path served by NewHealthServer when
+// HealthScript.Path is empty.
+const DefaultHealthPath = "/health"
+
+// HealthStep is one stage of a scripted health endpoint.
+type HealthStep struct {
+	// Status is the HTTP status code to return. Defaults to 200.
+	Status int
+	// Body is the response body. A JSON body is sent with a JSON content type.
+	Body string
+	// Delay is slept before responding, to simulate slow endpoints.
+	Delay time.Duration
+	// Count is how many requests this step serves before advancing.
+	// Defaults to 1.
+	Count int
+}
+
+// HealthScript describes how a health endpoint's responses change over time.
+// Steps are served in order; once exhausted the last step repeats.
+type HealthScript struct {
+	// Path is the health endpoint path. Defaults to DefaultHealthPath.
+	// Requests to any other path receive 404.
+	Path string
+	// Steps is the scripted sequence of responses.
+	// An empty script always responds healthy.
+	Steps []HealthStep
+}
+
+// Healthy returns a step that responds 200 with {"status":"healthy"}.
+func Healthy(count int) HealthStep {
+	return HealthStep{Status: http.StatusOK, Body: `{"status":"healthy"}`, Count: count}
+}
+
+// Degraded returns a step that responds 200 with {"status":"degraded"}.
+func Degraded(count int) HealthStep {
+	return HealthStep{Status: http.StatusOK, Body: `{"status":"degraded"}`, Count: count}
+}
+
+// Failing returns a step that responds with the given 4xx/5xx status.
+func Failing(status, count int) HealthStep {
+	return HealthStep{Status: status, Body: `{"error":"` + http.StatusText(status) + `"}`, Count: count}
+}
+
+// HealthServer is an httptest server that follows a HealthScript.
+type HealthServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	path     string
+	steps    []HealthStep
+	step     int
+	served   int
+	requests int
+}
+
+// NewHealthServer starts a scripted health endpoint that is closed
+// automatically via t.Cleanup.
+//
+// Example:
+//
+//	srv := testutil.NewHealthServer(t, testutil.HealthScript{
+//	    Steps: []testutil.HealthStep{
+//	        testutil.Healthy(2),
+//	        testutil.Degraded(1),
+//	        testutil.Failing(http.StatusInternalServerError, 1),
+//	    },
+//	})
+//	svc := healthcheck.ServiceInfo{Name: "api", Port: srv.Port()}
+func NewHealthServer(t *testing.T, script HealthScript) *HealthServer {
+	t.Helper()
+
+	hs := &HealthServer{
+		path:  script.Path,
+		steps: script.Steps,
+	}
+	if hs.path == "" {
+		hs.path = DefaultHealthPath
+	}
+	if len(hs.steps) == 0 {
+		hs.steps = []HealthStep{Healthy(1)}
+	}
+
+	hs.Server = httptest.NewServer(http.HandlerFunc(hs.serveHTTP))
+	t.Cleanup(hs.Close)
+
+	return hs
+}
+
+// Port returns the TCP port the server is listening on.
+func (hs *HealthServer) Port() int {
+	return hs.Listener.Addr().(*net.TCPAddr).Port
+}
+
+// Requests returns the number of health requests served so far.
+func (hs *HealthServer) Requests() int {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return hs.requests
+}
+
+// StepIndex returns the index of the step that will serve the next request.
+func (hs *HealthServer) StepIndex() int {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return hs.step
+}
+
+func (hs *HealthServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != hs.path {
+		http.NotFound(w, r)
+		return
+	}
+
+	step := hs.next()
+
+	if step.Delay > 0 {
+		select {
+		case <-time.After(step.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	status := step.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if len(step.Body) > 0 && (step.Body[0] == '{' || step.Body[0] == '[') {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(step.Body))
+}
+
+// next returns the current step and advances the script.
+func (hs *HealthServer) next() HealthStep {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.requests++
+	step := hs.steps[hs.step]
+
+	count := step.Count
+	if count <= 0 {
+		count = 1
+	}
+	hs.served++
+	if hs.served >= count && hs.step < len(hs.steps)-1 {
+		hs.step++
+		hs.served = 0
+	}
+
+	return step
+}
@@ -0,0 +1,92 @@
+package testutil
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getHealth(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestHealthServerScript(t *testing.T) {
+	srv := NewHealthServer(t, HealthScript{
+		Steps: []HealthStep{
+			Healthy(2),
+			Degraded(1),
+			Failing(http.StatusInternalServerError, 1),
+		},
+	})
+	url := srv.URL + DefaultHealthPath
+
+	want := []struct {
+		status int
+		body   string
+	}{
+		{200, "healthy"},
+		{200, "healthy"},
+		{200, "degraded"},
+		{500, "Internal Server Error"},
+		{500, "Internal Server Error"}, // last step repeats
+	}
+
+	for i, w := range want {
+		status, body := getHealth(t, url)
+		if status != w.status || !strings.Contains(body, w.body) {
+			t.Errorf("request %d: got %d %q, want %d containing %q", i, status, body, w.status, w.body)
+		}
+	}
+
+	if srv.Requests() != len(want) {
+		t.Errorf("expected %d requests, got %d", len(want), srv.Requests())
+	}
+	if srv.StepIndex() != 2 {
+		t.Errorf("expected to remain on last step, got %d", srv.StepIndex())
+	}
+}
+
+func TestHealthServerDefaults(t *testing.T) {
+	srv := NewHealthServer(t, HealthScript{})
+	if srv.Port() <= 0 {
+		t.Fatalf("invalid port %d", srv.Port())
+	}
+
+	status, body := getHealth(t, srv.URL+DefaultHealthPath)
+	if status != http.StatusOK || !strings.Contains(body, "healthy") {
+		t.Errorf("got %d %q", status, body)
+	}
+
+	status, _ = getHealth(t, srv.URL+"/other")
+	if status != http.StatusNotFound {
+		t.Errorf("expected 404 for unscripted path, got %d", status)
+	}
+	if srv.Requests() != 1 {
+		t.Errorf("expected 404 to not count as a health request, got %d", srv.Requests())
+	}
+}
+
+func TestHealthServerCustomPathAndDelay(t *testing.T) {
+	srv := NewHealthServer(t, HealthScript{
+		Path:  "/ready",
+		Steps: []HealthStep{{Body: "ok", Delay: 20 * time.Millisecond}},
+	})
+
+	start := time.Now()
+	status, body := getHealth(t, srv.URL+"/ready")
+	if status != http.StatusOK || body != "ok" {
+		t.Errorf("got %d %q", status, body)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected delay of at least 20ms, got %v", elapsed)
+	}
+}
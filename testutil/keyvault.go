@@ -0,0 +1,216 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"github.com/jongio/azd-core/keyvault"
+)
+
+// ErrFakeSecretNotFound is returned by FakeKeyVaultResolver for references
+// that have no programmed secret or error.
+var ErrFakeSecretNotFound = errors.New("secret not found")
+
+// FakeKeyVaultResolver is a deterministic stand-in for keyvault.KeyVaultResolver.
+// It satisfies env.Resolver, resolves references from a programmable map,
+// supports per-reference and global error injection, and records every call.
+// It never contacts Azure and needs no credentials.
+// FakeKeyVaultResolver is safe for concurrent use.
+type FakeKeyVaultResolver struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	errs    map[string]error
+	err     error
+	calls   [][]string
+	refs    []string
+}
+
+// NewFakeKeyVaultResolver creates a fake resolver pre-loaded with secrets,
+// keyed by the full Key Vault reference string.
+//
+// Example:
+//
+//	ref := "@Microsoft.KeyVault(VaultName=myvault;SecretName=api-key)"
+//	resolver := testutil.NewFakeKeyVaultResolver(map[string]string{ref: "s3cret"})
+//	resolved, warnings, err := env.Resolve(ctx, map[string]string{"API_KEY": ref}, resolver, keyvault.ResolveEnvironmentOptions{})
+func NewFakeKeyVaultResolver(secrets map[string]string) *FakeKeyVaultResolver {
+	f := &FakeKeyVaultResolver{
+		secrets: make(map[string]string, len(secrets)),
+		errs:    make(map[string]error),
+	}
+	for ref, value := range secrets {
+		f.secrets[normalizeReference(ref)] = value
+	}
+	return f
+}
+
+// SetSecret programs the value returned for reference.
+func (f *FakeKeyVaultResolver) SetSecret(reference, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ref := normalizeReference(reference)
+	f.secrets[ref] = value
+	delete(f.errs, ref)
+}
+
+// SetReferenceError makes resolution of reference fail with err.
+func (f *FakeKeyVaultResolver) SetReferenceError(reference string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[normalizeReference(reference)] = err
+}
+
+// SetError makes every ResolveEnvironmentVariables call fail with err,
+// simulating an unavailable vault or credential failure. Pass nil to clear.
+func (f *FakeKeyVaultResolver) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// ResolveReference resolves a single reference from the programmed secrets.
+func (f *FakeKeyVaultResolver) ResolveReference(ctx context.Context, reference string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lookup(reference)
+}
+
+// ResolveEnvironmentVariables mirrors keyvault.KeyVaultResolver semantics:
+// non-reference entries pass through, failed references produce warnings
+// (and keep their original value), and StopOnError aborts on the first failure.
+func (f *FakeKeyVaultResolver) ResolveEnvironmentVariables(ctx context.Context, envVars []string, options keyvault.ResolveEnvironmentOptions) ([]string, []keyvault.KeyVaultResolutionWarning, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, append([]string(nil), envVars...))
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+
+	resolved := make([]string, 0, len(envVars))
+	var warnings []keyvault.KeyVaultResolutionWarning
+
+	for _, envVar := range envVars {
+		if err := ctx.Err(); err != nil {
+			return nil, warnings, err
+		}
+
+		key, value, ok := strings.Cut(envVar, "=")
+		if !ok || !keyvault.IsKeyVaultReference(value) {
+			resolved = append(resolved, envVar)
+			continue
+		}
+
+		secret, err := f.lookup(value)
+		if err != nil {
+			warnings = append(warnings, keyvault.NewResolutionWarning(key, value, err))
+			if options.StopOnError {
+				return nil, warnings, fmt.Errorf("failed to resolve Key Vault reference for %s: %w", key, err)
+			}
+			resolved = append(resolved, envVar)
+			continue
+		}
+
+		resolved = append(resolved, key+"="+secret)
+	}
+
+	return resolved, warnings, nil
+}
+
+// Calls returns a copy of the env slices passed to ResolveEnvironmentVariables.
+func (f *FakeKeyVaultResolver) Calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([][]string, len(f.calls))
+	for i, c := range f.calls {
+		calls[i] = append([]string(nil), c...)
+	}
+	return calls
+}
+
+// ResolvedReferences returns every reference looked up, in order.
+func (f *FakeKeyVaultResolver) ResolvedReferences() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.refs...)
+}
+
+// lookup resolves reference. Caller must hold f.mu.
+func (f *FakeKeyVaultResolver) lookup(reference string) (string, error) {
+	ref := normalizeReference(reference)
+	f.refs = append(f.refs, ref)
+
+	if err, ok := f.errs[ref]; ok {
+		return "", err
+	}
+	if value, ok := f.secrets[ref]; ok {
+		return value, nil
+	}
+	return "", ErrFakeSecretNotFound
+}
+
+// normalizeReference trims whitespace and surrounding quotes, matching how
+// keyvault normalizes reference values.
+func normalizeReference(reference string) string {
+	ref := strings.TrimSpace(reference)
+	if len(ref) >= 2 && (ref[0] == '"' || ref[0] == '\'') && ref[len(ref)-1] == ref[0] {
+		ref = strings.TrimSpace(ref[1 : len(ref)-1])
+	}
+	return ref
+}
+
+// FakeCredential is an azcore.TokenCredential that returns a static token
+// without contacting Microsoft Entra ID. Use it with constructors such as
+// keyvault.NewKeyVaultResolverWithCredential in tests that must not depend
+// on azidentity or ambient Azure login state.
+type FakeCredential struct {
+	// Token is the access token returned. Defaults to "fake-token".
+	Token string
+	// Err, when set, is returned instead of a token.
+	Err error
+
+	mu     sync.Mutex
+	scopes [][]string
+}
+
+// GetToken implements azcore.TokenCredential.
+func (c *FakeCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.mu.Lock()
+	c.scopes = append(c.scopes, append([]string(nil), opts.Scopes...))
+	c.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return azcore.AccessToken{}, err
+	}
+	if c.Err != nil {
+		return azcore.AccessToken{}, c.Err
+	}
+
+	token := c.Token
+	if token == "" {
+		token = "fake-token"
+	}
+	return azcore.AccessToken{Token: token, ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// RequestedScopes returns the scopes passed to each GetToken call.
+func (c *FakeCredential) RequestedScopes() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	scopes := make([][]string, len(c.scopes))
+	for i, s := range c.scopes {
+		scopes[i] = append([]string(nil), s...)
+	}
+	return scopes
+}
@@ -0,0 +1,120 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"github.com/jongio/azd-core/env"
+	"github.com/jongio/azd-core/keyvault"
+)
+
+const (
+	fakeRef      = "@Microsoft.KeyVault(VaultName=myvault;SecretName=api-key)"
+	fakeOtherRef = "akvs://00000000-0000-0000-0000-000000000000/myvault/db-password"
+)
+
+var _ env.Resolver = (*FakeKeyVaultResolver)(nil)
+
+func TestFakeKeyVaultResolverWithEnvResolve(t *testing.T) {
+	resolver := NewFakeKeyVaultResolver(map[string]string{fakeRef: "s3cret"})
+
+	resolved, warnings, err := env.Resolve(context.Background(), map[string]string{
+		"API_KEY": fakeRef,
+		"PLAIN":   "value",
+	}, resolver, keyvault.ResolveEnvironmentOptions{})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if resolved["API_KEY"] != "s3cret" || resolved["PLAIN"] != "value" {
+		t.Errorf("unexpected resolved env: %v", resolved)
+	}
+	if len(resolver.Calls()) != 1 {
+		t.Errorf("expected 1 call, got %d", len(resolver.Calls()))
+	}
+	if refs := resolver.ResolvedReferences(); len(refs) != 1 || refs[0] != fakeRef {
+		t.Errorf("unexpected resolved references: %v", refs)
+	}
+}
+
+func TestFakeKeyVaultResolverWarnings(t *testing.T) {
+	resolver := NewFakeKeyVaultResolver(nil)
+	injected := errors.New("forbidden")
+	resolver.SetReferenceError(fakeRef, injected)
+
+	resolved, warnings, err := resolver.ResolveEnvironmentVariables(context.Background(),
+		[]string{"API_KEY=" + fakeRef, "DB=" + fakeOtherRef}, keyvault.ResolveEnvironmentOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %d", len(warnings))
+	}
+	if !errors.Is(warnings[0].Err, injected) || !errors.Is(warnings[1].Err, ErrFakeSecretNotFound) {
+		t.Errorf("unexpected warning errors: %v", warnings)
+	}
+	if warnings[0].SecretName == "" || warnings[1].Category != keyvault.WarningOther {
+		t.Errorf("expected warnings to be enriched like keyvault's: %+v", warnings)
+	}
+	if resolved[0] != "API_KEY="+fakeRef {
+		t.Errorf("expected unresolved value preserved, got %q", resolved[0])
+	}
+}
+
+func TestFakeKeyVaultResolverStopOnError(t *testing.T) {
+	resolver := NewFakeKeyVaultResolver(nil)
+
+	_, warnings, err := resolver.ResolveEnvironmentVariables(context.Background(),
+		[]string{"API_KEY=" + fakeRef}, keyvault.ResolveEnvironmentOptions{StopOnError: true})
+	if err == nil {
+		t.Fatal("expected error with StopOnError")
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected 1 warning, got %d", len(warnings))
+	}
+}
+
+func TestFakeKeyVaultResolverGlobalError(t *testing.T) {
+	resolver := NewFakeKeyVaultResolver(map[string]string{fakeRef: "s3cret"})
+	injected := errors.New("vault unavailable")
+	resolver.SetError(injected)
+
+	if _, _, err := resolver.ResolveEnvironmentVariables(context.Background(), []string{"API_KEY=" + fakeRef}, keyvault.ResolveEnvironmentOptions{}); !errors.Is(err, injected) {
+		t.Errorf("expected injected error, got %v", err)
+	}
+
+	resolver.SetError(nil)
+	resolver.SetSecret(`"`+fakeRef+`"`, "updated")
+	value, err := resolver.ResolveReference(context.Background(), fakeRef)
+	if err != nil || value != "updated" {
+		t.Errorf("ResolveReference = %q, %v", value, err)
+	}
+}
+
+func TestFakeCredential(t *testing.T) {
+	cred := &FakeCredential{}
+	tok, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"https://vault.azure.net/.default"}})
+	if err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+	if tok.Token != "fake-token" {
+		t.Errorf("expected default token, got %q", tok.Token)
+	}
+	if scopes := cred.RequestedScopes(); len(scopes) != 1 || scopes[0][0] != "https://vault.azure.net/.default" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	if _, err := keyvault.NewKeyVaultResolverWithCredential(cred); err != nil {
+		t.Errorf("expected resolver from fake credential: %v", err)
+	}
+
+	failing := &FakeCredential{Err: errors.New("no login")}
+	if _, err := failing.GetToken(context.Background(), policy.TokenRequestOptions{}); err == nil {
+		t.Error("expected injected error")
+	}
+}
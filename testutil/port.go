@@ -0,0 +1,65 @@
+package testutil
+
+import (
+	"net"
+	"testing"
+
+	"github.com/jongio/azd-core/portutil"
+)
+
+// FreePort returns a TCP port on 127.0.0.1 that was free at the time of the
+// call. The port is released before returning, so another process could
+// claim it; use OccupyPort when the port must stay bound.
+//
+// Example:
+//
+//	port := testutil.FreePort(t)
+//	if checker.checkPort(ctx, port) {
+//	    t.Error("expected port to not be listening")
+//	}
+func FreePort(t *testing.T) int {
+	t.Helper()
+
+	port, err := portutil.GetFreePort()
+	if err != nil {
+		t.Fatalf("Failed to find free port: %v", err)
+	}
+	return port
+}
+
+// OccupyPort binds a TCP listener on 127.0.0.1 and returns its port.
+// Incoming connections are accepted and closed immediately. The listener is
+// closed automatically via t.Cleanup.
+//
+// Example:
+//
+//	port := testutil.OccupyPort(t)
+//	if !checker.checkPort(ctx, port) {
+//	    t.Error("expected port to be listening")
+//	}
+func OccupyPort(t *testing.T) int {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to occupy port: %v", err)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	t.Cleanup(func() {
+		if err := ln.Close(); err != nil {
+			t.Logf("Failed to close listener: %v", err)
+		}
+	})
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
@@ -0,0 +1,37 @@
+package testutil
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFreePort(t *testing.T) {
+	port := FreePort(t)
+	if port <= 0 || port > 65535 {
+		t.Fatalf("invalid port %d", port)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil {
+		t.Fatalf("expected port %d to be bindable: %v", port, err)
+	}
+	_ = ln.Close()
+}
+
+func TestOccupyPort(t *testing.T) {
+	port := OccupyPort(t)
+	addr := "127.0.0.1:" + strconv.Itoa(port)
+
+	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+	if err != nil {
+		t.Fatalf("expected port %d to accept connections: %v", port, err)
+	}
+	_ = conn.Close()
+
+	if ln, err := net.Listen("tcp", addr); err == nil {
+		_ = ln.Close()
+		t.Errorf("expected port %d to be occupied", port)
+	}
+}
@@ -0,0 +1,107 @@
+package testutil
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// DummyProcess is a long-running child process for tests that need a real,
+// valid PID. Its exit status is reaped in the background, so once Kill
+// returns the PID no longer refers to a running (or zombie) process.
+type DummyProcess struct {
+	// Cmd is the underlying command. Do not call Cmd.Wait; use Kill or Done.
+	Cmd *exec.Cmd
+	// PID is the process ID of the running child.
+	PID  int
+	done chan struct{}
+}
+
+// StartDummyProcess starts a sleeping child process and returns it.
+// The process is killed automatically via t.Cleanup if the test has not
+// already done so.
+//
+// Example:
+//
+//	proc := testutil.StartDummyProcess(t)
+//	if !procutil.IsProcessRunning(proc.PID) {
+//	    t.Error("expected process to be running")
+//	}
+func StartDummyProcess(t *testing.T) *DummyProcess {
+	t.Helper()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", "Start-Sleep -Seconds 300")
+	} else {
+		cmd = exec.Command("sleep", "300")
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start dummy process: %v", err)
+	}
+
+	p := &DummyProcess{
+		Cmd:  cmd,
+		PID:  cmd.Process.Pid,
+		done: make(chan struct{}),
+	}
+	go func() {
+		_ = cmd.Wait()
+		close(p.done)
+	}()
+
+	t.Cleanup(func() {
+		if err := p.Kill(); err != nil {
+			t.Logf("Failed to kill dummy process %d: %v", p.PID, err)
+		}
+	})
+
+	return p
+}
+
+// Kill terminates the process and waits for it to be reaped.
+// Calling Kill on an already exited process is a no-op.
+func (p *DummyProcess) Kill() error {
+	select {
+	case <-p.done:
+		return nil
+	default:
+	}
+
+	if err := p.Cmd.Process.Kill(); err != nil {
+		select {
+		case <-p.done:
+			return nil
+		default:
+			return err
+		}
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-time.After(10 * time.Second):
+		return errors.New("timed out waiting for dummy process to exit")
+	}
+}
+
+// Done returns a channel that is closed once the process has exited.
+func (p *DummyProcess) Done() <-chan struct{} {
+	return p.done
+}
+
+// DeadPID returns a PID that is guaranteed not to belong to a running
+// process: it starts a dummy process, kills it, and returns its former PID.
+// This replaces magic values like 99999 in tests that need a stale PID.
+func DeadPID(t *testing.T) int {
+	t.Helper()
+
+	p := StartDummyProcess(t)
+	if err := p.Kill(); err != nil {
+		t.Fatalf("Failed to kill dummy process: %v", err)
+	}
+	return p.PID
+}
@@ -0,0 +1,55 @@
+package testutil
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func TestStartDummyProcess(t *testing.T) {
+	p := StartDummyProcess(t)
+	if p.PID <= 0 {
+		t.Fatalf("expected positive PID, got %d", p.PID)
+	}
+	if !processAlive(p.PID) {
+		t.Error("expected dummy process to be running")
+	}
+
+	if err := p.Kill(); err != nil {
+		t.Fatalf("Kill: %v", err)
+	}
+	select {
+	case <-p.Done():
+	default:
+		t.Error("expected Done to be closed after Kill")
+	}
+
+	// Killing twice is a no-op.
+	if err := p.Kill(); err != nil {
+		t.Errorf("second Kill returned error: %v", err)
+	}
+}
+
+func TestDeadPID(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signal 0 liveness probe is Unix-only")
+	}
+	pid := DeadPID(t)
+	if processAlive(pid) {
+		t.Errorf("expected PID %d to not be running", pid)
+	}
+}
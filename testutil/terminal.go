@@ -0,0 +1,43 @@
+package testutil
+
+import (
+	"strconv"
+	"testing"
+)
+
+// StripANSI removes ANSI escape sequences from s, so tests can assert on
+// rendered text without depending on colors or terminal support.
+//
+// Example:
+//
+//	output := testutil.CaptureOutput(t, func() error {
+//	    cliout.Success("deployed %s", "web")
+//	    return nil
+//	})
+//	if !strings.Contains(testutil.StripANSI(output), "deployed web") {
+//	    t.Errorf("unexpected output: %q", output)
+//	}
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// WithTermSize makes terminal size detection report cols columns and rows
+// rows for the duration of the test by setting COLUMNS and LINES, which
+// take precedence over querying the terminal in progress.NewMultiProgress
+// and cliout.Page. A non-positive value unsets that variable instead.
+//
+// Example:
+//
+//	testutil.WithTermSize(t, 80, 24)
+//	mp := progress.NewMultiProgress() // renders for an 80-column terminal
+func WithTermSize(t *testing.T, cols, rows int) {
+	t.Helper()
+
+	for key, value := range map[string]int{"COLUMNS": cols, "LINES": rows} {
+		if value > 0 {
+			SetEnv(t, key, strconv.Itoa(value))
+		} else {
+			UnsetEnv(t, key)
+		}
+	}
+}
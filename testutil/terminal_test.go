@@ -0,0 +1,55 @@
+package testutil
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "hello", "hello"},
+		{"colors", "\x1b[1m\x1b[92m✓\x1b[0m done", "✓ done"},
+		{"cursor movement", "\r\x1b[K\x1b[2Aline", "\rline"},
+		{"private mode", "\x1b[?25lhidden\x1b[?25h", "hidden"},
+		{"hyperlink BEL", "\x1b]8;;https://aka.ms\x07link\x1b]8;;\x07", "link"},
+		{"hyperlink ST", "\x1b]8;;https://aka.ms\x1b\\link\x1b]8;;\x1b\\", "link"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripANSI(tt.input); got != tt.want {
+				t.Errorf("StripANSI(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTermSize(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+	t.Setenv("LINES", "60")
+
+	t.Run("override", func(t *testing.T) {
+		WithTermSize(t, 80, 24)
+		if got := os.Getenv("COLUMNS"); got != "80" {
+			t.Errorf("COLUMNS = %q, want 80", got)
+		}
+		if got := os.Getenv("LINES"); got != "24" {
+			t.Errorf("LINES = %q, want 24", got)
+		}
+	})
+	t.Run("unset", func(t *testing.T) {
+		WithTermSize(t, 0, -1)
+		for _, key := range []string{"COLUMNS", "LINES"} {
+			if _, ok := os.LookupEnv(key); ok {
+				t.Errorf("%s should be unset", key)
+			}
+		}
+	})
+
+	if os.Getenv("COLUMNS") != "200" || os.Getenv("LINES") != "60" {
+		t.Error("WithTermSize did not restore the previous values")
+	}
+}
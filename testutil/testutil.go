@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -27,8 +28,78 @@ import (
 func CaptureOutput(t *testing.T, fn func() error) string {
 	t.Helper()
 
-	// Save original stdout
-	origStdout := os.Stdout
+	restore := redirect(t, &os.Stdout)
+	defer restore()
+	fnErr := fn()
+	output := restore()
+
+	if fnErr != nil {
+		t.Logf("Command error: %v", fnErr)
+	}
+
+	return output
+}
+
+// CaptureStderr captures stderr during function execution.
+// It behaves like CaptureOutput but redirects os.Stderr instead of os.Stdout.
+//
+// Example:
+//
+//	output := testutil.CaptureStderr(t, func() error {
+//	    fmt.Fprintln(os.Stderr, "warning")
+//	    return nil
+//	})
+func CaptureStderr(t *testing.T, fn func() error) string {
+	t.Helper()
+
+	restore := redirect(t, &os.Stderr)
+	defer restore()
+	fnErr := fn()
+	output := restore()
+
+	if fnErr != nil {
+		t.Logf("Command error: %v", fnErr)
+	}
+
+	return output
+}
+
+// CaptureAll captures stdout and stderr during function execution and returns
+// both streams separately. The original streams are always restored and the
+// pipes closed, even if fn panics.
+//
+// Example:
+//
+//	stdout, stderr := testutil.CaptureAll(t, func() error {
+//	    return runCommand()
+//	})
+func CaptureAll(t *testing.T, fn func() error) (stdout, stderr string) {
+	t.Helper()
+
+	restoreStdout := redirect(t, &os.Stdout)
+	defer restoreStdout()
+	restoreStderr := redirect(t, &os.Stderr)
+	defer restoreStderr()
+	fnErr := fn()
+	stderr = restoreStderr()
+	stdout = restoreStdout()
+
+	if fnErr != nil {
+		t.Logf("Command error: %v", fnErr)
+	}
+
+	return stdout, stderr
+}
+
+// redirect replaces *stream with the write end of a pipe and returns a
+// function that restores the original stream and returns everything written.
+// The function may be called more than once, so callers can both defer it
+// (to restore the stream if fn panics) and call it to collect the output.
+func redirect(t *testing.T, stream **os.File) func() string {
+	t.Helper()
+
+	// Save original stream
+	orig := *stream
 
 	// Create pipe
 	r, w, err := os.Pipe()
@@ -36,8 +107,8 @@ func CaptureOutput(t *testing.T, fn func() error) string {
 		t.Fatalf("Failed to create pipe: %v", err)
 	}
 
-	// Replace stdout
-	os.Stdout = w
+	// Replace stream
+	*stream = w
 
 	// Channel for output (buffered to avoid goroutine leak)
 	outCh := make(chan string, 1)
@@ -53,26 +124,23 @@ func CaptureOutput(t *testing.T, fn func() error) string {
 				break
 			}
 		}
+		_ = r.Close()
 		outCh <- output.String()
 	}()
 
-	// Execute function
-	fnErr := fn()
-
-	// Close write end and restore stdout
-	if err := w.Close(); err != nil {
-		t.Logf("Failed to close pipe writer: %v", err)
-	}
-	os.Stdout = origStdout
-
-	// Get output
-	output := <-outCh
-
-	if fnErr != nil {
-		t.Logf("Command error: %v", fnErr)
+	var once sync.Once
+	var output string
+	return func() string {
+		once.Do(func() {
+			// Close write end and restore stream
+			if err := w.Close(); err != nil {
+				t.Logf("Failed to close pipe writer: %v", err)
+			}
+			*stream = orig
+			output = <-outCh
+		})
+		return output
 	}
-
-	return output
 }
 
 // FindTestData finds a test data directory relative to the current working directory.
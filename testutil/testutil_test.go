@@ -116,6 +116,62 @@ func TestCaptureOutput(t *testing.T) {
 	})
 }
 
+func TestCaptureStderr(t *testing.T) {
+	output := CaptureStderr(t, func() error {
+		fmt.Fprintln(os.Stderr, "stderr output")
+		fmt.Println("stdout output")
+		return nil
+	})
+
+	if !strings.Contains(output, "stderr output") {
+		t.Errorf("expected output to contain 'stderr output', got: %s", output)
+	}
+	if strings.Contains(output, "stdout output") {
+		t.Errorf("expected stdout to be excluded, got: %s", output)
+	}
+}
+
+func TestCaptureAll(t *testing.T) {
+	origStdout, origStderr := os.Stdout, os.Stderr
+
+	stdout, stderr := CaptureAll(t, func() error {
+		fmt.Println("to stdout")
+		fmt.Fprintln(os.Stderr, "to stderr")
+		return errors.New("ignored")
+	})
+
+	if !strings.Contains(stdout, "to stdout") || strings.Contains(stdout, "to stderr") {
+		t.Errorf("unexpected stdout: %q", stdout)
+	}
+	if !strings.Contains(stderr, "to stderr") || strings.Contains(stderr, "to stdout") {
+		t.Errorf("unexpected stderr: %q", stderr)
+	}
+	if os.Stdout != origStdout || os.Stderr != origStderr {
+		t.Error("expected original streams to be restored")
+	}
+}
+
+func TestCaptureAllRestoresStreamsOnPanic(t *testing.T) {
+	origStdout, origStderr := os.Stdout, os.Stderr
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic to propagate")
+			}
+		}()
+		CaptureAll(t, func() error {
+			fmt.Println("before panic")
+			panic("boom")
+		})
+	}()
+
+	if os.Stdout != origStdout || os.Stderr != origStderr {
+		os.Stdout, os.Stderr = origStdout, origStderr
+		t.Fatal("expected original streams to be restored after a panic")
+	}
+}
+
 func TestFindTestData(t *testing.T) {
 	t.Run("finds testutil directory from current location", func(t *testing.T) {
 		// We're in testutil package, so searching for "testutil" should find current dir
@@ -0,0 +1,133 @@
+package urlutil
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotAzureEndpoint is returned by ParseAzureEndpoint when the URL's host
+// does not match a known Azure endpoint shape.
+var ErrNotAzureEndpoint = errors.New("not a recognized Azure endpoint")
+
+// Cloud identifies an Azure cloud. Values match the cloud names used by azd
+// and the Azure CLI.
+type Cloud string
+
+const (
+	CloudUnknown      Cloud = ""
+	CloudPublic       Cloud = "AzureCloud"
+	CloudUSGovernment Cloud = "AzureUSGovernment"
+	CloudChina        Cloud = "AzureChinaCloud"
+)
+
+// AzureService identifies the Azure service behind an endpoint.
+type AzureService string
+
+const (
+	ServiceKeyVault        AzureService = "keyvault"
+	ServiceAppService      AzureService = "appservice"
+	ServiceContainerApps   AzureService = "containerapps"
+	ServiceBlobStorage     AzureService = "blob"
+	ServiceResourceManager AzureService = "resourcemanager"
+)
+
+// AzureEndpoint describes a recognized Azure endpoint URL.
+type AzureEndpoint struct {
+	// Service is the Azure service the endpoint belongs to.
+	Service AzureService
+	// Cloud is the Azure cloud hosting the endpoint.
+	Cloud Cloud
+	// ResourceName is the resource-specific leading host label, such as the
+	// vault, app, or storage account name. Empty for shared endpoints like
+	// Resource Manager.
+	ResourceName string
+	// Host is the lowercase host name without port.
+	Host string
+}
+
+// azureSuffix maps a host suffix to its service and cloud.
+type azureSuffix struct {
+	suffix  string
+	service AzureService
+	cloud   Cloud
+	// exact means the host must equal suffix rather than end with it.
+	exact bool
+}
+
+// azureSuffixes lists the recognized endpoint shapes.
+var azureSuffixes = []azureSuffix{
+	{suffix: "vault.azure.net", service: ServiceKeyVault, cloud: CloudPublic},
+	{suffix: "vault.usgovcloudapi.net", service: ServiceKeyVault, cloud: CloudUSGovernment},
+	{suffix: "vault.azure.cn", service: ServiceKeyVault, cloud: CloudChina},
+
+	{suffix: "azurewebsites.net", service: ServiceAppService, cloud: CloudPublic},
+	{suffix: "azurewebsites.us", service: ServiceAppService, cloud: CloudUSGovernment},
+	{suffix: "chinacloudsites.cn", service: ServiceAppService, cloud: CloudChina},
+
+	{suffix: "azurecontainerapps.io", service: ServiceContainerApps, cloud: CloudPublic},
+	{suffix: "azurecontainerapps.us", service: ServiceContainerApps, cloud: CloudUSGovernment},
+
+	{suffix: "blob.core.windows.net", service: ServiceBlobStorage, cloud: CloudPublic},
+	{suffix: "blob.core.usgovcloudapi.net", service: ServiceBlobStorage, cloud: CloudUSGovernment},
+	{suffix: "blob.core.chinacloudapi.cn", service: ServiceBlobStorage, cloud: CloudChina},
+
+	{suffix: "management.azure.com", service: ServiceResourceManager, cloud: CloudPublic, exact: true},
+	{suffix: "management.usgovcloudapi.net", service: ServiceResourceManager, cloud: CloudUSGovernment, exact: true},
+	{suffix: "management.chinacloudapi.cn", service: ServiceResourceManager, cloud: CloudChina, exact: true},
+}
+
+// ParseAzureEndpoint validates rawURL (see Validate) and identifies the Azure
+// service and cloud from its host. Hosts are matched on label boundaries, so
+// "evilvault.azure.net.example.com" and "notazurewebsites.net" are rejected.
+//
+// Returns ErrNotAzureEndpoint if the host is not a recognized Azure endpoint.
+//
+// Example:
+//
+//	ep, err := urlutil.ParseAzureEndpoint("https://myvault.vault.azure.net/secrets/db")
+//	// ep.Service == urlutil.ServiceKeyVault, ep.Cloud == urlutil.CloudPublic, ep.ResourceName == "myvault"
+func ParseAzureEndpoint(rawURL string) (*AzureEndpoint, error) {
+	parsed, err := Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	for _, s := range azureSuffixes {
+		if s.exact {
+			if host == s.suffix {
+				return &AzureEndpoint{Service: s.service, Cloud: s.cloud, Host: host}, nil
+			}
+			continue
+		}
+		prefix, ok := strings.CutSuffix(host, "."+s.suffix)
+		if !ok || prefix == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(prefix, ".")
+		return &AzureEndpoint{Service: s.service, Cloud: s.cloud, ResourceName: name, Host: host}, nil
+	}
+	return nil, ErrNotAzureEndpoint
+}
+
+// IsAzureEndpoint reports whether rawURL is a valid URL for a recognized
+// Azure endpoint (Key Vault, App Service, Container Apps, Blob Storage, or
+// Resource Manager) in any supported cloud.
+func IsAzureEndpoint(rawURL string) bool {
+	_, err := ParseAzureEndpoint(rawURL)
+	return err == nil
+}
+
+// CloudFromURL returns the Azure cloud hosting rawURL, or CloudUnknown if the
+// URL is invalid or not a recognized Azure endpoint.
+//
+// Example:
+//
+//	urlutil.CloudFromURL("https://myvault.vault.usgovcloudapi.net") // CloudUSGovernment
+func CloudFromURL(rawURL string) Cloud {
+	ep, err := ParseAzureEndpoint(rawURL)
+	if err != nil {
+		return CloudUnknown
+	}
+	return ep.Cloud
+}
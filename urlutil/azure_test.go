@@ -0,0 +1,134 @@
+package urlutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAzureEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    AzureEndpoint
+		wantErr bool
+	}{
+		{
+			name: "key vault public",
+			url:  "https://myvault.vault.azure.net/secrets/db",
+			want: AzureEndpoint{Service: ServiceKeyVault, Cloud: CloudPublic, ResourceName: "myvault", Host: "myvault.vault.azure.net"},
+		},
+		{
+			name: "key vault us gov",
+			url:  "https://myvault.vault.usgovcloudapi.net",
+			want: AzureEndpoint{Service: ServiceKeyVault, Cloud: CloudUSGovernment, ResourceName: "myvault", Host: "myvault.vault.usgovcloudapi.net"},
+		},
+		{
+			name: "key vault china",
+			url:  "https://myvault.vault.azure.cn",
+			want: AzureEndpoint{Service: ServiceKeyVault, Cloud: CloudChina, ResourceName: "myvault", Host: "myvault.vault.azure.cn"},
+		},
+		{
+			name: "app service with port and uppercase",
+			url:  "https://MyApp.AzureWebsites.net:443/api",
+			want: AzureEndpoint{Service: ServiceAppService, Cloud: CloudPublic, ResourceName: "myapp", Host: "myapp.azurewebsites.net"},
+		},
+		{
+			name: "app service scm",
+			url:  "https://myapp.scm.azurewebsites.us",
+			want: AzureEndpoint{Service: ServiceAppService, Cloud: CloudUSGovernment, ResourceName: "myapp", Host: "myapp.scm.azurewebsites.us"},
+		},
+		{
+			name: "app service china",
+			url:  "https://myapp.chinacloudsites.cn",
+			want: AzureEndpoint{Service: ServiceAppService, Cloud: CloudChina, ResourceName: "myapp", Host: "myapp.chinacloudsites.cn"},
+		},
+		{
+			name: "container apps",
+			url:  "https://api.happyhill-1234.eastus.azurecontainerapps.io/health",
+			want: AzureEndpoint{Service: ServiceContainerApps, Cloud: CloudPublic, ResourceName: "api", Host: "api.happyhill-1234.eastus.azurecontainerapps.io"},
+		},
+		{
+			name: "blob public",
+			url:  "https://account.blob.core.windows.net/container/blob.txt",
+			want: AzureEndpoint{Service: ServiceBlobStorage, Cloud: CloudPublic, ResourceName: "account", Host: "account.blob.core.windows.net"},
+		},
+		{
+			name: "blob china",
+			url:  "https://account.blob.core.chinacloudapi.cn",
+			want: AzureEndpoint{Service: ServiceBlobStorage, Cloud: CloudChina, ResourceName: "account", Host: "account.blob.core.chinacloudapi.cn"},
+		},
+		{
+			name: "resource manager us gov",
+			url:  "https://management.usgovcloudapi.net/subscriptions",
+			want: AzureEndpoint{Service: ServiceResourceManager, Cloud: CloudUSGovernment, Host: "management.usgovcloudapi.net"},
+		},
+		{name: "lookalike prefix", url: "https://notazurewebsites.net", wantErr: true},
+		{name: "suffix in subdomain", url: "https://myvault.vault.azure.net.evil.com", wantErr: true},
+		{name: "bare suffix", url: "https://vault.azure.net", wantErr: true},
+		{name: "management subdomain", url: "https://x.management.azure.com", wantErr: true},
+		{name: "other host", url: "https://example.com", wantErr: true},
+		{name: "invalid url", url: "ftp://myvault.vault.azure.net", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAzureEndpoint(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseAzureEndpoint(%q) = %+v, want error", tt.url, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAzureEndpoint(%q) error = %v", tt.url, err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseAzureEndpoint(%q) = %+v, want %+v", tt.url, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAzureEndpoint_NotAzure(t *testing.T) {
+	_, err := ParseAzureEndpoint("https://example.com")
+	if !errors.Is(err, ErrNotAzureEndpoint) {
+		t.Errorf("error = %v, want ErrNotAzureEndpoint", err)
+	}
+}
+
+func TestIsAzureEndpoint(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://myvault.vault.azure.net", true},
+		{"https://account.blob.core.usgovcloudapi.net", true},
+		{"https://management.chinacloudapi.cn", true},
+		{"https://example.com", false},
+		{"not a url", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsAzureEndpoint(tt.url); got != tt.want {
+			t.Errorf("IsAzureEndpoint(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestCloudFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Cloud
+	}{
+		{"https://myvault.vault.azure.net", CloudPublic},
+		{"https://myapp.azurewebsites.us", CloudUSGovernment},
+		{"https://account.blob.core.chinacloudapi.cn", CloudChina},
+		{"https://example.com", CloudUnknown},
+		{"", CloudUnknown},
+	}
+	for _, tt := range tests {
+		if got := CloudFromURL(tt.url); got != tt.want {
+			t.Errorf("CloudFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package urlutil
+
+import (
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// ErrInvalidConnectionString is returned when a connection string cannot be
+// parsed or is missing parts required for its service.
+var ErrInvalidConnectionString = errors.New("invalid connection string")
+
+// ConnectionStringKind identifies the Azure service a connection string is for.
+type ConnectionStringKind string
+
+const (
+	ConnectionStringUnknown ConnectionStringKind = ""
+	// ConnectionStringStorage is an Azure Storage account connection string.
+	ConnectionStringStorage ConnectionStringKind = "storage"
+	// ConnectionStringServiceBus is a Service Bus or Event Hubs namespace
+	// connection string.
+	ConnectionStringServiceBus ConnectionStringKind = "servicebus"
+	// ConnectionStringCosmos is an Azure Cosmos DB account connection string.
+	ConnectionStringCosmos ConnectionStringKind = "cosmos"
+)
+
+// secretConnectionStringKeys are the lowercase keys whose values are secrets.
+var secretConnectionStringKeys = map[string]bool{
+	"accountkey":            true,
+	"sharedaccesskey":       true,
+	"sharedaccesssignature": true,
+	"password":              true,
+	"pwd":                   true,
+	"clientsecret":          true,
+	"accesskey":             true,
+}
+
+// storageEndpointKeys are the per-service endpoint overrides of a storage
+// connection string.
+var storageEndpointKeys = []string{"BlobEndpoint", "QueueEndpoint", "TableEndpoint", "FileEndpoint"}
+
+// ConnectionStringPart is one Key=Value component of a connection string.
+type ConnectionStringPart struct {
+	// Key is the component name as written.
+	Key string
+	// Value is the component value.
+	Value string
+	// Secret is true for keys, signatures, and passwords, which must be
+	// redacted before display.
+	Secret bool
+}
+
+// ConnectionString is a parsed Azure connection string.
+type ConnectionString struct {
+	// Kind is the service the connection string was recognized as.
+	Kind ConnectionStringKind
+	// Parts are the components in their original order.
+	Parts []ConnectionStringPart
+}
+
+// ParseConnectionString splits an Azure connection string of the form
+// "Key1=Value1;Key2=Value2" into its parts and recognizes the service it is
+// for. Keys are matched case-insensitively; values may contain '='.
+// It only checks the syntax; use the Validate*ConnectionString functions to
+// check that the parts a service needs are present.
+//
+// Example:
+//
+//	cs, err := urlutil.ParseConnectionString(os.Getenv("STORAGE_CONNECTION_STRING"))
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(cs.AccountName(), cs.Endpoint())
+//	fmt.Println(cs.Redacted()) // AccountKey=***REDACTED***
+func ParseConnectionString(s string) (*ConnectionString, error) {
+	cs := &ConnectionString{}
+	seen := make(map[string]bool)
+	for i, segment := range strings.Split(s, ";") {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(segment, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			// A segment without "=" is often part of a secret split on ";",
+			// so report its position rather than any of its content.
+			return nil, fmt.Errorf("%w: segment %d is not Key=Value", ErrInvalidConnectionString, i+1)
+		}
+		lower := strings.ToLower(key)
+		if seen[lower] {
+			return nil, fmt.Errorf("%w: duplicate key %s", ErrInvalidConnectionString, key)
+		}
+		seen[lower] = true
+		cs.Parts = append(cs.Parts, ConnectionStringPart{
+			Key:    key,
+			Value:  strings.TrimSpace(value),
+			Secret: secretConnectionStringKeys[lower],
+		})
+	}
+	if len(cs.Parts) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidConnectionString)
+	}
+	cs.Kind = cs.detectKind()
+	return cs, nil
+}
+
+// Get returns the value of key, matched case-insensitively.
+func (cs *ConnectionString) Get(key string) (string, bool) {
+	for _, p := range cs.Parts {
+		if strings.EqualFold(p.Key, key) {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// value returns the value of key, or "" if it is absent.
+func (cs *ConnectionString) value(key string) string {
+	v, _ := cs.Get(key)
+	return v
+}
+
+// Endpoint returns the service endpoint: AccountEndpoint for Cosmos DB,
+// Endpoint for Service Bus, and for storage the BlobEndpoint or the blob
+// endpoint built from AccountName, EndpointSuffix, and
+// DefaultEndpointsProtocol. It returns "" if the endpoint cannot be
+// determined.
+func (cs *ConnectionString) Endpoint() string {
+	switch cs.Kind {
+	case ConnectionStringCosmos:
+		return cs.value("AccountEndpoint")
+	case ConnectionStringServiceBus:
+		return cs.value("Endpoint")
+	case ConnectionStringStorage:
+		if v := cs.value("BlobEndpoint"); v != "" {
+			return v
+		}
+		account := cs.value("AccountName")
+		if account == "" {
+			return ""
+		}
+		protocol := cs.value("DefaultEndpointsProtocol")
+		if protocol == "" {
+			protocol = "https"
+		}
+		suffix := cs.value("EndpointSuffix")
+		if suffix == "" {
+			suffix = "core.windows.net"
+		}
+		return fmt.Sprintf("%s://%s.blob.%s", strings.ToLower(protocol), account, suffix)
+	}
+	return ""
+}
+
+// AccountName returns the storage account, Service Bus namespace, or Cosmos
+// DB account name, taken from AccountName or the first label of the
+// endpoint host. It returns "" if there is none.
+func (cs *ConnectionString) AccountName() string {
+	if v := cs.value("AccountName"); v != "" {
+		return v
+	}
+	if cs.Kind == ConnectionStringStorage {
+		return ""
+	}
+	u, err := neturl.Parse(cs.Endpoint())
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	name, _, _ := strings.Cut(u.Hostname(), ".")
+	return name
+}
+
+// Redacted returns the connection string with every secret value replaced
+// by security.RedactedPlaceholder, safe for logs and error messages.
+func (cs *ConnectionString) Redacted() string {
+	segments := make([]string, len(cs.Parts))
+	for i, p := range cs.Parts {
+		value := p.Value
+		if p.Secret {
+			value = security.RedactedPlaceholder
+		}
+		segments[i] = p.Key + "=" + value
+	}
+	return strings.Join(segments, ";")
+}
+
+// detectKind recognizes the service from the keys present.
+func (cs *ConnectionString) detectKind() ConnectionStringKind {
+	if _, ok := cs.Get("AccountEndpoint"); ok {
+		return ConnectionStringCosmos
+	}
+	if strings.HasPrefix(strings.ToLower(cs.value("Endpoint")), "sb://") {
+		return ConnectionStringServiceBus
+	}
+	if _, ok := cs.Get("AccountName"); ok {
+		return ConnectionStringStorage
+	}
+	if _, ok := cs.Get("UseDevelopmentStorage"); ok {
+		return ConnectionStringStorage
+	}
+	for _, key := range storageEndpointKeys {
+		if _, ok := cs.Get(key); ok {
+			return ConnectionStringStorage
+		}
+	}
+	return ConnectionStringUnknown
+}
+
+// ValidateStorageConnectionString checks that s is a usable Azure Storage
+// connection string: either UseDevelopmentStorage=true, or an AccountName
+// with an AccountKey or SharedAccessSignature, or service endpoints with a
+// SharedAccessSignature. Endpoints must be valid HTTP or HTTPS URLs.
+func ValidateStorageConnectionString(s string) error {
+	cs, err := parseKind(s, ConnectionStringStorage)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(cs.value("UseDevelopmentStorage"), "true") {
+		return nil
+	}
+	if p := cs.value("DefaultEndpointsProtocol"); p != "" && !strings.EqualFold(p, "https") && !strings.EqualFold(p, "http") {
+		return fmt.Errorf("%w: DefaultEndpointsProtocol must be http or https, got %q", ErrInvalidConnectionString, p)
+	}
+	hasEndpoint := false
+	for _, key := range storageEndpointKeys {
+		if v, ok := cs.Get(key); ok {
+			if err := Validate(v); err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrInvalidConnectionString, key, err)
+			}
+			hasEndpoint = true
+		}
+	}
+	_, hasKey := cs.Get("AccountKey")
+	_, hasSAS := cs.Get("SharedAccessSignature")
+	switch {
+	case cs.value("AccountName") != "" && (hasKey || hasSAS):
+		return nil
+	case hasEndpoint && hasSAS:
+		return nil
+	case cs.value("AccountName") == "" && !hasEndpoint:
+		return fmt.Errorf("%w: missing AccountName", ErrInvalidConnectionString)
+	default:
+		return fmt.Errorf("%w: missing AccountKey or SharedAccessSignature", ErrInvalidConnectionString)
+	}
+}
+
+// ValidateServiceBusConnectionString checks that s is a usable Service Bus
+// or Event Hubs connection string: an sb:// Endpoint with a host, and either
+// SharedAccessKeyName and SharedAccessKey, a SharedAccessSignature, or an
+// Authentication setting for Microsoft Entra ID.
+func ValidateServiceBusConnectionString(s string) error {
+	cs, err := parseKind(s, ConnectionStringServiceBus)
+	if err != nil {
+		return err
+	}
+	u, err := neturl.Parse(cs.value("Endpoint"))
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("%w: Endpoint must be sb://<namespace>.servicebus.windows.net/", ErrInvalidConnectionString)
+	}
+	_, hasKeyName := cs.Get("SharedAccessKeyName")
+	_, hasKey := cs.Get("SharedAccessKey")
+	_, hasSAS := cs.Get("SharedAccessSignature")
+	_, hasAuth := cs.Get("Authentication")
+	switch {
+	case hasKeyName && hasKey, hasSAS, hasAuth:
+		return nil
+	case hasKey:
+		return fmt.Errorf("%w: missing SharedAccessKeyName", ErrInvalidConnectionString)
+	default:
+		return fmt.Errorf("%w: missing SharedAccessKey or SharedAccessSignature", ErrInvalidConnectionString)
+	}
+}
+
+// ValidateCosmosConnectionString checks that s is a usable Cosmos DB
+// connection string: an AccountEndpoint that passes Validate and an
+// AccountKey.
+func ValidateCosmosConnectionString(s string) error {
+	cs, err := parseKind(s, ConnectionStringCosmos)
+	if err != nil {
+		return err
+	}
+	if err := Validate(cs.value("AccountEndpoint")); err != nil {
+		return fmt.Errorf("%w: AccountEndpoint: %w", ErrInvalidConnectionString, err)
+	}
+	if cs.value("AccountKey") == "" {
+		return fmt.Errorf("%w: missing AccountKey", ErrInvalidConnectionString)
+	}
+	return nil
+}
+
+// parseKind parses s and checks that it was recognized as kind.
+func parseKind(s string, kind ConnectionStringKind) (*ConnectionString, error) {
+	cs, err := ParseConnectionString(s)
+	if err != nil {
+		return nil, err
+	}
+	if cs.Kind != kind {
+		return nil, fmt.Errorf("%w: not a %s connection string", ErrInvalidConnectionString, kind)
+	}
+	return cs, nil
+}
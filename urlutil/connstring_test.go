@@ -0,0 +1,134 @@
+package urlutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-core/security"
+)
+
+const (
+	testStorageConn    = "DefaultEndpointsProtocol=https;AccountName=myacct;AccountKey=c2VjcmV0a2V5==;EndpointSuffix=core.windows.net"
+	testServiceBusConn = "Endpoint=sb://myns.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=abc123="
+	testCosmosConn     = "AccountEndpoint=https://mycosmos.documents.azure.com:443/;AccountKey=Zm9vYmFy==;"
+)
+
+func TestParseConnectionString(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantKind     ConnectionStringKind
+		wantEndpoint string
+		wantAccount  string
+		wantSecrets  []string
+	}{
+		{"storage", testStorageConn, ConnectionStringStorage, "https://myacct.blob.core.windows.net", "myacct", []string{"AccountKey"}},
+		{"storage gov cloud", "AccountName=gov;AccountKey=k;EndpointSuffix=core.usgovcloudapi.net", ConnectionStringStorage, "https://gov.blob.core.usgovcloudapi.net", "gov", []string{"AccountKey"}},
+		{"storage SAS endpoint", "BlobEndpoint=https://acct.blob.core.windows.net/;SharedAccessSignature=sv=2022&sig=abc", ConnectionStringStorage, "https://acct.blob.core.windows.net/", "", []string{"SharedAccessSignature"}},
+		{"azurite", "UseDevelopmentStorage=true", ConnectionStringStorage, "", "", nil},
+		{"service bus", testServiceBusConn, ConnectionStringServiceBus, "sb://myns.servicebus.windows.net/", "myns", []string{"SharedAccessKey"}},
+		{"cosmos", testCosmosConn, ConnectionStringCosmos, "https://mycosmos.documents.azure.com:443/", "mycosmos", []string{"AccountKey"}},
+		{"unknown", "Server=tcp:db;Password=p", ConnectionStringUnknown, "", "", []string{"Password"}},
+		{"case insensitive keys", "accountname=lower;accountkey=k", ConnectionStringStorage, "https://lower.blob.core.windows.net", "lower", []string{"accountkey"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := ParseConnectionString(tt.input)
+			if err != nil {
+				t.Fatalf("ParseConnectionString() error = %v", err)
+			}
+			if cs.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", cs.Kind, tt.wantKind)
+			}
+			if got := cs.Endpoint(); got != tt.wantEndpoint {
+				t.Errorf("Endpoint() = %q, want %q", got, tt.wantEndpoint)
+			}
+			if got := cs.AccountName(); got != tt.wantAccount {
+				t.Errorf("AccountName() = %q, want %q", got, tt.wantAccount)
+			}
+			var secrets []string
+			for _, p := range cs.Parts {
+				if p.Secret {
+					secrets = append(secrets, p.Key)
+					if strings.Contains(cs.Redacted(), p.Key+"="+p.Value) {
+						t.Errorf("Redacted() = %q leaks %s", cs.Redacted(), p.Key)
+					}
+				}
+			}
+			if strings.Join(secrets, ",") != strings.Join(tt.wantSecrets, ",") {
+				t.Errorf("secret parts = %v, want %v", secrets, tt.wantSecrets)
+			}
+		})
+	}
+}
+
+func TestParseConnectionString_Values(t *testing.T) {
+	cs, err := ParseConnectionString(testStorageConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := cs.Get("accountkey"); !ok || v != "c2VjcmV0a2V5==" {
+		t.Errorf("Get(accountkey) = %q, %v", v, ok)
+	}
+	if _, ok := cs.Get("Missing"); ok {
+		t.Error("Get(Missing) should report false")
+	}
+	want := "DefaultEndpointsProtocol=https;AccountName=myacct;AccountKey=" + security.RedactedPlaceholder + ";EndpointSuffix=core.windows.net"
+	if got := cs.Redacted(); got != want {
+		t.Errorf("Redacted() = %q, want %q", got, want)
+	}
+}
+
+func TestParseConnectionString_Errors(t *testing.T) {
+	for _, input := range []string{"", " ; ;", "AccountName=a;garbage", "=value", "AccountName=a;accountname=b"} {
+		if _, err := ParseConnectionString(input); !errors.Is(err, ErrInvalidConnectionString) {
+			t.Errorf("ParseConnectionString(%q) error = %v, want ErrInvalidConnectionString", input, err)
+		}
+	}
+	for _, segment := range []string{"AccountKeyc2VjcmV0a2V5c2VjcmV0", "c2VjcmV0"} {
+		_, err := ParseConnectionString("AccountName=a;" + segment)
+		if err == nil || strings.Contains(err.Error(), segment[:4]) || !strings.Contains(err.Error(), "segment 2") {
+			t.Errorf("malformed segment error = %v, want position only", err)
+		}
+	}
+}
+
+func TestValidateConnectionStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) error
+		input    string
+		wantErr  bool
+	}{
+		{"storage valid", ValidateStorageConnectionString, testStorageConn, false},
+		{"storage azurite", ValidateStorageConnectionString, "UseDevelopmentStorage=true", false},
+		{"storage SAS", ValidateStorageConnectionString, "BlobEndpoint=https://a.blob.core.windows.net;SharedAccessSignature=sig", false},
+		{"storage missing key", ValidateStorageConnectionString, "AccountName=a", true},
+		{"storage endpoint without credential", ValidateStorageConnectionString, "BlobEndpoint=https://a.blob.core.windows.net", true},
+		{"storage bad protocol", ValidateStorageConnectionString, "DefaultEndpointsProtocol=ftp;AccountName=a;AccountKey=k", true},
+		{"storage bad endpoint", ValidateStorageConnectionString, "BlobEndpoint=javascript:alert(1);SharedAccessSignature=sig", true},
+		{"storage wrong kind", ValidateStorageConnectionString, testCosmosConn, true},
+		{"service bus valid", ValidateServiceBusConnectionString, testServiceBusConn, false},
+		{"service bus SAS", ValidateServiceBusConnectionString, "Endpoint=sb://ns.servicebus.windows.net/;SharedAccessSignature=SharedAccessSignature sr=x", false},
+		{"service bus entra", ValidateServiceBusConnectionString, "Endpoint=sb://ns.servicebus.windows.net/;Authentication=Managed Identity", false},
+		{"service bus missing key name", ValidateServiceBusConnectionString, "Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKey=k", true},
+		{"service bus no credential", ValidateServiceBusConnectionString, "Endpoint=sb://ns.servicebus.windows.net/", true},
+		{"service bus no host", ValidateServiceBusConnectionString, "Endpoint=sb://;SharedAccessSignature=s", true},
+		{"service bus wrong kind", ValidateServiceBusConnectionString, testStorageConn, true},
+		{"cosmos valid", ValidateCosmosConnectionString, testCosmosConn, false},
+		{"cosmos missing key", ValidateCosmosConnectionString, "AccountEndpoint=https://c.documents.azure.com:443/", true},
+		{"cosmos bad endpoint", ValidateCosmosConnectionString, "AccountEndpoint=file:///etc/passwd;AccountKey=k", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidConnectionString) {
+				t.Errorf("error = %v, want ErrInvalidConnectionString", err)
+			}
+		})
+	}
+}
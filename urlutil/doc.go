@@ -38,6 +38,40 @@
 //	normalized := urlutil.NormalizeScheme("example.com", "https")
 //	// Returns: "https://example.com"
 //
+// Use Sanitize before handing a URL to another process. It rejects embedded
+// credentials and percent-encodes unsafe query and fragment characters:
+//
+//	safe, err := urlutil.Sanitize(reportURL)
+//	if err != nil {
+//		return err
+//	}
+//
+// Use RedactForLogging before a URL reaches logs or error messages. It masks
+// user info and secret query parameters (sig, code, token, client_secret,
+// and similar) while keeping the rest of the URL readable:
+//
+//	logger.Info("calling", "url", urlutil.RedactForLogging(endpoint))
+//
+// Use ParseAzureEndpoint, IsAzureEndpoint, and CloudFromURL to recognize Azure
+// endpoints (Key Vault, App Service, Container Apps, Blob Storage, Resource
+// Manager) and the cloud that hosts them (public, US Government, China):
+//
+//	if urlutil.CloudFromURL(vaultURL) == urlutil.CloudUSGovernment {
+//		scope = "https://vault.usgovcloudapi.net/.default"
+//	}
+//
+// Use ParseConnectionString to read Storage, Service Bus, Event Hubs, and
+// Cosmos DB connection strings. Secret parts are flagged, and Redacted
+// returns a copy that is safe to log. ValidateStorageConnectionString,
+// ValidateServiceBusConnectionString, and ValidateCosmosConnectionString
+// check that a service's required parts are present:
+//
+//	cs, err := urlutil.ParseConnectionString(conn)
+//	if err != nil {
+//		return err
+//	}
+//	log.Printf("using %s (%s)", cs.AccountName(), cs.Redacted())
+//
 // # Validation Rules
 //
 // The validation functions enforce the following rules:
@@ -0,0 +1,99 @@
+package urlutil
+
+import (
+	neturl "net/url"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// sensitiveQueryParams are query parameter names that carry secrets but are
+// too short or generic for security.IsSensitiveKey: SAS signatures, OAuth
+// authorization codes, Azure Functions keys, and API keys.
+var sensitiveQueryParams = map[string]bool{
+	"sig":  true,
+	"code": true,
+	"key":  true,
+}
+
+// RedactForLogging returns rawURL with secrets replaced by
+// security.RedactedPlaceholder, so it can be logged or included in an error
+// while its scheme, host, path, and parameter names stay readable. It masks
+// the user info (user:pass@) and the values of query and fragment
+// parameters such as sig, code, token, and client_secret, or any name
+// security.IsSensitiveKey recognizes. The rest of the URL is returned
+// unchanged, including its escaping and parameter order.
+//
+// Strings that do not parse as URLs are passed through
+// security.RedactSecrets instead.
+//
+// Example:
+//
+//	logger.Info("downloading", "url", urlutil.RedactForLogging(blobURL))
+//	// https://acct.blob.core.windows.net/c/tool.zip?sv=2024-01-01&sig=***REDACTED***
+func RedactForLogging(rawURL string) string {
+	if rawURL == "" {
+		return rawURL
+	}
+	parsed, err := neturl.Parse(rawURL)
+	if err != nil || (parsed.Scheme == "" && parsed.Host == "") {
+		return security.RedactSecrets(rawURL)
+	}
+
+	rest := rawURL
+	var b strings.Builder
+	if parsed.User != nil {
+		// Keep everything up to "//", mask the user info, and continue
+		// after the last "@" in the authority.
+		schemeEnd := strings.Index(rest, "//") + 2
+		authorityEnd := len(rest)
+		if i := strings.IndexAny(rest[schemeEnd:], "/?#"); i >= 0 {
+			authorityEnd = schemeEnd + i
+		}
+		at := strings.LastIndex(rest[:authorityEnd], "@")
+		b.WriteString(rest[:schemeEnd])
+		b.WriteString(security.RedactedPlaceholder)
+		rest = rest[at:]
+	}
+
+	base, fragment, hasFragment := strings.Cut(rest, "#")
+	base, query, hasQuery := strings.Cut(base, "?")
+	b.WriteString(base)
+	if hasQuery {
+		b.WriteByte('?')
+		b.WriteString(redactParams(query))
+	}
+	if hasFragment {
+		// OAuth implicit flows return tokens in the fragment.
+		b.WriteByte('#')
+		b.WriteString(redactParams(fragment))
+	}
+	return b.String()
+}
+
+// redactParams masks the values of sensitive name=value pairs in an
+// &-separated parameter list, leaving everything else as written.
+func redactParams(params string) string {
+	if !strings.Contains(params, "=") {
+		return params
+	}
+	pairs := strings.Split(params, "&")
+	for i, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || value == "" {
+			continue
+		}
+		if unescaped, err := neturl.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if isSensitiveParam(name) {
+			pairs[i] = pair[:len(pair)-len(value)] + security.RedactedPlaceholder
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// isSensitiveParam reports whether a query parameter value should be masked.
+func isSensitiveParam(name string) bool {
+	return sensitiveQueryParams[strings.ToLower(name)] || security.IsSensitiveKey(name)
+}